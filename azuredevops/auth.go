@@ -0,0 +1,256 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuredevops
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/gitprovider/cache"
+)
+
+const (
+	// DefaultDomain specifies the default domain used as the backend.
+	DefaultDomain = "dev.azure.com"
+	// TokenVariable is the common name for the environment variable
+	// containing an Azure DevOps personal access token.
+	TokenVariable = "AZURE_DEVOPS_EXT_PAT" // #nosec G101
+)
+
+// ClientOption is the interface to implement for passing options to NewClient.
+// The clientOptions struct is private to force usage of the With... functions.
+type ClientOption interface {
+	// ApplyToAzureDevOpsClientOptions applies set fields of this object into target.
+	ApplyToAzureDevOpsClientOptions(target *clientOptions) error
+}
+
+// clientOptions is the struct that tracks data about what options have been set.
+type clientOptions struct {
+	// clientOptions shares all the common options
+	gitprovider.CommonClientOptions
+
+	// AuthTransport is a ChainableRoundTripperFunc adding authentication credentials to the transport chain.
+	AuthTransport gitprovider.ChainableRoundTripperFunc
+
+	// EnableConditionalRequests will be set if conditional requests should be used.
+	// Default: false
+	EnableConditionalRequests *bool
+}
+
+// ApplyToAzureDevOpsClientOptions implements ClientOption, and applies the set fields of opts
+// into target. If both opts and target has the same specific field set, ErrInvalidClientOptions is returned.
+func (opts *clientOptions) ApplyToAzureDevOpsClientOptions(target *clientOptions) error {
+	// Apply common values, if any
+	if err := opts.CommonClientOptions.ApplyToCommonClientOptions(&target.CommonClientOptions); err != nil {
+		return err
+	}
+
+	if opts.AuthTransport != nil {
+		// Make sure the user didn't specify the AuthTransport twice
+		if target.AuthTransport != nil {
+			return fmt.Errorf("option AuthTransport already configured: %w", gitprovider.ErrInvalidClientOptions)
+		}
+		target.AuthTransport = opts.AuthTransport
+	}
+
+	if opts.EnableConditionalRequests != nil {
+		// Make sure the user didn't specify the EnableConditionalRequests twice
+		if target.EnableConditionalRequests != nil {
+			return fmt.Errorf("option EnableConditionalRequests already configured: %w", gitprovider.ErrInvalidClientOptions)
+		}
+		target.EnableConditionalRequests = opts.EnableConditionalRequests
+	}
+	return nil
+}
+
+// getTransportChain builds the full chain of transports (from left to right,
+// as per gitprovider.BuildClientFromTransportChain) of the form described in NewClient.
+func (opts *clientOptions) getTransportChain() (chain []gitprovider.ChainableRoundTripperFunc) {
+	if opts.PostChainTransportHook != nil {
+		chain = append(chain, opts.PostChainTransportHook)
+	}
+	if opts.AuthTransport != nil {
+		chain = append(chain, opts.AuthTransport)
+	}
+	if opts.EnableConditionalRequests != nil && *opts.EnableConditionalRequests {
+		chain = append(chain, cache.NewHTTPCacheTransport)
+	}
+	if opts.PreChainTransportHook != nil {
+		chain = append(chain, opts.PreChainTransportHook)
+	}
+	return
+}
+
+// buildCommonOption is a helper for returning a ClientOption out of a common option field.
+func buildCommonOption(opt gitprovider.CommonClientOptions) *clientOptions {
+	return &clientOptions{CommonClientOptions: opt}
+}
+
+// errorOption implements ClientOption, and just wraps an error which is immediately returned.
+// This struct can be used through the optionError function, in order to make makeOptions fail
+// if there are invalid options given to the With... functions.
+type errorOption struct {
+	err error
+}
+
+// ApplyToAzureDevOpsClientOptions implements ClientOption, but just returns the internal error.
+func (e *errorOption) ApplyToAzureDevOpsClientOptions(*clientOptions) error { return e.err }
+
+// optionError is a constructor for errorOption.
+func optionError(err error) ClientOption {
+	return &errorOption{err}
+}
+
+//
+// Common options
+//
+
+// WithDomain initializes a Client for a custom Azure DevOps Server instance, e.g.
+// "devops.example.com" or "https://example.com/tfs". If domain doesn't contain a scheme,
+// https is used. domain must not be an empty string.
+func WithDomain(domain string) ClientOption {
+	return buildCommonOption(gitprovider.CommonClientOptions{Domain: &domain})
+}
+
+// WithDestructiveAPICalls tells the client whether it's allowed to do dangerous and possibly destructive
+// actions, like e.g. deleting a repository.
+func WithDestructiveAPICalls(destructiveActions bool) ClientOption {
+	return buildCommonOption(gitprovider.CommonClientOptions{EnableDestructiveAPICalls: &destructiveActions})
+}
+
+// WithPreChainTransportHook registers a ChainableRoundTripperFunc "before" the cache and authentication
+// transports in the chain. For more information, see NewClient, and gitprovider.CommonClientOptions.PreChainTransportHook.
+func WithPreChainTransportHook(preRoundTripperFunc gitprovider.ChainableRoundTripperFunc) ClientOption {
+	// Don't allow an empty value
+	if preRoundTripperFunc == nil {
+		return optionError(fmt.Errorf("preRoundTripperFunc cannot be nil: %w", gitprovider.ErrInvalidClientOptions))
+	}
+
+	return buildCommonOption(gitprovider.CommonClientOptions{PreChainTransportHook: preRoundTripperFunc})
+}
+
+// WithPostChainTransportHook registers a ChainableRoundTripperFunc "after" the cache and authentication
+// transports in the chain. For more information, see NewClient, and gitprovider.CommonClientOptions.WithPostChainTransportHook.
+func WithPostChainTransportHook(postRoundTripperFunc gitprovider.ChainableRoundTripperFunc) ClientOption {
+	// Don't allow an empty value
+	if postRoundTripperFunc == nil {
+		return optionError(fmt.Errorf("postRoundTripperFunc cannot be nil: %w", gitprovider.ErrInvalidClientOptions))
+	}
+
+	return buildCommonOption(gitprovider.CommonClientOptions{PostChainTransportHook: postRoundTripperFunc})
+}
+
+//
+// Azure DevOps-specific options
+//
+
+// WithPersonalAccessToken initializes a Client which authenticates with Azure DevOps through a
+// personal access token (PAT), sent using HTTP basic authentication. pat must not be an empty string.
+// See: https://learn.microsoft.com/en-us/azure/devops/organizations/accounts/use-personal-access-tokens-to-authenticate
+func WithPersonalAccessToken(pat string) ClientOption {
+	// Don't allow an empty value
+	if len(pat) == 0 {
+		return optionError(fmt.Errorf("pat cannot be empty: %w", gitprovider.ErrInvalidClientOptions))
+	}
+
+	return &clientOptions{AuthTransport: patTransport(pat)}
+}
+
+func patTransport(pat string) gitprovider.ChainableRoundTripperFunc {
+	return func(in http.RoundTripper) http.RoundTripper {
+		if in == nil {
+			in = http.DefaultTransport
+		}
+		return &patRoundTripper{pat: pat, transport: in}
+	}
+}
+
+// patRoundTripper sets the basic auth header, with an empty username and the
+// personal access token as password, on a copy of every request.
+type patRoundTripper struct {
+	pat       string
+	transport http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (rt *patRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	// As per the http.RoundTripper contract, the request must not be modified
+	authReq := req.Clone(req.Context())
+	authReq.SetBasicAuth("", rt.pat)
+	return rt.transport.RoundTrip(authReq)
+}
+
+// WithConditionalRequests instructs the client to use Conditional Requests to Azure DevOps, asking
+// whether a resource has changed (using the ETag of the earlier response), and using an in-memory
+// cached "database" if not.
+func WithConditionalRequests(conditionalRequests bool) ClientOption {
+	return &clientOptions{EnableConditionalRequests: &conditionalRequests}
+}
+
+// makeOptions assembles a clientOptions struct from ClientOption mutator functions.
+func makeOptions(opts ...ClientOption) (*clientOptions, error) {
+	o := &clientOptions{}
+	for _, opt := range opts {
+		if err := opt.ApplyToAzureDevOpsClientOptions(o); err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+// NewClient creates a new gitprovider.Client instance for Azure DevOps API endpoints.
+//
+// Azure DevOps Services (dev.azure.com) is used by default. Use WithDomain to change
+// the domain to an Azure DevOps Server instance.
+//
+// Using WithPersonalAccessToken you can specify authentication credentials,
+// passing no such ClientOption will allow public read access only.
+//
+// You can customize low-level HTTP Transport functionality by using the With{Pre,Post}ChainTransportHook options.
+// You can also use conditional requests (and an in-memory cache) using WithConditionalRequests.
+//
+// The chain of transports looks like this:
+// Azure DevOps API <-> "Post Chain" <-> Authentication <-> Cache <-> "Pre Chain" <-> *http.Client.
+func NewClient(optFns ...ClientOption) (gitprovider.Client, error) {
+	// Complete the options struct
+	opts, err := makeOptions(optFns...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a *http.Client using the transport chain
+	httpClient, err := gitprovider.BuildClientFromTransportChain(opts.getTransportChain())
+	if err != nil {
+		return nil, err
+	}
+
+	// By default, turn destructive actions off. But allow overrides.
+	destructiveActions := false
+	if opts.EnableDestructiveAPICalls != nil {
+		destructiveActions = *opts.EnableDestructiveAPICalls
+	}
+
+	// Default to Azure DevOps Services
+	domain := DefaultDomain
+	if opts.Domain != nil {
+		domain = *opts.Domain
+	}
+	domainURL := strings.TrimSuffix(gitprovider.GetDomainURL(domain), "/")
+	return newClient(httpClient, domainURL, domain, destructiveActions), nil
+}
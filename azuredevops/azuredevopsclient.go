@@ -0,0 +1,944 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuredevops
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+const (
+	// apiVersion is the version of the REST API used for all requests, except for the
+	// SSH public keys, which are only available in a preview API.
+	apiVersion = "7.1"
+	// sessionTokensAPIVersion is the version of the session tokens API.
+	sessionTokensAPIVersion = "7.1-preview.1"
+	// connectionDataAPIVersion is the version of the connection data API.
+	connectionDataAPIVersion = "7.1-preview.1"
+	// defaultPageSize is the page size used for listing.
+	defaultPageSize = 100
+	// continuationTokenHeader holds the token for getting the next page, if any.
+	continuationTokenHeader = "X-Ms-Continuationtoken"
+	// zeroObjectID is the object ID used as the old object ID of refs to be created.
+	zeroObjectID = "0000000000000000000000000000000000000000"
+)
+
+// azureDevOpsClient is a wrapper around the Azure DevOps REST API, which implements higher-level
+// methods, operating on the structs in types.go. Pagination is implemented for all List* methods,
+// all returned objects are validated, and HTTP errors are handled/wrapped using handleHTTPError.
+// This interface is also fakeable, in order to unit-test the client.
+type azureDevOpsClient interface {
+	// Client returns the underlying *http.Client
+	Client() *http.Client
+
+	// GetProject is a wrapper for "GET /{organization}/_apis/projects/{project}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetProject(ctx context.Context, org, project string) (*Project, error)
+	// ListProjects is a wrapper for "GET /{organization}/_apis/projects".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListProjects(ctx context.Context, org string) ([]*Project, error)
+
+	// ListTeams is a wrapper for "GET /{organization}/_apis/projects/{project}/teams".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListTeams(ctx context.Context, org, project string) ([]*Team, error)
+	// ListTeamMembers is a wrapper for "GET /{organization}/_apis/projects/{project}/teams/{team}/members".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListTeamMembers(ctx context.Context, org, project, teamID string) ([]*TeamMember, error)
+
+	// GetRepo is a wrapper for "GET /{organization}/{project}/_apis/git/repositories/{repository}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetRepo(ctx context.Context, org, project, repo string) (*Repository, error)
+	// ListRepos is a wrapper for "GET /{organization}/{project}/_apis/git/repositories", or
+	// "GET /{organization}/_apis/git/repositories" for all repositories of the organization if
+	// project is empty.
+	// This function handles HTTP error wrapping, and validates the server result.
+	ListRepos(ctx context.Context, org, project string) ([]*Repository, error)
+	// CreateRepo is a wrapper for "POST /{organization}/{project}/_apis/git/repositories".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateRepo(ctx context.Context, org, project string, req *CreateRepositoryOptions) (*Repository, error)
+	// UpdateRepo is a wrapper for "PATCH /{organization}/{project}/_apis/git/repositories/{repositoryId}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	UpdateRepo(ctx context.Context, org, project, repoID string, req *Repository) (*Repository, error)
+	// DeleteRepo is a wrapper for "DELETE /{organization}/{project}/_apis/git/repositories/{repositoryId}".
+	// This function handles HTTP error wrapping.
+	// DANGEROUS COMMAND: In order to use this, you must set destructiveActions to true.
+	DeleteRepo(ctx context.Context, org, project, repoID string) error
+
+	// ListSSHKeys is a wrapper for "GET /{organization}/_apis/token/sessiontokens?isPublic=true"
+	// on the identity (VSSPS) endpoint.
+	// This function handles HTTP error wrapping, and validates the server result.
+	ListSSHKeys(ctx context.Context, org string) ([]*SessionToken, error)
+	// CreateSSHKey is a wrapper for "POST /{organization}/_apis/token/sessiontokens"
+	// on the identity (VSSPS) endpoint.
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateSSHKey(ctx context.Context, org string, req *SessionToken) (*SessionToken, error)
+	// DeleteSSHKey is a wrapper for "DELETE /{organization}/_apis/token/sessiontokens/{authorizationId}?isPublic=true"
+	// on the identity (VSSPS) endpoint.
+	// This function handles HTTP error wrapping.
+	DeleteSSHKey(ctx context.Context, org, authorizationID string) error
+
+	// ListCommitsPage is a wrapper for "GET /{organization}/{project}/_apis/git/repositories/{repository}/commits".
+	// This function handles HTTP error wrapping, and validates the server result.
+	ListCommitsPage(ctx context.Context, org, project, repo, branch string, perPage int, page int) ([]*Commit, error)
+	// CreateCommit is a wrapper for "POST /{organization}/{project}/_apis/git/repositories/{repository}/pushes",
+	// with "GET .../refs" to get the head of branch and "GET .../items" to tell whether files are
+	// added or edited. If branch doesn't exist, it's created from startBranch, if set.
+	// If parent is set, ErrConflict is returned unless the commit is pushed on top of it.
+	// If author or committer is nil, the authenticated user is used instead.
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateCommit(ctx context.Context, org, project, repo, branch, startBranch, parent, message string, author, committer *gitprovider.CommitAuthor, files []gitprovider.CommitFile) (*Commit, error)
+	// GetBranch is a wrapper for "GET /{organization}/{project}/_apis/git/repositories/{repository}/refs",
+	// filtered to the branch with the given name.
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetBranch(ctx context.Context, org, project, repo, branch string) (*GitRef, error)
+	// ListBranches is a wrapper for "GET /{organization}/{project}/_apis/git/repositories/{repository}/refs",
+	// filtered to branches. All refs are returned at once.
+	// This function handles HTTP error wrapping, and validates the server result.
+	ListBranches(ctx context.Context, org, project, repo string) ([]*GitRef, error)
+	// GetCommitID is a wrapper for "GET /{organization}/{project}/_apis/git/repositories/{repository}/refs",
+	// returning the ID of the commit at the branch or tag with the given name.
+	// This function handles HTTP error wrapping.
+	GetCommitID(ctx context.Context, org, project, repo, ref string) (string, error)
+	// CreateBranch is a wrapper for "POST /{organization}/{project}/_apis/git/repositories/{repository}/refs".
+	// This function handles HTTP error wrapping.
+	CreateBranch(ctx context.Context, org, project, repo, branch, sha string) error
+	// DeleteBranch is a wrapper for "POST /{organization}/{project}/_apis/git/repositories/{repository}/refs",
+	// with "GET .../refs" to get the head of branch.
+	// This function handles HTTP error wrapping.
+	// DANGEROUS COMMAND: In order to use this, you must set destructiveActions to true.
+	DeleteBranch(ctx context.Context, org, project, repo, branch string) error
+	// CreatePullRequest is a wrapper for "POST /{organization}/{project}/_apis/git/repositories/{repository}/pullrequests".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreatePullRequest(ctx context.Context, org, project, repo string, req *PullRequest) (*PullRequest, error)
+	// GetPullRequest is a wrapper for "GET /{organization}/{project}/_apis/git/repositories/{repository}/pullrequests/{pullRequestId}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetPullRequest(ctx context.Context, org, project, repo string, id int) (*PullRequest, error)
+	// ListPullRequests is a wrapper for "GET /{organization}/{project}/_apis/git/repositories/{repository}/pullrequests".
+	// query holds the searchCriteria parameters.
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListPullRequests(ctx context.Context, org, project, repo string, query url.Values) ([]*PullRequest, error)
+	// UpdatePullRequest is a wrapper for "PATCH /{organization}/{project}/_apis/git/repositories/{repository}/pullrequests/{pullRequestId}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	UpdatePullRequest(ctx context.Context, org, project, repo string, id int, req *PullRequestUpdate) (*PullRequest, error)
+	// ListPullRequestThreads is a wrapper for "GET /{organization}/{project}/_apis/git/repositories/{repository}/pullrequests/{pullRequestId}/threads".
+	// This function handles HTTP error wrapping, and validates the server result.
+	ListPullRequestThreads(ctx context.Context, org, project, repo string, id int) ([]*CommentThread, error)
+	// CreatePullRequestThread is a wrapper for "POST /{organization}/{project}/_apis/git/repositories/{repository}/pullrequests/{pullRequestId}/threads".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreatePullRequestThread(ctx context.Context, org, project, repo string, id int, req *CommentThread) (*CommentThread, error)
+	// UpdatePullRequestComment is a wrapper for "PATCH /{organization}/{project}/_apis/git/repositories/{repository}/pullrequests/{pullRequestId}/threads/{threadId}/comments/{commentId}".
+	// This function handles HTTP error wrapping.
+	UpdatePullRequestComment(ctx context.Context, org, project, repo string, id int, threadID, commentID int64, content string) (*Comment, error)
+	// DeletePullRequestComment is a wrapper for "DELETE /{organization}/{project}/_apis/git/repositories/{repository}/pullrequests/{pullRequestId}/threads/{threadId}/comments/{commentId}".
+	// This function handles HTTP error wrapping.
+	DeletePullRequestComment(ctx context.Context, org, project, repo string, id int, threadID, commentID int64) error
+	// CreatePullRequestReviewer is a wrapper for "PUT /{organization}/{project}/_apis/git/repositories/{repository}/pullrequests/{pullRequestId}/reviewers/{reviewerId}",
+	// which adds the reviewer, or updates its vote.
+	// This function handles HTTP error wrapping.
+	CreatePullRequestReviewer(ctx context.Context, org, project, repo string, id int, reviewerID string, vote int) (*IdentityRefWithVote, error)
+
+	// GetConnectionData is a wrapper for "GET /{organization}/_apis/connectionData".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetConnectionData(ctx context.Context, org string) (*ConnectionData, error)
+}
+
+// azureDevOpsClientImpl is a wrapper around *http.Client, which implements higher-level methods,
+// operating on the structs in types.go. See the azureDevOpsClient interface for method documentation.
+// Pagination is implemented for all List* methods, all returned
+// objects are validated, and HTTP errors are handled/wrapped using handleHTTPError.
+type azureDevOpsClientImpl struct {
+	c         *http.Client
+	domainURL string
+	// vsspsURL is the URL of the identity service, which manages SSH public keys
+	vsspsURL           string
+	destructiveActions bool
+}
+
+// azureDevOpsClientImpl implements azureDevOpsClient.
+var _ azureDevOpsClient = &azureDevOpsClientImpl{}
+
+func (c *azureDevOpsClientImpl) Client() *http.Client {
+	return c.c
+}
+
+func (c *azureDevOpsClientImpl) GetProject(ctx context.Context, org, project string) (*Project, error) {
+	apiObj := &Project{}
+	// GET /{organization}/_apis/projects/{project}
+	if err := c.do(ctx, http.MethodGet, c.url(c.domainURL, nil, org, "_apis", "projects", project), nil, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Validate the API object
+	if err := validateProjectAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *azureDevOpsClientImpl) ListProjects(ctx context.Context, org string) ([]*Project, error) {
+	apiObjs := []*Project{}
+	query := url.Values{}
+	query.Set("$top", strconv.Itoa(defaultPageSize))
+	for {
+		resp := &struct {
+			Value []*Project `json:"value"`
+		}{}
+		// GET /{organization}/_apis/projects
+		httpResp, err := c.doResponse(ctx, http.MethodGet, c.url(c.domainURL, query, org, "_apis", "projects"), nil, resp)
+		if err != nil {
+			return nil, handleHTTPError(err)
+		}
+		apiObjs = append(apiObjs, resp.Value...)
+
+		// The continuation token is set if there are more pages
+		token := httpResp.Header.Get(continuationTokenHeader)
+		if len(token) == 0 {
+			break
+		}
+		query.Set("continuationToken", token)
+	}
+
+	// Validate the API objects
+	for _, apiObj := range apiObjs {
+		if err := validateProjectAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *azureDevOpsClientImpl) ListTeams(ctx context.Context, org, project string) ([]*Team, error) {
+	apiObjs := []*Team{}
+	// GET /{organization}/_apis/projects/{project}/teams
+	err := c.allPages(ctx, c.url(c.domainURL, nil, org, "_apis", "projects", project, "teams"), func(data []byte) (int, error) {
+		var pageObjs []*Team
+		err := json.Unmarshal(data, &pageObjs)
+		apiObjs = append(apiObjs, pageObjs...)
+		return len(pageObjs), err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Make sure the ID and Name fields are set.
+	for _, apiObj := range apiObjs {
+		if len(apiObj.ID) == 0 || len(apiObj.Name) == 0 {
+			return nil, fmt.Errorf("didn't expect id or name to be unset for team: %+v: %w", apiObj, gitprovider.ErrInvalidServerData)
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *azureDevOpsClientImpl) ListTeamMembers(ctx context.Context, org, project, teamID string) ([]*TeamMember, error) {
+	apiObjs := []*TeamMember{}
+	// GET /{organization}/_apis/projects/{project}/teams/{team}/members
+	err := c.allPages(ctx, c.url(c.domainURL, nil, org, "_apis", "projects", project, "teams", teamID, "members"), func(data []byte) (int, error) {
+		var pageObjs []*TeamMember
+		err := json.Unmarshal(data, &pageObjs)
+		apiObjs = append(apiObjs, pageObjs...)
+		return len(pageObjs), err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Make sure the UniqueName field is set.
+	for _, apiObj := range apiObjs {
+		if apiObj.Identity == nil || len(apiObj.Identity.UniqueName) == 0 {
+			return nil, fmt.Errorf("didn't expect uniqueName to be empty for team member: %+v: %w", apiObj, gitprovider.ErrInvalidServerData)
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *azureDevOpsClientImpl) GetRepo(ctx context.Context, org, project, repo string) (*Repository, error) {
+	apiObj := &Repository{}
+	// GET /{organization}/{project}/_apis/git/repositories/{repository}
+	err := c.do(ctx, http.MethodGet, c.url(c.domainURL, nil, org, project, "_apis", "git", "repositories", repo), nil, apiObj)
+	return validateRepositoryAPIResp(apiObj, err)
+}
+
+func validateRepositoryAPIResp(apiObj *Repository, err error) (*Repository, error) {
+	// If the response contained an error, return
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Make sure apiObj is valid
+	if err := validateRepositoryAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *azureDevOpsClientImpl) ListRepos(ctx context.Context, org, project string) ([]*Repository, error) {
+	// GET /{organization}/{project}/_apis/git/repositories (if project != "")
+	// GET /{organization}/_apis/git/repositories (if project == "")
+	segments := []string{org, project, "_apis", "git", "repositories"}
+	if len(project) == 0 {
+		segments = []string{org, "_apis", "git", "repositories"}
+	}
+	resp := &struct {
+		Value []*Repository `json:"value"`
+	}{}
+	// This endpoint isn't paginated
+	if err := c.do(ctx, http.MethodGet, c.url(c.domainURL, nil, segments...), nil, resp); err != nil {
+		return nil, handleHTTPError(err)
+	}
+
+	for _, apiObj := range resp.Value {
+		// Make sure apiObj is valid
+		if err := validateRepositoryAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return resp.Value, nil
+}
+
+func (c *azureDevOpsClientImpl) CreateRepo(ctx context.Context, org, project string, req *CreateRepositoryOptions) (*Repository, error) {
+	apiObj := &Repository{}
+	// POST /{organization}/{project}/_apis/git/repositories
+	err := c.do(ctx, http.MethodPost, c.url(c.domainURL, nil, org, project, "_apis", "git", "repositories"), req, apiObj)
+	return validateRepositoryAPIResp(apiObj, err)
+}
+
+func (c *azureDevOpsClientImpl) UpdateRepo(ctx context.Context, org, project, repoID string, req *Repository) (*Repository, error) {
+	apiObj := &Repository{}
+	// PATCH /{organization}/{project}/_apis/git/repositories/{repositoryId}
+	err := c.do(ctx, http.MethodPatch, c.url(c.domainURL, nil, org, project, "_apis", "git", "repositories", repoID), req, apiObj)
+	return validateRepositoryAPIResp(apiObj, err)
+}
+
+func (c *azureDevOpsClientImpl) DeleteRepo(ctx context.Context, org, project, repoID string) error {
+	// Don't allow deleting repositories if the user didn't explicitly allow dangerous API calls.
+	if !c.destructiveActions {
+		return fmt.Errorf("cannot delete repository: %w", gitprovider.ErrDestructiveCallDisallowed)
+	}
+	// DELETE /{organization}/{project}/_apis/git/repositories/{repositoryId}
+	err := c.do(ctx, http.MethodDelete, c.url(c.domainURL, nil, org, project, "_apis", "git", "repositories", repoID), nil, nil)
+	return handleHTTPError(err)
+}
+
+func (c *azureDevOpsClientImpl) ListSSHKeys(ctx context.Context, org string) ([]*SessionToken, error) {
+	query := sshKeyQuery()
+	query.Set("includePublicData", "true")
+
+	apiObjs := []*SessionToken{}
+	// GET /{organization}/_apis/token/sessiontokens
+	if err := c.do(ctx, http.MethodGet, c.url(c.vsspsURL, query, org, "_apis", "token", "sessiontokens"), nil, &apiObjs); err != nil {
+		return nil, handleHTTPError(err)
+	}
+
+	for _, apiObj := range apiObjs {
+		if err := validateSSHKeyAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *azureDevOpsClientImpl) CreateSSHKey(ctx context.Context, org string, req *SessionToken) (*SessionToken, error) {
+	apiObj := &SessionToken{}
+	// POST /{organization}/_apis/token/sessiontokens
+	if err := c.do(ctx, http.MethodPost, c.url(c.vsspsURL, sshKeyQuery(), org, "_apis", "token", "sessiontokens"), req, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validateSSHKeyAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *azureDevOpsClientImpl) DeleteSSHKey(ctx context.Context, org, authorizationID string) error {
+	// DELETE /{organization}/_apis/token/sessiontokens/{authorizationId}
+	err := c.do(ctx, http.MethodDelete, c.url(c.vsspsURL, sshKeyQuery(), org, "_apis", "token", "sessiontokens", authorizationID), nil, nil)
+	return handleHTTPError(err)
+}
+
+// sshKeyQuery returns the query for the session tokens API, which selects
+// SSH public keys instead of personal access tokens.
+func sshKeyQuery() url.Values {
+	query := url.Values{}
+	query.Set("api-version", sessionTokensAPIVersion)
+	query.Set("isPublic", "true")
+	return query
+}
+
+func (c *azureDevOpsClientImpl) ListCommitsPage(ctx context.Context, org, project, repo, branch string, perPage int, page int) ([]*Commit, error) {
+	query := url.Values{}
+	query.Set("searchCriteria.itemVersion.version", branch)
+	query.Set("searchCriteria.itemVersion.versionType", "branch")
+	if perPage > 0 {
+		query.Set("searchCriteria.$top", strconv.Itoa(perPage))
+		// Pages are 1-indexed, treat 0 as "the first page" like the other providers do
+		if page > 1 {
+			query.Set("searchCriteria.$skip", strconv.Itoa((page-1)*perPage))
+		}
+	}
+
+	resp := &struct {
+		Value []*Commit `json:"value"`
+	}{}
+	// GET /{organization}/{project}/_apis/git/repositories/{repository}/commits
+	if err := c.do(ctx, http.MethodGet, c.url(c.domainURL, query, org, project, "_apis", "git", "repositories", repo, "commits"), nil, resp); err != nil {
+		return nil, handleHTTPError(err)
+	}
+
+	for _, apiObj := range resp.Value {
+		if err := validateCommitAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return resp.Value, nil
+}
+
+func (c *azureDevOpsClientImpl) CreateCommit(ctx context.Context, org, project, repo, branch, startBranch, parent, message string, author, committer *gitprovider.CommitAuthor, files []gitprovider.CommitFile) (*Commit, error) {
+	commit := &Commit{
+		Comment:   message,
+		Author:    gitUserDateToAPI(author),
+		Committer: gitUserDateToAPI(committer),
+		Changes:   make([]*Change, 0, len(files)),
+	}
+
+	// The push is based on the current head of the branch, if it exists. This is required
+	// by the API, and protects against concurrent changes.
+	oldObjectID := zeroObjectID
+	ref, err := c.getBranch(ctx, org, project, repo, branch)
+	if err != nil {
+		return nil, err
+	}
+	base, baseBranch := ref, branch
+	if ref != nil {
+		oldObjectID = ref.ObjectID
+	} else if len(startBranch) != 0 {
+		// New branches start at the head of startBranch
+		if base, err = c.getBranch(ctx, org, project, repo, startBranch); err != nil {
+			return nil, err
+		}
+		if base == nil {
+			return nil, fmt.Errorf("branch %q: %w", startBranch, gitprovider.ErrNotFound)
+		}
+		baseBranch = startBranch
+		commit.Parents = []string{base.ObjectID}
+	}
+	head := ""
+	if base != nil {
+		head = base.ObjectID
+	} else {
+		// Only the first branch of an empty repository can be created without a start branch
+		branches, err := c.ListBranches(ctx, org, project, repo)
+		if err != nil {
+			return nil, err
+		}
+		if len(branches) != 0 {
+			return nil, fmt.Errorf("branch %q: %w", branch, gitprovider.ErrNotFound)
+		}
+	}
+	if len(parent) != 0 && parent != head {
+		return nil, fmt.Errorf("branch %q is at %q, not %q: %w", branch, head, parent, gitprovider.ErrConflict)
+	}
+
+	for _, file := range files {
+		changes, err := c.fileChanges(ctx, org, project, repo, baseBranch, base != nil, file)
+		if err != nil {
+			return nil, err
+		}
+		commit.Changes = append(commit.Changes, changes...)
+	}
+
+	req := &Push{
+		RefUpdates: []*GitRefUpdate{{Name: branchRef(branch), OldObjectID: oldObjectID}},
+		Commits:    []*Commit{commit},
+	}
+	resp := &Push{}
+	// POST /{organization}/{project}/_apis/git/repositories/{repository}/pushes
+	if err := c.do(ctx, http.MethodPost, c.url(c.domainURL, nil, org, project, "_apis", "git", "repositories", repo, "pushes"), req, resp); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if len(resp.Commits) == 0 {
+		return nil, fmt.Errorf("didn't expect the pushed commits to be empty: %w", gitprovider.ErrInvalidServerData)
+	}
+
+	// Only a single commit was pushed
+	apiObj := resp.Commits[0]
+	if err := validateCommitAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+// fileChanges returns the changes of a pushed commit applying the action of file to branch,
+// which exists if branchExists is true.
+func (c *azureDevOpsClientImpl) fileChanges(ctx context.Context, org, project, repo, branch string, branchExists bool, file gitprovider.CommitFile) ([]*Change, error) {
+	// The mode of files can't be set through the API
+	if file.IsExecutable() {
+		return nil, fmt.Errorf("cannot commit executable file %q: %w", *file.Path, gitprovider.ErrNoProviderSupport)
+	}
+
+	// Paths are absolute in Azure DevOps
+	path := "/" + strings.TrimPrefix(*file.Path, "/")
+	change := &Change{Item: &Item{Path: path}}
+	var changes []*Change
+	switch file.GetAction() {
+	case gitprovider.CommitFileActionDelete:
+		change.ChangeType = changeTypeDelete
+		return []*Change{change}, nil
+	case gitprovider.CommitFileActionMove:
+		previousPath := "/" + strings.TrimPrefix(*file.PreviousPath, "/")
+		if file.Content == nil {
+			change.ChangeType = changeTypeRename
+			change.SourceServerItem = previousPath
+			return []*Change{change}, nil
+		}
+		// A renamed file can't be edited in the same commit, add it to its new path instead
+		changes = append(changes, &Change{ChangeType: changeTypeDelete, Item: &Item{Path: previousPath}})
+		change.ChangeType = changeTypeAdd
+	case gitprovider.CommitFileActionCreate:
+		change.ChangeType = changeTypeAdd
+	case gitprovider.CommitFileActionUpdate:
+		change.ChangeType = changeTypeEdit
+	default:
+		// Existing files must be edited instead of added
+		exists := false
+		if branchExists {
+			var err error
+			if exists, err = c.itemExists(ctx, org, project, repo, branch, path); err != nil {
+				return nil, err
+			}
+		}
+		change.ChangeType = changeTypeAdd
+		if exists {
+			change.ChangeType = changeTypeEdit
+		}
+	}
+
+	// The content is base64-encoded, so that binary files can be pushed as well
+	content, err := file.GetContent()
+	if err != nil {
+		return nil, err
+	}
+	change.NewContent = &ItemContent{
+		Content:     base64.StdEncoding.EncodeToString(content),
+		ContentType: contentTypeBase64,
+	}
+	return append(changes, change), nil
+}
+
+// getBranch returns the ref of the given branch, or nil if the branch doesn't exist.
+func (c *azureDevOpsClientImpl) getBranch(ctx context.Context, org, project, repo, branch string) (*GitRef, error) {
+	return c.getRef(ctx, org, project, repo, branchRef(branch))
+}
+
+// getRef returns the ref with the given full name, or nil if the ref doesn't exist.
+func (c *azureDevOpsClientImpl) getRef(ctx context.Context, org, project, repo, name string) (*GitRef, error) {
+	refs, err := c.listRefs(ctx, org, project, repo, strings.TrimPrefix(name, "refs/"))
+	if err != nil {
+		return nil, err
+	}
+	for _, ref := range refs {
+		if ref.Name == name {
+			return ref, nil
+		}
+	}
+	return nil, nil
+}
+
+// listRefs returns the refs whose name without the "refs/" prefix starts with filter.
+func (c *azureDevOpsClientImpl) listRefs(ctx context.Context, org, project, repo, filter string) ([]*GitRef, error) {
+	// The filter matches ref names by prefix
+	query := url.Values{}
+	query.Set("filter", filter)
+	// Also return the commits annotated tags point to
+	query.Set("peelTags", "true")
+
+	resp := &struct {
+		Value []*GitRef `json:"value"`
+	}{}
+	// GET /{organization}/{project}/_apis/git/repositories/{repository}/refs
+	if err := c.do(ctx, http.MethodGet, c.url(c.domainURL, query, org, project, "_apis", "git", "repositories", repo, "refs"), nil, resp); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	return resp.Value, nil
+}
+
+// itemExists returns true if there's a file or directory at path on the given branch.
+func (c *azureDevOpsClientImpl) itemExists(ctx context.Context, org, project, repo, branch, path string) (bool, error) {
+	query := url.Values{}
+	query.Set("path", path)
+	query.Set("versionDescriptor.version", branch)
+	query.Set("versionDescriptor.versionType", "branch")
+
+	// GET /{organization}/{project}/_apis/git/repositories/{repository}/items
+	err := c.do(ctx, http.MethodGet, c.url(c.domainURL, query, org, project, "_apis", "git", "repositories", repo, "items"), nil, &Item{})
+	if err != nil {
+		err = handleHTTPError(err)
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (c *azureDevOpsClientImpl) GetBranch(ctx context.Context, org, project, repo, branch string) (*GitRef, error) {
+	apiObj, err := c.getBranch(ctx, org, project, repo, branch)
+	if err != nil {
+		return nil, err
+	}
+	if apiObj == nil {
+		return nil, fmt.Errorf("branch %q: %w", branch, gitprovider.ErrNotFound)
+	}
+	if err := validateGitRefAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *azureDevOpsClientImpl) ListBranches(ctx context.Context, org, project, repo string) ([]*GitRef, error) {
+	apiObjs, err := c.listRefs(ctx, org, project, repo, "heads/")
+	if err != nil {
+		return nil, err
+	}
+	for _, apiObj := range apiObjs {
+		if err := validateGitRefAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *azureDevOpsClientImpl) GetCommitID(ctx context.Context, org, project, repo, ref string) (string, error) {
+	// Branches take precedence over tags with the same name, like in git
+	for _, name := range []string{branchRef(ref), "refs/tags/" + ref} {
+		apiObj, err := c.getRef(ctx, org, project, repo, name)
+		if err != nil {
+			return "", err
+		}
+		if apiObj == nil {
+			continue
+		}
+		if len(apiObj.PeeledObjectID) != 0 {
+			return apiObj.PeeledObjectID, nil
+		}
+		return apiObj.ObjectID, nil
+	}
+	return "", fmt.Errorf("ref %q: %w", ref, gitprovider.ErrNotFound)
+}
+
+func (c *azureDevOpsClientImpl) CreateBranch(ctx context.Context, org, project, repo, branch, sha string) error {
+	result, err := c.updateRef(ctx, org, project, repo, &GitRefUpdate{
+		Name:        branchRef(branch),
+		OldObjectID: zeroObjectID,
+		NewObjectID: sha,
+	})
+	if err != nil {
+		return err
+	}
+	if result.Success {
+		return nil
+	}
+	// The old object ID doesn't match if the branch already exists
+	if result.UpdateStatus == "staleOldObjectId" {
+		return fmt.Errorf("branch %q: %w", branch, gitprovider.ErrAlreadyExists)
+	}
+	return fmt.Errorf("failed to create branch %q: %s %s", branch, result.UpdateStatus, result.CustomMessage)
+}
+
+func (c *azureDevOpsClientImpl) DeleteBranch(ctx context.Context, org, project, repo, branch string) error {
+	// Don't allow deleting branches if the user didn't explicitly allow dangerous API calls.
+	if !c.destructiveActions {
+		return fmt.Errorf("cannot delete branch: %w", gitprovider.ErrDestructiveCallDisallowed)
+	}
+	// Branches are deleted by moving them from their head to the zero object ID
+	ref, err := c.GetBranch(ctx, org, project, repo, branch)
+	if err != nil {
+		return err
+	}
+	result, err := c.updateRef(ctx, org, project, repo, &GitRefUpdate{
+		Name:        ref.Name,
+		OldObjectID: ref.ObjectID,
+		NewObjectID: zeroObjectID,
+	})
+	if err != nil {
+		return err
+	}
+	if !result.Success {
+		return fmt.Errorf("failed to delete branch %q: %s %s", branch, result.UpdateStatus, result.CustomMessage)
+	}
+	return nil
+}
+
+// updateRef applies a single ref update, and returns its result.
+func (c *azureDevOpsClientImpl) updateRef(ctx context.Context, org, project, repo string, update *GitRefUpdate) (*GitRefUpdateResult, error) {
+	resp := &struct {
+		Value []*GitRefUpdateResult `json:"value"`
+	}{}
+	// POST /{organization}/{project}/_apis/git/repositories/{repository}/refs
+	if err := c.do(ctx, http.MethodPost, c.url(c.domainURL, nil, org, project, "_apis", "git", "repositories", repo, "refs"), []*GitRefUpdate{update}, resp); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// The result of every ref update is reported separately
+	if len(resp.Value) != 1 {
+		return nil, fmt.Errorf("expected one ref update result, got %d: %w", len(resp.Value), gitprovider.ErrInvalidServerData)
+	}
+	return resp.Value[0], nil
+}
+
+func (c *azureDevOpsClientImpl) CreatePullRequest(ctx context.Context, org, project, repo string, req *PullRequest) (*PullRequest, error) {
+	apiObj := &PullRequest{}
+	// POST /{organization}/{project}/_apis/git/repositories/{repository}/pullrequests
+	if err := c.do(ctx, http.MethodPost, c.url(c.domainURL, nil, org, project, "_apis", "git", "repositories", repo, "pullrequests"), req, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validatePullRequestAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *azureDevOpsClientImpl) GetPullRequest(ctx context.Context, org, project, repo string, id int) (*PullRequest, error) {
+	apiObj := &PullRequest{}
+	// GET /{organization}/{project}/_apis/git/repositories/{repository}/pullrequests/{pullRequestId}
+	if err := c.do(ctx, http.MethodGet, c.url(c.domainURL, nil, org, project, "_apis", "git", "repositories", repo, "pullrequests", strconv.Itoa(id)), nil, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validatePullRequestAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *azureDevOpsClientImpl) ListPullRequests(ctx context.Context, org, project, repo string, query url.Values) ([]*PullRequest, error) {
+	apiObjs := []*PullRequest{}
+	// GET /{organization}/{project}/_apis/git/repositories/{repository}/pullrequests
+	err := c.allPages(ctx, c.url(c.domainURL, query, org, project, "_apis", "git", "repositories", repo, "pullrequests"), func(data []byte) (int, error) {
+		var pageObjs []*PullRequest
+		err := json.Unmarshal(data, &pageObjs)
+		apiObjs = append(apiObjs, pageObjs...)
+		return len(pageObjs), err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, apiObj := range apiObjs {
+		if err := validatePullRequestAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *azureDevOpsClientImpl) UpdatePullRequest(ctx context.Context, org, project, repo string, id int, req *PullRequestUpdate) (*PullRequest, error) {
+	apiObj := &PullRequest{}
+	// PATCH /{organization}/{project}/_apis/git/repositories/{repository}/pullrequests/{pullRequestId}
+	if err := c.do(ctx, http.MethodPatch, c.url(c.domainURL, nil, org, project, "_apis", "git", "repositories", repo, "pullrequests", strconv.Itoa(id)), req, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validatePullRequestAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *azureDevOpsClientImpl) ListPullRequestThreads(ctx context.Context, org, project, repo string, id int) ([]*CommentThread, error) {
+	resp := &struct {
+		Value []*CommentThread `json:"value"`
+	}{}
+	// This endpoint isn't paginated
+	// GET /{organization}/{project}/_apis/git/repositories/{repository}/pullrequests/{pullRequestId}/threads
+	if err := c.do(ctx, http.MethodGet, c.url(c.domainURL, nil, org, project, "_apis", "git", "repositories", repo, "pullrequests", strconv.Itoa(id), "threads"), nil, resp); err != nil {
+		return nil, handleHTTPError(err)
+	}
+
+	for _, apiObj := range resp.Value {
+		if err := validateCommentThreadAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return resp.Value, nil
+}
+
+func (c *azureDevOpsClientImpl) CreatePullRequestThread(ctx context.Context, org, project, repo string, id int, req *CommentThread) (*CommentThread, error) {
+	apiObj := &CommentThread{}
+	// POST /{organization}/{project}/_apis/git/repositories/{repository}/pullrequests/{pullRequestId}/threads
+	if err := c.do(ctx, http.MethodPost, c.url(c.domainURL, nil, org, project, "_apis", "git", "repositories", repo, "pullrequests", strconv.Itoa(id), "threads"), req, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validateCommentThreadAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *azureDevOpsClientImpl) UpdatePullRequestComment(ctx context.Context, org, project, repo string, id int, threadID, commentID int64, content string) (*Comment, error) {
+	apiObj := &Comment{}
+	// PATCH /{organization}/{project}/_apis/git/repositories/{repository}/pullrequests/{pullRequestId}/threads/{threadId}/comments/{commentId}
+	if err := c.do(ctx, http.MethodPatch, c.url(c.domainURL, nil, org, project, "_apis", "git", "repositories", repo, "pullrequests", strconv.Itoa(id),
+		"threads", strconv.FormatInt(threadID, 10), "comments", strconv.FormatInt(commentID, 10)), &Comment{Content: content}, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	return apiObj, nil
+}
+
+func (c *azureDevOpsClientImpl) DeletePullRequestComment(ctx context.Context, org, project, repo string, id int, threadID, commentID int64) error {
+	// DELETE /{organization}/{project}/_apis/git/repositories/{repository}/pullrequests/{pullRequestId}/threads/{threadId}/comments/{commentId}
+	err := c.do(ctx, http.MethodDelete, c.url(c.domainURL, nil, org, project, "_apis", "git", "repositories", repo, "pullrequests", strconv.Itoa(id),
+		"threads", strconv.FormatInt(threadID, 10), "comments", strconv.FormatInt(commentID, 10)), nil, nil)
+	return handleHTTPError(err)
+}
+
+func (c *azureDevOpsClientImpl) CreatePullRequestReviewer(ctx context.Context, org, project, repo string, id int, reviewerID string, vote int) (*IdentityRefWithVote, error) {
+	apiObj := &IdentityRefWithVote{}
+	// PUT /{organization}/{project}/_apis/git/repositories/{repository}/pullrequests/{pullRequestId}/reviewers/{reviewerId}
+	if err := c.do(ctx, http.MethodPut, c.url(c.domainURL, nil, org, project, "_apis", "git", "repositories", repo, "pullrequests", strconv.Itoa(id), "reviewers", reviewerID),
+		&IdentityRefWithVote{Vote: vote}, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	return apiObj, nil
+}
+
+func (c *azureDevOpsClientImpl) GetConnectionData(ctx context.Context, org string) (*ConnectionData, error) {
+	query := url.Values{}
+	query.Set("api-version", connectionDataAPIVersion)
+	apiObj := &ConnectionData{}
+	// GET /{organization}/_apis/connectionData
+	if err := c.do(ctx, http.MethodGet, c.url(c.domainURL, query, org, "_apis", "connectionData"), nil, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validateConnectionDataAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+// branchRef returns the full ref name of branch.
+func branchRef(branch string) string {
+	return "refs/heads/" + branch
+}
+
+// url returns the absolute URL for the path built from baseURL and the escaped path segments,
+// with the optional query appended. The api-version is set, unless it's part of query already.
+func (c *azureDevOpsClientImpl) url(baseURL string, query url.Values, segments ...string) string {
+	escaped := make([]string, 0, len(segments))
+	for _, s := range segments {
+		escaped = append(escaped, url.PathEscape(s))
+	}
+	q := url.Values{}
+	for k, v := range query {
+		q[k] = v
+	}
+	if len(q.Get("api-version")) == 0 {
+		q.Set("api-version", apiVersion)
+	}
+	return baseURL + "/" + strings.Join(escaped, "/") + "?" + q.Encode()
+}
+
+// do sends a request with the given JSON-encoded body (if non-nil) and decodes the
+// response into out (if non-nil). Non-2xx responses are returned as *ErrorResponse.
+func (c *azureDevOpsClientImpl) do(ctx context.Context, method, urlStr string, body, out interface{}) error {
+	_, err := c.doResponse(ctx, method, urlStr, body, out)
+	return err
+}
+
+// doResponse is like do, but also returns the HTTP response.
+func (c *azureDevOpsClientImpl) doResponse(ctx context.Context, method, urlStr string, body, out interface{}) (*http.Response, error) {
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reqBody = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, urlStr, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// Requests without valid credentials get the HTML sign-in page with status 203,
+	// which is treated as an error too
+	if resp.StatusCode < 200 || resp.StatusCode > 299 || resp.StatusCode == http.StatusNonAuthoritativeInfo {
+		errResp := &ErrorResponse{Response: resp}
+		// The error body is best-effort, the status code is what matters
+		if data, readErr := ioutil.ReadAll(resp.Body); readErr == nil && len(data) != 0 {
+			_ = json.Unmarshal(data, errResp)
+		}
+		return resp, errResp
+	}
+
+	if out != nil && resp.StatusCode != http.StatusNoContent {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil && err != io.EOF {
+			return resp, err
+		}
+	}
+	return resp, nil
+}
+
+// allPages requests urlStr using $top and $skip, until a page with less than defaultPageSize
+// items is returned. fn is called with the raw JSON list of every page, and returns the number
+// of items in it.
+// There is no need to wrap the resulting error in handleHTTPError(err), as that's already done.
+func (c *azureDevOpsClientImpl) allPages(ctx context.Context, urlStr string, fn func(data []byte) (int, error)) error {
+	u, err := url.Parse(urlStr)
+	if err != nil {
+		return err
+	}
+	query := u.Query()
+	query.Set("$top", strconv.Itoa(defaultPageSize))
+
+	for skip := 0; ; skip += defaultPageSize {
+		query.Set("$skip", strconv.Itoa(skip))
+		u.RawQuery = query.Encode()
+
+		resp := &struct {
+			Value json.RawMessage `json:"value"`
+		}{}
+		if err := c.do(ctx, http.MethodGet, u.String(), nil, resp); err != nil {
+			return handleHTTPError(err)
+		}
+		n := 0
+		if len(resp.Value) != 0 {
+			if n, err = fn(resp.Value); err != nil {
+				return err
+			}
+		}
+		if n < defaultPageSize {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuredevops
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+const (
+	// ProviderID is the provider ID for Azure DevOps.
+	ProviderID = gitprovider.ProviderID("azuredevops")
+
+	// defaultVSSPSURL is the URL of the identity service of Azure DevOps Services.
+	defaultVSSPSURL = "https://vssps.dev.azure.com"
+)
+
+func newClient(c *http.Client, domainURL, domain string, destructiveActions bool) *Client {
+	// Azure DevOps Server hosts the identity service itself
+	vsspsURL := domainURL
+	if domain == DefaultDomain {
+		vsspsURL = defaultVSSPSURL
+	}
+	adoClient := &azureDevOpsClientImpl{c, domainURL, vsspsURL, destructiveActions}
+	ctx := &clientContext{adoClient, domain, destructiveActions}
+	return &Client{
+		clientContext: ctx,
+		orgs: &OrganizationsClient{
+			clientContext: ctx,
+		},
+		orgRepos: &OrgRepositoriesClient{
+			clientContext: ctx,
+		},
+		userRepos: &UserRepositoriesClient{
+			clientContext: ctx,
+		},
+	}
+}
+
+type clientContext struct {
+	c                  azureDevOpsClient
+	domain             string
+	destructiveActions bool
+}
+
+// Client implements the gitprovider.Client interface.
+var _ gitprovider.Client = &Client{}
+
+// Client is an interface that allows talking to a Git provider.
+type Client struct {
+	*clientContext
+
+	orgs      *OrganizationsClient
+	orgRepos  *OrgRepositoriesClient
+	userRepos *UserRepositoriesClient
+}
+
+// SupportedDomain returns the domain endpoint for this client, e.g. "dev.azure.com" or
+// "my-custom-git-server.com/tfs". This allows a higher-level user to know what Client to use for
+// what endpoints.
+// This field is set at client creation time, and can't be changed.
+func (c *Client) SupportedDomain() string {
+	return c.domain
+}
+
+// ProviderID returns the provider ID "azuredevops".
+// This field is set at client creation time, and can't be changed.
+func (c *Client) ProviderID() gitprovider.ProviderID {
+	return ProviderID
+}
+
+// Raw returns the *http.Client used under the hood for accessing Azure DevOps,
+// with the full transport chain applied.
+func (c *Client) Raw() interface{} {
+	return c.c.Client()
+}
+
+// Organizations returns the OrganizationsClient handling sets of organizations.
+func (c *Client) Organizations() gitprovider.OrganizationsClient {
+	return c.orgs
+}
+
+// OrgRepositories returns the OrgRepositoriesClient handling sets of repositories in an organization.
+func (c *Client) OrgRepositories() gitprovider.OrgRepositoriesClient {
+	return c.orgRepos
+}
+
+// UserRepositories returns the UserRepositoriesClient handling sets of repositories for a user.
+// User repositories don't exist in Azure DevOps, hence all its methods return ErrNoProviderSupport.
+func (c *Client) UserRepositories() gitprovider.UserRepositoriesClient {
+	return c.userRepos
+}
+
+// HasTokenPermission returns true if the given token has the given permissions.
+//
+// Azure DevOps doesn't expose the scopes of a personal access token, hence ErrNoProviderSupport is returned.
+func (c *Client) HasTokenPermission(ctx context.Context, permission gitprovider.TokenPermission) (bool, error) {
+	return false, gitprovider.ErrNoProviderSupport
+}
+
+// Quota returns the current API request quota of the client.
+//
+// Azure DevOps only reports rate limits while delaying requests, hence ErrNoProviderSupport is returned.
+func (c *Client) Quota(ctx context.Context) (gitprovider.Quota, error) {
+	return gitprovider.Quota{}, gitprovider.ErrNoProviderSupport
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuredevops
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// TeamsClient implements the gitprovider.TeamsClient interface.
+var _ gitprovider.TeamsClient = &TeamsClient{}
+
+// TeamsClient handles teams project-wide.
+//
+// Teams belong to projects in Azure DevOps, hence ErrNoProviderSupport is returned
+// for top-level organizations.
+type TeamsClient struct {
+	*clientContext
+	ref gitprovider.OrganizationRef
+}
+
+// Get a team within the specific project.
+//
+// teamName must not be an empty string. Team names are case-insensitive in Azure DevOps.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *TeamsClient) Get(ctx context.Context, teamName string) (gitprovider.Team, error) {
+	// GET /{organization}/_apis/projects/{project}/teams
+	apiObjs, err := c.listTeams(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Find the team with the given name
+	for _, apiObj := range apiObjs {
+		if strings.EqualFold(apiObj.Name, teamName) {
+			return c.get(ctx, apiObj)
+		}
+	}
+	return nil, gitprovider.ErrNotFound
+}
+
+func (c *TeamsClient) get(ctx context.Context, apiObj *Team) (*team, error) {
+	// GET /{organization}/_apis/projects/{project}/teams/{team}/members
+	members, err := c.c.ListTeamMembers(ctx, c.ref.Organization, projectName(c.ref), apiObj.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Collect a list of the members' unique names. These are validated to be set in ListTeamMembers.
+	logins := make([]string, 0, len(members))
+	for _, member := range members {
+		logins = append(logins, member.Identity.UniqueName)
+	}
+
+	return &team{
+		members: members,
+		info: gitprovider.TeamInfo{
+			Name:    apiObj.Name,
+			Members: logins,
+		},
+		ref: c.ref,
+	}, nil
+}
+
+// List all teams within the specific project.
+//
+// List returns all available teams, using multiple paginated requests if needed.
+func (c *TeamsClient) List(ctx context.Context) ([]gitprovider.Team, error) {
+	// GET /{organization}/_apis/projects/{project}/teams
+	apiObjs, err := c.listTeams(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	teams := make([]gitprovider.Team, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		// Get detailed information about individual teams (including members).
+		team, err := c.get(ctx, apiObj)
+		if err != nil {
+			return nil, err
+		}
+
+		teams = append(teams, team)
+	}
+
+	return teams, nil
+}
+
+func (c *TeamsClient) listTeams(ctx context.Context) ([]*Team, error) {
+	project := projectName(c.ref)
+	if len(project) == 0 {
+		return nil, fmt.Errorf("azure devops teams belong to projects: %w", gitprovider.ErrNoProviderSupport)
+	}
+	return c.c.ListTeams(ctx, c.ref.Organization, project)
+}
+
+var _ gitprovider.Team = &team{}
+
+type team struct {
+	members []*TeamMember
+	info    gitprovider.TeamInfo
+	ref     gitprovider.OrganizationRef
+}
+
+func (t *team) Get() gitprovider.TeamInfo {
+	return t.info
+}
+
+func (t *team) APIObject() interface{} {
+	return t.members
+}
+
+func (t *team) Organization() gitprovider.OrganizationRef {
+	return t.ref
+}
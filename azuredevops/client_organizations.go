@@ -0,0 +1,99 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuredevops
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// OrganizationsClient implements the gitprovider.OrganizationsClient interface.
+var _ gitprovider.OrganizationsClient = &OrganizationsClient{}
+
+// OrganizationsClient operates on organizations, and the projects within them.
+type OrganizationsClient struct {
+	*clientContext
+}
+
+// Get a specific organization, or a project if ref has a sub-organization.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *OrganizationsClient) Get(ctx context.Context, ref gitprovider.OrganizationRef) (gitprovider.Organization, error) {
+	// Make sure the OrganizationRef is valid
+	if err := validateOrganizationRef(ref, c.domain); err != nil {
+		return nil, err
+	}
+
+	// There's no API object for organizations, listing the projects makes sure it exists
+	if len(ref.SubOrganizations) == 0 {
+		// GET /{organization}/_apis/projects
+		if _, err := c.c.ListProjects(ctx, ref.Organization); err != nil {
+			return nil, err
+		}
+		return newOrganization(c.clientContext, nil, ref), nil
+	}
+
+	// GET /{organization}/_apis/projects/{project}
+	apiObj, err := c.c.GetProject(ctx, ref.Organization, projectName(ref))
+	if err != nil {
+		return nil, err
+	}
+
+	return newOrganization(c.clientContext, apiObj, ref), nil
+}
+
+// List all top-level organizations the specific user has access to.
+//
+// This is not supported in Azure DevOps, as organizations aren't part of the REST API.
+// Use Children to list the projects of an organization.
+func (c *OrganizationsClient) List(_ context.Context) ([]gitprovider.Organization, error) {
+	return nil, fmt.Errorf("azure devops doesn't support listing organizations: %w", gitprovider.ErrNoProviderSupport)
+}
+
+// Children returns the immediate child-organizations for the specific OrganizationRef o.
+// For a top-level organization, these are its projects. Projects can't have children.
+//
+// Children returns all available organizations, using multiple paginated requests if needed.
+func (c *OrganizationsClient) Children(ctx context.Context, ref gitprovider.OrganizationRef) ([]gitprovider.Organization, error) {
+	// Make sure the OrganizationRef is valid
+	if err := validateOrganizationRef(ref, c.domain); err != nil {
+		return nil, err
+	}
+	if len(ref.SubOrganizations) != 0 {
+		return nil, fmt.Errorf("azure devops projects can't be nested: %w", gitprovider.ErrNoProviderSupport)
+	}
+
+	// GET /{organization}/_apis/projects
+	apiObjs, err := c.c.ListProjects(ctx, ref.Organization)
+	if err != nil {
+		return nil, err
+	}
+
+	projects := make([]gitprovider.Organization, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		// apiObj.Name is already validated to be set in ListProjects
+		projects = append(projects, newOrganization(c.clientContext, apiObj, gitprovider.OrganizationRef{
+			Domain:           c.domain,
+			Organization:     ref.Organization,
+			SubOrganizations: []string{apiObj.Name},
+		}))
+	}
+
+	return projects, nil
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuredevops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// OrgRepositoriesClient implements the gitprovider.OrgRepositoriesClient interface.
+var _ gitprovider.OrgRepositoriesClient = &OrgRepositoriesClient{}
+
+// OrgRepositoriesClient operates on repositories the user has access to.
+//
+// Repositories belong to a project, which must be given as the only sub-organization
+// of the OrganizationRef, e.g. {Organization: "my-org", SubOrganizations: ["my-project"]}.
+type OrgRepositoriesClient struct {
+	*clientContext
+}
+
+// Get returns the repository at the given path.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *OrgRepositoriesClient) Get(ctx context.Context, ref gitprovider.OrgRepositoryRef) (gitprovider.OrgRepository, error) {
+	// Make sure the OrgRepositoryRef is valid
+	if err := validateOrgRepositoryRef(ref, c.domain); err != nil {
+		return nil, err
+	}
+	// GET /{organization}/{project}/_apis/git/repositories/{repository}
+	apiObj, err := c.c.GetRepo(ctx, ref.Organization, projectName(ref.OrganizationRef), ref.RepositoryName)
+	if err != nil {
+		return nil, err
+	}
+	return newOrgRepository(c.clientContext, apiObj, ref), nil
+}
+
+// List all repositories in the given project, or in all projects of the organization
+// if ref is a top-level organization.
+//
+// List returns all available repositories, using multiple paginated requests if needed.
+func (c *OrgRepositoriesClient) List(ctx context.Context, ref gitprovider.OrganizationRef) ([]gitprovider.OrgRepository, error) {
+	// Make sure the OrganizationRef is valid
+	if err := validateOrganizationRef(ref, c.domain); err != nil {
+		return nil, err
+	}
+
+	// GET /{organization}/{project}/_apis/git/repositories
+	apiObjs, err := c.c.ListRepos(ctx, ref.Organization, projectName(ref))
+	if err != nil {
+		return nil, err
+	}
+
+	// Traverse the list, and return a list of OrgRepository objects
+	repos := make([]gitprovider.OrgRepository, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		// apiObj is already validated at ListRepos, hence the project is set
+		repos = append(repos, newOrgRepository(c.clientContext, apiObj, gitprovider.OrgRepositoryRef{
+			OrganizationRef: gitprovider.OrganizationRef{
+				Domain:           ref.Domain,
+				Organization:     ref.Organization,
+				SubOrganizations: []string{apiObj.Project.Name},
+			},
+			RepositoryName: apiObj.Name,
+		}))
+	}
+	return repos, nil
+}
+
+// Create creates a repository in the given project, with the data and options.
+//
+// Repositories are created empty, unless AutoInit is set, in which case a README is committed
+// to the default branch. License templates are not supported.
+//
+// ErrAlreadyExists will be returned if the resource already exists.
+func (c *OrgRepositoriesClient) Create(ctx context.Context, ref gitprovider.OrgRepositoryRef, req gitprovider.RepositoryInfo, opts ...gitprovider.RepositoryCreateOption) (gitprovider.OrgRepository, error) {
+	// Make sure the RepositoryRef is valid
+	if err := validateOrgRepositoryRef(ref, c.domain); err != nil {
+		return nil, err
+	}
+
+	apiObj, err := createRepository(ctx, c.c, ref, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return newOrgRepository(c.clientContext, apiObj, ref), nil
+}
+
+// Reconcile makes sure the given desired state (req) becomes the actual state in the backing Git provider.
+//
+// If req doesn't exist under the hood, it is created (actionTaken == true).
+// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+// If req is already the actual state, this is a no-op (actionTaken == false).
+func (c *OrgRepositoriesClient) Reconcile(ctx context.Context, ref gitprovider.OrgRepositoryRef, req gitprovider.RepositoryInfo, opts ...gitprovider.RepositoryReconcileOption) (gitprovider.OrgRepository, bool, error) {
+	// First thing, validate and default the request to ensure a valid and fully-populated object
+	// (to minimize any possible diffs between desired and actual state)
+	if err := gitprovider.ValidateAndDefaultInfo(&req); err != nil {
+		return nil, false, err
+	}
+
+	actual, err := c.Get(ctx, ref)
+	if err != nil {
+		// Create if not found
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			resp, err := c.Create(ctx, ref, req, toCreateOpts(opts...)...)
+			return resp, true, err
+		}
+
+		// Unexpected path, Get should succeed or return NotFound
+		return nil, false, err
+	}
+
+	// If the desired matches the actual state, just return the actual state
+	if req.Equals(actual.Get()) {
+		return actual, false, nil
+	}
+	// Populate the desired state to the current-actual object
+	if err := actual.Set(req); err != nil {
+		return actual, false, err
+	}
+	// Apply the desired state by running Update
+	return actual, true, actual.Update(ctx)
+}
+
+func createRepository(ctx context.Context, c azureDevOpsClient, ref gitprovider.OrgRepositoryRef, req gitprovider.RepositoryInfo, opts ...gitprovider.RepositoryCreateOption) (*Repository, error) {
+	// First thing, validate and default the request to ensure a valid and fully-populated object
+	// (to minimize any possible diffs between desired and actual state)
+	if err := gitprovider.ValidateAndDefaultInfo(&req); err != nil {
+		return nil, err
+	}
+
+	// Assemble the options struct based on the given options
+	o, err := gitprovider.MakeRepositoryCreateOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+	if o.LicenseTemplate != nil {
+		return nil, fmt.Errorf("azure devops doesn't support license templates: %w", gitprovider.ErrNoProviderSupport)
+	}
+
+	// The ID of the project is required, and its visibility applies to the repository
+	// GET /{organization}/_apis/projects/{project}
+	project, err := c.GetProject(ctx, ref.Organization, projectName(ref.OrganizationRef))
+	if err != nil {
+		return nil, err
+	}
+	if err := repositoryInfoToAPIObj(&req, &Repository{Project: project}); err != nil {
+		return nil, err
+	}
+
+	// POST /{organization}/{project}/_apis/git/repositories
+	apiObj, err := c.CreateRepo(ctx, ref.Organization, project.Name, &CreateRepositoryOptions{
+		Name:    ref.RepositoryName,
+		Project: &Project{ID: project.ID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if o.AutoInit == nil || !*o.AutoInit {
+		return apiObj, nil
+	}
+
+	// The first branch pushed to an empty repository becomes its default branch
+	// POST /{organization}/{project}/_apis/git/repositories/{repository}/pushes
+	readme := fmt.Sprintf("# %s\n", ref.RepositoryName)
+	if _, err := c.CreateCommit(ctx, ref.Organization, project.Name, apiObj.ID, *req.DefaultBranch, "", "", initialCommitMessage, nil, nil, []gitprovider.CommitFile{
+		{
+			Path:    gitprovider.StringVar("README.md"),
+			Content: &readme,
+		},
+	}); err != nil {
+		return nil, err
+	}
+	// GET /{organization}/{project}/_apis/git/repositories/{repository}
+	return c.GetRepo(ctx, ref.Organization, project.Name, apiObj.ID)
+}
+
+func toCreateOpts(opts ...gitprovider.RepositoryReconcileOption) []gitprovider.RepositoryCreateOption {
+	// Convert RepositoryReconcileOption => RepositoryCreateOption
+	createOpts := make([]gitprovider.RepositoryCreateOption, 0, len(opts))
+	for _, opt := range opts {
+		createOpts = append(createOpts, opt)
+	}
+	return createOpts
+}
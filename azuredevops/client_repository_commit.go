@@ -0,0 +1,107 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuredevops
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// CommitClient implements the gitprovider.CommitClient interface.
+var _ gitprovider.CommitClient = &CommitClient{}
+
+// CommitClient operates on the commits for a specific repository.
+type CommitClient struct {
+	*clientContext
+	ref gitprovider.OrgRepositoryRef
+}
+
+// ListPage lists all repository commits of the given page and page size.
+// ListPage returns all available repository commits
+// using multiple paginated requests if needed.
+func (c *CommitClient) ListPage(ctx context.Context, branch string, perPage, page int) ([]gitprovider.Commit, error) {
+	cs, err := c.listPage(ctx, branch, perPage, page)
+	if err != nil {
+		return nil, err
+	}
+	// Cast to the generic []gitprovider.Commit
+	commits := make([]gitprovider.Commit, 0, len(cs))
+	for _, commit := range cs {
+		commits = append(commits, commit)
+	}
+	return commits, nil
+}
+
+func (c *CommitClient) listPage(ctx context.Context, branch string, perPage, page int) ([]*commitType, error) {
+	// GET /{organization}/{project}/_apis/git/repositories/{repository}/commits
+	apiObjs, err := c.c.ListCommitsPage(ctx, c.ref.Organization, projectName(c.ref.OrganizationRef), c.ref.RepositoryName, branch, perPage, page)
+	if err != nil {
+		return nil, err
+	}
+
+	// Map the api object to our CommitType type
+	commits := make([]*commitType, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		// apiObj is already validated at ListCommitsPage
+		commits = append(commits, newCommit(c, apiObj))
+	}
+
+	return commits, nil
+}
+
+// Create creates a commit with the given specifications.
+//
+// All files are changed in a single commit, which is pushed on top of the head of branch. If branch
+// doesn't exist, it is created from the head of the StartBranch given in opts, or with the commit
+// as its root in an empty repository. Files without an Action are deleted if their Content is nil,
+// and otherwise edited if they exist, and added if not. The Author and Committer given in opts,
+// including their dates, are used as the identities of the commit.
+//
+// ErrNoProviderSupport is returned for executable files, as their mode can't be set.
+// ErrConflict is returned if the head of branch isn't the ExpectedParentSHA given in opts, or if
+// it moved while the commit was being created, which Azure DevOps checks as part of the push.
+func (c *CommitClient) Create(ctx context.Context, branch string, message string, files []gitprovider.CommitFile, opts ...gitprovider.CommitCreateOption) (gitprovider.Commit, error) {
+	o, err := gitprovider.MakeCommitCreateOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no files added")
+	}
+	for _, file := range files {
+		if err := file.ValidateInfo(); err != nil {
+			return nil, err
+		}
+	}
+
+	startBranch, parent := "", ""
+	if o.StartBranch != nil {
+		startBranch = *o.StartBranch
+	}
+	if o.ExpectedParentSHA != nil {
+		parent = *o.ExpectedParentSHA
+	}
+	// POST /{organization}/{project}/_apis/git/repositories/{repository}/pushes
+	apiObj, err := c.c.CreateCommit(ctx, c.ref.Organization, projectName(c.ref.OrganizationRef), c.ref.RepositoryName, branch, startBranch, parent, message, o.Author, o.Committer, files)
+	if err != nil {
+		return nil, err
+	}
+	return newCommit(c, apiObj), nil
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuredevops
+
+import (
+	"context"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// CommitStatusClient implements the gitprovider.CommitStatusClient interface.
+var _ gitprovider.CommitStatusClient = &CommitStatusClient{}
+
+// CommitStatusClient operates on the statuses of the commits of a specific repository.
+//
+// The Azure DevOps Git statuses API isn't wrapped by this package yet.
+// Hence, all methods return ErrNoProviderSupport.
+type CommitStatusClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// Get returns the combined status of the commit with the given sha.
+//
+// This isn't implemented by this package yet.
+func (c *CommitStatusClient) Get(_ context.Context, _ string) (gitprovider.CombinedCommitStatus, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// List lists all statuses of the commit with the given sha.
+//
+// This isn't implemented by this package yet.
+func (c *CommitStatusClient) List(_ context.Context, _ string) ([]gitprovider.CommitStatus, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// Create creates a status for the commit with the given sha.
+//
+// This isn't implemented by this package yet.
+func (c *CommitStatusClient) Create(_ context.Context, _ string, _ gitprovider.CommitStatusInfo) (gitprovider.CommitStatus, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
@@ -0,0 +1,50 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuredevops
+
+import (
+	"context"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// FileClient implements the gitprovider.FileClient interface.
+var _ gitprovider.FileClient = &FileClient{}
+
+// FileClient reads the files of a specific repository.
+//
+// The items API of Azure DevOps doesn't report the git modes of files, so executable files and
+// symbolic links can't be told apart from regular files.
+// Hence, all methods return ErrNoProviderSupport.
+type FileClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// Get returns the file at path as of ref, including its content.
+//
+// This is not supported in Azure DevOps.
+func (c *FileClient) Get(_ context.Context, _, _ string) (gitprovider.File, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// List lists the files and directories in the directory at dirPath as of ref.
+//
+// This is not supported in Azure DevOps.
+func (c *FileClient) List(_ context.Context, _, _ string) ([]gitprovider.File, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
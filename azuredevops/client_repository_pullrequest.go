@@ -0,0 +1,224 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuredevops
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+const (
+	pullRequestStatusActive    = "active"
+	pullRequestStatusCompleted = "completed"
+	pullRequestStatusAbandoned = "abandoned"
+)
+
+// PullRequestClient implements the gitprovider.PullRequestClient interface.
+var _ gitprovider.PullRequestClient = &PullRequestClient{}
+
+// PullRequestClient operates on the pull requests for a specific repository.
+type PullRequestClient struct {
+	*clientContext
+	ref gitprovider.OrgRepositoryRef
+}
+
+// Get returns the pull request with the given ID.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *PullRequestClient) Get(ctx context.Context, number int) (gitprovider.PullRequest, error) {
+	// GET /{organization}/{project}/_apis/git/repositories/{repository}/pullrequests/{pullRequestId}
+	pr, err := c.c.GetPullRequest(ctx, c.ref.Organization, projectName(c.ref.OrganizationRef), c.ref.RepositoryName, number)
+	if err != nil {
+		return nil, err
+	}
+	return newPullRequest(c.clientContext, pr, c.ref), nil
+}
+
+// List lists the pull requests of the repository matching the given filters.
+//
+// List returns all available pull requests, using multiple paginated requests if needed.
+func (c *PullRequestClient) List(ctx context.Context, opts ...gitprovider.PullRequestListOption) ([]gitprovider.PullRequest, error) {
+	o, err := gitprovider.MakePullRequestListOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only active pull requests are listed by default
+	query := url.Values{}
+	query.Set("searchCriteria.status", "all")
+	if o.State != nil {
+		query.Set("searchCriteria.status", map[gitprovider.PullRequestState]string{
+			gitprovider.PullRequestStateOpen:   pullRequestStatusActive,
+			gitprovider.PullRequestStateMerged: pullRequestStatusCompleted,
+			gitprovider.PullRequestStateClosed: pullRequestStatusAbandoned,
+		}[*o.State])
+	}
+	if o.HeadBranch != nil {
+		query.Set("searchCriteria.sourceRefName", branchRef(*o.HeadBranch))
+	}
+	if o.BaseBranch != nil {
+		query.Set("searchCriteria.targetRefName", branchRef(*o.BaseBranch))
+	}
+
+	// GET /{organization}/{project}/_apis/git/repositories/{repository}/pullrequests
+	apiObjs, err := c.c.ListPullRequests(ctx, c.ref.Organization, projectName(c.ref.OrganizationRef), c.ref.RepositoryName, query)
+	if err != nil {
+		return nil, err
+	}
+
+	prs := make([]gitprovider.PullRequest, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		prs = append(prs, newPullRequest(c.clientContext, apiObj, c.ref))
+	}
+	return prs, nil
+}
+
+// Create creates a pull request with the given specifications.
+func (c *PullRequestClient) Create(ctx context.Context, title, branch, baseBranch, description string) (gitprovider.PullRequest, error) {
+	req := &PullRequest{
+		Title:         title,
+		SourceRefName: branchRef(branch),
+		TargetRefName: branchRef(baseBranch),
+		Description:   description,
+	}
+
+	// POST /{organization}/{project}/_apis/git/repositories/{repository}/pullrequests
+	pr, err := c.c.CreatePullRequest(ctx, c.ref.Organization, projectName(c.ref.OrganizationRef), c.ref.RepositoryName, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPullRequest(c.clientContext, pr, c.ref), nil
+}
+
+// Update changes the title, description and/or target branch of the pull request with the
+// given ID. nil fields of opts are left unchanged.
+//
+// ErrNotFound is returned if the resource does not exist.
+// ErrNoProviderSupport is returned if labels are given.
+func (c *PullRequestClient) Update(ctx context.Context, number int, opts gitprovider.PullRequestUpdateOptions) (gitprovider.PullRequest, error) {
+	// Azure DevOps labels are managed through a separate API, which isn't supported
+	if len(opts.Labels) != 0 {
+		return nil, fmt.Errorf("pull request labels: %w", gitprovider.ErrNoProviderSupport)
+	}
+	req := &PullRequestUpdate{
+		Title:       opts.Title,
+		Description: opts.Description,
+	}
+	if opts.BaseBranch != nil {
+		req.TargetRefName = gitprovider.StringVar(branchRef(*opts.BaseBranch))
+	}
+	return c.update(ctx, number, req)
+}
+
+// Merge completes the pull request with the given ID, using the "noFastForward", "squash" or
+// "rebase" merge strategy. message is the message of the merge or squash commit; an empty
+// message uses the server default.
+func (c *PullRequestClient) Merge(ctx context.Context, number int, method gitprovider.MergeMethod, message string) error {
+	if err := gitprovider.ValidateMergeMethod(method); err != nil {
+		return fmt.Errorf("merge method %q: %w", method, gitprovider.ErrInvalidArgument)
+	}
+
+	// Completing requires the current head of the source branch
+	pr, err := c.c.GetPullRequest(ctx, c.ref.Organization, projectName(c.ref.OrganizationRef), c.ref.RepositoryName, number)
+	if err != nil {
+		return err
+	}
+	if pr.LastMergeSourceCommit == nil {
+		return fmt.Errorf("didn't expect lastMergeSourceCommit to be nil for pull request %d: %w", number, gitprovider.ErrInvalidServerData)
+	}
+	_, err = c.update(ctx, number, &PullRequestUpdate{
+		Status:                gitprovider.StringVar(pullRequestStatusCompleted),
+		LastMergeSourceCommit: &Commit{CommitID: pr.LastMergeSourceCommit.CommitID},
+		CompletionOptions: &CompletionOptions{
+			MergeStrategy: map[gitprovider.MergeMethod]string{
+				gitprovider.MergeMethodMerge:  "noFastForward",
+				gitprovider.MergeMethodSquash: "squash",
+				gitprovider.MergeMethodRebase: "rebase",
+			}[method],
+			MergeCommitMessage: message,
+		},
+	})
+	return err
+}
+
+// Close abandons the pull request with the given ID.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *PullRequestClient) Close(ctx context.Context, number int) (gitprovider.PullRequest, error) {
+	return c.update(ctx, number, &PullRequestUpdate{Status: gitprovider.StringVar(pullRequestStatusAbandoned)})
+}
+
+func (c *PullRequestClient) update(ctx context.Context, number int, req *PullRequestUpdate) (gitprovider.PullRequest, error) {
+	// PATCH /{organization}/{project}/_apis/git/repositories/{repository}/pullrequests/{pullRequestId}
+	pr, err := c.c.UpdatePullRequest(ctx, c.ref.Organization, projectName(c.ref.OrganizationRef), c.ref.RepositoryName, number, req)
+	if err != nil {
+		return nil, err
+	}
+	return newPullRequest(c.clientContext, pr, c.ref), nil
+}
+
+// Reconcile makes sure the given desired state (req) becomes the actual state in the backing Git provider.
+// The pull request is looked up by req.HeadBranch and req.BaseBranch among the open pull
+// requests; the title and description of req are reconciled.
+//
+// If req doesn't exist under the hood, it is created (actionTaken == true).
+// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+// If req is already the actual state, this is a no-op (actionTaken == false).
+//
+// ErrNoProviderSupport is returned if req has labels.
+func (c *PullRequestClient) Reconcile(ctx context.Context, req gitprovider.PullRequestInfo) (gitprovider.PullRequest, bool, error) {
+	// First thing, validate the request
+	if err := req.ValidateInfo(); err != nil {
+		return nil, false, err
+	}
+	if len(req.Labels) != 0 {
+		return nil, false, fmt.Errorf("pull request labels: %w", gitprovider.ErrNoProviderSupport)
+	}
+
+	// Look for an open pull request from the head into the base branch
+	prs, err := c.List(ctx, &gitprovider.PullRequestListOptions{
+		State:      gitprovider.PullRequestStateVar(gitprovider.PullRequestStateOpen),
+		HeadBranch: &req.HeadBranch,
+		BaseBranch: &req.BaseBranch,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Create if not found
+	if len(prs) == 0 {
+		resp, err := c.Create(ctx, req.Title, req.HeadBranch, req.BaseBranch, req.Description)
+		return resp, true, err
+	}
+
+	// If the desired matches the actual state, just return the actual state
+	actual := prs[0]
+	if req.Equals(actual.Get()) {
+		return actual, false, nil
+	}
+
+	// Apply the desired state by running Update
+	resp, err := c.Update(ctx, actual.Get().Number, gitprovider.PullRequestUpdateOptions{
+		Title:       &req.Title,
+		Description: &req.Description,
+	})
+	return resp, true, err
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuredevops
+
+import (
+	"context"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+const (
+	commentTypeText    = "text"
+	threadStatusActive = "active"
+	// firstCommentID is the ID of the comment starting a thread
+	firstCommentID = 1
+)
+
+// PullRequestCommentClient implements the gitprovider.PullRequestCommentClient interface.
+var _ gitprovider.PullRequestCommentClient = &PullRequestCommentClient{}
+
+// PullRequestCommentClient operates on the comments of a specific pull request. Azure DevOps
+// only has threads of comments, so every comment is the first comment of a thread, and the
+// ID of the comment is the ID of the thread.
+type PullRequestCommentClient struct {
+	*clientContext
+	ref gitprovider.OrgRepositoryRef
+	id  int
+}
+
+// List all comments of the pull request, oldest first. Threads on the files of the pull request,
+// and comments generated by the server, are left out.
+func (c *PullRequestCommentClient) List(ctx context.Context) ([]gitprovider.PullRequestComment, error) {
+	// GET /{organization}/{project}/_apis/git/repositories/{repository}/pullrequests/{pullRequestId}/threads
+	apiObjs, err := c.c.ListPullRequestThreads(ctx, c.ref.Organization, projectName(c.ref.OrganizationRef), c.ref.RepositoryName, c.id)
+	if err != nil {
+		return nil, err
+	}
+
+	comments := make([]gitprovider.PullRequestComment, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		first := apiObj.Comments[0]
+		if apiObj.IsDeleted || len(apiObj.ThreadContext) != 0 || first.IsDeleted || first.CommentType != commentTypeText {
+			continue
+		}
+		comments = append(comments, newPullRequestComment(apiObj.ID, first))
+	}
+	return comments, nil
+}
+
+// Create adds a comment with the given body to the pull request, starting a new thread.
+func (c *PullRequestCommentClient) Create(ctx context.Context, body string) (gitprovider.PullRequestComment, error) {
+	// POST /{organization}/{project}/_apis/git/repositories/{repository}/pullrequests/{pullRequestId}/threads
+	apiObj, err := c.c.CreatePullRequestThread(ctx, c.ref.Organization, projectName(c.ref.OrganizationRef), c.ref.RepositoryName, c.id, &CommentThread{
+		Comments: []*Comment{{Content: body, CommentType: commentTypeText}},
+		Status:   threadStatusActive,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newPullRequestComment(apiObj.ID, apiObj.Comments[0]), nil
+}
+
+// Update changes the body of the comment with the given ID, i.e. of the first comment of the
+// thread with that ID.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *PullRequestCommentClient) Update(ctx context.Context, id int64, body string) (gitprovider.PullRequestComment, error) {
+	// PATCH /{organization}/{project}/_apis/git/repositories/{repository}/pullrequests/{pullRequestId}/threads/{threadId}/comments/{commentId}
+	apiObj, err := c.c.UpdatePullRequestComment(ctx, c.ref.Organization, projectName(c.ref.OrganizationRef), c.ref.RepositoryName, c.id, id, firstCommentID, body)
+	if err != nil {
+		return nil, err
+	}
+	return newPullRequestComment(id, apiObj), nil
+}
+
+// Delete deletes the comment with the given ID, i.e. the first comment of the thread with that ID.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *PullRequestCommentClient) Delete(ctx context.Context, id int64) error {
+	// DELETE /{organization}/{project}/_apis/git/repositories/{repository}/pullrequests/{pullRequestId}/threads/{threadId}/comments/{commentId}
+	return c.c.DeletePullRequestComment(ctx, c.ref.Organization, projectName(c.ref.OrganizationRef), c.ref.RepositoryName, c.id, id, firstCommentID)
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuredevops
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// PullRequestReviewClient implements the gitprovider.PullRequestReviewClient interface.
+var _ gitprovider.PullRequestReviewClient = &PullRequestReviewClient{}
+
+// PullRequestReviewClient operates on the reviews of a specific pull request. Azure DevOps
+// doesn't have reviews, so the votes of the reviewers, and comments, are used instead.
+type PullRequestReviewClient struct {
+	*clientContext
+	ref gitprovider.OrgRepositoryRef
+	id  int
+}
+
+// List lists the reviewers of the pull request who voted for or against it. Reviews only
+// commenting are comments, which are listed by PullRequest.Comments().
+func (c *PullRequestReviewClient) List(ctx context.Context) ([]gitprovider.PullRequestReview, error) {
+	// GET /{organization}/{project}/_apis/git/repositories/{repository}/pullrequests/{pullRequestId}
+	pr, err := c.c.GetPullRequest(ctx, c.ref.Organization, projectName(c.ref.OrganizationRef), c.ref.RepositoryName, c.id)
+	if err != nil {
+		return nil, err
+	}
+
+	reviews := make([]gitprovider.PullRequestReview, 0, len(pr.Reviewers))
+	for _, reviewer := range pr.Reviewers {
+		if reviewer.Vote == voteNone {
+			continue
+		}
+		reviews = append(reviews, newVoteReview(reviewer, nil))
+	}
+	return reviews, nil
+}
+
+// Submit votes for or against the pull request as the authenticated user, or adds a comment to
+// it. A non-empty body is added as a comment when voting.
+func (c *PullRequestReviewClient) Submit(ctx context.Context, state gitprovider.PullRequestReviewState, body string) (gitprovider.PullRequestReview, error) {
+	var vote int
+	switch state {
+	case gitprovider.PullRequestReviewStateApproved:
+		vote = voteApproved
+	case gitprovider.PullRequestReviewStateChangesRequested:
+		vote = voteWaitingForAuthor
+	case gitprovider.PullRequestReviewStateCommented:
+		thread, err := c.createThread(ctx, body)
+		if err != nil {
+			return nil, err
+		}
+		return newCommentReview(thread), nil
+	default:
+		return nil, fmt.Errorf("review state %q: %w", state, gitprovider.ErrInvalidArgument)
+	}
+
+	// Votes are cast by adding the authenticated user as a reviewer
+	// GET /{organization}/_apis/connectionData
+	conn, err := c.c.GetConnectionData(ctx, c.ref.Organization)
+	if err != nil {
+		return nil, err
+	}
+	// PUT /{organization}/{project}/_apis/git/repositories/{repository}/pullrequests/{pullRequestId}/reviewers/{reviewerId}
+	reviewer, err := c.c.CreatePullRequestReviewer(ctx, c.ref.Organization, projectName(c.ref.OrganizationRef), c.ref.RepositoryName, c.id,
+		conn.AuthenticatedUser.ID, vote)
+	if err != nil {
+		return nil, err
+	}
+	if body == "" {
+		return newVoteReview(reviewer, nil), nil
+	}
+	thread, err := c.createThread(ctx, body)
+	if err != nil {
+		return nil, err
+	}
+	return newVoteReview(reviewer, thread.Comments[0]), nil
+}
+
+// createThread starts a new thread with the given comment.
+func (c *PullRequestReviewClient) createThread(ctx context.Context, body string) (*CommentThread, error) {
+	// POST /{organization}/{project}/_apis/git/repositories/{repository}/pullrequests/{pullRequestId}/threads
+	return c.c.CreatePullRequestThread(ctx, c.ref.Organization, projectName(c.ref.OrganizationRef), c.ref.RepositoryName, c.id, &CommentThread{
+		Comments: []*Comment{{Content: body, CommentType: commentTypeText}},
+		Status:   threadStatusActive,
+	})
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuredevops
+
+import (
+	"context"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// TeamAccessClient implements the gitprovider.TeamAccessClient interface.
+var _ gitprovider.TeamAccessClient = &TeamAccessClient{}
+
+// TeamAccessClient operates on the teams list for a specific repository.
+//
+// Repository permissions are managed through security namespaces in Azure DevOps,
+// and can't be expressed as team access.
+// Hence, all methods return ErrNoProviderSupport.
+type TeamAccessClient struct {
+	*clientContext
+	ref gitprovider.OrgRepositoryRef
+}
+
+// Get a team's permission level of this given repository.
+//
+// This is not supported in Azure DevOps.
+func (c *TeamAccessClient) Get(_ context.Context, _ string) (gitprovider.TeamAccess, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// List the team access control list for this repository.
+//
+// This is not supported in Azure DevOps.
+func (c *TeamAccessClient) List(_ context.Context) ([]gitprovider.TeamAccess, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// Create adds a given team to the repository's team access control list.
+//
+// This is not supported in Azure DevOps.
+func (c *TeamAccessClient) Create(_ context.Context, _ gitprovider.TeamAccessInfo) (gitprovider.TeamAccess, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// Reconcile makes sure the given desired state (req) becomes the actual state in the backing Git provider.
+//
+// This is not supported in Azure DevOps.
+func (c *TeamAccessClient) Reconcile(_ context.Context, _ gitprovider.TeamAccessInfo) (gitprovider.TeamAccess, bool, error) {
+	return nil, false, gitprovider.ErrNoProviderSupport
+}
@@ -0,0 +1,1046 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuredevops
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+// newTestClient starts a server handling the API paths of mux, and returns a client pointing to it.
+func newTestClient(t *testing.T, mux *http.ServeMux, opts ...ClientOption) *Client {
+	t.Helper()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	c, err := NewClient(append([]ClientOption{WithDomain(srv.URL)}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c.(*Client)
+}
+
+func writeJSON(t *testing.T, w http.ResponseWriter, status int, obj interface{}) {
+	t.Helper()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(obj); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeError(t *testing.T, w http.ResponseWriter, status int, message, typeKey string) {
+	t.Helper()
+	writeJSON(t, w, status, map[string]interface{}{
+		"$id":     "1",
+		"message": message,
+		"typeKey": typeKey,
+	})
+}
+
+func writeList(t *testing.T, w http.ResponseWriter, values interface{}) {
+	t.Helper()
+	writeJSON(t, w, http.StatusOK, map[string]interface{}{
+		"value": values,
+	})
+}
+
+func decodeJSON(t *testing.T, r *http.Request, obj interface{}) {
+	t.Helper()
+	if err := json.NewDecoder(r.Body).Decode(obj); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	c, err := NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.SupportedDomain(); got != DefaultDomain {
+		t.Errorf("SupportedDomain() = %q, want %q", got, DefaultDomain)
+	}
+	impl := c.(*Client).c.(*azureDevOpsClientImpl)
+	if impl.domainURL != "https://dev.azure.com" || impl.vsspsURL != defaultVSSPSURL {
+		t.Errorf("domainURL, vsspsURL = %q, %q", impl.domainURL, impl.vsspsURL)
+	}
+	if got := c.ProviderID(); got != ProviderID {
+		t.Errorf("ProviderID() = %q, want %q", got, ProviderID)
+	}
+
+	// Azure DevOps Server hosts the identity service itself
+	c, err = NewClient(WithDomain("devops.example.com/tfs/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	impl = c.(*Client).c.(*azureDevOpsClientImpl)
+	if impl.domainURL != "https://devops.example.com/tfs" || impl.vsspsURL != impl.domainURL {
+		t.Errorf("domainURL, vsspsURL = %q, %q", impl.domainURL, impl.vsspsURL)
+	}
+
+	_, err = c.HasTokenPermission(context.Background(), gitprovider.TokenPermissionRWRepository)
+	validation.TestExpectErrors(t, "HasTokenPermission", err, gitprovider.ErrNoProviderSupport)
+	_, err = c.UserRepositories().Get(context.Background(), gitprovider.UserRepositoryRef{})
+	validation.TestExpectErrors(t, "UserRepositories().Get", err, gitprovider.ErrNoProviderSupport)
+}
+
+func TestOrganizationsClient(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/org/_apis/projects/project", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("api-version") != apiVersion {
+			t.Errorf("unexpected api-version %q", r.URL.Query().Get("api-version"))
+		}
+		writeJSON(t, w, http.StatusOK, Project{ID: "p1", Name: "project", Description: "desc", Visibility: projectVisibilityPrivate})
+	})
+	mux.HandleFunc("/org/_apis/projects/missing", func(w http.ResponseWriter, r *http.Request) {
+		writeError(t, w, http.StatusNotFound, "TF200016: The following project does not exist: missing.", "ProjectDoesNotExistWithNameException")
+	})
+	mux.HandleFunc("/org/_apis/projects", func(w http.ResponseWriter, r *http.Request) {
+		// Serve two pages, to test pagination
+		if r.URL.Query().Get("continuationToken") == "next" {
+			writeList(t, w, []Project{{ID: "p2", Name: "other"}})
+			return
+		}
+		w.Header().Set("x-ms-continuationtoken", "next")
+		writeList(t, w, []Project{{ID: "p1", Name: "project"}})
+	})
+	mux.HandleFunc("/org/_apis/projects/project/teams", func(w http.ResponseWriter, r *http.Request) {
+		writeList(t, w, []Team{{ID: "t1", Name: "project Team"}, {ID: "t2", Name: "Devs"}})
+	})
+	mux.HandleFunc("/org/_apis/projects/project/teams/t2/members", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("$top") != fmt.Sprint(defaultPageSize) {
+			t.Errorf("unexpected page size %q", r.URL.Query().Get("$top"))
+		}
+		writeList(t, w, []TeamMember{
+			{Identity: &IdentityRef{DisplayName: "Alice", UniqueName: "alice@example.com"}},
+			{Identity: &IdentityRef{DisplayName: "Bob", UniqueName: "bob@example.com"}},
+		})
+	})
+	c := newTestClient(t, mux)
+	ctx := context.Background()
+
+	orgRef := gitprovider.OrganizationRef{Domain: c.domain, Organization: "org"}
+	org, err := c.Organizations().Get(ctx, orgRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info := org.Get(); *info.Name != "org" || org.APIObject().(*Project) != nil {
+		t.Errorf("Organization.Get() = %+v", info)
+	}
+
+	projectRef := gitprovider.OrganizationRef{Domain: c.domain, Organization: "org", SubOrganizations: []string{"project"}}
+	project, err := c.Organizations().Get(ctx, projectRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info := project.Get(); *info.Name != "project" || *info.Description != "desc" {
+		t.Errorf("Organization.Get() = %+v", info)
+	}
+
+	_, err = c.Organizations().Get(ctx, gitprovider.OrganizationRef{Domain: c.domain, Organization: "org", SubOrganizations: []string{"missing"}})
+	validation.TestExpectErrors(t, "Organizations().Get", err, gitprovider.ErrNotFound)
+
+	_, err = c.Organizations().Get(ctx, gitprovider.OrganizationRef{Domain: "other.com", Organization: "org"})
+	validation.TestExpectErrors(t, "Organizations().Get", err, gitprovider.ErrDomainUnsupported)
+
+	_, err = c.Organizations().List(ctx)
+	validation.TestExpectErrors(t, "Organizations().List", err, gitprovider.ErrNoProviderSupport)
+
+	projects, err := c.Organizations().Children(ctx, orgRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(projects) != 2 || projects[1].Organization().GetIdentity() != "org/other" {
+		t.Errorf("Organizations().Children() = %v, want project and other", projects)
+	}
+	_, err = c.Organizations().Children(ctx, projectRef)
+	validation.TestExpectErrors(t, "Organizations().Children", err, gitprovider.ErrNoProviderSupport)
+
+	team, err := project.Teams().Get(ctx, "devs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := team.Get(); got.Name != "Devs" || len(got.Members) != 2 || got.Members[1] != "bob@example.com" {
+		t.Errorf("Teams().Get() = %+v", got)
+	}
+	_, err = project.Teams().Get(ctx, "missing")
+	validation.TestExpectErrors(t, "Teams().Get", err, gitprovider.ErrNotFound)
+	_, err = org.Teams().List(ctx)
+	validation.TestExpectErrors(t, "Teams().List", err, gitprovider.ErrNoProviderSupport)
+}
+
+// fakeServer is a minimal in-memory implementation of the project, repository and SSH key endpoints.
+type fakeServer struct {
+	t        *testing.T
+	projects map[string]*Project
+	// repos by "{project}/{name}"
+	repos map[string]*Repository
+	keys  []*SessionToken
+	// heads of the branches, and the files on the default branch by path
+	branches map[string]string
+	files    map[string]string
+	// commits, newest first
+	commits []*Commit
+	prs     []*PullRequest
+	// comment threads on all pull requests, in creation order
+	threads []*CommentThread
+}
+
+func newFakeServer(t *testing.T) *fakeServer {
+	return &fakeServer{
+		t: t,
+		projects: map[string]*Project{
+			"project": {ID: "p1", Name: "project", Visibility: projectVisibilityPrivate},
+		},
+		repos:    map[string]*Repository{},
+		branches: map[string]string{},
+		files:    map[string]string{},
+	}
+}
+
+func (s *fakeServer) register(mux *http.ServeMux) {
+	mux.HandleFunc("/org/", s.handle)
+}
+
+func (s *fakeServer) handle(w http.ResponseWriter, r *http.Request) {
+	t := s.t
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/org/"), "/")
+	switch {
+	case strings.HasPrefix(r.URL.Path, "/org/_apis/token/sessiontokens"):
+		s.handleKeys(w, r, parts[3:])
+		return
+	case r.URL.Path == "/org/_apis/connectionData":
+		if r.URL.Query().Get("api-version") != connectionDataAPIVersion {
+			t.Errorf("unexpected api-version %q", r.URL.Query().Get("api-version"))
+		}
+		writeJSON(t, w, http.StatusOK, &ConnectionData{AuthenticatedUser: &Identity{ID: "u1", ProviderDisplayName: "me"}})
+		return
+	case strings.HasPrefix(r.URL.Path, "/org/_apis/projects/"):
+		project, ok := s.projects[parts[2]]
+		if !ok {
+			writeError(t, w, http.StatusNotFound, "TF200016: The following project does not exist: "+parts[2]+".", "ProjectDoesNotExistWithNameException")
+			return
+		}
+		writeJSON(t, w, http.StatusOK, project)
+		return
+	case r.URL.Path == "/org/_apis/git/repositories":
+		repos := []*Repository{}
+		for _, repo := range s.repos {
+			repos = append(repos, repo)
+		}
+		writeList(t, w, repos)
+		return
+	}
+
+	// {project}/_apis/git/repositories[/{repo}[/...]]
+	project, ok := s.projects[parts[0]]
+	if !ok || len(parts) < 4 {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if len(parts) == 4 {
+		switch r.Method {
+		case http.MethodGet:
+			repos := []*Repository{}
+			for name, repo := range s.repos {
+				if strings.HasPrefix(name, project.Name+"/") {
+					repos = append(repos, repo)
+				}
+			}
+			writeList(t, w, repos)
+		case http.MethodPost:
+			req := &CreateRepositoryOptions{}
+			decodeJSON(t, r, req)
+			if req.Project == nil || req.Project.ID != project.ID {
+				t.Errorf("unexpected project in create request: %+v", req.Project)
+			}
+			if _, exists := s.repos[project.Name+"/"+req.Name]; exists {
+				writeError(t, w, http.StatusConflict, "TF400948: A Git repository with the name "+req.Name+" already exists.", "GitRepositoryNameAlreadyExistsException")
+				return
+			}
+			repo := &Repository{
+				ID:      fmt.Sprintf("r%d", len(s.repos)+1),
+				Name:    req.Name,
+				Project: project,
+				WebURL:  "https://dev.azure.com/org/" + project.Name + "/_git/" + req.Name,
+			}
+			s.repos[project.Name+"/"+req.Name] = repo
+			writeJSON(t, w, http.StatusCreated, repo)
+		}
+		return
+	}
+
+	// Repositories can be referred to by name or ID
+	var repo *Repository
+	for name, rp := range s.repos {
+		if rp.ID == parts[4] || name == project.Name+"/"+parts[4] {
+			repo = rp
+		}
+	}
+	if repo == nil {
+		writeError(t, w, http.StatusNotFound, "TF401019: The Git repository with name or identifier "+parts[4]+" does not exist or you do not have permissions for the operation you are attempting.", "GitRepositoryNotFoundException")
+		return
+	}
+	if len(parts) == 5 {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(t, w, http.StatusOK, repo)
+		case http.MethodPatch:
+			req := &Repository{}
+			decodeJSON(t, r, req)
+			repo.DefaultBranch = req.DefaultBranch
+			writeJSON(t, w, http.StatusOK, repo)
+		case http.MethodDelete:
+			delete(s.repos, project.Name+"/"+repo.Name)
+			w.WriteHeader(http.StatusNoContent)
+		}
+		return
+	}
+
+	switch parts[5] {
+	case "refs":
+		if r.Method == http.MethodGet {
+			refs := []*GitRef{}
+			for branch, sha := range s.branches {
+				if strings.HasPrefix("heads/"+branch, r.URL.Query().Get("filter")) {
+					refs = append(refs, &GitRef{Name: "refs/heads/" + branch, ObjectID: sha})
+				}
+			}
+			writeList(t, w, refs)
+			return
+		}
+		var req []*GitRefUpdate
+		decodeJSON(t, r, &req)
+		results := []*GitRefUpdateResult{}
+		for _, update := range req {
+			branch := strings.TrimPrefix(update.Name, "refs/heads/")
+			result := &GitRefUpdateResult{Name: update.Name, NewObjectID: update.NewObjectID, Success: true, UpdateStatus: "succeeded"}
+			head, exists := s.branches[branch]
+			if !exists {
+				head = zeroObjectID
+			}
+			switch {
+			case update.OldObjectID != head:
+				result.Success = false
+				result.UpdateStatus = "staleOldObjectId"
+			case update.NewObjectID == zeroObjectID:
+				delete(s.branches, branch)
+			default:
+				s.branches[branch] = update.NewObjectID
+			}
+			results = append(results, result)
+		}
+		writeList(t, w, results)
+	case "items":
+		path := strings.TrimPrefix(r.URL.Query().Get("path"), "/")
+		if _, ok := s.files[path]; !ok {
+			writeError(t, w, http.StatusNotFound, "TF401174: The item '"+path+"' could not be found in the repository.", "GitItemNotFoundException")
+			return
+		}
+		writeJSON(t, w, http.StatusOK, Item{Path: "/" + path})
+	case "commits":
+		if r.URL.Query().Get("searchCriteria.itemVersion.version") != "main" || r.URL.Query().Get("searchCriteria.$skip") != "2" {
+			t.Errorf("unexpected query %q", r.URL.RawQuery)
+		}
+		writeList(t, w, s.commits)
+	case "pushes":
+		s.handlePush(w, r, repo)
+	case "pullrequests":
+		s.handlePullRequests(w, r, repo, parts[6:])
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (s *fakeServer) handlePullRequests(w http.ResponseWriter, r *http.Request, repo *Repository, rest []string) {
+	t := s.t
+	if len(rest) == 0 {
+		if r.Method == http.MethodGet {
+			query := r.URL.Query()
+			values := []*PullRequest{}
+			for _, pr := range s.prs {
+				if (query.Get("searchCriteria.status") == "all" || query.Get("searchCriteria.status") == pr.Status) &&
+					(query.Get("searchCriteria.sourceRefName") == "" || query.Get("searchCriteria.sourceRefName") == pr.SourceRefName) {
+					values = append(values, pr)
+				}
+			}
+			writeList(t, w, values)
+			return
+		}
+		req := &PullRequest{}
+		decodeJSON(t, r, req)
+		req.PullRequestID = int64(len(s.prs) + 1)
+		req.Status = "active"
+		req.Repository = &Repository{ID: repo.ID, Name: repo.Name}
+		req.LastMergeSourceCommit = &Commit{CommitID: s.branches[strings.TrimPrefix(req.SourceRefName, "refs/heads/")]}
+		s.prs = append(s.prs, req)
+		writeJSON(t, w, http.StatusCreated, req)
+		return
+	}
+
+	var pr *PullRequest
+	for _, p := range s.prs {
+		if fmt.Sprint(p.PullRequestID) == rest[0] {
+			pr = p
+		}
+	}
+	if pr == nil {
+		writeError(t, w, http.StatusNotFound, "TF401180: The requested pull request was not found.", "GitPullRequestNotFoundException")
+		return
+	}
+	if len(rest) > 1 {
+		switch rest[1] {
+		case "threads":
+			s.handleThreads(w, r, rest[2:])
+		case "reviewers":
+			req := &IdentityRefWithVote{}
+			decodeJSON(t, r, req)
+			reviewer := &IdentityRefWithVote{ID: rest[2], UniqueName: "me@example.com", Vote: req.Vote}
+			pr.Reviewers = append(pr.Reviewers, reviewer)
+			writeJSON(t, w, http.StatusOK, reviewer)
+		}
+		return
+	}
+	if r.Method == http.MethodPatch {
+		req := &PullRequestUpdate{}
+		decodeJSON(t, r, req)
+		if req.Title != nil {
+			pr.Title = *req.Title
+		}
+		if req.Status != nil {
+			if *req.Status == "completed" && (req.LastMergeSourceCommit == nil || req.LastMergeSourceCommit.CommitID != pr.LastMergeSourceCommit.CommitID ||
+				req.CompletionOptions == nil || req.CompletionOptions.MergeStrategy != "squash") {
+				t.Errorf("unexpected completion request %+v", req)
+			}
+			pr.Status = *req.Status
+		}
+	}
+	writeJSON(t, w, http.StatusOK, pr)
+}
+
+func (s *fakeServer) handleThreads(w http.ResponseWriter, r *http.Request, rest []string) {
+	t := s.t
+	if len(rest) == 0 {
+		if r.Method == http.MethodGet {
+			writeJSON(t, w, http.StatusOK, map[string]interface{}{"value": s.threads})
+			return
+		}
+		req := &CommentThread{}
+		decodeJSON(t, r, req)
+		req.ID = int64(len(s.threads) + 1)
+		req.Comments[0].ID = 1
+		req.Comments[0].Author = &IdentityRef{UniqueName: "me@example.com"}
+		s.threads = append(s.threads, req)
+		writeJSON(t, w, http.StatusOK, req)
+		return
+	}
+
+	for _, thread := range s.threads {
+		if fmt.Sprint(thread.ID) != rest[0] || len(rest) != 3 || rest[2] != "1" || thread.Comments[0].IsDeleted {
+			continue
+		}
+		comment := thread.Comments[0]
+		if r.Method == http.MethodDelete {
+			comment.IsDeleted = true
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		req := &Comment{}
+		decodeJSON(t, r, req)
+		comment.Content = req.Content
+		writeJSON(t, w, http.StatusOK, comment)
+		return
+	}
+	writeError(t, w, http.StatusNotFound, "The requested thread or comment was not found.", "CommentNotFoundException")
+}
+
+func (s *fakeServer) handlePush(w http.ResponseWriter, r *http.Request, repo *Repository) {
+	t := s.t
+	req := &Push{}
+	decodeJSON(t, r, req)
+	update := req.RefUpdates[0]
+	branch := strings.TrimPrefix(update.Name, "refs/heads/")
+	head, exists := s.branches[branch]
+	if !exists {
+		head = zeroObjectID
+	}
+	if update.OldObjectID != head {
+		writeError(t, w, http.StatusConflict, "TF402436: The ref "+update.Name+" has been updated.", "GitReferenceStaleException")
+		return
+	}
+	commit := req.Commits[0]
+	for _, change := range commit.Changes {
+		path := strings.TrimPrefix(change.Item.Path, "/")
+		_, fileExists := s.files[path]
+		creates := change.ChangeType == changeTypeAdd || change.ChangeType == changeTypeRename
+		if _, sourceExists := s.files[strings.TrimPrefix(change.SourceServerItem, "/")]; creates == fileExists ||
+			(change.ChangeType == changeTypeRename && !sourceExists) {
+			writeError(t, w, http.StatusBadRequest, "TF402455: unexpected "+change.ChangeType+" of "+change.Item.Path, "GitItemAlreadyExistsException")
+			return
+		}
+		if change.ChangeType == changeTypeDelete {
+			delete(s.files, path)
+			continue
+		}
+		if change.ChangeType == changeTypeRename {
+			source := strings.TrimPrefix(change.SourceServerItem, "/")
+			s.files[path] = s.files[source]
+			delete(s.files, source)
+			continue
+		}
+		data, err := base64.StdEncoding.DecodeString(change.NewContent.Content)
+		if err != nil || change.NewContent.ContentType != contentTypeBase64 {
+			t.Fatalf("unexpected content %+v", change.NewContent)
+		}
+		s.files[path] = string(data)
+	}
+	created := &Commit{
+		CommitID:  fmt.Sprintf("%040d", len(s.commits)+1),
+		Author:    commit.Author,
+		Committer: commit.Committer,
+		Comment:   commit.Comment,
+		Parents:   commit.Parents,
+	}
+	s.commits = append([]*Commit{created}, s.commits...)
+	s.branches[branch] = created.CommitID
+	// The first branch pushed becomes the default branch
+	if len(repo.DefaultBranch) == 0 {
+		repo.DefaultBranch = update.Name
+	}
+	writeJSON(t, w, http.StatusCreated, Push{PushID: 1, RefUpdates: req.RefUpdates, Commits: []*Commit{created}})
+}
+
+func (s *fakeServer) handleKeys(w http.ResponseWriter, r *http.Request, rest []string) {
+	t := s.t
+	if r.URL.Query().Get("isPublic") != "true" || r.URL.Query().Get("api-version") != sessionTokensAPIVersion {
+		t.Errorf("unexpected query %q", r.URL.RawQuery)
+	}
+	switch {
+	case r.Method == http.MethodGet:
+		writeJSON(t, w, http.StatusOK, s.keys)
+	case r.Method == http.MethodPost:
+		req := &SessionToken{}
+		decodeJSON(t, r, req)
+		if !req.IsPublic {
+			t.Error("expected an SSH public key to be created")
+		}
+		for _, k := range s.keys {
+			if k.PublicData == req.PublicData {
+				writeError(t, w, http.StatusBadRequest, "An SSH key with the same public data already exists.", "")
+				return
+			}
+		}
+		req.AuthorizationID = fmt.Sprintf("a%d", len(s.keys)+1)
+		s.keys = append(s.keys, req)
+		writeJSON(t, w, http.StatusOK, req)
+	case r.Method == http.MethodDelete && len(rest) == 1:
+		for i, k := range s.keys {
+			if k.AuthorizationID == rest[0] {
+				s.keys = append(s.keys[:i], s.keys[i+1:]...)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		writeError(t, w, http.StatusNotFound, "", "SessionTokenNotFoundException")
+	}
+}
+
+func TestOrgRepositoriesClient(t *testing.T) {
+	mux := http.NewServeMux()
+	srv := newFakeServer(t)
+	srv.register(mux)
+	c := newTestClient(t, mux)
+	ctx := context.Background()
+
+	ref := newOrgRepositoryRef(c.domain, "org", []string{"project"}, "repo")
+	_, err := c.OrgRepositories().Get(ctx, ref)
+	validation.TestExpectErrors(t, "OrgRepositories().Get", err, gitprovider.ErrNotFound)
+
+	_, err = c.OrgRepositories().Get(ctx, newOrgRepositoryRef(c.domain, "org", nil, "repo"))
+	validation.TestExpectErrors(t, "OrgRepositories().Get", err, gitprovider.ErrInvalidArgument)
+
+	// Unsupported fields are rejected before creating anything
+	_, err = c.OrgRepositories().Create(ctx, ref, gitprovider.RepositoryInfo{Description: gitprovider.StringVar("desc")})
+	validation.TestExpectErrors(t, "OrgRepositories().Create", err, gitprovider.ErrNoProviderSupport)
+	_, err = c.OrgRepositories().Create(ctx, ref, gitprovider.RepositoryInfo{
+		Visibility: gitprovider.RepositoryVisibilityVar(gitprovider.RepositoryVisibilityPublic),
+	})
+	validation.TestExpectErrors(t, "OrgRepositories().Create", err, gitprovider.ErrNoProviderSupport)
+	_, err = c.OrgRepositories().Create(ctx, ref, gitprovider.RepositoryInfo{}, &gitprovider.RepositoryCreateOptions{
+		LicenseTemplate: gitprovider.LicenseTemplateVar(gitprovider.LicenseTemplateMIT),
+	})
+	validation.TestExpectErrors(t, "OrgRepositories().Create", err, gitprovider.ErrNoProviderSupport)
+	if len(srv.repos) != 0 {
+		t.Fatalf("expected no repositories to be created, got %v", srv.repos)
+	}
+
+	// Reconcile should create the repository, and push a README to the default branch
+	repo, actionTaken, err := c.OrgRepositories().Reconcile(ctx, ref, gitprovider.RepositoryInfo{
+		DefaultBranch: gitprovider.StringVar("main"),
+	}, &gitprovider.RepositoryCreateOptions{AutoInit: gitprovider.BoolVar(true)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !actionTaken {
+		t.Error("expected Reconcile to create the repository")
+	}
+	info := repo.Get()
+	if *info.Visibility != gitprovider.RepositoryVisibilityPrivate || *info.DefaultBranch != "main" || info.Description != nil {
+		t.Errorf("unexpected repository info: %+v", info)
+	}
+	if srv.files["README.md"] != "# repo\n" || srv.commits[0].Comment != initialCommitMessage {
+		t.Errorf("expected an initial commit with a README, got %v", srv.files)
+	}
+
+	// Creating it again is an error
+	_, err = c.OrgRepositories().Create(ctx, ref, gitprovider.RepositoryInfo{})
+	validation.TestExpectErrors(t, "OrgRepositories().Create", err, gitprovider.ErrAlreadyExists)
+
+	// Reconciling the same state again is a no-op
+	_, actionTaken, err = c.OrgRepositories().Reconcile(ctx, ref, gitprovider.RepositoryInfo{
+		DefaultBranch: gitprovider.StringVar("main"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actionTaken {
+		t.Error("expected Reconcile to be a no-op")
+	}
+
+	// Changing the default branch updates the repository
+	srv.branches["develop"] = srv.branches["main"]
+	_, actionTaken, err = c.OrgRepositories().Reconcile(ctx, ref, gitprovider.RepositoryInfo{
+		DefaultBranch: gitprovider.StringVar("develop"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !actionTaken || srv.repos["project/repo"].DefaultBranch != "refs/heads/develop" {
+		t.Error("expected Reconcile to change the default branch to develop")
+	}
+
+	// The visibility of the project can't be changed
+	err = repo.Set(gitprovider.RepositoryInfo{
+		Visibility: gitprovider.RepositoryVisibilityVar(gitprovider.RepositoryVisibilityPublic),
+	})
+	validation.TestExpectErrors(t, "Repository.Set", err, gitprovider.ErrNoProviderSupport)
+
+	// Listing the organization lists the repositories of all projects
+	repos, err := c.OrgRepositories().List(ctx, gitprovider.OrganizationRef{Domain: c.domain, Organization: "org"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repos) != 1 || repos[0].Repository().(gitprovider.OrgRepositoryRef).SubOrganizations[0] != "project" {
+		t.Errorf("OrgRepositories().List() = %v, want [repo]", repos)
+	}
+
+	_, err = repo.TeamAccess().List(ctx)
+	validation.TestExpectErrors(t, "TeamAccess().List", err, gitprovider.ErrNoProviderSupport)
+
+	// Deleting requires destructive actions to be enabled
+	err = repo.Delete(ctx)
+	validation.TestExpectErrors(t, "Repository.Delete", err, gitprovider.ErrDestructiveCallDisallowed)
+
+	c = newTestClient(t, mux, WithDestructiveAPICalls(true))
+	repo, err = c.OrgRepositories().Get(ctx, newOrgRepositoryRef(c.domain, "org", []string{"project"}, "repo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Delete(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(srv.repos) != 0 {
+		t.Errorf("expected the repository to be deleted, got %v", srv.repos)
+	}
+}
+
+func TestDeployKeyClient(t *testing.T) {
+	mux := http.NewServeMux()
+	srv := newFakeServer(t)
+	srv.repos["project/repo"] = &Repository{ID: "r1", Name: "repo", Project: srv.projects["project"]}
+	srv.register(mux)
+	c := newTestClient(t, mux)
+	ctx := context.Background()
+
+	repo, err := c.OrgRepositories().Get(ctx, newOrgRepositoryRef(c.domain, "org", []string{"project"}, "repo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = repo.DeployKeys().Get(ctx, "flux")
+	validation.TestExpectErrors(t, "DeployKeys().Get", err, gitprovider.ErrNotFound)
+
+	// Read-only keys can't be expressed
+	req := gitprovider.DeployKeyInfo{
+		Name: "flux",
+		Key:  []byte("ssh-rsa AAAA flux@example.com"),
+	}
+	_, _, err = repo.DeployKeys().Reconcile(ctx, req)
+	validation.TestExpectErrors(t, "DeployKeys().Reconcile", err, gitprovider.ErrNoProviderSupport)
+
+	req.ReadOnly = gitprovider.BoolVar(false)
+	_, actionTaken, err := repo.DeployKeys().Reconcile(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !actionTaken || len(srv.keys) != 1 || srv.keys[0].DisplayName != "flux" {
+		t.Fatalf("expected Reconcile to create the key, got %v", srv.keys)
+	}
+
+	_, err = repo.DeployKeys().Create(ctx, req)
+	validation.TestExpectErrors(t, "DeployKeys().Create", err, gitprovider.ErrAlreadyExists)
+
+	// Reconciling again is a no-op
+	key, actionTaken, err := repo.DeployKeys().Reconcile(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actionTaken {
+		t.Errorf("expected Reconcile to be a no-op, got %s", key.Get().Key)
+	}
+
+	// Changing the key recreates it
+	req.Key = []byte("ssh-ed25519 BBBB flux@example.com")
+	_, actionTaken, err = repo.DeployKeys().Reconcile(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !actionTaken || len(srv.keys) != 1 || srv.keys[0].PublicData != string(req.Key) {
+		t.Errorf("expected Reconcile to recreate the key, got %v", srv.keys)
+	}
+
+	keys, err := repo.DeployKeys().List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || *keys[0].Get().ReadOnly {
+		t.Errorf("DeployKeys().List() = %v, want 1 read-write key", keys)
+	}
+	if err := keys[0].Delete(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(srv.keys) != 0 {
+		t.Errorf("expected the key to be deleted, got %v", srv.keys)
+	}
+}
+
+func TestCommitsBranchesPullRequests(t *testing.T) {
+	mux := http.NewServeMux()
+	srv := newFakeServer(t)
+	srv.repos["project/repo"] = &Repository{ID: "r1", Name: "repo", Project: srv.projects["project"]}
+	srv.register(mux)
+	c := newTestClient(t, mux)
+	ctx := context.Background()
+
+	repo, err := c.OrgRepositories().Get(ctx, newOrgRepositoryRef(c.domain, "org", []string{"project"}, "repo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = repo.Commits().Create(ctx, "main", "empty", nil)
+	if err == nil {
+		t.Error("expected an error when committing no files")
+	}
+
+	// The first push creates the branch
+	first, err := repo.Commits().Create(ctx, "main", "add files", []gitprovider.CommitFile{
+		{
+			Path:    gitprovider.StringVar("foo.txt"),
+			Content: gitprovider.StringVar("foo"),
+		},
+		{
+			Path:    gitprovider.StringVar("gone.txt"),
+			Content: gitprovider.StringVar("gone"),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// All files are changed in a single commit on top of the head of the branch
+	commit, err := repo.Commits().Create(ctx, "main", "change files", []gitprovider.CommitFile{
+		{
+			Path:    gitprovider.StringVar("foo.txt"),
+			Content: gitprovider.StringVar("bar"),
+		},
+		{
+			Path:    gitprovider.StringVar("dir/bar.txt"),
+			Content: gitprovider.StringVar("bar"),
+		},
+		{
+			Path: gitprovider.StringVar("gone.txt"),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(srv.commits) != 2 || commit.Get().Sha != srv.branches["main"] || commit.Get().Sha == first.Get().Sha {
+		t.Errorf("unexpected commit %v, server has %v", commit.Get(), srv.commits)
+	}
+	if srv.files["foo.txt"] != "bar" || srv.files["dir/bar.txt"] != "bar" || len(srv.files) != 2 {
+		t.Errorf("unexpected files after commit: %v", srv.files)
+	}
+
+	commits, err := repo.Commits().ListPage(ctx, "main", 2, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 2 || commits[0].Get().Sha != commit.Get().Sha {
+		t.Errorf("Commits().ListPage() = %v, want 2 commits", commits)
+	}
+
+	if err := repo.Branches().Create(ctx, "feature", commit.Get().Sha); err != nil {
+		t.Fatal(err)
+	}
+	if srv.branches["feature"] != commit.Get().Sha {
+		t.Errorf("expected branch feature to point to %s, got %v", commit.Get().Sha, srv.branches)
+	}
+	err = repo.Branches().Create(ctx, "feature", first.Get().Sha)
+	validation.TestExpectErrors(t, "Branches().Create", err, gitprovider.ErrAlreadyExists)
+
+	branch, err := repo.Branches().Get(ctx, "feature")
+	if want := (gitprovider.BranchInfo{Name: "feature", Sha: commit.Get().Sha}); err != nil || branch.Get() != want {
+		t.Errorf("Branches().Get() = %v, %v, want %+v", branch, err, want)
+	}
+	// The refs filter matches prefixes, Get must only return exact matches
+	_, err = repo.Branches().Get(ctx, "feat")
+	validation.TestExpectErrors(t, "Branches().Get", err, gitprovider.ErrNotFound)
+
+	other, err := repo.Branches().CreateFrom(ctx, "other", "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (gitprovider.BranchInfo{Name: "other", Sha: commit.Get().Sha}); other.Get() != want || srv.branches["other"] != want.Sha {
+		t.Errorf("Branches().CreateFrom() = %+v, want %+v", other.Get(), want)
+	}
+	_, err = repo.Branches().CreateFrom(ctx, "another", "missing")
+	validation.TestExpectErrors(t, "Branches().CreateFrom", err, gitprovider.ErrNotFound)
+
+	branches, err := repo.Branches().List(ctx)
+	if err != nil || len(branches) != 3 {
+		t.Errorf("Branches().List() = %v, %v, want 3 branches", branches, err)
+	}
+	err = repo.Branches().Delete(ctx, "other")
+	validation.TestExpectErrors(t, "Branches().Delete", err, gitprovider.ErrDestructiveCallDisallowed)
+	dc := newTestClient(t, mux, WithDestructiveAPICalls(true))
+	drepo, err := dc.OrgRepositories().Get(ctx, gitprovider.OrgRepositoryRef{
+		OrganizationRef: gitprovider.OrganizationRef{Domain: dc.SupportedDomain(), Organization: "org", SubOrganizations: []string{"project"}},
+		RepositoryName:  "repo",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := drepo.Branches().Delete(ctx, "other"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := srv.branches["other"]; ok {
+		t.Error("expected branch other to be deleted")
+	}
+	err = drepo.Branches().Delete(ctx, "other")
+	validation.TestExpectErrors(t, "Branches().Delete", err, gitprovider.ErrNotFound)
+
+	pr, err := repo.PullRequests().Create(ctx, "title", "feature", "main", "description")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantURL := fmt.Sprintf("%s/org/project/_git/repo/pullrequest/1", repo.(*orgRepository).c.(*azureDevOpsClientImpl).domainURL)
+	if got := pr.Get().WebURL; got != wantURL {
+		t.Errorf("PullRequest.Get().WebURL = %q, want %q", got, wantURL)
+	}
+	if got := srv.prs[0]; got.SourceRefName != "refs/heads/feature" || got.TargetRefName != "refs/heads/main" || got.Description != "description" {
+		t.Errorf("unexpected pull request: %+v", got)
+	}
+	pr, err = repo.PullRequests().Get(ctx, 1)
+	if err != nil || pr.Get().Number != 1 || pr.Get().State != gitprovider.PullRequestStateOpen || pr.Get().HeadBranch != "feature" {
+		t.Errorf("PullRequests().Get() = %+v, %v", pr, err)
+	}
+	_, err = repo.PullRequests().Get(ctx, 2)
+	validation.TestExpectErrors(t, "PullRequests().Get", err, gitprovider.ErrNotFound)
+
+	pr, err = repo.PullRequests().Update(ctx, 1, gitprovider.PullRequestUpdateOptions{Title: gitprovider.StringVar("new title")})
+	if err != nil || pr.Get().Title != "new title" || pr.Get().Description != "description" {
+		t.Errorf("PullRequests().Update() = %+v, %v", pr, err)
+	}
+
+	req := gitprovider.PullRequestInfo{Title: "new title", Description: "description", HeadBranch: "feature", BaseBranch: "main"}
+	pr, actionTaken, err := repo.PullRequests().Reconcile(ctx, req)
+	if err != nil || actionTaken || pr.Get().Number != 1 {
+		t.Errorf("PullRequests().Reconcile() = %+v, %v, %v", pr, actionTaken, err)
+	}
+	req.Labels = []string{"bot"}
+	_, _, err = repo.PullRequests().Reconcile(ctx, req)
+	validation.TestExpectErrors(t, "PullRequests().Reconcile", err, gitprovider.ErrNoProviderSupport)
+
+	comment, err := pr.Comments().Create(ctx, "plan output")
+	if err != nil || comment.Get().ID != 1 || comment.Get().Author != "me@example.com" {
+		t.Errorf("Comments().Create() = %+v, %v", comment, err)
+	}
+	srv.threads = append(srv.threads, &CommentThread{ID: 2, Comments: []*Comment{{ID: 1, Content: "Policy status updated", CommentType: "system"}}})
+	if comment, err = pr.Comments().Update(ctx, 1, "new plan output"); err != nil || comment.Get().ID != 1 || comment.Get().Body != "new plan output" {
+		t.Errorf("Comments().Update() = %+v, %v", comment, err)
+	}
+	comments, err := pr.Comments().List(ctx)
+	if err != nil || len(comments) != 1 || comments[0].Get().Body != "new plan output" {
+		t.Errorf("Comments().List() = %v, %v", comments, err)
+	}
+	if err := pr.Comments().Delete(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	err = pr.Comments().Delete(ctx, 1)
+	validation.TestExpectErrors(t, "Comments().Delete", err, gitprovider.ErrNotFound)
+	if comments, err := pr.Comments().List(ctx); err != nil || len(comments) != 0 {
+		t.Errorf("Comments().List() = %v, %v, want no comments", comments, err)
+	}
+
+	review, err := pr.Reviews().Submit(ctx, gitprovider.PullRequestReviewStateChangesRequested, "needs work")
+	if err != nil || review.Get().State != gitprovider.PullRequestReviewStateChangesRequested || review.Get().Body != "needs work" {
+		t.Errorf("Reviews().Submit() = %+v, %v", review, err)
+	}
+	if got := srv.prs[0].Reviewers; len(got) != 1 || got[0].ID != "u1" || got[0].Vote != voteWaitingForAuthor {
+		t.Errorf("unexpected reviewers %+v", got)
+	}
+	reviews, err := pr.Reviews().List(ctx)
+	if err != nil || len(reviews) != 1 || reviews[0].Get().Author != "me@example.com" {
+		t.Errorf("Reviews().List() = %v, %v", reviews, err)
+	}
+
+	if err := repo.PullRequests().Merge(ctx, 1, gitprovider.MergeMethodSquash, "squashed"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repo.PullRequests().Create(ctx, "other", "other", "main", ""); err != nil {
+		t.Fatal(err)
+	}
+	pr, err = repo.PullRequests().Close(ctx, 2)
+	if err != nil || pr.Get().State != gitprovider.PullRequestStateClosed {
+		t.Errorf("PullRequests().Close() = %+v, %v", pr, err)
+	}
+
+	prs, err := repo.PullRequests().List(ctx, &gitprovider.PullRequestListOptions{State: gitprovider.PullRequestStateVar(gitprovider.PullRequestStateMerged)})
+	if err != nil || len(prs) != 1 || prs[0].Get().Number != 1 {
+		t.Errorf("PullRequests().List(merged) = %v, %v", prs, err)
+	}
+	prs, err = repo.PullRequests().List(ctx, &gitprovider.PullRequestListOptions{HeadBranch: gitprovider.StringVar("other")})
+	if err != nil || len(prs) != 1 || prs[0].Get().Number != 2 {
+		t.Errorf("PullRequests().List(head) = %v, %v", prs, err)
+	}
+
+	_, err = repo.Commits().Create(ctx, "main", "executable", []gitprovider.CommitFile{{
+		Path:    gitprovider.StringVar("run.sh"),
+		Content: gitprovider.StringVar("echo"),
+		Mode:    gitprovider.FileModeVar(gitprovider.FileModeExecutable),
+	}})
+	validation.TestExpectErrors(t, "Commits().Create", err, gitprovider.ErrNoProviderSupport)
+	if _, err := repo.Commits().Create(ctx, "main", "add files", []gitprovider.CommitFile{
+		{
+			Action:  gitprovider.CommitFileActionVar(gitprovider.CommitFileActionCreate),
+			Path:    gitprovider.StringVar("a.txt"),
+			Content: gitprovider.StringVar("a"),
+		},
+		{
+			Path:     gitprovider.StringVar("c.bin"),
+			Content:  gitprovider.StringVar("AAEC"),
+			Encoding: gitprovider.ContentEncodingVar(gitprovider.ContentEncodingBase64),
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// Files are renamed, unless their content changes too
+	parent := srv.branches["main"]
+	if _, err := repo.Commits().Create(ctx, "main", "move files", []gitprovider.CommitFile{
+		{
+			Action:       gitprovider.CommitFileActionVar(gitprovider.CommitFileActionMove),
+			Path:         gitprovider.StringVar("b.txt"),
+			PreviousPath: gitprovider.StringVar("a.txt"),
+		},
+		{
+			Action:       gitprovider.CommitFileActionVar(gitprovider.CommitFileActionMove),
+			Path:         gitprovider.StringVar("d.txt"),
+			PreviousPath: gitprovider.StringVar("c.bin"),
+			Content:      gitprovider.StringVar("d"),
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	_, aExists := srv.files["a.txt"]
+	_, cExists := srv.files["c.bin"]
+	if srv.files["b.txt"] != "a" || srv.files["d.txt"] != "d" || aExists || cExists {
+		t.Errorf("unexpected files after moving: %v", srv.files)
+	}
+
+	// The push is rejected if the branch moved since parent was read
+	_, err = repo.Commits().Create(ctx, "main", "stale", []gitprovider.CommitFile{{
+		Path:    gitprovider.StringVar("b.txt"),
+		Content: gitprovider.StringVar("b"),
+	}}, &gitprovider.CommitCreateOptions{ExpectedParentSHA: &parent})
+	validation.TestExpectErrors(t, "Commits().Create() on a moved branch", err, gitprovider.ErrConflict)
+	if srv.files["b.txt"] != "a" {
+		t.Errorf("stale commit changed b.txt to %q", srv.files["b.txt"])
+	}
+
+	// New branches need a start branch, as the repository isn't empty
+	files := []gitprovider.CommitFile{{Path: gitprovider.StringVar("new.txt"), Content: gitprovider.StringVar("new")}}
+	_, err = repo.Commits().Create(ctx, "new", "no start", files)
+	validation.TestExpectErrors(t, "Commits().Create() on a missing branch", err, gitprovider.ErrNotFound)
+	head := srv.branches["main"]
+	author := &gitprovider.CommitAuthor{Name: "Jane", Email: "jane@example.com", Date: time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)}
+	commit, err = repo.Commits().Create(ctx, "new", "start", files, &gitprovider.CommitCreateOptions{
+		StartBranch: gitprovider.StringVar("main"),
+		Author:      author,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if srv.branches["new"] != commit.Get().Sha || srv.branches["main"] != head || !reflect.DeepEqual(commit.Get().ParentShas, []string{head}) {
+		t.Errorf("unexpected branches %v and parents %v after creating one from main", srv.branches, commit.Get().ParentShas)
+	}
+	if got := commit.Get(); !reflect.DeepEqual(got.Author, author) || got.Committer != nil {
+		t.Errorf("Commits().Create() = %+v, want author %+v and no committer", got, author)
+	}
+}
+
+func TestClient_invalidCredentials(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/org/_apis/projects", func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "" || pass != "wrong" {
+			t.Errorf("unexpected basic auth %q:%q", user, pass)
+		}
+		// The server responds with its sign-in page
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusNonAuthoritativeInfo)
+		_, _ = w.Write([]byte("<html>Azure DevOps Services | Sign In</html>"))
+	})
+	c := newTestClient(t, mux, WithPersonalAccessToken("wrong"))
+	_, err := c.Organizations().Children(context.Background(), gitprovider.OrganizationRef{Domain: c.domain, Organization: "org"})
+	validation.TestExpectErrors(t, "Organizations().Children", err, &gitprovider.InvalidCredentialsError{})
+	var errResp *ErrorResponse
+	if !errors.As(err, &errResp) || errResp.Response.StatusCode != http.StatusNonAuthoritativeInfo {
+		t.Errorf("expected the error to wrap the ErrorResponse, got %v", err)
+	}
+}
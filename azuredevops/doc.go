@@ -0,0 +1,21 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package azuredevops implements the gitprovider interfaces for Azure DevOps Services and Server,
+// using the REST API 7.1. An Azure DevOps organization (or collection) maps to an organization,
+// and its projects are exposed as sub-organizations, which contain the repositories.
+// User repositories don't exist in Azure DevOps.
+package azuredevops
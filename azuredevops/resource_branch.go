@@ -0,0 +1,66 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuredevops
+
+import (
+	"strings"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+func newBranch(apiObj *GitRef) *branch {
+	return &branch{
+		r: *apiObj,
+	}
+}
+
+var _ gitprovider.Branch = &branch{}
+
+type branch struct {
+	r GitRef
+}
+
+func (b *branch) Get() gitprovider.BranchInfo {
+	return branchFromAPI(&b.r)
+}
+
+func (b *branch) APIObject() interface{} {
+	return &b.r
+}
+
+func branchFromAPI(apiObj *GitRef) gitprovider.BranchInfo {
+	// Branch policies aren't part of the ref, only locking the branch is
+	return gitprovider.BranchInfo{
+		Name:      strings.TrimPrefix(apiObj.Name, branchRef("")),
+		Sha:       apiObj.ObjectID,
+		Protected: apiObj.IsLocked,
+	}
+}
+
+// validateGitRefAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateGitRefAPI(apiObj *GitRef) error {
+	return validateAPIObject("AzureDevOps.GitRef", func(validator validation.Validator) {
+		if len(apiObj.Name) == 0 {
+			validator.Required("Name")
+		}
+		if len(apiObj.ObjectID) == 0 {
+			validator.Required("ObjectID")
+		}
+	})
+}
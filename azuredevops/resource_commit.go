@@ -0,0 +1,97 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuredevops
+
+import (
+	"time"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+func newCommit(c *CommitClient, commit *Commit) *commitType {
+	return &commitType{
+		k: *commit,
+		c: c,
+	}
+}
+
+var _ gitprovider.Commit = &commitType{}
+
+type commitType struct {
+	k Commit
+	c *CommitClient
+}
+
+func (c *commitType) Get() gitprovider.CommitInfo {
+	return commitFromAPI(&c.k)
+}
+
+func (c *commitType) APIObject() interface{} {
+	return &c.k
+}
+
+func commitFromAPI(apiObj *Commit) gitprovider.CommitInfo {
+	// The tree is only returned when getting a single commit
+	return gitprovider.CommitInfo{
+		Sha:        apiObj.CommitID,
+		TreeSha:    apiObj.TreeID,
+		Message:    apiObj.Comment,
+		Author:     gitUserDateFromAPI(apiObj.Author),
+		Committer:  gitUserDateFromAPI(apiObj.Committer),
+		ParentShas: apiObj.Parents,
+		WebURL:     apiObj.RemoteURL,
+	}
+}
+
+func gitUserDateFromAPI(apiObj *GitUserDate) *gitprovider.CommitAuthor {
+	if apiObj == nil {
+		return nil
+	}
+	// Dates that can't be parsed are left as zero
+	date, _ := time.Parse(time.RFC3339, apiObj.Date)
+	return &gitprovider.CommitAuthor{
+		Name:  apiObj.Name,
+		Email: apiObj.Email,
+		Date:  date,
+	}
+}
+
+func gitUserDateToAPI(author *gitprovider.CommitAuthor) *GitUserDate {
+	if author == nil {
+		return nil
+	}
+	apiObj := &GitUserDate{
+		Name:  author.Name,
+		Email: author.Email,
+	}
+	// A zero date is left out, for the server to use the time of the push
+	if !author.Date.IsZero() {
+		apiObj.Date = author.Date.Format(time.RFC3339)
+	}
+	return apiObj
+}
+
+// validateCommitAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateCommitAPI(apiObj *Commit) error {
+	return validateAPIObject("AzureDevOps.Commit", func(validator validation.Validator) {
+		if len(apiObj.CommitID) == 0 {
+			validator.Required("CommitID")
+		}
+	})
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuredevops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+func newDeployKey(c *DeployKeyClient, key *SessionToken) *deployKey {
+	return &deployKey{
+		k: *key,
+		c: c,
+	}
+}
+
+var _ gitprovider.DeployKey = &deployKey{}
+
+type deployKey struct {
+	k SessionToken
+	c *DeployKeyClient
+}
+
+func (dk *deployKey) Get() gitprovider.DeployKeyInfo {
+	return deployKeyFromAPI(&dk.k)
+}
+
+func (dk *deployKey) Set(info gitprovider.DeployKeyInfo) error {
+	if err := info.ValidateInfo(); err != nil {
+		return err
+	}
+	if info.ReadOnly != nil && *info.ReadOnly {
+		return fmt.Errorf("azure devops SSH keys grant the permissions of the user, read-only keys are not supported: %w", gitprovider.ErrNoProviderSupport)
+	}
+	deployKeyInfoToAPIObj(&info, &dk.k)
+	return nil
+}
+
+func (dk *deployKey) APIObject() interface{} {
+	return &dk.k
+}
+
+func (dk *deployKey) Repository() gitprovider.RepositoryRef {
+	return dk.c.ref
+}
+
+// Update will apply the desired state in this object to the server.
+// Only set fields will be respected (i.e. PATCH behaviour).
+// In order to apply changes to this object, use the .Set({Resource}Info) error
+// function, or cast .APIObject() to a pointer to the provider-specific type
+// and set custom fields there.
+//
+// ErrNotFound is returned if the resource does not exist.
+//
+// The internal API object will be overridden with the received server data.
+func (dk *deployKey) Update(ctx context.Context) error {
+	// Delete the old key and recreate
+	if err := dk.Delete(ctx); err != nil {
+		return err
+	}
+	return dk.createIntoSelf(ctx)
+}
+
+// Delete deletes the SSH public key of the user.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (dk *deployKey) Delete(ctx context.Context) error {
+	// We can use the same authorization ID that we got from the GET calls. Make sure it's set.
+	// This _should never_ happen, but just check for it anyways to avoid deleting the wrong thing.
+	if len(dk.k.AuthorizationID) == 0 {
+		return fmt.Errorf("didn't expect AuthorizationID to be unset: %w", gitprovider.ErrUnexpectedEvent)
+	}
+
+	// DELETE /{organization}/_apis/token/sessiontokens/{authorizationId}
+	return dk.c.c.DeleteSSHKey(ctx, dk.c.ref.Organization, dk.k.AuthorizationID)
+}
+
+// Reconcile makes sure the desired state in this object (called "req" here) becomes
+// the actual state in the backing Git provider.
+//
+// If req doesn't exist under the hood, it is created (actionTaken == true).
+// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+// If req is already the actual state, this is a no-op (actionTaken == false).
+//
+// The internal API object will be overridden with the received server data if actionTaken == true.
+func (dk *deployKey) Reconcile(ctx context.Context) (bool, error) {
+	actual, err := dk.c.get(ctx, dk.k.DisplayName)
+	if err != nil {
+		// Create if not found
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			return true, dk.createIntoSelf(ctx)
+		}
+
+		// Unexpected path, Get should succeed or return NotFound
+		return false, err
+	}
+
+	// Use wrappers here to extract the "spec" part of the object for comparison
+	desiredSpec := newAzureDevOpsKeySpec(&dk.k)
+	actualSpec := newAzureDevOpsKeySpec(&actual.k)
+
+	// If the desired matches the actual state, do nothing
+	if desiredSpec.Equals(actualSpec) {
+		return false, nil
+	}
+	// If desired and actual state mis-match, update
+	return true, dk.Update(ctx)
+}
+
+func (dk *deployKey) createIntoSelf(ctx context.Context) error {
+	// POST /{organization}/_apis/token/sessiontokens
+	apiObj, err := dk.c.c.CreateSSHKey(ctx, dk.c.ref.Organization, newAzureDevOpsKeySpec(&dk.k).SessionToken)
+	if err != nil {
+		return err
+	}
+	dk.k = *apiObj
+	return nil
+}
+
+func validateSSHKeyAPI(apiObj *SessionToken) error {
+	return validateAPIObject("AzureDevOps.SessionToken", func(validator validation.Validator) {
+		// Make sure the authorization ID, display name and public key are populated
+		if len(apiObj.AuthorizationID) == 0 {
+			validator.Required("AuthorizationID")
+		}
+		if len(apiObj.DisplayName) == 0 {
+			validator.Required("DisplayName")
+		}
+		if len(apiObj.PublicData) == 0 {
+			validator.Required("PublicData")
+		}
+	})
+}
+
+func deployKeyFromAPI(apiObj *SessionToken) gitprovider.DeployKeyInfo {
+	// SSH public keys grant the permissions of the user
+	return gitprovider.DeployKeyInfo{
+		Name:     apiObj.DisplayName,
+		Key:      []byte(apiObj.PublicData),
+		ReadOnly: gitprovider.BoolVar(false),
+	}
+}
+
+func deployKeyToAPI(info *gitprovider.DeployKeyInfo) *SessionToken {
+	k := &SessionToken{}
+	deployKeyInfoToAPIObj(info, k)
+	return k
+}
+
+func deployKeyInfoToAPIObj(info *gitprovider.DeployKeyInfo, apiObj *SessionToken) {
+	// Required fields, we assume info is validated, and hence these are set
+	apiObj.DisplayName = info.Name
+	apiObj.PublicData = strings.TrimSpace(string(info.Key))
+	apiObj.IsPublic = true
+}
+
+// This function copies over the fields that are part of create request of an SSH public key
+// i.e. the desired spec of the key. This allows us to separate "spec" from "status" fields.
+func newAzureDevOpsKeySpec(key *SessionToken) *azureDevOpsKeySpec {
+	return &azureDevOpsKeySpec{
+		&SessionToken{
+			DisplayName: key.DisplayName,
+			PublicData:  key.PublicData,
+			IsPublic:    true,
+		},
+	}
+}
+
+type azureDevOpsKeySpec struct {
+	*SessionToken
+}
+
+func (s *azureDevOpsKeySpec) Equals(other *azureDevOpsKeySpec) bool {
+	return reflect.DeepEqual(s, other)
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuredevops
+
+import (
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+// newOrganization returns an organization, which is a project if apiObj is non-nil,
+// and a top-level organization otherwise.
+func newOrganization(ctx *clientContext, apiObj *Project, ref gitprovider.OrganizationRef) *organization {
+	return &organization{
+		clientContext: ctx,
+		p:             apiObj,
+		ref:           ref,
+		teams: &TeamsClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		webhooks: &WebhookClient{
+			clientContext: ctx,
+		},
+	}
+}
+
+var _ gitprovider.Organization = &organization{}
+
+type organization struct {
+	*clientContext
+
+	// p is nil for top-level organizations
+	p   *Project
+	ref gitprovider.OrganizationRef
+
+	teams    *TeamsClient
+	webhooks *WebhookClient
+}
+
+func (o *organization) Get() gitprovider.OrganizationInfo {
+	if o.p == nil {
+		return gitprovider.OrganizationInfo{
+			Name: gitprovider.StringVar(o.ref.Organization),
+		}
+	}
+	return organizationFromAPI(o.p)
+}
+
+// APIObject returns the underlying *Project, which is nil for top-level organizations.
+func (o *organization) APIObject() interface{} {
+	return o.p
+}
+
+func (o *organization) Organization() gitprovider.OrganizationRef {
+	return o.ref
+}
+
+func (o *organization) Teams() gitprovider.TeamsClient {
+	return o.teams
+}
+
+func (o *organization) Webhooks() gitprovider.WebhookClient {
+	return o.webhooks
+}
+
+func organizationFromAPI(apiObj *Project) gitprovider.OrganizationInfo {
+	return gitprovider.OrganizationInfo{
+		Name:        gitprovider.StringVar(apiObj.Name),
+		Description: gitprovider.StringVar(apiObj.Description),
+	}
+}
+
+// validateProjectAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateProjectAPI(apiObj *Project) error {
+	return validateAPIObject("AzureDevOps.Project", func(validator validation.Validator) {
+		if len(apiObj.ID) == 0 {
+			validator.Required("ID")
+		}
+		if len(apiObj.Name) == 0 {
+			validator.Required("Name")
+		}
+	})
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuredevops
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+func newPullRequest(ctx *clientContext, apiObj *PullRequest, ref gitprovider.OrgRepositoryRef) *pullrequest {
+	return &pullrequest{
+		clientContext: ctx,
+		pr:            *apiObj,
+		ref:           ref,
+		comments: &PullRequestCommentClient{
+			clientContext: ctx,
+			ref:           ref,
+			id:            int(apiObj.PullRequestID),
+		},
+		reviews: &PullRequestReviewClient{
+			clientContext: ctx,
+			ref:           ref,
+			id:            int(apiObj.PullRequestID),
+		},
+	}
+}
+
+var _ gitprovider.PullRequest = &pullrequest{}
+
+type pullrequest struct {
+	*clientContext
+
+	pr  PullRequest
+	ref gitprovider.OrgRepositoryRef
+
+	comments *PullRequestCommentClient
+	reviews  *PullRequestReviewClient
+}
+
+func (pr *pullrequest) Get() gitprovider.PullRequestInfo {
+	return pullrequestFromAPI(&pr.pr, pr.ref)
+}
+
+func (pr *pullrequest) APIObject() interface{} {
+	return &pr.pr
+}
+
+func (pr *pullrequest) Comments() gitprovider.PullRequestCommentClient {
+	return pr.comments
+}
+
+func (pr *pullrequest) Reviews() gitprovider.PullRequestReviewClient {
+	return pr.reviews
+}
+
+func pullrequestFromAPI(apiObj *PullRequest, ref gitprovider.OrgRepositoryRef) gitprovider.PullRequestInfo {
+	// The API only returns the API URL of the pull request, build the web URL from the
+	// web URL of the repository instead
+	repoURL := ""
+	if apiObj.Repository != nil {
+		repoURL = apiObj.Repository.WebURL
+	}
+	if len(repoURL) == 0 {
+		repoURL = fmt.Sprintf("%s/%s/%s/_git/%s", strings.TrimSuffix(gitprovider.GetDomainURL(ref.Domain), "/"),
+			url.PathEscape(ref.Organization), url.PathEscape(projectName(ref.OrganizationRef)), url.PathEscape(ref.RepositoryName))
+	}
+	info := gitprovider.PullRequestInfo{
+		Number:      int(apiObj.PullRequestID),
+		Title:       apiObj.Title,
+		Description: apiObj.Description,
+		HeadBranch:  strings.TrimPrefix(apiObj.SourceRefName, "refs/heads/"),
+		BaseBranch:  strings.TrimPrefix(apiObj.TargetRefName, "refs/heads/"),
+		State:       pullRequestStateFromAPI(apiObj.Status),
+		Draft:       apiObj.IsDraft,
+		Mergeable:   mergeableFromAPI(apiObj.MergeStatus),
+		ClosedAt:    apiObj.ClosedDate,
+		WebURL:      fmt.Sprintf("%s/pullrequest/%d", repoURL, apiObj.PullRequestID),
+	}
+	if apiObj.CreatedBy != nil {
+		info.Author = apiObj.CreatedBy.UniqueName
+	}
+	if apiObj.CreationDate != nil {
+		info.CreatedAt = *apiObj.CreationDate
+	}
+	if info.State == gitprovider.PullRequestStateMerged {
+		info.MergedAt = apiObj.ClosedDate
+	}
+	return info
+}
+
+// pullRequestStateFromAPI maps the statuses "active", "completed" and "abandoned" to a
+// PullRequestState.
+func pullRequestStateFromAPI(status string) gitprovider.PullRequestState {
+	switch status {
+	case pullRequestStatusCompleted:
+		return gitprovider.PullRequestStateMerged
+	case pullRequestStatusAbandoned:
+		return gitprovider.PullRequestStateClosed
+	default:
+		return gitprovider.PullRequestStateOpen
+	}
+}
+
+// mergeableFromAPI returns nil if the server hasn't tried to merge the pull request yet.
+func mergeableFromAPI(mergeStatus string) *bool {
+	switch mergeStatus {
+	case "succeeded":
+		return gitprovider.BoolVar(true)
+	case "conflicts", "failure", "rejectedByPolicy":
+		return gitprovider.BoolVar(false)
+	default:
+		return nil
+	}
+}
+
+// validatePullRequestAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validatePullRequestAPI(apiObj *PullRequest) error {
+	return validateAPIObject("AzureDevOps.PullRequest", func(validator validation.Validator) {
+		if apiObj.PullRequestID == 0 {
+			validator.Required("PullRequestID")
+		}
+		if apiObj.Status == "" {
+			validator.Required("Status")
+		}
+	})
+}
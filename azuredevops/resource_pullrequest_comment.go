@@ -0,0 +1,76 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuredevops
+
+import (
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+func newPullRequestComment(threadID int64, apiObj *Comment) *pullRequestComment {
+	return &pullRequestComment{
+		threadID: threadID,
+		c:        *apiObj,
+	}
+}
+
+var _ gitprovider.PullRequestComment = &pullRequestComment{}
+
+type pullRequestComment struct {
+	// threadID is the ID of the thread started by the comment, which is the ID of the comment
+	threadID int64
+	c        Comment
+}
+
+func (c *pullRequestComment) Get() gitprovider.PullRequestCommentInfo {
+	return pullRequestCommentFromAPI(c.threadID, &c.c)
+}
+
+func (c *pullRequestComment) APIObject() interface{} {
+	return &c.c
+}
+
+func pullRequestCommentFromAPI(threadID int64, apiObj *Comment) gitprovider.PullRequestCommentInfo {
+	info := gitprovider.PullRequestCommentInfo{
+		ID:   threadID,
+		Body: apiObj.Content,
+	}
+	if apiObj.Author != nil {
+		info.Author = apiObj.Author.UniqueName
+	}
+	if apiObj.PublishedDate != nil {
+		info.CreatedAt = *apiObj.PublishedDate
+	}
+	if apiObj.LastUpdatedDate != nil {
+		info.UpdatedAt = *apiObj.LastUpdatedDate
+	}
+	return info
+}
+
+// validateCommentThreadAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateCommentThreadAPI(apiObj *CommentThread) error {
+	return validateAPIObject("AzureDevOps.CommentThread", func(validator validation.Validator) {
+		// Make sure the ID is populated, it's needed to update and delete the comment
+		if apiObj.ID == 0 {
+			validator.Required("ID")
+		}
+		if len(apiObj.Comments) == 0 {
+			validator.Required("Comments")
+		}
+	})
+}
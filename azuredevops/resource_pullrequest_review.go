@@ -0,0 +1,88 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuredevops
+
+import (
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+const (
+	voteApproved         = 10
+	voteNone             = 0
+	voteWaitingForAuthor = -5
+)
+
+// newVoteReview returns the review of the given reviewer, with the content of the comment added
+// along with it, if any. Positive votes are approvals, negative votes request changes.
+func newVoteReview(reviewer *IdentityRefWithVote, comment *Comment) *pullRequestReview {
+	info := gitprovider.PullRequestReviewInfo{
+		State:  gitprovider.PullRequestReviewStateApproved,
+		Author: reviewer.UniqueName,
+	}
+	if reviewer.Vote < voteNone {
+		info.State = gitprovider.PullRequestReviewStateChangesRequested
+	}
+	if comment != nil {
+		info.Body = comment.Content
+		info.SubmittedAt = comment.PublishedDate
+	}
+	return &pullRequestReview{info: info, apiObj: reviewer}
+}
+
+// newCommentReview returns a commenting review for the thread started by a comment.
+func newCommentReview(thread *CommentThread) *pullRequestReview {
+	comment := thread.Comments[0]
+	info := gitprovider.PullRequestReviewInfo{
+		ID:          thread.ID,
+		State:       gitprovider.PullRequestReviewStateCommented,
+		Body:        comment.Content,
+		SubmittedAt: comment.PublishedDate,
+	}
+	if comment.Author != nil {
+		info.Author = comment.Author.UniqueName
+	}
+	return &pullRequestReview{info: info, apiObj: thread}
+}
+
+var _ gitprovider.PullRequestReview = &pullRequestReview{}
+
+// pullRequestReview is a reviewer's vote or a comment thread, as Azure DevOps doesn't have reviews.
+type pullRequestReview struct {
+	info gitprovider.PullRequestReviewInfo
+	// apiObj is an *IdentityRefWithVote or a *CommentThread
+	apiObj interface{}
+}
+
+func (r *pullRequestReview) Get() gitprovider.PullRequestReviewInfo {
+	return r.info
+}
+
+func (r *pullRequestReview) APIObject() interface{} {
+	return r.apiObj
+}
+
+// validateConnectionDataAPI validates the apiObj received from the server, to make sure that it
+// is valid for our use.
+func validateConnectionDataAPI(apiObj *ConnectionData) error {
+	return validateAPIObject("AzureDevOps.ConnectionData", func(validator validation.Validator) {
+		// The ID of the authenticated user is needed to vote
+		if apiObj.AuthenticatedUser == nil || len(apiObj.AuthenticatedUser.ID) == 0 {
+			validator.Required("AuthenticatedUser.ID")
+		}
+	})
+}
@@ -0,0 +1,331 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuredevops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+const (
+	// projectVisibilityPrivate and projectVisibilityPublic are the visibilities of a project,
+	// which apply to all of its repositories.
+	projectVisibilityPrivate = "private"
+	projectVisibilityPublic  = "public"
+
+	// initialCommitMessage is the message of the commit adding a README to auto-initialized repositories.
+	initialCommitMessage = "Initial commit"
+)
+
+func newOrgRepository(ctx *clientContext, apiObj *Repository, ref gitprovider.OrgRepositoryRef) *orgRepository {
+	return &orgRepository{
+		clientContext: ctx,
+		r:             *apiObj,
+		ref:           ref,
+		deployKeys: &DeployKeyClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		commits: &CommitClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		branches: &BranchClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		files: &FileClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		branchProtections: &BranchProtectionClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		pullRequests: &PullRequestClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		tags: &TagClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		releases: &ReleaseClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		webhooks: &WebhookClient{
+			clientContext: ctx,
+		},
+		commitStatuses: &CommitStatusClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		issues: &IssueClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		teamAccess: &TeamAccessClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+	}
+}
+
+var _ gitprovider.OrgRepository = &orgRepository{}
+
+type orgRepository struct {
+	*clientContext
+
+	r   Repository
+	ref gitprovider.OrgRepositoryRef
+
+	deployKeys        *DeployKeyClient
+	commits           *CommitClient
+	branches          *BranchClient
+	files             *FileClient
+	branchProtections *BranchProtectionClient
+	pullRequests      *PullRequestClient
+	tags              *TagClient
+	releases          *ReleaseClient
+	webhooks          *WebhookClient
+	commitStatuses    *CommitStatusClient
+	issues            *IssueClient
+	teamAccess        *TeamAccessClient
+}
+
+func (r *orgRepository) Get() gitprovider.RepositoryInfo {
+	return repositoryFromAPI(&r.r)
+}
+
+func (r *orgRepository) Set(info gitprovider.RepositoryInfo) error {
+	if err := info.ValidateInfo(); err != nil {
+		return err
+	}
+	return repositoryInfoToAPIObj(&info, &r.r)
+}
+
+func (r *orgRepository) APIObject() interface{} {
+	return &r.r
+}
+
+func (r *orgRepository) Repository() gitprovider.RepositoryRef {
+	return r.ref
+}
+
+func (r *orgRepository) DeployKeys() gitprovider.DeployKeyClient {
+	return r.deployKeys
+}
+
+func (r *orgRepository) Commits() gitprovider.CommitClient {
+	return r.commits
+}
+
+func (r *orgRepository) Branches() gitprovider.BranchClient {
+	return r.branches
+}
+
+func (r *orgRepository) Files() gitprovider.FileClient {
+	return r.files
+}
+
+func (r *orgRepository) BranchProtections() gitprovider.BranchProtectionClient {
+	return r.branchProtections
+}
+
+func (r *orgRepository) PullRequests() gitprovider.PullRequestClient {
+	return r.pullRequests
+}
+
+func (r *orgRepository) Tags() gitprovider.TagClient {
+	return r.tags
+}
+
+func (r *orgRepository) Releases() gitprovider.ReleaseClient {
+	return r.releases
+}
+
+func (r *orgRepository) Webhooks() gitprovider.WebhookClient {
+	return r.webhooks
+}
+
+func (r *orgRepository) CommitStatuses() gitprovider.CommitStatusClient {
+	return r.commitStatuses
+}
+
+func (r *orgRepository) Issues() gitprovider.IssueClient {
+	return r.issues
+}
+
+func (r *orgRepository) TeamAccess() gitprovider.TeamAccessClient {
+	return r.teamAccess
+}
+
+// Update will apply the desired state in this object to the server.
+// Only set fields will be respected (i.e. PATCH behaviour).
+// In order to apply changes to this object, use the .Set({Resource}Info) error
+// function, or cast .APIObject() to a pointer to the provider-specific type
+// and set custom fields there.
+//
+// ErrNotFound is returned if the resource does not exist.
+//
+// The internal API object will be overridden with the received server data.
+func (r *orgRepository) Update(ctx context.Context) error {
+	// Only the name and default branch can be changed
+	req := &Repository{
+		Name:          r.r.Name,
+		DefaultBranch: r.r.DefaultBranch,
+	}
+	// PATCH /{organization}/{project}/_apis/git/repositories/{repositoryId}
+	apiObj, err := r.c.UpdateRepo(ctx, r.ref.Organization, projectName(r.ref.OrganizationRef), r.r.ID, req)
+	if err != nil {
+		return err
+	}
+	r.r = *apiObj
+	return nil
+}
+
+// Reconcile makes sure the desired state in this object (called "req" here) becomes
+// the actual state in the backing Git provider.
+//
+// If req doesn't exist under the hood, it is created (actionTaken == true).
+// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+// If req is already the actual state, this is a no-op (actionTaken == false).
+//
+// The internal API object will be overridden with the received server data if actionTaken == true.
+func (r *orgRepository) Reconcile(ctx context.Context) (bool, error) {
+	apiObj, err := r.c.GetRepo(ctx, r.ref.Organization, projectName(r.ref.OrganizationRef), r.ref.RepositoryName)
+	if err != nil {
+		// Create if not found
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			repo, err := createRepository(ctx, r.c, r.ref, r.Get())
+			if err != nil {
+				return true, err
+			}
+			r.r = *repo
+			return true, nil
+		}
+
+		return false, err
+	}
+
+	// Use wrappers here to extract the "spec" part of the object for comparison
+	desiredSpec := newAzureDevOpsRepositorySpec(&r.r)
+	actualSpec := newAzureDevOpsRepositorySpec(apiObj)
+
+	// If desired state already is the actual state, do nothing
+	if desiredSpec.Equals(actualSpec) {
+		return false, nil
+	}
+	// The ID is needed for updating
+	r.r.ID = apiObj.ID
+	// Otherwise, make the desired state the actual state
+	return true, r.Update(ctx)
+}
+
+// Delete deletes the current resource irreversibly.
+//
+// ErrNotFound is returned if the resource doesn't exist anymore.
+func (r *orgRepository) Delete(ctx context.Context) error {
+	// DELETE /{organization}/{project}/_apis/git/repositories/{repositoryId}
+	return r.c.DeleteRepo(ctx, r.ref.Organization, projectName(r.ref.OrganizationRef), r.r.ID)
+}
+
+// validateRepositoryAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateRepositoryAPI(apiObj *Repository) error {
+	return validateAPIObject("AzureDevOps.Repository", func(validator validation.Validator) {
+		// Make sure the ID and name are set
+		if len(apiObj.ID) == 0 {
+			validator.Required("ID")
+		}
+		if len(apiObj.Name) == 0 {
+			validator.Required("Name")
+		}
+		// Make sure the project is set, as repositories are listed across projects
+		if apiObj.Project == nil || len(apiObj.Project.Name) == 0 {
+			validator.Required("Project.Name")
+		}
+	})
+}
+
+func repositoryFromAPI(apiObj *Repository) gitprovider.RepositoryInfo {
+	repo := gitprovider.RepositoryInfo{}
+	// Repositories without any branches have no default branch
+	if len(apiObj.DefaultBranch) != 0 {
+		repo.DefaultBranch = gitprovider.StringVar(strings.TrimPrefix(apiObj.DefaultBranch, "refs/heads/"))
+	}
+	// The visibility is a property of the project
+	if apiObj.Project != nil {
+		repo.Visibility = visibilityFromProject(apiObj.Project)
+	}
+	return repo
+}
+
+// visibilityFromProject returns the visibility of the repositories in project, or nil if it's unknown.
+func visibilityFromProject(project *Project) *gitprovider.RepositoryVisibility {
+	switch project.Visibility {
+	case projectVisibilityPrivate:
+		return gitprovider.RepositoryVisibilityVar(gitprovider.RepositoryVisibilityPrivate)
+	case projectVisibilityPublic:
+		return gitprovider.RepositoryVisibilityVar(gitprovider.RepositoryVisibilityPublic)
+	}
+	return nil
+}
+
+func repositoryInfoToAPIObj(repo *gitprovider.RepositoryInfo, apiObj *Repository) error {
+	if repo.Description != nil {
+		return fmt.Errorf("azure devops repositories don't have a description: %w", gitprovider.ErrNoProviderSupport)
+	}
+	if repo.DefaultBranch != nil {
+		apiObj.DefaultBranch = branchRef(*repo.DefaultBranch)
+	}
+	// The visibility can't be set per repository, so it must match the visibility of the project
+	if repo.Visibility != nil && apiObj.Project != nil {
+		if actual := visibilityFromProject(apiObj.Project); actual == nil || *actual != *repo.Visibility {
+			return fmt.Errorf("repositories in project %q can't be %s, as the visibility of a repository is the visibility of its project: %w",
+				apiObj.Project.Name, *repo.Visibility, gitprovider.ErrNoProviderSupport)
+		}
+	}
+	return nil
+}
+
+// This function copies over the fields that are part of create/update requests of a repository
+// i.e. the desired spec of the repository. This allows us to separate "spec" from "status" fields.
+// See also: https://learn.microsoft.com/en-us/rest/api/azure/devops/git/repositories/update
+func newAzureDevOpsRepositorySpec(repo *Repository) *azureDevOpsRepositorySpec {
+	return &azureDevOpsRepositorySpec{
+		&Repository{
+			Name:          repo.Name,
+			DefaultBranch: repo.DefaultBranch,
+		},
+	}
+}
+
+type azureDevOpsRepositorySpec struct {
+	*Repository
+}
+
+func (s *azureDevOpsRepositorySpec) Equals(other *azureDevOpsRepositorySpec) bool {
+	return reflect.DeepEqual(s, other)
+}
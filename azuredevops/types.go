@@ -0,0 +1,296 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuredevops
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Project represents an Azure DevOps project (a TeamProjectReference).
+type Project struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	URL         string `json:"url,omitempty"`
+	State       string `json:"state,omitempty"`
+	// Visibility is either "private" or "public".
+	Visibility string `json:"visibility,omitempty"`
+}
+
+// Team represents a team in an Azure DevOps project (a WebApiTeam).
+type Team struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	URL         string `json:"url,omitempty"`
+	ProjectName string `json:"projectName,omitempty"`
+	ProjectID   string `json:"projectId,omitempty"`
+}
+
+// IdentityRef is a reference to a user or group.
+type IdentityRef struct {
+	ID          string `json:"id,omitempty"`
+	DisplayName string `json:"displayName,omitempty"`
+	// UniqueName is the login of the user, usually the email address.
+	UniqueName string `json:"uniqueName,omitempty"`
+}
+
+// TeamMember is a member of a team.
+type TeamMember struct {
+	Identity    *IdentityRef `json:"identity"`
+	IsTeamAdmin bool         `json:"isTeamAdmin,omitempty"`
+}
+
+// Repository represents an Azure DevOps Git repository (a GitRepository).
+type Repository struct {
+	ID      string   `json:"id,omitempty"`
+	Name    string   `json:"name,omitempty"`
+	URL     string   `json:"url,omitempty"`
+	Project *Project `json:"project,omitempty"`
+	// DefaultBranch is the full name of the default branch, e.g. "refs/heads/main".
+	// It is empty for repositories without any branches.
+	DefaultBranch string `json:"defaultBranch,omitempty"`
+	Size          int64  `json:"size,omitempty"`
+	RemoteURL     string `json:"remoteUrl,omitempty"`
+	SSHURL        string `json:"sshUrl,omitempty"`
+	WebURL        string `json:"webUrl,omitempty"`
+	IsDisabled    bool   `json:"isDisabled,omitempty"`
+}
+
+// CreateRepositoryOptions is the request body for creating a repository.
+type CreateRepositoryOptions struct {
+	Name string `json:"name"`
+	// Project only needs to have the ID set.
+	Project *Project `json:"project"`
+}
+
+// SessionToken represents a personal access token, or, if IsPublic is set, an SSH public key
+// of the authenticated user.
+type SessionToken struct {
+	AuthorizationID string   `json:"authorizationId,omitempty"`
+	DisplayName     string   `json:"displayName"`
+	Scope           string   `json:"scope,omitempty"`
+	TargetAccounts  []string `json:"targetAccounts,omitempty"`
+	ValidFrom       string   `json:"validFrom,omitempty"`
+	ValidTo         string   `json:"validTo,omitempty"`
+	IsValid         bool     `json:"isValid,omitempty"`
+	IsPublic        bool     `json:"isPublic"`
+	// PublicData is the public SSH key.
+	PublicData string `json:"publicData,omitempty"`
+}
+
+// GitRef is a reference, e.g. a branch, in a repository.
+type GitRef struct {
+	// Name is the full name of the ref, e.g. "refs/heads/main".
+	Name     string `json:"name"`
+	ObjectID string `json:"objectId"`
+	// PeeledObjectID is the commit an annotated tag points to, if requested using peelTags.
+	PeeledObjectID string `json:"peeledObjectId,omitempty"`
+	// IsLocked is true if the branch is locked, i.e. read-only.
+	IsLocked bool `json:"isLocked,omitempty"`
+}
+
+// GitRefUpdate is a request to move a ref from OldObjectID to NewObjectID.
+// OldObjectID is all zeroes (see zeroObjectID) for new refs.
+type GitRefUpdate struct {
+	Name        string `json:"name"`
+	OldObjectID string `json:"oldObjectId"`
+	NewObjectID string `json:"newObjectId,omitempty"`
+}
+
+// GitRefUpdateResult is the result of a GitRefUpdate.
+type GitRefUpdateResult struct {
+	Name          string `json:"name"`
+	OldObjectID   string `json:"oldObjectId,omitempty"`
+	NewObjectID   string `json:"newObjectId,omitempty"`
+	Success       bool   `json:"success"`
+	UpdateStatus  string `json:"updateStatus,omitempty"`
+	CustomMessage string `json:"customMessage,omitempty"`
+}
+
+// GitUserDate is the author or committer of a commit.
+type GitUserDate struct {
+	Name  string `json:"name,omitempty"`
+	Email string `json:"email,omitempty"`
+	Date  string `json:"date,omitempty"`
+}
+
+// Commit represents a Git commit (a GitCommitRef). When part of a push request,
+// Changes contains the changes of the commit.
+type Commit struct {
+	CommitID  string       `json:"commitId,omitempty"`
+	Author    *GitUserDate `json:"author,omitempty"`
+	Committer *GitUserDate `json:"committer,omitempty"`
+	Comment   string       `json:"comment,omitempty"`
+	Parents   []string     `json:"parents,omitempty"`
+	// TreeID is only returned when getting a single commit.
+	TreeID    string    `json:"treeId,omitempty"`
+	URL       string    `json:"url,omitempty"`
+	RemoteURL string    `json:"remoteUrl,omitempty"`
+	Changes   []*Change `json:"changes,omitempty"`
+}
+
+const (
+	// changeTypeAdd adds a new file.
+	changeTypeAdd = "add"
+	// changeTypeEdit changes an existing file.
+	changeTypeEdit = "edit"
+	// changeTypeDelete deletes an existing file.
+	changeTypeDelete = "delete"
+	// changeTypeRename moves an existing file, from the SourceServerItem of the change.
+	changeTypeRename = "rename"
+
+	// contentTypeBase64 is the content type of base64-encoded file contents.
+	contentTypeBase64 = "base64encoded"
+)
+
+// Change is a change to a single file, part of a pushed commit.
+type Change struct {
+	ChangeType string       `json:"changeType"`
+	Item       *Item        `json:"item"`
+	NewContent *ItemContent `json:"newContent,omitempty"`
+	// SourceServerItem is the previous path of a renamed file.
+	SourceServerItem string `json:"sourceServerItem,omitempty"`
+}
+
+// Item is a file or directory in a repository.
+type Item struct {
+	Path     string `json:"path"`
+	ObjectID string `json:"objectId,omitempty"`
+	IsFolder bool   `json:"isFolder,omitempty"`
+}
+
+// ItemContent is the content of a file, in a push request.
+type ItemContent struct {
+	Content     string `json:"content"`
+	ContentType string `json:"contentType"`
+}
+
+// Push represents a push of one or more commits (a GitPush).
+type Push struct {
+	PushID     int64           `json:"pushId,omitempty"`
+	RefUpdates []*GitRefUpdate `json:"refUpdates"`
+	Commits    []*Commit       `json:"commits"`
+}
+
+// PullRequest represents an Azure DevOps pull request (a GitPullRequest).
+type PullRequest struct {
+	PullRequestID int64  `json:"pullRequestId,omitempty"`
+	Status        string `json:"status,omitempty"`
+	Title         string `json:"title"`
+	Description   string `json:"description,omitempty"`
+	// SourceRefName and TargetRefName are full ref names, e.g. "refs/heads/main".
+	SourceRefName string       `json:"sourceRefName"`
+	TargetRefName string       `json:"targetRefName"`
+	IsDraft       bool         `json:"isDraft,omitempty"`
+	MergeStatus   string       `json:"mergeStatus,omitempty"`
+	CreatedBy     *IdentityRef `json:"createdBy,omitempty"`
+	CreationDate  *time.Time   `json:"creationDate,omitempty"`
+	ClosedDate    *time.Time   `json:"closedDate,omitempty"`
+	// LastMergeSourceCommit is the head of the source branch, which must be given to complete
+	// the pull request.
+	LastMergeSourceCommit *Commit     `json:"lastMergeSourceCommit,omitempty"`
+	URL                   string      `json:"url,omitempty"`
+	Repository            *Repository `json:"repository,omitempty"`
+	// Reviewers are the reviewers of the pull request, with their votes
+	Reviewers []*IdentityRefWithVote `json:"reviewers,omitempty"`
+}
+
+// IdentityRefWithVote is a reviewer of a pull request. Vote is 10 for approved, 5 for approved
+// with suggestions, 0 for no vote, -5 for waiting for the author and -10 for rejected.
+type IdentityRefWithVote struct {
+	ID          string `json:"id,omitempty"`
+	DisplayName string `json:"displayName,omitempty"`
+	UniqueName  string `json:"uniqueName,omitempty"`
+	Vote        int    `json:"vote"`
+}
+
+// CommentThread is a thread of comments on a pull request. Threads on a file of the pull
+// request have a ThreadContext.
+type CommentThread struct {
+	ID              int64           `json:"id,omitempty"`
+	Comments        []*Comment      `json:"comments"`
+	Status          string          `json:"status,omitempty"`
+	ThreadContext   json.RawMessage `json:"threadContext,omitempty"`
+	IsDeleted       bool            `json:"isDeleted,omitempty"`
+	PublishedDate   *time.Time      `json:"publishedDate,omitempty"`
+	LastUpdatedDate *time.Time      `json:"lastUpdatedDate,omitempty"`
+}
+
+// Comment is a comment in a thread. CommentType is "text" for comments of users, and "system"
+// for comments generated by the server.
+type Comment struct {
+	ID              int64        `json:"id,omitempty"`
+	ParentCommentID int64        `json:"parentCommentId,omitempty"`
+	Content         string       `json:"content"`
+	CommentType     string       `json:"commentType,omitempty"`
+	Author          *IdentityRef `json:"author,omitempty"`
+	IsDeleted       bool         `json:"isDeleted,omitempty"`
+	PublishedDate   *time.Time   `json:"publishedDate,omitempty"`
+	LastUpdatedDate *time.Time   `json:"lastUpdatedDate,omitempty"`
+}
+
+// ConnectionData describes the connection to an organization, e.g. the authenticated user.
+type ConnectionData struct {
+	AuthenticatedUser *Identity `json:"authenticatedUser,omitempty"`
+}
+
+// Identity is a user or group known to the server.
+type Identity struct {
+	ID                  string `json:"id,omitempty"`
+	ProviderDisplayName string `json:"providerDisplayName,omitempty"`
+}
+
+// PullRequestUpdate is the request body for updating a pull request. Status is "active",
+// "abandoned" or "completed".
+type PullRequestUpdate struct {
+	Title                 *string            `json:"title,omitempty"`
+	Description           *string            `json:"description,omitempty"`
+	TargetRefName         *string            `json:"targetRefName,omitempty"`
+	Status                *string            `json:"status,omitempty"`
+	LastMergeSourceCommit *Commit            `json:"lastMergeSourceCommit,omitempty"`
+	CompletionOptions     *CompletionOptions `json:"completionOptions,omitempty"`
+}
+
+// CompletionOptions specifies how a pull request is merged when it's completed. MergeStrategy
+// is "noFastForward", "squash", "rebase" or "rebaseMerge".
+type CompletionOptions struct {
+	MergeStrategy      string `json:"mergeStrategy,omitempty"`
+	MergeCommitMessage string `json:"mergeCommitMessage,omitempty"`
+}
+
+// ErrorResponse is the error returned from the server when a request fails.
+type ErrorResponse struct {
+	// Response is the HTTP response that caused this error.
+	Response *http.Response `json:"-"`
+	// Message describes what went wrong, and usually starts with a TF error code.
+	Message string `json:"message"`
+	// TypeKey is the short name of the server-side exception, e.g. "GitRepositoryNotFoundException".
+	TypeKey string `json:"typeKey,omitempty"`
+}
+
+// Error implements the error interface.
+func (e *ErrorResponse) Error() string {
+	msg := fmt.Sprintf("%s %s: %d", e.Response.Request.Method, e.Response.Request.URL, e.Response.StatusCode)
+	if len(e.Message) != 0 {
+		msg = fmt.Sprintf("%s %s", msg, e.Message)
+	}
+	return msg
+}
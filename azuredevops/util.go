@@ -0,0 +1,158 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuredevops
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+const (
+	alreadyExistsMagicString = "already exists"
+	staleRefTypeKey          = "GitReferenceStaleException"
+	apiDocURL                = "https://learn.microsoft.com/en-us/rest/api/azure/devops/"
+)
+
+// validateOrgRepositoryRef makes sure the OrgRepositoryRef is valid for Azure DevOps' usage.
+// Repositories always belong to a project, hence exactly one sub-organization is required.
+func validateOrgRepositoryRef(ref gitprovider.OrgRepositoryRef, expectedDomain string) error {
+	// Make sure the RepositoryRef fields are valid
+	if err := validation.ValidateTargets("OrgRepositoryRef", ref); err != nil {
+		return err
+	}
+	// Make sure the type is valid, and domain is expected
+	if err := validateIdentityFields(ref, expectedDomain); err != nil {
+		return err
+	}
+	if len(ref.SubOrganizations) != 1 {
+		return fmt.Errorf("repositories must be in a project, given as the only sub-organization: %w", gitprovider.ErrInvalidArgument)
+	}
+	return nil
+}
+
+// validateOrganizationRef makes sure the OrganizationRef is valid for Azure DevOps' usage.
+func validateOrganizationRef(ref gitprovider.OrganizationRef, expectedDomain string) error {
+	// Make sure the OrganizationRef fields are valid
+	if err := validation.ValidateTargets("OrganizationRef", ref); err != nil {
+		return err
+	}
+	// Make sure the type is valid, and domain is expected
+	return validateIdentityFields(ref, expectedDomain)
+}
+
+// validateIdentityFields makes sure the type of the IdentityRef is supported, and the domain is as expected.
+func validateIdentityFields(ref gitprovider.IdentityRef, expectedDomain string) error {
+	// Make sure the expected domain is used
+	if ref.GetDomain() != expectedDomain {
+		return fmt.Errorf("domain %q not supported by this client: %w", ref.GetDomain(), gitprovider.ErrDomainUnsupported)
+	}
+	// Make sure the right type of identityref is used
+	switch ref.GetType() {
+	case gitprovider.IdentityTypeOrganization:
+		return nil
+	case gitprovider.IdentityTypeSuborganization:
+		// Projects can't be nested
+		if len(strings.Split(ref.GetIdentity(), "/")) > 2 {
+			return fmt.Errorf("azure devops projects can't be nested: %w", gitprovider.ErrNoProviderSupport)
+		}
+		return nil
+	case gitprovider.IdentityTypeUser:
+		return fmt.Errorf("azure devops doesn't support user repositories: %w", gitprovider.ErrNoProviderSupport)
+	}
+	return fmt.Errorf("invalid identity type: %v: %w", ref.GetType(), gitprovider.ErrInvalidArgument)
+}
+
+// projectName returns the name of the project of the given OrganizationRef,
+// or an empty string if it refers to a top-level organization.
+func projectName(ref gitprovider.OrganizationRef) string {
+	if len(ref.SubOrganizations) == 0 {
+		return ""
+	}
+	return ref.SubOrganizations[0]
+}
+
+// handleHTTPError checks the type of err, and returns typed variants of it
+// However, it _always_ keeps the original error too, and just wraps it in a MultiError
+// The consumer must use errors.Is and errors.As to check for equality and get data out of it.
+func handleHTTPError(err error) error {
+	// Short-circuit quickly if possible, allow always piping through this function
+	if err == nil {
+		return nil
+	}
+	azureErrorResponse := &ErrorResponse{}
+	if errors.As(err, &azureErrorResponse) {
+		httpErr := gitprovider.HTTPError{
+			Response:         azureErrorResponse.Response,
+			ErrorMessage:     azureErrorResponse.Error(),
+			Message:          azureErrorResponse.Message,
+			DocumentationURL: apiDocURL,
+		}
+		switch azureErrorResponse.Response.StatusCode {
+		// Check for invalid credentials, and return a typed error in that case.
+		// Instead of 401, the server redirects requests with missing or invalid credentials
+		// to its sign-in page, which is returned with status 203.
+		case http.StatusForbidden, http.StatusUnauthorized, http.StatusNonAuthoritativeInfo:
+			return validation.NewMultiError(err,
+				&gitprovider.InvalidCredentialsError{HTTPError: httpErr},
+			)
+		// Check for 404 Not Found
+		case http.StatusNotFound:
+			return validation.NewMultiError(err, gitprovider.ErrNotFound)
+		// Check for rate limiting, the server asks to back off using Retry-After
+		case http.StatusTooManyRequests:
+			return validation.NewMultiError(err, &gitprovider.RateLimitError{HTTPError: httpErr})
+		}
+		// Check for refs which moved since they were read, e.g. by a concurrent push
+		if azureErrorResponse.TypeKey == staleRefTypeKey {
+			return validation.NewMultiError(err, gitprovider.ErrConflict)
+		}
+		// Check for already exists errors
+		if isAlreadyExistsError(azureErrorResponse) {
+			return validation.NewMultiError(err, gitprovider.ErrAlreadyExists)
+		}
+		// Otherwise, return a generic *HTTPError
+		return validation.NewMultiError(err, &httpErr)
+	}
+	// Do nothing, just pipe through the unknown err
+	return err
+}
+
+// isAlreadyExistsError returns true if the error denotes a conflict with an existing resource,
+// e.g. "TF400948: A Git repository with the name foo already exists.".
+func isAlreadyExistsError(e *ErrorResponse) bool {
+	return strings.Contains(e.TypeKey, "AlreadyExists") ||
+		strings.Contains(strings.ToLower(e.Message), alreadyExistsMagicString)
+}
+
+// validateAPIObject creates a Validatior with the specified name, gives it to fn, and
+// depending on if any error was registered with it; either returns nil, or a MultiError
+// with both the validation error and ErrInvalidServerData, to mark that the server data
+// was invalid.
+func validateAPIObject(name string, fn func(validation.Validator)) error {
+	v := validation.New(name)
+	fn(v)
+	// If there was a validation error, also mark it specifically as invalid server data
+	if err := v.Error(); err != nil {
+		return validation.NewMultiError(err, gitprovider.ErrInvalidServerData)
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azuredevops
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+func Test_validateAPIObject(t *testing.T) {
+	tests := []struct {
+		name         string
+		structName   string
+		fn           func(validation.Validator)
+		expectedErrs []error
+	}{
+		{
+			name:       "no error => nil",
+			structName: "Foo",
+			fn:         func(validation.Validator) {},
+		},
+		{
+			name:       "one error => MultiError & InvalidServerData",
+			structName: "Foo",
+			fn: func(v validation.Validator) {
+				v.Required("FieldBar")
+			},
+			expectedErrs: []error{gitprovider.ErrInvalidServerData, &validation.MultiError{}, validation.ErrFieldRequired},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAPIObject(tt.structName, tt.fn)
+			validation.TestExpectErrors(t, "validateAPIObject", err, tt.expectedErrs...)
+		})
+	}
+}
+
+func newAzureDevOpsError(statusCode int, message, typeKey string) *ErrorResponse {
+	return &ErrorResponse{
+		Response: &http.Response{
+			Request: &http.Request{
+				Method: "GET",
+				URL:    &url.URL{},
+			},
+			StatusCode: statusCode,
+		},
+		Message: message,
+		TypeKey: typeKey,
+	}
+}
+
+func Test_handleHTTPError(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		expectedErrs []error
+	}{
+		{
+			name: "nil => nil",
+		},
+		{
+			name:         "unknown error => passthrough",
+			err:          gitprovider.ErrUnexpectedEvent,
+			expectedErrs: []error{gitprovider.ErrUnexpectedEvent},
+		},
+		{
+			name:         "401 => InvalidCredentialsError",
+			err:          newAzureDevOpsError(http.StatusUnauthorized, "", ""),
+			expectedErrs: []error{&validation.MultiError{}, &gitprovider.InvalidCredentialsError{}, &ErrorResponse{}},
+		},
+		{
+			name:         "203 sign-in page => InvalidCredentialsError",
+			err:          newAzureDevOpsError(http.StatusNonAuthoritativeInfo, "", ""),
+			expectedErrs: []error{&validation.MultiError{}, &gitprovider.InvalidCredentialsError{}, &ErrorResponse{}},
+		},
+		{
+			name:         "404 => ErrNotFound",
+			err:          newAzureDevOpsError(http.StatusNotFound, "TF401019: The Git repository with name or identifier foo does not exist or you do not have permissions for the operation you are attempting.", "GitRepositoryNotFoundException"),
+			expectedErrs: []error{&validation.MultiError{}, gitprovider.ErrNotFound, &ErrorResponse{}},
+		},
+		{
+			name:         "429 => RateLimitError",
+			err:          newAzureDevOpsError(http.StatusTooManyRequests, "", ""),
+			expectedErrs: []error{&validation.MultiError{}, &gitprovider.RateLimitError{}, &ErrorResponse{}},
+		},
+		{
+			name:         "409 with already exists type => ErrAlreadyExists",
+			err:          newAzureDevOpsError(http.StatusConflict, "TF400948: A Git repository with the name foo already exists.", "GitRepositoryNameAlreadyExistsException"),
+			expectedErrs: []error{&validation.MultiError{}, gitprovider.ErrAlreadyExists, &ErrorResponse{}},
+		},
+		{
+			name:         "409 with stale ref type => ErrConflict",
+			err:          newAzureDevOpsError(http.StatusConflict, "TF402436: The ref refs/heads/main has been updated.", "GitReferenceStaleException"),
+			expectedErrs: []error{&validation.MultiError{}, gitprovider.ErrConflict, &ErrorResponse{}},
+		},
+		{
+			name:         "400 with already exists message => ErrAlreadyExists",
+			err:          newAzureDevOpsError(http.StatusBadRequest, "An SSH key with the same public data already exists.", ""),
+			expectedErrs: []error{&validation.MultiError{}, gitprovider.ErrAlreadyExists, &ErrorResponse{}},
+		},
+		{
+			name:         "400 otherwise => HTTPError",
+			err:          newAzureDevOpsError(http.StatusBadRequest, "TF400813: The user is not authorized to access this resource.", "InvalidArgumentValueException"),
+			expectedErrs: []error{&validation.MultiError{}, &gitprovider.HTTPError{}, &ErrorResponse{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := handleHTTPError(tt.err)
+			validation.TestExpectErrors(t, "handleHTTPError", err, tt.expectedErrs...)
+			if tt.err == nil {
+				return
+			}
+			// The original error must always be kept
+			if !errors.Is(err, tt.err) {
+				t.Errorf("handleHTTPError() = %v, expected to wrap %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func Test_validateOrgRepositoryRef(t *testing.T) {
+	tests := []struct {
+		name         string
+		ref          gitprovider.OrgRepositoryRef
+		expectedErrs []error
+	}{
+		{
+			name: "project",
+			ref:  newOrgRepositoryRef("dev.azure.com", "org", []string{"project"}, "repo"),
+		},
+		{
+			name:         "no project",
+			ref:          newOrgRepositoryRef("dev.azure.com", "org", nil, "repo"),
+			expectedErrs: []error{gitprovider.ErrInvalidArgument},
+		},
+		{
+			name:         "nested projects",
+			ref:          newOrgRepositoryRef("dev.azure.com", "org", []string{"project", "sub"}, "repo"),
+			expectedErrs: []error{gitprovider.ErrNoProviderSupport},
+		},
+		{
+			name:         "other domain",
+			ref:          newOrgRepositoryRef("example.com", "org", []string{"project"}, "repo"),
+			expectedErrs: []error{gitprovider.ErrDomainUnsupported},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateOrgRepositoryRef(tt.ref, "dev.azure.com")
+			validation.TestExpectErrors(t, "validateOrgRepositoryRef", err, tt.expectedErrs...)
+		})
+	}
+}
+
+func newOrgRepositoryRef(domain, org string, projects []string, repo string) gitprovider.OrgRepositoryRef {
+	return gitprovider.OrgRepositoryRef{
+		OrganizationRef: gitprovider.OrganizationRef{Domain: domain, Organization: org, SubOrganizations: projects},
+		RepositoryName:  repo,
+	}
+}
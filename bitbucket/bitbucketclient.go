@@ -0,0 +1,732 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitbucket
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+// bitbucketClient is a wrapper around the Bitbucket Cloud REST API, which implements higher-level
+// methods, operating on the structs in types.go. Pagination is implemented for all List* methods,
+// all returned objects are validated, and HTTP errors are handled/wrapped using handleHTTPError.
+// This interface is also fakeable, in order to unit-test the client.
+type bitbucketClient interface {
+	// Client returns the underlying *http.Client
+	Client() *http.Client
+
+	// GetTokenScopes is a wrapper for "GET /user", returning the comma-separated
+	// X-OAuth-Scopes header of the response.
+	// This function handles HTTP error wrapping.
+	GetTokenScopes(ctx context.Context) (string, error)
+
+	// GetWorkspace is a wrapper for "GET /workspaces/{workspace}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetWorkspace(ctx context.Context, workspace string) (*Workspace, error)
+	// ListWorkspaces is a wrapper for "GET /workspaces".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListWorkspaces(ctx context.Context) ([]*Workspace, error)
+
+	// GetRepo is a wrapper for "GET /repositories/{workspace}/{repo_slug}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetRepo(ctx context.Context, workspace, repo string) (*Repository, error)
+	// ListRepos is a wrapper for "GET /repositories/{workspace}".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListRepos(ctx context.Context, workspace string) ([]*Repository, error)
+	// CreateRepo is a wrapper for "POST /repositories/{workspace}/{repo_slug}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateRepo(ctx context.Context, workspace, repo string, req *Repository) (*Repository, error)
+	// UpdateRepo is a wrapper for "PUT /repositories/{workspace}/{repo_slug}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	UpdateRepo(ctx context.Context, workspace, repo string, req *Repository) (*Repository, error)
+	// DeleteRepo is a wrapper for "DELETE /repositories/{workspace}/{repo_slug}".
+	// This function handles HTTP error wrapping.
+	// DANGEROUS COMMAND: In order to use this, you must set destructiveActions to true.
+	DeleteRepo(ctx context.Context, workspace, repo string) error
+
+	// ListKeys is a wrapper for "GET /repositories/{workspace}/{repo_slug}/deploy-keys".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListKeys(ctx context.Context, workspace, repo string) ([]*DeployKey, error)
+	// CreateKey is a wrapper for "POST /repositories/{workspace}/{repo_slug}/deploy-keys".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateKey(ctx context.Context, workspace, repo string, req *DeployKey) (*DeployKey, error)
+	// DeleteKey is a wrapper for "DELETE /repositories/{workspace}/{repo_slug}/deploy-keys/{key_id}".
+	// This function handles HTTP error wrapping.
+	DeleteKey(ctx context.Context, workspace, repo string, id int) error
+
+	// ListCommitsPage is a wrapper for "GET /repositories/{workspace}/{repo_slug}/commits/{branch}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	ListCommitsPage(ctx context.Context, workspace, repo, branch string, perPage int, page int) ([]*Commit, error)
+	// GetCommit is a wrapper for "GET /repositories/{workspace}/{repo_slug}/commit/{revision}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetCommit(ctx context.Context, workspace, repo, revision string) (*Commit, error)
+	// CreateCommit is a wrapper for "POST /repositories/{workspace}/{repo_slug}/src", with
+	// "GET /repositories/{workspace}/{repo_slug}/src/{commit}/{path}" for the content of moved files.
+	// If parent is set, it's the parent of the commit, and ErrConflict is returned if branch
+	// exists at another commit. If author is set, it's the author of the commit, in the
+	// "Name <email>" format.
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateCommit(ctx context.Context, workspace, repo, branch, parent, message, author string, files []gitprovider.CommitFile) (*Commit, error)
+	// GetFileContent is a wrapper for "GET /repositories/{workspace}/{repo_slug}/src/{commit}/{path}".
+	// revision can be a commit SHA, a branch or a tag name.
+	// This function handles HTTP error wrapping.
+	GetFileContent(ctx context.Context, workspace, repo, revision, path string) ([]byte, error)
+
+	// GetBranch is a wrapper for "GET /repositories/{workspace}/{repo_slug}/refs/branches/{name}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetBranch(ctx context.Context, workspace, repo, branch string) (*Branch, error)
+	// ListBranches is a wrapper for "GET /repositories/{workspace}/{repo_slug}/refs/branches".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListBranches(ctx context.Context, workspace, repo string) ([]*Branch, error)
+	// CreateBranch is a wrapper for "POST /repositories/{workspace}/{repo_slug}/refs/branches".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateBranch(ctx context.Context, workspace, repo, branch, sha string) (*Branch, error)
+	// DeleteBranch is a wrapper for "DELETE /repositories/{workspace}/{repo_slug}/refs/branches/{name}".
+	// This function handles HTTP error wrapping.
+	// DANGEROUS COMMAND: In order to use this, you must set destructiveActions to true.
+	DeleteBranch(ctx context.Context, workspace, repo, branch string) error
+
+	// CreatePullRequest is a wrapper for "POST /repositories/{workspace}/{repo_slug}/pullrequests".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreatePullRequest(ctx context.Context, workspace, repo string, req *PullRequest) (*PullRequest, error)
+	// GetPullRequest is a wrapper for "GET /repositories/{workspace}/{repo_slug}/pullrequests/{id}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetPullRequest(ctx context.Context, workspace, repo string, id int) (*PullRequest, error)
+	// ListPullRequests is a wrapper for "GET /repositories/{workspace}/{repo_slug}/pullrequests".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListPullRequests(ctx context.Context, workspace, repo string, query url.Values) ([]*PullRequest, error)
+	// UpdatePullRequest is a wrapper for "PUT /repositories/{workspace}/{repo_slug}/pullrequests/{id}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	UpdatePullRequest(ctx context.Context, workspace, repo string, id int, req *PullRequest) (*PullRequest, error)
+	// MergePullRequest is a wrapper for "POST /repositories/{workspace}/{repo_slug}/pullrequests/{id}/merge".
+	// This function handles HTTP error wrapping, and validates the server result.
+	MergePullRequest(ctx context.Context, workspace, repo string, id int, req *PullRequestMerge) (*PullRequest, error)
+	// DeclinePullRequest is a wrapper for "POST /repositories/{workspace}/{repo_slug}/pullrequests/{id}/decline".
+	// This function handles HTTP error wrapping, and validates the server result.
+	DeclinePullRequest(ctx context.Context, workspace, repo string, id int) (*PullRequest, error)
+
+	// ListPullRequestComments is a wrapper for "GET /repositories/{workspace}/{repo_slug}/pullrequests/{id}/comments".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListPullRequestComments(ctx context.Context, workspace, repo string, id int) ([]*Comment, error)
+	// CreatePullRequestComment is a wrapper for "POST /repositories/{workspace}/{repo_slug}/pullrequests/{id}/comments".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreatePullRequestComment(ctx context.Context, workspace, repo string, id int, body string) (*Comment, error)
+	// UpdatePullRequestComment is a wrapper for "PUT /repositories/{workspace}/{repo_slug}/pullrequests/{id}/comments/{comment_id}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	UpdatePullRequestComment(ctx context.Context, workspace, repo string, id, commentID int, body string) (*Comment, error)
+	// DeletePullRequestComment is a wrapper for "DELETE /repositories/{workspace}/{repo_slug}/pullrequests/{id}/comments/{comment_id}".
+	// This function handles HTTP error wrapping.
+	DeletePullRequestComment(ctx context.Context, workspace, repo string, id, commentID int) error
+	// ApprovePullRequest is a wrapper for "POST /repositories/{workspace}/{repo_slug}/pullrequests/{id}/approve".
+	// This function handles HTTP error wrapping.
+	ApprovePullRequest(ctx context.Context, workspace, repo string, id int) (*Participant, error)
+	// RequestPullRequestChanges is a wrapper for "POST /repositories/{workspace}/{repo_slug}/pullrequests/{id}/request-changes".
+	// This function handles HTTP error wrapping.
+	RequestPullRequestChanges(ctx context.Context, workspace, repo string, id int) (*Participant, error)
+}
+
+// bitbucketClientImpl is a wrapper around the Bitbucket Cloud REST API, which implements higher-level
+// methods. See the bitbucketClient interface for method documentation.
+// Pagination is implemented for all List* methods, all returned
+// objects are validated, and HTTP errors are handled/wrapped using handleHTTPError.
+type bitbucketClientImpl struct {
+	c                  *http.Client
+	baseURL            string
+	destructiveActions bool
+}
+
+// bitbucketClientImpl implements bitbucketClient.
+var _ bitbucketClient = &bitbucketClientImpl{}
+
+func (c *bitbucketClientImpl) Client() *http.Client {
+	return c.c
+}
+
+func (c *bitbucketClientImpl) GetTokenScopes(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url(nil, "user"), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/json")
+	// GET /user
+	resp, err := c.roundTrip(req, nil)
+	if err != nil {
+		return "", handleHTTPError(err)
+	}
+	return resp.Header.Get("X-OAuth-Scopes"), nil
+}
+
+func (c *bitbucketClientImpl) GetWorkspace(ctx context.Context, workspace string) (*Workspace, error) {
+	apiObj := &Workspace{}
+	// GET /workspaces/{workspace}
+	if err := c.do(ctx, http.MethodGet, c.url(nil, "workspaces", workspace), nil, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Validate the API object
+	if err := validateWorkspaceAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *bitbucketClientImpl) ListWorkspaces(ctx context.Context) ([]*Workspace, error) {
+	apiObjs := []*Workspace{}
+	// GET /workspaces
+	err := c.allPages(ctx, c.url(nil, "workspaces"), func(values json.RawMessage) error {
+		var pageObjs []*Workspace
+		err := json.Unmarshal(values, &pageObjs)
+		apiObjs = append(apiObjs, pageObjs...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate the API objects
+	for _, apiObj := range apiObjs {
+		if err := validateWorkspaceAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *bitbucketClientImpl) GetRepo(ctx context.Context, workspace, repo string) (*Repository, error) {
+	apiObj := &Repository{}
+	// GET /repositories/{workspace}/{repo_slug}
+	err := c.do(ctx, http.MethodGet, c.url(nil, "repositories", workspace, repo), nil, apiObj)
+	return validateRepositoryAPIResp(apiObj, err)
+}
+
+func validateRepositoryAPIResp(apiObj *Repository, err error) (*Repository, error) {
+	// If the response contained an error, return
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Make sure apiObj is valid
+	if err := validateRepositoryAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *bitbucketClientImpl) ListRepos(ctx context.Context, workspace string) ([]*Repository, error) {
+	apiObjs := []*Repository{}
+	// GET /repositories/{workspace}
+	err := c.allPages(ctx, c.url(nil, "repositories", workspace), func(values json.RawMessage) error {
+		var pageObjs []*Repository
+		err := json.Unmarshal(values, &pageObjs)
+		apiObjs = append(apiObjs, pageObjs...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, apiObj := range apiObjs {
+		// Make sure apiObj is valid
+		if err := validateRepositoryAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *bitbucketClientImpl) CreateRepo(ctx context.Context, workspace, repo string, req *Repository) (*Repository, error) {
+	apiObj := &Repository{}
+	// POST /repositories/{workspace}/{repo_slug}
+	err := c.do(ctx, http.MethodPost, c.url(nil, "repositories", workspace, repo), req, apiObj)
+	return validateRepositoryAPIResp(apiObj, err)
+}
+
+func (c *bitbucketClientImpl) UpdateRepo(ctx context.Context, workspace, repo string, req *Repository) (*Repository, error) {
+	apiObj := &Repository{}
+	// PUT /repositories/{workspace}/{repo_slug}
+	err := c.do(ctx, http.MethodPut, c.url(nil, "repositories", workspace, repo), req, apiObj)
+	return validateRepositoryAPIResp(apiObj, err)
+}
+
+func (c *bitbucketClientImpl) DeleteRepo(ctx context.Context, workspace, repo string) error {
+	// Don't allow deleting repositories if the user didn't explicitly allow dangerous API calls.
+	if !c.destructiveActions {
+		return fmt.Errorf("cannot delete repository: %w", gitprovider.ErrDestructiveCallDisallowed)
+	}
+	// DELETE /repositories/{workspace}/{repo_slug}
+	err := c.do(ctx, http.MethodDelete, c.url(nil, "repositories", workspace, repo), nil, nil)
+	return handleHTTPError(err)
+}
+
+func (c *bitbucketClientImpl) ListKeys(ctx context.Context, workspace, repo string) ([]*DeployKey, error) {
+	apiObjs := []*DeployKey{}
+	// GET /repositories/{workspace}/{repo_slug}/deploy-keys
+	err := c.allPages(ctx, c.url(nil, "repositories", workspace, repo, "deploy-keys"), func(values json.RawMessage) error {
+		var pageObjs []*DeployKey
+		err := json.Unmarshal(values, &pageObjs)
+		apiObjs = append(apiObjs, pageObjs...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, apiObj := range apiObjs {
+		if err := validateDeployKeyAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *bitbucketClientImpl) CreateKey(ctx context.Context, workspace, repo string, req *DeployKey) (*DeployKey, error) {
+	apiObj := &DeployKey{}
+	// POST /repositories/{workspace}/{repo_slug}/deploy-keys
+	if err := c.do(ctx, http.MethodPost, c.url(nil, "repositories", workspace, repo, "deploy-keys"), req, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validateDeployKeyAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *bitbucketClientImpl) DeleteKey(ctx context.Context, workspace, repo string, id int) error {
+	// DELETE /repositories/{workspace}/{repo_slug}/deploy-keys/{key_id}
+	err := c.do(ctx, http.MethodDelete, c.url(nil, "repositories", workspace, repo, "deploy-keys", strconv.Itoa(id)), nil, nil)
+	return handleHTTPError(err)
+}
+
+func (c *bitbucketClientImpl) ListCommitsPage(ctx context.Context, workspace, repo, branch string, perPage int, page int) ([]*Commit, error) {
+	query := url.Values{}
+	if perPage > 0 {
+		query.Set("pagelen", strconv.Itoa(perPage))
+	}
+	// Bitbucket pages are 1-indexed, treat 0 as "the first page" like the other providers do
+	if page > 0 {
+		query.Set("page", strconv.Itoa(page))
+	}
+
+	resp := &struct {
+		Values []*Commit `json:"values"`
+	}{}
+	// GET /repositories/{workspace}/{repo_slug}/commits/{branch}
+	if err := c.do(ctx, http.MethodGet, c.url(query, "repositories", workspace, repo, "commits", branch), nil, resp); err != nil {
+		return nil, handleHTTPError(err)
+	}
+
+	for _, apiObj := range resp.Values {
+		if err := validateCommitAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return resp.Values, nil
+}
+
+func (c *bitbucketClientImpl) GetCommit(ctx context.Context, workspace, repo, revision string) (*Commit, error) {
+	apiObj := &Commit{}
+	// GET /repositories/{workspace}/{repo_slug}/commit/{revision}
+	if err := c.do(ctx, http.MethodGet, c.url(nil, "repositories", workspace, repo, "commit", revision), nil, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validateCommitAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *bitbucketClientImpl) CreateCommit(ctx context.Context, workspace, repo, branch, parent, message, author string, files []gitprovider.CommitFile) (*Commit, error) {
+	// The src endpoint takes a multipart form, where every field that isn't a
+	// known parameter is interpreted as a file path with its content. The paths
+	// listed in "files" fields without such a field are deleted.
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	fields := [][2]string{{"message", message}, {"branch", branch}}
+	if len(author) != 0 {
+		fields = append(fields, [2]string{"author", author})
+	}
+	// Moved files are read at the parent, if any
+	revision := branch
+	if len(parent) != 0 {
+		fields = append(fields, [2]string{"parents", parent})
+		revision = parent
+	}
+	for _, file := range files {
+		// The executable bit can't be set through form fields
+		if file.IsExecutable() {
+			return nil, fmt.Errorf("cannot commit executable file %q: %w", *file.Path, gitprovider.ErrNoProviderSupport)
+		}
+		content, err := file.GetContent()
+		if err != nil {
+			return nil, err
+		}
+		switch file.GetAction() {
+		case gitprovider.CommitFileActionDelete:
+			fields = append(fields, [2]string{"files", *file.Path})
+			continue
+		case gitprovider.CommitFileActionMove:
+			// Moving is deleting the previous path, and writing the content to the new one
+			fields = append(fields, [2]string{"files", *file.PreviousPath})
+			if content == nil {
+				if content, err = c.GetFileContent(ctx, workspace, repo, revision, *file.PreviousPath); err != nil {
+					return nil, err
+				}
+			}
+		}
+		fields = append(fields, [2]string{*file.Path, string(content)})
+	}
+	for _, field := range fields {
+		if err := w.WriteField(field[0], field[1]); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	// POST /repositories/{workspace}/{repo_slug}/src
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url(nil, "repositories", workspace, repo, "src"), body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	resp, err := c.roundTrip(req, nil)
+	if err != nil {
+		// The server replies with "409 Conflict" if branch isn't at parent
+		bbErrorResponse := &ErrorResponse{}
+		if errors.As(err, &bbErrorResponse) && bbErrorResponse.Response.StatusCode == http.StatusConflict {
+			return nil, validation.NewMultiError(err, gitprovider.ErrConflict)
+		}
+		return nil, handleHTTPError(err)
+	}
+
+	// The server replies with "201 Created" and the location of the new commit.
+	// Fall back to asking for the head of the branch if the header isn't there.
+	revision = branch
+	if location := resp.Header.Get("Location"); len(location) != 0 {
+		revision = path.Base(location)
+	}
+	return c.GetCommit(ctx, workspace, repo, revision)
+}
+
+func (c *bitbucketClientImpl) GetFileContent(ctx context.Context, workspace, repo, revision, path string) ([]byte, error) {
+	// The file path is part of the URL, keep its slashes
+	segments := append([]string{"repositories", workspace, repo, "src", revision}, strings.Split(path, "/")...)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url(nil, segments...), nil)
+	if err != nil {
+		return nil, err
+	}
+	var content []byte
+	// GET /repositories/{workspace}/{repo_slug}/src/{commit}/{path}
+	if _, err := c.roundTrip(req, &content); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	return content, nil
+}
+
+func (c *bitbucketClientImpl) GetBranch(ctx context.Context, workspace, repo, branch string) (*Branch, error) {
+	apiObj := &Branch{}
+	// GET /repositories/{workspace}/{repo_slug}/refs/branches/{name}
+	if err := c.do(ctx, http.MethodGet, c.url(nil, "repositories", workspace, repo, "refs", "branches", branch), nil, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validateBranchAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *bitbucketClientImpl) ListBranches(ctx context.Context, workspace, repo string) ([]*Branch, error) {
+	apiObjs := []*Branch{}
+	// GET /repositories/{workspace}/{repo_slug}/refs/branches
+	err := c.allPages(ctx, c.url(nil, "repositories", workspace, repo, "refs", "branches"), func(values json.RawMessage) error {
+		var pageObjs []*Branch
+		err := json.Unmarshal(values, &pageObjs)
+		apiObjs = append(apiObjs, pageObjs...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, apiObj := range apiObjs {
+		if err := validateBranchAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *bitbucketClientImpl) CreateBranch(ctx context.Context, workspace, repo, branch, sha string) (*Branch, error) {
+	req := &Branch{
+		Name:   branch,
+		Target: &Commit{Hash: sha},
+	}
+	apiObj := &Branch{}
+	// POST /repositories/{workspace}/{repo_slug}/refs/branches
+	if err := c.do(ctx, http.MethodPost, c.url(nil, "repositories", workspace, repo, "refs", "branches"), req, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validateBranchAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *bitbucketClientImpl) DeleteBranch(ctx context.Context, workspace, repo, branch string) error {
+	// Don't allow deleting branches if the user didn't explicitly allow dangerous API calls.
+	if !c.destructiveActions {
+		return fmt.Errorf("cannot delete branch: %w", gitprovider.ErrDestructiveCallDisallowed)
+	}
+	// DELETE /repositories/{workspace}/{repo_slug}/refs/branches/{name}
+	err := c.do(ctx, http.MethodDelete, c.url(nil, "repositories", workspace, repo, "refs", "branches", branch), nil, nil)
+	return handleHTTPError(err)
+}
+
+func (c *bitbucketClientImpl) CreatePullRequest(ctx context.Context, workspace, repo string, req *PullRequest) (*PullRequest, error) {
+	apiObj := &PullRequest{}
+	// POST /repositories/{workspace}/{repo_slug}/pullrequests
+	if err := c.do(ctx, http.MethodPost, c.url(nil, "repositories", workspace, repo, "pullrequests"), req, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validatePullRequestAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *bitbucketClientImpl) GetPullRequest(ctx context.Context, workspace, repo string, id int) (*PullRequest, error) {
+	// GET /repositories/{workspace}/{repo_slug}/pullrequests/{id}
+	return c.doPullRequest(ctx, http.MethodGet, c.url(nil, "repositories", workspace, repo, "pullrequests", strconv.Itoa(id)), nil)
+}
+
+func (c *bitbucketClientImpl) ListPullRequests(ctx context.Context, workspace, repo string, query url.Values) ([]*PullRequest, error) {
+	apiObjs := []*PullRequest{}
+	// GET /repositories/{workspace}/{repo_slug}/pullrequests
+	err := c.allPages(ctx, c.url(query, "repositories", workspace, repo, "pullrequests"), func(values json.RawMessage) error {
+		var pageObjs []*PullRequest
+		err := json.Unmarshal(values, &pageObjs)
+		apiObjs = append(apiObjs, pageObjs...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, apiObj := range apiObjs {
+		if err := validatePullRequestAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *bitbucketClientImpl) UpdatePullRequest(ctx context.Context, workspace, repo string, id int, req *PullRequest) (*PullRequest, error) {
+	// PUT /repositories/{workspace}/{repo_slug}/pullrequests/{id}
+	return c.doPullRequest(ctx, http.MethodPut, c.url(nil, "repositories", workspace, repo, "pullrequests", strconv.Itoa(id)), req)
+}
+
+func (c *bitbucketClientImpl) MergePullRequest(ctx context.Context, workspace, repo string, id int, req *PullRequestMerge) (*PullRequest, error) {
+	// POST /repositories/{workspace}/{repo_slug}/pullrequests/{id}/merge
+	return c.doPullRequest(ctx, http.MethodPost, c.url(nil, "repositories", workspace, repo, "pullrequests", strconv.Itoa(id), "merge"), req)
+}
+
+func (c *bitbucketClientImpl) DeclinePullRequest(ctx context.Context, workspace, repo string, id int) (*PullRequest, error) {
+	// POST /repositories/{workspace}/{repo_slug}/pullrequests/{id}/decline
+	return c.doPullRequest(ctx, http.MethodPost, c.url(nil, "repositories", workspace, repo, "pullrequests", strconv.Itoa(id), "decline"), nil)
+}
+
+func (c *bitbucketClientImpl) ListPullRequestComments(ctx context.Context, workspace, repo string, id int) ([]*Comment, error) {
+	apiObjs := []*Comment{}
+	// GET /repositories/{workspace}/{repo_slug}/pullrequests/{id}/comments
+	err := c.allPages(ctx, c.url(nil, "repositories", workspace, repo, "pullrequests", strconv.Itoa(id), "comments"), func(values json.RawMessage) error {
+		var pageObjs []*Comment
+		err := json.Unmarshal(values, &pageObjs)
+		apiObjs = append(apiObjs, pageObjs...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, apiObj := range apiObjs {
+		if err := validateCommentAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *bitbucketClientImpl) CreatePullRequestComment(ctx context.Context, workspace, repo string, id int, body string) (*Comment, error) {
+	// POST /repositories/{workspace}/{repo_slug}/pullrequests/{id}/comments
+	return c.doComment(ctx, http.MethodPost, c.url(nil, "repositories", workspace, repo, "pullrequests", strconv.Itoa(id), "comments"), body)
+}
+
+func (c *bitbucketClientImpl) UpdatePullRequestComment(ctx context.Context, workspace, repo string, id, commentID int, body string) (*Comment, error) {
+	// PUT /repositories/{workspace}/{repo_slug}/pullrequests/{id}/comments/{comment_id}
+	return c.doComment(ctx, http.MethodPut, c.url(nil, "repositories", workspace, repo, "pullrequests", strconv.Itoa(id), "comments", strconv.Itoa(commentID)), body)
+}
+
+func (c *bitbucketClientImpl) DeletePullRequestComment(ctx context.Context, workspace, repo string, id, commentID int) error {
+	// DELETE /repositories/{workspace}/{repo_slug}/pullrequests/{id}/comments/{comment_id}
+	err := c.do(ctx, http.MethodDelete, c.url(nil, "repositories", workspace, repo, "pullrequests", strconv.Itoa(id), "comments", strconv.Itoa(commentID)), nil, nil)
+	return handleHTTPError(err)
+}
+
+func (c *bitbucketClientImpl) ApprovePullRequest(ctx context.Context, workspace, repo string, id int) (*Participant, error) {
+	apiObj := &Participant{}
+	// POST /repositories/{workspace}/{repo_slug}/pullrequests/{id}/approve
+	if err := c.do(ctx, http.MethodPost, c.url(nil, "repositories", workspace, repo, "pullrequests", strconv.Itoa(id), "approve"), nil, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	return apiObj, nil
+}
+
+func (c *bitbucketClientImpl) RequestPullRequestChanges(ctx context.Context, workspace, repo string, id int) (*Participant, error) {
+	apiObj := &Participant{}
+	// POST /repositories/{workspace}/{repo_slug}/pullrequests/{id}/request-changes
+	if err := c.do(ctx, http.MethodPost, c.url(nil, "repositories", workspace, repo, "pullrequests", strconv.Itoa(id), "request-changes"), nil, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	return apiObj, nil
+}
+
+// doComment sends a request with the given comment body returning a comment, and validates
+// the result.
+func (c *bitbucketClientImpl) doComment(ctx context.Context, method, urlStr, body string) (*Comment, error) {
+	apiObj := &Comment{}
+	if err := c.do(ctx, method, urlStr, &Comment{Content: CommentContent{Raw: body}}, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validateCommentAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+// doPullRequest sends a request returning a pull request, and validates the result.
+func (c *bitbucketClientImpl) doPullRequest(ctx context.Context, method, urlStr string, body interface{}) (*PullRequest, error) {
+	apiObj := &PullRequest{}
+	if err := c.do(ctx, method, urlStr, body, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validatePullRequestAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+// url returns the absolute URL for the API path built from the escaped path segments,
+// with the optional query appended.
+func (c *bitbucketClientImpl) url(query url.Values, segments ...string) string {
+	escaped := make([]string, 0, len(segments))
+	for _, s := range segments {
+		escaped = append(escaped, url.PathEscape(s))
+	}
+	u := strings.TrimSuffix(c.baseURL, "/") + "/" + strings.Join(escaped, "/")
+	if len(query) != 0 {
+		u = u + "?" + query.Encode()
+	}
+	return u
+}
+
+// do sends a request with the given JSON-encoded body (if non-nil) and decodes the
+// response into out (if non-nil). Non-2xx responses are returned as *ErrorResponse.
+func (c *bitbucketClientImpl) do(ctx context.Context, method, urlStr string, body, out interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, urlStr, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	_, err = c.roundTrip(req, out)
+	return err
+}
+
+// roundTrip executes req, and decodes a successful response into out, if out is non-nil.
+// If out is a *[]byte, the raw response body is read into it instead.
+func (c *bitbucketClientImpl) roundTrip(req *http.Request, out interface{}) (*http.Response, error) {
+	resp, err := c.c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		errResp := &ErrorResponse{Response: resp}
+		// The error body is best-effort, the status code is what matters
+		if data, readErr := ioutil.ReadAll(resp.Body); readErr == nil && len(data) != 0 {
+			_ = json.Unmarshal(data, errResp)
+		}
+		return resp, errResp
+	}
+
+	if raw, ok := out.(*[]byte); ok {
+		*raw, err = ioutil.ReadAll(resp.Body)
+		return resp, err
+	}
+	if out != nil && resp.StatusCode != http.StatusNoContent {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil && err != io.EOF {
+			return resp, err
+		}
+	}
+	return resp, nil
+}
+
+// allPages requests urlStr, and follows the "next" links of the paginated responses until
+// the last page has been fetched. fn is called with the raw "values" list of every page.
+// There is no need to wrap the resulting error in handleHTTPError(err), as that's already done.
+func (c *bitbucketClientImpl) allPages(ctx context.Context, urlStr string, fn func(values json.RawMessage) error) error {
+	for len(urlStr) != 0 {
+		page := &struct {
+			paginatedResponse
+			Values json.RawMessage `json:"values"`
+		}{}
+		if err := c.do(ctx, http.MethodGet, urlStr, nil, page); err != nil {
+			return handleHTTPError(err)
+		}
+		if len(page.Values) != 0 {
+			if err := fn(page.Values); err != nil {
+				return err
+			}
+		}
+		urlStr = page.Next
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitbucket
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// ProviderID is the provider ID for Bitbucket Cloud.
+const ProviderID = gitprovider.ProviderID("bitbucket")
+
+func newClient(c *http.Client, baseURL, domain string, destructiveActions bool) *Client {
+	bbClient := &bitbucketClientImpl{c, baseURL, destructiveActions}
+	ctx := &clientContext{bbClient, domain, destructiveActions}
+	return &Client{
+		clientContext: ctx,
+		orgs: &OrganizationsClient{
+			clientContext: ctx,
+		},
+		orgRepos: &OrgRepositoriesClient{
+			clientContext: ctx,
+		},
+		userRepos: &UserRepositoriesClient{
+			clientContext: ctx,
+		},
+	}
+}
+
+type clientContext struct {
+	c                  bitbucketClient
+	domain             string
+	destructiveActions bool
+}
+
+// Client implements the gitprovider.Client interface.
+var _ gitprovider.Client = &Client{}
+
+// Client is an interface that allows talking to a Git provider.
+type Client struct {
+	*clientContext
+
+	orgs      *OrganizationsClient
+	orgRepos  *OrgRepositoriesClient
+	userRepos *UserRepositoriesClient
+}
+
+// SupportedDomain returns the domain endpoint for this client, e.g. "bitbucket.org" or
+// "my-custom-proxy.com:6443". This allows a higher-level user to know what Client to use for
+// what endpoints.
+// This field is set at client creation time, and can't be changed.
+func (c *Client) SupportedDomain() string {
+	return c.domain
+}
+
+// ProviderID returns the provider ID "bitbucket".
+// This field is set at client creation time, and can't be changed.
+func (c *Client) ProviderID() gitprovider.ProviderID {
+	return ProviderID
+}
+
+// Raw returns the *http.Client used under the hood for accessing Bitbucket Cloud,
+// with the full transport chain applied.
+func (c *Client) Raw() interface{} {
+	return c.c.Client()
+}
+
+// Organizations returns the OrganizationsClient handling sets of organizations.
+func (c *Client) Organizations() gitprovider.OrganizationsClient {
+	return c.orgs
+}
+
+// OrgRepositories returns the OrgRepositoriesClient handling sets of repositories in an organization.
+func (c *Client) OrgRepositories() gitprovider.OrgRepositoriesClient {
+	return c.orgRepos
+}
+
+// UserRepositories returns the UserRepositoriesClient handling sets of repositories for a user.
+func (c *Client) UserRepositories() gitprovider.UserRepositoriesClient {
+	return c.userRepos
+}
+
+//nolint:gochecknoglobals
+var permissionScopes = map[gitprovider.TokenPermission][]string{
+	// Both the write and admin scopes allow pushing to repositories
+	gitprovider.TokenPermissionRWRepository: {"repository:write", "repository:admin"},
+}
+
+// HasTokenPermission returns true if the given token has the given permissions.
+//
+// This only works for OAuth2 tokens, as Bitbucket doesn't return scopes for app passwords.
+func (c *Client) HasTokenPermission(ctx context.Context, permission gitprovider.TokenPermission) (bool, error) {
+	requestedScopes, ok := permissionScopes[permission]
+	if !ok {
+		return false, gitprovider.ErrNoProviderSupport
+	}
+
+	// GET /user
+	scopes, err := c.c.GetTokenScopes(ctx)
+	if err != nil {
+		return false, err
+	}
+	if scopes == "" {
+		return false, gitprovider.ErrMissingHeader
+	}
+
+	for _, s := range strings.Split(scopes, ",") {
+		scope := strings.TrimSpace(s)
+		for _, requestedScope := range requestedScopes {
+			if scope == requestedScope {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// Quota returns the current API request quota of the client.
+//
+// Bitbucket Cloud doesn't report the remaining quota, hence ErrNoProviderSupport is returned.
+func (c *Client) Quota(ctx context.Context) (gitprovider.Quota, error) {
+	return gitprovider.Quota{}, gitprovider.ErrNoProviderSupport
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitbucket
+
+import (
+	"context"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// TeamsClient implements the gitprovider.TeamsClient interface.
+var _ gitprovider.TeamsClient = &TeamsClient{}
+
+// TeamsClient handles teams workspace-wide.
+//
+// Bitbucket Cloud has user groups instead of teams, which are not part of the 2.0 API.
+// Hence, all methods return ErrNoProviderSupport.
+type TeamsClient struct {
+	*clientContext
+	ref gitprovider.OrganizationRef
+}
+
+// Get a team within the specific organization.
+//
+// This is not supported in Bitbucket.
+func (c *TeamsClient) Get(_ context.Context, _ string) (gitprovider.Team, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// List all teams within the specific organization.
+//
+// This is not supported in Bitbucket.
+func (c *TeamsClient) List(_ context.Context) ([]gitprovider.Team, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitbucket
+
+import (
+	"context"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// IssueClient implements the gitprovider.IssueClient interface.
+var _ gitprovider.IssueClient = &IssueClient{}
+
+// IssueClient operates on the issues for a specific repository.
+//
+// The Bitbucket Cloud issue tracker API isn't wrapped by this package yet.
+// Hence, all methods return ErrNoProviderSupport.
+type IssueClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// Get returns the issue with the given number.
+//
+// This isn't implemented by this package yet.
+func (c *IssueClient) Get(_ context.Context, _ int) (gitprovider.Issue, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// List lists the issues of the repository matching the given filters.
+//
+// This isn't implemented by this package yet.
+func (c *IssueClient) List(_ context.Context, _ ...gitprovider.IssueListOption) ([]gitprovider.Issue, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// Create creates an issue with the given specifications.
+//
+// This isn't implemented by this package yet.
+func (c *IssueClient) Create(_ context.Context, _ gitprovider.IssueInfo) (gitprovider.Issue, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// Update changes the title, body, labels and/or assignees of the issue with the given number.
+//
+// This isn't implemented by this package yet.
+func (c *IssueClient) Update(_ context.Context, _ int, _ gitprovider.IssueUpdateOptions) (gitprovider.Issue, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// Close closes the issue with the given number.
+//
+// This isn't implemented by this package yet.
+func (c *IssueClient) Close(_ context.Context, _ int) (gitprovider.Issue, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// Reopen reopens the issue with the given number.
+//
+// This isn't implemented by this package yet.
+func (c *IssueClient) Reopen(_ context.Context, _ int) (gitprovider.Issue, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// AddLabels adds the given labels to the issue with the given number.
+//
+// This isn't implemented by this package yet.
+func (c *IssueClient) AddLabels(_ context.Context, _ int, _ []string) (gitprovider.Issue, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// RemoveLabels removes the given labels from the issue with the given number.
+//
+// This isn't implemented by this package yet.
+func (c *IssueClient) RemoveLabels(_ context.Context, _ int, _ []string) (gitprovider.Issue, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitbucket
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// PullRequestReviewClient implements the gitprovider.PullRequestReviewClient interface.
+var _ gitprovider.PullRequestReviewClient = &PullRequestReviewClient{}
+
+// PullRequestReviewClient operates on the reviews of a specific pull request. Bitbucket doesn't
+// have reviews, so the approvals and change requests of the participants, and comments, are
+// used instead.
+type PullRequestReviewClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+	id  int
+}
+
+// List lists the participants of the pull request who approved it or requested changes.
+// Reviews only commenting are comments, which are listed by PullRequest.Comments().
+func (c *PullRequestReviewClient) List(ctx context.Context) ([]gitprovider.PullRequestReview, error) {
+	// GET /repositories/{workspace}/{repo_slug}/pullrequests/{id}
+	pr, err := c.c.GetPullRequest(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), c.id)
+	if err != nil {
+		return nil, err
+	}
+
+	reviews := make([]gitprovider.PullRequestReview, 0, len(pr.Participants))
+	for _, participant := range pr.Participants {
+		if participant.State != participantStateApproved && participant.State != participantStateChangesRequested {
+			continue
+		}
+		reviews = append(reviews, newParticipantReview(participant, nil))
+	}
+	return reviews, nil
+}
+
+// Submit approves the pull request, requests changes, or adds a comment to it. A non-empty
+// body is added as a comment when approving or requesting changes.
+func (c *PullRequestReviewClient) Submit(ctx context.Context, state gitprovider.PullRequestReviewState, body string) (gitprovider.PullRequestReview, error) {
+	var participant *Participant
+	var err error
+	switch state {
+	case gitprovider.PullRequestReviewStateApproved:
+		// POST /repositories/{workspace}/{repo_slug}/pullrequests/{id}/approve
+		participant, err = c.c.ApprovePullRequest(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), c.id)
+	case gitprovider.PullRequestReviewStateChangesRequested:
+		// POST /repositories/{workspace}/{repo_slug}/pullrequests/{id}/request-changes
+		participant, err = c.c.RequestPullRequestChanges(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), c.id)
+	case gitprovider.PullRequestReviewStateCommented:
+		// POST /repositories/{workspace}/{repo_slug}/pullrequests/{id}/comments
+		comment, err := c.c.CreatePullRequestComment(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), c.id, body)
+		if err != nil {
+			return nil, err
+		}
+		return newCommentReview(comment), nil
+	default:
+		return nil, fmt.Errorf("review state %q: %w", state, gitprovider.ErrInvalidArgument)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if body == "" {
+		return newParticipantReview(participant, nil), nil
+	}
+
+	// POST /repositories/{workspace}/{repo_slug}/pullrequests/{id}/comments
+	comment, err := c.c.CreatePullRequestComment(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), c.id, body)
+	if err != nil {
+		return nil, err
+	}
+	return newParticipantReview(participant, comment), nil
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitbucket
+
+import (
+	"context"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// TagClient implements the gitprovider.TagClient interface.
+var _ gitprovider.TagClient = &TagClient{}
+
+// TagClient operates on the tags for a specific repository.
+//
+// The Bitbucket Cloud tags API isn't wrapped by this package yet.
+// Hence, all methods return ErrNoProviderSupport.
+type TagClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// Get returns the tag with the given name.
+//
+// This isn't implemented by this package yet.
+func (c *TagClient) Get(_ context.Context, _ string) (gitprovider.Tag, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// List lists all tags of the repository.
+//
+// This isn't implemented by this package yet.
+func (c *TagClient) List(_ context.Context) ([]gitprovider.Tag, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// Create creates a tag with the given specifications.
+//
+// This isn't implemented by this package yet.
+func (c *TagClient) Create(_ context.Context, _ gitprovider.TagInfo) (gitprovider.Tag, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// Delete deletes the tag with the given name.
+//
+// This isn't implemented by this package yet.
+func (c *TagClient) Delete(_ context.Context, _ string) error {
+	return gitprovider.ErrNoProviderSupport
+}
@@ -0,0 +1,952 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+// newTestClient starts a server handling the API paths of mux, and returns a client pointing to it.
+func newTestClient(t *testing.T, mux *http.ServeMux, opts ...ClientOption) (*Client, string) {
+	t.Helper()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	c, err := NewClient(append([]ClientOption{WithDomain(srv.URL)}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c.(*Client), srv.URL
+}
+
+func writeJSON(t *testing.T, w http.ResponseWriter, status int, obj interface{}) {
+	t.Helper()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(obj); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeError(t *testing.T, w http.ResponseWriter, status int, message string) {
+	t.Helper()
+	writeJSON(t, w, status, map[string]interface{}{
+		"type":  "error",
+		"error": map[string]string{"message": message},
+	})
+}
+
+func TestNewClient_domain(t *testing.T) {
+	c, err := NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.SupportedDomain(); got != DefaultDomain {
+		t.Errorf("SupportedDomain() = %q, want %q", got, DefaultDomain)
+	}
+	if got := c.(*Client).c.(*bitbucketClientImpl).baseURL; got != DefaultAPIURL {
+		t.Errorf("baseURL = %q, want %q", got, DefaultAPIURL)
+	}
+	if got := c.ProviderID(); got != ProviderID {
+		t.Errorf("ProviderID() = %q, want %q", got, ProviderID)
+	}
+}
+
+func TestOrganizationsClient(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/2.0/workspaces/foo", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, http.StatusOK, Workspace{Slug: "foo", Name: "Foo"})
+	})
+	mux.HandleFunc("/2.0/workspaces/missing", func(w http.ResponseWriter, r *http.Request) {
+		writeError(t, w, http.StatusNotFound, "No workspace with identifier 'missing'.")
+	})
+	var serverURL string
+	mux.HandleFunc("/2.0/workspaces", func(w http.ResponseWriter, r *http.Request) {
+		// Serve two pages, to test pagination
+		if r.URL.Query().Get("page") == "2" {
+			writeJSON(t, w, http.StatusOK, map[string]interface{}{
+				"values": []Workspace{{Slug: "bar"}},
+			})
+			return
+		}
+		writeJSON(t, w, http.StatusOK, map[string]interface{}{
+			"values": []Workspace{{Slug: "foo", Name: "Foo"}},
+			"next":   serverURL + "/2.0/workspaces?page=2",
+		})
+	})
+	c, url := newTestClient(t, mux)
+	serverURL = url
+	ctx := context.Background()
+
+	org, err := c.Organizations().Get(ctx, gitprovider.OrganizationRef{Domain: c.domain, Organization: "foo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := *org.Get().Name; got != "Foo" {
+		t.Errorf("Organization.Get().Name = %q, want %q", got, "Foo")
+	}
+
+	_, err = c.Organizations().Get(ctx, gitprovider.OrganizationRef{Domain: c.domain, Organization: "missing"})
+	validation.TestExpectErrors(t, "Organizations().Get", err, gitprovider.ErrNotFound)
+
+	_, err = c.Organizations().Get(ctx, gitprovider.OrganizationRef{Domain: "other.com", Organization: "foo"})
+	validation.TestExpectErrors(t, "Organizations().Get", err, gitprovider.ErrDomainUnsupported)
+
+	_, err = c.Organizations().Get(ctx, gitprovider.OrganizationRef{Domain: c.domain, Organization: "foo", SubOrganizations: []string{"bar"}})
+	validation.TestExpectErrors(t, "Organizations().Get", err, gitprovider.ErrNoProviderSupport)
+
+	orgs, err := c.Organizations().List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orgs) != 2 || orgs[1].Organization().Organization != "bar" {
+		t.Errorf("Organizations().List() = %v, want foo and bar", orgs)
+	}
+
+	_, err = c.Organizations().Children(ctx, org.Organization())
+	validation.TestExpectErrors(t, "Organizations().Children", err, gitprovider.ErrNoProviderSupport)
+	_, err = org.Teams().List(ctx)
+	validation.TestExpectErrors(t, "Teams().List", err, gitprovider.ErrNoProviderSupport)
+}
+
+// fakeRepoServer is a minimal in-memory implementation of the repository endpoints.
+type fakeRepoServer struct {
+	t     *testing.T
+	repos map[string]*Repository
+	keys  []*DeployKey
+	// commits on the main branch, newest first
+	commits []Commit
+	// head commit hashes by branch name
+	branches map[string]string
+	prs      []*PullRequest
+	// comments on all pull requests, in creation order
+	comments []*Comment
+	url      string
+}
+
+func (s *fakeRepoServer) register(mux *http.ServeMux) {
+	s.branches = map[string]string{}
+	mux.HandleFunc("/2.0/repositories/", s.handle)
+}
+
+func (s *fakeRepoServer) handle(w http.ResponseWriter, r *http.Request) {
+	t := s.t
+	// Path: /2.0/repositories/{workspace}[/{repo}[/...]]
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/2.0/repositories/"), "/")
+	if len(parts) == 1 {
+		values := []*Repository{}
+		for name, repo := range s.repos {
+			if strings.HasPrefix(name, parts[0]+"/") {
+				values = append(values, repo)
+			}
+		}
+		writeJSON(t, w, http.StatusOK, map[string]interface{}{"values": values})
+		return
+	}
+	name := parts[0] + "/" + parts[1]
+	repo, exists := s.repos[name]
+	if len(parts) == 2 {
+		switch r.Method {
+		case http.MethodGet:
+			if !exists {
+				writeError(t, w, http.StatusNotFound, "Repository "+name+" not found")
+				return
+			}
+			writeJSON(t, w, http.StatusOK, repo)
+		case http.MethodPost, http.MethodPut:
+			if r.Method == http.MethodPost && exists {
+				writeError(t, w, http.StatusBadRequest, "Repository with this Slug and Owner already exists.")
+				return
+			}
+			req := &Repository{}
+			if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+				t.Fatal(err)
+			}
+			req.Slug = parts[1]
+			req.FullName = name
+			s.repos[name] = req
+			writeJSON(t, w, http.StatusOK, req)
+		case http.MethodDelete:
+			delete(s.repos, name)
+			w.WriteHeader(http.StatusNoContent)
+		}
+		return
+	}
+	if !exists {
+		writeError(t, w, http.StatusNotFound, "Repository "+name+" not found")
+		return
+	}
+
+	switch parts[2] {
+	case "deploy-keys":
+		switch {
+		case r.Method == http.MethodGet:
+			writeJSON(t, w, http.StatusOK, map[string]interface{}{"values": s.keys})
+		case r.Method == http.MethodPost:
+			req := &DeployKey{}
+			if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+				t.Fatal(err)
+			}
+			// The server splits off the comment of the key
+			if i := strings.LastIndex(req.Key, " "); i > strings.Index(req.Key, " ") {
+				req.Comment = req.Key[i+1:]
+				req.Key = req.Key[:i]
+			}
+			for _, k := range s.keys {
+				if k.Key == req.Key {
+					writeError(t, w, http.StatusBadRequest, "Someone has already added that access key to this repository.")
+					return
+				}
+			}
+			req.ID = len(s.keys) + 1
+			s.keys = append(s.keys, req)
+			writeJSON(t, w, http.StatusOK, req)
+		case r.Method == http.MethodDelete:
+			for i, k := range s.keys {
+				if fmt.Sprint(k.ID) == parts[3] {
+					s.keys = append(s.keys[:i], s.keys[i+1:]...)
+					w.WriteHeader(http.StatusNoContent)
+					return
+				}
+			}
+			writeError(t, w, http.StatusNotFound, "Not found")
+		}
+	case "commits":
+		writeJSON(t, w, http.StatusOK, map[string]interface{}{"values": s.commits})
+	case "commit":
+		// The revision can also be a branch name
+		revision := parts[3]
+		if hash, ok := s.branches[revision]; ok {
+			revision = hash
+		}
+		for _, c := range s.commits {
+			if c.Hash == revision {
+				writeJSON(t, w, http.StatusOK, c)
+				return
+			}
+		}
+		writeError(t, w, http.StatusNotFound, "Commit not found")
+	case "src":
+		if r.Method == http.MethodGet {
+			// Path: src/{commit}/{path}, all files have the same content
+			_, _ = w.Write([]byte("content of " + strings.Join(parts[4:], "/")))
+			return
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatal(err)
+		}
+		// New branches are created on top of parents, existing ones must be at it
+		parent := r.FormValue("parents")
+		if head, ok := s.branches[r.FormValue("branch")]; ok && len(parent) != 0 && parent != head {
+			writeError(t, w, http.StatusConflict, "Commit "+parent+" is not the head of the branch")
+			return
+		}
+		hash := fmt.Sprintf("%040d", len(s.commits)+1)
+		message := r.FormValue("message")
+		keys := make([]string, 0, len(r.MultipartForm.Value))
+		for k := range r.MultipartForm.Value {
+			if k != "message" && k != "branch" && k != "parents" && k != "author" {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			for _, v := range r.MultipartForm.Value[k] {
+				message += "\n" + k + "=" + v
+			}
+		}
+		commit := Commit{Hash: hash, Message: message, Author: CommitAuthor{Raw: r.FormValue("author")}}
+		if len(parent) != 0 {
+			commit.Parents = []Commit{{Hash: parent}}
+		}
+		s.commits = append([]Commit{commit}, s.commits...)
+		s.branches[r.FormValue("branch")] = hash
+		w.Header().Set("Location", s.url+"/2.0/repositories/"+name+"/commit/"+hash)
+		w.WriteHeader(http.StatusCreated)
+	case "refs":
+		s.handleBranches(w, r, parts[4:])
+	case "pullrequests":
+		s.handlePullRequests(w, r, name, parts[3:])
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (s *fakeRepoServer) handleBranches(w http.ResponseWriter, r *http.Request, parts []string) {
+	t := s.t
+	// Path: refs/branches[/{name}]
+	if len(parts) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			values := []*Branch{}
+			for name, hash := range s.branches {
+				values = append(values, &Branch{Name: name, Target: &Commit{Hash: hash}})
+			}
+			writeJSON(t, w, http.StatusOK, map[string]interface{}{"values": values})
+		case http.MethodPost:
+			req := &Branch{}
+			if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := s.branches[req.Name]; ok {
+				writeError(t, w, http.StatusBadRequest, fmt.Sprintf("BRANCH_ALREADY_EXISTS: Branch %q already exists", req.Name))
+				return
+			}
+			s.branches[req.Name] = req.Target.Hash
+			writeJSON(t, w, http.StatusCreated, req)
+		}
+		return
+	}
+	hash, ok := s.branches[parts[0]]
+	if !ok {
+		writeError(t, w, http.StatusNotFound, "Branch not found")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(t, w, http.StatusOK, &Branch{Name: parts[0], Target: &Commit{Hash: hash}})
+	case http.MethodDelete:
+		delete(s.branches, parts[0])
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestOrgRepositoriesClient(t *testing.T) {
+	mux := http.NewServeMux()
+	srv := &fakeRepoServer{t: t, repos: map[string]*Repository{}}
+	srv.register(mux)
+	c, url := newTestClient(t, mux)
+	srv.url = url
+	ctx := context.Background()
+
+	ref := gitprovider.OrgRepositoryRef{
+		OrganizationRef: gitprovider.OrganizationRef{Domain: c.domain, Organization: "foo"},
+		RepositoryName:  "bar",
+	}
+	_, err := c.OrgRepositories().Get(ctx, ref)
+	validation.TestExpectErrors(t, "OrgRepositories().Get", err, gitprovider.ErrNotFound)
+
+	// Reconcile should create the repository
+	repo, actionTaken, err := c.OrgRepositories().Reconcile(ctx, ref, gitprovider.RepositoryInfo{
+		Description: gitprovider.StringVar("desc"),
+	}, &gitprovider.RepositoryCreateOptions{AutoInit: gitprovider.BoolVar(true)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !actionTaken {
+		t.Error("expected Reconcile to create the repository")
+	}
+	info := repo.Get()
+	if *info.Visibility != gitprovider.RepositoryVisibilityPrivate || *info.DefaultBranch != "master" || *info.Description != "desc" {
+		t.Errorf("unexpected repository info: %+v", info)
+	}
+	if len(srv.commits) != 1 || !strings.Contains(srv.commits[0].Message, "README.md=") {
+		t.Errorf("expected an initial commit with a README, got %v", srv.commits)
+	}
+
+	// Creating it again is an error
+	_, err = c.OrgRepositories().Create(ctx, ref, gitprovider.RepositoryInfo{})
+	validation.TestExpectErrors(t, "OrgRepositories().Create", err, gitprovider.ErrAlreadyExists)
+
+	// Reconciling the same state again is a no-op
+	_, actionTaken, err = c.OrgRepositories().Reconcile(ctx, ref, gitprovider.RepositoryInfo{
+		Description: gitprovider.StringVar("desc"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actionTaken {
+		t.Error("expected Reconcile to be a no-op")
+	}
+
+	// Changing the visibility updates the repository
+	_, actionTaken, err = c.OrgRepositories().Reconcile(ctx, ref, gitprovider.RepositoryInfo{
+		Description: gitprovider.StringVar("desc"),
+		Visibility:  gitprovider.RepositoryVisibilityVar(gitprovider.RepositoryVisibilityPublic),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !actionTaken || *srv.repos["foo/bar"].IsPrivate {
+		t.Error("expected Reconcile to make the repository public")
+	}
+
+	// Internal repositories can't be expressed
+	err = repo.Set(gitprovider.RepositoryInfo{
+		Visibility: gitprovider.RepositoryVisibilityVar(gitprovider.RepositoryVisibilityInternal),
+	})
+	validation.TestExpectErrors(t, "Repository.Set", err, gitprovider.ErrNoProviderSupport)
+
+	repos, err := c.OrgRepositories().List(ctx, ref.OrganizationRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repos) != 1 || repos[0].Repository().GetRepository() != "bar" {
+		t.Errorf("OrgRepositories().List() = %v, want [bar]", repos)
+	}
+
+	_, err = repo.TeamAccess().List(ctx)
+	validation.TestExpectErrors(t, "TeamAccess().List", err, gitprovider.ErrNoProviderSupport)
+
+	// Deleting requires destructive actions to be enabled
+	err = repo.Delete(ctx)
+	validation.TestExpectErrors(t, "Repository.Delete", err, gitprovider.ErrDestructiveCallDisallowed)
+}
+
+func TestUserRepositoriesClient(t *testing.T) {
+	mux := http.NewServeMux()
+	srv := &fakeRepoServer{t: t, repos: map[string]*Repository{}}
+	srv.register(mux)
+	c, _ := newTestClient(t, mux, WithDestructiveAPICalls(true))
+	ctx := context.Background()
+
+	ref := gitprovider.UserRepositoryRef{
+		UserRef:        gitprovider.UserRef{Domain: c.domain, UserLogin: "user"},
+		RepositoryName: "bar",
+	}
+	repo, err := c.UserRepositories().Create(ctx, ref, gitprovider.RepositoryInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req := srv.repos["user/bar"]; req.SCM != "git" || !*req.IsPrivate || req.MainBranch.Name != "master" {
+		t.Errorf("unexpected create request: %+v", req)
+	}
+
+	repos, err := c.UserRepositories().List(ctx, ref.UserRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repos) != 1 {
+		t.Errorf("UserRepositories().List() = %v, want 1 repository", repos)
+	}
+
+	if err := repo.Delete(ctx); err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.UserRepositories().Get(ctx, ref)
+	validation.TestExpectErrors(t, "UserRepositories().Get", err, gitprovider.ErrNotFound)
+}
+
+func TestDeployKeyClient(t *testing.T) {
+	mux := http.NewServeMux()
+	srv := &fakeRepoServer{t: t, repos: map[string]*Repository{
+		"foo/bar": {Slug: "bar", IsPrivate: gitprovider.BoolVar(true)},
+	}}
+	srv.register(mux)
+	c, _ := newTestClient(t, mux)
+	ctx := context.Background()
+
+	repo, err := c.OrgRepositories().Get(ctx, gitprovider.OrgRepositoryRef{
+		OrganizationRef: gitprovider.OrganizationRef{Domain: c.domain, Organization: "foo"},
+		RepositoryName:  "bar",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = repo.DeployKeys().Get(ctx, "flux")
+	validation.TestExpectErrors(t, "DeployKeys().Get", err, gitprovider.ErrNotFound)
+
+	_, err = repo.DeployKeys().Create(ctx, gitprovider.DeployKeyInfo{
+		Name:     "flux",
+		Key:      []byte("ssh-rsa AAAA flux@example.com"),
+		ReadOnly: gitprovider.BoolVar(false),
+	})
+	validation.TestExpectErrors(t, "DeployKeys().Create", err, gitprovider.ErrNoProviderSupport)
+
+	req := gitprovider.DeployKeyInfo{
+		Name: "flux",
+		Key:  []byte("ssh-rsa AAAA flux@example.com"),
+	}
+	_, actionTaken, err := repo.DeployKeys().Reconcile(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !actionTaken || len(srv.keys) != 1 {
+		t.Fatalf("expected Reconcile to create the key, got %v", srv.keys)
+	}
+
+	_, err = repo.DeployKeys().Create(ctx, req)
+	validation.TestExpectErrors(t, "DeployKeys().Create", err, gitprovider.ErrAlreadyExists)
+
+	// The key and comment are joined back together, so reconciling again is a no-op
+	key, actionTaken, err := repo.DeployKeys().Reconcile(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actionTaken {
+		t.Errorf("expected Reconcile to be a no-op, got %s", key.Get().Key)
+	}
+
+	// Changing the key recreates it
+	req.Key = []byte("ssh-rsa BBBB flux@example.com")
+	_, actionTaken, err = repo.DeployKeys().Reconcile(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !actionTaken || len(srv.keys) != 1 || srv.keys[0].Key != "ssh-rsa BBBB" {
+		t.Errorf("expected Reconcile to recreate the key, got %v", srv.keys)
+	}
+
+	keys, err := repo.DeployKeys().List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 {
+		t.Errorf("DeployKeys().List() = %v, want 1 key", keys)
+	}
+	if err := keys[0].Delete(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(srv.keys) != 0 {
+		t.Errorf("expected the key to be deleted, got %v", srv.keys)
+	}
+}
+
+func (s *fakeRepoServer) handlePullRequests(w http.ResponseWriter, r *http.Request, name string, parts []string) {
+	t := s.t
+	if len(parts) == 0 {
+		if r.Method == http.MethodGet {
+			// Only support the state and branch filters used by the tests
+			values := []*PullRequest{}
+			for _, pr := range s.prs {
+				matches := false
+				for _, state := range r.URL.Query()["state"] {
+					matches = matches || pr.State == state
+				}
+				if q := r.URL.Query().Get("q"); len(q) != 0 {
+					for _, filter := range strings.Split(q, " AND ") {
+						matches = matches && (filter == fmt.Sprintf("source.branch.name=%q", pr.Source.Branch.Name) ||
+							filter == fmt.Sprintf("destination.branch.name=%q", pr.Destination.Branch.Name))
+					}
+				}
+				if matches {
+					values = append(values, pr)
+				}
+			}
+			writeJSON(t, w, http.StatusOK, map[string]interface{}{"values": values})
+			return
+		}
+		req := &PullRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			t.Fatal(err)
+		}
+		req.ID = len(s.prs) + 1
+		req.State = "OPEN"
+		req.Links = &Links{HTML: &Link{Href: fmt.Sprintf("https://bitbucket.org/%s/pull-requests/%d", name, req.ID)}}
+		s.prs = append(s.prs, req)
+		writeJSON(t, w, http.StatusCreated, req)
+		return
+	}
+
+	var pr *PullRequest
+	for _, p := range s.prs {
+		if fmt.Sprint(p.ID) == parts[0] {
+			pr = p
+		}
+	}
+	if pr == nil {
+		writeError(t, w, http.StatusNotFound, "Pull request not found")
+		return
+	}
+	switch {
+	case len(parts) == 1 && r.Method == http.MethodPut:
+		req := &PullRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			t.Fatal(err)
+		}
+		pr.Title, pr.Description, pr.Destination = req.Title, req.Description, req.Destination
+	case len(parts) == 2 && parts[1] == "merge":
+		req := &PullRequestMerge{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			t.Fatal(err)
+		}
+		if req.MergeStrategy != "squash" {
+			t.Errorf("unexpected merge strategy %q", req.MergeStrategy)
+		}
+		pr.State = "MERGED"
+	case len(parts) == 2 && parts[1] == "decline":
+		pr.State = "DECLINED"
+	case len(parts) == 2 && (parts[1] == "approve" || parts[1] == "request-changes"):
+		participant := &Participant{User: &Account{Nickname: "me"}, Role: "REVIEWER", State: participantStateApproved, Approved: true}
+		if parts[1] == "request-changes" {
+			participant.State, participant.Approved = participantStateChangesRequested, false
+		}
+		pr.Participants = append(pr.Participants, participant)
+		writeJSON(t, w, http.StatusOK, participant)
+		return
+	case len(parts) >= 2 && parts[1] == "comments":
+		s.handleComments(w, r, parts[2:])
+		return
+	}
+	writeJSON(t, w, http.StatusOK, pr)
+}
+
+func (s *fakeRepoServer) handleComments(w http.ResponseWriter, r *http.Request, parts []string) {
+	t := s.t
+	if len(parts) == 0 {
+		if r.Method == http.MethodGet {
+			writeJSON(t, w, http.StatusOK, map[string]interface{}{"values": s.comments})
+			return
+		}
+		req := &Comment{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			t.Fatal(err)
+		}
+		req.ID = len(s.comments) + 1
+		req.User = &Account{Nickname: "me"}
+		s.comments = append(s.comments, req)
+		writeJSON(t, w, http.StatusCreated, req)
+		return
+	}
+
+	var comment *Comment
+	for _, c := range s.comments {
+		if fmt.Sprint(c.ID) == parts[0] && !c.Deleted {
+			comment = c
+		}
+	}
+	if comment == nil {
+		writeError(t, w, http.StatusNotFound, "Comment not found")
+		return
+	}
+	switch r.Method {
+	case http.MethodPut:
+		req := &Comment{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			t.Fatal(err)
+		}
+		comment.Content = req.Content
+	case http.MethodDelete:
+		comment.Deleted = true
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(t, w, http.StatusOK, comment)
+}
+
+func TestCommitsBranchesPullRequests(t *testing.T) {
+	mux := http.NewServeMux()
+	srv := &fakeRepoServer{t: t, repos: map[string]*Repository{
+		"foo/bar": {Slug: "bar", IsPrivate: gitprovider.BoolVar(true)},
+	}}
+	srv.register(mux)
+	c, url := newTestClient(t, mux)
+	srv.url = url
+	ctx := context.Background()
+
+	repo, err := c.OrgRepositories().Get(ctx, gitprovider.OrgRepositoryRef{
+		OrganizationRef: gitprovider.OrganizationRef{Domain: c.domain, Organization: "foo"},
+		RepositoryName:  "bar",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = repo.Commits().Create(ctx, "master", "empty", nil)
+	if err == nil {
+		t.Error("expected an error when committing no files")
+	}
+
+	commit, err := repo.Commits().Create(ctx, "master", "add file", []gitprovider.CommitFile{{
+		Path:    gitprovider.StringVar("foo.txt"),
+		Content: gitprovider.StringVar("bar"),
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if commit.Get().Sha != srv.commits[0].Hash || srv.commits[0].Message != "add file\nfoo.txt=bar" {
+		t.Errorf("unexpected commit %v, server has %v", commit.Get(), srv.commits)
+	}
+
+	commits, err := repo.Commits().ListPage(ctx, "master", 1, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 1 || commits[0].Get().Sha != commit.Get().Sha {
+		t.Errorf("Commits().ListPage() = %v, want [%v]", commits, commit.Get())
+	}
+
+	if err := repo.Branches().Create(ctx, "feature", commit.Get().Sha); err != nil {
+		t.Fatal(err)
+	}
+	err = repo.Branches().Create(ctx, "feature", commit.Get().Sha)
+	validation.TestExpectErrors(t, "Branches().Create", err, gitprovider.ErrAlreadyExists)
+
+	branch, err := repo.Branches().Get(ctx, "feature")
+	if err != nil || branch.Get().Sha != commit.Get().Sha {
+		t.Errorf("Branches().Get() = %v, %v", branch, err)
+	}
+	_, err = repo.Branches().Get(ctx, "missing")
+	validation.TestExpectErrors(t, "Branches().Get", err, gitprovider.ErrNotFound)
+
+	other, err := repo.Branches().CreateFrom(ctx, "other", "master")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (gitprovider.BranchInfo{Name: "other", Sha: commit.Get().Sha}); other.Get() != want {
+		t.Errorf("Branches().CreateFrom() = %+v, want %+v", other.Get(), want)
+	}
+	_, err = repo.Branches().CreateFrom(ctx, "another", "missing")
+	validation.TestExpectErrors(t, "Branches().CreateFrom", err, gitprovider.ErrNotFound)
+
+	branches, err := repo.Branches().List(ctx)
+	if err != nil || len(branches) != 3 {
+		t.Errorf("Branches().List() = %v, %v, want 3 branches", branches, err)
+	}
+	err = repo.Branches().Delete(ctx, "other")
+	validation.TestExpectErrors(t, "Branches().Delete", err, gitprovider.ErrDestructiveCallDisallowed)
+	dc, _ := newTestClient(t, mux, WithDestructiveAPICalls(true))
+	drepo, err := dc.OrgRepositories().Get(ctx, gitprovider.OrgRepositoryRef{
+		OrganizationRef: gitprovider.OrganizationRef{Domain: dc.domain, Organization: "foo"},
+		RepositoryName:  "bar",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := drepo.Branches().Delete(ctx, "other"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := srv.branches["other"]; ok {
+		t.Error("expected branch other to be deleted")
+	}
+
+	pr, err := repo.PullRequests().Create(ctx, "title", "feature", "master", "description")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := pr.Get().WebURL; got != "https://bitbucket.org/foo/bar/pull-requests/1" {
+		t.Errorf("PullRequest.Get().WebURL = %q", got)
+	}
+	if got := srv.prs[0]; got.Source.Branch.Name != "feature" || got.Destination.Branch.Name != "master" {
+		t.Errorf("unexpected pull request: %+v", got)
+	}
+
+	pr, err = repo.PullRequests().Get(ctx, 1)
+	if err != nil || pr.Get().Number != 1 || pr.Get().State != gitprovider.PullRequestStateOpen || pr.Get().HeadBranch != "feature" {
+		t.Errorf("PullRequests().Get() = %+v, %v", pr, err)
+	}
+	_, err = repo.PullRequests().Get(ctx, 2)
+	validation.TestExpectErrors(t, "PullRequests().Get", err, gitprovider.ErrNotFound)
+
+	pr, err = repo.PullRequests().Update(ctx, 1, gitprovider.PullRequestUpdateOptions{Title: gitprovider.StringVar("new title")})
+	if err != nil || pr.Get().Title != "new title" || pr.Get().Description != "description" || pr.Get().BaseBranch != "master" {
+		t.Errorf("PullRequests().Update() = %+v, %v", pr, err)
+	}
+
+	req := gitprovider.PullRequestInfo{Title: "new title", Description: "description", HeadBranch: "feature", BaseBranch: "master"}
+	pr, actionTaken, err := repo.PullRequests().Reconcile(ctx, req)
+	if err != nil || actionTaken || pr.Get().Number != 1 {
+		t.Errorf("PullRequests().Reconcile() = %+v, %v, %v", pr, actionTaken, err)
+	}
+	req.Labels = []string{"bot"}
+	_, _, err = repo.PullRequests().Reconcile(ctx, req)
+	validation.TestExpectErrors(t, "PullRequests().Reconcile", err, gitprovider.ErrNoProviderSupport)
+
+	comment, err := pr.Comments().Create(ctx, "plan output")
+	if err != nil || comment.Get().ID != 1 || comment.Get().Author != "me" {
+		t.Errorf("Comments().Create() = %+v, %v", comment, err)
+	}
+	srv.comments = append(srv.comments, &Comment{ID: 2, Content: CommentContent{Raw: "inline"}, Inline: json.RawMessage(`{"path":"foo.txt"}`)})
+	if comment, err = pr.Comments().Update(ctx, 1, "new plan output"); err != nil || comment.Get().Body != "new plan output" {
+		t.Errorf("Comments().Update() = %+v, %v", comment, err)
+	}
+	comments, err := pr.Comments().List(ctx)
+	if err != nil || len(comments) != 1 || comments[0].Get().Body != "new plan output" {
+		t.Errorf("Comments().List() = %v, %v", comments, err)
+	}
+	if err := pr.Comments().Delete(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	err = pr.Comments().Delete(ctx, 1)
+	validation.TestExpectErrors(t, "Comments().Delete", err, gitprovider.ErrNotFound)
+
+	review, err := pr.Reviews().Submit(ctx, gitprovider.PullRequestReviewStateApproved, "looks good")
+	if err != nil || review.Get().State != gitprovider.PullRequestReviewStateApproved || review.Get().Body != "looks good" {
+		t.Errorf("Reviews().Submit(approved) = %+v, %v", review, err)
+	}
+	if review, err = pr.Reviews().Submit(ctx, gitprovider.PullRequestReviewStateCommented, "a comment"); err != nil || review.Get().State != gitprovider.PullRequestReviewStateCommented {
+		t.Errorf("Reviews().Submit(commented) = %+v, %v", review, err)
+	}
+	reviews, err := pr.Reviews().List(ctx)
+	if err != nil || len(reviews) != 1 || reviews[0].Get().Author != "me" {
+		t.Errorf("Reviews().List() = %v, %v", reviews, err)
+	}
+
+	err = repo.PullRequests().Merge(ctx, 1, gitprovider.MergeMethodRebase, "")
+	validation.TestExpectErrors(t, "PullRequests().Merge", err, gitprovider.ErrNoProviderSupport)
+	if err := repo.PullRequests().Merge(ctx, 1, gitprovider.MergeMethodSquash, "squashed"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repo.PullRequests().Create(ctx, "other", "other", "master", ""); err != nil {
+		t.Fatal(err)
+	}
+	pr, err = repo.PullRequests().Close(ctx, 2)
+	if err != nil || pr.Get().State != gitprovider.PullRequestStateClosed {
+		t.Errorf("PullRequests().Close() = %+v, %v", pr, err)
+	}
+
+	prs, err := repo.PullRequests().List(ctx, &gitprovider.PullRequestListOptions{State: gitprovider.PullRequestStateVar(gitprovider.PullRequestStateMerged)})
+	if err != nil || len(prs) != 1 || prs[0].Get().Number != 1 {
+		t.Errorf("PullRequests().List(merged) = %v, %v", prs, err)
+	}
+	prs, err = repo.PullRequests().List(ctx, &gitprovider.PullRequestListOptions{HeadBranch: gitprovider.StringVar("other")})
+	if err != nil || len(prs) != 1 || prs[0].Get().Number != 2 {
+		t.Errorf("PullRequests().List(head) = %v, %v", prs, err)
+	}
+	// New branches need a start branch, as the repository isn't empty
+	_, err = repo.Commits().Create(ctx, "changes", "missing branch", []gitprovider.CommitFile{{Path: gitprovider.StringVar("gone.txt")}})
+	validation.TestExpectErrors(t, "Commits().Create() on a missing branch", err, gitprovider.ErrNotFound)
+	// Deleted and moved files are listed as "files"
+	_, err = repo.Commits().Create(ctx, "changes", "change files", []gitprovider.CommitFile{
+		{
+			Path: gitprovider.StringVar("gone.txt"),
+		},
+		{
+			Action:       gitprovider.CommitFileActionVar(gitprovider.CommitFileActionMove),
+			Path:         gitprovider.StringVar("dir/new.txt"),
+			PreviousPath: gitprovider.StringVar("dir/old.txt"),
+		},
+		{
+			Path:     gitprovider.StringVar("bin"),
+			Content:  gitprovider.StringVar("YmluYXJ5"),
+			Encoding: gitprovider.ContentEncodingVar(gitprovider.ContentEncodingBase64),
+		},
+	}, &gitprovider.CommitCreateOptions{StartBranch: gitprovider.StringVar("master")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "change files\nbin=binary\ndir/new.txt=content of dir/old.txt\nfiles=gone.txt\nfiles=dir/old.txt"; srv.commits[0].Message != want {
+		t.Errorf("unexpected commit message %q, want %q", srv.commits[0].Message, want)
+	}
+	_, err = repo.Commits().Create(ctx, "changes", "executable", []gitprovider.CommitFile{{
+		Path:    gitprovider.StringVar("run.sh"),
+		Content: gitprovider.StringVar("echo"),
+		Mode:    gitprovider.FileModeVar(gitprovider.FileModeExecutable),
+	}})
+	validation.TestExpectErrors(t, "Commits().Create", err, gitprovider.ErrNoProviderSupport)
+
+	// The server checks the expected parent
+	files := []gitprovider.CommitFile{{Path: gitprovider.StringVar("foo.txt"), Content: gitprovider.StringVar("baz")}}
+	head := srv.branches["changes"]
+	if _, err := repo.Commits().Create(ctx, "changes", "expected", files, &gitprovider.CommitCreateOptions{ExpectedParentSHA: &head}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = repo.Commits().Create(ctx, "changes", "stale", files, &gitprovider.CommitCreateOptions{ExpectedParentSHA: &head})
+	validation.TestExpectErrors(t, "Commits().Create() on a moved branch", err, gitprovider.ErrConflict)
+
+	// The author is passed in the "Name <email>" format, while the committer can't be set
+	head = srv.branches["changes"]
+	author := &gitprovider.CommitAuthor{Name: "Jane Doe", Email: "jane@example.com"}
+	commit, err = repo.Commits().Create(ctx, "changes", "authored", files, &gitprovider.CommitCreateOptions{Author: author})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := commit.Get(); !reflect.DeepEqual(got.Author, author) || !reflect.DeepEqual(got.ParentShas, []string{head}) {
+		t.Errorf("Commits().Create() = %+v, want author %+v and parent %q", got, author, head)
+	}
+	_, err = repo.Commits().Create(ctx, "changes", "committed", files, &gitprovider.CommitCreateOptions{Committer: author})
+	validation.TestExpectErrors(t, "Commits().Create() with a committer", err, gitprovider.ErrNoProviderSupport)
+}
+
+func TestClient_HasTokenPermission(t *testing.T) {
+	tests := []struct {
+		name         string
+		scopes       string
+		permission   gitprovider.TokenPermission
+		want         bool
+		expectedErrs []error
+	}{
+		{
+			name:       "write scope",
+			scopes:     "account, repository:write",
+			permission: gitprovider.TokenPermissionRWRepository,
+			want:       true,
+		},
+		{
+			name:       "admin scope",
+			scopes:     "repository:admin",
+			permission: gitprovider.TokenPermissionRWRepository,
+			want:       true,
+		},
+		{
+			name:       "read scope only",
+			scopes:     "repository",
+			permission: gitprovider.TokenPermissionRWRepository,
+		},
+		{
+			name:         "missing header",
+			permission:   gitprovider.TokenPermissionRWRepository,
+			expectedErrs: []error{gitprovider.ErrMissingHeader},
+		},
+		{
+			name:         "unknown permission",
+			permission:   gitprovider.TokenPermission(-1),
+			expectedErrs: []error{gitprovider.ErrNoProviderSupport},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/2.0/user", func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("Authorization") != "Bearer token" {
+					t.Errorf("unexpected Authorization header %q", r.Header.Get("Authorization"))
+				}
+				if len(tt.scopes) != 0 {
+					w.Header().Set("X-OAuth-Scopes", tt.scopes)
+				}
+				writeJSON(t, w, http.StatusOK, Account{Nickname: "user"})
+			})
+			c, _ := newTestClient(t, mux, WithOAuth2Token("token"))
+			got, err := c.HasTokenPermission(context.Background(), tt.permission)
+			validation.TestExpectErrors(t, "HasTokenPermission", err, tt.expectedErrs...)
+			if got != tt.want {
+				t.Errorf("HasTokenPermission() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_invalidCredentials(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/2.0/workspaces", func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "user" || pass != "wrong" {
+			t.Errorf("unexpected basic auth %q:%q", user, pass)
+		}
+		writeError(t, w, http.StatusUnauthorized, "Invalid credentials")
+	})
+	c, _ := newTestClient(t, mux, WithAppPassword("user", "wrong"))
+	_, err := c.Organizations().List(context.Background())
+	validation.TestExpectErrors(t, "Organizations().List", err, &gitprovider.InvalidCredentialsError{})
+	var errResp *ErrorResponse
+	if !errors.As(err, &errResp) || errResp.Detail.Message != "Invalid credentials" {
+		t.Errorf("expected the error to wrap the ErrorResponse, got %v", err)
+	}
+}
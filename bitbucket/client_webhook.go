@@ -0,0 +1,62 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitbucket
+
+import (
+	"context"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// WebhookClient implements the gitprovider.WebhookClient interface.
+var _ gitprovider.WebhookClient = &WebhookClient{}
+
+// WebhookClient operates on the webhooks of a specific repository or organization.
+//
+// The Bitbucket Cloud webhooks API isn't wrapped by this package yet.
+// Hence, all methods return ErrNoProviderSupport.
+type WebhookClient struct {
+	*clientContext
+}
+
+// Get returns the webhook delivering to the given URL.
+//
+// This isn't implemented by this package yet.
+func (c *WebhookClient) Get(_ context.Context, _ string) (gitprovider.Webhook, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// List lists all webhooks of the repository or organization.
+//
+// This isn't implemented by this package yet.
+func (c *WebhookClient) List(_ context.Context) ([]gitprovider.Webhook, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// Create creates a webhook with the given specifications.
+//
+// This isn't implemented by this package yet.
+func (c *WebhookClient) Create(_ context.Context, _ gitprovider.WebhookInfo) (gitprovider.Webhook, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// Reconcile makes sure the given desired state (req) becomes the actual state in the backing Git provider.
+//
+// This isn't implemented by this package yet.
+func (c *WebhookClient) Reconcile(_ context.Context, _ gitprovider.WebhookInfo) (gitprovider.Webhook, bool, error) {
+	return nil, false, gitprovider.ErrNoProviderSupport
+}
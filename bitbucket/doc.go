@@ -14,9 +14,7 @@ See the License for the specific language governing permissions and
 limitations under the License.
 */
 
+// Package bitbucket implements the gitprovider interfaces for Bitbucket Cloud (bitbucket.org),
+// using the Bitbucket REST API 2.0. Workspaces are exposed as organizations, and the personal
+// workspace of a user is used for user repositories.
 package bitbucket
-
-import (
-	// TODO: Dummy import until we have the implementation ready.
-	_ "github.com/ktrysmt/go-bitbucket"
-)
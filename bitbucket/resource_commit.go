@@ -0,0 +1,96 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitbucket
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+func newCommit(c *CommitClient, commit *Commit) *commitType {
+	return &commitType{
+		k: *commit,
+		c: c,
+	}
+}
+
+var _ gitprovider.Commit = &commitType{}
+
+type commitType struct {
+	k Commit
+	c *CommitClient
+}
+
+func (c *commitType) Get() gitprovider.CommitInfo {
+	return commitFromAPI(&c.k)
+}
+
+func (c *commitType) APIObject() interface{} {
+	return &c.k
+}
+
+func commitFromAPI(apiObj *Commit) gitprovider.CommitInfo {
+	// The Bitbucket Cloud API doesn't expose the tree nor the committer of a commit
+	info := gitprovider.CommitInfo{
+		Sha:        apiObj.Hash,
+		Message:    apiObj.Message,
+		Author:     commitAuthorFromAPI(apiObj),
+		ParentShas: make([]string, 0, len(apiObj.Parents)),
+	}
+	for _, parent := range apiObj.Parents {
+		info.ParentShas = append(info.ParentShas, parent.Hash)
+	}
+	if apiObj.Links != nil && apiObj.Links.HTML != nil {
+		info.WebURL = apiObj.Links.HTML.Href
+	}
+	return info
+}
+
+// commitAuthorFromAPI returns the author of apiObj, whose raw form is "Name <email>".
+func commitAuthorFromAPI(apiObj *Commit) *gitprovider.CommitAuthor {
+	if len(apiObj.Author.Raw) == 0 {
+		return nil
+	}
+	raw := apiObj.Author.Raw
+	author := &gitprovider.CommitAuthor{Name: raw}
+	if i := strings.LastIndex(raw, " <"); i != -1 && strings.HasSuffix(raw, ">") {
+		author.Name = raw[:i]
+		author.Email = raw[i+2 : len(raw)-1]
+	}
+	if apiObj.Date != nil {
+		author.Date = *apiObj.Date
+	}
+	return author
+}
+
+// commitAuthorToAPI returns the author in the "Name <email>" format Bitbucket Cloud expects.
+func commitAuthorToAPI(author *gitprovider.CommitAuthor) string {
+	return fmt.Sprintf("%s <%s>", author.Name, author.Email)
+}
+
+// validateCommitAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateCommitAPI(apiObj *Commit) error {
+	return validateAPIObject("Bitbucket.Commit", func(validator validation.Validator) {
+		if len(apiObj.Hash) == 0 {
+			validator.Required("Hash")
+		}
+	})
+}
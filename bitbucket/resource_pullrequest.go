@@ -0,0 +1,119 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitbucket
+
+import (
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+func newPullRequest(ctx *clientContext, apiObj *PullRequest, ref gitprovider.RepositoryRef) *pullrequest {
+	return &pullrequest{
+		clientContext: ctx,
+		pr:            *apiObj,
+		comments: &PullRequestCommentClient{
+			clientContext: ctx,
+			ref:           ref,
+			id:            apiObj.ID,
+		},
+		reviews: &PullRequestReviewClient{
+			clientContext: ctx,
+			ref:           ref,
+			id:            apiObj.ID,
+		},
+	}
+}
+
+var _ gitprovider.PullRequest = &pullrequest{}
+
+type pullrequest struct {
+	*clientContext
+
+	pr PullRequest
+
+	comments *PullRequestCommentClient
+	reviews  *PullRequestReviewClient
+}
+
+func (pr *pullrequest) Get() gitprovider.PullRequestInfo {
+	return pullrequestFromAPI(&pr.pr)
+}
+
+func (pr *pullrequest) APIObject() interface{} {
+	return &pr.pr
+}
+
+// Comments gives access to this specific pull request's comments
+func (pr *pullrequest) Comments() gitprovider.PullRequestCommentClient {
+	return pr.comments
+}
+
+// Reviews gives access to this specific pull request's reviews
+func (pr *pullrequest) Reviews() gitprovider.PullRequestReviewClient {
+	return pr.reviews
+}
+
+func pullrequestFromAPI(apiObj *PullRequest) gitprovider.PullRequestInfo {
+	info := gitprovider.PullRequestInfo{
+		Number:      apiObj.ID,
+		Title:       apiObj.Title,
+		Description: apiObj.Description,
+		HeadBranch:  apiObj.Source.Branch.Name,
+		BaseBranch:  apiObj.Destination.Branch.Name,
+		State:       pullRequestStateFromAPI(apiObj.State),
+		Draft:       apiObj.Draft,
+	}
+	if apiObj.Author != nil {
+		info.Author = apiObj.Author.Nickname
+	}
+	if apiObj.CreatedOn != nil {
+		info.CreatedAt = *apiObj.CreatedOn
+	}
+	if apiObj.UpdatedOn != nil {
+		info.UpdatedAt = *apiObj.UpdatedOn
+	}
+	if apiObj.Links != nil && apiObj.Links.HTML != nil {
+		info.WebURL = apiObj.Links.HTML.Href
+	}
+	return info
+}
+
+// pullRequestStateFromAPI maps the Bitbucket states OPEN, MERGED, DECLINED and SUPERSEDED
+// to a PullRequestState.
+func pullRequestStateFromAPI(state string) gitprovider.PullRequestState {
+	switch state {
+	case pullRequestStateOpen:
+		return gitprovider.PullRequestStateOpen
+	case pullRequestStateMerged:
+		return gitprovider.PullRequestStateMerged
+	default:
+		return gitprovider.PullRequestStateClosed
+	}
+}
+
+// validatePullRequestAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validatePullRequestAPI(apiObj *PullRequest) error {
+	return validateAPIObject("Bitbucket.PullRequest", func(validator validation.Validator) {
+		if apiObj.ID == 0 {
+			validator.Required("ID")
+		}
+		if apiObj.State == "" {
+			validator.Required("State")
+		}
+	})
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitbucket
+
+import (
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+func newPullRequestComment(apiObj *Comment) *pullRequestComment {
+	return &pullRequestComment{
+		c: *apiObj,
+	}
+}
+
+var _ gitprovider.PullRequestComment = &pullRequestComment{}
+
+type pullRequestComment struct {
+	c Comment
+}
+
+func (c *pullRequestComment) Get() gitprovider.PullRequestCommentInfo {
+	return pullRequestCommentFromAPI(&c.c)
+}
+
+func (c *pullRequestComment) APIObject() interface{} {
+	return &c.c
+}
+
+func pullRequestCommentFromAPI(apiObj *Comment) gitprovider.PullRequestCommentInfo {
+	info := gitprovider.PullRequestCommentInfo{
+		ID:   int64(apiObj.ID),
+		Body: apiObj.Content.Raw,
+	}
+	if apiObj.User != nil {
+		info.Author = apiObj.User.Nickname
+	}
+	if apiObj.CreatedOn != nil {
+		info.CreatedAt = *apiObj.CreatedOn
+	}
+	if apiObj.UpdatedOn != nil {
+		info.UpdatedAt = *apiObj.UpdatedOn
+	}
+	return info
+}
+
+func validateCommentAPI(apiObj *Comment) error {
+	return validateAPIObject("Bitbucket.Comment", func(validator validation.Validator) {
+		// Make sure the ID is populated, it's needed to update and delete the comment
+		if apiObj.ID == 0 {
+			validator.Required("ID")
+		}
+	})
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitbucket
+
+import (
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+const (
+	participantStateApproved         = "approved"
+	participantStateChangesRequested = "changes_requested"
+)
+
+// newParticipantReview returns the review of the given participant, with the body of the
+// comment added along with it, if any.
+func newParticipantReview(participant *Participant, comment *Comment) *pullRequestReview {
+	info := gitprovider.PullRequestReviewInfo{
+		State:       gitprovider.PullRequestReviewStateApproved,
+		SubmittedAt: participant.ParticipatedOn,
+	}
+	if participant.State == participantStateChangesRequested {
+		info.State = gitprovider.PullRequestReviewStateChangesRequested
+	}
+	if participant.User != nil {
+		info.Author = participant.User.Nickname
+	}
+	if comment != nil {
+		info.Body = comment.Content.Raw
+	}
+	return &pullRequestReview{info: info, apiObj: participant}
+}
+
+// newCommentReview returns a commenting review for the given comment.
+func newCommentReview(comment *Comment) *pullRequestReview {
+	info := gitprovider.PullRequestReviewInfo{
+		ID:          int64(comment.ID),
+		State:       gitprovider.PullRequestReviewStateCommented,
+		Body:        comment.Content.Raw,
+		SubmittedAt: comment.CreatedOn,
+	}
+	if comment.User != nil {
+		info.Author = comment.User.Nickname
+	}
+	return &pullRequestReview{info: info, apiObj: comment}
+}
+
+var _ gitprovider.PullRequestReview = &pullRequestReview{}
+
+// pullRequestReview is a participant's verdict or a comment, as Bitbucket doesn't have reviews.
+type pullRequestReview struct {
+	info gitprovider.PullRequestReviewInfo
+	// apiObj is a *Participant or a *Comment
+	apiObj interface{}
+}
+
+func (r *pullRequestReview) Get() gitprovider.PullRequestReviewInfo {
+	return r.info
+}
+
+func (r *pullRequestReview) APIObject() interface{} {
+	return r.apiObj
+}
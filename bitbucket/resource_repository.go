@@ -0,0 +1,336 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitbucket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+func newUserRepository(ctx *clientContext, apiObj *Repository, ref gitprovider.RepositoryRef) *userRepository {
+	return &userRepository{
+		clientContext: ctx,
+		r:             *apiObj,
+		ref:           ref,
+		deployKeys: &DeployKeyClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		commits: &CommitClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		branches: &BranchClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		files: &FileClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		branchProtections: &BranchProtectionClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		pullRequests: &PullRequestClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		tags: &TagClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		releases: &ReleaseClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		webhooks: &WebhookClient{
+			clientContext: ctx,
+		},
+		commitStatuses: &CommitStatusClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		issues: &IssueClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+	}
+}
+
+var _ gitprovider.UserRepository = &userRepository{}
+
+type userRepository struct {
+	*clientContext
+
+	r   Repository
+	ref gitprovider.RepositoryRef
+
+	deployKeys        *DeployKeyClient
+	commits           *CommitClient
+	branches          *BranchClient
+	files             *FileClient
+	branchProtections *BranchProtectionClient
+	pullRequests      *PullRequestClient
+	tags              *TagClient
+	releases          *ReleaseClient
+	webhooks          *WebhookClient
+	commitStatuses    *CommitStatusClient
+	issues            *IssueClient
+}
+
+func (r *userRepository) Get() gitprovider.RepositoryInfo {
+	return repositoryFromAPI(&r.r)
+}
+
+func (r *userRepository) Set(info gitprovider.RepositoryInfo) error {
+	if err := info.ValidateInfo(); err != nil {
+		return err
+	}
+	return repositoryInfoToAPIObj(&info, &r.r)
+}
+
+func (r *userRepository) APIObject() interface{} {
+	return &r.r
+}
+
+func (r *userRepository) Repository() gitprovider.RepositoryRef {
+	return r.ref
+}
+
+func (r *userRepository) DeployKeys() gitprovider.DeployKeyClient {
+	return r.deployKeys
+}
+
+func (r *userRepository) Commits() gitprovider.CommitClient {
+	return r.commits
+}
+
+func (r *userRepository) Branches() gitprovider.BranchClient {
+	return r.branches
+}
+
+func (r *userRepository) Files() gitprovider.FileClient {
+	return r.files
+}
+
+func (r *userRepository) BranchProtections() gitprovider.BranchProtectionClient {
+	return r.branchProtections
+}
+
+func (r *userRepository) PullRequests() gitprovider.PullRequestClient {
+	return r.pullRequests
+}
+
+func (r *userRepository) Tags() gitprovider.TagClient {
+	return r.tags
+}
+
+func (r *userRepository) Releases() gitprovider.ReleaseClient {
+	return r.releases
+}
+
+func (r *userRepository) Webhooks() gitprovider.WebhookClient {
+	return r.webhooks
+}
+
+func (r *userRepository) CommitStatuses() gitprovider.CommitStatusClient {
+	return r.commitStatuses
+}
+
+func (r *userRepository) Issues() gitprovider.IssueClient {
+	return r.issues
+}
+
+// Update will apply the desired state in this object to the server.
+// Only set fields will be respected (i.e. PATCH behaviour).
+// In order to apply changes to this object, use the .Set({Resource}Info) error
+// function, or cast .APIObject() to a pointer to the provider-specific type
+// and set custom fields there.
+//
+// ErrNotFound is returned if the resource does not exist.
+//
+// The internal API object will be overridden with the received server data.
+func (r *userRepository) Update(ctx context.Context) error {
+	// PUT /repositories/{workspace}/{repo_slug}
+	apiObj, err := r.c.UpdateRepo(ctx, r.ref.GetIdentity(), r.ref.GetRepository(), newBitbucketRepositorySpec(&r.r).Repository)
+	if err != nil {
+		return err
+	}
+	r.r = *apiObj
+	return nil
+}
+
+// Reconcile makes sure the desired state in this object (called "req" here) becomes
+// the actual state in the backing Git provider.
+//
+// If req doesn't exist under the hood, it is created (actionTaken == true).
+// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+// If req is already the actual state, this is a no-op (actionTaken == false).
+//
+// The internal API object will be overridden with the received server data if actionTaken == true.
+func (r *userRepository) Reconcile(ctx context.Context) (bool, error) {
+	apiObj, err := r.c.GetRepo(ctx, r.ref.GetIdentity(), r.ref.GetRepository())
+	if err != nil {
+		// Create if not found
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			repo, err := r.c.CreateRepo(ctx, r.ref.GetIdentity(), r.ref.GetRepository(), newBitbucketRepositorySpec(&r.r).Repository)
+			if err != nil {
+				return true, err
+			}
+			r.r = *repo
+			return true, nil
+		}
+
+		return false, err
+	}
+
+	// Use wrappers here to extract the "spec" part of the object for comparison
+	desiredSpec := newBitbucketRepositorySpec(&r.r)
+	actualSpec := newBitbucketRepositorySpec(apiObj)
+
+	// If desired state already is the actual state, do nothing
+	if desiredSpec.Equals(actualSpec) {
+		return false, nil
+	}
+	// Otherwise, make the desired state the actual state
+	return true, r.Update(ctx)
+}
+
+// Delete deletes the current resource irreversibly.
+//
+// ErrNotFound is returned if the resource doesn't exist anymore.
+func (r *userRepository) Delete(ctx context.Context) error {
+	// DELETE /repositories/{workspace}/{repo_slug}
+	return r.c.DeleteRepo(ctx, r.ref.GetIdentity(), r.ref.GetRepository())
+}
+
+func newOrgRepository(ctx *clientContext, apiObj *Repository, ref gitprovider.RepositoryRef) *orgRepository {
+	return &orgRepository{
+		userRepository: *newUserRepository(ctx, apiObj, ref),
+		teamAccess: &TeamAccessClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+	}
+}
+
+var _ gitprovider.OrgRepository = &orgRepository{}
+
+type orgRepository struct {
+	userRepository
+
+	teamAccess *TeamAccessClient
+}
+
+func (r *orgRepository) TeamAccess() gitprovider.TeamAccessClient {
+	return r.teamAccess
+}
+
+// validateRepositoryAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateRepositoryAPI(apiObj *Repository) error {
+	return validateAPIObject("Bitbucket.Repository", func(validator validation.Validator) {
+		// Make sure slug is set, as that's what we use to refer to the repository
+		if len(apiObj.Slug) == 0 {
+			validator.Required("Slug")
+		}
+		// Make sure visibility can be derived
+		if apiObj.IsPrivate == nil {
+			validator.Required("IsPrivate")
+		}
+		// Set default branch to master if unset, which is the case for empty repositories
+		if apiObj.MainBranch == nil {
+			apiObj.MainBranch = &BranchRef{Name: "master"}
+		}
+	})
+}
+
+func repositoryFromAPI(apiObj *Repository) gitprovider.RepositoryInfo {
+	repo := gitprovider.RepositoryInfo{
+		Description: apiObj.Description,
+	}
+	if apiObj.MainBranch != nil {
+		repo.DefaultBranch = gitprovider.StringVar(apiObj.MainBranch.Name)
+	}
+	if apiObj.IsPrivate != nil {
+		visibility := gitprovider.RepositoryVisibilityPublic
+		if *apiObj.IsPrivate {
+			visibility = gitprovider.RepositoryVisibilityPrivate
+		}
+		repo.Visibility = gitprovider.RepositoryVisibilityVar(visibility)
+	}
+	return repo
+}
+
+func repositoryToAPI(repo *gitprovider.RepositoryInfo) (*Repository, error) {
+	apiObj := &Repository{
+		SCM: "git",
+	}
+	if err := repositoryInfoToAPIObj(repo, apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func repositoryInfoToAPIObj(repo *gitprovider.RepositoryInfo, apiObj *Repository) error {
+	if repo.Description != nil {
+		apiObj.Description = repo.Description
+	}
+	if repo.DefaultBranch != nil {
+		apiObj.MainBranch = &BranchRef{Name: *repo.DefaultBranch}
+	}
+	if repo.Visibility != nil {
+		switch *repo.Visibility {
+		case gitprovider.RepositoryVisibilityPrivate:
+			apiObj.IsPrivate = gitprovider.BoolVar(true)
+		case gitprovider.RepositoryVisibilityPublic:
+			apiObj.IsPrivate = gitprovider.BoolVar(false)
+		default:
+			return fmt.Errorf("bitbucket doesn't support %q repositories: %w", *repo.Visibility, gitprovider.ErrNoProviderSupport)
+		}
+	}
+	return nil
+}
+
+// This function copies over the fields that are part of create/update requests of a repository
+// i.e. the desired spec of the repository. This allows us to separate "spec" from "status" fields.
+// See also: https://developer.atlassian.com/bitbucket/api/2/reference/resource/repositories/%7Bworkspace%7D/%7Brepo_slug%7D#put
+func newBitbucketRepositorySpec(repo *Repository) *bitbucketRepositorySpec {
+	return &bitbucketRepositorySpec{
+		&Repository{
+			SCM:         repo.SCM,
+			Description: repo.Description,
+			IsPrivate:   repo.IsPrivate,
+			MainBranch:  repo.MainBranch,
+			Project:     repo.Project,
+		},
+	}
+}
+
+type bitbucketRepositorySpec struct {
+	*Repository
+}
+
+func (s *bitbucketRepositorySpec) Equals(other *bitbucketRepositorySpec) bool {
+	return reflect.DeepEqual(s, other)
+}
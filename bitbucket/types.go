@@ -0,0 +1,215 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bitbucket
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// The structs in this file are the Bitbucket Cloud API 2.0 objects returned from the server,
+// and what .APIObject() returns for the resources of this package. Only fields that are used
+// by this library, or are likely to be useful for consumers, are modelled.
+// See: https://developer.atlassian.com/bitbucket/api/2/reference/resource/
+
+// Link is a hypermedia link to a resource.
+type Link struct {
+	Href string `json:"href"`
+	Name string `json:"name,omitempty"`
+}
+
+// Links contains the links that Bitbucket returns for most objects.
+type Links struct {
+	Self  *Link  `json:"self,omitempty"`
+	HTML  *Link  `json:"html,omitempty"`
+	Clone []Link `json:"clone,omitempty"`
+}
+
+// Workspace represents a Bitbucket Cloud workspace, the equivalent of an organization.
+type Workspace struct {
+	UUID      string `json:"uuid,omitempty"`
+	Slug      string `json:"slug"`
+	Name      string `json:"name,omitempty"`
+	IsPrivate bool   `json:"is_private,omitempty"`
+	Links     *Links `json:"links,omitempty"`
+}
+
+// Account represents a Bitbucket Cloud user account.
+type Account struct {
+	UUID        string `json:"uuid,omitempty"`
+	Nickname    string `json:"nickname,omitempty"`
+	DisplayName string `json:"display_name,omitempty"`
+	AccountID   string `json:"account_id,omitempty"`
+}
+
+// BranchRef is a reference to a branch by name.
+type BranchRef struct {
+	Name string `json:"name"`
+}
+
+// Project represents the project a repository belongs to within a workspace.
+type Project struct {
+	Key  string `json:"key"`
+	Name string `json:"name,omitempty"`
+}
+
+// Repository represents a Bitbucket Cloud repository.
+type Repository struct {
+	UUID        string     `json:"uuid,omitempty"`
+	Slug        string     `json:"slug,omitempty"`
+	Name        string     `json:"name,omitempty"`
+	FullName    string     `json:"full_name,omitempty"`
+	SCM         string     `json:"scm,omitempty"`
+	Description *string    `json:"description,omitempty"`
+	IsPrivate   *bool      `json:"is_private,omitempty"`
+	MainBranch  *BranchRef `json:"mainbranch,omitempty"`
+	Project     *Project   `json:"project,omitempty"`
+	Workspace   *Workspace `json:"workspace,omitempty"`
+	Owner       *Account   `json:"owner,omitempty"`
+	Links       *Links     `json:"links,omitempty"`
+	CreatedOn   *time.Time `json:"created_on,omitempty"`
+	UpdatedOn   *time.Time `json:"updated_on,omitempty"`
+}
+
+// DeployKey represents a Bitbucket Cloud (always read-only) repository access key.
+type DeployKey struct {
+	ID        int        `json:"id,omitempty"`
+	Key       string     `json:"key"`
+	Label     string     `json:"label"`
+	Comment   string     `json:"comment,omitempty"`
+	CreatedOn *time.Time `json:"created_on,omitempty"`
+	LastUsed  *time.Time `json:"last_used,omitempty"`
+}
+
+// CommitAuthor is the author of a commit.
+type CommitAuthor struct {
+	Raw  string   `json:"raw"`
+	User *Account `json:"user,omitempty"`
+}
+
+// Commit represents a Bitbucket Cloud commit.
+type Commit struct {
+	Hash    string       `json:"hash"`
+	Message string       `json:"message,omitempty"`
+	Date    *time.Time   `json:"date,omitempty"`
+	Author  CommitAuthor `json:"author,omitempty"`
+	Parents []Commit     `json:"parents,omitempty"`
+	Links   *Links       `json:"links,omitempty"`
+}
+
+// Branch represents a Bitbucket Cloud branch.
+type Branch struct {
+	Name   string  `json:"name"`
+	Target *Commit `json:"target,omitempty"`
+	Links  *Links  `json:"links,omitempty"`
+}
+
+// PullRequestEndpoint is the source or destination of a pull request.
+type PullRequestEndpoint struct {
+	Branch     BranchRef   `json:"branch"`
+	Commit     *Commit     `json:"commit,omitempty"`
+	Repository *Repository `json:"repository,omitempty"`
+}
+
+// PullRequest represents a Bitbucket Cloud pull request.
+type PullRequest struct {
+	ID          int                 `json:"id,omitempty"`
+	Title       string              `json:"title"`
+	Description string              `json:"description,omitempty"`
+	State       string              `json:"state,omitempty"`
+	Source      PullRequestEndpoint `json:"source"`
+	Destination PullRequestEndpoint `json:"destination"`
+	Author      *Account            `json:"author,omitempty"`
+	Draft       bool                `json:"draft,omitempty"`
+	MergeCommit *Commit             `json:"merge_commit,omitempty"`
+	ClosedBy    *Account            `json:"closed_by,omitempty"`
+	// Participants are the reviewers and other users who took part in the pull request.
+	Participants []*Participant `json:"participants,omitempty"`
+	Links        *Links         `json:"links,omitempty"`
+	CreatedOn    *time.Time     `json:"created_on,omitempty"`
+	UpdatedOn    *time.Time     `json:"updated_on,omitempty"`
+}
+
+// Participant is a user taking part in a pull request. State is "approved",
+// "changes_requested", or empty if the user didn't review the pull request.
+type Participant struct {
+	User           *Account   `json:"user,omitempty"`
+	Role           string     `json:"role,omitempty"`
+	Approved       bool       `json:"approved"`
+	State          string     `json:"state,omitempty"`
+	ParticipatedOn *time.Time `json:"participated_on,omitempty"`
+}
+
+// Comment represents a pull request comment. Inline is set for comments on a line of the diff.
+type Comment struct {
+	ID        int             `json:"id,omitempty"`
+	Content   CommentContent  `json:"content"`
+	User      *Account        `json:"user,omitempty"`
+	Inline    json.RawMessage `json:"inline,omitempty"`
+	Deleted   bool            `json:"deleted,omitempty"`
+	CreatedOn *time.Time      `json:"created_on,omitempty"`
+	UpdatedOn *time.Time      `json:"updated_on,omitempty"`
+}
+
+// CommentContent is the content of a comment. Raw is the Markdown source.
+type CommentContent struct {
+	Raw string `json:"raw"`
+}
+
+// PullRequestMerge is the request body for merging a pull request.
+type PullRequestMerge struct {
+	Message       string `json:"message,omitempty"`
+	MergeStrategy string `json:"merge_strategy"`
+}
+
+// paginatedResponse is the envelope used by Bitbucket for all list responses. Values is decoded
+// separately by the caller, as its type depends on the endpoint.
+type paginatedResponse struct {
+	Next string `json:"next"`
+}
+
+// ErrorResponse is the error returned from the server when a request fails.
+// See: https://developer.atlassian.com/bitbucket/api/2/reference/meta/uri-uuid#errors
+type ErrorResponse struct {
+	// Response is the HTTP response that caused this error.
+	Response *http.Response `json:"-"`
+	// Type is always "error".
+	Type string `json:"type"`
+	// Detail contains the error message and additional context.
+	Detail ErrorDetail `json:"error"`
+}
+
+// ErrorDetail describes what went wrong in a failed request.
+type ErrorDetail struct {
+	Message string              `json:"message"`
+	Detail  string              `json:"detail,omitempty"`
+	Fields  map[string][]string `json:"fields,omitempty"`
+}
+
+// Error implements the error interface.
+func (e *ErrorResponse) Error() string {
+	msg := fmt.Sprintf("%s %s: %d", e.Response.Request.Method, e.Response.Request.URL, e.Response.StatusCode)
+	if len(e.Detail.Message) != 0 {
+		msg = fmt.Sprintf("%s %s", msg, e.Detail.Message)
+	}
+	if len(e.Detail.Fields) != 0 {
+		msg = fmt.Sprintf("%s %v", msg, e.Detail.Fields)
+	}
+	return msg
+}
@@ -0,0 +1,288 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/gitprovider/cache"
+)
+
+const (
+	// TokenVariable is the common name for the environment variable
+	// containing a Gitea access token.
+	TokenVariable = "GITEA_TOKEN" // #nosec G101
+)
+
+// ClientOption is the interface to implement for passing options to NewClient.
+// The clientOptions struct is private to force usage of the With... functions.
+type ClientOption interface {
+	// ApplyToGiteaClientOptions applies set fields of this object into target.
+	ApplyToGiteaClientOptions(target *clientOptions) error
+}
+
+// clientOptions is the struct that tracks data about what options have been set.
+type clientOptions struct {
+	// clientOptions shares all the common options
+	gitprovider.CommonClientOptions
+
+	// AuthTransport is a ChainableRoundTripperFunc adding authentication credentials to the transport chain.
+	AuthTransport gitprovider.ChainableRoundTripperFunc
+
+	// EnableConditionalRequests will be set if conditional requests should be used.
+	// Default: false
+	EnableConditionalRequests *bool
+}
+
+// ApplyToGiteaClientOptions implements ClientOption, and applies the set fields of opts
+// into target. If both opts and target has the same specific field set, ErrInvalidClientOptions is returned.
+func (opts *clientOptions) ApplyToGiteaClientOptions(target *clientOptions) error {
+	// Apply common values, if any
+	if err := opts.CommonClientOptions.ApplyToCommonClientOptions(&target.CommonClientOptions); err != nil {
+		return err
+	}
+
+	if opts.AuthTransport != nil {
+		// Make sure the user didn't specify the AuthTransport twice
+		if target.AuthTransport != nil {
+			return fmt.Errorf("option AuthTransport already configured: %w", gitprovider.ErrInvalidClientOptions)
+		}
+		target.AuthTransport = opts.AuthTransport
+	}
+
+	if opts.EnableConditionalRequests != nil {
+		// Make sure the user didn't specify the EnableConditionalRequests twice
+		if target.EnableConditionalRequests != nil {
+			return fmt.Errorf("option EnableConditionalRequests already configured: %w", gitprovider.ErrInvalidClientOptions)
+		}
+		target.EnableConditionalRequests = opts.EnableConditionalRequests
+	}
+	return nil
+}
+
+// getTransportChain builds the full chain of transports (from left to right,
+// as per gitprovider.BuildClientFromTransportChain) of the form described in NewClient.
+func (opts *clientOptions) getTransportChain() (chain []gitprovider.ChainableRoundTripperFunc) {
+	if opts.PostChainTransportHook != nil {
+		chain = append(chain, opts.PostChainTransportHook)
+	}
+	if opts.AuthTransport != nil {
+		chain = append(chain, opts.AuthTransport)
+	}
+	if opts.EnableConditionalRequests != nil && *opts.EnableConditionalRequests {
+		chain = append(chain, cache.NewHTTPCacheTransport)
+	}
+	if opts.PreChainTransportHook != nil {
+		chain = append(chain, opts.PreChainTransportHook)
+	}
+	return
+}
+
+// buildCommonOption is a helper for returning a ClientOption out of a common option field.
+func buildCommonOption(opt gitprovider.CommonClientOptions) *clientOptions {
+	return &clientOptions{CommonClientOptions: opt}
+}
+
+// errorOption implements ClientOption, and just wraps an error which is immediately returned.
+// This struct can be used through the optionError function, in order to make makeOptions fail
+// if there are invalid options given to the With... functions.
+type errorOption struct {
+	err error
+}
+
+// ApplyToGiteaClientOptions implements ClientOption, but just returns the internal error.
+func (e *errorOption) ApplyToGiteaClientOptions(*clientOptions) error { return e.err }
+
+// optionError is a constructor for errorOption.
+func optionError(err error) ClientOption {
+	return &errorOption{err}
+}
+
+//
+// Common options
+//
+
+// WithDomain initializes a Client for the Gitea instance at the given domain, e.g.
+// "gitea.example.com" or "https://example.com:3000". If domain doesn't contain a scheme,
+// https is used. domain must not be an empty string. This option is required.
+func WithDomain(domain string) ClientOption {
+	return buildCommonOption(gitprovider.CommonClientOptions{Domain: &domain})
+}
+
+// WithDestructiveAPICalls tells the client whether it's allowed to do dangerous and possibly destructive
+// actions, like e.g. deleting a repository.
+func WithDestructiveAPICalls(destructiveActions bool) ClientOption {
+	return buildCommonOption(gitprovider.CommonClientOptions{EnableDestructiveAPICalls: &destructiveActions})
+}
+
+// WithPreChainTransportHook registers a ChainableRoundTripperFunc "before" the cache and authentication
+// transports in the chain. For more information, see NewClient, and gitprovider.CommonClientOptions.PreChainTransportHook.
+func WithPreChainTransportHook(preRoundTripperFunc gitprovider.ChainableRoundTripperFunc) ClientOption {
+	// Don't allow an empty value
+	if preRoundTripperFunc == nil {
+		return optionError(fmt.Errorf("preRoundTripperFunc cannot be nil: %w", gitprovider.ErrInvalidClientOptions))
+	}
+
+	return buildCommonOption(gitprovider.CommonClientOptions{PreChainTransportHook: preRoundTripperFunc})
+}
+
+// WithPostChainTransportHook registers a ChainableRoundTripperFunc "after" the cache and authentication
+// transports in the chain. For more information, see NewClient, and gitprovider.CommonClientOptions.WithPostChainTransportHook.
+func WithPostChainTransportHook(postRoundTripperFunc gitprovider.ChainableRoundTripperFunc) ClientOption {
+	// Don't allow an empty value
+	if postRoundTripperFunc == nil {
+		return optionError(fmt.Errorf("postRoundTripperFunc cannot be nil: %w", gitprovider.ErrInvalidClientOptions))
+	}
+
+	return buildCommonOption(gitprovider.CommonClientOptions{PostChainTransportHook: postRoundTripperFunc})
+}
+
+//
+// Gitea-specific options
+//
+
+// WithToken initializes a Client which authenticates with Gitea through an access token,
+// sent in the "Authorization: token" header. token must not be an empty string.
+// See: https://docs.gitea.com/development/api-usage#authentication
+func WithToken(token string) ClientOption {
+	// Don't allow an empty value
+	if len(token) == 0 {
+		return optionError(fmt.Errorf("token cannot be empty: %w", gitprovider.ErrInvalidClientOptions))
+	}
+
+	return &clientOptions{AuthTransport: tokenTransport(token)}
+}
+
+func tokenTransport(token string) gitprovider.ChainableRoundTripperFunc {
+	return func(in http.RoundTripper) http.RoundTripper {
+		if in == nil {
+			in = http.DefaultTransport
+		}
+		return &tokenRoundTripper{token: token, transport: in}
+	}
+}
+
+// tokenRoundTripper sets the token authorization header on a copy of every request.
+type tokenRoundTripper struct {
+	token     string
+	transport http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (rt *tokenRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	// As per the http.RoundTripper contract, the request must not be modified
+	authReq := req.Clone(req.Context())
+	authReq.Header.Set("Authorization", "token "+rt.token)
+	return rt.transport.RoundTrip(authReq)
+}
+
+// WithBasicAuth initializes a Client which authenticates with Gitea through HTTP basic
+// authentication, using the given username and password (or access token). Neither may
+// be an empty string.
+func WithBasicAuth(username, password string) ClientOption {
+	// Don't allow empty values
+	if len(username) == 0 || len(password) == 0 {
+		return optionError(fmt.Errorf("username and password cannot be empty: %w", gitprovider.ErrInvalidClientOptions))
+	}
+
+	return &clientOptions{AuthTransport: basicAuthTransport(username, password)}
+}
+
+func basicAuthTransport(username, password string) gitprovider.ChainableRoundTripperFunc {
+	return func(in http.RoundTripper) http.RoundTripper {
+		if in == nil {
+			in = http.DefaultTransport
+		}
+		return &basicAuthRoundTripper{username: username, password: password, transport: in}
+	}
+}
+
+// basicAuthRoundTripper sets the basic auth header on a copy of every request.
+type basicAuthRoundTripper struct {
+	username  string
+	password  string
+	transport http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (rt *basicAuthRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	// As per the http.RoundTripper contract, the request must not be modified
+	authReq := req.Clone(req.Context())
+	authReq.SetBasicAuth(rt.username, rt.password)
+	return rt.transport.RoundTrip(authReq)
+}
+
+// WithConditionalRequests instructs the client to use Conditional Requests to Gitea, asking
+// whether a resource has changed (using the ETag of the earlier response), and using an in-memory
+// cached "database" if not.
+func WithConditionalRequests(conditionalRequests bool) ClientOption {
+	return &clientOptions{EnableConditionalRequests: &conditionalRequests}
+}
+
+// makeOptions assembles a clientOptions struct from ClientOption mutator functions.
+func makeOptions(opts ...ClientOption) (*clientOptions, error) {
+	o := &clientOptions{}
+	for _, opt := range opts {
+		if err := opt.ApplyToGiteaClientOptions(o); err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+// NewClient creates a new gitprovider.Client instance for Gitea (and Forgejo) API endpoints.
+//
+// The domain of the server must be given using WithDomain, as there is no default.
+//
+// Using WithToken or WithBasicAuth you can specify authentication
+// credentials, passing no such ClientOption will allow public read access only.
+//
+// You can customize low-level HTTP Transport functionality by using the With{Pre,Post}ChainTransportHook options.
+// You can also use conditional requests (and an in-memory cache) using WithConditionalRequests.
+//
+// The chain of transports looks like this:
+// Gitea API <-> "Post Chain" <-> Authentication <-> Cache <-> "Pre Chain" <-> *http.Client.
+func NewClient(optFns ...ClientOption) (gitprovider.Client, error) {
+	// Complete the options struct
+	opts, err := makeOptions(optFns...)
+	if err != nil {
+		return nil, err
+	}
+
+	// There is no default domain for self-hosted servers
+	if opts.Domain == nil {
+		return nil, fmt.Errorf("option Domain is required: %w", gitprovider.ErrInvalidClientOptions)
+	}
+
+	// Create a *http.Client using the transport chain
+	httpClient, err := gitprovider.BuildClientFromTransportChain(opts.getTransportChain())
+	if err != nil {
+		return nil, err
+	}
+
+	// By default, turn destructive actions off. But allow overrides.
+	destructiveActions := false
+	if opts.EnableDestructiveAPICalls != nil {
+		destructiveActions = *opts.EnableDestructiveAPICalls
+	}
+
+	domainURL := strings.TrimSuffix(gitprovider.GetDomainURL(*opts.Domain), "/")
+	return newClient(httpClient, domainURL, *opts.Domain, destructiveActions), nil
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"context"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// BranchClient implements the gitprovider.BranchClient interface.
+var _ gitprovider.BranchClient = &BranchClient{}
+
+// BranchClient operates on the branch for a specific repository.
+type BranchClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// Get returns the branch with the given name.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *BranchClient) Get(ctx context.Context, branch string) (gitprovider.Branch, error) {
+	// GET /repos/{owner}/{repo}/branches/{branch}
+	apiObj, err := c.c.GetBranch(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), branch)
+	if err != nil {
+		return nil, err
+	}
+	return newBranch(apiObj), nil
+}
+
+// List lists all branches of the repository.
+//
+// List returns all available branches, using multiple paginated requests if needed.
+func (c *BranchClient) List(ctx context.Context) ([]gitprovider.Branch, error) {
+	// GET /repos/{owner}/{repo}/branches
+	apiObjs, err := c.c.ListBranches(ctx, c.ref.GetIdentity(), c.ref.GetRepository())
+	if err != nil {
+		return nil, err
+	}
+
+	branches := make([]gitprovider.Branch, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		branches = append(branches, newBranch(apiObj))
+	}
+	return branches, nil
+}
+
+// Create creates a branch with the given specifications.
+// Creating a branch from a commit SHA requires Gitea 1.22 or later.
+func (c *BranchClient) Create(ctx context.Context, branch, sha string) error {
+	// POST /repos/{owner}/{repo}/branches
+	if _, err := c.c.CreateBranch(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), branch, sha); err != nil {
+		return err
+	}
+	return nil
+}
+
+// CreateFrom creates a branch starting at the head of another branch, or at a tag.
+//
+// ErrNotFound is returned if from doesn't exist, and ErrAlreadyExists if branch does.
+func (c *BranchClient) CreateFrom(ctx context.Context, branch, from string) (gitprovider.Branch, error) {
+	// POST /repos/{owner}/{repo}/branches
+	apiObj, err := c.c.CreateBranch(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), branch, from)
+	if err != nil {
+		return nil, err
+	}
+	return newBranch(apiObj), nil
+}
+
+// Delete deletes the branch with the given name.
+//
+// ErrDestructiveCallDisallowed is returned if the client wasn't set up with destructive
+// API calls enabled. ErrNotFound is returned if the resource does not exist.
+func (c *BranchClient) Delete(ctx context.Context, branch string) error {
+	// DELETE /repos/{owner}/{repo}/branches/{branch}
+	return c.c.DeleteBranch(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), branch)
+}
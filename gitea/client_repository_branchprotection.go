@@ -0,0 +1,105 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"context"
+	"errors"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// BranchProtectionClient implements the gitprovider.BranchProtectionClient interface.
+var _ gitprovider.BranchProtectionClient = &BranchProtectionClient{}
+
+// BranchProtectionClient operates on the branch protection rules for a specific repository.
+type BranchProtectionClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// Get returns the protection rules of the branch with the given name.
+//
+// ErrNotFound is returned if the branch isn't protected.
+func (c *BranchProtectionClient) Get(ctx context.Context, branch string) (gitprovider.BranchProtection, error) {
+	return c.get(ctx, branch)
+}
+
+func (c *BranchProtectionClient) get(ctx context.Context, branch string) (*branchProtection, error) {
+	// GET /repos/{owner}/{repo}/branch_protections/{name}
+	apiObj, err := c.c.GetBranchProtection(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), branch)
+	if err != nil {
+		return nil, err
+	}
+	return newBranchProtection(c, apiObj), nil
+}
+
+// Reconcile makes sure the given desired state (req) becomes the actual state in the backing Git provider.
+//
+// If req doesn't exist under the hood, it is created (actionTaken == true).
+// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+// If req is already the actual state, this is a no-op (actionTaken == false).
+//
+// ErrNoProviderSupport is returned if req requires code owner reviews, or allows force-pushes
+// or deletions.
+func (c *BranchProtectionClient) Reconcile(ctx context.Context, req gitprovider.BranchProtectionInfo) (gitprovider.BranchProtection, bool, error) {
+	// First thing, validate and default the request to ensure a valid and fully-populated object
+	// (to minimize any possible diffs between desired and actual state)
+	if err := gitprovider.ValidateAndDefaultInfo(&req); err != nil {
+		return nil, false, err
+	}
+
+	actual, err := c.get(ctx, req.Branch)
+	if err != nil {
+		// Create if not found
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			// Gitea allows protecting branches that don't exist (yet), unlike the other providers
+			// GET /repos/{owner}/{repo}/branches/{branch}
+			if _, err := c.c.GetBranch(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), req.Branch); err != nil {
+				return nil, false, err
+			}
+			bp := newBranchProtection(c, &BranchProtection{})
+			if err := bp.Set(req); err != nil {
+				return nil, false, err
+			}
+			return bp, true, bp.createIntoSelf(ctx)
+		}
+
+		// Unexpected path, Get should succeed or return NotFound
+		return nil, false, err
+	}
+
+	// If the desired matches the actual state, just return the actual state
+	if req.Equals(actual.Get()) {
+		return actual, false, nil
+	}
+
+	// Populate the desired state to the current-actual object
+	if err := actual.Set(req); err != nil {
+		return actual, false, err
+	}
+	// Apply the desired state by running Update
+	return actual, true, actual.Update(ctx)
+}
+
+// Delete removes the protection rules of the branch with the given name.
+//
+// ErrNotFound is returned if the branch isn't protected.
+func (c *BranchProtectionClient) Delete(ctx context.Context, branch string) error {
+	// DELETE /repos/{owner}/{repo}/branch_protections/{name}
+	return c.c.DeleteBranchProtection(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), branch)
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// CommitClient implements the gitprovider.CommitClient interface.
+var _ gitprovider.CommitClient = &CommitClient{}
+
+// CommitClient operates on the commits for a specific repository.
+type CommitClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// ListPage lists all repository commits of the given page and page size.
+// ListPage returns all available repository commits
+// using multiple paginated requests if needed.
+func (c *CommitClient) ListPage(ctx context.Context, branch string, perPage, page int) ([]gitprovider.Commit, error) {
+	cs, err := c.listPage(ctx, branch, perPage, page)
+	if err != nil {
+		return nil, err
+	}
+	// Cast to the generic []gitprovider.Commit
+	commits := make([]gitprovider.Commit, 0, len(cs))
+	for _, commit := range cs {
+		commits = append(commits, commit)
+	}
+	return commits, nil
+}
+
+func (c *CommitClient) listPage(ctx context.Context, branch string, perPage, page int) ([]*commitType, error) {
+	// GET /repos/{owner}/{repo}/commits
+	apiObjs, err := c.c.ListCommitsPage(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), branch, perPage, page)
+	if err != nil {
+		return nil, err
+	}
+
+	// Map the api object to our CommitType type
+	commits := make([]*commitType, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		// apiObj is already validated at ListCommitsPage
+		commits = append(commits, newCommit(c, apiObj))
+	}
+
+	return commits, nil
+}
+
+// Create creates a commit with the given specifications.
+//
+// All files are changed in a single commit on top of the head of branch. Files without an Action
+// are deleted if their Content is nil, and otherwise updated if they exist, and created if not.
+// This requires Gitea 1.20 or later.
+//
+// ErrNotFound is returned if a file to update, delete or move does not exist.
+// ErrAlreadyExists is returned if a file to create, or the destination of a move, exists.
+// ErrNoProviderSupport is returned for executable files, as Gitea can't set file modes.
+//
+// New branches are created from the StartBranch given in opts, and the first commit of an empty
+// repository creates branch. The Author and Committer given in opts, including their dates, are
+// used as the identities of the commit.
+//
+// ErrConflict is returned if the head of branch isn't the ExpectedParentSHA given in opts, or if a
+// file to update or delete changed while the commit was being created. The head is checked before
+// the commit, as Gitea can't check it atomically.
+func (c *CommitClient) Create(ctx context.Context, branch string, message string, files []gitprovider.CommitFile, opts ...gitprovider.CommitCreateOption) (gitprovider.Commit, error) {
+	o, err := gitprovider.MakeCommitCreateOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no files added")
+	}
+	for _, file := range files {
+		if err := file.ValidateInfo(); err != nil {
+			return nil, err
+		}
+	}
+
+	// New branches start at the head of the start branch, if given
+	head, exists, err := c.head(ctx, branch)
+	if err != nil {
+		return nil, err
+	}
+	startBranch := ""
+	if !exists && o.StartBranch != nil {
+		startBranch = *o.StartBranch
+		if head, exists, err = c.head(ctx, startBranch); err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, fmt.Errorf("branch %q: %w", startBranch, gitprovider.ErrNotFound)
+		}
+	}
+	if !exists {
+		// Only the first branch of an empty repository can be created without a start branch
+		branches, err := c.c.ListBranches(ctx, c.ref.GetIdentity(), c.ref.GetRepository())
+		if err != nil {
+			return nil, err
+		}
+		if len(branches) != 0 {
+			return nil, fmt.Errorf("branch %q: %w", branch, gitprovider.ErrNotFound)
+		}
+	}
+	if o.ConflictsWith(head) {
+		return nil, fmt.Errorf("branch %q is at %q, not %q: %w", branch, head, *o.ExpectedParentSHA, gitprovider.ErrConflict)
+	}
+
+	// POST /repos/{owner}/{repo}/contents
+	apiObj, err := c.c.CreateCommit(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), branch, startBranch, message, o.Author, o.Committer, files)
+	if err != nil {
+		return nil, err
+	}
+	return newCommit(c, apiObj), nil
+}
+
+// head returns the SHA of the head of branch, and whether branch exists.
+func (c *CommitClient) head(ctx context.Context, branch string) (string, bool, error) {
+	// GET /repos/{owner}/{repo}/branches/{branch}
+	apiObj, err := c.c.GetBranch(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), branch)
+	if errors.Is(err, gitprovider.ErrNotFound) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return apiObj.Commit.ID, true, nil
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+const (
+	// giteaEntryTypeBlob is the type of the tree entries of files.
+	giteaEntryTypeBlob = "blob"
+	// giteaEntryTypeTree is the type of the tree entries of directories.
+	giteaEntryTypeTree = "tree"
+)
+
+// FileClient implements the gitprovider.FileClient interface.
+var _ gitprovider.FileClient = &FileClient{}
+
+// FileClient reads the files of a specific repository.
+//
+// Files are read through the git trees and blobs APIs, as the contents API doesn't report
+// file modes.
+type FileClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// Get returns the file at path as of ref, i.e. a branch, tag or commit sha, including its content.
+//
+// ErrNotFound is returned if ref doesn't exist, or if path isn't a file as of ref.
+func (c *FileClient) Get(ctx context.Context, filePath, ref string) (gitprovider.File, error) {
+	dirPath, _ := path.Split(strings.Trim(filePath, "/"))
+	// GET /repos/{owner}/{repo}/git/trees/{sha}
+	entries, err := c.list(ctx, dirPath, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if entry.Path != strings.Trim(filePath, "/") || entry.Type != giteaEntryTypeBlob {
+			continue
+		}
+		// GET /repos/{owner}/{repo}/git/blobs/{sha}
+		content, err := c.c.GetBlob(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), entry.SHA)
+		if err != nil {
+			return nil, err
+		}
+		return newFile(entry, content), nil
+	}
+	return nil, fmt.Errorf("file %q: %w", filePath, gitprovider.ErrNotFound)
+}
+
+// List lists the files and directories in the directory at dirPath as of ref.
+//
+// ErrNotFound is returned if ref doesn't exist, or if dirPath isn't a directory as of ref.
+func (c *FileClient) List(ctx context.Context, dirPath, ref string) ([]gitprovider.File, error) {
+	// GET /repos/{owner}/{repo}/git/trees/{sha}
+	entries, err := c.list(ctx, dirPath, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]gitprovider.File, 0, len(entries))
+	for _, entry := range entries {
+		files = append(files, newFile(entry, nil))
+	}
+	return files, nil
+}
+
+// list returns the entries of the tree of the directory at dirPath as of ref, with their paths
+// relative to the root of the repository. Gitea only resolves refs to their root trees, so the
+// trees of the parent directories are walked down to dirPath.
+func (c *FileClient) list(ctx context.Context, dirPath, ref string) ([]*GitEntry, error) {
+	// GET /repos/{owner}/{repo}/git/trees/{sha}
+	tree, err := c.c.GetTree(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), ref)
+	if err != nil {
+		return nil, err
+	}
+
+	dirPath = strings.Trim(dirPath, "/")
+	if len(dirPath) != 0 {
+		for _, name := range strings.Split(dirPath, "/") {
+			sha := ""
+			for _, entry := range tree.Entries {
+				if entry.Path == name && entry.Type == giteaEntryTypeTree {
+					sha = entry.SHA
+				}
+			}
+			if len(sha) == 0 {
+				return nil, fmt.Errorf("directory %q: %w", dirPath, gitprovider.ErrNotFound)
+			}
+			// GET /repos/{owner}/{repo}/git/trees/{sha}
+			if tree, err = c.c.GetTree(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), sha); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, entry := range tree.Entries {
+		entry.Path = path.Join(dirPath, entry.Path)
+	}
+	return tree.Entries, nil
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// PullRequestClient implements the gitprovider.PullRequestClient interface.
+var _ gitprovider.PullRequestClient = &PullRequestClient{}
+
+// PullRequestClient operates on the pull requests for a specific repository.
+type PullRequestClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// Get returns the pull request with the given index.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *PullRequestClient) Get(ctx context.Context, number int) (gitprovider.PullRequest, error) {
+	// GET /repos/{owner}/{repo}/pulls/{index}
+	pr, err := c.c.GetPullRequest(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), number)
+	if err != nil {
+		return nil, err
+	}
+	return newPullRequest(c.clientContext, pr, c.ref), nil
+}
+
+// List lists the pull requests of the repository matching the given filters.
+//
+// List returns all available pull requests, using multiple paginated requests if needed.
+func (c *PullRequestClient) List(ctx context.Context, opts ...gitprovider.PullRequestListOption) ([]gitprovider.PullRequest, error) {
+	o, err := gitprovider.MakePullRequestListOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Gitea only knows open and closed pull requests, and can't filter on branches.
+	// Everything else is filtered below.
+	state := "all"
+	if o.State != nil {
+		state = "closed"
+		if *o.State == gitprovider.PullRequestStateOpen {
+			state = "open"
+		}
+	}
+
+	// GET /repos/{owner}/{repo}/pulls
+	apiObjs, err := c.c.ListPullRequests(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), state)
+	if err != nil {
+		return nil, err
+	}
+
+	prs := make([]gitprovider.PullRequest, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		pr := newPullRequest(c.clientContext, apiObj, c.ref)
+		if o.Matches(pr.Get()) {
+			prs = append(prs, pr)
+		}
+	}
+	return prs, nil
+}
+
+// Create creates a pull request with the given specifications.
+func (c *PullRequestClient) Create(ctx context.Context, title, branch, baseBranch, description string) (gitprovider.PullRequest, error) {
+	req := &CreatePullRequestOption{
+		Title: title,
+		Head:  branch,
+		Base:  baseBranch,
+		Body:  description,
+	}
+
+	// POST /repos/{owner}/{repo}/pulls
+	pr, err := c.c.CreatePullRequest(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), req)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPullRequest(c.clientContext, pr, c.ref), nil
+}
+
+// Update changes the title, description and/or base branch of the pull request with the
+// given index. nil fields of opts are left unchanged.
+//
+// ErrNotFound is returned if the resource does not exist.
+// ErrNoProviderSupport is returned if labels are given.
+func (c *PullRequestClient) Update(ctx context.Context, number int, opts gitprovider.PullRequestUpdateOptions) (gitprovider.PullRequest, error) {
+	// Gitea labels are set by ID, which isn't supported
+	if len(opts.Labels) != 0 {
+		return nil, fmt.Errorf("pull request labels: %w", gitprovider.ErrNoProviderSupport)
+	}
+	return c.edit(ctx, number, &EditPullRequestOption{
+		Title: opts.Title,
+		Body:  opts.Description,
+		Base:  opts.BaseBranch,
+	})
+}
+
+// Merge merges the pull request with the given index, using the given method. message is the
+// message of the merge or squash commit; an empty message uses the Gitea default.
+func (c *PullRequestClient) Merge(ctx context.Context, number int, method gitprovider.MergeMethod, message string) error {
+	if err := gitprovider.ValidateMergeMethod(method); err != nil {
+		return fmt.Errorf("merge method %q: %w", method, gitprovider.ErrInvalidArgument)
+	}
+	req := &MergePullRequestOption{
+		Do:                string(method),
+		MergeMessageField: message,
+	}
+	// POST /repos/{owner}/{repo}/pulls/{index}/merge
+	return c.c.MergePullRequest(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), number, req)
+}
+
+// Close closes the pull request with the given index, without merging it.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *PullRequestClient) Close(ctx context.Context, number int) (gitprovider.PullRequest, error) {
+	return c.edit(ctx, number, &EditPullRequestOption{State: gitprovider.StringVar("closed")})
+}
+
+func (c *PullRequestClient) edit(ctx context.Context, number int, req *EditPullRequestOption) (gitprovider.PullRequest, error) {
+	// PATCH /repos/{owner}/{repo}/pulls/{index}
+	pr, err := c.c.EditPullRequest(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), number, req)
+	if err != nil {
+		return nil, err
+	}
+	return newPullRequest(c.clientContext, pr, c.ref), nil
+}
+
+// Reconcile makes sure the given desired state (req) becomes the actual state in the backing Git provider.
+// The pull request is looked up by req.HeadBranch and req.BaseBranch among the open pull
+// requests; the title and description of req are reconciled.
+//
+// If req doesn't exist under the hood, it is created (actionTaken == true).
+// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+// If req is already the actual state, this is a no-op (actionTaken == false).
+//
+// ErrNoProviderSupport is returned if req has labels.
+func (c *PullRequestClient) Reconcile(ctx context.Context, req gitprovider.PullRequestInfo) (gitprovider.PullRequest, bool, error) {
+	// First thing, validate the request
+	if err := req.ValidateInfo(); err != nil {
+		return nil, false, err
+	}
+	if len(req.Labels) != 0 {
+		return nil, false, fmt.Errorf("pull request labels: %w", gitprovider.ErrNoProviderSupport)
+	}
+
+	// Look for an open pull request from the head into the base branch
+	prs, err := c.List(ctx, &gitprovider.PullRequestListOptions{
+		State:      gitprovider.PullRequestStateVar(gitprovider.PullRequestStateOpen),
+		HeadBranch: &req.HeadBranch,
+		BaseBranch: &req.BaseBranch,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Create if not found
+	if len(prs) == 0 {
+		resp, err := c.Create(ctx, req.Title, req.HeadBranch, req.BaseBranch, req.Description)
+		return resp, true, err
+	}
+
+	// If the desired matches the actual state, just return the actual state
+	actual := prs[0]
+	if req.Equals(actual.Get()) {
+		return actual, false, nil
+	}
+
+	// Apply the desired state by running Update
+	resp, err := c.Update(ctx, actual.Get().Number, gitprovider.PullRequestUpdateOptions{
+		Title:       &req.Title,
+		Description: &req.Description,
+	})
+	return resp, true, err
+}
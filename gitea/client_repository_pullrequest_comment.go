@@ -0,0 +1,80 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"context"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// PullRequestCommentClient implements the gitprovider.PullRequestCommentClient interface.
+var _ gitprovider.PullRequestCommentClient = &PullRequestCommentClient{}
+
+// PullRequestCommentClient operates on the comments of a specific pull request.
+type PullRequestCommentClient struct {
+	*clientContext
+	ref   gitprovider.RepositoryRef
+	index int
+}
+
+// List all comments of the pull request, oldest first.
+//
+// List returns all available comments, using multiple paginated requests if needed.
+func (c *PullRequestCommentClient) List(ctx context.Context) ([]gitprovider.PullRequestComment, error) {
+	// GET /repos/{owner}/{repo}/issues/{index}/comments
+	apiObjs, err := c.c.ListIssueComments(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), c.index)
+	if err != nil {
+		return nil, err
+	}
+
+	comments := make([]gitprovider.PullRequestComment, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		comments = append(comments, newPullRequestComment(apiObj))
+	}
+	return comments, nil
+}
+
+// Create adds a comment with the given body to the pull request.
+func (c *PullRequestCommentClient) Create(ctx context.Context, body string) (gitprovider.PullRequestComment, error) {
+	// POST /repos/{owner}/{repo}/issues/{index}/comments
+	apiObj, err := c.c.CreateIssueComment(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), c.index, body)
+	if err != nil {
+		return nil, err
+	}
+	return newPullRequestComment(apiObj), nil
+}
+
+// Update changes the body of the comment with the given ID.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *PullRequestCommentClient) Update(ctx context.Context, id int64, body string) (gitprovider.PullRequestComment, error) {
+	// PATCH /repos/{owner}/{repo}/issues/comments/{id}
+	apiObj, err := c.c.EditIssueComment(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), id, body)
+	if err != nil {
+		return nil, err
+	}
+	return newPullRequestComment(apiObj), nil
+}
+
+// Delete deletes the comment with the given ID.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *PullRequestCommentClient) Delete(ctx context.Context, id int64) error {
+	// DELETE /repos/{owner}/{repo}/issues/comments/{id}
+	return c.c.DeleteIssueComment(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), id)
+}
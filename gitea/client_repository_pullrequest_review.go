@@ -0,0 +1,71 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"context"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// PullRequestReviewClient implements the gitprovider.PullRequestReviewClient interface.
+var _ gitprovider.PullRequestReviewClient = &PullRequestReviewClient{}
+
+// PullRequestReviewClient operates on the reviews of a specific pull request.
+type PullRequestReviewClient struct {
+	*clientContext
+	ref   gitprovider.RepositoryRef
+	index int
+}
+
+// List lists the submitted reviews of the pull request, oldest first.
+//
+// List returns all available reviews, using multiple paginated requests if needed.
+func (c *PullRequestReviewClient) List(ctx context.Context) ([]gitprovider.PullRequestReview, error) {
+	// GET /repos/{owner}/{repo}/pulls/{index}/reviews
+	apiObjs, err := c.c.ListPullReviews(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), c.index)
+	if err != nil {
+		return nil, err
+	}
+
+	reviews := make([]gitprovider.PullRequestReview, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		// Pending reviews and review requests aren't submitted reviews
+		if apiObj.State == reviewStatePending || apiObj.State == reviewStateRequestReview {
+			continue
+		}
+		reviews = append(reviews, newPullRequestReview(apiObj))
+	}
+	return reviews, nil
+}
+
+// Submit submits a review with the given state and body.
+func (c *PullRequestReviewClient) Submit(ctx context.Context, state gitprovider.PullRequestReviewState, body string) (gitprovider.PullRequestReview, error) {
+	event, err := reviewStateToAPI(state)
+	if err != nil {
+		return nil, err
+	}
+	// POST /repos/{owner}/{repo}/pulls/{index}/reviews
+	apiObj, err := c.c.CreatePullReview(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), c.index, &CreatePullReviewOptions{
+		Event: event,
+		Body:  body,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newPullRequestReview(apiObj), nil
+}
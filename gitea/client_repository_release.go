@@ -0,0 +1,77 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"context"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// ReleaseClient implements the gitprovider.ReleaseClient interface.
+var _ gitprovider.ReleaseClient = &ReleaseClient{}
+
+// ReleaseClient operates on the releases for a specific repository.
+//
+// The Gitea releases API isn't wrapped by this package yet.
+// Hence, all methods return ErrNoProviderSupport.
+type ReleaseClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// Get returns the release of the tag with the given name.
+//
+// This isn't implemented by this package yet.
+func (c *ReleaseClient) Get(_ context.Context, _ string) (gitprovider.Release, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// List lists all releases of the repository.
+//
+// This isn't implemented by this package yet.
+func (c *ReleaseClient) List(_ context.Context) ([]gitprovider.Release, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// Create creates a release with the given specifications.
+//
+// This isn't implemented by this package yet.
+func (c *ReleaseClient) Create(_ context.Context, _ gitprovider.ReleaseInfo) (gitprovider.Release, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// Update changes the release of the tag req.TagName to the state of req.
+//
+// This isn't implemented by this package yet.
+func (c *ReleaseClient) Update(_ context.Context, _ gitprovider.ReleaseInfo) (gitprovider.Release, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// Delete deletes the release of the tag with the given name.
+//
+// This isn't implemented by this package yet.
+func (c *ReleaseClient) Delete(_ context.Context, _ string) error {
+	return gitprovider.ErrNoProviderSupport
+}
+
+// Reconcile makes sure the given desired state (req) becomes the actual state in the backing Git provider.
+//
+// This isn't implemented by this package yet.
+func (c *ReleaseClient) Reconcile(_ context.Context, _ gitprovider.ReleaseInfo) (gitprovider.Release, bool, error) {
+	return nil, false, gitprovider.ErrNoProviderSupport
+}
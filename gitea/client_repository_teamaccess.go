@@ -0,0 +1,148 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// TeamAccessClient implements the gitprovider.TeamAccessClient interface.
+var _ gitprovider.TeamAccessClient = &TeamAccessClient{}
+
+// TeamAccessClient operates on the teams list for a specific repository.
+//
+// In Gitea, the permission is a property of the team, and applies to all repositories of the
+// team. Hence teams can only be given access with the permission they already have, and
+// ErrNoProviderSupport is returned for any other permission.
+type TeamAccessClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// Get a team's permission level of this given repository.
+//
+// ErrNotFound is returned if the resource does not exist.
+//
+// TeamAccess.APIObject will be nil, because there's no underlying Gitea struct.
+func (c *TeamAccessClient) Get(ctx context.Context, name string) (gitprovider.TeamAccess, error) {
+	// GET /repos/{owner}/{repo}/teams/{team}
+	apiObj, err := c.c.GetRepoTeam(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), name)
+	if err != nil {
+		return nil, err
+	}
+
+	return newTeamAccess(c, teamAccessFromAPI(apiObj)), nil
+}
+
+// List lists the team access control list for this repository.
+func (c *TeamAccessClient) List(ctx context.Context) ([]gitprovider.TeamAccess, error) {
+	// GET /repos/{owner}/{repo}/teams
+	apiObjs, err := c.c.ListRepoTeams(ctx, c.ref.GetIdentity(), c.ref.GetRepository())
+	if err != nil {
+		return nil, err
+	}
+
+	teamAccess := make([]gitprovider.TeamAccess, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		teamAccess = append(teamAccess, newTeamAccess(c, teamAccessFromAPI(apiObj)))
+	}
+
+	return teamAccess, nil
+}
+
+// Create adds a given team to the repo's team access control list.
+//
+// ErrAlreadyExists will be returned if the resource already exists.
+func (c *TeamAccessClient) Create(ctx context.Context, req gitprovider.TeamAccessInfo) (gitprovider.TeamAccess, error) {
+	// First thing, validate and default the request to ensure a valid and fully-populated object
+	// (to minimize any possible diffs between desired and actual state)
+	if err := gitprovider.ValidateAndDefaultInfo(&req); err != nil {
+		return nil, err
+	}
+
+	// Make sure the team has the requested permission, as it can't be set per repository
+	// GET /orgs/{org}/teams
+	apiObjs, err := c.c.ListOrgTeams(ctx, c.ref.GetIdentity())
+	if err != nil {
+		return nil, err
+	}
+	var apiObj *Team
+	for _, t := range apiObjs {
+		if strings.EqualFold(t.Name, req.Name) {
+			apiObj = t
+			break
+		}
+	}
+	if apiObj == nil {
+		return nil, fmt.Errorf("team %q not found: %w", req.Name, gitprovider.ErrNotFound)
+	}
+	actual := teamAccessFromAPI(apiObj)
+	if actual.Permission == nil || *actual.Permission != *req.Permission {
+		return nil, fmt.Errorf("gitea doesn't support giving team %q permission %q to a single repository: %w",
+			req.Name, *req.Permission, gitprovider.ErrNoProviderSupport)
+	}
+
+	// PUT /repos/{owner}/{repo}/teams/{team}
+	if err := c.c.AddRepoTeam(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), apiObj.Name); err != nil {
+		return nil, err
+	}
+
+	return newTeamAccess(c, actual), nil
+}
+
+// Reconcile makes sure the given desired state (req) becomes the actual state in the backing Git provider.
+//
+// If req doesn't exist under the hood, it is created (actionTaken == true).
+// If req doesn't equal the actual state, the resource will be deleted and recreated (actionTaken == true).
+// If req is already the actual state, this is a no-op (actionTaken == false).
+func (c *TeamAccessClient) Reconcile(ctx context.Context,
+	req gitprovider.TeamAccessInfo,
+) (gitprovider.TeamAccess, bool, error) {
+	// First thing, validate and default the request to ensure a valid and fully-populated object
+	// (to minimize any possible diffs between desired and actual state)
+	if err := gitprovider.ValidateAndDefaultInfo(&req); err != nil {
+		return nil, false, err
+	}
+
+	actual, err := c.Get(ctx, req.Name)
+	if err != nil {
+		// Create if not found
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			resp, err := c.Create(ctx, req)
+			return resp, true, err
+		}
+
+		// Unexpected path, Get should succeed or return NotFound
+		return nil, false, err
+	}
+
+	// If the desired matches the actual state, just return the actual state
+	if req.Equals(actual.Get()) {
+		return actual, false, nil
+	}
+
+	// Populate the desired state to the current-actual object
+	if err := actual.Set(req); err != nil {
+		return actual, false, err
+	}
+	return actual, true, actual.Update(ctx)
+}
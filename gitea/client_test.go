@@ -0,0 +1,1372 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"context"
+	"crypto/sha1" //nolint:gosec
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	gopath "path"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+// newTestClient starts a server handling the API paths of mux, and returns a client pointing to it.
+func newTestClient(t *testing.T, mux *http.ServeMux, opts ...ClientOption) *Client {
+	t.Helper()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	c, err := NewClient(append([]ClientOption{WithDomain(srv.URL)}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c.(*Client)
+}
+
+func writeJSON(t *testing.T, w http.ResponseWriter, status int, obj interface{}) {
+	t.Helper()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(obj); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeError(t *testing.T, w http.ResponseWriter, status int, message string) {
+	t.Helper()
+	writeJSON(t, w, status, map[string]string{"message": message, "url": "https://gitea.example.com/api/swagger"})
+}
+
+func decodeJSON(t *testing.T, r *http.Request, obj interface{}) {
+	t.Helper()
+	if err := json.NewDecoder(r.Body).Decode(obj); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	_, err := NewClient()
+	validation.TestExpectErrors(t, "NewClient", err, gitprovider.ErrInvalidClientOptions)
+
+	c, err := NewClient(WithDomain("gitea.example.com/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.SupportedDomain(); got != "gitea.example.com/" {
+		t.Errorf("SupportedDomain() = %q, want %q", got, "gitea.example.com/")
+	}
+	if got := c.(*Client).c.(*giteaClientImpl).domainURL; got != "https://gitea.example.com" {
+		t.Errorf("domainURL = %q, want %q", got, "https://gitea.example.com")
+	}
+	if got := c.ProviderID(); got != ProviderID {
+		t.Errorf("ProviderID() = %q, want %q", got, ProviderID)
+	}
+	_, err = c.HasTokenPermission(context.Background(), gitprovider.TokenPermissionRWRepository)
+	validation.TestExpectErrors(t, "HasTokenPermission", err, gitprovider.ErrNoProviderSupport)
+}
+
+func TestOrganizationsClient(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/orgs/org", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, http.StatusOK, Organization{ID: 1, UserName: "org", FullName: "Organization", Description: "desc"})
+	})
+	mux.HandleFunc("/api/v1/orgs/missing", func(w http.ResponseWriter, r *http.Request) {
+		writeError(t, w, http.StatusNotFound, "GetOrgByName")
+	})
+	mux.HandleFunc("/api/v1/user/orgs", func(w http.ResponseWriter, r *http.Request) {
+		// Serve two pages, to test pagination
+		if r.URL.Query().Get("page") == "2" {
+			writeJSON(t, w, http.StatusOK, []Organization{{ID: 2, UserName: "other"}})
+			return
+		}
+		if r.URL.Query().Get("limit") != fmt.Sprint(defaultPageSize) {
+			t.Errorf("unexpected page size %q", r.URL.Query().Get("limit"))
+		}
+		w.Header().Set("Link", fmt.Sprintf(`<http://%s/api/v1/user/orgs?limit=50&page=2>; rel="next",<http://%s/api/v1/user/orgs?limit=50&page=2>; rel="last"`, r.Host, r.Host))
+		writeJSON(t, w, http.StatusOK, []Organization{{ID: 1, UserName: "org"}})
+	})
+	mux.HandleFunc("/api/v1/orgs/org/teams", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, http.StatusOK, []Team{
+			{ID: 1, Name: "Owners", Permission: teamPermissionOwner},
+			{ID: 2, Name: "devs", Permission: teamPermissionNone, UnitsMap: map[string]string{codeUnit: teamPermissionWrite}},
+		})
+	})
+	mux.HandleFunc("/api/v1/teams/2/members", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, http.StatusOK, []User{{ID: 1, UserName: "alice"}, {ID: 2, UserName: "bob"}})
+	})
+	c := newTestClient(t, mux)
+	ctx := context.Background()
+
+	org, err := c.Organizations().Get(ctx, gitprovider.OrganizationRef{Domain: c.domain, Organization: "org"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info := org.Get(); *info.Name != "Organization" || *info.Description != "desc" {
+		t.Errorf("Organization.Get() = %+v", info)
+	}
+
+	_, err = c.Organizations().Get(ctx, gitprovider.OrganizationRef{Domain: c.domain, Organization: "missing"})
+	validation.TestExpectErrors(t, "Organizations().Get", err, gitprovider.ErrNotFound)
+
+	_, err = c.Organizations().Get(ctx, gitprovider.OrganizationRef{Domain: "other.com", Organization: "org"})
+	validation.TestExpectErrors(t, "Organizations().Get", err, gitprovider.ErrDomainUnsupported)
+
+	_, err = c.Organizations().Get(ctx, gitprovider.OrganizationRef{Domain: c.domain, Organization: "org", SubOrganizations: []string{"sub"}})
+	validation.TestExpectErrors(t, "Organizations().Get", err, gitprovider.ErrNoProviderSupport)
+
+	orgs, err := c.Organizations().List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orgs) != 2 || orgs[1].Organization().Organization != "other" {
+		t.Errorf("Organizations().List() = %v, want org and other", orgs)
+	}
+
+	_, err = c.Organizations().Children(ctx, org.Organization())
+	validation.TestExpectErrors(t, "Organizations().Children", err, gitprovider.ErrNoProviderSupport)
+
+	team, err := org.Teams().Get(ctx, "Devs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := team.Get(); got.Name != "devs" || len(got.Members) != 2 || got.Members[1] != "bob" {
+		t.Errorf("Teams().Get() = %+v", got)
+	}
+	_, err = org.Teams().Get(ctx, "missing")
+	validation.TestExpectErrors(t, "Teams().Get", err, gitprovider.ErrNotFound)
+}
+
+// fakeRepoServer is a minimal in-memory implementation of the repository endpoints.
+type fakeRepoServer struct {
+	t *testing.T
+	// repos by "{owner}/{repo}"
+	repos map[string]*Repository
+	keys  []*DeployKey
+	// teams of the organization, and the names of the teams with access to the repository
+	teams     []*Team
+	repoTeams []string
+	// file contents on the default branch, by path
+	files map[string]string
+	// commits on the default branch, newest first
+	commits []*Commit
+	// branches by name, and their protection rules by branch name
+	branches    map[string]*Branch
+	protections map[string]*BranchProtection
+	prs         []*PullRequest
+	// comments and reviews on all pull requests, in creation order
+	comments []*Comment
+	reviews  []*PullReview
+}
+
+func newFakeRepoServer(t *testing.T, repos map[string]*Repository) *fakeRepoServer {
+	return &fakeRepoServer{t: t, repos: repos, files: map[string]string{}, branches: map[string]*Branch{}, protections: map[string]*BranchProtection{}}
+}
+
+func (s *fakeRepoServer) register(mux *http.ServeMux) {
+	mux.HandleFunc("/api/v1/orgs/", s.handleOwnerRepos)
+	mux.HandleFunc("/api/v1/users/", s.handleOwnerRepos)
+	mux.HandleFunc("/api/v1/user/repos", s.handleOwnerRepos)
+	mux.HandleFunc("/api/v1/repos/", s.handle)
+}
+
+// handleOwnerRepos lists and creates repositories of users and organizations.
+func (s *fakeRepoServer) handleOwnerRepos(w http.ResponseWriter, r *http.Request) {
+	t := s.t
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/v1/"), "/")
+	owner := "alice"
+	if parts[0] != "user" {
+		owner = parts[1]
+	}
+	if len(parts) == 3 && parts[2] == "teams" {
+		writeJSON(t, w, http.StatusOK, s.teams)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		values := []*Repository{}
+		for name, repo := range s.repos {
+			if strings.HasPrefix(name, owner+"/") {
+				values = append(values, repo)
+			}
+		}
+		writeJSON(t, w, http.StatusOK, values)
+	case http.MethodPost:
+		req := &CreateRepoOption{}
+		decodeJSON(t, r, req)
+		name := owner + "/" + req.Name
+		if _, exists := s.repos[name]; exists {
+			writeError(t, w, http.StatusConflict, "The repository with the same name already exists.")
+			return
+		}
+		repo := &Repository{
+			ID:            int64(len(s.repos) + 1),
+			Owner:         &User{UserName: owner},
+			Name:          req.Name,
+			FullName:      name,
+			Description:   &req.Description,
+			Private:       &req.Private,
+			DefaultBranch: &req.DefaultBranch,
+			Empty:         !req.AutoInit,
+		}
+		if req.AutoInit {
+			s.files["README.md"] = "# " + req.Name
+			s.files["LICENSE"] = req.License
+		}
+		s.repos[name] = repo
+		writeJSON(t, w, http.StatusCreated, repo)
+	}
+}
+
+func (s *fakeRepoServer) handle(w http.ResponseWriter, r *http.Request) {
+	t := s.t
+	// {owner}/{repo}[/...]
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/v1/repos/"), "/")
+	name := parts[0] + "/" + parts[1]
+	repo, ok := s.repos[name]
+	if !ok {
+		writeError(t, w, http.StatusNotFound, "The target couldn't be found.")
+		return
+	}
+	rest := parts[2:]
+	if len(rest) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(t, w, http.StatusOK, repo)
+		case http.MethodPatch:
+			req := &Repository{}
+			decodeJSON(t, r, req)
+			if req.Description != nil {
+				repo.Description = req.Description
+			}
+			if req.Private != nil {
+				repo.Private = req.Private
+			}
+			if req.DefaultBranch != nil {
+				repo.DefaultBranch = req.DefaultBranch
+			}
+			writeJSON(t, w, http.StatusOK, repo)
+		case http.MethodDelete:
+			delete(s.repos, name)
+			w.WriteHeader(http.StatusNoContent)
+		}
+		return
+	}
+
+	switch rest[0] {
+	case "keys":
+		s.handleKeys(w, r, rest[1:])
+	case "teams":
+		s.handleTeams(w, r, rest[1:])
+	case "commits":
+		if r.URL.Query().Get("sha") != "main" || r.URL.Query().Get("limit") != "2" {
+			t.Errorf("unexpected query %q", r.URL.RawQuery)
+		}
+		writeJSON(t, w, http.StatusOK, s.commits)
+	case "contents":
+		s.handleContents(w, r, strings.Join(rest[1:], "/"))
+	case "git":
+		s.handleGit(w, r, rest[1:])
+	case "branches":
+		s.handleBranches(w, r, rest[1:])
+	case "branch_protections":
+		s.handleBranchProtections(w, r, rest[1:])
+	case "pulls":
+		s.handlePulls(w, r, name, rest[1:])
+	case "issues":
+		s.handleComments(w, r, rest[1:])
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (s *fakeRepoServer) handlePulls(w http.ResponseWriter, r *http.Request, name string, rest []string) {
+	t := s.t
+	if len(rest) == 0 {
+		if r.Method == http.MethodGet {
+			state := r.URL.Query().Get("state")
+			values := []*PullRequest{}
+			for _, pr := range s.prs {
+				if state == "all" || state == pr.State {
+					values = append(values, pr)
+				}
+			}
+			writeJSON(t, w, http.StatusOK, values)
+			return
+		}
+		req := &CreatePullRequestOption{}
+		decodeJSON(t, r, req)
+		pr := &PullRequest{
+			ID:      int64(len(s.prs) + 100),
+			Number:  int64(len(s.prs) + 1),
+			Title:   req.Title,
+			Body:    req.Body,
+			State:   "open",
+			Head:    &PRBranchInfo{Ref: req.Head},
+			Base:    &PRBranchInfo{Ref: req.Base},
+			HTMLURL: fmt.Sprintf("https://gitea.example.com/%s/pulls/%d", name, len(s.prs)+1),
+		}
+		s.prs = append(s.prs, pr)
+		writeJSON(t, w, http.StatusCreated, pr)
+		return
+	}
+
+	var pr *PullRequest
+	for _, p := range s.prs {
+		if fmt.Sprint(p.Number) == rest[0] {
+			pr = p
+		}
+	}
+	if pr == nil {
+		writeError(t, w, http.StatusNotFound, "The target couldn't be found.")
+		return
+	}
+	switch {
+	case r.Method == http.MethodPatch:
+		req := &EditPullRequestOption{}
+		decodeJSON(t, r, req)
+		if req.Title != nil {
+			pr.Title = *req.Title
+		}
+		if req.State != nil {
+			pr.State = *req.State
+		}
+	case len(rest) == 2 && rest[1] == "merge":
+		req := &MergePullRequestOption{}
+		decodeJSON(t, r, req)
+		if req.Do != "squash" {
+			t.Errorf("unexpected merge method %q", req.Do)
+		}
+		pr.State, pr.HasMerged = "closed", true
+		w.WriteHeader(http.StatusOK)
+		return
+	case len(rest) == 2 && rest[1] == "reviews" && r.Method == http.MethodGet:
+		writeJSON(t, w, http.StatusOK, s.reviews)
+		return
+	case len(rest) == 2 && rest[1] == "reviews":
+		req := &CreatePullReviewOptions{}
+		decodeJSON(t, r, req)
+		review := &PullReview{ID: int64(len(s.reviews) + 1), Reviewer: &User{UserName: "me"}, State: req.Event, Body: req.Body}
+		s.reviews = append(s.reviews, review)
+		writeJSON(t, w, http.StatusOK, review)
+		return
+	}
+	writeJSON(t, w, http.StatusOK, pr)
+}
+
+// handleComments handles issues/{index}/comments and issues/comments/{id}.
+func (s *fakeRepoServer) handleComments(w http.ResponseWriter, r *http.Request, rest []string) {
+	t := s.t
+	if len(rest) == 2 && rest[1] == "comments" {
+		if r.Method == http.MethodGet {
+			writeJSON(t, w, http.StatusOK, s.comments)
+			return
+		}
+		req := &Comment{}
+		decodeJSON(t, r, req)
+		req.ID = int64(len(s.comments) + 1)
+		req.Poster = &User{UserName: "me"}
+		s.comments = append(s.comments, req)
+		writeJSON(t, w, http.StatusCreated, req)
+		return
+	}
+
+	for i, comment := range s.comments {
+		if len(rest) != 2 || fmt.Sprint(comment.ID) != rest[1] {
+			continue
+		}
+		if r.Method == http.MethodDelete {
+			s.comments = append(s.comments[:i], s.comments[i+1:]...)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		req := &Comment{}
+		decodeJSON(t, r, req)
+		comment.Body = req.Body
+		writeJSON(t, w, http.StatusOK, comment)
+		return
+	}
+	writeError(t, w, http.StatusNotFound, "The target couldn't be found.")
+}
+
+func (s *fakeRepoServer) handleKeys(w http.ResponseWriter, r *http.Request, rest []string) {
+	t := s.t
+	switch {
+	case r.Method == http.MethodGet:
+		writeJSON(t, w, http.StatusOK, s.keys)
+	case r.Method == http.MethodPost:
+		req := &DeployKey{}
+		decodeJSON(t, r, req)
+		// The server drops the comment of the key
+		req.Key = keyWithoutComment(req.Key)
+		for _, k := range s.keys {
+			if k.Key == req.Key {
+				writeError(t, w, http.StatusUnprocessableEntity, "Key content has been used as non-deploy key")
+				return
+			}
+		}
+		req.ID = int64(len(s.keys) + 1)
+		s.keys = append(s.keys, req)
+		writeJSON(t, w, http.StatusCreated, req)
+	case r.Method == http.MethodDelete && len(rest) == 1:
+		for i, k := range s.keys {
+			if fmt.Sprint(k.ID) == rest[0] {
+				s.keys = append(s.keys[:i], s.keys[i+1:]...)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		writeError(t, w, http.StatusNotFound, "The target couldn't be found.")
+	}
+}
+
+func (s *fakeRepoServer) handleTeams(w http.ResponseWriter, r *http.Request, rest []string) {
+	t := s.t
+	if len(rest) == 0 {
+		teams := []*Team{}
+		for _, team := range s.teams {
+			for _, name := range s.repoTeams {
+				if team.Name == name {
+					teams = append(teams, team)
+				}
+			}
+		}
+		writeJSON(t, w, http.StatusOK, teams)
+		return
+	}
+	var team *Team
+	for _, tm := range s.teams {
+		if strings.EqualFold(tm.Name, rest[0]) {
+			team = tm
+		}
+	}
+	if team == nil {
+		writeError(t, w, http.StatusNotFound, "The target couldn't be found.")
+		return
+	}
+	hasAccess := -1
+	for i, name := range s.repoTeams {
+		if name == team.Name {
+			hasAccess = i
+		}
+	}
+	switch r.Method {
+	case http.MethodGet:
+		if hasAccess == -1 {
+			writeError(t, w, http.StatusNotFound, "The target couldn't be found.")
+			return
+		}
+		writeJSON(t, w, http.StatusOK, team)
+	case http.MethodPut:
+		if hasAccess != -1 {
+			writeError(t, w, http.StatusUnprocessableEntity, "team already added to repository")
+			return
+		}
+		s.repoTeams = append(s.repoTeams, team.Name)
+		w.WriteHeader(http.StatusNoContent)
+	case http.MethodDelete:
+		s.repoTeams = append(s.repoTeams[:hasAccess], s.repoTeams[hasAccess+1:]...)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (s *fakeRepoServer) handleContents(w http.ResponseWriter, r *http.Request, path string) {
+	t := s.t
+	if r.Method == http.MethodGet {
+		content, ok := s.files[path]
+		if !ok {
+			writeError(t, w, http.StatusNotFound, "object does not exist [id: , rel_path: "+path+"]")
+			return
+		}
+		writeJSON(t, w, http.StatusOK, ContentsResponse{
+			Name:    path,
+			Path:    path,
+			Type:    "file",
+			SHA:     blobSHA(content),
+			Content: base64.StdEncoding.EncodeToString([]byte(content)),
+		})
+		return
+	}
+
+	req := &ChangeFilesOptions{}
+	decodeJSON(t, r, req)
+	for _, op := range req.Files {
+		// Moved files are identified by their previous path
+		fromPath := op.Path
+		if len(op.FromPath) != 0 {
+			fromPath = op.FromPath
+		}
+		content, exists := s.files[fromPath]
+		switch op.Operation {
+		case fileOperationCreate:
+			if exists {
+				writeError(t, w, http.StatusUnprocessableEntity, "repository file already exists [path: "+op.Path+"]")
+				return
+			}
+		case fileOperationUpdate, fileOperationDelete:
+			if !exists || op.SHA != blobSHA(content) {
+				writeError(t, w, http.StatusConflict, "sha does not match [given: "+op.SHA+"]")
+				return
+			}
+		}
+		delete(s.files, fromPath)
+		if op.Operation == fileOperationDelete {
+			continue
+		}
+		data, err := base64.StdEncoding.DecodeString(op.Content)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.files[op.Path] = string(data)
+	}
+	commit := &FileCommitResponse{
+		CommitMeta: CommitMeta{SHA: fmt.Sprintf("%040d", len(s.commits)+1)},
+		Message:    req.Message,
+		Author:     req.Author,
+		Committer:  req.Committer,
+		Tree:       &CommitMeta{SHA: fmt.Sprintf("%040d", 1000+len(s.commits))},
+	}
+	if req.Author != nil && req.Dates != nil && req.Dates.Author != nil {
+		commit.Author.Date = req.Dates.Author.Format(time.RFC3339)
+	}
+	if parent, ok := s.branches[req.Branch]; ok {
+		commit.Parents = []*CommitMeta{{SHA: parent.Commit.ID}}
+	}
+	s.commits = append([]*Commit{{
+		CommitMeta: commit.CommitMeta,
+		Commit:     &RepoCommit{Message: commit.Message, Author: commit.Author, Committer: commit.Committer, Tree: commit.Tree},
+		Parents:    commit.Parents,
+	}}, s.commits...)
+	// New branches are created from the given branch, which is left as is
+	branch := req.Branch
+	if len(req.NewBranch) != 0 {
+		branch = req.NewBranch
+	}
+	s.branches[branch] = &Branch{Name: branch, Commit: &PayloadCommit{ID: commit.SHA}}
+	writeJSON(t, w, http.StatusCreated, FilesResponse{Commit: commit})
+}
+
+func (s *fakeRepoServer) handleBranches(w http.ResponseWriter, r *http.Request, rest []string) {
+	t := s.t
+	if len(rest) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			values := []*Branch{}
+			for _, b := range s.branches {
+				values = append(values, b)
+			}
+			writeJSON(t, w, http.StatusOK, values)
+		case http.MethodPost:
+			req := map[string]string{}
+			decodeJSON(t, r, &req)
+			if _, ok := s.branches[req["new_branch_name"]]; ok {
+				writeError(t, w, http.StatusConflict, "The branch already exists.")
+				return
+			}
+			// The old ref can be a branch name or a commit SHA
+			sha := req["old_ref_name"]
+			if from, ok := s.branches[sha]; ok {
+				sha = from.Commit.ID
+			} else if len(sha) != 40 {
+				writeError(t, w, http.StatusNotFound, "The old branch does not exist")
+				return
+			}
+			b := &Branch{Name: req["new_branch_name"], Commit: &PayloadCommit{ID: sha}}
+			s.branches[b.Name] = b
+			writeJSON(t, w, http.StatusCreated, b)
+		}
+		return
+	}
+	b, ok := s.branches[rest[0]]
+	if !ok {
+		writeError(t, w, http.StatusNotFound, "The target couldn't be found.")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(t, w, http.StatusOK, b)
+	case http.MethodDelete:
+		delete(s.branches, rest[0])
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (s *fakeRepoServer) handleBranchProtections(w http.ResponseWriter, r *http.Request, rest []string) {
+	t := s.t
+	if len(rest) == 0 {
+		req := &BranchProtection{}
+		decodeJSON(t, r, req)
+		if _, ok := s.protections[req.BranchName]; ok {
+			writeError(t, w, http.StatusForbidden, "Branch protection already exist")
+			return
+		}
+		s.protections[req.BranchName] = req
+		writeJSON(t, w, http.StatusCreated, req)
+		return
+	}
+	p, ok := s.protections[rest[0]]
+	if !ok {
+		writeError(t, w, http.StatusNotFound, "The target couldn't be found.")
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(t, w, http.StatusOK, p)
+	case http.MethodPatch:
+		decodeJSON(t, r, p)
+		writeJSON(t, w, http.StatusOK, p)
+	case http.MethodDelete:
+		delete(s.protections, rest[0])
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// blobSHA returns a fake, but content-dependent, blob SHA.
+func blobSHA(content string) string {
+	return fmt.Sprintf("%x", sha1.Sum([]byte(content))) //nolint:gosec
+}
+
+// handleGit serves the trees and blobs of the files on the default branch. The root tree is
+// resolved from any branch name or commit SHA, and the trees of directories have fake SHAs.
+func (s *fakeRepoServer) handleGit(w http.ResponseWriter, r *http.Request, rest []string) {
+	t := s.t
+	if len(rest) != 2 || r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	switch rest[0] {
+	case "blobs":
+		for _, content := range s.files {
+			if blobSHA(content) == rest[1] {
+				writeJSON(t, w, http.StatusOK, GitBlobResponse{
+					SHA:      rest[1],
+					Size:     int64(len(content)),
+					Content:  base64.StdEncoding.EncodeToString([]byte(content)),
+					Encoding: "base64",
+				})
+				return
+			}
+		}
+	case "trees":
+		dir, ok := ".", s.branches[rest[1]] != nil
+		for _, c := range s.commits {
+			ok = ok || c.SHA == rest[1]
+		}
+		entries := map[string]*GitEntry{}
+		for path, content := range s.files {
+			// Every parent directory of the file is an entry of the tree of its own parent
+			for p := path; p != "."; p = gopath.Dir(p) {
+				entry := &GitEntry{Path: gopath.Base(p), Mode: "040000", Type: "tree", SHA: blobSHA("tree " + p)}
+				if p == path {
+					entry = &GitEntry{Path: gopath.Base(p), Mode: "100644", Type: "blob", SHA: blobSHA(content), Size: int64(len(content))}
+				} else if !ok && entry.SHA == rest[1] {
+					dir, ok = p, true
+				}
+				entries[gopath.Dir(p)+"/"+entry.Path] = entry
+			}
+		}
+		if ok {
+			tree := &GitTreeResponse{SHA: rest[1], Page: 1, Entries: []*GitEntry{}}
+			for key, entry := range entries {
+				if gopath.Dir(key) == dir {
+					tree.Entries = append(tree.Entries, entry)
+				}
+			}
+			sort.Slice(tree.Entries, func(i, j int) bool { return tree.Entries[i].Path < tree.Entries[j].Path })
+			writeJSON(t, w, http.StatusOK, tree)
+			return
+		}
+	}
+	writeError(t, w, http.StatusNotFound, "object does not exist [id: "+rest[1]+"]")
+}
+
+func TestOrgRepositoriesClient(t *testing.T) {
+	mux := http.NewServeMux()
+	srv := newFakeRepoServer(t, map[string]*Repository{})
+	srv.register(mux)
+	c := newTestClient(t, mux)
+	ctx := context.Background()
+
+	ref := gitprovider.OrgRepositoryRef{
+		OrganizationRef: gitprovider.OrganizationRef{Domain: c.domain, Organization: "org"},
+		RepositoryName:  "repo",
+	}
+	_, err := c.OrgRepositories().Get(ctx, ref)
+	validation.TestExpectErrors(t, "OrgRepositories().Get", err, gitprovider.ErrNotFound)
+
+	// Reconcile should create the repository
+	repo, actionTaken, err := c.OrgRepositories().Reconcile(ctx, ref, gitprovider.RepositoryInfo{
+		Description: gitprovider.StringVar("desc"),
+	}, &gitprovider.RepositoryCreateOptions{
+		AutoInit:        gitprovider.BoolVar(true),
+		LicenseTemplate: gitprovider.LicenseTemplateVar(gitprovider.LicenseTemplateApache2),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !actionTaken {
+		t.Error("expected Reconcile to create the repository")
+	}
+	info := repo.Get()
+	if *info.Visibility != gitprovider.RepositoryVisibilityPrivate || *info.DefaultBranch != "master" || *info.Description != "desc" {
+		t.Errorf("unexpected repository info: %+v", info)
+	}
+	if srv.files["LICENSE"] != "Apache-2.0" {
+		t.Errorf("expected the repository to be initialized with a license, got %v", srv.files)
+	}
+
+	// Creating it again is an error
+	_, err = c.OrgRepositories().Create(ctx, ref, gitprovider.RepositoryInfo{})
+	validation.TestExpectErrors(t, "OrgRepositories().Create", err, gitprovider.ErrAlreadyExists)
+
+	// Reconciling the same state again is a no-op
+	_, actionTaken, err = c.OrgRepositories().Reconcile(ctx, ref, gitprovider.RepositoryInfo{
+		Description: gitprovider.StringVar("desc"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actionTaken {
+		t.Error("expected Reconcile to be a no-op")
+	}
+
+	// Changing the visibility and default branch updates the repository
+	_, actionTaken, err = c.OrgRepositories().Reconcile(ctx, ref, gitprovider.RepositoryInfo{
+		Description:   gitprovider.StringVar("desc"),
+		DefaultBranch: gitprovider.StringVar("main"),
+		Visibility:    gitprovider.RepositoryVisibilityVar(gitprovider.RepositoryVisibilityPublic),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.repos["org/repo"]; !actionTaken || *got.Private || *got.DefaultBranch != "main" {
+		t.Error("expected Reconcile to make the repository public, with default branch main")
+	}
+
+	// Internal repositories can't be expressed
+	err = repo.Set(gitprovider.RepositoryInfo{
+		Visibility: gitprovider.RepositoryVisibilityVar(gitprovider.RepositoryVisibilityInternal),
+	})
+	validation.TestExpectErrors(t, "Repository.Set", err, gitprovider.ErrNoProviderSupport)
+
+	repos, err := c.OrgRepositories().List(ctx, ref.OrganizationRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repos) != 1 || repos[0].Repository().GetRepository() != "repo" || *repos[0].Get().DefaultBranch != "main" {
+		t.Errorf("OrgRepositories().List() = %v, want [repo]", repos)
+	}
+
+	// Deleting requires destructive actions to be enabled
+	err = repo.Delete(ctx)
+	validation.TestExpectErrors(t, "Repository.Delete", err, gitprovider.ErrDestructiveCallDisallowed)
+}
+
+func TestUserRepositoriesClient(t *testing.T) {
+	mux := http.NewServeMux()
+	srv := newFakeRepoServer(t, map[string]*Repository{})
+	srv.register(mux)
+	c := newTestClient(t, mux, WithDestructiveAPICalls(true))
+	ctx := context.Background()
+
+	ref := gitprovider.UserRepositoryRef{
+		UserRef:        gitprovider.UserRef{Domain: c.domain, UserLogin: "alice"},
+		RepositoryName: "repo",
+	}
+	repo, err := c.UserRepositories().Create(ctx, ref, gitprovider.RepositoryInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req := srv.repos["alice/repo"]; req == nil || !*req.Private || !req.Empty {
+		t.Errorf("unexpected create request: %+v", req)
+	}
+
+	repos, err := c.UserRepositories().List(ctx, ref.UserRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repos) != 1 {
+		t.Errorf("UserRepositories().List() = %v, want 1 repository", repos)
+	}
+
+	if err := repo.Delete(ctx); err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.UserRepositories().Get(ctx, ref)
+	validation.TestExpectErrors(t, "UserRepositories().Get", err, gitprovider.ErrNotFound)
+}
+
+func TestDeployKeyClient(t *testing.T) {
+	mux := http.NewServeMux()
+	srv := newFakeRepoServer(t, map[string]*Repository{
+		"org/repo": {Name: "repo", Private: gitprovider.BoolVar(true)},
+	})
+	srv.register(mux)
+	c := newTestClient(t, mux)
+	ctx := context.Background()
+
+	repo, err := c.OrgRepositories().Get(ctx, gitprovider.OrgRepositoryRef{
+		OrganizationRef: gitprovider.OrganizationRef{Domain: c.domain, Organization: "org"},
+		RepositoryName:  "repo",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = repo.DeployKeys().Get(ctx, "flux")
+	validation.TestExpectErrors(t, "DeployKeys().Get", err, gitprovider.ErrNotFound)
+
+	req := gitprovider.DeployKeyInfo{
+		Name: "flux",
+		Key:  []byte("ssh-ed25519 AAAA flux@example.com"),
+	}
+	_, actionTaken, err := repo.DeployKeys().Reconcile(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !actionTaken || len(srv.keys) != 1 || !srv.keys[0].ReadOnly {
+		t.Fatalf("expected Reconcile to create a read-only key, got %v", srv.keys)
+	}
+
+	_, err = repo.DeployKeys().Create(ctx, req)
+	validation.TestExpectErrors(t, "DeployKeys().Create", err, gitprovider.ErrAlreadyExists)
+
+	// Reconciling again is a no-op, even though the comment of the key was dropped
+	key, actionTaken, err := repo.DeployKeys().Reconcile(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actionTaken {
+		t.Errorf("expected Reconcile to be a no-op, got %s", key.Get().Key)
+	}
+
+	// Changing the access recreates the key
+	req.ReadOnly = gitprovider.BoolVar(false)
+	_, actionTaken, err = repo.DeployKeys().Reconcile(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !actionTaken || len(srv.keys) != 1 || srv.keys[0].ReadOnly {
+		t.Errorf("expected Reconcile to recreate the key with write access, got %v", srv.keys)
+	}
+
+	keys, err := repo.DeployKeys().List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || *keys[0].Get().ReadOnly {
+		t.Errorf("DeployKeys().List() = %v, want 1 read-write key", keys)
+	}
+	if err := keys[0].Delete(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(srv.keys) != 0 {
+		t.Errorf("expected the key to be deleted, got %v", srv.keys)
+	}
+}
+
+func TestTeamAccessClient(t *testing.T) {
+	mux := http.NewServeMux()
+	srv := newFakeRepoServer(t, map[string]*Repository{
+		"org/repo": {Name: "repo", Private: gitprovider.BoolVar(true)},
+	})
+	srv.teams = []*Team{
+		{ID: 1, Name: "readers", Permission: teamPermissionRead},
+		{ID: 2, Name: "devs", Permission: teamPermissionNone, UnitsMap: map[string]string{codeUnit: teamPermissionWrite}},
+	}
+	srv.register(mux)
+	c := newTestClient(t, mux)
+	ctx := context.Background()
+
+	repo, err := c.OrgRepositories().Get(ctx, gitprovider.OrgRepositoryRef{
+		OrganizationRef: gitprovider.OrganizationRef{Domain: c.domain, Organization: "org"},
+		RepositoryName:  "repo",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The default permission is pull, which matches the team
+	ta, actionTaken, err := repo.TeamAccess().Reconcile(ctx, gitprovider.TeamAccessInfo{Name: "readers"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !actionTaken || len(srv.repoTeams) != 1 || *ta.Get().Permission != gitprovider.RepositoryPermissionPull {
+		t.Errorf("expected Reconcile to add the team, got %v", srv.repoTeams)
+	}
+
+	// The permission of newer servers is read from the code unit
+	_, actionTaken, err = repo.TeamAccess().Reconcile(ctx, gitprovider.TeamAccessInfo{
+		Name:       "devs",
+		Permission: gitprovider.RepositoryPermissionVar(gitprovider.RepositoryPermissionPush),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !actionTaken || len(srv.repoTeams) != 2 {
+		t.Errorf("expected Reconcile to add the team, got %v", srv.repoTeams)
+	}
+	_, actionTaken, err = repo.TeamAccess().Reconcile(ctx, gitprovider.TeamAccessInfo{
+		Name:       "devs",
+		Permission: gitprovider.RepositoryPermissionVar(gitprovider.RepositoryPermissionPush),
+	})
+	if err != nil || actionTaken {
+		t.Errorf("expected Reconcile to be a no-op, got %v, %v", actionTaken, err)
+	}
+
+	// The permission can't be changed for a single repository
+	_, _, err = repo.TeamAccess().Reconcile(ctx, gitprovider.TeamAccessInfo{
+		Name:       "devs",
+		Permission: gitprovider.RepositoryPermissionVar(gitprovider.RepositoryPermissionAdmin),
+	})
+	validation.TestExpectErrors(t, "TeamAccess().Reconcile", err, gitprovider.ErrNoProviderSupport)
+
+	_, err = repo.TeamAccess().Create(ctx, gitprovider.TeamAccessInfo{Name: "missing"})
+	validation.TestExpectErrors(t, "TeamAccess().Create", err, gitprovider.ErrNotFound)
+
+	list, err := repo.TeamAccess().List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 {
+		t.Errorf("TeamAccess().List() = %v, want 2 teams", list)
+	}
+	if err := list[0].Delete(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(srv.repoTeams) != 1 || srv.repoTeams[0] != "devs" {
+		t.Errorf("expected readers to be removed, got %v", srv.repoTeams)
+	}
+}
+
+func TestCommitsBranchesPullRequests(t *testing.T) {
+	mux := http.NewServeMux()
+	srv := newFakeRepoServer(t, map[string]*Repository{
+		"org/repo": {Name: "repo", Private: gitprovider.BoolVar(true)},
+	})
+	srv.files["foo.txt"] = "old"
+	srv.files["gone.txt"] = "gone"
+	srv.register(mux)
+	c := newTestClient(t, mux)
+	ctx := context.Background()
+
+	repo, err := c.OrgRepositories().Get(ctx, gitprovider.OrgRepositoryRef{
+		OrganizationRef: gitprovider.OrganizationRef{Domain: c.domain, Organization: "org"},
+		RepositoryName:  "repo",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = repo.Commits().Create(ctx, "main", "empty", nil)
+	if err == nil {
+		t.Error("expected an error when committing no files")
+	}
+
+	// All files are changed in a single commit
+	commit, err := repo.Commits().Create(ctx, "main", "change files", []gitprovider.CommitFile{
+		{
+			Path:    gitprovider.StringVar("foo.txt"),
+			Content: gitprovider.StringVar("foo"),
+		},
+		{
+			Path:    gitprovider.StringVar("dir/bar.txt"),
+			Content: gitprovider.StringVar("bar"),
+		},
+		{
+			Path: gitprovider.StringVar("gone.txt"),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(srv.commits) != 1 || commit.Get().Sha != srv.commits[0].SHA || commit.Get().TreeSha == "" {
+		t.Errorf("unexpected commit %v, server has %v", commit.Get(), srv.commits)
+	}
+	if srv.files["foo.txt"] != "foo" || srv.files["dir/bar.txt"] != "bar" || len(srv.files) != 2 {
+		t.Errorf("unexpected files after commit: %v", srv.files)
+	}
+
+	commits, err := repo.Commits().ListPage(ctx, "main", 2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 1 || commits[0].Get().Sha != commit.Get().Sha {
+		t.Errorf("Commits().ListPage() = %v, want 1 commit", commits)
+	}
+
+	_, err = repo.Commits().Create(ctx, "main", "create existing", []gitprovider.CommitFile{{
+		Action:  gitprovider.CommitFileActionVar(gitprovider.CommitFileActionCreate),
+		Path:    gitprovider.StringVar("foo.txt"),
+		Content: gitprovider.StringVar("foo"),
+	}})
+	validation.TestExpectErrors(t, "Commits().Create", err, gitprovider.ErrAlreadyExists)
+	_, err = repo.Commits().Create(ctx, "main", "executable", []gitprovider.CommitFile{{
+		Path:    gitprovider.StringVar("run.sh"),
+		Content: gitprovider.StringVar("echo"),
+		Mode:    gitprovider.FileModeVar(gitprovider.FileModeExecutable),
+	}})
+	validation.TestExpectErrors(t, "Commits().Create", err, gitprovider.ErrNoProviderSupport)
+
+	// Moved files keep their content, unless it's given
+	parent := commit.Get().Sha
+	commit, err = repo.Commits().Create(ctx, "main", "move files", []gitprovider.CommitFile{
+		{
+			Action:       gitprovider.CommitFileActionVar(gitprovider.CommitFileActionMove),
+			Path:         gitprovider.StringVar("moved.txt"),
+			PreviousPath: gitprovider.StringVar("foo.txt"),
+		},
+		{
+			Action:       gitprovider.CommitFileActionVar(gitprovider.CommitFileActionMove),
+			Path:         gitprovider.StringVar("bar.bin"),
+			PreviousPath: gitprovider.StringVar("dir/bar.txt"),
+			Content:      gitprovider.StringVar("AAEC"),
+			Encoding:     gitprovider.ContentEncodingVar(gitprovider.ContentEncodingBase64),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if srv.files["moved.txt"] != "foo" || srv.files["bar.bin"] != "\x00\x01\x02" || len(srv.files) != 2 {
+		t.Errorf("unexpected files after moving: %v", srv.files)
+	}
+	_, err = repo.Commits().Create(ctx, "main", "stale", []gitprovider.CommitFile{{
+		Path:    gitprovider.StringVar("moved.txt"),
+		Content: gitprovider.StringVar("bar"),
+	}}, &gitprovider.CommitCreateOptions{ExpectedParentSHA: &parent})
+	validation.TestExpectErrors(t, "Commits().Create() on a moved branch", err, gitprovider.ErrConflict)
+	if srv.files["moved.txt"] != "foo" {
+		t.Errorf("stale commit changed moved.txt to %q", srv.files["moved.txt"])
+	}
+
+	if err := repo.Branches().Create(ctx, "feature", commit.Get().Sha); err != nil {
+		t.Fatal(err)
+	}
+	err = repo.Branches().Create(ctx, "feature", commit.Get().Sha)
+	validation.TestExpectErrors(t, "Branches().Create", err, gitprovider.ErrAlreadyExists)
+
+	srv.branches["main"].Protected = true
+	branch, err := repo.Branches().Get(ctx, "main")
+	if want := (gitprovider.BranchInfo{Name: "main", Sha: commit.Get().Sha, Protected: true}); err != nil || branch.Get() != want {
+		t.Errorf("Branches().Get() = %v, %v, want %+v", branch, err, want)
+	}
+	_, err = repo.Branches().Get(ctx, "missing")
+	validation.TestExpectErrors(t, "Branches().Get", err, gitprovider.ErrNotFound)
+
+	other, err := repo.Branches().CreateFrom(ctx, "other", "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (gitprovider.BranchInfo{Name: "other", Sha: commit.Get().Sha}); other.Get() != want {
+		t.Errorf("Branches().CreateFrom() = %+v, want %+v", other.Get(), want)
+	}
+	_, err = repo.Branches().CreateFrom(ctx, "another", "missing")
+	validation.TestExpectErrors(t, "Branches().CreateFrom", err, gitprovider.ErrNotFound)
+
+	branches, err := repo.Branches().List(ctx)
+	if err != nil || len(branches) != 3 {
+		t.Errorf("Branches().List() = %v, %v, want 3 branches", branches, err)
+	}
+	err = repo.Branches().Delete(ctx, "other")
+	validation.TestExpectErrors(t, "Branches().Delete", err, gitprovider.ErrDestructiveCallDisallowed)
+	dc := newTestClient(t, mux, WithDestructiveAPICalls(true))
+	drepo, err := dc.OrgRepositories().Get(ctx, gitprovider.OrgRepositoryRef{
+		OrganizationRef: gitprovider.OrganizationRef{Domain: dc.domain, Organization: "org"},
+		RepositoryName:  "repo",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := drepo.Branches().Delete(ctx, "other"); err != nil {
+		t.Fatal(err)
+	}
+	err = drepo.Branches().Delete(ctx, "other")
+	validation.TestExpectErrors(t, "Branches().Delete", err, gitprovider.ErrNotFound)
+
+	pr, err := repo.PullRequests().Create(ctx, "title", "feature", "main", "description")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := pr.Get().WebURL; got != "https://gitea.example.com/org/repo/pulls/1" {
+		t.Errorf("PullRequest.Get().WebURL = %q", got)
+	}
+	if got := srv.prs[0]; got.Head.Ref != "feature" || got.Base.Ref != "main" || got.Body != "description" {
+		t.Errorf("unexpected pull request: %+v", got)
+	}
+	pr, err = repo.PullRequests().Get(ctx, 1)
+	if err != nil || pr.Get().Number != 1 || pr.Get().State != gitprovider.PullRequestStateOpen || pr.Get().HeadBranch != "feature" {
+		t.Errorf("PullRequests().Get() = %+v, %v", pr, err)
+	}
+	_, err = repo.PullRequests().Get(ctx, 2)
+	validation.TestExpectErrors(t, "PullRequests().Get", err, gitprovider.ErrNotFound)
+
+	pr, err = repo.PullRequests().Update(ctx, 1, gitprovider.PullRequestUpdateOptions{Title: gitprovider.StringVar("new title")})
+	if err != nil || pr.Get().Title != "new title" || pr.Get().Description != "description" {
+		t.Errorf("PullRequests().Update() = %+v, %v", pr, err)
+	}
+
+	req := gitprovider.PullRequestInfo{Title: "new title", Description: "description", HeadBranch: "feature", BaseBranch: "main"}
+	pr, actionTaken, err := repo.PullRequests().Reconcile(ctx, req)
+	if err != nil || actionTaken || pr.Get().Number != 1 {
+		t.Errorf("PullRequests().Reconcile() = %+v, %v, %v", pr, actionTaken, err)
+	}
+	req.Labels = []string{"bot"}
+	_, _, err = repo.PullRequests().Reconcile(ctx, req)
+	validation.TestExpectErrors(t, "PullRequests().Reconcile", err, gitprovider.ErrNoProviderSupport)
+
+	comment, err := pr.Comments().Create(ctx, "plan output")
+	if err != nil || comment.Get().ID != 1 || comment.Get().Author != "me" {
+		t.Errorf("Comments().Create() = %+v, %v", comment, err)
+	}
+	if comment, err = pr.Comments().Update(ctx, 1, "new plan output"); err != nil || comment.Get().Body != "new plan output" {
+		t.Errorf("Comments().Update() = %+v, %v", comment, err)
+	}
+	comments, err := pr.Comments().List(ctx)
+	if err != nil || len(comments) != 1 || comments[0].Get().Body != "new plan output" {
+		t.Errorf("Comments().List() = %v, %v", comments, err)
+	}
+	if err := pr.Comments().Delete(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	err = pr.Comments().Delete(ctx, 1)
+	validation.TestExpectErrors(t, "Comments().Delete", err, gitprovider.ErrNotFound)
+
+	review, err := pr.Reviews().Submit(ctx, gitprovider.PullRequestReviewStateChangesRequested, "needs work")
+	if err != nil || review.Get().State != gitprovider.PullRequestReviewStateChangesRequested || review.Get().Body != "needs work" {
+		t.Errorf("Reviews().Submit() = %+v, %v", review, err)
+	}
+	srv.reviews = append(srv.reviews, &PullReview{ID: 2, State: reviewStatePending})
+	reviews, err := pr.Reviews().List(ctx)
+	if err != nil || len(reviews) != 1 || reviews[0].Get().Author != "me" {
+		t.Errorf("Reviews().List() = %v, %v", reviews, err)
+	}
+	_, err = pr.Reviews().Submit(ctx, gitprovider.PullRequestReviewState("dismissed"), "")
+	validation.TestExpectErrors(t, "Reviews().Submit", err, gitprovider.ErrInvalidArgument)
+
+	if err := repo.PullRequests().Merge(ctx, 1, gitprovider.MergeMethodSquash, "squashed"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repo.PullRequests().Create(ctx, "other", "other", "main", ""); err != nil {
+		t.Fatal(err)
+	}
+	pr, err = repo.PullRequests().Close(ctx, 2)
+	if err != nil || pr.Get().State != gitprovider.PullRequestStateClosed {
+		t.Errorf("PullRequests().Close() = %+v, %v", pr, err)
+	}
+
+	prs, err := repo.PullRequests().List(ctx, &gitprovider.PullRequestListOptions{State: gitprovider.PullRequestStateVar(gitprovider.PullRequestStateMerged)})
+	if err != nil || len(prs) != 1 || prs[0].Get().Number != 1 {
+		t.Errorf("PullRequests().List(merged) = %v, %v", prs, err)
+	}
+	prs, err = repo.PullRequests().List(ctx, &gitprovider.PullRequestListOptions{HeadBranch: gitprovider.StringVar("other")})
+	if err != nil || len(prs) != 1 || prs[0].Get().Number != 2 {
+		t.Errorf("PullRequests().List(head) = %v, %v", prs, err)
+	}
+
+	// New branches need a start branch, as the repository isn't empty anymore
+	files := []gitprovider.CommitFile{{Path: gitprovider.StringVar("new.txt"), Content: gitprovider.StringVar("new")}}
+	_, err = repo.Commits().Create(ctx, "new", "no start", files)
+	validation.TestExpectErrors(t, "Commits().Create() on a missing branch", err, gitprovider.ErrNotFound)
+	_, err = repo.Commits().Create(ctx, "new", "missing start", files, &gitprovider.CommitCreateOptions{StartBranch: gitprovider.StringVar("missing")})
+	validation.TestExpectErrors(t, "Commits().Create() from a missing branch", err, gitprovider.ErrNotFound)
+	mainHead := srv.branches["main"].Commit.ID
+	author := &gitprovider.CommitAuthor{Name: "Jane", Email: "jane@example.com", Date: time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)}
+	commit, err = repo.Commits().Create(ctx, "new", "start", files, &gitprovider.CommitCreateOptions{
+		StartBranch:       gitprovider.StringVar("main"),
+		ExpectedParentSHA: &mainHead,
+		Author:            author,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if srv.branches["new"] == nil || srv.branches["new"].Commit.ID != commit.Get().Sha || srv.branches["main"].Commit.ID != mainHead {
+		t.Errorf("unexpected branches after creating one from main: %v", srv.branches)
+	}
+	if got := commit.Get(); !reflect.DeepEqual(got.Author, author) || !reflect.DeepEqual(got.ParentShas, []string{mainHead}) {
+		t.Errorf("Commits().Create() = %+v, want author %+v and parent %q", got, author, mainHead)
+	}
+}
+
+func TestBranchProtectionClient(t *testing.T) {
+	mux := http.NewServeMux()
+	srv := newFakeRepoServer(t, map[string]*Repository{
+		"org/repo": {Name: "repo", Private: gitprovider.BoolVar(true)},
+	})
+	srv.branches["main"] = &Branch{Name: "main", Commit: &PayloadCommit{ID: blobSHA("main")}}
+	srv.register(mux)
+	c := newTestClient(t, mux)
+	ctx := context.Background()
+
+	repo, err := c.OrgRepositories().Get(ctx, gitprovider.OrgRepositoryRef{
+		OrganizationRef: gitprovider.OrganizationRef{Domain: c.domain, Organization: "org"},
+		RepositoryName:  "repo",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = repo.BranchProtections().Get(ctx, "main")
+	validation.TestExpectErrors(t, "BranchProtections().Get", err, gitprovider.ErrNotFound)
+	_, _, err = repo.BranchProtections().Reconcile(ctx, gitprovider.BranchProtectionInfo{Branch: "missing"})
+	validation.TestExpectErrors(t, "BranchProtections().Reconcile", err, gitprovider.ErrNotFound)
+	_, _, err = repo.BranchProtections().Reconcile(ctx, gitprovider.BranchProtectionInfo{
+		Branch:           "main",
+		AllowForcePushes: gitprovider.BoolVar(true),
+	})
+	validation.TestExpectErrors(t, "BranchProtections().Reconcile", err, gitprovider.ErrNoProviderSupport)
+
+	req := gitprovider.BranchProtectionInfo{
+		Branch:               "main",
+		RequiredApprovals:    gitprovider.IntVar(1),
+		RequiredStatusChecks: []string{"ci"},
+	}
+	req.Default()
+	bp, actionTaken, err := repo.BranchProtections().Reconcile(ctx, req)
+	if err != nil || !actionTaken {
+		t.Fatalf("BranchProtections().Reconcile() = %v, %v, want created", actionTaken, err)
+	}
+	if p := srv.protections["main"]; !p.EnablePush || p.EnablePushWhitelist || !p.EnableStatusCheck || p.RequiredApprovals != 1 {
+		t.Errorf("unexpected protection %+v", p)
+	}
+	if !req.Equals(bp.Get()) {
+		t.Errorf("BranchProtections().Reconcile() = %+v, want %+v", bp.Get(), req)
+	}
+
+	_, actionTaken, err = repo.BranchProtections().Reconcile(ctx, req)
+	if err != nil || actionTaken {
+		t.Errorf("BranchProtections().Reconcile() = %v, %v, want no-op", actionTaken, err)
+	}
+
+	req.RestrictPushes = gitprovider.BoolVar(true)
+	req.RequiredStatusChecks = nil
+	_, actionTaken, err = repo.BranchProtections().Reconcile(ctx, req)
+	if err != nil || !actionTaken {
+		t.Fatalf("BranchProtections().Reconcile() = %v, %v, want updated", actionTaken, err)
+	}
+	if p := srv.protections["main"]; !p.EnablePushWhitelist || p.EnableStatusCheck || len(p.PushWhitelistUsernames) != 0 {
+		t.Errorf("unexpected protection %+v", p)
+	}
+	bp, err = repo.BranchProtections().Get(ctx, "main")
+	if err != nil || !req.Equals(bp.Get()) {
+		t.Errorf("BranchProtections().Get() = %v, %v, want %+v", bp, err, req)
+	}
+
+	if err := repo.BranchProtections().Delete(ctx, "main"); err != nil {
+		t.Fatal(err)
+	}
+	err = repo.BranchProtections().Delete(ctx, "main")
+	validation.TestExpectErrors(t, "BranchProtections().Delete", err, gitprovider.ErrNotFound)
+}
+
+func TestFileClient(t *testing.T) {
+	mux := http.NewServeMux()
+	srv := newFakeRepoServer(t, map[string]*Repository{
+		"org/repo": {Name: "repo", Private: gitprovider.BoolVar(true)},
+	})
+	srv.branches["main"] = &Branch{Name: "main", Commit: &PayloadCommit{ID: blobSHA("main")}}
+	srv.files["README.md"] = "# repo"
+	srv.files["dir/foo.txt"] = "foo"
+	srv.files["dir/sub/bar.txt"] = "bar"
+	srv.register(mux)
+	c := newTestClient(t, mux)
+	ctx := context.Background()
+
+	repo, err := c.OrgRepositories().Get(ctx, gitprovider.OrgRepositoryRef{
+		OrganizationRef: gitprovider.OrganizationRef{Domain: c.domain, Organization: "org"},
+		RepositoryName:  "repo",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Nested directories are found by walking down the trees
+	file, err := repo.Files().Get(ctx, "dir/sub/bar.txt", "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := gitprovider.FileInfo{Path: "dir/sub/bar.txt", Mode: gitprovider.FileModeRegular, Sha: blobSHA("bar"), Size: 3, Content: []byte("bar")}
+	if got := file.Get(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Files().Get() = %+v, want %+v", got, want)
+	}
+	_, err = repo.Files().Get(ctx, "dir/sub", "main")
+	validation.TestExpectErrors(t, "Files().Get() of a directory", err, gitprovider.ErrNotFound)
+	_, err = repo.Files().Get(ctx, "README.md", "missing")
+	validation.TestExpectErrors(t, "Files().Get() of a missing ref", err, gitprovider.ErrNotFound)
+
+	files, err := repo.Files().List(ctx, "dir", "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 || files[0].Get().Path != "dir/foo.txt" || files[0].Get().Content != nil ||
+		files[1].Get().Path != "dir/sub" || !files[1].Get().IsDir() {
+		t.Errorf("Files().List() = %+v", files)
+	}
+	files, err = repo.Files().List(ctx, "", "main")
+	if err != nil || len(files) != 2 || files[0].Get().Path != "README.md" {
+		t.Errorf("Files().List() of the root directory = %v, %v", files, err)
+	}
+	_, err = repo.Files().List(ctx, "dir/missing", "main")
+	validation.TestExpectErrors(t, "Files().List() of a missing directory", err, gitprovider.ErrNotFound)
+}
+
+func TestClient_invalidCredentials(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/user/orgs", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "token wrong" {
+			t.Errorf("unexpected Authorization header %q", r.Header.Get("Authorization"))
+		}
+		writeError(t, w, http.StatusUnauthorized, "user does not exist [uid: 0, name: ]")
+	})
+	c := newTestClient(t, mux, WithToken("wrong"))
+	_, err := c.Organizations().List(context.Background())
+	validation.TestExpectErrors(t, "Organizations().List", err, &gitprovider.InvalidCredentialsError{})
+	var errResp *ErrorResponse
+	if !errors.As(err, &errResp) || !strings.HasPrefix(errResp.Message, "user does not exist") {
+		t.Errorf("expected the error to wrap the ErrorResponse, got %v", err)
+	}
+	var credErr *gitprovider.InvalidCredentialsError
+	if errors.As(err, &credErr) && credErr.DocumentationURL != "https://gitea.example.com/api/swagger" {
+		t.Errorf("expected the documentation URL of the server, got %q", credErr.DocumentationURL)
+	}
+}
+
+func TestClient_basicAuth(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/orgs/org", func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "user" || pass != "pass" {
+			t.Errorf("unexpected basic auth %q:%q", user, pass)
+		}
+		writeJSON(t, w, http.StatusOK, Organization{ID: 1, UserName: "org"})
+	})
+	c := newTestClient(t, mux, WithBasicAuth("user", "pass"))
+	if _, err := c.Organizations().Get(context.Background(), gitprovider.OrganizationRef{Domain: c.domain, Organization: "org"}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,985 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+const (
+	// apiPath is the path of the v1 REST API, relative to the domain URL.
+	apiPath = "api/v1"
+	// defaultPageSize is the page size used for listing. The server caps it at its
+	// configured maximum, and the Link header is used to find the next page anyway.
+	defaultPageSize = 50
+)
+
+// nextLinkRegexp matches the URL of the next page in a Link header.
+var nextLinkRegexp = regexp.MustCompile(`<([^>]+)>;\s*rel="next"`)
+
+// giteaClient is a wrapper around the Gitea REST API, which implements higher-level methods,
+// operating on the structs in types.go. Pagination is implemented for all List* methods, all
+// returned objects are validated, and HTTP errors are handled/wrapped using handleHTTPError.
+// This interface is also fakeable, in order to unit-test the client.
+type giteaClient interface {
+	// Client returns the underlying *http.Client
+	Client() *http.Client
+
+	// GetOrg is a wrapper for "GET /orgs/{org}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetOrg(ctx context.Context, orgName string) (*Organization, error)
+	// ListOrgs is a wrapper for "GET /user/orgs".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListOrgs(ctx context.Context) ([]*Organization, error)
+
+	// ListOrgTeams is a wrapper for "GET /orgs/{org}/teams".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListOrgTeams(ctx context.Context, orgName string) ([]*Team, error)
+	// ListTeamMembers is a wrapper for "GET /teams/{id}/members".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListTeamMembers(ctx context.Context, teamID int64) ([]*User, error)
+
+	// GetRepo is a wrapper for "GET /repos/{owner}/{repo}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetRepo(ctx context.Context, owner, repo string) (*Repository, error)
+	// ListOrgRepos is a wrapper for "GET /orgs/{org}/repos".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListOrgRepos(ctx context.Context, org string) ([]*Repository, error)
+	// ListUserRepos is a wrapper for "GET /users/{username}/repos".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListUserRepos(ctx context.Context, username string) ([]*Repository, error)
+	// CreateRepo is a wrapper for "POST /user/repos" (if orgName == "")
+	// or "POST /orgs/{org}/repos" (if orgName != "").
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateRepo(ctx context.Context, orgName string, req *CreateRepoOption) (*Repository, error)
+	// UpdateRepo is a wrapper for "PATCH /repos/{owner}/{repo}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	UpdateRepo(ctx context.Context, owner, repo string, req *Repository) (*Repository, error)
+	// DeleteRepo is a wrapper for "DELETE /repos/{owner}/{repo}".
+	// This function handles HTTP error wrapping.
+	// DANGEROUS COMMAND: In order to use this, you must set destructiveActions to true.
+	DeleteRepo(ctx context.Context, owner, repo string) error
+
+	// ListKeys is a wrapper for "GET /repos/{owner}/{repo}/keys".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListKeys(ctx context.Context, owner, repo string) ([]*DeployKey, error)
+	// CreateKey is a wrapper for "POST /repos/{owner}/{repo}/keys".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateKey(ctx context.Context, owner, repo string, req *DeployKey) (*DeployKey, error)
+	// DeleteKey is a wrapper for "DELETE /repos/{owner}/{repo}/keys/{id}".
+	// This function handles HTTP error wrapping.
+	DeleteKey(ctx context.Context, owner, repo string, id int64) error
+
+	// GetRepoTeam is a wrapper for "GET /repos/{owner}/{repo}/teams/{team}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetRepoTeam(ctx context.Context, owner, repo, teamName string) (*Team, error)
+	// ListRepoTeams is a wrapper for "GET /repos/{owner}/{repo}/teams".
+	// This function handles HTTP error wrapping, and validates the server result.
+	ListRepoTeams(ctx context.Context, owner, repo string) ([]*Team, error)
+	// AddRepoTeam is a wrapper for "PUT /repos/{owner}/{repo}/teams/{team}".
+	// This function handles HTTP error wrapping.
+	AddRepoTeam(ctx context.Context, owner, repo, teamName string) error
+	// RemoveRepoTeam is a wrapper for "DELETE /repos/{owner}/{repo}/teams/{team}".
+	// This function handles HTTP error wrapping.
+	RemoveRepoTeam(ctx context.Context, owner, repo, teamName string) error
+
+	// ListCommitsPage is a wrapper for "GET /repos/{owner}/{repo}/commits".
+	// This function handles HTTP error wrapping, and validates the server result.
+	ListCommitsPage(ctx context.Context, owner, repo, branch string, perPage int, page int) ([]*Commit, error)
+	// CreateCommit is a wrapper for "POST /repos/{owner}/{repo}/contents", with
+	// "GET /repos/{owner}/{repo}/contents/{filepath}" to tell whether files are created or updated.
+	// If startBranch is set, branch is created from it.
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateCommit(ctx context.Context, owner, repo, branch, startBranch, message string, author, committer *gitprovider.CommitAuthor, files []gitprovider.CommitFile) (*Commit, error)
+	// GetTree is a wrapper for "GET /repos/{owner}/{repo}/git/trees/{sha}", where sha is a tree
+	// sha, or a branch, tag or commit sha to get the root tree of.
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	GetTree(ctx context.Context, owner, repo, sha string) (*GitTreeResponse, error)
+	// GetBlob is a wrapper for "GET /repos/{owner}/{repo}/git/blobs/{sha}", returning the
+	// decoded content of the blob.
+	// This function handles HTTP error wrapping.
+	GetBlob(ctx context.Context, owner, repo, sha string) ([]byte, error)
+	// GetBranch is a wrapper for "GET /repos/{owner}/{repo}/branches/{branch}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetBranch(ctx context.Context, owner, repo, branch string) (*Branch, error)
+	// ListBranches is a wrapper for "GET /repos/{owner}/{repo}/branches".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListBranches(ctx context.Context, owner, repo string) ([]*Branch, error)
+	// CreateBranch is a wrapper for "POST /repos/{owner}/{repo}/branches".
+	// ref can be a commit SHA, a branch or a tag name, and is resolved by the server.
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateBranch(ctx context.Context, owner, repo, branch, ref string) (*Branch, error)
+	// DeleteBranch is a wrapper for "DELETE /repos/{owner}/{repo}/branches/{branch}".
+	// This function handles HTTP error wrapping.
+	// DANGEROUS COMMAND: In order to use this, you must set destructiveActions to true.
+	DeleteBranch(ctx context.Context, owner, repo, branch string) error
+	// GetBranchProtection is a wrapper for "GET /repos/{owner}/{repo}/branch_protections/{name}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetBranchProtection(ctx context.Context, owner, repo, branch string) (*BranchProtection, error)
+	// CreateBranchProtection is a wrapper for "POST /repos/{owner}/{repo}/branch_protections".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateBranchProtection(ctx context.Context, owner, repo string, req *BranchProtection) (*BranchProtection, error)
+	// EditBranchProtection is a wrapper for "PATCH /repos/{owner}/{repo}/branch_protections/{name}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	EditBranchProtection(ctx context.Context, owner, repo string, req *BranchProtection) (*BranchProtection, error)
+	// DeleteBranchProtection is a wrapper for "DELETE /repos/{owner}/{repo}/branch_protections/{name}".
+	// This function handles HTTP error wrapping.
+	DeleteBranchProtection(ctx context.Context, owner, repo, branch string) error
+	// CreatePullRequest is a wrapper for "POST /repos/{owner}/{repo}/pulls".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreatePullRequest(ctx context.Context, owner, repo string, req *CreatePullRequestOption) (*PullRequest, error)
+	// GetPullRequest is a wrapper for "GET /repos/{owner}/{repo}/pulls/{index}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetPullRequest(ctx context.Context, owner, repo string, index int) (*PullRequest, error)
+	// ListPullRequests is a wrapper for "GET /repos/{owner}/{repo}/pulls".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListPullRequests(ctx context.Context, owner, repo, state string) ([]*PullRequest, error)
+	// EditPullRequest is a wrapper for "PATCH /repos/{owner}/{repo}/pulls/{index}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	EditPullRequest(ctx context.Context, owner, repo string, index int, req *EditPullRequestOption) (*PullRequest, error)
+	// MergePullRequest is a wrapper for "POST /repos/{owner}/{repo}/pulls/{index}/merge".
+	// This function handles HTTP error wrapping.
+	MergePullRequest(ctx context.Context, owner, repo string, index int, req *MergePullRequestOption) error
+	// ListIssueComments is a wrapper for "GET /repos/{owner}/{repo}/issues/{index}/comments".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListIssueComments(ctx context.Context, owner, repo string, index int) ([]*Comment, error)
+	// CreateIssueComment is a wrapper for "POST /repos/{owner}/{repo}/issues/{index}/comments".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateIssueComment(ctx context.Context, owner, repo string, index int, body string) (*Comment, error)
+	// EditIssueComment is a wrapper for "PATCH /repos/{owner}/{repo}/issues/comments/{id}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	EditIssueComment(ctx context.Context, owner, repo string, id int64, body string) (*Comment, error)
+	// DeleteIssueComment is a wrapper for "DELETE /repos/{owner}/{repo}/issues/comments/{id}".
+	// This function handles HTTP error wrapping.
+	DeleteIssueComment(ctx context.Context, owner, repo string, id int64) error
+	// ListPullReviews is a wrapper for "GET /repos/{owner}/{repo}/pulls/{index}/reviews".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListPullReviews(ctx context.Context, owner, repo string, index int) ([]*PullReview, error)
+	// CreatePullReview is a wrapper for "POST /repos/{owner}/{repo}/pulls/{index}/reviews".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreatePullReview(ctx context.Context, owner, repo string, index int, req *CreatePullReviewOptions) (*PullReview, error)
+}
+
+// giteaClientImpl is a wrapper around *http.Client, which implements higher-level methods,
+// operating on the structs in types.go. See the giteaClient interface for method documentation.
+// Pagination is implemented for all List* methods, all returned
+// objects are validated, and HTTP errors are handled/wrapped using handleHTTPError.
+type giteaClientImpl struct {
+	c                  *http.Client
+	domainURL          string
+	destructiveActions bool
+}
+
+// giteaClientImpl implements giteaClient.
+var _ giteaClient = &giteaClientImpl{}
+
+func (c *giteaClientImpl) Client() *http.Client {
+	return c.c
+}
+
+func (c *giteaClientImpl) GetOrg(ctx context.Context, orgName string) (*Organization, error) {
+	apiObj := &Organization{}
+	// GET /orgs/{org}
+	if err := c.do(ctx, http.MethodGet, c.url(nil, "orgs", orgName), nil, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Validate the API object
+	if err := validateOrganizationAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *giteaClientImpl) ListOrgs(ctx context.Context) ([]*Organization, error) {
+	apiObjs := []*Organization{}
+	// GET /user/orgs
+	err := c.allPages(ctx, c.url(nil, "user", "orgs"), func(data []byte) error {
+		var pageObjs []*Organization
+		err := json.Unmarshal(data, &pageObjs)
+		apiObjs = append(apiObjs, pageObjs...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate the API objects
+	for _, apiObj := range apiObjs {
+		if err := validateOrganizationAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *giteaClientImpl) ListOrgTeams(ctx context.Context, orgName string) ([]*Team, error) {
+	apiObjs := []*Team{}
+	// GET /orgs/{org}/teams
+	err := c.allPages(ctx, c.url(nil, "orgs", orgName, "teams"), func(data []byte) error {
+		var pageObjs []*Team
+		err := json.Unmarshal(data, &pageObjs)
+		apiObjs = append(apiObjs, pageObjs...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return validateTeamObjects(apiObjs)
+}
+
+func validateTeamObjects(apiObjs []*Team) ([]*Team, error) {
+	// Make sure the ID and Name fields are set.
+	for _, apiObj := range apiObjs {
+		if apiObj.ID == 0 || len(apiObj.Name) == 0 {
+			return nil, fmt.Errorf("didn't expect id or name to be unset for team: %+v: %w", apiObj, gitprovider.ErrInvalidServerData)
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *giteaClientImpl) ListTeamMembers(ctx context.Context, teamID int64) ([]*User, error) {
+	apiObjs := []*User{}
+	// GET /teams/{id}/members
+	err := c.allPages(ctx, c.url(nil, "teams", strconv.FormatInt(teamID, 10), "members"), func(data []byte) error {
+		var pageObjs []*User
+		err := json.Unmarshal(data, &pageObjs)
+		apiObjs = append(apiObjs, pageObjs...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Make sure the UserName field is set.
+	for _, apiObj := range apiObjs {
+		if len(apiObj.UserName) == 0 {
+			return nil, fmt.Errorf("didn't expect login to be empty for user: %+v: %w", apiObj, gitprovider.ErrInvalidServerData)
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *giteaClientImpl) GetRepo(ctx context.Context, owner, repo string) (*Repository, error) {
+	apiObj := &Repository{}
+	// GET /repos/{owner}/{repo}
+	err := c.do(ctx, http.MethodGet, c.url(nil, "repos", owner, repo), nil, apiObj)
+	return validateRepositoryAPIResp(apiObj, err)
+}
+
+func validateRepositoryAPIResp(apiObj *Repository, err error) (*Repository, error) {
+	// If the response contained an error, return
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Make sure apiObj is valid
+	if err := validateRepositoryAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *giteaClientImpl) ListOrgRepos(ctx context.Context, org string) ([]*Repository, error) {
+	// GET /orgs/{org}/repos
+	return c.listRepos(ctx, c.url(nil, "orgs", org, "repos"))
+}
+
+func (c *giteaClientImpl) ListUserRepos(ctx context.Context, username string) ([]*Repository, error) {
+	// GET /users/{username}/repos
+	return c.listRepos(ctx, c.url(nil, "users", username, "repos"))
+}
+
+func (c *giteaClientImpl) listRepos(ctx context.Context, urlStr string) ([]*Repository, error) {
+	var apiObjs []*Repository
+	err := c.allPages(ctx, urlStr, func(data []byte) error {
+		var pageObjs []*Repository
+		err := json.Unmarshal(data, &pageObjs)
+		apiObjs = append(apiObjs, pageObjs...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, apiObj := range apiObjs {
+		// Make sure apiObj is valid
+		if err := validateRepositoryAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *giteaClientImpl) CreateRepo(ctx context.Context, orgName string, req *CreateRepoOption) (*Repository, error) {
+	// POST /user/repos (if orgName == "")
+	// POST /orgs/{org}/repos (if orgName != "")
+	urlStr := c.url(nil, "user", "repos")
+	if len(orgName) != 0 {
+		urlStr = c.url(nil, "orgs", orgName, "repos")
+	}
+	apiObj := &Repository{}
+	err := c.do(ctx, http.MethodPost, urlStr, req, apiObj)
+	return validateRepositoryAPIResp(apiObj, err)
+}
+
+func (c *giteaClientImpl) UpdateRepo(ctx context.Context, owner, repo string, req *Repository) (*Repository, error) {
+	apiObj := &Repository{}
+	// PATCH /repos/{owner}/{repo}
+	err := c.do(ctx, http.MethodPatch, c.url(nil, "repos", owner, repo), req, apiObj)
+	return validateRepositoryAPIResp(apiObj, err)
+}
+
+func (c *giteaClientImpl) DeleteRepo(ctx context.Context, owner, repo string) error {
+	// Don't allow deleting repositories if the user didn't explicitly allow dangerous API calls.
+	if !c.destructiveActions {
+		return fmt.Errorf("cannot delete repository: %w", gitprovider.ErrDestructiveCallDisallowed)
+	}
+	// DELETE /repos/{owner}/{repo}
+	err := c.do(ctx, http.MethodDelete, c.url(nil, "repos", owner, repo), nil, nil)
+	return handleHTTPError(err)
+}
+
+func (c *giteaClientImpl) ListKeys(ctx context.Context, owner, repo string) ([]*DeployKey, error) {
+	apiObjs := []*DeployKey{}
+	// GET /repos/{owner}/{repo}/keys
+	err := c.allPages(ctx, c.url(nil, "repos", owner, repo, "keys"), func(data []byte) error {
+		var pageObjs []*DeployKey
+		err := json.Unmarshal(data, &pageObjs)
+		apiObjs = append(apiObjs, pageObjs...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, apiObj := range apiObjs {
+		if err := validateDeployKeyAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *giteaClientImpl) CreateKey(ctx context.Context, owner, repo string, req *DeployKey) (*DeployKey, error) {
+	apiObj := &DeployKey{}
+	// POST /repos/{owner}/{repo}/keys
+	if err := c.do(ctx, http.MethodPost, c.url(nil, "repos", owner, repo, "keys"), req, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validateDeployKeyAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *giteaClientImpl) DeleteKey(ctx context.Context, owner, repo string, id int64) error {
+	// DELETE /repos/{owner}/{repo}/keys/{id}
+	err := c.do(ctx, http.MethodDelete, c.url(nil, "repos", owner, repo, "keys", strconv.FormatInt(id, 10)), nil, nil)
+	return handleHTTPError(err)
+}
+
+func (c *giteaClientImpl) GetRepoTeam(ctx context.Context, owner, repo, teamName string) (*Team, error) {
+	apiObj := &Team{}
+	// GET /repos/{owner}/{repo}/teams/{team}
+	if err := c.do(ctx, http.MethodGet, c.url(nil, "repos", owner, repo, "teams", teamName), nil, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if _, err := validateTeamObjects([]*Team{apiObj}); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *giteaClientImpl) ListRepoTeams(ctx context.Context, owner, repo string) ([]*Team, error) {
+	apiObjs := []*Team{}
+	// GET /repos/{owner}/{repo}/teams
+	if err := c.do(ctx, http.MethodGet, c.url(nil, "repos", owner, repo, "teams"), nil, &apiObjs); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	return validateTeamObjects(apiObjs)
+}
+
+func (c *giteaClientImpl) AddRepoTeam(ctx context.Context, owner, repo, teamName string) error {
+	// PUT /repos/{owner}/{repo}/teams/{team}
+	err := c.do(ctx, http.MethodPut, c.url(nil, "repos", owner, repo, "teams", teamName), nil, nil)
+	return handleHTTPError(err)
+}
+
+func (c *giteaClientImpl) RemoveRepoTeam(ctx context.Context, owner, repo, teamName string) error {
+	// DELETE /repos/{owner}/{repo}/teams/{team}
+	err := c.do(ctx, http.MethodDelete, c.url(nil, "repos", owner, repo, "teams", teamName), nil, nil)
+	return handleHTTPError(err)
+}
+
+func (c *giteaClientImpl) ListCommitsPage(ctx context.Context, owner, repo, branch string, perPage int, page int) ([]*Commit, error) {
+	// Skip the expensive parts of the response, which are not used
+	query := url.Values{}
+	query.Set("sha", branch)
+	query.Set("stat", "false")
+	query.Set("verification", "false")
+	query.Set("files", "false")
+	if perPage > 0 {
+		query.Set("limit", strconv.Itoa(perPage))
+	}
+	if page > 0 {
+		query.Set("page", strconv.Itoa(page))
+	}
+
+	apiObjs := []*Commit{}
+	// GET /repos/{owner}/{repo}/commits
+	if err := c.do(ctx, http.MethodGet, c.url(query, "repos", owner, repo, "commits"), nil, &apiObjs); err != nil {
+		return nil, handleHTTPError(err)
+	}
+
+	for _, apiObj := range apiObjs {
+		if err := validateCommitAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *giteaClientImpl) CreateCommit(ctx context.Context, owner, repo, branch, startBranch, message string, author, committer *gitprovider.CommitAuthor, files []gitprovider.CommitFile) (*Commit, error) {
+	req := &ChangeFilesOptions{
+		Branch:    branch,
+		Message:   message,
+		Author:    commitUserToAPI(author),
+		Committer: commitUserToAPI(committer),
+		Dates:     commitDatesToAPI(author, committer),
+		Files:     make([]*ChangeFileOperation, 0, len(files)),
+	}
+	// The files of new branches are changed on top of the branch they're created from
+	if len(startBranch) != 0 {
+		req.Branch = startBranch
+		req.NewBranch = branch
+	}
+	for _, file := range files {
+		op, err := c.changeFileOperation(ctx, owner, repo, req.Branch, file)
+		if err != nil {
+			return nil, err
+		}
+		req.Files = append(req.Files, op)
+	}
+
+	resp := &FilesResponse{}
+	// POST /repos/{owner}/{repo}/contents
+	if err := c.do(ctx, http.MethodPost, c.url(nil, "repos", owner, repo, "contents"), req, resp); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if resp.Commit == nil {
+		return nil, fmt.Errorf("didn't expect commit to be nil in response: %w", gitprovider.ErrInvalidServerData)
+	}
+
+	// Convert to the same representation as the commits in ListCommitsPage
+	apiObj := &Commit{
+		CommitMeta: resp.Commit.CommitMeta,
+		HTMLURL:    resp.Commit.HTMLURL,
+		Commit: &RepoCommit{
+			Message:   resp.Commit.Message,
+			Author:    resp.Commit.Author,
+			Committer: resp.Commit.Committer,
+			Tree:      resp.Commit.Tree,
+		},
+		Parents: resp.Commit.Parents,
+	}
+	if err := validateCommitAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+// changeFileOperation returns the operation applying the action of file on the given branch.
+func (c *giteaClientImpl) changeFileOperation(ctx context.Context, owner, repo, branch string, file gitprovider.CommitFile) (*ChangeFileOperation, error) {
+	// Gitea doesn't set file modes through the API, all files are regular files
+	if file.IsExecutable() {
+		return nil, fmt.Errorf("cannot commit executable file %q: %w", *file.Path, gitprovider.ErrNoProviderSupport)
+	}
+
+	// Existing files are updated, which requires the SHA of the current blob
+	current, err := c.getFile(ctx, owner, repo, branch, *file.Path)
+	if err != nil {
+		return nil, err
+	}
+	op := &ChangeFileOperation{Path: *file.Path}
+	if current != nil {
+		op.SHA = current.SHA
+	}
+	switch action := file.GetAction(); {
+	case action == gitprovider.CommitFileActionMove:
+		// Moving is updating the file at its previous path, with a new path
+		previous, err := c.getFile(ctx, owner, repo, branch, *file.PreviousPath)
+		if err != nil {
+			return nil, err
+		}
+		if previous == nil {
+			return nil, fmt.Errorf("file %q: %w", *file.PreviousPath, gitprovider.ErrNotFound)
+		}
+		if current != nil && *file.Path != *file.PreviousPath {
+			return nil, fmt.Errorf("file %q: %w", *file.Path, gitprovider.ErrAlreadyExists)
+		}
+		op.Operation = fileOperationUpdate
+		op.FromPath = *file.PreviousPath
+		op.SHA = previous.SHA
+		// Keep the content if there's no new one
+		op.Content = previous.Content
+	case action == gitprovider.CommitFileActionCreate && current != nil:
+		return nil, fmt.Errorf("file %q: %w", *file.Path, gitprovider.ErrAlreadyExists)
+	case (action == gitprovider.CommitFileActionUpdate || action == gitprovider.CommitFileActionDelete) && current == nil:
+		return nil, fmt.Errorf("file %q: %w", *file.Path, gitprovider.ErrNotFound)
+	case action == gitprovider.CommitFileActionDelete:
+		op.Operation = fileOperationDelete
+	case current == nil:
+		op.Operation = fileOperationCreate
+	default:
+		op.Operation = fileOperationUpdate
+	}
+
+	// The content must be base64-encoded
+	if content, _ := file.GetContent(); content != nil {
+		op.Content = base64.StdEncoding.EncodeToString(content)
+	}
+	return op, nil
+}
+
+// getFile returns the file at path on the given branch, or nil if the file (or branch) doesn't exist.
+func (c *giteaClientImpl) getFile(ctx context.Context, owner, repo, branch, path string) (*ContentsResponse, error) {
+	query := url.Values{}
+	query.Set("ref", branch)
+	// The file path is part of the URL, keep its slashes
+	segments := append([]string{"repos", owner, repo, "contents"}, strings.Split(path, "/")...)
+
+	// The response is a list for directories, decode it separately
+	var raw json.RawMessage
+	// GET /repos/{owner}/{repo}/contents/{filepath}
+	if err := c.do(ctx, http.MethodGet, c.url(query, segments...), nil, &raw); err != nil {
+		err = handleHTTPError(err)
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	contents := &ContentsResponse{}
+	if err := json.Unmarshal(raw, contents); err != nil {
+		return nil, fmt.Errorf("path %q is not a file: %w", path, gitprovider.ErrInvalidArgument)
+	}
+	return contents, nil
+}
+
+func (c *giteaClientImpl) GetTree(ctx context.Context, owner, repo, sha string) (*GitTreeResponse, error) {
+	tree := &GitTreeResponse{}
+	query := url.Values{}
+	// Large trees are truncated into pages
+	for page := 1; ; page++ {
+		query.Set("page", strconv.Itoa(page))
+		apiObj := &GitTreeResponse{}
+		// GET /repos/{owner}/{repo}/git/trees/{sha}
+		if err := c.do(ctx, http.MethodGet, c.url(query, "repos", owner, repo, "git", "trees", sha), nil, apiObj); err != nil {
+			return nil, handleHTTPError(err)
+		}
+		tree.SHA = apiObj.SHA
+		tree.Entries = append(tree.Entries, apiObj.Entries...)
+		if !apiObj.Truncated || len(apiObj.Entries) == 0 {
+			break
+		}
+	}
+
+	for _, entry := range tree.Entries {
+		if err := validateGitEntryAPI(entry); err != nil {
+			return nil, err
+		}
+	}
+	return tree, nil
+}
+
+func (c *giteaClientImpl) GetBlob(ctx context.Context, owner, repo, sha string) ([]byte, error) {
+	apiObj := &GitBlobResponse{}
+	// GET /repos/{owner}/{repo}/git/blobs/{sha}
+	if err := c.do(ctx, http.MethodGet, c.url(nil, "repos", owner, repo, "git", "blobs", sha), nil, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if apiObj.Encoding != string(gitprovider.ContentEncodingBase64) {
+		return []byte(apiObj.Content), nil
+	}
+	content, err := base64.StdEncoding.DecodeString(apiObj.Content)
+	if err != nil {
+		return nil, fmt.Errorf("blob %q: %v: %w", sha, err, gitprovider.ErrInvalidServerData)
+	}
+	return content, nil
+}
+
+func (c *giteaClientImpl) GetBranch(ctx context.Context, owner, repo, branch string) (*Branch, error) {
+	apiObj := &Branch{}
+	// GET /repos/{owner}/{repo}/branches/{branch}
+	if err := c.do(ctx, http.MethodGet, c.url(nil, "repos", owner, repo, "branches", branch), nil, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validateBranchAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *giteaClientImpl) ListBranches(ctx context.Context, owner, repo string) ([]*Branch, error) {
+	apiObjs := []*Branch{}
+	// GET /repos/{owner}/{repo}/branches
+	err := c.allPages(ctx, c.url(nil, "repos", owner, repo, "branches"), func(data []byte) error {
+		var pageObjs []*Branch
+		err := json.Unmarshal(data, &pageObjs)
+		apiObjs = append(apiObjs, pageObjs...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, apiObj := range apiObjs {
+		if err := validateBranchAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *giteaClientImpl) CreateBranch(ctx context.Context, owner, repo, branch, ref string) (*Branch, error) {
+	req := &struct {
+		NewBranchName string `json:"new_branch_name"`
+		OldRefName    string `json:"old_ref_name"`
+	}{
+		NewBranchName: branch,
+		OldRefName:    ref,
+	}
+	apiObj := &Branch{}
+	// POST /repos/{owner}/{repo}/branches
+	if err := c.do(ctx, http.MethodPost, c.url(nil, "repos", owner, repo, "branches"), req, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validateBranchAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *giteaClientImpl) DeleteBranch(ctx context.Context, owner, repo, branch string) error {
+	// Don't allow deleting branches if the user didn't explicitly allow dangerous API calls.
+	if !c.destructiveActions {
+		return fmt.Errorf("cannot delete branch: %w", gitprovider.ErrDestructiveCallDisallowed)
+	}
+	// DELETE /repos/{owner}/{repo}/branches/{branch}
+	err := c.do(ctx, http.MethodDelete, c.url(nil, "repos", owner, repo, "branches", branch), nil, nil)
+	return handleHTTPError(err)
+}
+
+func (c *giteaClientImpl) GetBranchProtection(ctx context.Context, owner, repo, branch string) (*BranchProtection, error) {
+	apiObj := &BranchProtection{}
+	// GET /repos/{owner}/{repo}/branch_protections/{name}
+	if err := c.do(ctx, http.MethodGet, c.url(nil, "repos", owner, repo, "branch_protections", branch), nil, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validateBranchProtectionAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *giteaClientImpl) CreateBranchProtection(ctx context.Context, owner, repo string, req *BranchProtection) (*BranchProtection, error) {
+	apiObj := &BranchProtection{}
+	// POST /repos/{owner}/{repo}/branch_protections
+	if err := c.do(ctx, http.MethodPost, c.url(nil, "repos", owner, repo, "branch_protections"), req, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validateBranchProtectionAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *giteaClientImpl) EditBranchProtection(ctx context.Context, owner, repo string, req *BranchProtection) (*BranchProtection, error) {
+	apiObj := &BranchProtection{}
+	// PATCH /repos/{owner}/{repo}/branch_protections/{name}
+	if err := c.do(ctx, http.MethodPatch, c.url(nil, "repos", owner, repo, "branch_protections", req.BranchName), req, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validateBranchProtectionAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *giteaClientImpl) DeleteBranchProtection(ctx context.Context, owner, repo, branch string) error {
+	// DELETE /repos/{owner}/{repo}/branch_protections/{name}
+	err := c.do(ctx, http.MethodDelete, c.url(nil, "repos", owner, repo, "branch_protections", branch), nil, nil)
+	return handleHTTPError(err)
+}
+
+func (c *giteaClientImpl) CreatePullRequest(ctx context.Context, owner, repo string, req *CreatePullRequestOption) (*PullRequest, error) {
+	apiObj := &PullRequest{}
+	// POST /repos/{owner}/{repo}/pulls
+	if err := c.do(ctx, http.MethodPost, c.url(nil, "repos", owner, repo, "pulls"), req, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validatePullRequestAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *giteaClientImpl) GetPullRequest(ctx context.Context, owner, repo string, index int) (*PullRequest, error) {
+	apiObj := &PullRequest{}
+	// GET /repos/{owner}/{repo}/pulls/{index}
+	if err := c.do(ctx, http.MethodGet, c.url(nil, "repos", owner, repo, "pulls", strconv.Itoa(index)), nil, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validatePullRequestAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *giteaClientImpl) ListPullRequests(ctx context.Context, owner, repo, state string) ([]*PullRequest, error) {
+	query := url.Values{}
+	query.Set("state", state)
+
+	apiObjs := []*PullRequest{}
+	// GET /repos/{owner}/{repo}/pulls
+	err := c.allPages(ctx, c.url(query, "repos", owner, repo, "pulls"), func(data []byte) error {
+		var pageObjs []*PullRequest
+		err := json.Unmarshal(data, &pageObjs)
+		apiObjs = append(apiObjs, pageObjs...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, apiObj := range apiObjs {
+		if err := validatePullRequestAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *giteaClientImpl) EditPullRequest(ctx context.Context, owner, repo string, index int, req *EditPullRequestOption) (*PullRequest, error) {
+	apiObj := &PullRequest{}
+	// PATCH /repos/{owner}/{repo}/pulls/{index}
+	if err := c.do(ctx, http.MethodPatch, c.url(nil, "repos", owner, repo, "pulls", strconv.Itoa(index)), req, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validatePullRequestAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *giteaClientImpl) MergePullRequest(ctx context.Context, owner, repo string, index int, req *MergePullRequestOption) error {
+	// POST /repos/{owner}/{repo}/pulls/{index}/merge
+	err := c.do(ctx, http.MethodPost, c.url(nil, "repos", owner, repo, "pulls", strconv.Itoa(index), "merge"), req, nil)
+	return handleHTTPError(err)
+}
+
+func (c *giteaClientImpl) ListIssueComments(ctx context.Context, owner, repo string, index int) ([]*Comment, error) {
+	apiObjs := []*Comment{}
+	// GET /repos/{owner}/{repo}/issues/{index}/comments
+	err := c.allPages(ctx, c.url(nil, "repos", owner, repo, "issues", strconv.Itoa(index), "comments"), func(data []byte) error {
+		var pageObjs []*Comment
+		err := json.Unmarshal(data, &pageObjs)
+		apiObjs = append(apiObjs, pageObjs...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, apiObj := range apiObjs {
+		if err := validateCommentAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *giteaClientImpl) CreateIssueComment(ctx context.Context, owner, repo string, index int, body string) (*Comment, error) {
+	apiObj := &Comment{}
+	// POST /repos/{owner}/{repo}/issues/{index}/comments
+	if err := c.do(ctx, http.MethodPost, c.url(nil, "repos", owner, repo, "issues", strconv.Itoa(index), "comments"), &Comment{Body: body}, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validateCommentAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *giteaClientImpl) EditIssueComment(ctx context.Context, owner, repo string, id int64, body string) (*Comment, error) {
+	apiObj := &Comment{}
+	// PATCH /repos/{owner}/{repo}/issues/comments/{id}
+	if err := c.do(ctx, http.MethodPatch, c.url(nil, "repos", owner, repo, "issues", "comments", strconv.FormatInt(id, 10)), &Comment{Body: body}, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validateCommentAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *giteaClientImpl) DeleteIssueComment(ctx context.Context, owner, repo string, id int64) error {
+	// DELETE /repos/{owner}/{repo}/issues/comments/{id}
+	err := c.do(ctx, http.MethodDelete, c.url(nil, "repos", owner, repo, "issues", "comments", strconv.FormatInt(id, 10)), nil, nil)
+	return handleHTTPError(err)
+}
+
+func (c *giteaClientImpl) ListPullReviews(ctx context.Context, owner, repo string, index int) ([]*PullReview, error) {
+	apiObjs := []*PullReview{}
+	// GET /repos/{owner}/{repo}/pulls/{index}/reviews
+	err := c.allPages(ctx, c.url(nil, "repos", owner, repo, "pulls", strconv.Itoa(index), "reviews"), func(data []byte) error {
+		var pageObjs []*PullReview
+		err := json.Unmarshal(data, &pageObjs)
+		apiObjs = append(apiObjs, pageObjs...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, apiObj := range apiObjs {
+		if err := validatePullReviewAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *giteaClientImpl) CreatePullReview(ctx context.Context, owner, repo string, index int, req *CreatePullReviewOptions) (*PullReview, error) {
+	apiObj := &PullReview{}
+	// POST /repos/{owner}/{repo}/pulls/{index}/reviews
+	if err := c.do(ctx, http.MethodPost, c.url(nil, "repos", owner, repo, "pulls", strconv.Itoa(index), "reviews"), req, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validatePullReviewAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+// url returns the absolute API URL for the path built from the escaped path segments,
+// with the optional query appended.
+func (c *giteaClientImpl) url(query url.Values, segments ...string) string {
+	escaped := make([]string, 0, len(segments)+1)
+	escaped = append(escaped, apiPath)
+	for _, s := range segments {
+		escaped = append(escaped, url.PathEscape(s))
+	}
+	u := c.domainURL + "/" + strings.Join(escaped, "/")
+	if len(query) != 0 {
+		u = u + "?" + query.Encode()
+	}
+	return u
+}
+
+// do sends a request with the given JSON-encoded body (if non-nil) and decodes the
+// response into out (if non-nil). Non-2xx responses are returned as *ErrorResponse.
+func (c *giteaClientImpl) do(ctx context.Context, method, urlStr string, body, out interface{}) error {
+	_, err := c.doResponse(ctx, method, urlStr, body, out)
+	return err
+}
+
+// doResponse is like do, but also returns the HTTP response.
+func (c *giteaClientImpl) doResponse(ctx context.Context, method, urlStr string, body, out interface{}) (*http.Response, error) {
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reqBody = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, urlStr, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		errResp := &ErrorResponse{Response: resp}
+		// The error body is best-effort, the status code is what matters
+		if data, readErr := ioutil.ReadAll(resp.Body); readErr == nil && len(data) != 0 {
+			_ = json.Unmarshal(data, errResp)
+		}
+		return resp, errResp
+	}
+
+	if out != nil && resp.StatusCode != http.StatusNoContent {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil && err != io.EOF {
+			return resp, err
+		}
+	}
+	return resp, nil
+}
+
+// allPages requests urlStr, and follows the "next" links of the Link header until the last
+// page has been fetched. fn is called with the raw JSON list of every page.
+// There is no need to wrap the resulting error in handleHTTPError(err), as that's already done.
+func (c *giteaClientImpl) allPages(ctx context.Context, urlStr string, fn func(data []byte) error) error {
+	u, err := url.Parse(urlStr)
+	if err != nil {
+		return err
+	}
+	query := u.Query()
+	query.Set("limit", strconv.Itoa(defaultPageSize))
+	u.RawQuery = query.Encode()
+	next := u.String()
+
+	for len(next) != 0 {
+		var data json.RawMessage
+		resp, err := c.doResponse(ctx, http.MethodGet, next, nil, &data)
+		if err != nil {
+			return handleHTTPError(err)
+		}
+		if len(data) != 0 {
+			if err := fn(data); err != nil {
+				return err
+			}
+		}
+		next = ""
+		if m := nextLinkRegexp.FindStringSubmatch(resp.Header.Get("Link")); m != nil {
+			next = m[1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+func newBranch(apiObj *Branch) *branch {
+	return &branch{
+		b: *apiObj,
+	}
+}
+
+var _ gitprovider.Branch = &branch{}
+
+type branch struct {
+	b Branch
+}
+
+func (b *branch) Get() gitprovider.BranchInfo {
+	return branchFromAPI(&b.b)
+}
+
+func (b *branch) APIObject() interface{} {
+	return &b.b
+}
+
+func branchFromAPI(apiObj *Branch) gitprovider.BranchInfo {
+	return gitprovider.BranchInfo{
+		Name:      apiObj.Name,
+		Sha:       apiObj.Commit.ID,
+		Protected: apiObj.Protected,
+	}
+}
+
+// validateBranchAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateBranchAPI(apiObj *Branch) error {
+	return validateAPIObject("Gitea.Branch", func(validator validation.Validator) {
+		if len(apiObj.Name) == 0 {
+			validator.Required("Name")
+		}
+		if apiObj.Commit == nil || len(apiObj.Commit.ID) == 0 {
+			validator.Required("Commit.ID")
+		}
+	})
+}
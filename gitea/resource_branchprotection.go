@@ -0,0 +1,184 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+func newBranchProtection(c *BranchProtectionClient, apiObj *BranchProtection) *branchProtection {
+	return &branchProtection{
+		p: *apiObj,
+		c: c,
+	}
+}
+
+var _ gitprovider.BranchProtection = &branchProtection{}
+
+type branchProtection struct {
+	p BranchProtection
+	c *BranchProtectionClient
+}
+
+func (bp *branchProtection) Get() gitprovider.BranchProtectionInfo {
+	return branchProtectionFromAPI(&bp.p)
+}
+
+func (bp *branchProtection) Set(info gitprovider.BranchProtectionInfo) error {
+	if err := info.ValidateInfo(); err != nil {
+		return err
+	}
+	return branchProtectionInfoToAPIObj(&info, &bp.p)
+}
+
+func (bp *branchProtection) APIObject() interface{} {
+	return &bp.p
+}
+
+func (bp *branchProtection) Repository() gitprovider.RepositoryRef {
+	return bp.c.ref
+}
+
+// Update will apply the desired state in this object to the server.
+// In order to apply changes to this object, use the .Set({Resource}Info) error
+// function, or cast .APIObject() to a pointer to the provider-specific type
+// and set custom fields there.
+//
+// ErrNotFound is returned if the resource does not exist.
+//
+// The internal API object will be overridden with the received server data.
+func (bp *branchProtection) Update(ctx context.Context) error {
+	// PATCH /repos/{owner}/{repo}/branch_protections/{name}
+	apiObj, err := bp.c.c.EditBranchProtection(ctx, bp.c.ref.GetIdentity(), bp.c.ref.GetRepository(), &bp.p)
+	if err != nil {
+		return err
+	}
+	bp.p = *apiObj
+	return nil
+}
+
+// Delete removes the protection rules of the branch.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (bp *branchProtection) Delete(ctx context.Context) error {
+	// DELETE /repos/{owner}/{repo}/branch_protections/{name}
+	return bp.c.c.DeleteBranchProtection(ctx, bp.c.ref.GetIdentity(), bp.c.ref.GetRepository(), bp.p.BranchName)
+}
+
+// Reconcile makes sure the desired state in this object (called "req" here) becomes
+// the actual state in the backing Git provider.
+//
+// If req doesn't exist under the hood, it is created (actionTaken == true).
+// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+// If req is already the actual state, this is a no-op (actionTaken == false).
+//
+// The internal API object will be overridden with the received server data if actionTaken == true.
+func (bp *branchProtection) Reconcile(ctx context.Context) (bool, error) {
+	actual, err := bp.c.get(ctx, bp.p.BranchName)
+	if err != nil {
+		// Create if not found
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			return true, bp.createIntoSelf(ctx)
+		}
+
+		// Unexpected path, Get should succeed or return NotFound
+		return false, err
+	}
+
+	// If the desired matches the actual state, do nothing
+	if bp.Get().Equals(actual.Get()) {
+		return false, nil
+	}
+	// If desired and actual state mis-match, update
+	return true, bp.Update(ctx)
+}
+
+func (bp *branchProtection) createIntoSelf(ctx context.Context) error {
+	// POST /repos/{owner}/{repo}/branch_protections
+	apiObj, err := bp.c.c.CreateBranchProtection(ctx, bp.c.ref.GetIdentity(), bp.c.ref.GetRepository(), &bp.p)
+	if err != nil {
+		return err
+	}
+	bp.p = *apiObj
+	return nil
+}
+
+func branchProtectionFromAPI(apiObj *BranchProtection) gitprovider.BranchProtectionInfo {
+	info := gitprovider.BranchProtectionInfo{
+		Branch:              apiObj.BranchName,
+		RequiredApprovals:   gitprovider.IntVar(int(apiObj.RequiredApprovals)),
+		DismissStaleReviews: gitprovider.BoolVar(apiObj.DismissStaleApprovals),
+		// Without push access no one can push, with the whitelist only the listed users and teams
+		RestrictPushes:          gitprovider.BoolVar(!apiObj.EnablePush || apiObj.EnablePushWhitelist),
+		RequireCodeOwnerReviews: gitprovider.BoolVar(false),
+		AllowForcePushes:        gitprovider.BoolVar(false),
+		AllowDeletions:          gitprovider.BoolVar(false),
+	}
+	if apiObj.EnableStatusCheck && len(apiObj.StatusCheckContexts) != 0 {
+		info.RequiredStatusChecks = apiObj.StatusCheckContexts
+	}
+	return info
+}
+
+func branchProtectionInfoToAPIObj(info *gitprovider.BranchProtectionInfo, apiObj *BranchProtection) error {
+	switch {
+	case info.RequireCodeOwnerReviews != nil && *info.RequireCodeOwnerReviews:
+		return fmt.Errorf("cannot require code owner reviews: %w", gitprovider.ErrNoProviderSupport)
+	case info.AllowForcePushes != nil && *info.AllowForcePushes:
+		return fmt.Errorf("cannot allow force-pushes to a protected branch: %w", gitprovider.ErrNoProviderSupport)
+	case info.AllowDeletions != nil && *info.AllowDeletions:
+		return fmt.Errorf("cannot allow deleting a protected branch: %w", gitprovider.ErrNoProviderSupport)
+	}
+
+	apiObj.BranchName = info.Branch
+	if info.RequiredApprovals != nil {
+		apiObj.RequiredApprovals = int64(*info.RequiredApprovals)
+	}
+	if info.DismissStaleReviews != nil {
+		apiObj.DismissStaleApprovals = *info.DismissStaleReviews
+	}
+	apiObj.EnableStatusCheck = len(info.RequiredStatusChecks) != 0
+	apiObj.StatusCheckContexts = info.RequiredStatusChecks
+	if info.RestrictPushes != nil {
+		restricted := !apiObj.EnablePush || apiObj.EnablePushWhitelist
+		switch {
+		case !*info.RestrictPushes:
+			apiObj.EnablePush = true
+			apiObj.EnablePushWhitelist = false
+		case !restricted:
+			// Only the whitelisted users and teams, by default none, may push
+			apiObj.EnablePush = true
+			apiObj.EnablePushWhitelist = true
+		}
+	}
+	return nil
+}
+
+// validateBranchProtectionAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateBranchProtectionAPI(apiObj *BranchProtection) error {
+	return validateAPIObject("Gitea.BranchProtection", func(validator validation.Validator) {
+		if len(apiObj.BranchName) == 0 {
+			validator.Required("BranchName")
+		}
+	})
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"time"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+func newCommit(c *CommitClient, commit *Commit) *commitType {
+	return &commitType{
+		k: *commit,
+		c: c,
+	}
+}
+
+var _ gitprovider.Commit = &commitType{}
+
+type commitType struct {
+	k Commit
+	c *CommitClient
+}
+
+func (c *commitType) Get() gitprovider.CommitInfo {
+	return commitFromAPI(&c.k)
+}
+
+func (c *commitType) APIObject() interface{} {
+	return &c.k
+}
+
+func commitFromAPI(apiObj *Commit) gitprovider.CommitInfo {
+	// Tree is validated to be set in validateCommitAPI
+	parentShas := make([]string, 0, len(apiObj.Parents))
+	for _, parent := range apiObj.Parents {
+		parentShas = append(parentShas, parent.SHA)
+	}
+	return gitprovider.CommitInfo{
+		Sha:        apiObj.SHA,
+		TreeSha:    apiObj.Commit.Tree.SHA,
+		Message:    apiObj.Commit.Message,
+		Author:     commitUserFromAPI(apiObj.Commit.Author),
+		Committer:  commitUserFromAPI(apiObj.Commit.Committer),
+		ParentShas: parentShas,
+		WebURL:     apiObj.HTMLURL,
+	}
+}
+
+func commitUserFromAPI(apiObj *CommitUser) *gitprovider.CommitAuthor {
+	if apiObj == nil {
+		return nil
+	}
+	// Dates that can't be parsed are left as zero
+	date, _ := time.Parse(time.RFC3339, apiObj.Date)
+	return &gitprovider.CommitAuthor{
+		Name:  apiObj.Name,
+		Email: apiObj.Email,
+		Date:  date,
+	}
+}
+
+func commitUserToAPI(author *gitprovider.CommitAuthor) *CommitUser {
+	if author == nil {
+		return nil
+	}
+	// Dates are set separately, through commitDatesToAPI
+	return &CommitUser{
+		Name:  author.Name,
+		Email: author.Email,
+	}
+}
+
+// commitDatesToAPI returns the dates of the given author and committer, or nil if neither has one.
+func commitDatesToAPI(author, committer *gitprovider.CommitAuthor) *CommitDateOptions {
+	dates := &CommitDateOptions{}
+	if author != nil && !author.Date.IsZero() {
+		dates.Author = &author.Date
+	}
+	if committer != nil && !committer.Date.IsZero() {
+		dates.Committer = &committer.Date
+	}
+	if dates.Author == nil && dates.Committer == nil {
+		return nil
+	}
+	return dates
+}
+
+// validateCommitAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateCommitAPI(apiObj *Commit) error {
+	return validateAPIObject("Gitea.Commit", func(validator validation.Validator) {
+		if len(apiObj.SHA) == 0 {
+			validator.Required("SHA")
+		}
+		if apiObj.Commit == nil || apiObj.Commit.Tree == nil {
+			validator.Required("Commit.Tree")
+		}
+	})
+}
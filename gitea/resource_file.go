@@ -0,0 +1,73 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+// newFile returns the file of a tree entry, whose Path is relative to the root of the
+// repository, with the given content if it was fetched.
+func newFile(apiObj *GitEntry, content []byte) *file {
+	return &file{
+		e:       *apiObj,
+		content: content,
+	}
+}
+
+var _ gitprovider.File = &file{}
+
+type file struct {
+	e       GitEntry
+	content []byte
+}
+
+func (f *file) Get() gitprovider.FileInfo {
+	info := fileFromAPI(&f.e)
+	info.Content = append([]byte(nil), f.content...)
+	return info
+}
+
+func (f *file) APIObject() interface{} {
+	return &f.e
+}
+
+func fileFromAPI(apiObj *GitEntry) gitprovider.FileInfo {
+	return gitprovider.FileInfo{
+		Path: apiObj.Path,
+		Mode: gitprovider.FileMode(apiObj.Mode),
+		Sha:  apiObj.SHA,
+		Size: apiObj.Size,
+	}
+}
+
+// validateGitEntryAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateGitEntryAPI(apiObj *GitEntry) error {
+	return validateAPIObject("Gitea.GitEntry", func(validator validation.Validator) {
+		if apiObj.Path == "" {
+			validator.Required("Path")
+		}
+		if apiObj.Mode == "" {
+			validator.Required("Mode")
+		}
+		if apiObj.SHA == "" {
+			validator.Required("SHA")
+		}
+	})
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+func newOrganization(ctx *clientContext, apiObj *Organization, ref gitprovider.OrganizationRef) *organization {
+	return &organization{
+		clientContext: ctx,
+		o:             *apiObj,
+		ref:           ref,
+		teams: &TeamsClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		webhooks: &WebhookClient{
+			clientContext: ctx,
+		},
+	}
+}
+
+var _ gitprovider.Organization = &organization{}
+
+type organization struct {
+	*clientContext
+
+	o   Organization
+	ref gitprovider.OrganizationRef
+
+	teams    *TeamsClient
+	webhooks *WebhookClient
+}
+
+func (o *organization) Get() gitprovider.OrganizationInfo {
+	return organizationFromAPI(&o.o)
+}
+
+func (o *organization) APIObject() interface{} {
+	return &o.o
+}
+
+func (o *organization) Organization() gitprovider.OrganizationRef {
+	return o.ref
+}
+
+func (o *organization) Teams() gitprovider.TeamsClient {
+	return o.teams
+}
+
+func (o *organization) Webhooks() gitprovider.WebhookClient {
+	return o.webhooks
+}
+
+func organizationFromAPI(apiObj *Organization) gitprovider.OrganizationInfo {
+	// The full name is optional, fall back to the name of the organization
+	name := apiObj.FullName
+	if len(name) == 0 {
+		name = apiObj.UserName
+	}
+	return gitprovider.OrganizationInfo{
+		Name:        gitprovider.StringVar(name),
+		Description: gitprovider.StringVar(apiObj.Description),
+	}
+}
+
+// validateOrganizationAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateOrganizationAPI(apiObj *Organization) error {
+	return validateAPIObject("Gitea.Organization", func(validator validation.Validator) {
+		if len(apiObj.UserName) == 0 {
+			validator.Required("UserName")
+		}
+	})
+}
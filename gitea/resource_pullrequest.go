@@ -0,0 +1,124 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+func newPullRequest(ctx *clientContext, apiObj *PullRequest, ref gitprovider.RepositoryRef) *pullrequest {
+	return &pullrequest{
+		clientContext: ctx,
+		pr:            *apiObj,
+		comments: &PullRequestCommentClient{
+			clientContext: ctx,
+			ref:           ref,
+			index:         int(apiObj.Number),
+		},
+		reviews: &PullRequestReviewClient{
+			clientContext: ctx,
+			ref:           ref,
+			index:         int(apiObj.Number),
+		},
+	}
+}
+
+var _ gitprovider.PullRequest = &pullrequest{}
+
+type pullrequest struct {
+	*clientContext
+
+	pr PullRequest
+
+	comments *PullRequestCommentClient
+	reviews  *PullRequestReviewClient
+}
+
+func (pr *pullrequest) Get() gitprovider.PullRequestInfo {
+	return pullrequestFromAPI(&pr.pr)
+}
+
+func (pr *pullrequest) APIObject() interface{} {
+	return &pr.pr
+}
+
+func (pr *pullrequest) Comments() gitprovider.PullRequestCommentClient {
+	return pr.comments
+}
+
+func (pr *pullrequest) Reviews() gitprovider.PullRequestReviewClient {
+	return pr.reviews
+}
+
+func pullrequestFromAPI(apiObj *PullRequest) gitprovider.PullRequestInfo {
+	info := gitprovider.PullRequestInfo{
+		Number:      int(apiObj.Number),
+		Title:       apiObj.Title,
+		Description: apiObj.Body,
+		State:       pullRequestStateFromAPI(apiObj),
+		MergedAt:    apiObj.Merged,
+		ClosedAt:    apiObj.Closed,
+		WebURL:      apiObj.HTMLURL,
+	}
+	if apiObj.Head != nil {
+		info.HeadBranch = apiObj.Head.Ref
+	}
+	if apiObj.Base != nil {
+		info.BaseBranch = apiObj.Base.Ref
+	}
+	if apiObj.Poster != nil {
+		info.Author = apiObj.Poster.UserName
+	}
+	if apiObj.Created != nil {
+		info.CreatedAt = *apiObj.Created
+	}
+	if apiObj.Updated != nil {
+		info.UpdatedAt = *apiObj.Updated
+	}
+	// Mergeability is only meaningful for open pull requests
+	if info.State == gitprovider.PullRequestStateOpen {
+		info.Mergeable = gitprovider.BoolVar(apiObj.Mergeable)
+	}
+	return info
+}
+
+// pullRequestStateFromAPI returns the state of the pull request. Gitea reports merged pull
+// requests as closed.
+func pullRequestStateFromAPI(apiObj *PullRequest) gitprovider.PullRequestState {
+	switch {
+	case apiObj.State == "open":
+		return gitprovider.PullRequestStateOpen
+	case apiObj.HasMerged || apiObj.Merged != nil:
+		return gitprovider.PullRequestStateMerged
+	default:
+		return gitprovider.PullRequestStateClosed
+	}
+}
+
+// validatePullRequestAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validatePullRequestAPI(apiObj *PullRequest) error {
+	return validateAPIObject("Gitea.PullRequest", func(validator validation.Validator) {
+		if apiObj.Number == 0 {
+			validator.Required("Number")
+		}
+		if apiObj.State == "" {
+			validator.Required("State")
+		}
+	})
+}
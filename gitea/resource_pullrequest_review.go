@@ -0,0 +1,105 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"fmt"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+const (
+	reviewStateApproved       = "APPROVED"
+	reviewStateComment        = "COMMENT"
+	reviewStateRequestChanges = "REQUEST_CHANGES"
+	reviewStatePending        = "PENDING"
+	reviewStateRequestReview  = "REQUEST_REVIEW"
+)
+
+func newPullRequestReview(apiObj *PullReview) *pullRequestReview {
+	return &pullRequestReview{
+		r: *apiObj,
+	}
+}
+
+var _ gitprovider.PullRequestReview = &pullRequestReview{}
+
+type pullRequestReview struct {
+	r PullReview
+}
+
+func (r *pullRequestReview) Get() gitprovider.PullRequestReviewInfo {
+	return pullRequestReviewFromAPI(&r.r)
+}
+
+func (r *pullRequestReview) APIObject() interface{} {
+	return &r.r
+}
+
+func pullRequestReviewFromAPI(apiObj *PullReview) gitprovider.PullRequestReviewInfo {
+	info := gitprovider.PullRequestReviewInfo{
+		ID:          apiObj.ID,
+		State:       reviewStateFromAPI(apiObj.State),
+		Body:        apiObj.Body,
+		SubmittedAt: apiObj.Submitted,
+	}
+	if apiObj.Reviewer != nil {
+		info.Author = apiObj.Reviewer.UserName
+	}
+	return info
+}
+
+// reviewStateFromAPI maps the Gitea review states to a PullRequestReviewState. All states but
+// APPROVED and REQUEST_CHANGES are mapped to PullRequestReviewStateCommented.
+func reviewStateFromAPI(state string) gitprovider.PullRequestReviewState {
+	switch state {
+	case reviewStateApproved:
+		return gitprovider.PullRequestReviewStateApproved
+	case reviewStateRequestChanges:
+		return gitprovider.PullRequestReviewStateChangesRequested
+	default:
+		return gitprovider.PullRequestReviewStateCommented
+	}
+}
+
+// reviewStateToAPI maps a PullRequestReviewState to the event submitting a Gitea review.
+func reviewStateToAPI(state gitprovider.PullRequestReviewState) (string, error) {
+	switch state {
+	case gitprovider.PullRequestReviewStateApproved:
+		return reviewStateApproved, nil
+	case gitprovider.PullRequestReviewStateChangesRequested:
+		return reviewStateRequestChanges, nil
+	case gitprovider.PullRequestReviewStateCommented:
+		return reviewStateComment, nil
+	default:
+		return "", fmt.Errorf("review state %q: %w", state, gitprovider.ErrInvalidArgument)
+	}
+}
+
+// validatePullReviewAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validatePullReviewAPI(apiObj *PullReview) error {
+	return validateAPIObject("Gitea.PullReview", func(validator validation.Validator) {
+		if apiObj.ID == 0 {
+			validator.Required("ID")
+		}
+		if apiObj.State == "" {
+			validator.Required("State")
+		}
+	})
+}
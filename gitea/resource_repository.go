@@ -0,0 +1,375 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+// readmeTemplateDefault is the name of the README template used for auto-initialized repositories.
+const readmeTemplateDefault = "Default"
+
+// licenseTemplates maps the license templates to the SPDX identifiers of the Gitea license templates.
+//
+//nolint:gochecknoglobals
+var licenseTemplates = map[gitprovider.LicenseTemplate]string{
+	gitprovider.LicenseTemplateApache2: "Apache-2.0",
+	gitprovider.LicenseTemplateMIT:     "MIT",
+	gitprovider.LicenseTemplateGPL3:    "GPL-3.0",
+}
+
+func newUserRepository(ctx *clientContext, apiObj *Repository, ref gitprovider.RepositoryRef) *userRepository {
+	return &userRepository{
+		clientContext: ctx,
+		r:             *apiObj,
+		ref:           ref,
+		deployKeys: &DeployKeyClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		commits: &CommitClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		branches: &BranchClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		files: &FileClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		branchProtections: &BranchProtectionClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		pullRequests: &PullRequestClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		tags: &TagClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		releases: &ReleaseClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		webhooks: &WebhookClient{
+			clientContext: ctx,
+		},
+		commitStatuses: &CommitStatusClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		issues: &IssueClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+	}
+}
+
+var _ gitprovider.UserRepository = &userRepository{}
+
+type userRepository struct {
+	*clientContext
+
+	r   Repository
+	ref gitprovider.RepositoryRef
+
+	deployKeys        *DeployKeyClient
+	commits           *CommitClient
+	branches          *BranchClient
+	files             *FileClient
+	branchProtections *BranchProtectionClient
+	pullRequests      *PullRequestClient
+	tags              *TagClient
+	releases          *ReleaseClient
+	webhooks          *WebhookClient
+	commitStatuses    *CommitStatusClient
+	issues            *IssueClient
+}
+
+func (r *userRepository) Get() gitprovider.RepositoryInfo {
+	return repositoryFromAPI(&r.r)
+}
+
+func (r *userRepository) Set(info gitprovider.RepositoryInfo) error {
+	if err := info.ValidateInfo(); err != nil {
+		return err
+	}
+	return repositoryInfoToAPIObj(&info, &r.r)
+}
+
+func (r *userRepository) APIObject() interface{} {
+	return &r.r
+}
+
+func (r *userRepository) Repository() gitprovider.RepositoryRef {
+	return r.ref
+}
+
+func (r *userRepository) DeployKeys() gitprovider.DeployKeyClient {
+	return r.deployKeys
+}
+
+func (r *userRepository) Commits() gitprovider.CommitClient {
+	return r.commits
+}
+
+func (r *userRepository) Branches() gitprovider.BranchClient {
+	return r.branches
+}
+
+func (r *userRepository) Files() gitprovider.FileClient {
+	return r.files
+}
+
+func (r *userRepository) BranchProtections() gitprovider.BranchProtectionClient {
+	return r.branchProtections
+}
+
+func (r *userRepository) PullRequests() gitprovider.PullRequestClient {
+	return r.pullRequests
+}
+
+func (r *userRepository) Tags() gitprovider.TagClient {
+	return r.tags
+}
+
+func (r *userRepository) Releases() gitprovider.ReleaseClient {
+	return r.releases
+}
+
+func (r *userRepository) Webhooks() gitprovider.WebhookClient {
+	return r.webhooks
+}
+
+func (r *userRepository) CommitStatuses() gitprovider.CommitStatusClient {
+	return r.commitStatuses
+}
+
+func (r *userRepository) Issues() gitprovider.IssueClient {
+	return r.issues
+}
+
+// Update will apply the desired state in this object to the server.
+// Only set fields will be respected (i.e. PATCH behaviour).
+// In order to apply changes to this object, use the .Set({Resource}Info) error
+// function, or cast .APIObject() to a pointer to the provider-specific type
+// and set custom fields there.
+//
+// ErrNotFound is returned if the resource does not exist.
+//
+// The internal API object will be overridden with the received server data.
+func (r *userRepository) Update(ctx context.Context) error {
+	// PATCH /repos/{owner}/{repo}
+	apiObj, err := r.c.UpdateRepo(ctx, r.ref.GetIdentity(), r.ref.GetRepository(), newGiteaRepositorySpec(&r.r).Repository)
+	if err != nil {
+		return err
+	}
+	r.r = *apiObj
+	return nil
+}
+
+// Reconcile makes sure the desired state in this object (called "req" here) becomes
+// the actual state in the backing Git provider.
+//
+// If req doesn't exist under the hood, it is created (actionTaken == true).
+// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+// If req is already the actual state, this is a no-op (actionTaken == false).
+//
+// The internal API object will be overridden with the received server data if actionTaken == true.
+func (r *userRepository) Reconcile(ctx context.Context) (bool, error) {
+	apiObj, err := r.c.GetRepo(ctx, r.ref.GetIdentity(), r.ref.GetRepository())
+	if err != nil {
+		// Create if not found
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			orgName := ""
+			if orgRef, ok := r.ref.(gitprovider.OrgRepositoryRef); ok {
+				orgName = orgRef.Organization
+			}
+			repo, err := r.c.CreateRepo(ctx, orgName, newCreateRepoOption(&r.r))
+			if err != nil {
+				return true, err
+			}
+			r.r = *repo
+			return true, nil
+		}
+
+		return false, err
+	}
+
+	// Use wrappers here to extract the "spec" part of the object for comparison
+	desiredSpec := newGiteaRepositorySpec(&r.r)
+	actualSpec := newGiteaRepositorySpec(apiObj)
+
+	// If desired state already is the actual state, do nothing
+	if desiredSpec.Equals(actualSpec) {
+		return false, nil
+	}
+	// Otherwise, make the desired state the actual state
+	return true, r.Update(ctx)
+}
+
+// Delete deletes the current resource irreversibly.
+//
+// ErrNotFound is returned if the resource doesn't exist anymore.
+func (r *userRepository) Delete(ctx context.Context) error {
+	// DELETE /repos/{owner}/{repo}
+	return r.c.DeleteRepo(ctx, r.ref.GetIdentity(), r.ref.GetRepository())
+}
+
+func newOrgRepository(ctx *clientContext, apiObj *Repository, ref gitprovider.RepositoryRef) *orgRepository {
+	return &orgRepository{
+		userRepository: *newUserRepository(ctx, apiObj, ref),
+		teamAccess: &TeamAccessClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+	}
+}
+
+var _ gitprovider.OrgRepository = &orgRepository{}
+
+type orgRepository struct {
+	userRepository
+
+	teamAccess *TeamAccessClient
+}
+
+func (r *orgRepository) TeamAccess() gitprovider.TeamAccessClient {
+	return r.teamAccess
+}
+
+// validateRepositoryAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateRepositoryAPI(apiObj *Repository) error {
+	return validateAPIObject("Gitea.Repository", func(validator validation.Validator) {
+		// Make sure name is set
+		if len(apiObj.Name) == 0 {
+			validator.Required("Name")
+		}
+		// Make sure visibility can be derived
+		if apiObj.Private == nil {
+			validator.Required("Private")
+		}
+	})
+}
+
+func repositoryFromAPI(apiObj *Repository) gitprovider.RepositoryInfo {
+	repo := gitprovider.RepositoryInfo{
+		DefaultBranch: apiObj.DefaultBranch,
+	}
+	// The server returns an empty description when unset
+	if apiObj.Description != nil && len(*apiObj.Description) != 0 {
+		repo.Description = apiObj.Description
+	}
+	if apiObj.Private != nil {
+		visibility := gitprovider.RepositoryVisibilityPublic
+		if *apiObj.Private {
+			visibility = gitprovider.RepositoryVisibilityPrivate
+		}
+		repo.Visibility = gitprovider.RepositoryVisibilityVar(visibility)
+	}
+	return repo
+}
+
+func repositoryToAPI(repo *gitprovider.RepositoryInfo, ref gitprovider.RepositoryRef) (*Repository, error) {
+	apiObj := &Repository{
+		Name: ref.GetRepository(),
+	}
+	if err := repositoryInfoToAPIObj(repo, apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func repositoryInfoToAPIObj(repo *gitprovider.RepositoryInfo, apiObj *Repository) error {
+	if repo.Description != nil {
+		apiObj.Description = repo.Description
+	}
+	if repo.DefaultBranch != nil {
+		apiObj.DefaultBranch = repo.DefaultBranch
+	}
+	if repo.Visibility != nil {
+		switch *repo.Visibility {
+		case gitprovider.RepositoryVisibilityPrivate:
+			apiObj.Private = gitprovider.BoolVar(true)
+		case gitprovider.RepositoryVisibilityPublic:
+			apiObj.Private = gitprovider.BoolVar(false)
+		default:
+			return fmt.Errorf("gitea doesn't support %q repositories: %w", *repo.Visibility, gitprovider.ErrNoProviderSupport)
+		}
+	}
+	return nil
+}
+
+// newCreateRepoOption returns the request body for creating the given repository.
+func newCreateRepoOption(apiObj *Repository) *CreateRepoOption {
+	opt := &CreateRepoOption{
+		Name: apiObj.Name,
+	}
+	if apiObj.Description != nil {
+		opt.Description = *apiObj.Description
+	}
+	if apiObj.Private != nil {
+		opt.Private = *apiObj.Private
+	}
+	if apiObj.DefaultBranch != nil {
+		opt.DefaultBranch = *apiObj.DefaultBranch
+	}
+	return opt
+}
+
+func applyRepoCreateOptions(opt *CreateRepoOption, opts gitprovider.RepositoryCreateOptions) {
+	if opts.AutoInit != nil && *opts.AutoInit {
+		opt.AutoInit = true
+		opt.Readme = readmeTemplateDefault
+	}
+	if opts.LicenseTemplate != nil {
+		opt.License = licenseTemplates[*opts.LicenseTemplate]
+	}
+}
+
+// This function copies over the fields that are part of create/update requests of a repository
+// i.e. the desired spec of the repository. This allows us to separate "spec" from "status" fields.
+// See also: https://try.gitea.io/api/swagger#/repository/repoEdit
+func newGiteaRepositorySpec(repo *Repository) *giteaRepositorySpec {
+	return &giteaRepositorySpec{
+		&Repository{
+			Name:          repo.Name,
+			Description:   repo.Description,
+			Private:       repo.Private,
+			DefaultBranch: repo.DefaultBranch,
+		},
+	}
+}
+
+type giteaRepositorySpec struct {
+	*Repository
+}
+
+func (s *giteaRepositorySpec) Equals(other *giteaRepositorySpec) bool {
+	return reflect.DeepEqual(s, other)
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"context"
+	"errors"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+const (
+	// codeUnit is the repository unit controlling access to the code.
+	codeUnit = "repo.code"
+
+	teamPermissionNone  = "none"
+	teamPermissionRead  = "read"
+	teamPermissionWrite = "write"
+	teamPermissionAdmin = "admin"
+	teamPermissionOwner = "owner"
+)
+
+func newTeamAccess(c *TeamAccessClient, ta gitprovider.TeamAccessInfo) *teamAccess {
+	return &teamAccess{
+		ta: ta,
+		c:  c,
+	}
+}
+
+var _ gitprovider.TeamAccess = &teamAccess{}
+
+type teamAccess struct {
+	ta gitprovider.TeamAccessInfo
+	c  *TeamAccessClient
+}
+
+func (ta *teamAccess) Get() gitprovider.TeamAccessInfo {
+	return ta.ta
+}
+
+func (ta *teamAccess) Set(info gitprovider.TeamAccessInfo) error {
+	if err := info.ValidateInfo(); err != nil {
+		return err
+	}
+	ta.ta = info
+	return nil
+}
+
+func (ta *teamAccess) APIObject() interface{} {
+	return nil
+}
+
+func (ta *teamAccess) Repository() gitprovider.RepositoryRef {
+	return ta.c.ref
+}
+
+// Delete removes the given team from the repo's team access control list.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (ta *teamAccess) Delete(ctx context.Context) error {
+	// DELETE /repos/{owner}/{repo}/teams/{team}
+	return ta.c.c.RemoveRepoTeam(ctx, ta.c.ref.GetIdentity(), ta.c.ref.GetRepository(), ta.ta.Name)
+}
+
+// Update makes sure the team has access to the repository, with the desired permission.
+// As the permission can't be changed per repository, ErrNoProviderSupport is returned
+// if the team has another permission.
+func (ta *teamAccess) Update(ctx context.Context) error {
+	resp, err := ta.c.Create(ctx, ta.Get())
+	if err != nil {
+		// The team already has access, with the right permission
+		if errors.Is(err, gitprovider.ErrAlreadyExists) {
+			return nil
+		}
+		return err
+	}
+	return ta.Set(resp.Get())
+}
+
+// Reconcile makes sure the given desired state (req) becomes the actual state in the backing Git provider.
+//
+// If req doesn't exist under the hood, it is created (actionTaken == true).
+// If req doesn't equal the actual state, the resource will be deleted and recreated (actionTaken == true).
+// If req is already the actual state, this is a no-op (actionTaken == false).
+func (ta *teamAccess) Reconcile(ctx context.Context) (bool, error) {
+	req := ta.Get()
+	actual, err := ta.c.Get(ctx, req.Name)
+	if err != nil {
+		// Create if not found
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			resp, err := ta.c.Create(ctx, req)
+			if err != nil {
+				return true, err
+			}
+			return true, ta.Set(resp.Get())
+		}
+
+		// Unexpected path, Get should succeed or return NotFound
+		return false, err
+	}
+
+	// If the desired matches the actual state, just return the actual state
+	if req.Equals(actual.Get()) {
+		return false, nil
+	}
+
+	return true, ta.Update(ctx)
+}
+
+func teamAccessFromAPI(apiObj *Team) gitprovider.TeamAccessInfo {
+	return gitprovider.TeamAccessInfo{
+		Name:       apiObj.Name,
+		Permission: getPermissionFromTeam(apiObj),
+	}
+}
+
+// getPermissionFromTeam returns the permission the team has on its repositories. Newer servers
+// don't set the team-wide permission, the permission of the code unit is used in that case.
+func getPermissionFromTeam(apiObj *Team) *gitprovider.RepositoryPermission {
+	permission := apiObj.Permission
+	if len(permission) == 0 || permission == teamPermissionNone {
+		permission = apiObj.UnitsMap[codeUnit]
+	}
+	switch permission {
+	case teamPermissionRead:
+		return gitprovider.RepositoryPermissionVar(gitprovider.RepositoryPermissionPull)
+	case teamPermissionWrite:
+		return gitprovider.RepositoryPermissionVar(gitprovider.RepositoryPermissionPush)
+	case teamPermissionAdmin, teamPermissionOwner:
+		return gitprovider.RepositoryPermissionVar(gitprovider.RepositoryPermissionAdmin)
+	}
+	return nil
+}
@@ -0,0 +1,337 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Organization represents a Gitea organization.
+type Organization struct {
+	ID          int64  `json:"id,omitempty"`
+	UserName    string `json:"username"`
+	FullName    string `json:"full_name,omitempty"`
+	Description string `json:"description,omitempty"`
+	Website     string `json:"website,omitempty"`
+	Visibility  string `json:"visibility,omitempty"`
+}
+
+// User represents a Gitea user.
+type User struct {
+	ID       int64  `json:"id,omitempty"`
+	UserName string `json:"login"`
+	FullName string `json:"full_name,omitempty"`
+	Email    string `json:"email,omitempty"`
+}
+
+// Team represents a team in a Gitea organization. Permission is one of "none", "read", "write",
+// "admin" or "owner". Newer servers set Permission to "none", and specify the permission of
+// every repository unit in UnitsMap instead.
+type Team struct {
+	ID                      int64             `json:"id,omitempty"`
+	Name                    string            `json:"name"`
+	Description             string            `json:"description,omitempty"`
+	Permission              string            `json:"permission,omitempty"`
+	IncludesAllRepositories bool              `json:"includes_all_repositories,omitempty"`
+	Units                   []string          `json:"units,omitempty"`
+	UnitsMap                map[string]string `json:"units_map,omitempty"`
+}
+
+// Repository represents a Gitea repository.
+type Repository struct {
+	ID            int64   `json:"id,omitempty"`
+	Owner         *User   `json:"owner,omitempty"`
+	Name          string  `json:"name,omitempty"`
+	FullName      string  `json:"full_name,omitempty"`
+	Description   *string `json:"description,omitempty"`
+	Empty         bool    `json:"empty,omitempty"`
+	Private       *bool   `json:"private,omitempty"`
+	Fork          bool    `json:"fork,omitempty"`
+	Mirror        bool    `json:"mirror,omitempty"`
+	HTMLURL       string  `json:"html_url,omitempty"`
+	SSHURL        string  `json:"ssh_url,omitempty"`
+	CloneURL      string  `json:"clone_url,omitempty"`
+	DefaultBranch *string `json:"default_branch,omitempty"`
+}
+
+// CreateRepoOption is the request body for creating a repository.
+type CreateRepoOption struct {
+	Name          string `json:"name"`
+	Description   string `json:"description,omitempty"`
+	Private       bool   `json:"private"`
+	AutoInit      bool   `json:"auto_init,omitempty"`
+	License       string `json:"license,omitempty"`
+	Readme        string `json:"readme,omitempty"`
+	DefaultBranch string `json:"default_branch,omitempty"`
+}
+
+// DeployKey represents a Gitea deploy key.
+type DeployKey struct {
+	ID          int64  `json:"id,omitempty"`
+	KeyID       int64  `json:"key_id,omitempty"`
+	Key         string `json:"key"`
+	URL         string `json:"url,omitempty"`
+	Title       string `json:"title"`
+	Fingerprint string `json:"fingerprint,omitempty"`
+	ReadOnly    bool   `json:"read_only"`
+}
+
+// CommitMeta contains the SHA of a commit or tree, and its API URL.
+type CommitMeta struct {
+	URL string `json:"url,omitempty"`
+	SHA string `json:"sha"`
+}
+
+// CommitUser is the author or committer of a commit.
+type CommitUser struct {
+	Name  string `json:"name,omitempty"`
+	Email string `json:"email,omitempty"`
+	Date  string `json:"date,omitempty"`
+}
+
+// RepoCommit contains the git-level data of a commit.
+type RepoCommit struct {
+	Message   string      `json:"message,omitempty"`
+	Author    *CommitUser `json:"author,omitempty"`
+	Committer *CommitUser `json:"committer,omitempty"`
+	Tree      *CommitMeta `json:"tree,omitempty"`
+}
+
+// Commit represents a Gitea commit.
+type Commit struct {
+	CommitMeta
+	HTMLURL string        `json:"html_url,omitempty"`
+	Commit  *RepoCommit   `json:"commit,omitempty"`
+	Parents []*CommitMeta `json:"parents,omitempty"`
+}
+
+// FileCommitResponse is the commit created by a change to the contents of a repository.
+type FileCommitResponse struct {
+	CommitMeta
+	HTMLURL   string        `json:"html_url,omitempty"`
+	Author    *CommitUser   `json:"author,omitempty"`
+	Committer *CommitUser   `json:"committer,omitempty"`
+	Parents   []*CommitMeta `json:"parents,omitempty"`
+	Message   string        `json:"message,omitempty"`
+	Tree      *CommitMeta   `json:"tree,omitempty"`
+}
+
+const (
+	// fileOperationCreate creates a new file.
+	fileOperationCreate = "create"
+	// fileOperationUpdate updates an existing file, identified by its blob SHA.
+	fileOperationUpdate = "update"
+	// fileOperationDelete deletes an existing file, identified by its blob SHA.
+	fileOperationDelete = "delete"
+)
+
+// ChangeFileOperation is a change to a single file, part of ChangeFilesOptions.
+type ChangeFileOperation struct {
+	Operation string `json:"operation"`
+	Path      string `json:"path"`
+	// Content must be base64-encoded.
+	Content string `json:"content,omitempty"`
+	// SHA is the blob SHA of the file being updated or deleted.
+	SHA string `json:"sha,omitempty"`
+	// FromPath is the previous path of a file moved by an update.
+	FromPath string `json:"from_path,omitempty"`
+}
+
+// ChangeFilesOptions is the request body for changing multiple files in a single commit.
+type ChangeFilesOptions struct {
+	Branch    string                 `json:"branch,omitempty"`
+	NewBranch string                 `json:"new_branch,omitempty"`
+	Message   string                 `json:"message,omitempty"`
+	Author    *CommitUser            `json:"author,omitempty"`
+	Committer *CommitUser            `json:"committer,omitempty"`
+	Dates     *CommitDateOptions     `json:"dates,omitempty"`
+	Files     []*ChangeFileOperation `json:"files"`
+}
+
+// CommitDateOptions sets the author and committer dates of a commit, which otherwise are the
+// time of the commit.
+type CommitDateOptions struct {
+	Author    *time.Time `json:"author,omitempty"`
+	Committer *time.Time `json:"committer,omitempty"`
+}
+
+// FilesResponse is the response to a ChangeFilesOptions request.
+type FilesResponse struct {
+	Commit *FileCommitResponse `json:"commit"`
+}
+
+// ContentsResponse contains information about a file or directory in a repository.
+type ContentsResponse struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	SHA  string `json:"sha"`
+	Type string `json:"type"`
+	// Content is the base64-encoded content of a file.
+	Content string `json:"content,omitempty"`
+}
+
+// GitTreeResponse is a page of the entries of a git tree.
+type GitTreeResponse struct {
+	SHA       string      `json:"sha"`
+	Entries   []*GitEntry `json:"tree"`
+	Truncated bool        `json:"truncated"`
+	Page      int         `json:"page"`
+}
+
+// GitEntry is an entry of a git tree.
+type GitEntry struct {
+	Path string `json:"path"`
+	Mode string `json:"mode"`
+	Type string `json:"type"`
+	Size int64  `json:"size"`
+	SHA  string `json:"sha"`
+}
+
+// GitBlobResponse contains the content of a git blob.
+type GitBlobResponse struct {
+	SHA  string `json:"sha"`
+	Size int64  `json:"size"`
+	// Content is the content of the blob, encoded as specified by Encoding.
+	Content  string `json:"content"`
+	Encoding string `json:"encoding"`
+}
+
+// Branch represents a Gitea branch.
+type Branch struct {
+	Name      string         `json:"name"`
+	Commit    *PayloadCommit `json:"commit,omitempty"`
+	Protected bool           `json:"protected,omitempty"`
+}
+
+// PayloadCommit is the latest commit of a branch.
+type PayloadCommit struct {
+	ID      string `json:"id"`
+	Message string `json:"message,omitempty"`
+	URL     string `json:"url,omitempty"`
+}
+
+// BranchProtection represents the protection rules of a Gitea branch.
+// It is used both as the server response and as the body of create and edit requests.
+type BranchProtection struct {
+	BranchName             string   `json:"branch_name"`
+	EnablePush             bool     `json:"enable_push"`
+	EnablePushWhitelist    bool     `json:"enable_push_whitelist"`
+	PushWhitelistUsernames []string `json:"push_whitelist_usernames,omitempty"`
+	PushWhitelistTeams     []string `json:"push_whitelist_teams,omitempty"`
+	EnableStatusCheck      bool     `json:"enable_status_check"`
+	StatusCheckContexts    []string `json:"status_check_contexts"`
+	RequiredApprovals      int64    `json:"required_approvals"`
+	DismissStaleApprovals  bool     `json:"dismiss_stale_approvals"`
+}
+
+// PRBranchInfo is the head or base of a pull request.
+type PRBranchInfo struct {
+	Name string `json:"label,omitempty"`
+	Ref  string `json:"ref"`
+	Sha  string `json:"sha,omitempty"`
+}
+
+// PullRequest represents a Gitea pull request.
+type PullRequest struct {
+	ID        int64         `json:"id,omitempty"`
+	Number    int64         `json:"number,omitempty"`
+	HTMLURL   string        `json:"html_url,omitempty"`
+	Title     string        `json:"title"`
+	Body      string        `json:"body,omitempty"`
+	State     string        `json:"state,omitempty"`
+	Poster    *User         `json:"user,omitempty"`
+	Head      *PRBranchInfo `json:"head,omitempty"`
+	Base      *PRBranchInfo `json:"base,omitempty"`
+	Mergeable bool          `json:"mergeable,omitempty"`
+	HasMerged bool          `json:"merged,omitempty"`
+	Created   *time.Time    `json:"created_at,omitempty"`
+	Updated   *time.Time    `json:"updated_at,omitempty"`
+	Merged    *time.Time    `json:"merged_at,omitempty"`
+	Closed    *time.Time    `json:"closed_at,omitempty"`
+}
+
+// EditPullRequestOption is the request body for editing a pull request.
+type EditPullRequestOption struct {
+	Title *string `json:"title,omitempty"`
+	Body  *string `json:"body,omitempty"`
+	Base  *string `json:"base,omitempty"`
+	State *string `json:"state,omitempty"`
+}
+
+// MergePullRequestOption is the request body for merging a pull request. Do is one of
+// "merge", "rebase", "rebase-merge" or "squash".
+type MergePullRequestOption struct {
+	Do                string `json:"Do"`
+	MergeMessageField string `json:"MergeMessageField,omitempty"`
+}
+
+// CreatePullRequestOption is the request body for creating a pull request.
+type CreatePullRequestOption struct {
+	Head  string `json:"head"`
+	Base  string `json:"base"`
+	Title string `json:"title"`
+	Body  string `json:"body,omitempty"`
+}
+
+// Comment represents a comment on a Gitea issue or pull request.
+type Comment struct {
+	ID      int64     `json:"id,omitempty"`
+	HTMLURL string    `json:"html_url,omitempty"`
+	Body    string    `json:"body"`
+	Poster  *User     `json:"user,omitempty"`
+	Created time.Time `json:"created_at,omitempty"`
+	Updated time.Time `json:"updated_at,omitempty"`
+}
+
+// PullReview represents a review of a Gitea pull request. State is one of "APPROVED",
+// "PENDING", "COMMENT", "REQUEST_CHANGES" or "REQUEST_REVIEW".
+type PullReview struct {
+	ID        int64      `json:"id,omitempty"`
+	Reviewer  *User      `json:"user,omitempty"`
+	State     string     `json:"state,omitempty"`
+	Body      string     `json:"body,omitempty"`
+	Submitted *time.Time `json:"submitted_at,omitempty"`
+	HTMLURL   string     `json:"html_url,omitempty"`
+}
+
+// CreatePullReviewOptions is the request body for submitting a pull request review. Event is
+// one of "APPROVED", "REQUEST_CHANGES" or "COMMENT".
+type CreatePullReviewOptions struct {
+	Event string `json:"event"`
+	Body  string `json:"body,omitempty"`
+}
+
+// ErrorResponse is the error returned from the server when a request fails.
+type ErrorResponse struct {
+	// Response is the HTTP response that caused this error.
+	Response *http.Response `json:"-"`
+	// Message describes what went wrong.
+	Message string `json:"message"`
+	// URL points to the API documentation.
+	URL string `json:"url,omitempty"`
+}
+
+// Error implements the error interface.
+func (e *ErrorResponse) Error() string {
+	msg := fmt.Sprintf("%s %s: %d", e.Response.Request.Method, e.Response.Request.URL, e.Response.StatusCode)
+	if len(e.Message) != 0 {
+		msg = fmt.Sprintf("%s %s", msg, e.Message)
+	}
+	return msg
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+const (
+	alreadyExistsMagicString = "already exist"
+	alreadyAddedMagicString  = "already added"
+	keyInUseMagicString      = "has been used"
+	shaMismatchMagicString   = "sha does not match"
+	apiDocURL                = "https://docs.gitea.com/api/"
+)
+
+// validateUserRepositoryRef makes sure the UserRepositoryRef is valid for Gitea's usage.
+func validateUserRepositoryRef(ref gitprovider.UserRepositoryRef, expectedDomain string) error {
+	// Make sure the RepositoryRef fields are valid
+	if err := validation.ValidateTargets("UserRepositoryRef", ref); err != nil {
+		return err
+	}
+	// Make sure the type is valid, and domain is expected
+	return validateIdentityFields(ref, expectedDomain)
+}
+
+// validateOrgRepositoryRef makes sure the OrgRepositoryRef is valid for Gitea's usage.
+func validateOrgRepositoryRef(ref gitprovider.OrgRepositoryRef, expectedDomain string) error {
+	// Make sure the RepositoryRef fields are valid
+	if err := validation.ValidateTargets("OrgRepositoryRef", ref); err != nil {
+		return err
+	}
+	// Make sure the type is valid, and domain is expected
+	return validateIdentityFields(ref, expectedDomain)
+}
+
+// validateOrganizationRef makes sure the OrganizationRef is valid for Gitea's usage.
+func validateOrganizationRef(ref gitprovider.OrganizationRef, expectedDomain string) error {
+	// Make sure the OrganizationRef fields are valid
+	if err := validation.ValidateTargets("OrganizationRef", ref); err != nil {
+		return err
+	}
+	// Make sure the type is valid, and domain is expected
+	return validateIdentityFields(ref, expectedDomain)
+}
+
+// validateUserRef makes sure the UserRef is valid for Gitea's usage.
+func validateUserRef(ref gitprovider.UserRef, expectedDomain string) error {
+	// Make sure the UserRef fields are valid
+	if err := validation.ValidateTargets("UserRef", ref); err != nil {
+		return err
+	}
+	// Make sure the type is valid, and domain is expected
+	return validateIdentityFields(ref, expectedDomain)
+}
+
+// validateIdentityFields makes sure the type of the IdentityRef is supported, and the domain is as expected.
+func validateIdentityFields(ref gitprovider.IdentityRef, expectedDomain string) error {
+	// Make sure the expected domain is used
+	if ref.GetDomain() != expectedDomain {
+		return fmt.Errorf("domain %q not supported by this client: %w", ref.GetDomain(), gitprovider.ErrDomainUnsupported)
+	}
+	// Make sure the right type of identityref is used
+	switch ref.GetType() {
+	case gitprovider.IdentityTypeOrganization, gitprovider.IdentityTypeUser:
+		return nil
+	case gitprovider.IdentityTypeSuborganization:
+		return fmt.Errorf("gitea doesn't support sub-organizations: %w", gitprovider.ErrNoProviderSupport)
+	}
+	return fmt.Errorf("invalid identity type: %v: %w", ref.GetType(), gitprovider.ErrInvalidArgument)
+}
+
+// handleHTTPError checks the type of err, and returns typed variants of it
+// However, it _always_ keeps the original error too, and just wraps it in a MultiError
+// The consumer must use errors.Is and errors.As to check for equality and get data out of it.
+func handleHTTPError(err error) error {
+	// Short-circuit quickly if possible, allow always piping through this function
+	if err == nil {
+		return nil
+	}
+	giteaErrorResponse := &ErrorResponse{}
+	if errors.As(err, &giteaErrorResponse) {
+		// The server points to its own API documentation, if it's enabled
+		docURL := giteaErrorResponse.URL
+		if len(docURL) == 0 {
+			docURL = apiDocURL
+		}
+		httpErr := gitprovider.HTTPError{
+			Response:         giteaErrorResponse.Response,
+			ErrorMessage:     giteaErrorResponse.Error(),
+			Message:          giteaErrorResponse.Message,
+			DocumentationURL: docURL,
+		}
+		switch giteaErrorResponse.Response.StatusCode {
+		// Check for invalid credentials, and return a typed error in that case
+		case http.StatusForbidden, http.StatusUnauthorized:
+			return validation.NewMultiError(err,
+				&gitprovider.InvalidCredentialsError{HTTPError: httpErr},
+			)
+		// Check for 404 Not Found
+		case http.StatusNotFound:
+			return validation.NewMultiError(err, gitprovider.ErrNotFound)
+		// Rate limiting is optional, and only returns the status code and Retry-After
+		case http.StatusTooManyRequests:
+			return validation.NewMultiError(err, &gitprovider.RateLimitError{HTTPError: httpErr})
+		}
+		// A file changed since its SHA was read, i.e. the branch moved during a commit
+		if giteaErrorResponse.Response.StatusCode == http.StatusConflict &&
+			strings.Contains(strings.ToLower(giteaErrorResponse.Message), shaMismatchMagicString) {
+			return validation.NewMultiError(err, gitprovider.ErrConflict)
+		}
+		// Check for already exists errors
+		if isAlreadyExistsError(giteaErrorResponse) {
+			return validation.NewMultiError(err, gitprovider.ErrAlreadyExists)
+		}
+		// Otherwise, return a generic *HTTPError
+		return validation.NewMultiError(err, &httpErr)
+	}
+	// Do nothing, just pipe through the unknown err
+	return err
+}
+
+// isAlreadyExistsError returns true if the error message denotes a conflict with an existing resource.
+// Gitea uses both 409 and 422 for these, hence the message is checked instead of the status code.
+func isAlreadyExistsError(e *ErrorResponse) bool {
+	msg := strings.ToLower(e.Message)
+	return strings.Contains(msg, alreadyExistsMagicString) ||
+		strings.Contains(msg, alreadyAddedMagicString) ||
+		strings.Contains(msg, keyInUseMagicString)
+}
+
+// validateAPIObject creates a Validatior with the specified name, gives it to fn, and
+// depending on if any error was registered with it; either returns nil, or a MultiError
+// with both the validation error and ErrInvalidServerData, to mark that the server data
+// was invalid.
+func validateAPIObject(name string, fn func(validation.Validator)) error {
+	v := validation.New(name)
+	fn(v)
+	// If there was a validation error, also mark it specifically as invalid server data
+	if err := v.Error(); err != nil {
+		return validation.NewMultiError(err, gitprovider.ErrInvalidServerData)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitea
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+func Test_validateAPIObject(t *testing.T) {
+	tests := []struct {
+		name         string
+		structName   string
+		fn           func(validation.Validator)
+		expectedErrs []error
+	}{
+		{
+			name:       "no error => nil",
+			structName: "Foo",
+			fn:         func(validation.Validator) {},
+		},
+		{
+			name:       "one error => MultiError & InvalidServerData",
+			structName: "Foo",
+			fn: func(v validation.Validator) {
+				v.Required("FieldBar")
+			},
+			expectedErrs: []error{gitprovider.ErrInvalidServerData, &validation.MultiError{}, validation.ErrFieldRequired},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAPIObject(tt.structName, tt.fn)
+			validation.TestExpectErrors(t, "validateAPIObject", err, tt.expectedErrs...)
+		})
+	}
+}
+
+func newGiteaError(statusCode int, message string) *ErrorResponse {
+	return &ErrorResponse{
+		Response: &http.Response{
+			Request: &http.Request{
+				Method: "GET",
+				URL:    &url.URL{},
+			},
+			StatusCode: statusCode,
+		},
+		Message: message,
+	}
+}
+
+func Test_handleHTTPError(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		expectedErrs []error
+	}{
+		{
+			name: "nil => nil",
+		},
+		{
+			name:         "unknown error => passthrough",
+			err:          gitprovider.ErrUnexpectedEvent,
+			expectedErrs: []error{gitprovider.ErrUnexpectedEvent},
+		},
+		{
+			name:         "401 => InvalidCredentialsError",
+			err:          newGiteaError(http.StatusUnauthorized, "token is required"),
+			expectedErrs: []error{&validation.MultiError{}, &gitprovider.InvalidCredentialsError{}, &ErrorResponse{}},
+		},
+		{
+			name:         "403 => InvalidCredentialsError",
+			err:          newGiteaError(http.StatusForbidden, "user should be an owner or a collaborator with admin write of a repository"),
+			expectedErrs: []error{&validation.MultiError{}, &gitprovider.InvalidCredentialsError{}, &ErrorResponse{}},
+		},
+		{
+			name:         "404 => ErrNotFound",
+			err:          newGiteaError(http.StatusNotFound, "The target couldn't be found."),
+			expectedErrs: []error{&validation.MultiError{}, gitprovider.ErrNotFound, &ErrorResponse{}},
+		},
+		{
+			name:         "429 => RateLimitError",
+			err:          newGiteaError(http.StatusTooManyRequests, ""),
+			expectedErrs: []error{&validation.MultiError{}, &gitprovider.RateLimitError{}, &ErrorResponse{}},
+		},
+		{
+			name:         "409 with already exists message => ErrAlreadyExists",
+			err:          newGiteaError(http.StatusConflict, "The repository with the same name already exists."),
+			expectedErrs: []error{&validation.MultiError{}, gitprovider.ErrAlreadyExists, &ErrorResponse{}},
+		},
+		{
+			name:         "422 with key in use message => ErrAlreadyExists",
+			err:          newGiteaError(http.StatusUnprocessableEntity, "Key content has been used as non-deploy key"),
+			expectedErrs: []error{&validation.MultiError{}, gitprovider.ErrAlreadyExists, &ErrorResponse{}},
+		},
+		{
+			name:         "422 otherwise => HTTPError",
+			err:          newGiteaError(http.StatusUnprocessableEntity, "[Name]: Required"),
+			expectedErrs: []error{&validation.MultiError{}, &gitprovider.HTTPError{}, &ErrorResponse{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := handleHTTPError(tt.err)
+			validation.TestExpectErrors(t, "handleHTTPError", err, tt.expectedErrs...)
+			if tt.err == nil {
+				return
+			}
+			// The original error must always be kept
+			if !errors.Is(err, tt.err) {
+				t.Errorf("handleHTTPError() = %v, expected to wrap %v", err, tt.err)
+			}
+		})
+	}
+}
@@ -25,6 +25,8 @@ import (
 
 	"github.com/fluxcd/go-git-providers/gitprovider"
 	"github.com/fluxcd/go-git-providers/gitprovider/cache"
+	"github.com/fluxcd/go-git-providers/gitprovider/ratelimit"
+	"github.com/fluxcd/go-git-providers/gitprovider/retry"
 )
 
 const (
@@ -50,11 +52,26 @@ type clientOptions struct {
 	// AuthTransport is a ChainableRoundTripperFunc adding authentication credentials to the transport chain.
 	AuthTransport gitprovider.ChainableRoundTripperFunc
 
+	// credential is the token set by WithOAuth2Token, namespacing the keys of CacheBackend.
+	credential string
+
 	// EnableConditionalRequests will be set if conditional requests should be used.
 	// TODO: Move this to gitprovider.CommonClientOptions if other providers support this too.
 	// See: https://developer.github.com/v3/#conditional-requests for more info.
 	// Default: false
 	EnableConditionalRequests *bool
+
+	// CacheBackend stores the responses if conditional requests are enabled.
+	// Default: nil (in memory)
+	CacheBackend cache.Backend
+
+	// RetryOptions will be set if transient and rate-limited failures should be retried.
+	// Default: nil (no retries)
+	RetryOptions *retry.Options
+
+	// RateLimiter throttles requests according to the quota reported by the server.
+	// Default: nil (no throttling)
+	RateLimiter *ratelimit.Limiter
 }
 
 // ApplyToGithubClientOptions implements ClientOption, and applies the set fields of opts
@@ -71,6 +88,7 @@ func (opts *clientOptions) ApplyToGithubClientOptions(target *clientOptions) err
 			return fmt.Errorf("option AuthTransport already configured: %w", gitprovider.ErrInvalidClientOptions)
 		}
 		target.AuthTransport = opts.AuthTransport
+		target.credential = opts.credential
 	}
 
 	if opts.EnableConditionalRequests != nil {
@@ -80,6 +98,30 @@ func (opts *clientOptions) ApplyToGithubClientOptions(target *clientOptions) err
 		}
 		target.EnableConditionalRequests = opts.EnableConditionalRequests
 	}
+
+	if opts.CacheBackend != nil {
+		// Make sure the user didn't specify the CacheBackend twice
+		if target.CacheBackend != nil {
+			return fmt.Errorf("option CacheBackend already configured: %w", gitprovider.ErrInvalidClientOptions)
+		}
+		target.CacheBackend = opts.CacheBackend
+	}
+
+	if opts.RetryOptions != nil {
+		// Make sure the user didn't specify the RetryOptions twice
+		if target.RetryOptions != nil {
+			return fmt.Errorf("option RetryOptions already configured: %w", gitprovider.ErrInvalidClientOptions)
+		}
+		target.RetryOptions = opts.RetryOptions
+	}
+
+	if opts.RateLimiter != nil {
+		// Make sure the user didn't specify the RateLimiter twice
+		if target.RateLimiter != nil {
+			return fmt.Errorf("option RateLimiter already configured: %w", gitprovider.ErrInvalidClientOptions)
+		}
+		target.RateLimiter = opts.RateLimiter
+	}
 	return nil
 }
 
@@ -89,13 +131,23 @@ func (opts *clientOptions) getTransportChain() (chain []gitprovider.ChainableRou
 	if opts.PostChainTransportHook != nil {
 		chain = append(chain, opts.PostChainTransportHook)
 	}
+	if opts.RateLimiter != nil {
+		chain = append(chain, opts.RateLimiter.Transport)
+	}
+	if opts.RetryOptions != nil {
+		chain = append(chain, retry.NewRetryTransport(*opts.RetryOptions))
+	}
 	if opts.AuthTransport != nil {
 		chain = append(chain, opts.AuthTransport)
 	}
 	if opts.EnableConditionalRequests != nil && *opts.EnableConditionalRequests {
 		// TODO: Provide some kind of debug logging if/when the httpcache is used
 		// One can see if the request hit the cache using: resp.Header[httpcache.XFromCache]
-		chain = append(chain, cache.NewHTTPCacheTransport)
+		if opts.CacheBackend != nil {
+			chain = append(chain, cache.NewHTTPCacheTransportFunc(cache.ForCredential(opts.CacheBackend, opts.credential)))
+		} else {
+			chain = append(chain, cache.NewHTTPCacheTransport)
+		}
 	}
 	if opts.PreChainTransportHook != nil {
 		chain = append(chain, opts.PreChainTransportHook)
@@ -173,7 +225,7 @@ func WithOAuth2Token(oauth2Token string) ClientOption {
 		return optionError(fmt.Errorf("oauth2Token cannot be empty: %w", gitprovider.ErrInvalidClientOptions))
 	}
 
-	return &clientOptions{AuthTransport: oauth2Transport(oauth2Token)}
+	return &clientOptions{AuthTransport: oauth2Transport(oauth2Token), credential: oauth2Token}
 }
 
 func oauth2Transport(oauth2Token string) gitprovider.ChainableRoundTripperFunc {
@@ -195,6 +247,41 @@ func WithConditionalRequests(conditionalRequests bool) ClientOption {
 	return &clientOptions{EnableConditionalRequests: &conditionalRequests}
 }
 
+// WithConditionalRequestsBackend is like WithConditionalRequests(true), but stores the cached responses
+// in the given backend, e.g. cache.NewDiskBackend to survive restarts, cache.NewLRUBackend to bound the
+// memory usage, or cache.NewExternalBackend to share the cache between replicas. The built-in backends
+// report hit/miss statistics through cache.StatsReporter. The keys are namespaced by a hash of the token
+// (see cache.ForCredential), so clients using different tokens can share a backend without seeing each
+// other's responses.
+func WithConditionalRequestsBackend(backend cache.Backend) ClientOption {
+	// Don't allow an empty value
+	if backend == nil {
+		return optionError(fmt.Errorf("backend cannot be nil: %w", gitprovider.ErrInvalidClientOptions))
+	}
+
+	return &clientOptions{EnableConditionalRequests: gitprovider.BoolVar(true), CacheBackend: backend}
+}
+
+// WithRetry instructs the client to retry requests failing because of transient errors, like
+// 502, 503 and 504 responses or connection resets, and because of rate limiting, with exponential
+// backoff. Non-idempotent requests (POST and PATCH) are only retried when rejected by rate limiting.
+// Zero fields of opts are replaced by their defaults. See retry.NewRetryTransport for more information.
+func WithRetry(opts retry.Options) ClientOption {
+	return &clientOptions{RetryOptions: &opts}
+}
+
+// WithRateLimiter instructs the client to throttle requests using the given ratelimit.Limiter, before
+// the quota reported by the server is exhausted. The limiter may be shared by all clients using the
+// same token, in order to share the quota. See ratelimit.NewLimiter for more information.
+func WithRateLimiter(limiter *ratelimit.Limiter) ClientOption {
+	// Don't allow an empty value
+	if limiter == nil {
+		return optionError(fmt.Errorf("limiter cannot be nil: %w", gitprovider.ErrInvalidClientOptions))
+	}
+
+	return &clientOptions{RateLimiter: limiter}
+}
+
 // makeOptions assembles a clientOptions struct from ClientOption mutator functions.
 func makeOptions(opts ...ClientOption) (*clientOptions, error) {
 	o := &clientOptions{}
@@ -217,10 +304,13 @@ func makeOptions(opts ...ClientOption) (*clientOptions, error) {
 // GitHub Enterprise can be used if you specify the domain using WithDomain.
 //
 // You can customize low-level HTTP Transport functionality by using the With{Pre,Post}ChainTransportHook options.
-// You can also use conditional requests (and an in-memory cache) using WithConditionalRequests.
+// You can also use conditional requests (and an in-memory cache) using WithConditionalRequests, or
+// WithConditionalRequestsBackend for other cache backends,
+// retries of transient and rate-limited failures using WithRetry, and client-side throttling using
+// WithRateLimiter.
 //
 // The chain of transports looks like this:
-// github.com API <-> "Post Chain" <-> Authentication <-> Cache <-> "Pre Chain" <-> *github.Client.
+// github.com API <-> "Post Chain" <-> Rate limiter <-> Retry <-> Authentication <-> Cache <-> "Pre Chain" <-> *github.Client.
 func NewClient(optFns ...ClientOption) (gitprovider.Client, error) {
 	// Complete the options struct
 	opts, err := makeOptions(optFns...)
@@ -23,6 +23,8 @@ import (
 
 	"github.com/fluxcd/go-git-providers/gitprovider"
 	"github.com/fluxcd/go-git-providers/gitprovider/cache"
+	"github.com/fluxcd/go-git-providers/gitprovider/ratelimit"
+	"github.com/fluxcd/go-git-providers/gitprovider/retry"
 	"github.com/fluxcd/go-git-providers/validation"
 )
 
@@ -106,7 +108,24 @@ func Test_clientOptions_getTransportChain(t *testing.T) {
 	}
 }
 
+func Test_clientOptions_getTransportChain_retry(t *testing.T) {
+	opts := &clientOptions{
+		CommonClientOptions: gitprovider.CommonClientOptions{
+			PostChainTransportHook: dummyRoundTripper1,
+		},
+		AuthTransport: dummyRoundTripper2,
+		RetryOptions:  &retry.Options{},
+	}
+	// expect: "post chain" <-> "retry" <-> "auth"
+	gotChain := opts.getTransportChain()
+	if len(gotChain) != 3 || !roundTrippersEqual(gotChain[0], dummyRoundTripper1) || !roundTrippersEqual(gotChain[2], dummyRoundTripper2) {
+		t.Errorf("clientOptions.getTransportChain() = %v, want post chain, retry and auth", gotChain)
+	}
+}
+
 func Test_makeOptions(t *testing.T) {
+	limiter := ratelimit.NewLimiter(ratelimit.Options{})
+	backend := cache.NewMemoryBackend()
 	tests := []struct {
 		name         string
 		opts         []ClientOption
@@ -155,7 +174,7 @@ func Test_makeOptions(t *testing.T) {
 		{
 			name: "WithOAuth2Token",
 			opts: []ClientOption{WithOAuth2Token("foo")},
-			want: &clientOptions{AuthTransport: oauth2Transport("foo")},
+			want: &clientOptions{AuthTransport: oauth2Transport("foo"), credential: "foo"},
 		},
 		{
 			name:         "WithOAuth2Token, empty",
@@ -172,6 +191,46 @@ func Test_makeOptions(t *testing.T) {
 			opts:         []ClientOption{WithConditionalRequests(true), WithConditionalRequests(false)},
 			expectedErrs: []error{gitprovider.ErrInvalidClientOptions},
 		},
+		{
+			name: "WithConditionalRequestsBackend",
+			opts: []ClientOption{WithConditionalRequestsBackend(backend)},
+			want: &clientOptions{EnableConditionalRequests: gitprovider.BoolVar(true), CacheBackend: backend},
+		},
+		{
+			name:         "WithConditionalRequestsBackend, nil",
+			opts:         []ClientOption{WithConditionalRequestsBackend(nil)},
+			expectedErrs: []error{gitprovider.ErrInvalidClientOptions},
+		},
+		{
+			name:         "WithConditionalRequestsBackend, exclusive with WithConditionalRequests",
+			opts:         []ClientOption{WithConditionalRequests(true), WithConditionalRequestsBackend(backend)},
+			expectedErrs: []error{gitprovider.ErrInvalidClientOptions},
+		},
+		{
+			name: "WithRetry",
+			opts: []ClientOption{WithRetry(retry.Options{MaxRetries: 2})},
+			want: &clientOptions{RetryOptions: &retry.Options{MaxRetries: 2}},
+		},
+		{
+			name: "WithRateLimiter",
+			opts: []ClientOption{WithRateLimiter(limiter)},
+			want: &clientOptions{RateLimiter: limiter},
+		},
+		{
+			name:         "WithRateLimiter, nil",
+			opts:         []ClientOption{WithRateLimiter(nil)},
+			expectedErrs: []error{gitprovider.ErrInvalidClientOptions},
+		},
+		{
+			name:         "WithRateLimiter, exclusive",
+			opts:         []ClientOption{WithRateLimiter(limiter), WithRateLimiter(limiter)},
+			expectedErrs: []error{gitprovider.ErrInvalidClientOptions},
+		},
+		{
+			name:         "WithRetry, exclusive",
+			opts:         []ClientOption{WithRetry(retry.Options{}), WithRetry(retry.Options{})},
+			expectedErrs: []error{gitprovider.ErrInvalidClientOptions},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -23,6 +23,7 @@ import (
 	"github.com/google/go-github/v32/github"
 
 	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/gitprovider/ratelimit"
 )
 
 // ProviderID is the provider ID for GitHub.
@@ -129,3 +130,18 @@ func (c *Client) HasTokenPermission(ctx context.Context, permission gitprovider.
 
 	return false, nil
 }
+
+// Quota returns the current quota of the core API, as requested from GitHub. Requesting it doesn't
+// count against the quota, and isn't throttled by the rate limiter, so that it can be requested
+// while the quota is exhausted.
+func (c *Client) Quota(ctx context.Context) (gitprovider.Quota, error) {
+	rate, err := c.c.GetRateLimit(ratelimit.WithoutThrottling(ctx))
+	if err != nil {
+		return gitprovider.Quota{}, err
+	}
+	return gitprovider.Quota{
+		Limit:     rate.Limit,
+		Remaining: rate.Remaining,
+		Reset:     rate.Reset.Time,
+	}, nil
+}
@@ -18,8 +18,8 @@ package github
 
 import (
 	"context"
+
 	"github.com/fluxcd/go-git-providers/gitprovider"
-	"github.com/google/go-github/v32/github"
 )
 
 // BranchClient implements the gitprovider.BranchClient interface.
@@ -31,21 +31,63 @@ type BranchClient struct {
 	ref gitprovider.RepositoryRef
 }
 
-// Create creates a branch with the given specifications.
-func (c *BranchClient) Create(ctx context.Context, branch, sha string) error {
+// Get returns the branch with the given name.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *BranchClient) Get(ctx context.Context, branch string) (gitprovider.Branch, error) {
+	// GET /repos/{owner}/{repo}/branches/{branch}
+	apiObj, err := c.c.GetBranch(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), branch)
+	if err != nil {
+		return nil, err
+	}
+	return newBranch(apiObj), nil
+}
 
-	ref := "refs/heads/" + branch
+// List lists all branches of the repository.
+//
+// List returns all available branches, using multiple paginated requests if needed.
+func (c *BranchClient) List(ctx context.Context) ([]gitprovider.Branch, error) {
+	// GET /repos/{owner}/{repo}/branches
+	apiObjs, err := c.c.ListBranches(ctx, c.ref.GetIdentity(), c.ref.GetRepository())
+	if err != nil {
+		return nil, err
+	}
 
-	reference := &github.Reference{
-		Ref: &ref,
-		Object: &github.GitObject{
-			SHA: &sha,
-		},
+	branches := make([]gitprovider.Branch, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		branches = append(branches, newBranch(apiObj))
 	}
+	return branches, nil
+}
 
-	if _, _, err := c.c.Client().Git.CreateRef(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), reference); err != nil {
-		return err
+// Create creates a branch with the given specifications.
+func (c *BranchClient) Create(ctx context.Context, branch, sha string) error {
+	// POST /repos/{owner}/{repo}/git/refs
+	return c.c.CreateBranch(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), branch, sha)
+}
+
+// CreateFrom creates a branch starting at the head of another branch, or at a tag.
+//
+// ErrNotFound is returned if from doesn't exist, and ErrAlreadyExists if branch does.
+func (c *BranchClient) CreateFrom(ctx context.Context, branch, from string) (gitprovider.Branch, error) {
+	// GET /repos/{owner}/{repo}/commits/{ref}
+	sha, err := c.c.GetCommitSHA(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), from)
+	if err != nil {
+		return nil, err
+	}
+	// POST /repos/{owner}/{repo}/git/refs
+	if err := c.c.CreateBranch(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), branch, sha); err != nil {
+		return nil, err
 	}
+	// GET /repos/{owner}/{repo}/branches/{branch}
+	return c.Get(ctx, branch)
+}
 
-	return nil
+// Delete deletes the branch with the given name.
+//
+// ErrDestructiveCallDisallowed is returned if the client wasn't set up with destructive
+// API calls enabled. ErrNotFound is returned if the resource does not exist.
+func (c *BranchClient) Delete(ctx context.Context, branch string) error {
+	// DELETE /repos/{owner}/{repo}/git/refs/heads/{branch}
+	return c.c.DeleteBranch(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), branch)
 }
@@ -18,8 +18,12 @@ package github
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"path"
+
 	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
 	"github.com/google/go-github/v32/github"
 )
 
@@ -68,46 +72,122 @@ func (c *CommitClient) listPage(ctx context.Context, branch string, perPage, pag
 }
 
 // Create creates a commit with the given specifications.
-func (c *CommitClient) Create(ctx context.Context, branch string, message string, files []gitprovider.CommitFile) (gitprovider.Commit, error) {
+//
+// The files are changed in a new tree on top of the tree of the head of branch. Files without
+// an Action are deleted if their Content is nil, and created or updated otherwise. New files are
+// regular files unless Mode is set, while changed and moved files keep their mode.
+//
+// The Author and Committer given in opts are used as the identities of the commit.
+//
+// New branches are created from the head of the StartBranch given in opts. As the git database API
+// doesn't work in empty repositories, their first file is committed through the contents API, and
+// the other files are committed on top of it, resulting in two commits.
+//
+// The branch is updated without force, so ErrConflict is returned if it moved while the commit
+// was being created, as well as if its head isn't the ExpectedParentSHA given in opts.
+//
+// ErrNotFound is returned if a file to update, delete or move does not exist.
+// ErrAlreadyExists is returned if a file to create, or the destination of a move, exists.
+func (c *CommitClient) Create(ctx context.Context, branch string, message string, files []gitprovider.CommitFile, opts ...gitprovider.CommitCreateOption) (gitprovider.Commit, error) {
+	o, err := gitprovider.MakeCommitCreateOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
 
 	if len(files) == 0 {
 		return nil, fmt.Errorf("no files added")
 	}
-
-	treeEntries := make([]*github.TreeEntry, 0)
 	for _, file := range files {
-		treeEntries = append(treeEntries, &github.TreeEntry{
-			Path:    file.Path,
-			Mode:    &githubNewFileMode,
-			Type:    &githubBlobTypeFile,
-			Content: file.Content,
-		})
+		if err := file.ValidateInfo(); err != nil {
+			return nil, err
+		}
 	}
 
-	commits, err := c.ListPage(ctx, branch, 1, 0)
+	// New branches start at the head of the start branch, if given
+	parentSHA, exists, err := c.head(ctx, branch)
 	if err != nil {
 		return nil, err
 	}
+	newBranch := !exists
+	if newBranch && o.StartBranch != nil {
+		if parentSHA, exists, err = c.head(ctx, *o.StartBranch); err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, fmt.Errorf("branch %q: %w", *o.StartBranch, gitprovider.ErrNotFound)
+		}
+	}
+	if !exists {
+		// Only the first branch of an empty repository can be created without a start branch
+		branches, err := c.c.ListBranches(ctx, c.ref.GetIdentity(), c.ref.GetRepository())
+		if err != nil {
+			return nil, err
+		}
+		if len(branches) != 0 {
+			return nil, fmt.Errorf("branch %q: %w", branch, gitprovider.ErrNotFound)
+		}
+	}
+	if o.ConflictsWith(parentSHA) {
+		return nil, fmt.Errorf("branch %q is at %q, not %q: %w", branch, parentSHA, *o.ExpectedParentSHA, gitprovider.ErrConflict)
+	}
+	if !exists {
+		return c.createInitial(ctx, branch, message, files, o)
+	}
 
-	latestCommitTreeSHA := commits[0].Get().TreeSha
+	// The modes and blobs of existing files are needed to update, move and delete them
+	baseTreeSHA, baseEntries, err := c.baseTree(ctx, parentSHA, files)
+	if err != nil {
+		return nil, err
+	}
 
-	tree, _, err := c.c.Client().Git.CreateTree(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), latestCommitTreeSHA, treeEntries)
+	treeEntries, err := commitTreeEntries(baseEntries, files, func(content string) (*string, error) {
+		// POST /repos/{owner}/{repo}/git/blobs
+		blob, _, err := c.c.Client().Git.CreateBlob(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), &github.Blob{
+			Content:  &content,
+			Encoding: github.String(string(gitprovider.ContentEncodingBase64)),
+		})
+		if err != nil {
+			return nil, handleHTTPError(err)
+		}
+		return blob.SHA, nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	latestCommitSHA := commits[0].Get().Sha
+	// POST /repos/{owner}/{repo}/git/trees
+	tree, _, err := c.c.Client().Git.CreateTree(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), baseTreeSHA, treeEntries)
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+
+	// POST /repos/{owner}/{repo}/git/commits
 	nCommit, _, err := c.c.Client().Git.CreateCommit(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), &github.Commit{
 		Message: &message,
 		Tree:    tree,
 		Parents: []*github.Commit{
-			&github.Commit{
-				SHA: &latestCommitSHA,
+			{
+				SHA: &parentSHA,
 			},
 		},
+		Author:    commitAuthorToAPI(o.Author),
+		Committer: commitAuthorToAPI(o.Committer),
 	})
 	if err != nil {
-		return nil, err
+		return nil, handleHTTPError(err)
+	}
+
+	if newBranch {
+		// A concurrently created branch makes this fail, instead of being overwritten
+		// POST /repos/{owner}/{repo}/git/refs
+		err := c.c.CreateBranch(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), branch, nCommit.GetSHA())
+		if errors.Is(err, gitprovider.ErrAlreadyExists) {
+			return nil, validation.NewMultiError(err, gitprovider.ErrConflict)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return newCommit(c, nCommit), nil
 	}
 
 	ref := "refs/heads/" + branch
@@ -118,9 +198,217 @@ func (c *CommitClient) Create(ctx context.Context, branch string, message string
 		},
 	}
 
-	if _, _, err := c.c.Client().Git.UpdateRef(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), ghRef, true); err != nil {
-		return nil, err
+	// Concurrent pushes to the branch make this update fail, instead of being overwritten
+	// PATCH /repos/{owner}/{repo}/git/refs/{ref}
+	if _, _, err := c.c.Client().Git.UpdateRef(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), ghRef, false); err != nil {
+		return nil, handleRefError(err)
 	}
 
 	return newCommit(c, nCommit), nil
 }
+
+// head returns the SHA of the head of branch, and whether branch exists.
+func (c *CommitClient) head(ctx context.Context, branch string) (string, bool, error) {
+	// GET /repos/{owner}/{repo}/branches/{branch}
+	apiObj, err := c.c.GetBranch(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), branch)
+	if errors.Is(err, gitprovider.ErrNotFound) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return apiObj.GetCommit().GetSHA(), true, nil
+}
+
+// baseTree returns the SHA of the tree of the commit with the given SHA, and the entries of its
+// files, at least of those at the paths of files. GitHub truncates the recursive tree of large
+// repositories, in which case only the directories of files are listed, one by one.
+func (c *CommitClient) baseTree(ctx context.Context, commitSHA string, files []gitprovider.CommitFile) (string, []*github.TreeEntry, error) {
+	// GET /repos/{owner}/{repo}/git/trees/{tree_sha}?recursive=1
+	tree, _, err := c.c.Client().Git.GetTree(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), commitSHA, true)
+	if err != nil {
+		return "", nil, handleHTTPError(err)
+	}
+	if !tree.GetTruncated() {
+		return tree.GetSHA(), tree.Entries, nil
+	}
+
+	var entries []*github.TreeEntry
+	listed := map[string]bool{}
+	for _, file := range files {
+		paths := []string{*file.Path}
+		if file.PreviousPath != nil {
+			paths = append(paths, *file.PreviousPath)
+		}
+		for _, p := range paths {
+			dir := path.Dir(p)
+			if listed[dir] {
+				continue
+			}
+			listed[dir] = true
+			dirEntries, err := c.dirEntries(ctx, commitSHA, dir)
+			if err != nil {
+				return "", nil, err
+			}
+			entries = append(entries, dirEntries...)
+		}
+	}
+	return tree.GetSHA(), entries, nil
+}
+
+// dirEntries returns the entries of the directory dir in the tree of the commit with the given
+// SHA, with their paths relative to the root of the tree. A missing directory has no entries.
+func (c *CommitClient) dirEntries(ctx context.Context, commitSHA, dir string) ([]*github.TreeEntry, error) {
+	treeish := commitSHA
+	if dir != "." {
+		treeish = commitSHA + ":" + dir
+	}
+	// GET /repos/{owner}/{repo}/git/trees/{tree_sha}
+	tree, _, err := c.c.Client().Git.GetTree(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), treeish, false)
+	if err != nil {
+		if err = handleHTTPError(err); errors.Is(err, gitprovider.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	entries := make([]*github.TreeEntry, 0, len(tree.Entries))
+	for _, apiEntry := range tree.Entries {
+		entry := *apiEntry
+		if dir != "." {
+			entry.Path = github.String(dir + "/" + apiEntry.GetPath())
+		}
+		entries = append(entries, &entry)
+	}
+	return entries, nil
+}
+
+// createInitial creates the first commit(s) of an empty repository on branch. The first regular
+// file is committed through the contents API, as the git database API fails until the repository
+// has a commit, and the other files are committed on top of it.
+func (c *CommitClient) createInitial(ctx context.Context, branch, message string, files []gitprovider.CommitFile, o gitprovider.CommitCreateOptions) (gitprovider.Commit, error) {
+	first := -1
+	for i, file := range files {
+		// There's nothing to update, delete or move in an empty repository
+		action := file.GetAction()
+		if (len(action) != 0 && action != gitprovider.CommitFileActionCreate) || file.Content == nil {
+			return nil, fmt.Errorf("file %q: %w", *file.Path, gitprovider.ErrNotFound)
+		}
+		if first == -1 && !file.IsExecutable() {
+			first = i
+		}
+	}
+	if first == -1 {
+		return nil, fmt.Errorf("cannot commit only executable files to an empty repository: %w", gitprovider.ErrNoProviderSupport)
+	}
+
+	content, err := files[first].GetContent()
+	if err != nil {
+		return nil, err
+	}
+	// PUT /repos/{owner}/{repo}/contents/{path}
+	resp, _, err := c.c.Client().Repositories.CreateFile(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), *files[first].Path, &github.RepositoryContentFileOptions{
+		Message:   &message,
+		Content:   content,
+		Branch:    &branch,
+		Author:    commitAuthorToAPI(o.Author),
+		Committer: commitAuthorToAPI(o.Committer),
+	})
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if len(files) == 1 {
+		return newCommit(c, &resp.Commit), nil
+	}
+
+	rest := make([]gitprovider.CommitFile, 0, len(files)-1)
+	rest = append(rest, files[:first]...)
+	rest = append(rest, files[first+1:]...)
+	return c.Create(ctx, branch, message, rest, &gitprovider.CommitCreateOptions{
+		ExpectedParentSHA: resp.Commit.SHA,
+		Author:            o.Author,
+		Committer:         o.Committer,
+	})
+}
+
+// commitTreeEntries returns the tree entries applying the actions of files to the tree with the
+// given (recursive) entries. Base64-encoded content is uploaded with createBlob, which returns
+// the SHA of the blob, as the content of tree entries must be UTF-8 text.
+func commitTreeEntries(base []*github.TreeEntry, files []gitprovider.CommitFile, createBlob func(content string) (*string, error)) ([]*github.TreeEntry, error) {
+	existing := make(map[string]*github.TreeEntry, len(base))
+	for _, entry := range base {
+		if entry.GetType() == githubBlobTypeFile {
+			existing[entry.GetPath()] = entry
+		}
+	}
+
+	treeEntries := make([]*github.TreeEntry, 0, len(files))
+	for _, file := range files {
+		path := *file.Path
+		old, exists := existing[path]
+		switch file.GetAction() {
+		case gitprovider.CommitFileActionCreate:
+			if exists {
+				return nil, fmt.Errorf("file %q: %w", path, gitprovider.ErrAlreadyExists)
+			}
+		case gitprovider.CommitFileActionUpdate:
+			if !exists {
+				return nil, fmt.Errorf("file %q: %w", path, gitprovider.ErrNotFound)
+			}
+		case gitprovider.CommitFileActionDelete:
+			if !exists {
+				return nil, fmt.Errorf("file %q: %w", path, gitprovider.ErrNotFound)
+			}
+			treeEntries = append(treeEntries, deletedTreeEntry(old))
+			delete(existing, path)
+			continue
+		case gitprovider.CommitFileActionMove:
+			if exists {
+				return nil, fmt.Errorf("file %q: %w", path, gitprovider.ErrAlreadyExists)
+			}
+			if old, exists = existing[*file.PreviousPath]; !exists {
+				return nil, fmt.Errorf("file %q: %w", *file.PreviousPath, gitprovider.ErrNotFound)
+			}
+			treeEntries = append(treeEntries, deletedTreeEntry(old))
+			delete(existing, *file.PreviousPath)
+		}
+
+		entry := &github.TreeEntry{
+			Path: github.String(path),
+			Mode: github.String(githubNewFileMode),
+			Type: github.String(githubBlobTypeFile),
+		}
+		if old != nil {
+			entry.Mode = old.Mode
+		}
+		if file.Mode != nil {
+			entry.Mode = github.String(string(*file.Mode))
+		}
+		switch {
+		case file.Content == nil:
+			// A moved file without new content keeps its blob
+			entry.SHA = old.SHA
+		case file.Encoding != nil && *file.Encoding == gitprovider.ContentEncodingBase64:
+			sha, err := createBlob(*file.Content)
+			if err != nil {
+				return nil, err
+			}
+			entry.SHA = sha
+		default:
+			entry.Content = file.Content
+		}
+		treeEntries = append(treeEntries, entry)
+		existing[path] = entry
+	}
+	return treeEntries, nil
+}
+
+// deletedTreeEntry returns the tree entry removing the given entry from a tree.
+func deletedTreeEntry(entry *github.TreeEntry) *github.TreeEntry {
+	// Without SHA and content, the entry is marshaled with a null SHA, which deletes the file
+	return &github.TreeEntry{
+		Path: entry.Path,
+		Mode: entry.Mode,
+		Type: github.String(githubBlobTypeFile),
+	}
+}
@@ -0,0 +1,323 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-github/v32/github"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+func Test_commitTreeEntries(t *testing.T) {
+	base := []*github.TreeEntry{
+		{Path: github.String("dir"), Mode: github.String("040000"), Type: github.String("tree"), SHA: github.String("dir-sha")},
+		{Path: github.String("dir/run.sh"), Mode: github.String("100755"), Type: github.String("blob"), SHA: github.String("run-sha")},
+		{Path: github.String("README.md"), Mode: github.String("100644"), Type: github.String("blob"), SHA: github.String("readme-sha")},
+	}
+	createBlob := func(content string) (*string, error) {
+		return github.String("blob-" + content), nil
+	}
+	tests := []struct {
+		name    string
+		files   []gitprovider.CommitFile
+		want    []*github.TreeEntry
+		wantErr error
+	}{
+		{
+			name: "update keeps the mode",
+			files: []gitprovider.CommitFile{
+				{Path: gitprovider.StringVar("dir/run.sh"), Content: gitprovider.StringVar("echo")},
+			},
+			want: []*github.TreeEntry{
+				{Path: github.String("dir/run.sh"), Mode: github.String("100755"), Type: github.String("blob"), Content: github.String("echo")},
+			},
+		},
+		{
+			name: "create an executable binary file",
+			files: []gitprovider.CommitFile{{
+				Action:   gitprovider.CommitFileActionVar(gitprovider.CommitFileActionCreate),
+				Path:     gitprovider.StringVar("bin"),
+				Content:  gitprovider.StringVar("AAEC"),
+				Encoding: gitprovider.ContentEncodingVar(gitprovider.ContentEncodingBase64),
+				Mode:     gitprovider.FileModeVar(gitprovider.FileModeExecutable),
+			}},
+			want: []*github.TreeEntry{
+				{Path: github.String("bin"), Mode: github.String("100755"), Type: github.String("blob"), SHA: github.String("blob-AAEC")},
+			},
+		},
+		{
+			name: "move and delete",
+			files: []gitprovider.CommitFile{
+				{Action: gitprovider.CommitFileActionVar(gitprovider.CommitFileActionMove), Path: gitprovider.StringVar("run.sh"), PreviousPath: gitprovider.StringVar("dir/run.sh")},
+				{Path: gitprovider.StringVar("README.md")},
+			},
+			want: []*github.TreeEntry{
+				{Path: github.String("dir/run.sh"), Mode: github.String("100755"), Type: github.String("blob")},
+				{Path: github.String("run.sh"), Mode: github.String("100755"), Type: github.String("blob"), SHA: github.String("run-sha")},
+				{Path: github.String("README.md"), Mode: github.String("100644"), Type: github.String("blob")},
+			},
+		},
+		{
+			name: "create an existing file",
+			files: []gitprovider.CommitFile{
+				{Action: gitprovider.CommitFileActionVar(gitprovider.CommitFileActionCreate), Path: gitprovider.StringVar("README.md"), Content: gitprovider.StringVar("")},
+			},
+			wantErr: gitprovider.ErrAlreadyExists,
+		},
+		{
+			name: "delete a directory",
+			files: []gitprovider.CommitFile{
+				{Path: gitprovider.StringVar("dir")},
+			},
+			wantErr: gitprovider.ErrNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := commitTreeEntries(base, tt.files, createBlob)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("commitTreeEntries() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("commitTreeEntries() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// newTestCommitClient returns a CommitClient for the repository org/repo of a test server, which
+// answers requests using the handlers of routes, keyed by method and path, e.g.
+// "GET /repos/org/repo/branches/main". Other requests fail the test.
+func newTestCommitClient(t *testing.T, routes map[string]http.HandlerFunc) *CommitClient {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler, ok := routes[r.Method+" "+r.URL.Path]
+		if !ok {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotImplemented)
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(srv.Close)
+
+	gh := github.NewClient(nil)
+	gh.BaseURL, _ = url.Parse(srv.URL + "/")
+	return &CommitClient{
+		clientContext: &clientContext{c: &githubClientImpl{c: gh}, domain: githubDomain},
+		ref: gitprovider.OrgRepositoryRef{
+			OrganizationRef: gitprovider.OrganizationRef{Domain: githubDomain, Organization: "org"},
+			RepositoryName:  "repo",
+		},
+	}
+}
+
+// respond returns a handler responding with the given status code and JSON body.
+func respond(statusCode int, body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(statusCode)
+		_, _ = w.Write([]byte(body))
+	}
+}
+
+func TestCommitClient_Create_truncatedTree(t *testing.T) {
+	var created struct {
+		BaseTree string              `json:"base_tree"`
+		Tree     []*github.TreeEntry `json:"tree"`
+	}
+	c := newTestCommitClient(t, map[string]http.HandlerFunc{
+		"GET /repos/org/repo/branches/main": respond(http.StatusOK, `{"name":"main","commit":{"sha":"parent"}}`),
+		"GET /repos/org/repo/git/trees/parent": func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("recursive") != "" {
+				// The recursive tree doesn't contain dir/a.txt, as it's truncated
+				respond(http.StatusOK, `{"sha":"root","truncated":true,"tree":[]}`)(w, r)
+				return
+			}
+			respond(http.StatusOK, `{"sha":"root","tree":[{"path":"dir","mode":"040000","type":"tree","sha":"dir-sha"}]}`)(w, r)
+		},
+		"GET /repos/org/repo/git/trees/parent:dir":     respond(http.StatusOK, `{"sha":"dir-sha","tree":[{"path":"a.txt","mode":"100755","type":"blob","sha":"a-sha"}]}`),
+		"GET /repos/org/repo/git/trees/parent:missing": respond(http.StatusNotFound, `{"message":"Not Found"}`),
+		"POST /repos/org/repo/git/trees": func(w http.ResponseWriter, r *http.Request) {
+			if err := json.NewDecoder(r.Body).Decode(&created); err != nil {
+				t.Error(err)
+			}
+			respond(http.StatusCreated, `{"sha":"new-tree"}`)(w, r)
+		},
+		"POST /repos/org/repo/git/commits":          respond(http.StatusCreated, `{"sha":"new-commit"}`),
+		"PATCH /repos/org/repo/git/refs/heads/main": respond(http.StatusOK, `{"ref":"refs/heads/main","object":{"sha":"new-commit"}}`),
+	})
+	ctx := context.Background()
+
+	_, err := c.Create(ctx, "main", "update", []gitprovider.CommitFile{
+		{Action: gitprovider.CommitFileActionVar(gitprovider.CommitFileActionUpdate), Path: gitprovider.StringVar("dir/a.txt"), Content: gitprovider.StringVar("a")},
+		{Action: gitprovider.CommitFileActionVar(gitprovider.CommitFileActionCreate), Path: gitprovider.StringVar("b.txt"), Content: gitprovider.StringVar("b")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*github.TreeEntry{
+		{Path: github.String("dir/a.txt"), Mode: github.String("100755"), Type: github.String("blob"), Content: github.String("a")},
+		{Path: github.String("b.txt"), Mode: github.String("100644"), Type: github.String("blob"), Content: github.String("b")},
+	}
+	if created.BaseTree != "root" || !reflect.DeepEqual(created.Tree, want) {
+		t.Errorf("created tree on %q with %v, want on root with %v", created.BaseTree, created.Tree, want)
+	}
+
+	// Files in missing directories don't exist
+	_, err = c.Create(ctx, "main", "delete", []gitprovider.CommitFile{{Path: gitprovider.StringVar("missing/c.txt")}})
+	if !errors.Is(err, gitprovider.ErrNotFound) {
+		t.Errorf("Create() deleting a file in a missing directory = %v, want %v", err, gitprovider.ErrNotFound)
+	}
+}
+
+func TestCommitClient_Create_conflict(t *testing.T) {
+	files := []gitprovider.CommitFile{
+		{Path: gitprovider.StringVar("a.txt"), Content: gitprovider.StringVar("a")},
+	}
+	tests := []struct {
+		name string
+		opts []gitprovider.CommitCreateOption
+	}{
+		{
+			name: "branch moved while committing",
+		},
+		{
+			name: "stale expected parent",
+			opts: []gitprovider.CommitCreateOption{
+				&gitprovider.CommitCreateOptions{ExpectedParentSHA: gitprovider.StringVar("stale")},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestCommitClient(t, map[string]http.HandlerFunc{
+				"GET /repos/org/repo/branches/main":         respond(http.StatusOK, `{"name":"main","commit":{"sha":"parent"}}`),
+				"GET /repos/org/repo/git/trees/parent":      respond(http.StatusOK, `{"sha":"root","tree":[{"path":"a.txt","mode":"100644","type":"blob","sha":"a-sha"}]}`),
+				"POST /repos/org/repo/git/trees":            respond(http.StatusCreated, `{"sha":"new-tree"}`),
+				"POST /repos/org/repo/git/commits":          respond(http.StatusCreated, `{"sha":"new-commit"}`),
+				"PATCH /repos/org/repo/git/refs/heads/main": respond(http.StatusUnprocessableEntity, `{"message":"Update is not a fast forward"}`),
+			})
+			_, err := c.Create(context.Background(), "main", "update", files, tt.opts...)
+			if !errors.Is(err, gitprovider.ErrConflict) {
+				t.Errorf("Create() = %v, want %v", err, gitprovider.ErrConflict)
+			}
+		})
+	}
+}
+
+func TestCommitClient_Create_emptyRepository(t *testing.T) {
+	var first bool
+	var created struct {
+		BaseTree string              `json:"base_tree"`
+		Tree     []*github.TreeEntry `json:"tree"`
+	}
+	c := newTestCommitClient(t, map[string]http.HandlerFunc{
+		"GET /repos/org/repo/branches/main": func(w http.ResponseWriter, r *http.Request) {
+			if !first {
+				respond(http.StatusNotFound, `{"message":"Branch not found"}`)(w, r)
+				return
+			}
+			respond(http.StatusOK, `{"name":"main","commit":{"sha":"first"}}`)(w, r)
+		},
+		"GET /repos/org/repo/branches": respond(http.StatusOK, `[]`),
+		// The git database API doesn't work until the first commit
+		"GET /repos/org/repo/git/trees/first": func(w http.ResponseWriter, r *http.Request) {
+			if !first {
+				respond(http.StatusConflict, `{"message":"Git Repository is empty."}`)(w, r)
+				return
+			}
+			respond(http.StatusOK, `{"sha":"first-tree","tree":[{"path":"a.txt","mode":"100644","type":"blob","sha":"a-sha"}]}`)(w, r)
+		},
+		"PUT /repos/org/repo/contents/a.txt": func(w http.ResponseWriter, r *http.Request) {
+			first = true
+			respond(http.StatusCreated, `{"content":{"path":"a.txt"},"commit":{"sha":"first"}}`)(w, r)
+		},
+		"POST /repos/org/repo/git/trees": func(w http.ResponseWriter, r *http.Request) {
+			if err := json.NewDecoder(r.Body).Decode(&created); err != nil {
+				t.Error(err)
+			}
+			respond(http.StatusCreated, `{"sha":"second-tree"}`)(w, r)
+		},
+		"POST /repos/org/repo/git/commits":          respond(http.StatusCreated, `{"sha":"second","tree":{"sha":"second-tree"}}`),
+		"PATCH /repos/org/repo/git/refs/heads/main": respond(http.StatusOK, `{"ref":"refs/heads/main","object":{"sha":"second"}}`),
+	})
+
+	commit, err := c.Create(context.Background(), "main", "initial", []gitprovider.CommitFile{
+		{Path: gitprovider.StringVar("a.txt"), Content: gitprovider.StringVar("a")},
+		{Path: gitprovider.StringVar("b.txt"), Content: gitprovider.StringVar("b")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if commit.Get().Sha != "second" {
+		t.Errorf("Create() = %q, want second", commit.Get().Sha)
+	}
+	want := []*github.TreeEntry{
+		{Path: github.String("b.txt"), Mode: github.String("100644"), Type: github.String("blob"), Content: github.String("b")},
+	}
+	if created.BaseTree != "first-tree" || !reflect.DeepEqual(created.Tree, want) {
+		t.Errorf("created tree on %q with %v, want on first-tree with %v", created.BaseTree, created.Tree, want)
+	}
+}
+
+func TestCommitClient_Create_startBranch(t *testing.T) {
+	files := []gitprovider.CommitFile{
+		{Path: gitprovider.StringVar("a.txt"), Content: gitprovider.StringVar("a")},
+	}
+	var createdRef struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	}
+	c := newTestCommitClient(t, map[string]http.HandlerFunc{
+		"GET /repos/org/repo/branches/feature": respond(http.StatusNotFound, `{"message":"Branch not found"}`),
+		"GET /repos/org/repo/branches/unknown": respond(http.StatusNotFound, `{"message":"Branch not found"}`),
+		"GET /repos/org/repo/branches/main":    respond(http.StatusOK, `{"name":"main","commit":{"sha":"parent"}}`),
+		"GET /repos/org/repo/git/trees/parent": respond(http.StatusOK, `{"sha":"root","tree":[]}`),
+		"POST /repos/org/repo/git/trees":       respond(http.StatusCreated, `{"sha":"new-tree"}`),
+		"POST /repos/org/repo/git/commits":     respond(http.StatusCreated, `{"sha":"new-commit","parents":[{"sha":"parent"}]}`),
+		"POST /repos/org/repo/git/refs": func(w http.ResponseWriter, r *http.Request) {
+			if err := json.NewDecoder(r.Body).Decode(&createdRef); err != nil {
+				t.Error(err)
+			}
+			respond(http.StatusCreated, `{"ref":"refs/heads/feature","object":{"sha":"new-commit"}}`)(w, r)
+		},
+	})
+	ctx := context.Background()
+
+	_, err := c.Create(ctx, "feature", "add", files, &gitprovider.CommitCreateOptions{StartBranch: gitprovider.StringVar("main")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if createdRef.Ref != "refs/heads/feature" || createdRef.SHA != "new-commit" {
+		t.Errorf("created ref %q at %q, want refs/heads/feature at new-commit", createdRef.Ref, createdRef.SHA)
+	}
+
+	_, err = c.Create(ctx, "feature", "add", files, &gitprovider.CommitCreateOptions{StartBranch: gitprovider.StringVar("unknown")})
+	if !errors.Is(err, gitprovider.ErrNotFound) {
+		t.Errorf("Create() from an unknown start branch = %v, want %v", err, gitprovider.ErrNotFound)
+	}
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// CommitStatusClient implements the gitprovider.CommitStatusClient interface.
+var _ gitprovider.CommitStatusClient = &CommitStatusClient{}
+
+// CommitStatusClient operates on the statuses of the commits of a specific repository.
+type CommitStatusClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// Get returns the combined status of the commit with the given sha, i.e. the latest status of
+// each context.
+//
+// Get uses multiple paginated requests if needed.
+func (c *CommitStatusClient) Get(ctx context.Context, sha string) (gitprovider.CombinedCommitStatus, error) {
+	// GET /repos/{owner}/{repo}/commits/{ref}/status
+	apiObj, err := c.c.GetCombinedStatus(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), sha)
+	if err != nil {
+		return nil, err
+	}
+	return newCombinedCommitStatus(apiObj), nil
+}
+
+// List lists all statuses of the commit with the given sha, newest first, including the
+// statuses replaced by newer ones with the same context.
+//
+// List returns all available statuses, using multiple paginated requests if needed.
+func (c *CommitStatusClient) List(ctx context.Context, sha string) ([]gitprovider.CommitStatus, error) {
+	// GET /repos/{owner}/{repo}/commits/{ref}/statuses
+	apiObjs, err := c.c.ListStatuses(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), sha)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]gitprovider.CommitStatus, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		statuses = append(statuses, newCommitStatus(apiObj))
+	}
+	return statuses, nil
+}
+
+// Create creates a status for the commit with the given sha, which replaces the status with
+// the same req.Context in the combined status of the commit.
+//
+// ErrNotFound is returned if the commit doesn't exist.
+func (c *CommitStatusClient) Create(ctx context.Context, sha string, req gitprovider.CommitStatusInfo) (gitprovider.CommitStatus, error) {
+	// Default and validate the request
+	req.Default()
+	if err := req.ValidateInfo(); err != nil {
+		return nil, err
+	}
+
+	// POST /repos/{owner}/{repo}/statuses/{sha}
+	apiObj, err := c.c.CreateStatus(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), sha, commitStatusToAPI(&req))
+	if err != nil {
+		return nil, err
+	}
+	return newCommitStatus(apiObj), nil
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/google/go-github/v32/github"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// FileClient implements the gitprovider.FileClient interface.
+var _ gitprovider.FileClient = &FileClient{}
+
+// FileClient reads the files of a specific repository.
+//
+// Files are read through the git trees and blobs APIs, as the contents API neither reports
+// file modes nor returns the content of files larger than 1 MB.
+type FileClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// Get returns the file at path as of ref, i.e. a branch, tag or commit sha, including its
+// content. Files up to the 100 MB limit of the blobs API are supported.
+//
+// ErrNotFound is returned if ref doesn't exist, or if path isn't a file as of ref.
+func (c *FileClient) Get(ctx context.Context, filePath, ref string) (gitprovider.File, error) {
+	dirPath, name := path.Split(strings.Trim(filePath, "/"))
+	// GET /repos/{owner}/{repo}/git/trees/{tree_sha}
+	entries, truncated, err := c.list(ctx, dirPath, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if path.Base(entry.GetPath()) != name || entry.GetType() != githubBlobTypeFile {
+			continue
+		}
+		// GET /repos/{owner}/{repo}/git/blobs/{file_sha}
+		content, err := c.c.GetBlobRaw(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), entry.GetSHA())
+		if err != nil {
+			return nil, err
+		}
+		entry.Content = github.String(string(content))
+		return newFile(entry), nil
+	}
+	if truncated {
+		// The file might be among the entries which weren't returned
+		return nil, fmt.Errorf("file %q: %w", filePath, errTruncatedTree(dirPath))
+	}
+	return nil, fmt.Errorf("file %q: %w", filePath, gitprovider.ErrNotFound)
+}
+
+// List lists the files and directories in the directory at dirPath as of ref.
+//
+// ErrNotFound is returned if ref doesn't exist, or if dirPath isn't a directory as of ref.
+func (c *FileClient) List(ctx context.Context, dirPath, ref string) ([]gitprovider.File, error) {
+	// GET /repos/{owner}/{repo}/git/trees/{tree_sha}
+	entries, truncated, err := c.list(ctx, dirPath, ref)
+	if err != nil {
+		return nil, err
+	}
+	if truncated {
+		return nil, errTruncatedTree(dirPath)
+	}
+
+	files := make([]gitprovider.File, 0, len(entries))
+	for _, entry := range entries {
+		files = append(files, newFile(entry))
+	}
+	return files, nil
+}
+
+// list returns the entries of the tree of the directory at dirPath as of ref, with their paths
+// relative to the root of the repository. truncated is true if the directory has more entries
+// than the git trees API returns at once, in which case only some of them are returned.
+func (c *FileClient) list(ctx context.Context, dirPath, ref string) (entries []*github.TreeEntry, truncated bool, err error) {
+	dirPath = strings.Trim(dirPath, "/")
+	treeish := ref
+	if len(dirPath) != 0 {
+		treeish = ref + ":" + dirPath
+	}
+	// GET /repos/{owner}/{repo}/git/trees/{tree_sha}
+	// A missing ref or directory is a 404, which GetTree maps to ErrNotFound through handleHTTPError
+	tree, err := c.c.GetTree(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), treeish)
+	if err != nil {
+		return nil, false, fmt.Errorf("directory %q as of %q: %w", dirPath, ref, err)
+	}
+	for _, entry := range tree.Entries {
+		entry.Path = github.String(path.Join(dirPath, entry.GetPath()))
+	}
+	return tree.Entries, tree.GetTruncated(), nil
+}
+
+// errTruncatedTree returns the error for a directory with more entries than the git trees API
+// returns at once.
+func errTruncatedTree(dirPath string) error {
+	return fmt.Errorf("directory %q has too many entries to be listed: %w", dirPath, gitprovider.ErrInvalidServerData)
+}
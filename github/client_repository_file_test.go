@@ -0,0 +1,64 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+func TestFileClient(t *testing.T) {
+	cc := newTestCommitClient(t, map[string]http.HandlerFunc{
+		"GET /repos/org/repo/git/trees/main:dir":       respond(http.StatusOK, `{"sha":"dir-sha","tree":[{"path":"a.txt","mode":"100644","type":"blob","sha":"a-sha","size":1}]}`),
+		"GET /repos/org/repo/git/blobs/a-sha":          respond(http.StatusOK, `a`),
+		"GET /repos/org/repo/git/trees/main:missing":   respond(http.StatusNotFound, `{"message":"Not Found"}`),
+		"GET /repos/org/repo/git/trees/main:truncated": respond(http.StatusOK, `{"sha":"big-sha","truncated":true,"tree":[{"path":"b.txt","mode":"100644","type":"blob","sha":"b-sha"}]}`),
+	})
+	c := &FileClient{clientContext: cc.clientContext, ref: cc.ref}
+	ctx := context.Background()
+
+	file, err := c.Get(ctx, "dir/a.txt", "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.Get().Path != "dir/a.txt" || string(file.Get().Content) != "a" {
+		t.Errorf("Get() = %+v, want dir/a.txt with content a", file.Get())
+	}
+	if _, err := c.Get(ctx, "dir/b.txt", "main"); !errors.Is(err, gitprovider.ErrNotFound) {
+		t.Errorf("Get() of a missing file = %v, want %v", err, gitprovider.ErrNotFound)
+	}
+
+	// Missing directories map to ErrNotFound
+	if _, err := c.List(ctx, "missing", "main"); !errors.Is(err, gitprovider.ErrNotFound) {
+		t.Errorf("List() of a missing directory = %v, want %v", err, gitprovider.ErrNotFound)
+	}
+	if _, err := c.Get(ctx, "missing/a.txt", "main"); !errors.Is(err, gitprovider.ErrNotFound) {
+		t.Errorf("Get() in a missing directory = %v, want %v", err, gitprovider.ErrNotFound)
+	}
+
+	// Truncated directories can't be listed, nor can files missing from them be told apart
+	if _, err := c.List(ctx, "truncated", "main"); !errors.Is(err, gitprovider.ErrInvalidServerData) {
+		t.Errorf("List() of a truncated directory = %v, want %v", err, gitprovider.ErrInvalidServerData)
+	}
+	if _, err := c.Get(ctx, "truncated/c.txt", "main"); errors.Is(err, gitprovider.ErrNotFound) || err == nil {
+		t.Errorf("Get() of a file missing from a truncated directory = %v, want an error other than %v", err, gitprovider.ErrNotFound)
+	}
+}
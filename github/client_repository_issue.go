@@ -0,0 +1,186 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/go-github/v32/github"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// IssueClient implements the gitprovider.IssueClient interface.
+var _ gitprovider.IssueClient = &IssueClient{}
+
+// IssueClient operates on the issues for a specific repository.
+type IssueClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// Get returns the issue with the given number.
+//
+// ErrNotFound is returned if the resource does not exist, also if the number is the one of a
+// pull request.
+func (c *IssueClient) Get(ctx context.Context, number int) (gitprovider.Issue, error) {
+	// GET /repos/{owner}/{repo}/issues/{issue_number}
+	apiObj, err := c.c.GetIssue(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), number)
+	if err != nil {
+		return nil, err
+	}
+	// Pull requests are issues too in GitHub, but not in the other providers
+	if apiObj.IsPullRequest() {
+		return nil, fmt.Errorf("issue %d is a pull request: %w", number, gitprovider.ErrNotFound)
+	}
+	return newIssue(c.clientContext, apiObj, c.ref), nil
+}
+
+// List lists the issues of the repository matching the given filters. Pull requests are never
+// listed.
+//
+// List returns all available issues, using multiple paginated requests if needed.
+func (c *IssueClient) List(ctx context.Context, opts ...gitprovider.IssueListOption) ([]gitprovider.Issue, error) {
+	o, err := gitprovider.MakeIssueListOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	listOpts := &github.IssueListByRepoOptions{State: "all", Labels: o.Labels}
+	if o.State != nil {
+		listOpts.State = string(*o.State)
+	}
+	if o.Assignee != nil {
+		listOpts.Assignee = *o.Assignee
+	}
+
+	// GET /repos/{owner}/{repo}/issues
+	apiObjs, err := c.c.ListIssues(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), listOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	issues := make([]gitprovider.Issue, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		if apiObj.IsPullRequest() {
+			continue
+		}
+		issues = append(issues, newIssue(c.clientContext, apiObj, c.ref))
+	}
+	return issues, nil
+}
+
+// Create creates an open issue with the given specifications. req.State is ignored.
+func (c *IssueClient) Create(ctx context.Context, req gitprovider.IssueInfo) (gitprovider.Issue, error) {
+	// Default and validate the request
+	req.Default()
+	if err := req.ValidateInfo(); err != nil {
+		return nil, err
+	}
+
+	issueReq := &github.IssueRequest{
+		Title: &req.Title,
+		Body:  &req.Body,
+	}
+	if len(req.Labels) != 0 {
+		issueReq.Labels = &req.Labels
+	}
+	if len(req.Assignees) != 0 {
+		issueReq.Assignees = &req.Assignees
+	}
+	// POST /repos/{owner}/{repo}/issues
+	apiObj, err := c.c.CreateIssue(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), issueReq)
+	if err != nil {
+		return nil, err
+	}
+	return newIssue(c.clientContext, apiObj, c.ref), nil
+}
+
+// Update changes the title, body, labels and/or assignees of the issue with the given number.
+// nil fields of opts are left unchanged.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *IssueClient) Update(ctx context.Context, number int, opts gitprovider.IssueUpdateOptions) (gitprovider.Issue, error) {
+	req := &github.IssueRequest{
+		Title: opts.Title,
+		Body:  opts.Body,
+	}
+	// Empty, non-nil slices are sent, to remove all labels or assignees
+	if opts.Labels != nil {
+		req.Labels = &opts.Labels
+	}
+	if opts.Assignees != nil {
+		req.Assignees = &opts.Assignees
+	}
+	return c.edit(ctx, number, req)
+}
+
+// Close closes the issue with the given number.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *IssueClient) Close(ctx context.Context, number int) (gitprovider.Issue, error) {
+	return c.edit(ctx, number, &github.IssueRequest{State: github.String("closed")})
+}
+
+// Reopen reopens the closed issue with the given number.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *IssueClient) Reopen(ctx context.Context, number int) (gitprovider.Issue, error) {
+	return c.edit(ctx, number, &github.IssueRequest{State: github.String("open")})
+}
+
+// AddLabels adds the given labels to the issue with the given number, keeping the existing
+// ones. Missing labels are created by GitHub.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *IssueClient) AddLabels(ctx context.Context, number int, labels []string) (gitprovider.Issue, error) {
+	if len(labels) != 0 {
+		// POST /repos/{owner}/{repo}/issues/{issue_number}/labels
+		if err := c.c.AddIssueLabels(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), number, labels); err != nil {
+			return nil, err
+		}
+	}
+	return c.Get(ctx, number)
+}
+
+// RemoveLabels removes the given labels from the issue with the given number. Labels the
+// issue doesn't have are ignored.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *IssueClient) RemoveLabels(ctx context.Context, number int, labels []string) (gitprovider.Issue, error) {
+	for _, label := range labels {
+		// GitHub returns "404 Not Found" for labels the issue doesn't have. If the issue
+		// doesn't exist, the Get below returns ErrNotFound.
+		// DELETE /repos/{owner}/{repo}/issues/{issue_number}/labels/{name}
+		err := c.c.RemoveIssueLabel(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), number, label)
+		if err != nil && !errors.Is(err, gitprovider.ErrNotFound) {
+			return nil, err
+		}
+	}
+	return c.Get(ctx, number)
+}
+
+func (c *IssueClient) edit(ctx context.Context, number int, req *github.IssueRequest) (gitprovider.Issue, error) {
+	// PATCH /repos/{owner}/{repo}/issues/{issue_number}
+	apiObj, err := c.c.EditIssue(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), number, req)
+	if err != nil {
+		return nil, err
+	}
+	return newIssue(c.clientContext, apiObj, c.ref), nil
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// IssueCommentClient implements the gitprovider.IssueCommentClient interface.
+var _ gitprovider.IssueCommentClient = &IssueCommentClient{}
+
+// IssueCommentClient operates on the comments of a specific issue. Issues and pull requests
+// share the comments API of GitHub.
+type IssueCommentClient struct {
+	*clientContext
+	ref    gitprovider.RepositoryRef
+	number int
+}
+
+// List all comments of the issue, oldest first.
+//
+// List returns all available comments, using multiple paginated requests if needed.
+func (c *IssueCommentClient) List(ctx context.Context) ([]gitprovider.IssueComment, error) {
+	// GET /repos/{owner}/{repo}/issues/{issue_number}/comments
+	apiObjs, err := c.c.ListPullRequestComments(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), c.number)
+	if err != nil {
+		return nil, err
+	}
+
+	comments := make([]gitprovider.IssueComment, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		comments = append(comments, newIssueComment(apiObj))
+	}
+	return comments, nil
+}
+
+// Create adds a comment with the given body to the issue.
+func (c *IssueCommentClient) Create(ctx context.Context, body string) (gitprovider.IssueComment, error) {
+	// POST /repos/{owner}/{repo}/issues/{issue_number}/comments
+	apiObj, err := c.c.CreatePullRequestComment(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), c.number, body)
+	if err != nil {
+		return nil, err
+	}
+	return newIssueComment(apiObj), nil
+}
+
+// Update changes the body of the comment with the given ID.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *IssueCommentClient) Update(ctx context.Context, id int64, body string) (gitprovider.IssueComment, error) {
+	// PATCH /repos/{owner}/{repo}/issues/comments/{comment_id}
+	apiObj, err := c.c.EditPullRequestComment(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), id, body)
+	if err != nil {
+		return nil, err
+	}
+	return newIssueComment(apiObj), nil
+}
+
+// Delete deletes the comment with the given ID.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *IssueCommentClient) Delete(ctx context.Context, id int64) error {
+	// DELETE /repos/{owner}/{repo}/issues/comments/{comment_id}
+	return c.c.DeletePullRequestComment(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), id)
+}
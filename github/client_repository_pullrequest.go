@@ -18,9 +18,11 @@ package github
 
 import (
 	"context"
+	"fmt"
 
-	"github.com/fluxcd/go-git-providers/gitprovider"
 	"github.com/google/go-github/v32/github"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
 )
 
 // PullRequestClient implements the gitprovider.PullRequestClient interface.
@@ -32,20 +34,171 @@ type PullRequestClient struct {
 	ref gitprovider.RepositoryRef
 }
 
+// Get returns the pull request with the given number.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *PullRequestClient) Get(ctx context.Context, number int) (gitprovider.PullRequest, error) {
+	// GET /repos/{owner}/{repo}/pulls/{pull_number}
+	apiObj, err := c.c.GetPullRequest(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), number)
+	if err != nil {
+		return nil, err
+	}
+	return newPullRequest(c.clientContext, apiObj, c.ref), nil
+}
+
+// List lists the pull requests of the repository matching the given filters.
+//
+// List returns all available pull requests, using multiple paginated requests if needed.
+func (c *PullRequestClient) List(ctx context.Context, opts ...gitprovider.PullRequestListOption) ([]gitprovider.PullRequest, error) {
+	o, err := gitprovider.MakePullRequestListOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// GitHub only knows open and closed pull requests, merged ones are filtered below
+	listOpts := &github.PullRequestListOptions{State: "all"}
+	if o.State != nil {
+		listOpts.State = "closed"
+		if *o.State == gitprovider.PullRequestStateOpen {
+			listOpts.State = "open"
+		}
+	}
+	if o.HeadBranch != nil {
+		// The head filter requires the "user:ref-name" format
+		listOpts.Head = fmt.Sprintf("%s:%s", c.ref.GetIdentity(), *o.HeadBranch)
+	}
+	if o.BaseBranch != nil {
+		listOpts.Base = *o.BaseBranch
+	}
+
+	// GET /repos/{owner}/{repo}/pulls
+	apiObjs, err := c.c.ListPullRequests(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), listOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	prs := make([]gitprovider.PullRequest, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		pr := newPullRequest(c.clientContext, apiObj, c.ref)
+		if o.Matches(pr.Get()) {
+			prs = append(prs, pr)
+		}
+	}
+	return prs, nil
+}
+
 // Create creates a pull request with the given specifications.
 func (c *PullRequestClient) Create(ctx context.Context, title, branch, baseBranch, description string) (gitprovider.PullRequest, error) {
-
 	prOpts := &github.NewPullRequest{
 		Title: &title,
 		Head:  &branch,
 		Base:  &baseBranch,
 		Body:  &description,
 	}
-
-	pr, _, err := c.c.Client().PullRequests.Create(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), prOpts)
+	// POST /repos/{owner}/{repo}/pulls
+	pr, err := c.c.CreatePullRequest(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), prOpts)
 	if err != nil {
 		return nil, err
 	}
+	return newPullRequest(c.clientContext, pr, c.ref), nil
+}
 
-	return newPullRequest(c.clientContext, pr), nil
+// Update changes the title, description, base branch and/or labels of the pull request
+// with the given number. nil fields of opts are left unchanged.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *PullRequestClient) Update(ctx context.Context, number int, opts gitprovider.PullRequestUpdateOptions) (gitprovider.PullRequest, error) {
+	if opts.Labels != nil {
+		// Pull requests are issues too, and labels are only set through the issues API. This
+		// is done before the edit, so that the returned pull request contains the new labels.
+		// PUT /repos/{owner}/{repo}/issues/{issue_number}/labels
+		if err := c.c.ReplacePullRequestLabels(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), number, opts.Labels); err != nil {
+			return nil, err
+		}
+	}
+
+	req := &github.PullRequest{
+		Title: opts.Title,
+		Body:  opts.Description,
+	}
+	if opts.BaseBranch != nil {
+		req.Base = &github.PullRequestBranch{Ref: opts.BaseBranch}
+	}
+	return c.edit(ctx, number, req)
+}
+
+// Merge merges the pull request with the given number, using the given method. message is
+// the message of the merge or squash commit; an empty message uses the GitHub default.
+func (c *PullRequestClient) Merge(ctx context.Context, number int, method gitprovider.MergeMethod, message string) error {
+	if err := gitprovider.ValidateMergeMethod(method); err != nil {
+		return fmt.Errorf("merge method %q: %w", method, gitprovider.ErrInvalidArgument)
+	}
+	// PUT /repos/{owner}/{repo}/pulls/{pull_number}/merge
+	return c.c.MergePullRequest(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), number, string(method), message)
+}
+
+// Close closes the pull request with the given number, without merging it.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *PullRequestClient) Close(ctx context.Context, number int) (gitprovider.PullRequest, error) {
+	return c.edit(ctx, number, &github.PullRequest{State: github.String("closed")})
+}
+
+// Reconcile makes sure the given desired state (req) becomes the actual state in the backing Git provider.
+// The pull request is looked up by req.HeadBranch and req.BaseBranch among the open pull
+// requests; the title, description and labels of req are reconciled.
+//
+// If req doesn't exist under the hood, it is created (actionTaken == true).
+// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+// If req is already the actual state, this is a no-op (actionTaken == false).
+func (c *PullRequestClient) Reconcile(ctx context.Context, req gitprovider.PullRequestInfo) (gitprovider.PullRequest, bool, error) {
+	// First thing, validate the request
+	if err := req.ValidateInfo(); err != nil {
+		return nil, false, err
+	}
+
+	// Look for an open pull request from the head into the base branch
+	prs, err := c.List(ctx, &gitprovider.PullRequestListOptions{
+		State:      gitprovider.PullRequestStateVar(gitprovider.PullRequestStateOpen),
+		HeadBranch: &req.HeadBranch,
+		BaseBranch: &req.BaseBranch,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Create if not found
+	if len(prs) == 0 {
+		resp, err := c.Create(ctx, req.Title, req.HeadBranch, req.BaseBranch, req.Description)
+		if err != nil || len(req.Labels) == 0 {
+			return resp, true, err
+		}
+		// Labels can't be given at creation time, so set them afterwards
+		resp, err = c.Update(ctx, resp.Get().Number, gitprovider.PullRequestUpdateOptions{Labels: req.Labels})
+		return resp, true, err
+	}
+
+	// If the desired matches the actual state, just return the actual state
+	actual := prs[0]
+	if req.Equals(actual.Get()) {
+		return actual, false, nil
+	}
+
+	// Apply the desired state by running Update
+	resp, err := c.Update(ctx, actual.Get().Number, gitprovider.PullRequestUpdateOptions{
+		Title:       &req.Title,
+		Description: &req.Description,
+		// Use a non-nil slice, so that labels are removed if none are desired
+		Labels: append([]string{}, req.Labels...),
+	})
+	return resp, true, err
+}
+
+func (c *PullRequestClient) edit(ctx context.Context, number int, req *github.PullRequest) (gitprovider.PullRequest, error) {
+	// PATCH /repos/{owner}/{repo}/pulls/{pull_number}
+	apiObj, err := c.c.EditPullRequest(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), number, req)
+	if err != nil {
+		return nil, err
+	}
+	return newPullRequest(c.clientContext, apiObj, c.ref), nil
 }
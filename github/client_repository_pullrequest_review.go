@@ -0,0 +1,90 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/go-github/v32/github"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// PullRequestReviewClient implements the gitprovider.PullRequestReviewClient interface.
+var _ gitprovider.PullRequestReviewClient = &PullRequestReviewClient{}
+
+// PullRequestReviewClient operates on the reviews of a specific pull request.
+type PullRequestReviewClient struct {
+	*clientContext
+	ref    gitprovider.RepositoryRef
+	number int
+}
+
+// List all submitted reviews of the pull request, oldest first. Pending reviews, which are
+// only visible to their author, are left out.
+//
+// List returns all available reviews, using multiple paginated requests if needed.
+func (c *PullRequestReviewClient) List(ctx context.Context) ([]gitprovider.PullRequestReview, error) {
+	// GET /repos/{owner}/{repo}/pulls/{pull_number}/reviews
+	apiObjs, err := c.c.ListPullRequestReviews(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), c.number)
+	if err != nil {
+		return nil, err
+	}
+
+	reviews := make([]gitprovider.PullRequestReview, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		if apiObj.GetState() == reviewStatePending {
+			continue
+		}
+		reviews = append(reviews, newPullRequestReview(apiObj))
+	}
+	return reviews, nil
+}
+
+// Submit submits a review with the given verdict and body, as the authenticated user.
+// body may be empty when approving.
+func (c *PullRequestReviewClient) Submit(ctx context.Context, state gitprovider.PullRequestReviewState, body string) (gitprovider.PullRequestReview, error) {
+	event, err := reviewEventToAPI(state)
+	if err != nil {
+		return nil, err
+	}
+	req := &github.PullRequestReviewRequest{Event: &event}
+	if body != "" {
+		req.Body = &body
+	}
+	// POST /repos/{owner}/{repo}/pulls/{pull_number}/reviews
+	apiObj, err := c.c.CreatePullRequestReview(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), c.number, req)
+	if err != nil {
+		return nil, err
+	}
+	return newPullRequestReview(apiObj), nil
+}
+
+// reviewEventToAPI maps a PullRequestReviewState to the event submitting a review.
+func reviewEventToAPI(state gitprovider.PullRequestReviewState) (string, error) {
+	switch state {
+	case gitprovider.PullRequestReviewStateApproved:
+		return "APPROVE", nil
+	case gitprovider.PullRequestReviewStateChangesRequested:
+		return "REQUEST_CHANGES", nil
+	case gitprovider.PullRequestReviewStateCommented:
+		return "COMMENT", nil
+	default:
+		return "", fmt.Errorf("review state %q: %w", state, gitprovider.ErrInvalidArgument)
+	}
+}
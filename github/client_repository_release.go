@@ -0,0 +1,166 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/go-github/v32/github"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// ReleaseClient implements the gitprovider.ReleaseClient interface.
+var _ gitprovider.ReleaseClient = &ReleaseClient{}
+
+// ReleaseClient operates on the releases for a specific repository.
+type ReleaseClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// Get returns the release of the tag with the given name, including drafts.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *ReleaseClient) Get(ctx context.Context, tagName string) (gitprovider.Release, error) {
+	apiObj, err := c.getByTag(ctx, tagName)
+	if err != nil {
+		return nil, err
+	}
+	return newRelease(c, apiObj), nil
+}
+
+// List lists all releases of the repository, including drafts.
+//
+// List returns all available releases, using multiple paginated requests if needed.
+func (c *ReleaseClient) List(ctx context.Context) ([]gitprovider.Release, error) {
+	// GET /repos/{owner}/{repo}/releases
+	apiObjs, err := c.c.ListReleases(ctx, c.ref.GetIdentity(), c.ref.GetRepository())
+	if err != nil {
+		return nil, err
+	}
+
+	releases := make([]gitprovider.Release, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		releases = append(releases, newRelease(c, apiObj))
+	}
+	return releases, nil
+}
+
+// Create creates a release with the given specifications. The tag req.TagName is created
+// from req.TargetCommitish if it doesn't exist.
+//
+// ErrAlreadyExists is returned if the tag already has a release.
+func (c *ReleaseClient) Create(ctx context.Context, req gitprovider.ReleaseInfo) (gitprovider.Release, error) {
+	// First thing, validate and default the request to ensure a valid and fully-populated object
+	// (to minimize any possible diffs between desired and actual state)
+	if err := gitprovider.ValidateAndDefaultInfo(&req); err != nil {
+		return nil, err
+	}
+	// POST /repos/{owner}/{repo}/releases
+	apiObj, err := c.c.CreateRelease(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), releaseToAPI(&req))
+	if err != nil {
+		return nil, err
+	}
+	return newRelease(c, apiObj), nil
+}
+
+// Update changes the name, body, and draft and prerelease state of the release of the tag
+// req.TagName to the ones of req.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *ReleaseClient) Update(ctx context.Context, req gitprovider.ReleaseInfo) (gitprovider.Release, error) {
+	if err := gitprovider.ValidateAndDefaultInfo(&req); err != nil {
+		return nil, err
+	}
+	actual, err := c.getByTag(ctx, req.TagName)
+	if err != nil {
+		return nil, err
+	}
+	// PATCH /repos/{owner}/{repo}/releases/{release_id}
+	apiObj, err := c.c.EditRelease(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), actual.GetID(), releaseToAPI(&req))
+	if err != nil {
+		return nil, err
+	}
+	return newRelease(c, apiObj), nil
+}
+
+// Delete deletes the release of the tag with the given name, including its assets. The tag
+// itself is kept.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *ReleaseClient) Delete(ctx context.Context, tagName string) error {
+	actual, err := c.getByTag(ctx, tagName)
+	if err != nil {
+		return err
+	}
+	// DELETE /repos/{owner}/{repo}/releases/{release_id}
+	return c.c.DeleteRelease(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), actual.GetID())
+}
+
+// Reconcile makes sure the given desired state (req) becomes the actual state in the backing Git provider.
+// The release is looked up by req.TagName.
+//
+// If req doesn't exist under the hood, it is created (actionTaken == true).
+// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+// If req is already the actual state, this is a no-op (actionTaken == false).
+func (c *ReleaseClient) Reconcile(ctx context.Context, req gitprovider.ReleaseInfo) (gitprovider.Release, bool, error) {
+	// First thing, validate and default the request to ensure a valid and fully-populated object
+	// (to minimize any possible diffs between desired and actual state)
+	if err := gitprovider.ValidateAndDefaultInfo(&req); err != nil {
+		return nil, false, err
+	}
+
+	// Get the release of the desired tag
+	actual, err := c.Get(ctx, req.TagName)
+	if err != nil {
+		// Create if not found
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			resp, err := c.Create(ctx, req)
+			return resp, true, err
+		}
+
+		// Unexpected path, Get should succeed or return NotFound
+		return nil, false, err
+	}
+
+	// If the desired matches the actual state, just return the actual state
+	if req.Equals(actual.Get()) {
+		return actual, false, nil
+	}
+
+	// Apply the desired state by running Update
+	resp, err := c.Update(ctx, req)
+	return resp, true, err
+}
+
+// getByTag returns the release of the given tag. "GET /repos/{owner}/{repo}/releases/tags/{tag}"
+// only returns published releases, so look through all releases to also find drafts.
+func (c *ReleaseClient) getByTag(ctx context.Context, tagName string) (*github.RepositoryRelease, error) {
+	// GET /repos/{owner}/{repo}/releases
+	apiObjs, err := c.c.ListReleases(ctx, c.ref.GetIdentity(), c.ref.GetRepository())
+	if err != nil {
+		return nil, err
+	}
+	for _, apiObj := range apiObjs {
+		if apiObj.GetTagName() == tagName {
+			return apiObj, nil
+		}
+	}
+	return nil, gitprovider.ErrNotFound
+}
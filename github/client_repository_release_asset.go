@@ -0,0 +1,105 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+
+	"github.com/google/go-github/v32/github"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// ReleaseAssetClient implements the gitprovider.ReleaseAssetClient interface.
+var _ gitprovider.ReleaseAssetClient = &ReleaseAssetClient{}
+
+// ReleaseAssetClient operates on the assets of a specific release.
+type ReleaseAssetClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+	id  int64
+}
+
+// List lists all assets of the release.
+//
+// List returns all available assets, using multiple paginated requests if needed.
+func (c *ReleaseAssetClient) List(ctx context.Context) ([]gitprovider.ReleaseAsset, error) {
+	// GET /repos/{owner}/{repo}/releases/{release_id}/assets
+	apiObjs, err := c.c.ListReleaseAssets(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), c.id)
+	if err != nil {
+		return nil, err
+	}
+
+	assets := make([]gitprovider.ReleaseAsset, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		assets = append(assets, newReleaseAsset(apiObj))
+	}
+	return assets, nil
+}
+
+// Upload attaches a file with the given name and content to the release. The media type of
+// the asset is derived from the extension of name.
+//
+// ErrAlreadyExists is returned if the release already has an asset with the given name.
+func (c *ReleaseAssetClient) Upload(ctx context.Context, name string, content []byte) (gitprovider.ReleaseAsset, error) {
+	// POST /repos/{owner}/{repo}/releases/{release_id}/assets
+	apiObj, err := c.c.UploadReleaseAsset(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), c.id, name, content)
+	if err != nil {
+		return nil, err
+	}
+	return newReleaseAsset(apiObj), nil
+}
+
+// Download returns the content of the asset with the given name.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *ReleaseAssetClient) Download(ctx context.Context, name string) ([]byte, error) {
+	apiObj, err := c.getByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	// GET /repos/{owner}/{repo}/releases/assets/{asset_id}
+	return c.c.DownloadReleaseAsset(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), apiObj.GetID())
+}
+
+// Delete removes the asset with the given name from the release.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *ReleaseAssetClient) Delete(ctx context.Context, name string) error {
+	apiObj, err := c.getByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	// DELETE /repos/{owner}/{repo}/releases/assets/{asset_id}
+	return c.c.DeleteReleaseAsset(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), apiObj.GetID())
+}
+
+// getByName returns the asset of the release with the given name. Assets are addressed by ID
+// in the GitHub API, but names are unique within a release.
+func (c *ReleaseAssetClient) getByName(ctx context.Context, name string) (*github.ReleaseAsset, error) {
+	// GET /repos/{owner}/{repo}/releases/{release_id}/assets
+	apiObjs, err := c.c.ListReleaseAssets(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), c.id)
+	if err != nil {
+		return nil, err
+	}
+	for _, apiObj := range apiObjs {
+		if apiObj.GetName() == name {
+			return apiObj, nil
+		}
+	}
+	return nil, gitprovider.ErrNotFound
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+
+	"github.com/google/go-github/v32/github"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// TagClient implements the gitprovider.TagClient interface.
+var _ gitprovider.TagClient = &TagClient{}
+
+// TagClient operates on the tags for a specific repository.
+type TagClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// Get returns the tag with the given name.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *TagClient) Get(ctx context.Context, name string) (gitprovider.Tag, error) {
+	// GET /repos/{owner}/{repo}/git/ref/tags/{tag}
+	ref, err := c.c.GetTagRef(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), name)
+	if err != nil {
+		return nil, err
+	}
+	sha := ref.GetObject().GetSHA()
+
+	// Annotated tags point to a tag object, which holds the message and the commit sha
+	var annotated *github.Tag
+	if ref.GetObject().GetType() == "tag" {
+		// GET /repos/{owner}/{repo}/git/tags/{tag_sha}
+		annotated, err = c.c.GetTagObject(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), sha)
+		if err != nil {
+			return nil, err
+		}
+		sha = annotated.GetObject().GetSHA()
+	}
+	return newTag(tagToAPI(name, sha), annotated), nil
+}
+
+// List lists all tags of the repository. The messages of annotated tags aren't returned, use
+// Get for those.
+//
+// List returns all available tags, using multiple paginated requests if needed.
+func (c *TagClient) List(ctx context.Context) ([]gitprovider.Tag, error) {
+	// GET /repos/{owner}/{repo}/tags
+	apiObjs, err := c.c.ListTags(ctx, c.ref.GetIdentity(), c.ref.GetRepository())
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]gitprovider.Tag, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		tags = append(tags, newTag(apiObj, nil))
+	}
+	return tags, nil
+}
+
+// Create creates a tag with the given specifications. The tag is an annotated tag if
+// req.Message is set, and a lightweight tag otherwise.
+//
+// ErrAlreadyExists is returned if the tag already exists.
+func (c *TagClient) Create(ctx context.Context, req gitprovider.TagInfo) (gitprovider.Tag, error) {
+	if err := req.ValidateInfo(); err != nil {
+		return nil, err
+	}
+
+	// The tag ref of a lightweight tag points to the commit directly
+	refSha := req.Sha
+	var annotated *github.Tag
+	if len(req.Message) != 0 {
+		var err error
+		// POST /repos/{owner}/{repo}/git/tags
+		annotated, err = c.c.CreateTagObject(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), req.Name, req.Message, req.Sha)
+		if err != nil {
+			return nil, err
+		}
+		refSha = annotated.GetSHA()
+	}
+
+	// POST /repos/{owner}/{repo}/git/refs
+	if err := c.c.CreateTagRef(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), req.Name, refSha); err != nil {
+		return nil, err
+	}
+	return newTag(tagToAPI(req.Name, req.Sha), annotated), nil
+}
+
+// Delete deletes the tag with the given name. The release of the tag, if any, is kept as a
+// draft release.
+//
+// ErrDestructiveCallDisallowed is returned if the client wasn't set up with destructive
+// API calls enabled. ErrNotFound is returned if the resource does not exist.
+func (c *TagClient) Delete(ctx context.Context, name string) error {
+	// DELETE /repos/{owner}/{repo}/git/refs/tags/{tag}
+	return c.c.DeleteTag(ctx, c.ref.GetIdentity(), c.ref.GetRepository(), name)
+}
+
+func tagToAPI(name, sha string) *github.RepositoryTag {
+	return &github.RepositoryTag{
+		Name:   &name,
+		Commit: &github.Commit{SHA: &sha},
+	}
+}
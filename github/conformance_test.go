@@ -0,0 +1,54 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/gitprovider/conformance"
+)
+
+// TestConformance runs the conformance suite against github.com. It is skipped unless
+// GITHUB_TOKEN is set, and uses the same organization and user as the integration tests.
+// Team access is tested if GIT_PROVIDER_TEAM is set.
+func TestConformance(t *testing.T) {
+	token := os.Getenv("GITHUB_TOKEN")
+	if len(token) == 0 {
+		t.Skip("GITHUB_TOKEN is not set")
+	}
+	org, user := "fluxcd-testing", "fluxcd-gitprovider-bot"
+	if orgName := os.Getenv("GIT_PROVIDER_ORGANIZATION"); len(orgName) != 0 {
+		org = orgName
+	}
+	if userName := os.Getenv("GIT_PROVIDER_USER"); len(userName) != 0 {
+		user = userName
+	}
+
+	conformance.Run(t, conformance.Config{
+		NewClient: func(destructive bool) (gitprovider.Client, error) {
+			return NewClient(WithOAuth2Token(token), WithDestructiveAPICalls(destructive))
+		},
+		Organization: newOrgRef(org),
+		User:         newUserRef(user),
+		Team:         os.Getenv("GIT_PROVIDER_TEAM"),
+		Unsupported:  []conformance.Feature{conformance.FeatureOrganizationChildren},
+		Eventually:   30 * time.Second,
+	})
+}
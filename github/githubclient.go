@@ -17,8 +17,14 @@ limitations under the License.
 package github
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
 
 	"github.com/fluxcd/go-git-providers/gitprovider"
 	"github.com/google/go-github/v32/github"
@@ -32,6 +38,10 @@ type githubClient interface {
 	// Client returns the underlying *github.Client
 	Client() *github.Client
 
+	// GetRateLimit is a wrapper for "GET /rate_limit", returning the rate limit of the core API.
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetRateLimit(ctx context.Context) (*github.Rate, error)
+
 	// GetOrg is a wrapper for "GET /orgs/{org}".
 	// This function HTTP error wrapping, and validates the server result.
 	GetOrg(ctx context.Context, orgName string) (*github.Organization, error)
@@ -80,6 +90,119 @@ type githubClient interface {
 	// This function handles HTTP error wrapping.
 	DeleteKey(ctx context.Context, owner, repo string, id int64) error
 
+	// ListHooks is a wrapper for "GET /repos/{owner}/{repo}/hooks", or for
+	// "GET /orgs/{org}/hooks" if repo is empty.
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListHooks(ctx context.Context, owner, repo string) ([]*github.Hook, error)
+	// CreateHook is a wrapper for "POST /repos/{owner}/{repo}/hooks", or for
+	// "POST /orgs/{org}/hooks" if repo is empty.
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateHook(ctx context.Context, owner, repo string, req *github.Hook) (*github.Hook, error)
+	// EditHook is a wrapper for "PATCH /repos/{owner}/{repo}/hooks/{hook_id}", or for
+	// "PATCH /orgs/{org}/hooks/{hook_id}" if repo is empty.
+	// This function handles HTTP error wrapping, and validates the server result.
+	EditHook(ctx context.Context, owner, repo string, id int64, req *github.Hook) (*github.Hook, error)
+	// DeleteHook is a wrapper for "DELETE /repos/{owner}/{repo}/hooks/{hook_id}", or for
+	// "DELETE /orgs/{org}/hooks/{hook_id}" if repo is empty.
+	// This function handles HTTP error wrapping.
+	DeleteHook(ctx context.Context, owner, repo string, id int64) error
+
+	// GetBranch is a wrapper for "GET /repos/{owner}/{repo}/branches/{branch}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetBranch(ctx context.Context, owner, repo, branch string) (*github.Branch, error)
+	// ListBranches is a wrapper for "GET /repos/{owner}/{repo}/branches".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListBranches(ctx context.Context, owner, repo string) ([]*github.Branch, error)
+	// GetCommitSHA is a wrapper for "GET /repos/{owner}/{repo}/commits/{ref}", returning the sha
+	// of the commit the branch or tag ref points to.
+	// This function handles HTTP error wrapping.
+	GetCommitSHA(ctx context.Context, owner, repo, ref string) (string, error)
+	// CreateBranch is a wrapper for "POST /repos/{owner}/{repo}/git/refs".
+	// This function handles HTTP error wrapping.
+	CreateBranch(ctx context.Context, owner, repo, branch, sha string) error
+	// DeleteBranch is a wrapper for "DELETE /repos/{owner}/{repo}/git/refs/heads/{branch}".
+	// This function handles HTTP error wrapping.
+	// DANGEROUS COMMAND: In order to use this, you must set destructiveActions to true.
+	DeleteBranch(ctx context.Context, owner, repo, branch string) error
+
+	// GetTree is a wrapper for "GET /repos/{owner}/{repo}/git/trees/{tree_sha}", where treeish is
+	// a tree sha, or a ref followed by a colon and the path of a directory, e.g. "main:docs".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetTree(ctx context.Context, owner, repo, treeish string) (*github.Tree, error)
+	// GetBlobRaw is a wrapper for "GET /repos/{owner}/{repo}/git/blobs/{file_sha}", returning the
+	// raw content of the blob.
+	// This function handles HTTP error wrapping.
+	GetBlobRaw(ctx context.Context, owner, repo, sha string) ([]byte, error)
+
+	// GetBranchProtection is a wrapper for "GET /repos/{owner}/{repo}/branches/{branch}/protection".
+	// This function handles HTTP error wrapping.
+	GetBranchProtection(ctx context.Context, owner, repo, branch string) (*github.Protection, error)
+	// UpdateBranchProtection is a wrapper for "PUT /repos/{owner}/{repo}/branches/{branch}/protection".
+	// This function handles HTTP error wrapping.
+	UpdateBranchProtection(ctx context.Context, owner, repo, branch string, req *github.ProtectionRequest) (*github.Protection, error)
+	// DeleteBranchProtection is a wrapper for "DELETE /repos/{owner}/{repo}/branches/{branch}/protection".
+	// This function handles HTTP error wrapping.
+	DeleteBranchProtection(ctx context.Context, owner, repo, branch string) error
+
+	// ListTags is a wrapper for "GET /repos/{owner}/{repo}/tags".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListTags(ctx context.Context, owner, repo string) ([]*github.RepositoryTag, error)
+	// GetTagRef is a wrapper for "GET /repos/{owner}/{repo}/git/ref/tags/{tag}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetTagRef(ctx context.Context, owner, repo, tag string) (*github.Reference, error)
+	// GetTagObject is a wrapper for "GET /repos/{owner}/{repo}/git/tags/{tag_sha}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetTagObject(ctx context.Context, owner, repo, sha string) (*github.Tag, error)
+	// CreateTagObject is a wrapper for "POST /repos/{owner}/{repo}/git/tags", creating the tag
+	// object of an annotated tag pointing to the commit sha. It doesn't create the tag ref.
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateTagObject(ctx context.Context, owner, repo, tag, message, sha string) (*github.Tag, error)
+	// CreateTagRef is a wrapper for "POST /repos/{owner}/{repo}/git/refs".
+	// This function handles HTTP error wrapping.
+	CreateTagRef(ctx context.Context, owner, repo, tag, sha string) error
+	// DeleteTag is a wrapper for "DELETE /repos/{owner}/{repo}/git/refs/tags/{tag}".
+	// This function handles HTTP error wrapping.
+	// DANGEROUS COMMAND: In order to use this, you must set destructiveActions to true.
+	DeleteTag(ctx context.Context, owner, repo, tag string) error
+
+	// ListStatuses is a wrapper for "GET /repos/{owner}/{repo}/commits/{ref}/statuses".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListStatuses(ctx context.Context, owner, repo, ref string) ([]*github.RepoStatus, error)
+	// GetCombinedStatus is a wrapper for "GET /repos/{owner}/{repo}/commits/{ref}/status".
+	// This function handles pagination of the statuses, HTTP error wrapping, and validates the server result.
+	GetCombinedStatus(ctx context.Context, owner, repo, ref string) (*github.CombinedStatus, error)
+	// CreateStatus is a wrapper for "POST /repos/{owner}/{repo}/statuses/{sha}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateStatus(ctx context.Context, owner, repo, sha string, req *github.RepoStatus) (*github.RepoStatus, error)
+
+	// ListReleases is a wrapper for "GET /repos/{owner}/{repo}/releases".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListReleases(ctx context.Context, owner, repo string) ([]*github.RepositoryRelease, error)
+	// CreateRelease is a wrapper for "POST /repos/{owner}/{repo}/releases".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateRelease(ctx context.Context, owner, repo string, req *github.RepositoryRelease) (*github.RepositoryRelease, error)
+	// EditRelease is a wrapper for "PATCH /repos/{owner}/{repo}/releases/{release_id}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	EditRelease(ctx context.Context, owner, repo string, id int64, req *github.RepositoryRelease) (*github.RepositoryRelease, error)
+	// DeleteRelease is a wrapper for "DELETE /repos/{owner}/{repo}/releases/{release_id}".
+	// This function handles HTTP error wrapping.
+	DeleteRelease(ctx context.Context, owner, repo string, id int64) error
+
+	// ListReleaseAssets is a wrapper for "GET /repos/{owner}/{repo}/releases/{release_id}/assets".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListReleaseAssets(ctx context.Context, owner, repo string, id int64) ([]*github.ReleaseAsset, error)
+	// UploadReleaseAsset is a wrapper for "POST /repos/{owner}/{repo}/releases/{release_id}/assets"
+	// on the uploads host. The media type is derived from the extension of name.
+	// This function handles HTTP error wrapping, and validates the server result.
+	UploadReleaseAsset(ctx context.Context, owner, repo string, id int64, name string, content []byte) (*github.ReleaseAsset, error)
+	// DownloadReleaseAsset is a wrapper for "GET /repos/{owner}/{repo}/releases/assets/{asset_id}",
+	// returning the raw content of the asset.
+	// This function handles HTTP error wrapping.
+	DownloadReleaseAsset(ctx context.Context, owner, repo string, id int64) ([]byte, error)
+	// DeleteReleaseAsset is a wrapper for "DELETE /repos/{owner}/{repo}/releases/assets/{asset_id}".
+	// This function handles HTTP error wrapping.
+	DeleteReleaseAsset(ctx context.Context, owner, repo string, id int64) error
+
 	// GetTeamPermissions is a wrapper for "GET /orgs/{org}/teams/{team_slug}/repos/{owner}/{repo}".
 	// This function handles HTTP error wrapping, and validates the server result.
 	GetTeamPermissions(ctx context.Context, orgName, repo, teamName string) (map[string]bool, error)
@@ -92,6 +215,65 @@ type githubClient interface {
 	// RemoveTeam is a wrapper for "DELETE /orgs/{org}/teams/{team_slug}/repos/{owner}/{repo}".
 	// This function handles HTTP error wrapping.
 	RemoveTeam(ctx context.Context, orgName, repo, teamName string) error
+
+	// GetPullRequest is a wrapper for "GET /repos/{owner}/{repo}/pulls/{pull_number}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetPullRequest(ctx context.Context, owner, repo string, number int) (*github.PullRequest, error)
+	// ListPullRequests is a wrapper for "GET /repos/{owner}/{repo}/pulls".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListPullRequests(ctx context.Context, owner, repo string, opts *github.PullRequestListOptions) ([]*github.PullRequest, error)
+	// CreatePullRequest is a wrapper for "POST /repos/{owner}/{repo}/pulls".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreatePullRequest(ctx context.Context, owner, repo string, req *github.NewPullRequest) (*github.PullRequest, error)
+	// EditPullRequest is a wrapper for "PATCH /repos/{owner}/{repo}/pulls/{pull_number}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	EditPullRequest(ctx context.Context, owner, repo string, number int, req *github.PullRequest) (*github.PullRequest, error)
+	// MergePullRequest is a wrapper for "PUT /repos/{owner}/{repo}/pulls/{pull_number}/merge".
+	// This function handles HTTP error wrapping.
+	MergePullRequest(ctx context.Context, owner, repo string, number int, mergeMethod, message string) error
+	// ReplacePullRequestLabels is a wrapper for "PUT /repos/{owner}/{repo}/issues/{issue_number}/labels".
+	// This function handles HTTP error wrapping.
+	ReplacePullRequestLabels(ctx context.Context, owner, repo string, number int, labels []string) error
+
+	// ListPullRequestComments is a wrapper for "GET /repos/{owner}/{repo}/issues/{issue_number}/comments".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListPullRequestComments(ctx context.Context, owner, repo string, number int) ([]*github.IssueComment, error)
+	// CreatePullRequestComment is a wrapper for "POST /repos/{owner}/{repo}/issues/{issue_number}/comments".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreatePullRequestComment(ctx context.Context, owner, repo string, number int, body string) (*github.IssueComment, error)
+	// EditPullRequestComment is a wrapper for "PATCH /repos/{owner}/{repo}/issues/comments/{comment_id}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	EditPullRequestComment(ctx context.Context, owner, repo string, id int64, body string) (*github.IssueComment, error)
+	// DeletePullRequestComment is a wrapper for "DELETE /repos/{owner}/{repo}/issues/comments/{comment_id}".
+	// This function handles HTTP error wrapping.
+	DeletePullRequestComment(ctx context.Context, owner, repo string, id int64) error
+
+	// ListPullRequestReviews is a wrapper for "GET /repos/{owner}/{repo}/pulls/{pull_number}/reviews".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListPullRequestReviews(ctx context.Context, owner, repo string, number int) ([]*github.PullRequestReview, error)
+	// CreatePullRequestReview is a wrapper for "POST /repos/{owner}/{repo}/pulls/{pull_number}/reviews".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreatePullRequestReview(ctx context.Context, owner, repo string, number int, req *github.PullRequestReviewRequest) (*github.PullRequestReview, error)
+
+	// GetIssue is a wrapper for "GET /repos/{owner}/{repo}/issues/{issue_number}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetIssue(ctx context.Context, owner, repo string, number int) (*github.Issue, error)
+	// ListIssues is a wrapper for "GET /repos/{owner}/{repo}/issues". Pull requests are
+	// listed too, as they are issues in GitHub.
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListIssues(ctx context.Context, owner, repo string, opts *github.IssueListByRepoOptions) ([]*github.Issue, error)
+	// CreateIssue is a wrapper for "POST /repos/{owner}/{repo}/issues".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateIssue(ctx context.Context, owner, repo string, req *github.IssueRequest) (*github.Issue, error)
+	// EditIssue is a wrapper for "PATCH /repos/{owner}/{repo}/issues/{issue_number}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	EditIssue(ctx context.Context, owner, repo string, number int, req *github.IssueRequest) (*github.Issue, error)
+	// AddIssueLabels is a wrapper for "POST /repos/{owner}/{repo}/issues/{issue_number}/labels".
+	// This function handles HTTP error wrapping.
+	AddIssueLabels(ctx context.Context, owner, repo string, number int, labels []string) error
+	// RemoveIssueLabel is a wrapper for "DELETE /repos/{owner}/{repo}/issues/{issue_number}/labels/{name}".
+	// This function handles HTTP error wrapping.
+	RemoveIssueLabel(ctx context.Context, owner, repo string, number int, label string) error
 }
 
 // githubClientImpl is a wrapper around *github.Client, which implements higher-level methods,
@@ -110,6 +292,19 @@ func (c *githubClientImpl) Client() *github.Client {
 	return c.c
 }
 
+func (c *githubClientImpl) GetRateLimit(ctx context.Context) (*github.Rate, error) {
+	// GET /rate_limit
+	apiObj, _, err := c.c.RateLimits(ctx)
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Validate the API object
+	if apiObj.Core == nil {
+		return nil, fmt.Errorf("missing core rate limit: %w", gitprovider.ErrInvalidServerData)
+	}
+	return apiObj.Core, nil
+}
+
 func (c *githubClientImpl) GetOrg(ctx context.Context, orgName string) (*github.Organization, error) {
 	// GET /orgs/{org}
 	apiObj, _, err := c.c.Organizations.Get(ctx, orgName)
@@ -315,6 +510,11 @@ func (c *githubClientImpl) ListCommitsPage(ctx context.Context, owner, repo, bra
 			Tree: &github.Tree{
 				SHA: c.Commit.Tree.SHA,
 			},
+			Message:   c.Commit.Message,
+			Author:    c.Commit.Author,
+			Committer: c.Commit.Committer,
+			Parents:   c.Parents,
+			HTMLURL:   c.HTMLURL,
 		})
 	}
 
@@ -342,6 +542,452 @@ func (c *githubClientImpl) DeleteKey(ctx context.Context, owner, repo string, id
 	return handleHTTPError(err)
 }
 
+func (c *githubClientImpl) ListHooks(ctx context.Context, owner, repo string) ([]*github.Hook, error) {
+	apiObjs := []*github.Hook{}
+	opts := &github.ListOptions{}
+	err := allPages(opts, func() (*github.Response, error) {
+		var pageObjs []*github.Hook
+		var resp *github.Response
+		var listErr error
+		if len(repo) == 0 {
+			// GET /orgs/{org}/hooks
+			pageObjs, resp, listErr = c.c.Organizations.ListHooks(ctx, owner, opts)
+		} else {
+			// GET /repos/{owner}/{repo}/hooks
+			pageObjs, resp, listErr = c.c.Repositories.ListHooks(ctx, owner, repo, opts)
+		}
+		apiObjs = append(apiObjs, pageObjs...)
+		return resp, listErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, apiObj := range apiObjs {
+		if err := validateWebhookAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *githubClientImpl) CreateHook(ctx context.Context, owner, repo string, req *github.Hook) (*github.Hook, error) {
+	var apiObj *github.Hook
+	var err error
+	if len(repo) == 0 {
+		// POST /orgs/{org}/hooks
+		apiObj, _, err = c.c.Organizations.CreateHook(ctx, owner, req)
+	} else {
+		// POST /repos/{owner}/{repo}/hooks
+		apiObj, _, err = c.c.Repositories.CreateHook(ctx, owner, repo, req)
+	}
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validateWebhookAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *githubClientImpl) EditHook(ctx context.Context, owner, repo string, id int64, req *github.Hook) (*github.Hook, error) {
+	var apiObj *github.Hook
+	var err error
+	if len(repo) == 0 {
+		// PATCH /orgs/{org}/hooks/{hook_id}
+		apiObj, _, err = c.c.Organizations.EditHook(ctx, owner, id, req)
+	} else {
+		// PATCH /repos/{owner}/{repo}/hooks/{hook_id}
+		apiObj, _, err = c.c.Repositories.EditHook(ctx, owner, repo, id, req)
+	}
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validateWebhookAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *githubClientImpl) DeleteHook(ctx context.Context, owner, repo string, id int64) error {
+	var err error
+	if len(repo) == 0 {
+		// DELETE /orgs/{org}/hooks/{hook_id}
+		_, err = c.c.Organizations.DeleteHook(ctx, owner, id)
+	} else {
+		// DELETE /repos/{owner}/{repo}/hooks/{hook_id}
+		_, err = c.c.Repositories.DeleteHook(ctx, owner, repo, id)
+	}
+	return handleHTTPError(err)
+}
+
+func (c *githubClientImpl) GetBranch(ctx context.Context, owner, repo, branch string) (*github.Branch, error) {
+	// GET /repos/{owner}/{repo}/branches/{branch}
+	apiObj, _, err := c.c.Repositories.GetBranch(ctx, owner, repo, branch)
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validateBranchAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *githubClientImpl) ListBranches(ctx context.Context, owner, repo string) ([]*github.Branch, error) {
+	apiObjs := []*github.Branch{}
+	opts := &github.BranchListOptions{}
+	err := allPages(&opts.ListOptions, func() (*github.Response, error) {
+		// GET /repos/{owner}/{repo}/branches
+		pageObjs, resp, listErr := c.c.Repositories.ListBranches(ctx, owner, repo, opts)
+		apiObjs = append(apiObjs, pageObjs...)
+		return resp, listErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, apiObj := range apiObjs {
+		if err := validateBranchAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *githubClientImpl) GetCommitSHA(ctx context.Context, owner, repo, ref string) (string, error) {
+	// GET /repos/{owner}/{repo}/commits/{ref}
+	sha, _, err := c.c.Repositories.GetCommitSHA1(ctx, owner, repo, ref, "")
+	if err != nil {
+		return "", handleHTTPError(err)
+	}
+	return sha, nil
+}
+
+func (c *githubClientImpl) CreateBranch(ctx context.Context, owner, repo, branch, sha string) error {
+	// POST /repos/{owner}/{repo}/git/refs
+	_, _, err := c.c.Git.CreateRef(ctx, owner, repo, &github.Reference{
+		Ref:    github.String("refs/heads/" + branch),
+		Object: &github.GitObject{SHA: &sha},
+	})
+	return handleRefError(err)
+}
+
+func (c *githubClientImpl) DeleteBranch(ctx context.Context, owner, repo, branch string) error {
+	// Don't allow deleting branches if the user didn't explicitly allow dangerous API calls.
+	if !c.destructiveActions {
+		return fmt.Errorf("cannot delete branch: %w", gitprovider.ErrDestructiveCallDisallowed)
+	}
+	// DELETE /repos/{owner}/{repo}/git/refs/heads/{branch}
+	_, err := c.c.Git.DeleteRef(ctx, owner, repo, "heads/"+branch)
+	return handleRefError(err)
+}
+
+func (c *githubClientImpl) GetTree(ctx context.Context, owner, repo, treeish string) (*github.Tree, error) {
+	// GET /repos/{owner}/{repo}/git/trees/{tree_sha}
+	apiObj, _, err := c.c.Git.GetTree(ctx, owner, repo, treeish, false)
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	for _, entry := range apiObj.Entries {
+		if err := validateTreeEntryAPI(entry); err != nil {
+			return nil, err
+		}
+	}
+	return apiObj, nil
+}
+
+func (c *githubClientImpl) GetBlobRaw(ctx context.Context, owner, repo, sha string) ([]byte, error) {
+	// GET /repos/{owner}/{repo}/git/blobs/{file_sha}
+	content, _, err := c.c.Git.GetBlobRaw(ctx, owner, repo, sha)
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	return content, nil
+}
+
+func (c *githubClientImpl) GetBranchProtection(ctx context.Context, owner, repo, branch string) (*github.Protection, error) {
+	// GET /repos/{owner}/{repo}/branches/{branch}/protection
+	apiObj, _, err := c.c.Repositories.GetBranchProtection(ctx, owner, repo, branch)
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	return apiObj, nil
+}
+
+func (c *githubClientImpl) UpdateBranchProtection(ctx context.Context, owner, repo, branch string, req *github.ProtectionRequest) (*github.Protection, error) {
+	// PUT /repos/{owner}/{repo}/branches/{branch}/protection
+	apiObj, _, err := c.c.Repositories.UpdateBranchProtection(ctx, owner, repo, branch, req)
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	return apiObj, nil
+}
+
+func (c *githubClientImpl) DeleteBranchProtection(ctx context.Context, owner, repo, branch string) error {
+	// DELETE /repos/{owner}/{repo}/branches/{branch}/protection
+	_, err := c.c.Repositories.RemoveBranchProtection(ctx, owner, repo, branch)
+	return handleHTTPError(err)
+}
+
+func (c *githubClientImpl) ListTags(ctx context.Context, owner, repo string) ([]*github.RepositoryTag, error) {
+	opts := &github.ListOptions{}
+	apiObjs := []*github.RepositoryTag{}
+	err := allPages(opts, func() (*github.Response, error) {
+		// GET /repos/{owner}/{repo}/tags
+		pageObjs, resp, listErr := c.c.Repositories.ListTags(ctx, owner, repo, opts)
+		apiObjs = append(apiObjs, pageObjs...)
+		return resp, listErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate the API objects
+	for _, apiObj := range apiObjs {
+		if err := validateTagAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *githubClientImpl) GetTagRef(ctx context.Context, owner, repo, tag string) (*github.Reference, error) {
+	// GET /repos/{owner}/{repo}/git/ref/tags/{tag}
+	apiObj, _, err := c.c.Git.GetRef(ctx, owner, repo, "tags/"+tag)
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Validate the API object
+	if err := validateTagRefAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *githubClientImpl) GetTagObject(ctx context.Context, owner, repo, sha string) (*github.Tag, error) {
+	// GET /repos/{owner}/{repo}/git/tags/{tag_sha}
+	apiObj, _, err := c.c.Git.GetTag(ctx, owner, repo, sha)
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Validate the API object
+	if err := validateTagObjectAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *githubClientImpl) CreateTagObject(ctx context.Context, owner, repo, tag, message, sha string) (*github.Tag, error) {
+	// POST /repos/{owner}/{repo}/git/tags
+	apiObj, _, err := c.c.Git.CreateTag(ctx, owner, repo, &github.Tag{
+		Tag:     &tag,
+		Message: &message,
+		Object:  &github.GitObject{SHA: &sha, Type: github.String("commit")},
+	})
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Validate the API object
+	if err := validateTagObjectAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *githubClientImpl) CreateTagRef(ctx context.Context, owner, repo, tag, sha string) error {
+	// POST /repos/{owner}/{repo}/git/refs
+	_, _, err := c.c.Git.CreateRef(ctx, owner, repo, &github.Reference{
+		Ref:    github.String("refs/tags/" + tag),
+		Object: &github.GitObject{SHA: &sha},
+	})
+	return handleRefError(err)
+}
+
+func (c *githubClientImpl) DeleteTag(ctx context.Context, owner, repo, tag string) error {
+	// Don't allow deleting tags if the user didn't explicitly allow dangerous API calls.
+	if !c.destructiveActions {
+		return fmt.Errorf("cannot delete tag: %w", gitprovider.ErrDestructiveCallDisallowed)
+	}
+	// DELETE /repos/{owner}/{repo}/git/refs/tags/{tag}
+	_, err := c.c.Git.DeleteRef(ctx, owner, repo, "tags/"+tag)
+	return handleRefError(err)
+}
+
+func (c *githubClientImpl) ListStatuses(ctx context.Context, owner, repo, ref string) ([]*github.RepoStatus, error) {
+	opts := &github.ListOptions{}
+	apiObjs := []*github.RepoStatus{}
+	err := allPages(opts, func() (*github.Response, error) {
+		// GET /repos/{owner}/{repo}/commits/{ref}/statuses
+		pageObjs, resp, listErr := c.c.Repositories.ListStatuses(ctx, owner, repo, ref, opts)
+		apiObjs = append(apiObjs, pageObjs...)
+		return resp, handleCommitStatusError(listErr)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate the API objects
+	for _, apiObj := range apiObjs {
+		if err := validateCommitStatusAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *githubClientImpl) GetCombinedStatus(ctx context.Context, owner, repo, ref string) (*github.CombinedStatus, error) {
+	opts := &github.ListOptions{}
+	var apiObj *github.CombinedStatus
+	statuses := []*github.RepoStatus{}
+	err := allPages(opts, func() (*github.Response, error) {
+		// GET /repos/{owner}/{repo}/commits/{ref}/status
+		pageObj, resp, listErr := c.c.Repositories.GetCombinedStatus(ctx, owner, repo, ref, opts)
+		if pageObj != nil {
+			apiObj = pageObj
+			statuses = append(statuses, pageObj.Statuses...)
+		}
+		return resp, handleCommitStatusError(listErr)
+	})
+	if err != nil {
+		return nil, err
+	}
+	apiObj.Statuses = statuses
+
+	// Validate the API object
+	if err := validateCombinedCommitStatusAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *githubClientImpl) CreateStatus(ctx context.Context, owner, repo, sha string, req *github.RepoStatus) (*github.RepoStatus, error) {
+	// POST /repos/{owner}/{repo}/statuses/{sha}
+	apiObj, _, err := c.c.Repositories.CreateStatus(ctx, owner, repo, sha, req)
+	if err != nil {
+		return nil, handleCommitStatusError(err)
+	}
+	// Validate the API object
+	if err := validateCommitStatusAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *githubClientImpl) ListReleases(ctx context.Context, owner, repo string) ([]*github.RepositoryRelease, error) {
+	opts := &github.ListOptions{}
+	apiObjs := []*github.RepositoryRelease{}
+	err := allPages(opts, func() (*github.Response, error) {
+		// GET /repos/{owner}/{repo}/releases
+		pageObjs, resp, listErr := c.c.Repositories.ListReleases(ctx, owner, repo, opts)
+		apiObjs = append(apiObjs, pageObjs...)
+		return resp, listErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate the API objects
+	for _, apiObj := range apiObjs {
+		if err := validateReleaseAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *githubClientImpl) CreateRelease(ctx context.Context, owner, repo string, req *github.RepositoryRelease) (*github.RepositoryRelease, error) {
+	// POST /repos/{owner}/{repo}/releases
+	apiObj, _, err := c.c.Repositories.CreateRelease(ctx, owner, repo, req)
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Validate the API object
+	if err := validateReleaseAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *githubClientImpl) EditRelease(ctx context.Context, owner, repo string, id int64, req *github.RepositoryRelease) (*github.RepositoryRelease, error) {
+	// PATCH /repos/{owner}/{repo}/releases/{release_id}
+	apiObj, _, err := c.c.Repositories.EditRelease(ctx, owner, repo, id, req)
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Validate the API object
+	if err := validateReleaseAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *githubClientImpl) DeleteRelease(ctx context.Context, owner, repo string, id int64) error {
+	// DELETE /repos/{owner}/{repo}/releases/{release_id}
+	_, err := c.c.Repositories.DeleteRelease(ctx, owner, repo, id)
+	return handleHTTPError(err)
+}
+
+func (c *githubClientImpl) ListReleaseAssets(ctx context.Context, owner, repo string, id int64) ([]*github.ReleaseAsset, error) {
+	opts := &github.ListOptions{}
+	apiObjs := []*github.ReleaseAsset{}
+	err := allPages(opts, func() (*github.Response, error) {
+		// GET /repos/{owner}/{repo}/releases/{release_id}/assets
+		pageObjs, resp, listErr := c.c.Repositories.ListReleaseAssets(ctx, owner, repo, id, opts)
+		apiObjs = append(apiObjs, pageObjs...)
+		return resp, listErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate the API objects
+	for _, apiObj := range apiObjs {
+		if err := validateReleaseAssetAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *githubClientImpl) UploadReleaseAsset(ctx context.Context, owner, repo string, id int64, name string, content []byte) (*github.ReleaseAsset, error) {
+	// go-github only uploads *os.File contents, so build the upload request ourselves
+	mediaType := mime.TypeByExtension(path.Ext(name))
+	if mediaType == "" {
+		mediaType = defaultAssetMediaType
+	}
+	u := fmt.Sprintf("repos/%s/%s/releases/%d/assets?name=%s", owner, repo, id, url.QueryEscape(name))
+	req, err := c.c.NewUploadRequest(u, bytes.NewReader(content), int64(len(content)), mediaType)
+	if err != nil {
+		return nil, err
+	}
+	// POST /repos/{owner}/{repo}/releases/{release_id}/assets
+	apiObj := &github.ReleaseAsset{}
+	if _, err := c.c.Do(ctx, req, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Validate the API object
+	if err := validateReleaseAssetAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *githubClientImpl) DownloadReleaseAsset(ctx context.Context, owner, repo string, id int64) ([]byte, error) {
+	// GET /repos/{owner}/{repo}/releases/assets/{asset_id}
+	rc, _, err := c.c.Repositories.DownloadReleaseAsset(ctx, owner, repo, id, http.DefaultClient)
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	defer rc.Close()
+	return ioutil.ReadAll(rc)
+}
+
+func (c *githubClientImpl) DeleteReleaseAsset(ctx context.Context, owner, repo string, id int64) error {
+	// DELETE /repos/{owner}/{repo}/releases/assets/{asset_id}
+	_, err := c.c.Repositories.DeleteReleaseAsset(ctx, owner, repo, id)
+	return handleHTTPError(err)
+}
+
 func (c *githubClientImpl) GetTeamPermissions(ctx context.Context, orgName, repo, teamName string) (map[string]bool, error) {
 	// GET /orgs/{org}/teams/{team_slug}/repos/{owner}/{repo}
 	apiObj, _, err := c.c.Teams.IsTeamRepoBySlug(ctx, orgName, teamName, orgName, repo)
@@ -391,3 +1037,242 @@ func (c *githubClientImpl) RemoveTeam(ctx context.Context, orgName, repo, teamNa
 	_, err := c.c.Teams.RemoveTeamRepoBySlug(ctx, orgName, teamName, orgName, repo)
 	return handleHTTPError(err)
 }
+
+func (c *githubClientImpl) GetPullRequest(ctx context.Context, owner, repo string, number int) (*github.PullRequest, error) {
+	// GET /repos/{owner}/{repo}/pulls/{pull_number}
+	apiObj, _, err := c.c.PullRequests.Get(ctx, owner, repo, number)
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Validate the API object
+	if err := validatePullRequestAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *githubClientImpl) ListPullRequests(ctx context.Context, owner, repo string, opts *github.PullRequestListOptions) ([]*github.PullRequest, error) {
+	apiObjs := []*github.PullRequest{}
+	err := allPages(&opts.ListOptions, func() (*github.Response, error) {
+		// GET /repos/{owner}/{repo}/pulls
+		pageObjs, resp, listErr := c.c.PullRequests.List(ctx, owner, repo, opts)
+		apiObjs = append(apiObjs, pageObjs...)
+		return resp, listErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate the API objects
+	for _, apiObj := range apiObjs {
+		if err := validatePullRequestAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *githubClientImpl) CreatePullRequest(ctx context.Context, owner, repo string, req *github.NewPullRequest) (*github.PullRequest, error) {
+	// POST /repos/{owner}/{repo}/pulls
+	apiObj, _, err := c.c.PullRequests.Create(ctx, owner, repo, req)
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Validate the API object
+	if err := validatePullRequestAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *githubClientImpl) EditPullRequest(ctx context.Context, owner, repo string, number int, req *github.PullRequest) (*github.PullRequest, error) {
+	// PATCH /repos/{owner}/{repo}/pulls/{pull_number}
+	apiObj, _, err := c.c.PullRequests.Edit(ctx, owner, repo, number, req)
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Validate the API object
+	if err := validatePullRequestAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *githubClientImpl) MergePullRequest(ctx context.Context, owner, repo string, number int, mergeMethod, message string) error {
+	// PUT /repos/{owner}/{repo}/pulls/{pull_number}/merge
+	result, _, err := c.c.PullRequests.Merge(ctx, owner, repo, number, message, &github.PullRequestOptions{MergeMethod: mergeMethod})
+	if err != nil {
+		return handleHTTPError(err)
+	}
+	if !result.GetMerged() {
+		return fmt.Errorf("pull request %d wasn't merged: %s: %w", number, result.GetMessage(), gitprovider.ErrUnexpectedEvent)
+	}
+	return nil
+}
+
+func (c *githubClientImpl) ReplacePullRequestLabels(ctx context.Context, owner, repo string, number int, labels []string) error {
+	// PUT /repos/{owner}/{repo}/issues/{issue_number}/labels
+	_, _, err := c.c.Issues.ReplaceLabelsForIssue(ctx, owner, repo, number, labels)
+	return handleHTTPError(err)
+}
+
+func (c *githubClientImpl) ListPullRequestComments(ctx context.Context, owner, repo string, number int) ([]*github.IssueComment, error) {
+	opts := &github.IssueListCommentsOptions{}
+	apiObjs := []*github.IssueComment{}
+	err := allPages(&opts.ListOptions, func() (*github.Response, error) {
+		// GET /repos/{owner}/{repo}/issues/{issue_number}/comments
+		pageObjs, resp, listErr := c.c.Issues.ListComments(ctx, owner, repo, number, opts)
+		apiObjs = append(apiObjs, pageObjs...)
+		return resp, listErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate the API objects
+	for _, apiObj := range apiObjs {
+		if err := validatePullRequestCommentAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *githubClientImpl) CreatePullRequestComment(ctx context.Context, owner, repo string, number int, body string) (*github.IssueComment, error) {
+	// POST /repos/{owner}/{repo}/issues/{issue_number}/comments
+	apiObj, _, err := c.c.Issues.CreateComment(ctx, owner, repo, number, &github.IssueComment{Body: &body})
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Validate the API object
+	if err := validatePullRequestCommentAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *githubClientImpl) EditPullRequestComment(ctx context.Context, owner, repo string, id int64, body string) (*github.IssueComment, error) {
+	// PATCH /repos/{owner}/{repo}/issues/comments/{comment_id}
+	apiObj, _, err := c.c.Issues.EditComment(ctx, owner, repo, id, &github.IssueComment{Body: &body})
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Validate the API object
+	if err := validatePullRequestCommentAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *githubClientImpl) DeletePullRequestComment(ctx context.Context, owner, repo string, id int64) error {
+	// DELETE /repos/{owner}/{repo}/issues/comments/{comment_id}
+	_, err := c.c.Issues.DeleteComment(ctx, owner, repo, id)
+	return handleHTTPError(err)
+}
+
+func (c *githubClientImpl) ListPullRequestReviews(ctx context.Context, owner, repo string, number int) ([]*github.PullRequestReview, error) {
+	opts := &github.ListOptions{}
+	apiObjs := []*github.PullRequestReview{}
+	err := allPages(opts, func() (*github.Response, error) {
+		// GET /repos/{owner}/{repo}/pulls/{pull_number}/reviews
+		pageObjs, resp, listErr := c.c.PullRequests.ListReviews(ctx, owner, repo, number, opts)
+		apiObjs = append(apiObjs, pageObjs...)
+		return resp, listErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate the API objects
+	for _, apiObj := range apiObjs {
+		if err := validatePullRequestReviewAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *githubClientImpl) CreatePullRequestReview(ctx context.Context, owner, repo string, number int, req *github.PullRequestReviewRequest) (*github.PullRequestReview, error) {
+	// POST /repos/{owner}/{repo}/pulls/{pull_number}/reviews
+	apiObj, _, err := c.c.PullRequests.CreateReview(ctx, owner, repo, number, req)
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Validate the API object
+	if err := validatePullRequestReviewAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *githubClientImpl) GetIssue(ctx context.Context, owner, repo string, number int) (*github.Issue, error) {
+	// GET /repos/{owner}/{repo}/issues/{issue_number}
+	apiObj, _, err := c.c.Issues.Get(ctx, owner, repo, number)
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Validate the API object
+	if err := validateIssueAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *githubClientImpl) ListIssues(ctx context.Context, owner, repo string, opts *github.IssueListByRepoOptions) ([]*github.Issue, error) {
+	apiObjs := []*github.Issue{}
+	err := allPages(&opts.ListOptions, func() (*github.Response, error) {
+		// GET /repos/{owner}/{repo}/issues
+		pageObjs, resp, listErr := c.c.Issues.ListByRepo(ctx, owner, repo, opts)
+		apiObjs = append(apiObjs, pageObjs...)
+		return resp, listErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate the API objects
+	for _, apiObj := range apiObjs {
+		if err := validateIssueAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *githubClientImpl) CreateIssue(ctx context.Context, owner, repo string, req *github.IssueRequest) (*github.Issue, error) {
+	// POST /repos/{owner}/{repo}/issues
+	apiObj, _, err := c.c.Issues.Create(ctx, owner, repo, req)
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Validate the API object
+	if err := validateIssueAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *githubClientImpl) EditIssue(ctx context.Context, owner, repo string, number int, req *github.IssueRequest) (*github.Issue, error) {
+	// PATCH /repos/{owner}/{repo}/issues/{issue_number}
+	apiObj, _, err := c.c.Issues.Edit(ctx, owner, repo, number, req)
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Validate the API object
+	if err := validateIssueAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *githubClientImpl) AddIssueLabels(ctx context.Context, owner, repo string, number int, labels []string) error {
+	// POST /repos/{owner}/{repo}/issues/{issue_number}/labels
+	_, _, err := c.c.Issues.AddLabelsToIssue(ctx, owner, repo, number, labels)
+	return handleHTTPError(err)
+}
+
+func (c *githubClientImpl) RemoveIssueLabel(ctx context.Context, owner, repo string, number int, label string) error {
+	// DELETE /repos/{owner}/{repo}/issues/{issue_number}/labels/{name}
+	_, err := c.c.Issues.RemoveLabelForIssue(ctx, owner, repo, number, label)
+	return handleHTTPError(err)
+}
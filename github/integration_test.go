@@ -35,6 +35,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/gitprovider/recorder"
 	"github.com/fluxcd/go-git-providers/gitprovider/testutils"
 )
 
@@ -42,6 +43,12 @@ const (
 	ghTokenFile  = "/tmp/github-token"
 	githubDomain = "github.com"
 
+	// cassettePath is the cassette used when GIT_PROVIDER_RECORDER_MODE is "record" or "replay".
+	cassettePath = "testdata/cassettes/integration.json"
+	// recorderSeed seeds the random repository and branch names when recording or replaying,
+	// so that the requests match the cassette.
+	recorderSeed = 42
+
 	defaultDescription = "Foo description"
 	// TODO: This will change
 	defaultBranch = "main"
@@ -50,6 +57,8 @@ const (
 var (
 	// customTransportImpl is a shared instance of a customTransport, allowing counting of cache hits.
 	customTransportImpl *customTransport
+	// testRecorder records or replays the interactions of the suite, depending on GIT_PROVIDER_RECORDER_MODE.
+	testRecorder *recorder.Recorder
 )
 
 func init() {
@@ -138,16 +147,28 @@ var _ = Describe("GitHub Provider", func() {
 	)
 
 	BeforeSuite(func() {
+		mode, err := recorder.ModeFromEnv()
+		Expect(err).ToNot(HaveOccurred())
+
 		githubToken := os.Getenv("GITHUB_TOKEN")
 		if len(githubToken) == 0 {
 			b, err := ioutil.ReadFile(ghTokenFile)
 			if token := string(b); err == nil && len(token) != 0 {
 				githubToken = token
+			} else if mode == recorder.ModeReplay {
+				// No credentials are needed to replay the cassette
+				githubToken = "replay-token"
 			} else {
 				Fail("couldn't acquire GITHUB_TOKEN env variable")
 			}
 		}
 
+		if mode != recorder.ModePassthrough {
+			rand.Seed(recorderSeed)
+		}
+		testRecorder, err = recorder.New(cassettePath, mode, recorder.WithSecrets(githubToken))
+		Expect(err).ToNot(HaveOccurred())
+
 		if orgName := os.Getenv("GIT_PROVIDER_ORGANIZATION"); len(orgName) != 0 {
 			testOrgName = orgName
 		}
@@ -156,12 +177,12 @@ var _ = Describe("GitHub Provider", func() {
 			testUser = gitProviderUser
 		}
 
-		var err error
 		c, err = NewClient(
 			WithOAuth2Token(githubToken),
 			WithDestructiveAPICalls(true),
 			WithConditionalRequests(true),
 			WithPreChainTransportHook(customTransportFactory),
+			WithPostChainTransportHook(testRecorder.Transport),
 		)
 		Expect(err).ToNot(HaveOccurred())
 	})
@@ -255,6 +276,14 @@ var _ = Describe("GitHub Provider", func() {
 		Expect(errors.Is(err, gitprovider.ErrNoProviderSupport)).To(BeTrue())
 	})
 
+	It("should report the rate limit quota", func() {
+		quota, err := c.Quota(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(quota.Limit).To(BeNumerically(">", 0))
+		Expect(quota.Remaining).To(BeNumerically("<=", quota.Limit))
+		Expect(quota.Reset).ToNot(BeZero())
+	})
+
 	It("should be possible to create an org repository", func() {
 		// First, check what repositories are available
 		repos, err := c.OrgRepositories().List(ctx, newOrgRef(testOrgName))
@@ -326,7 +355,7 @@ var _ = Describe("GitHub Provider", func() {
 		Expect(err).ToNot(HaveOccurred())
 
 		// Should not be able to see the repo publicly
-		anonClient, err := NewClient()
+		anonClient, err := NewClient(WithPostChainTransportHook(testRecorder.Transport))
 		Expect(err).ToNot(HaveOccurred())
 		_, err = anonClient.UserRepositories().Get(ctx, userRepoRef)
 		Expect(errors.Is(err, gitprovider.ErrNotFound)).To(BeTrue())
@@ -413,6 +442,48 @@ var _ = Describe("GitHub Provider", func() {
 		Expect(actionTaken).To(BeTrue())
 	})
 
+	It("should be possible to protect a branch of an org repository", func() {
+		repoRef := newOrgRepoRef(testOrgName, testOrgRepoName)
+		repo, err := c.OrgRepositories().Get(ctx, repoRef)
+		Expect(err).ToNot(HaveOccurred())
+		defaultBranch := *repo.Get().DefaultBranch
+
+		_, err = repo.BranchProtections().Get(ctx, defaultBranch)
+		Expect(errors.Is(err, gitprovider.ErrNotFound)).To(BeTrue())
+
+		req := gitprovider.BranchProtectionInfo{
+			Branch:               defaultBranch,
+			RequiredApprovals:    gitprovider.IntVar(1),
+			RequiredStatusChecks: []string{"ci/build"},
+			DismissStaleReviews:  gitprovider.BoolVar(true),
+		}
+		bp, actionTaken, err := repo.BranchProtections().Reconcile(ctx, req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(actionTaken).To(BeTrue())
+		Expect(*bp.Get().RequiredApprovals).To(Equal(1))
+
+		// Reconciling the actual state is a no-op
+		_, actionTaken, err = repo.BranchProtections().Reconcile(ctx, req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(actionTaken).To(BeFalse())
+
+		req.RestrictPushes = gitprovider.BoolVar(true)
+		_, actionTaken, err = repo.BranchProtections().Reconcile(ctx, req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(actionTaken).To(BeTrue())
+		bp, err = repo.BranchProtections().Get(ctx, defaultBranch)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*bp.Get().RestrictPushes).To(BeTrue())
+
+		branch, err := repo.Branches().Get(ctx, defaultBranch)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(branch.Get().Protected).To(BeTrue())
+
+		Expect(bp.Delete(ctx)).ToNot(HaveOccurred())
+		_, err = repo.BranchProtections().Get(ctx, defaultBranch)
+		Expect(errors.Is(err, gitprovider.ErrNotFound)).To(BeTrue())
+	})
+
 	It("should validate that the token has the correct permissions", func() {
 		hasPermission, err := c.HasTokenPermission(ctx, 0)
 		Expect(err).To(Equal(gitprovider.ErrNoProviderSupport))
@@ -458,6 +529,21 @@ var _ = Describe("GitHub Provider", func() {
 		err = userRepo.Branches().Create(ctx, branchName, "wrong-sha")
 		Expect(err).To(HaveOccurred())
 
+		branch, err := userRepo.Branches().Get(ctx, branchName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(branch.Get().Sha).To(Equal(latestCommit.Get().Sha))
+
+		// Branch off the default branch without knowing its head
+		fromDefault, err := userRepo.Branches().CreateFrom(ctx, branchName+"-from", *defaultBranch)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fromDefault.Get().Sha).To(Equal(latestCommit.Get().Sha))
+		branches, err := userRepo.Branches().List(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(branches).To(HaveLen(3))
+		Expect(userRepo.Branches().Delete(ctx, branchName+"-from")).ToNot(HaveOccurred())
+		_, err = userRepo.Branches().Get(ctx, branchName+"-from")
+		Expect(errors.Is(err, gitprovider.ErrNotFound)).To(BeTrue())
+
 		path := "setup/config.txt"
 		content := "yaml content"
 		files := []gitprovider.CommitFile{
@@ -473,9 +559,55 @@ var _ = Describe("GitHub Provider", func() {
 		pr, err := userRepo.PullRequests().Create(ctx, "Added config file", branchName, *defaultBranch, "added config file")
 		Expect(err).ToNot(HaveOccurred())
 		Expect(pr.Get().WebURL).ToNot(BeEmpty())
+		Expect(pr.Get().State).To(Equal(gitprovider.PullRequestStateOpen))
+		Expect(pr.Get().HeadBranch).To(Equal(branchName))
+
+		prs, err := userRepo.PullRequests().List(ctx, &gitprovider.PullRequestListOptions{HeadBranch: &branchName})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(prs).To(HaveLen(1))
+		Expect(prs[0].Get().Number).To(Equal(pr.Get().Number))
+
+		// Reconciling updates the open pull request instead of opening another one
+		reconciled, actionTaken, err := userRepo.PullRequests().Reconcile(ctx, gitprovider.PullRequestInfo{
+			Title:       "Added config file",
+			Description: "added config file",
+			HeadBranch:  branchName,
+			BaseBranch:  *defaultBranch,
+			Labels:      []string{"automated"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(actionTaken).To(BeTrue())
+		Expect(reconciled.Get().Number).To(Equal(pr.Get().Number))
+		Expect(reconciled.Get().Labels).To(Equal([]string{"automated"}))
+
+		// Post plan output onto the pull request, and edit it
+		comment, err := reconciled.Comments().Create(ctx, "plan output")
+		Expect(err).ToNot(HaveOccurred())
+		comment, err = reconciled.Comments().Update(ctx, comment.Get().ID, "new plan output")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(comment.Get().Body).To(Equal("new plan output"))
+		comments, err := reconciled.Comments().List(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(comments).To(HaveLen(1))
+		Expect(comments[0].Get().ID).To(Equal(comment.Get().ID))
+		Expect(reconciled.Comments().Delete(ctx, comment.Get().ID)).ToNot(HaveOccurred())
+
+		review, err := reconciled.Reviews().Submit(ctx, gitprovider.PullRequestReviewStateCommented, "status summary")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(review.Get().State).To(Equal(gitprovider.PullRequestReviewStateCommented))
+
+		closed, err := userRepo.PullRequests().Close(ctx, pr.Get().Number)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(closed.Get().State).To(Equal(gitprovider.PullRequestStateClosed))
 	})
 
 	AfterSuite(func() {
+		if testRecorder != nil {
+			defer func() {
+				Expect(testRecorder.Stop()).ToNot(HaveOccurred())
+			}()
+		}
+
 		if os.Getenv("SKIP_CLEANUP") == "1" {
 			return
 		}
@@ -0,0 +1,65 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"github.com/google/go-github/v32/github"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+func newBranch(apiObj *github.Branch) *branch {
+	return &branch{
+		b: *apiObj,
+	}
+}
+
+var _ gitprovider.Branch = &branch{}
+
+type branch struct {
+	b github.Branch
+}
+
+func (b *branch) Get() gitprovider.BranchInfo {
+	return branchFromAPI(&b.b)
+}
+
+func (b *branch) APIObject() interface{} {
+	return &b.b
+}
+
+func branchFromAPI(apiObj *github.Branch) gitprovider.BranchInfo {
+	return gitprovider.BranchInfo{
+		Name:      apiObj.GetName(),
+		Sha:       apiObj.GetCommit().GetSHA(),
+		Protected: apiObj.GetProtected(),
+	}
+}
+
+// validateBranchAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateBranchAPI(apiObj *github.Branch) error {
+	return validateAPIObject("GitHub.Branch", func(validator validation.Validator) {
+		if apiObj.Name == nil {
+			validator.Required("Name")
+		}
+		if apiObj.Commit == nil || apiObj.Commit.SHA == nil {
+			validator.Required("Commit.SHA")
+		}
+	})
+}
@@ -0,0 +1,259 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/go-github/v32/github"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+func newBranchProtection(c *BranchProtectionClient, branch string, apiObj *github.Protection) *branchProtection {
+	return &branchProtection{
+		branch: branch,
+		p:      *apiObj,
+		c:      c,
+	}
+}
+
+var _ gitprovider.BranchProtection = &branchProtection{}
+
+type branchProtection struct {
+	// branch is the name of the protected branch, which isn't part of the API object
+	branch string
+	p      github.Protection
+	c      *BranchProtectionClient
+}
+
+func (bp *branchProtection) Get() gitprovider.BranchProtectionInfo {
+	return branchProtectionFromAPI(bp.branch, &bp.p)
+}
+
+func (bp *branchProtection) Set(info gitprovider.BranchProtectionInfo) error {
+	if err := info.ValidateInfo(); err != nil {
+		return err
+	}
+	bp.branch = info.Branch
+	branchProtectionInfoToAPIObj(&info, &bp.p)
+	return nil
+}
+
+func (bp *branchProtection) APIObject() interface{} {
+	return &bp.p
+}
+
+func (bp *branchProtection) Repository() gitprovider.RepositoryRef {
+	return bp.c.ref
+}
+
+// Update will apply the desired state in this object to the server.
+// The protection rules are replaced as a whole, but the rules of the API object which
+// BranchProtectionInfo doesn't describe (e.g. enforcing them for administrators) are kept.
+// In order to apply changes to this object, use the .Set({Resource}Info) error
+// function, or cast .APIObject() to a pointer to the provider-specific type
+// and set custom fields there.
+//
+// ErrNotFound is returned if the branch does not exist.
+// ErrNoProviderSupport is returned if pushes are restricted in a repository owned by a user.
+//
+// The internal API object will be overridden with the received server data.
+func (bp *branchProtection) Update(ctx context.Context) error {
+	req, err := branchProtectionToRequest(bp.c.ref, &bp.p)
+	if err != nil {
+		return err
+	}
+	// PUT /repos/{owner}/{repo}/branches/{branch}/protection
+	apiObj, err := bp.c.c.UpdateBranchProtection(ctx, bp.c.ref.GetIdentity(), bp.c.ref.GetRepository(), bp.branch, req)
+	if err != nil {
+		return err
+	}
+	bp.p = *apiObj
+	return nil
+}
+
+// Delete removes the protection rules of the branch.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (bp *branchProtection) Delete(ctx context.Context) error {
+	// DELETE /repos/{owner}/{repo}/branches/{branch}/protection
+	return bp.c.c.DeleteBranchProtection(ctx, bp.c.ref.GetIdentity(), bp.c.ref.GetRepository(), bp.branch)
+}
+
+// Reconcile makes sure the desired state in this object (called "req" here) becomes
+// the actual state in the backing Git provider.
+//
+// If req doesn't exist under the hood, it is created (actionTaken == true).
+// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+// If req is already the actual state, this is a no-op (actionTaken == false).
+//
+// The internal API object will be overridden with the received server data if actionTaken == true.
+func (bp *branchProtection) Reconcile(ctx context.Context) (bool, error) {
+	actual, err := bp.c.get(ctx, bp.branch)
+	if err != nil {
+		// Create if not found
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			return true, bp.Update(ctx)
+		}
+
+		// Unexpected path, Get should succeed or return NotFound
+		return false, err
+	}
+
+	// If the desired matches the actual state, do nothing
+	if bp.Get().Equals(actual.Get()) {
+		return false, nil
+	}
+	// If desired and actual state mis-match, update
+	return true, bp.Update(ctx)
+}
+
+func branchProtectionFromAPI(branch string, apiObj *github.Protection) gitprovider.BranchProtectionInfo {
+	info := gitprovider.BranchProtectionInfo{
+		Branch:                  branch,
+		RequiredApprovals:       gitprovider.IntVar(0),
+		DismissStaleReviews:     gitprovider.BoolVar(false),
+		RequireCodeOwnerReviews: gitprovider.BoolVar(false),
+		RestrictPushes:          gitprovider.BoolVar(apiObj.Restrictions != nil),
+		AllowForcePushes:        gitprovider.BoolVar(apiObj.AllowForcePushes != nil && apiObj.AllowForcePushes.Enabled),
+		AllowDeletions:          gitprovider.BoolVar(apiObj.AllowDeletions != nil && apiObj.AllowDeletions.Enabled),
+	}
+	if reviews := apiObj.RequiredPullRequestReviews; reviews != nil {
+		info.RequiredApprovals = gitprovider.IntVar(reviews.RequiredApprovingReviewCount)
+		info.DismissStaleReviews = gitprovider.BoolVar(reviews.DismissStaleReviews)
+		info.RequireCodeOwnerReviews = gitprovider.BoolVar(reviews.RequireCodeOwnerReviews)
+	}
+	if checks := apiObj.RequiredStatusChecks; checks != nil && len(checks.Contexts) != 0 {
+		info.RequiredStatusChecks = append([]string(nil), checks.Contexts...)
+	}
+	return info
+}
+
+func branchProtectionInfoToAPIObj(info *gitprovider.BranchProtectionInfo, apiObj *github.Protection) {
+	// The status checks are required only if there are any. Keep the strictness setting.
+	if len(info.RequiredStatusChecks) == 0 {
+		apiObj.RequiredStatusChecks = nil
+	} else {
+		if apiObj.RequiredStatusChecks == nil {
+			apiObj.RequiredStatusChecks = &github.RequiredStatusChecks{}
+		}
+		apiObj.RequiredStatusChecks.Contexts = append([]string{}, info.RequiredStatusChecks...)
+	}
+
+	// Reviews are required only if any of the review rules are set. Keep the dismissal restrictions.
+	reviews := &github.PullRequestReviewsEnforcement{}
+	if apiObj.RequiredPullRequestReviews != nil {
+		reviews = apiObj.RequiredPullRequestReviews
+	}
+	if info.RequiredApprovals != nil {
+		reviews.RequiredApprovingReviewCount = *info.RequiredApprovals
+	}
+	if info.DismissStaleReviews != nil {
+		reviews.DismissStaleReviews = *info.DismissStaleReviews
+	}
+	if info.RequireCodeOwnerReviews != nil {
+		reviews.RequireCodeOwnerReviews = *info.RequireCodeOwnerReviews
+	}
+	if reviews.RequiredApprovingReviewCount == 0 && !reviews.DismissStaleReviews && !reviews.RequireCodeOwnerReviews {
+		apiObj.RequiredPullRequestReviews = nil
+	} else {
+		apiObj.RequiredPullRequestReviews = reviews
+	}
+
+	// Restricting pushes without listing any users or teams leaves only administrators able to push.
+	// Keep the users and teams that were already allowed.
+	if info.RestrictPushes != nil {
+		if !*info.RestrictPushes {
+			apiObj.Restrictions = nil
+		} else if apiObj.Restrictions == nil {
+			apiObj.Restrictions = &github.BranchRestrictions{}
+		}
+	}
+	if info.AllowForcePushes != nil {
+		apiObj.AllowForcePushes = &github.AllowForcePushes{Enabled: *info.AllowForcePushes}
+	}
+	if info.AllowDeletions != nil {
+		apiObj.AllowDeletions = &github.AllowDeletions{Enabled: *info.AllowDeletions}
+	}
+}
+
+// branchProtectionToRequest returns the request for replacing the protection rules of a branch of
+// the repository at ref with apiObj.
+func branchProtectionToRequest(ref gitprovider.RepositoryRef, apiObj *github.Protection) (*github.ProtectionRequest, error) {
+	req := &github.ProtectionRequest{
+		RequiredStatusChecks: apiObj.RequiredStatusChecks,
+		EnforceAdmins:        apiObj.EnforceAdmins != nil && apiObj.EnforceAdmins.Enabled,
+	}
+	if reviews := apiObj.RequiredPullRequestReviews; reviews != nil {
+		req.RequiredPullRequestReviews = &github.PullRequestReviewsEnforcementRequest{
+			DismissStaleReviews:          reviews.DismissStaleReviews,
+			RequireCodeOwnerReviews:      reviews.RequireCodeOwnerReviews,
+			RequiredApprovingReviewCount: reviews.RequiredApprovingReviewCount,
+		}
+		if dr := reviews.DismissalRestrictions; dr != nil {
+			users, teams := userLogins(dr.Users), teamSlugs(dr.Teams)
+			req.RequiredPullRequestReviews.DismissalRestrictionsRequest = &github.DismissalRestrictionsRequest{
+				Users: &users,
+				Teams: &teams,
+			}
+		}
+	}
+	if r := apiObj.Restrictions; r != nil {
+		// GitHub only supports restricting pushes in organizations
+		if _, ok := ref.(gitprovider.OrgRepositoryRef); !ok {
+			return nil, fmt.Errorf("cannot restrict pushes in a user repository: %w", gitprovider.ErrNoProviderSupport)
+		}
+		req.Restrictions = &github.BranchRestrictionsRequest{
+			Users: userLogins(r.Users),
+			Teams: teamSlugs(r.Teams),
+		}
+		for _, app := range r.Apps {
+			req.Restrictions.Apps = append(req.Restrictions.Apps, app.GetSlug())
+		}
+	}
+	if apiObj.RequireLinearHistory != nil {
+		req.RequireLinearHistory = gitprovider.BoolVar(apiObj.RequireLinearHistory.Enabled)
+	}
+	if apiObj.AllowForcePushes != nil {
+		req.AllowForcePushes = gitprovider.BoolVar(apiObj.AllowForcePushes.Enabled)
+	}
+	if apiObj.AllowDeletions != nil {
+		req.AllowDeletions = gitprovider.BoolVar(apiObj.AllowDeletions.Enabled)
+	}
+	return req, nil
+}
+
+// userLogins returns the logins of users, never nil.
+func userLogins(users []*github.User) []string {
+	logins := make([]string, 0, len(users))
+	for _, user := range users {
+		logins = append(logins, user.GetLogin())
+	}
+	return logins
+}
+
+// teamSlugs returns the slugs of teams, never nil.
+func teamSlugs(teams []*github.Team) []string {
+	slugs := make([]string, 0, len(teams))
+	for _, team := range teams {
+		slugs = append(slugs, team.GetSlug())
+	}
+	return slugs
+}
@@ -0,0 +1,152 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-github/v32/github"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+func Test_branchProtectionToRequest(t *testing.T) {
+	orgRef := gitprovider.OrgRepositoryRef{
+		OrganizationRef: gitprovider.OrganizationRef{Domain: "github.com", Organization: "org"},
+		RepositoryName:  "repo",
+	}
+	userRef := gitprovider.UserRepositoryRef{
+		UserRef:        gitprovider.UserRef{Domain: "github.com", UserLogin: "user"},
+		RepositoryName: "repo",
+	}
+	tests := []struct {
+		name    string
+		ref     gitprovider.RepositoryRef
+		actual  *github.Protection
+		info    gitprovider.BranchProtectionInfo
+		want    *github.ProtectionRequest
+		wantErr error
+	}{
+		{
+			name:   "defaults",
+			ref:    orgRef,
+			actual: &github.Protection{},
+			info:   gitprovider.BranchProtectionInfo{Branch: "main"},
+			want: &github.ProtectionRequest{
+				AllowForcePushes: gitprovider.BoolVar(false),
+				AllowDeletions:   gitprovider.BoolVar(false),
+			},
+		},
+		{
+			name:   "all rules",
+			ref:    orgRef,
+			actual: &github.Protection{},
+			info: gitprovider.BranchProtectionInfo{
+				Branch:                  "main",
+				RequiredApprovals:       gitprovider.IntVar(2),
+				RequiredStatusChecks:    []string{"build"},
+				DismissStaleReviews:     gitprovider.BoolVar(true),
+				RequireCodeOwnerReviews: gitprovider.BoolVar(true),
+				RestrictPushes:          gitprovider.BoolVar(true),
+				AllowForcePushes:        gitprovider.BoolVar(true),
+				AllowDeletions:          gitprovider.BoolVar(true),
+			},
+			want: &github.ProtectionRequest{
+				RequiredStatusChecks: &github.RequiredStatusChecks{Contexts: []string{"build"}},
+				RequiredPullRequestReviews: &github.PullRequestReviewsEnforcementRequest{
+					DismissStaleReviews:          true,
+					RequireCodeOwnerReviews:      true,
+					RequiredApprovingReviewCount: 2,
+				},
+				Restrictions:     &github.BranchRestrictionsRequest{Users: []string{}, Teams: []string{}},
+				AllowForcePushes: gitprovider.BoolVar(true),
+				AllowDeletions:   gitprovider.BoolVar(true),
+			},
+		},
+		{
+			name: "keeps rules not described by the info",
+			ref:  orgRef,
+			actual: &github.Protection{
+				RequiredStatusChecks: &github.RequiredStatusChecks{Strict: true, Contexts: []string{"build"}},
+				EnforceAdmins:        &github.AdminEnforcement{Enabled: true},
+				Restrictions: &github.BranchRestrictions{
+					Users: []*github.User{{Login: github.String("alice")}},
+					Teams: []*github.Team{{Slug: github.String("admins")}},
+				},
+				RequireLinearHistory: &github.RequireLinearHistory{Enabled: true},
+			},
+			info: gitprovider.BranchProtectionInfo{
+				Branch:               "main",
+				RequiredStatusChecks: []string{"build", "test"},
+				RestrictPushes:       gitprovider.BoolVar(true),
+			},
+			want: &github.ProtectionRequest{
+				RequiredStatusChecks: &github.RequiredStatusChecks{Strict: true, Contexts: []string{"build", "test"}},
+				EnforceAdmins:        true,
+				Restrictions:         &github.BranchRestrictionsRequest{Users: []string{"alice"}, Teams: []string{"admins"}},
+				RequireLinearHistory: gitprovider.BoolVar(true),
+				AllowForcePushes:     gitprovider.BoolVar(false),
+				AllowDeletions:       gitprovider.BoolVar(false),
+			},
+		},
+		{
+			name: "removes rules",
+			ref:  orgRef,
+			actual: &github.Protection{
+				RequiredStatusChecks:       &github.RequiredStatusChecks{Contexts: []string{"build"}},
+				RequiredPullRequestReviews: &github.PullRequestReviewsEnforcement{RequiredApprovingReviewCount: 1},
+				Restrictions:               &github.BranchRestrictions{},
+			},
+			info: gitprovider.BranchProtectionInfo{Branch: "main"},
+			want: &github.ProtectionRequest{
+				AllowForcePushes: gitprovider.BoolVar(false),
+				AllowDeletions:   gitprovider.BoolVar(false),
+			},
+		},
+		{
+			name:    "restricted pushes in a user repository",
+			ref:     userRef,
+			actual:  &github.Protection{},
+			info:    gitprovider.BranchProtectionInfo{Branch: "main", RestrictPushes: gitprovider.BoolVar(true)},
+			wantErr: gitprovider.ErrNoProviderSupport,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := gitprovider.ValidateAndDefaultInfo(&tt.info); err != nil {
+				t.Fatal(err)
+			}
+			branchProtectionInfoToAPIObj(&tt.info, tt.actual)
+			got, err := branchProtectionToRequest(tt.ref, tt.actual)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("branchProtectionToRequest() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("branchProtectionToRequest() = %+v, want %+v", got, tt.want)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			// The info must survive the round-trip through the API object
+			if info := branchProtectionFromAPI("main", tt.actual); !tt.info.Equals(info) {
+				t.Errorf("branchProtectionFromAPI() = %+v, want %+v", info, tt.info)
+			}
+		})
+	}
+}
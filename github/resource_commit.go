@@ -45,8 +45,43 @@ func (c *commitType) APIObject() interface{} {
 }
 
 func commitFromAPI(apiObj *github.Commit) gitprovider.CommitInfo {
+	parentShas := make([]string, 0, len(apiObj.Parents))
+	for _, parent := range apiObj.Parents {
+		parentShas = append(parentShas, parent.GetSHA())
+	}
 	return gitprovider.CommitInfo{
-		Sha:     *apiObj.SHA,
-		TreeSha: *apiObj.Tree.SHA,
+		Sha:        *apiObj.SHA,
+		TreeSha:    *apiObj.Tree.SHA,
+		Message:    apiObj.GetMessage(),
+		Author:     commitAuthorFromAPI(apiObj.Author),
+		Committer:  commitAuthorFromAPI(apiObj.Committer),
+		ParentShas: parentShas,
+		WebURL:     apiObj.GetHTMLURL(),
+	}
+}
+
+func commitAuthorFromAPI(apiObj *github.CommitAuthor) *gitprovider.CommitAuthor {
+	if apiObj == nil {
+		return nil
+	}
+	return &gitprovider.CommitAuthor{
+		Name:  apiObj.GetName(),
+		Email: apiObj.GetEmail(),
+		Date:  apiObj.GetDate(),
+	}
+}
+
+func commitAuthorToAPI(author *gitprovider.CommitAuthor) *github.CommitAuthor {
+	if author == nil {
+		return nil
+	}
+	apiObj := &github.CommitAuthor{
+		Name:  &author.Name,
+		Email: &author.Email,
+	}
+	// A zero date is left out, for the server to use the time of the commit
+	if !author.Date.IsZero() {
+		apiObj.Date = &author.Date
 	}
+	return apiObj
 }
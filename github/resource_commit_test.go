@@ -0,0 +1,107 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v32/github"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+func Test_commitFromAPI(t *testing.T) {
+	date := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	tests := []struct {
+		name   string
+		apiObj *github.Commit
+		want   gitprovider.CommitInfo
+	}{
+		{
+			name: "all fields",
+			apiObj: &github.Commit{
+				SHA:       github.String("abc"),
+				Tree:      &github.Tree{SHA: github.String("def")},
+				Message:   github.String("Add docs"),
+				Author:    &github.CommitAuthor{Name: github.String("Jane"), Email: github.String("jane@example.com"), Date: &date},
+				Committer: &github.CommitAuthor{Name: github.String("Bot"), Email: github.String("bot@example.com"), Date: &date},
+				Parents:   []*github.Commit{{SHA: github.String("p1")}, {SHA: github.String("p2")}},
+				HTMLURL:   github.String("https://github.com/org/repo/commit/abc"),
+			},
+			want: gitprovider.CommitInfo{
+				Sha:        "abc",
+				TreeSha:    "def",
+				Message:    "Add docs",
+				Author:     &gitprovider.CommitAuthor{Name: "Jane", Email: "jane@example.com", Date: date},
+				Committer:  &gitprovider.CommitAuthor{Name: "Bot", Email: "bot@example.com", Date: date},
+				ParentShas: []string{"p1", "p2"},
+				WebURL:     "https://github.com/org/repo/commit/abc",
+			},
+		},
+		{
+			name: "only sha and tree",
+			apiObj: &github.Commit{
+				SHA:  github.String("abc"),
+				Tree: &github.Tree{SHA: github.String("def")},
+			},
+			want: gitprovider.CommitInfo{
+				Sha:        "abc",
+				TreeSha:    "def",
+				ParentShas: []string{},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := commitFromAPI(tt.apiObj); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("commitFromAPI() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_commitAuthorToAPI(t *testing.T) {
+	date := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	tests := []struct {
+		name   string
+		author *gitprovider.CommitAuthor
+		want   *github.CommitAuthor
+	}{
+		{
+			name: "nil",
+		},
+		{
+			name:   "zero date is left out",
+			author: &gitprovider.CommitAuthor{Name: "Jane", Email: "jane@example.com"},
+			want:   &github.CommitAuthor{Name: github.String("Jane"), Email: github.String("jane@example.com")},
+		},
+		{
+			name:   "date",
+			author: &gitprovider.CommitAuthor{Name: "Jane", Email: "jane@example.com", Date: date},
+			want:   &github.CommitAuthor{Name: github.String("Jane"), Email: github.String("jane@example.com"), Date: &date},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := commitAuthorToAPI(tt.author); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("commitAuthorToAPI() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
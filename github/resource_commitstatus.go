@@ -0,0 +1,125 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"github.com/google/go-github/v32/github"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+func newCommitStatus(apiObj *github.RepoStatus) *commitStatus {
+	return &commitStatus{
+		s: *apiObj,
+	}
+}
+
+var _ gitprovider.CommitStatus = &commitStatus{}
+
+type commitStatus struct {
+	s github.RepoStatus
+}
+
+func (s *commitStatus) Get() gitprovider.CommitStatusInfo {
+	return commitStatusFromAPI(&s.s)
+}
+
+func (s *commitStatus) APIObject() interface{} {
+	return &s.s
+}
+
+func newCombinedCommitStatus(apiObj *github.CombinedStatus) *combinedCommitStatus {
+	return &combinedCommitStatus{
+		s: *apiObj,
+	}
+}
+
+var _ gitprovider.CombinedCommitStatus = &combinedCommitStatus{}
+
+type combinedCommitStatus struct {
+	s github.CombinedStatus
+}
+
+func (s *combinedCommitStatus) Get() gitprovider.CombinedCommitStatusInfo {
+	return combinedCommitStatusFromAPI(&s.s)
+}
+
+func (s *combinedCommitStatus) APIObject() interface{} {
+	return &s.s
+}
+
+func commitStatusFromAPI(apiObj *github.RepoStatus) gitprovider.CommitStatusInfo {
+	// The states of GitHub map 1:1 to gitprovider.CommitStatusState
+	return gitprovider.CommitStatusInfo{
+		State:       gitprovider.CommitStatusState(apiObj.GetState()),
+		Context:     apiObj.GetContext(),
+		Description: apiObj.GetDescription(),
+		TargetURL:   apiObj.GetTargetURL(),
+		Creator:     apiObj.GetCreator().GetLogin(),
+		CreatedAt:   apiObj.GetCreatedAt(),
+	}
+}
+
+func combinedCommitStatusFromAPI(apiObj *github.CombinedStatus) gitprovider.CombinedCommitStatusInfo {
+	statuses := make([]gitprovider.CommitStatusInfo, 0, len(apiObj.Statuses))
+	for i := range apiObj.Statuses {
+		statuses = append(statuses, commitStatusFromAPI(apiObj.Statuses[i]))
+	}
+	return gitprovider.CombinedCommitStatusInfo{
+		Sha:      apiObj.GetSHA(),
+		State:    gitprovider.CommitStatusState(apiObj.GetState()),
+		Statuses: statuses,
+	}
+}
+
+func commitStatusToAPI(req *gitprovider.CommitStatusInfo) *github.RepoStatus {
+	apiObj := &github.RepoStatus{
+		State:   github.String(string(req.State)),
+		Context: &req.Context,
+	}
+	if len(req.Description) != 0 {
+		apiObj.Description = &req.Description
+	}
+	if len(req.TargetURL) != 0 {
+		apiObj.TargetURL = &req.TargetURL
+	}
+	return apiObj
+}
+
+// validateCommitStatusAPI validates the apiObj received from the server, to make sure that it
+// is valid for our use.
+func validateCommitStatusAPI(apiObj *github.RepoStatus) error {
+	return validateAPIObject("GitHub.RepoStatus", func(validator validation.Validator) {
+		if apiObj.State == nil {
+			validator.Required("State")
+		}
+	})
+}
+
+// validateCombinedCommitStatusAPI validates the apiObj received from the server, to make sure
+// that it is valid for our use.
+func validateCombinedCommitStatusAPI(apiObj *github.CombinedStatus) error {
+	return validateAPIObject("GitHub.CombinedStatus", func(validator validation.Validator) {
+		if apiObj.SHA == nil {
+			validator.Required("SHA")
+		}
+		if apiObj.State == nil {
+			validator.Required("State")
+		}
+	})
+}
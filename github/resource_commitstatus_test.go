@@ -0,0 +1,86 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v32/github"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+func Test_combinedCommitStatusFromAPI(t *testing.T) {
+	createdAt := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	apiObj := &github.CombinedStatus{
+		SHA:   github.String("abc123"),
+		State: github.String("failure"),
+		Statuses: []*github.RepoStatus{
+			{
+				State:     github.String("error"),
+				Context:   github.String("ci/build"),
+				TargetURL: github.String("https://ci.example.com/1"),
+				Creator:   &github.User{Login: github.String("octocat")},
+				CreatedAt: &createdAt,
+			},
+			{
+				State:       github.String("success"),
+				Context:     github.String("ci/lint"),
+				Description: github.String("All good"),
+			},
+		},
+	}
+	want := gitprovider.CombinedCommitStatusInfo{
+		Sha:   "abc123",
+		State: gitprovider.CommitStatusStateFailure,
+		Statuses: []gitprovider.CommitStatusInfo{
+			{
+				State:     gitprovider.CommitStatusStateError,
+				Context:   "ci/build",
+				TargetURL: "https://ci.example.com/1",
+				Creator:   "octocat",
+				CreatedAt: createdAt,
+			},
+			{
+				State:       gitprovider.CommitStatusStateSuccess,
+				Context:     "ci/lint",
+				Description: "All good",
+			},
+		},
+	}
+	if got := combinedCommitStatusFromAPI(apiObj); !reflect.DeepEqual(got, want) {
+		t.Errorf("combinedCommitStatusFromAPI() = %+v, want %+v", got, want)
+	}
+}
+
+func Test_commitStatusToAPI(t *testing.T) {
+	req := gitprovider.CommitStatusInfo{
+		State:     gitprovider.CommitStatusStatePending,
+		TargetURL: "https://ci.example.com/2",
+	}
+	req.Default()
+	want := &github.RepoStatus{
+		State:     github.String("pending"),
+		Context:   github.String("default"),
+		TargetURL: github.String("https://ci.example.com/2"),
+	}
+	if got := commitStatusToAPI(&req); !reflect.DeepEqual(got, want) {
+		t.Errorf("commitStatusToAPI() = %+v, want %+v", got, want)
+	}
+}
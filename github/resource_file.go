@@ -0,0 +1,75 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"github.com/google/go-github/v32/github"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+// newFile returns the file of a tree entry, whose Path is relative to the root of the
+// repository, and whose Content is set to the raw content of the file if it was fetched.
+func newFile(apiObj *github.TreeEntry) *file {
+	return &file{
+		e: *apiObj,
+	}
+}
+
+var _ gitprovider.File = &file{}
+
+type file struct {
+	e github.TreeEntry
+}
+
+func (f *file) Get() gitprovider.FileInfo {
+	return fileFromAPI(&f.e)
+}
+
+func (f *file) APIObject() interface{} {
+	return &f.e
+}
+
+func fileFromAPI(apiObj *github.TreeEntry) gitprovider.FileInfo {
+	info := gitprovider.FileInfo{
+		Path: apiObj.GetPath(),
+		Mode: gitprovider.FileMode(apiObj.GetMode()),
+		Sha:  apiObj.GetSHA(),
+		Size: int64(apiObj.GetSize()),
+	}
+	if apiObj.Content != nil {
+		info.Content = []byte(*apiObj.Content)
+	}
+	return info
+}
+
+// validateTreeEntryAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateTreeEntryAPI(apiObj *github.TreeEntry) error {
+	return validateAPIObject("GitHub.TreeEntry", func(validator validation.Validator) {
+		if apiObj.Path == nil {
+			validator.Required("Path")
+		}
+		if apiObj.Mode == nil {
+			validator.Required("Mode")
+		}
+		if apiObj.SHA == nil {
+			validator.Required("SHA")
+		}
+	})
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"github.com/google/go-github/v32/github"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+func newIssue(ctx *clientContext, apiObj *github.Issue, ref gitprovider.RepositoryRef) *issue {
+	return &issue{
+		i: *apiObj,
+		comments: &IssueCommentClient{
+			clientContext: ctx,
+			ref:           ref,
+			number:        apiObj.GetNumber(),
+		},
+	}
+}
+
+var _ gitprovider.Issue = &issue{}
+
+type issue struct {
+	i        github.Issue
+	comments *IssueCommentClient
+}
+
+func (i *issue) Get() gitprovider.IssueInfo {
+	return issueFromAPI(&i.i)
+}
+
+func (i *issue) APIObject() interface{} {
+	return &i.i
+}
+
+// Comments gives access to this specific issue's comments
+func (i *issue) Comments() gitprovider.IssueCommentClient {
+	return i.comments
+}
+
+func issueFromAPI(apiObj *github.Issue) gitprovider.IssueInfo {
+	return gitprovider.IssueInfo{
+		Number:    apiObj.GetNumber(),
+		Title:     apiObj.GetTitle(),
+		Body:      apiObj.GetBody(),
+		State:     gitprovider.IssueState(apiObj.GetState()),
+		Labels:    labelsFromAPI(apiObj.Labels),
+		Assignees: assigneesFromAPI(apiObj.Assignees),
+		Author:    apiObj.GetUser().GetLogin(),
+		CreatedAt: apiObj.GetCreatedAt(),
+		UpdatedAt: apiObj.GetUpdatedAt(),
+		ClosedAt:  apiObj.ClosedAt,
+		WebURL:    apiObj.GetHTMLURL(),
+	}
+}
+
+// assigneesFromAPI returns the logins of the given users, or nil if there are none.
+func assigneesFromAPI(apiObjs []*github.User) []string {
+	if len(apiObjs) == 0 {
+		return nil
+	}
+	logins := make([]string, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		logins = append(logins, apiObj.GetLogin())
+	}
+	return logins
+}
+
+func validateIssueAPI(apiObj *github.Issue) error {
+	return validateAPIObject("GitHub.Issue", func(validator validation.Validator) {
+		// Make sure the number and state are populated, as per
+		// https://docs.github.com/en/rest/reference/issues#get-an-issue
+		if apiObj.Number == nil {
+			validator.Required("Number")
+		}
+		if apiObj.State == nil {
+			validator.Required("State")
+		}
+	})
+}
@@ -0,0 +1,53 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"github.com/google/go-github/v32/github"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+func newIssueComment(apiObj *github.IssueComment) *issueComment {
+	return &issueComment{
+		c: *apiObj,
+	}
+}
+
+var _ gitprovider.IssueComment = &issueComment{}
+
+type issueComment struct {
+	c github.IssueComment
+}
+
+func (c *issueComment) Get() gitprovider.IssueCommentInfo {
+	return issueCommentFromAPI(&c.c)
+}
+
+func (c *issueComment) APIObject() interface{} {
+	return &c.c
+}
+
+func issueCommentFromAPI(apiObj *github.IssueComment) gitprovider.IssueCommentInfo {
+	return gitprovider.IssueCommentInfo{
+		ID:        apiObj.GetID(),
+		Body:      apiObj.GetBody(),
+		Author:    apiObj.GetUser().GetLogin(),
+		CreatedAt: apiObj.GetCreatedAt(),
+		UpdatedAt: apiObj.GetUpdatedAt(),
+	}
+}
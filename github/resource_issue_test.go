@@ -0,0 +1,85 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v32/github"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+func Test_issueFromAPI(t *testing.T) {
+	createdAt := time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC)
+	closedAt := createdAt.Add(time.Hour)
+	tests := []struct {
+		name   string
+		apiObj *github.Issue
+		want   gitprovider.IssueInfo
+	}{
+		{
+			name: "open, without labels and assignees",
+			apiObj: &github.Issue{
+				Number:    github.Int(1),
+				Title:     github.String("Drift detected"),
+				State:     github.String("open"),
+				User:      &github.User{Login: github.String("octocat")},
+				CreatedAt: &createdAt,
+				HTMLURL:   github.String("https://github.com/octocat/hello-world/issues/1"),
+			},
+			want: gitprovider.IssueInfo{
+				Number:    1,
+				Title:     "Drift detected",
+				State:     gitprovider.IssueStateOpen,
+				Author:    "octocat",
+				CreatedAt: createdAt,
+				WebURL:    "https://github.com/octocat/hello-world/issues/1",
+			},
+		},
+		{
+			name: "closed, with labels and assignees",
+			apiObj: &github.Issue{
+				Number:    github.Int(2),
+				Title:     github.String("Policy violation"),
+				Body:      github.String("Details"),
+				State:     github.String("closed"),
+				Labels:    []*github.Label{{Name: github.String("policy")}, {Name: github.String("prod")}},
+				Assignees: []*github.User{{Login: github.String("alice")}, {Login: github.String("bob")}},
+				ClosedAt:  &closedAt,
+			},
+			want: gitprovider.IssueInfo{
+				Number:    2,
+				Title:     "Policy violation",
+				Body:      "Details",
+				State:     gitprovider.IssueStateClosed,
+				Labels:    []string{"policy", "prod"},
+				Assignees: []string{"alice", "bob"},
+				ClosedAt:  &closedAt,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := issueFromAPI(tt.apiObj); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("issueFromAPI() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -32,6 +32,10 @@ func newOrganization(ctx *clientContext, apiObj *github.Organization, ref gitpro
 			clientContext: ctx,
 			ref:           ref,
 		},
+		webhooks: &WebhookClient{
+			clientContext: ctx,
+			owner:         ref.Organization,
+		},
 	}
 }
 
@@ -43,7 +47,8 @@ type organization struct {
 	o   github.Organization
 	ref gitprovider.OrganizationRef
 
-	teams *TeamsClient
+	teams    *TeamsClient
+	webhooks *WebhookClient
 }
 
 func (o *organization) Get() gitprovider.OrganizationInfo {
@@ -62,6 +67,10 @@ func (o *organization) Teams() gitprovider.TeamsClient {
 	return o.teams
 }
 
+func (o *organization) Webhooks() gitprovider.WebhookClient {
+	return o.webhooks
+}
+
 func organizationFromAPI(apiObj *github.Organization) gitprovider.OrganizationInfo {
 	return gitprovider.OrganizationInfo{
 		Name:        apiObj.Name,
@@ -17,14 +17,26 @@ limitations under the License.
 package github
 
 import (
-	"github.com/fluxcd/go-git-providers/gitprovider"
 	"github.com/google/go-github/v32/github"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
 )
 
-func newPullRequest(ctx *clientContext, apiObj *github.PullRequest) *pullrequest {
+func newPullRequest(ctx *clientContext, apiObj *github.PullRequest, ref gitprovider.RepositoryRef) *pullrequest {
 	return &pullrequest{
 		clientContext: ctx,
 		pr:            *apiObj,
+		comments: &PullRequestCommentClient{
+			clientContext: ctx,
+			ref:           ref,
+			number:        apiObj.GetNumber(),
+		},
+		reviews: &PullRequestReviewClient{
+			clientContext: ctx,
+			ref:           ref,
+			number:        apiObj.GetNumber(),
+		},
 	}
 }
 
@@ -34,6 +46,9 @@ type pullrequest struct {
 	*clientContext
 
 	pr github.PullRequest
+
+	comments *PullRequestCommentClient
+	reviews  *PullRequestReviewClient
 }
 
 func (pr *pullrequest) Get() gitprovider.PullRequestInfo {
@@ -44,8 +59,70 @@ func (pr *pullrequest) APIObject() interface{} {
 	return &pr.pr
 }
 
+// Comments gives access to this specific pull request's comments
+func (pr *pullrequest) Comments() gitprovider.PullRequestCommentClient {
+	return pr.comments
+}
+
+// Reviews gives access to this specific pull request's reviews
+func (pr *pullrequest) Reviews() gitprovider.PullRequestReviewClient {
+	return pr.reviews
+}
+
 func pullrequestFromAPI(apiObj *github.PullRequest) gitprovider.PullRequestInfo {
 	return gitprovider.PullRequestInfo{
-		WebURL: apiObj.GetHTMLURL(),
+		Number:      apiObj.GetNumber(),
+		Title:       apiObj.GetTitle(),
+		Description: apiObj.GetBody(),
+		HeadBranch:  apiObj.GetHead().GetRef(),
+		BaseBranch:  apiObj.GetBase().GetRef(),
+		State:       pullRequestStateFromAPI(apiObj),
+		Labels:      labelsFromAPI(apiObj.Labels),
+		Author:      apiObj.GetUser().GetLogin(),
+		Draft:       apiObj.GetDraft(),
+		Mergeable:   apiObj.Mergeable,
+		CreatedAt:   apiObj.GetCreatedAt(),
+		UpdatedAt:   apiObj.GetUpdatedAt(),
+		MergedAt:    apiObj.MergedAt,
+		ClosedAt:    apiObj.ClosedAt,
+		WebURL:      apiObj.GetHTMLURL(),
 	}
 }
+
+// labelsFromAPI returns the names of the given labels, or nil if there are none.
+func labelsFromAPI(apiObjs []*github.Label) []string {
+	if len(apiObjs) == 0 {
+		return nil
+	}
+	labels := make([]string, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		labels = append(labels, apiObj.GetName())
+	}
+	return labels
+}
+
+// pullRequestStateFromAPI returns the state of the pull request. GitHub reports merged pull
+// requests as closed, with a merge time.
+func pullRequestStateFromAPI(apiObj *github.PullRequest) gitprovider.PullRequestState {
+	switch {
+	case apiObj.GetState() == "open":
+		return gitprovider.PullRequestStateOpen
+	case apiObj.MergedAt != nil || apiObj.GetMerged():
+		return gitprovider.PullRequestStateMerged
+	default:
+		return gitprovider.PullRequestStateClosed
+	}
+}
+
+func validatePullRequestAPI(apiObj *github.PullRequest) error {
+	return validateAPIObject("GitHub.PullRequest", func(validator validation.Validator) {
+		// Make sure the number and state are populated, as per
+		// https://docs.github.com/en/rest/reference/pulls#get-a-pull-request
+		if apiObj.Number == nil {
+			validator.Required("Number")
+		}
+		if apiObj.State == nil {
+			validator.Required("State")
+		}
+	})
+}
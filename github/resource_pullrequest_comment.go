@@ -0,0 +1,63 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"github.com/google/go-github/v32/github"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+func newPullRequestComment(apiObj *github.IssueComment) *pullRequestComment {
+	return &pullRequestComment{
+		c: *apiObj,
+	}
+}
+
+var _ gitprovider.PullRequestComment = &pullRequestComment{}
+
+type pullRequestComment struct {
+	c github.IssueComment
+}
+
+func (c *pullRequestComment) Get() gitprovider.PullRequestCommentInfo {
+	return pullRequestCommentFromAPI(&c.c)
+}
+
+func (c *pullRequestComment) APIObject() interface{} {
+	return &c.c
+}
+
+func pullRequestCommentFromAPI(apiObj *github.IssueComment) gitprovider.PullRequestCommentInfo {
+	return gitprovider.PullRequestCommentInfo{
+		ID:        apiObj.GetID(),
+		Body:      apiObj.GetBody(),
+		Author:    apiObj.GetUser().GetLogin(),
+		CreatedAt: apiObj.GetCreatedAt(),
+		UpdatedAt: apiObj.GetUpdatedAt(),
+	}
+}
+
+func validatePullRequestCommentAPI(apiObj *github.IssueComment) error {
+	return validateAPIObject("GitHub.IssueComment", func(validator validation.Validator) {
+		// Make sure the ID is populated, it's needed to update and delete the comment
+		if apiObj.ID == nil {
+			validator.Required("ID")
+		}
+	})
+}
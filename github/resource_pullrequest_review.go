@@ -0,0 +1,86 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"github.com/google/go-github/v32/github"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+const (
+	reviewStateApproved         = "APPROVED"
+	reviewStateChangesRequested = "CHANGES_REQUESTED"
+	reviewStatePending          = "PENDING"
+)
+
+func newPullRequestReview(apiObj *github.PullRequestReview) *pullRequestReview {
+	return &pullRequestReview{
+		r: *apiObj,
+	}
+}
+
+var _ gitprovider.PullRequestReview = &pullRequestReview{}
+
+type pullRequestReview struct {
+	r github.PullRequestReview
+}
+
+func (r *pullRequestReview) Get() gitprovider.PullRequestReviewInfo {
+	return pullRequestReviewFromAPI(&r.r)
+}
+
+func (r *pullRequestReview) APIObject() interface{} {
+	return &r.r
+}
+
+func pullRequestReviewFromAPI(apiObj *github.PullRequestReview) gitprovider.PullRequestReviewInfo {
+	return gitprovider.PullRequestReviewInfo{
+		ID:          apiObj.GetID(),
+		State:       reviewStateFromAPI(apiObj.GetState()),
+		Body:        apiObj.GetBody(),
+		Author:      apiObj.GetUser().GetLogin(),
+		SubmittedAt: apiObj.SubmittedAt,
+	}
+}
+
+// reviewStateFromAPI maps the state of a review. Dismissed reviews don't count as approvals or
+// change requests anymore, so they are reported as comments, like "COMMENTED" reviews.
+func reviewStateFromAPI(state string) gitprovider.PullRequestReviewState {
+	switch state {
+	case reviewStateApproved:
+		return gitprovider.PullRequestReviewStateApproved
+	case reviewStateChangesRequested:
+		return gitprovider.PullRequestReviewStateChangesRequested
+	default:
+		return gitprovider.PullRequestReviewStateCommented
+	}
+}
+
+func validatePullRequestReviewAPI(apiObj *github.PullRequestReview) error {
+	return validateAPIObject("GitHub.PullRequestReview", func(validator validation.Validator) {
+		// Make sure the ID and state are populated, as per
+		// https://docs.github.com/en/rest/reference/pulls#reviews
+		if apiObj.ID == nil {
+			validator.Required("ID")
+		}
+		if apiObj.State == nil {
+			validator.Required("State")
+		}
+	})
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"github.com/google/go-github/v32/github"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+func newRelease(c *ReleaseClient, apiObj *github.RepositoryRelease) *release {
+	return &release{
+		r: *apiObj,
+		assets: &ReleaseAssetClient{
+			clientContext: c.clientContext,
+			ref:           c.ref,
+			id:            apiObj.GetID(),
+		},
+	}
+}
+
+var _ gitprovider.Release = &release{}
+
+type release struct {
+	r      github.RepositoryRelease
+	assets *ReleaseAssetClient
+}
+
+func (r *release) Get() gitprovider.ReleaseInfo {
+	return releaseFromAPI(&r.r)
+}
+
+func (r *release) APIObject() interface{} {
+	return &r.r
+}
+
+func (r *release) Assets() gitprovider.ReleaseAssetClient {
+	return r.assets
+}
+
+func releaseFromAPI(apiObj *github.RepositoryRelease) gitprovider.ReleaseInfo {
+	return gitprovider.ReleaseInfo{
+		TagName:         apiObj.GetTagName(),
+		TargetCommitish: apiObj.GetTargetCommitish(),
+		Name:            apiObj.GetName(),
+		Body:            apiObj.GetBody(),
+		Draft:           apiObj.GetDraft(),
+		Prerelease:      apiObj.GetPrerelease(),
+		CreatedAt:       apiObj.GetCreatedAt().Time,
+		WebURL:          apiObj.GetHTMLURL(),
+	}
+}
+
+func releaseToAPI(req *gitprovider.ReleaseInfo) *github.RepositoryRelease {
+	apiObj := &github.RepositoryRelease{
+		TagName:    &req.TagName,
+		Name:       &req.Name,
+		Body:       &req.Body,
+		Draft:      &req.Draft,
+		Prerelease: &req.Prerelease,
+	}
+	// Let GitHub default to the default branch if the target isn't set
+	if len(req.TargetCommitish) != 0 {
+		apiObj.TargetCommitish = &req.TargetCommitish
+	}
+	return apiObj
+}
+
+// validateReleaseAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateReleaseAPI(apiObj *github.RepositoryRelease) error {
+	return validateAPIObject("GitHub.RepositoryRelease", func(validator validation.Validator) {
+		// Make sure the ID is populated, it's needed to update and delete the release
+		if apiObj.ID == nil {
+			validator.Required("ID")
+		}
+		if apiObj.TagName == nil {
+			validator.Required("TagName")
+		}
+	})
+}
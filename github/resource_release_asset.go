@@ -0,0 +1,67 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"github.com/google/go-github/v32/github"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+func newReleaseAsset(apiObj *github.ReleaseAsset) *releaseAsset {
+	return &releaseAsset{
+		a: *apiObj,
+	}
+}
+
+var _ gitprovider.ReleaseAsset = &releaseAsset{}
+
+type releaseAsset struct {
+	a github.ReleaseAsset
+}
+
+func (a *releaseAsset) Get() gitprovider.ReleaseAssetInfo {
+	return releaseAssetFromAPI(&a.a)
+}
+
+func (a *releaseAsset) APIObject() interface{} {
+	return &a.a
+}
+
+func releaseAssetFromAPI(apiObj *github.ReleaseAsset) gitprovider.ReleaseAssetInfo {
+	return gitprovider.ReleaseAssetInfo{
+		Name:        apiObj.GetName(),
+		Size:        int64(apiObj.GetSize()),
+		ContentType: apiObj.GetContentType(),
+		DownloadURL: apiObj.GetBrowserDownloadURL(),
+	}
+}
+
+// validateReleaseAssetAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateReleaseAssetAPI(apiObj *github.ReleaseAsset) error {
+	return validateAPIObject("GitHub.ReleaseAsset", func(validator validation.Validator) {
+		// Make sure the ID is populated, it's needed to download and delete the asset
+		if apiObj.ID == nil {
+			validator.Required("ID")
+		}
+		if apiObj.Name == nil {
+			validator.Required("Name")
+		}
+	})
+}
@@ -0,0 +1,68 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/go-github/v32/github"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+func Test_releaseToAPI(t *testing.T) {
+	tests := []struct {
+		name string
+		req  gitprovider.ReleaseInfo
+		want *github.RepositoryRelease
+	}{
+		{
+			name: "with target",
+			req:  gitprovider.ReleaseInfo{TagName: "v1.0.0", TargetCommitish: "main", Name: "v1.0.0", Body: "notes", Prerelease: true},
+			want: &github.RepositoryRelease{
+				TagName:         github.String("v1.0.0"),
+				TargetCommitish: github.String("main"),
+				Name:            github.String("v1.0.0"),
+				Body:            github.String("notes"),
+				Draft:           github.Bool(false),
+				Prerelease:      github.Bool(true),
+			},
+		},
+		{
+			name: "default target",
+			req:  gitprovider.ReleaseInfo{TagName: "v1.0.0", Name: "v1.0.0", Draft: true},
+			want: &github.RepositoryRelease{
+				TagName:    github.String("v1.0.0"),
+				Name:       github.String("v1.0.0"),
+				Body:       github.String(""),
+				Draft:      github.Bool(true),
+				Prerelease: github.Bool(false),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := releaseToAPI(&tt.req); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("releaseToAPI() = %+v, want %+v", got, tt.want)
+			}
+			if got := releaseFromAPI(tt.want); !tt.req.Equals(got) {
+				t.Errorf("releaseFromAPI() = %+v, want %+v", got, tt.req)
+			}
+		})
+	}
+}
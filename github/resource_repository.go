@@ -44,10 +44,39 @@ func newUserRepository(ctx *clientContext, apiObj *github.Repository, ref gitpro
 			clientContext: ctx,
 			ref:           ref,
 		},
+		files: &FileClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		branchProtections: &BranchProtectionClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
 		pullRequests: &PullRequestClient{
 			clientContext: ctx,
 			ref:           ref,
 		},
+		tags: &TagClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		releases: &ReleaseClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		webhooks: &WebhookClient{
+			clientContext: ctx,
+			owner:         ref.GetIdentity(),
+			repository:    ref.GetRepository(),
+		},
+		commitStatuses: &CommitStatusClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		issues: &IssueClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
 	}
 }
 
@@ -59,10 +88,17 @@ type userRepository struct {
 	r   github.Repository // go-github
 	ref gitprovider.RepositoryRef
 
-	deployKeys   *DeployKeyClient
-	commits      *CommitClient
-	branches     *BranchClient
-	pullRequests *PullRequestClient
+	deployKeys        *DeployKeyClient
+	commits           *CommitClient
+	branches          *BranchClient
+	files             *FileClient
+	branchProtections *BranchProtectionClient
+	pullRequests      *PullRequestClient
+	tags              *TagClient
+	releases          *ReleaseClient
+	webhooks          *WebhookClient
+	commitStatuses    *CommitStatusClient
+	issues            *IssueClient
 }
 
 func (r *userRepository) Get() gitprovider.RepositoryInfo {
@@ -97,10 +133,38 @@ func (r *userRepository) Branches() gitprovider.BranchClient {
 	return r.branches
 }
 
+func (r *userRepository) Files() gitprovider.FileClient {
+	return r.files
+}
+
+func (r *userRepository) BranchProtections() gitprovider.BranchProtectionClient {
+	return r.branchProtections
+}
+
 func (r *userRepository) PullRequests() gitprovider.PullRequestClient {
 	return r.pullRequests
 }
 
+func (r *userRepository) Tags() gitprovider.TagClient {
+	return r.tags
+}
+
+func (r *userRepository) Releases() gitprovider.ReleaseClient {
+	return r.releases
+}
+
+func (r *userRepository) Webhooks() gitprovider.WebhookClient {
+	return r.webhooks
+}
+
+func (r *userRepository) CommitStatuses() gitprovider.CommitStatusClient {
+	return r.commitStatuses
+}
+
+func (r *userRepository) Issues() gitprovider.IssueClient {
+	return r.issues
+}
+
 // Update will apply the desired state in this object to the server.
 // Only set fields will be respected (i.e. PATCH behaviour).
 // In order to apply changes to this object, use the .Set({Resource}Info) error
@@ -0,0 +1,96 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"github.com/google/go-github/v32/github"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+// newTag creates a tag resource from the tag listing object. annotated is the tag object of an
+// annotated tag, and may be nil for lightweight tags, or when the tag object wasn't fetched.
+func newTag(apiObj *github.RepositoryTag, annotated *github.Tag) *tag {
+	return &tag{
+		t:         *apiObj,
+		annotated: annotated,
+	}
+}
+
+var _ gitprovider.Tag = &tag{}
+
+type tag struct {
+	t         github.RepositoryTag
+	annotated *github.Tag
+}
+
+func (t *tag) Get() gitprovider.TagInfo {
+	return tagFromAPI(&t.t, t.annotated)
+}
+
+func (t *tag) APIObject() interface{} {
+	return &t.t
+}
+
+func tagFromAPI(apiObj *github.RepositoryTag, annotated *github.Tag) gitprovider.TagInfo {
+	return gitprovider.TagInfo{
+		Name:    apiObj.GetName(),
+		Sha:     apiObj.GetCommit().GetSHA(),
+		Message: annotated.GetMessage(),
+	}
+}
+
+// validateTagAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateTagAPI(apiObj *github.RepositoryTag) error {
+	return validateAPIObject("GitHub.RepositoryTag", func(validator validation.Validator) {
+		if apiObj.Name == nil {
+			validator.Required("Name")
+		}
+		if apiObj.Commit == nil || apiObj.Commit.SHA == nil {
+			validator.Required("Commit.SHA")
+		}
+	})
+}
+
+// validateTagRefAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateTagRefAPI(apiObj *github.Reference) error {
+	return validateAPIObject("GitHub.Reference", func(validator validation.Validator) {
+		// The type tells apart lightweight tags from the tag objects of annotated tags
+		if apiObj.Object == nil || apiObj.Object.SHA == nil {
+			validator.Required("Object.SHA")
+		}
+		if apiObj.Object == nil || apiObj.Object.Type == nil {
+			validator.Required("Object.Type")
+		}
+	})
+}
+
+// validateTagObjectAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateTagObjectAPI(apiObj *github.Tag) error {
+	return validateAPIObject("GitHub.Tag", func(validator validation.Validator) {
+		if apiObj.SHA == nil {
+			validator.Required("SHA")
+		}
+		if apiObj.Object == nil || apiObj.Object.SHA == nil {
+			validator.Required("Object.SHA")
+		}
+	})
+}
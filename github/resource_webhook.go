@@ -0,0 +1,241 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/go-github/v32/github"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+const (
+	// The keys of the webhook configuration, see
+	// https://docs.github.com/en/rest/reference/repos#create-a-repository-webhook
+	hookConfigURL         = "url"
+	hookConfigContentType = "content_type"
+	hookConfigSecret      = "secret"
+	hookConfigInsecureSSL = "insecure_ssl"
+	// maskedHookSecret is the value of the secret of received webhooks.
+	maskedHookSecret = "********"
+)
+
+// webhookEvents maps the webhook events to the names of the GitHub events.
+//
+//nolint:gochecknoglobals
+var webhookEvents = map[gitprovider.WebhookEvent]string{
+	gitprovider.WebhookEventPush:        "push",
+	gitprovider.WebhookEventTag:         "create",
+	gitprovider.WebhookEventPullRequest: "pull_request",
+	gitprovider.WebhookEventRelease:     "release",
+	gitprovider.WebhookEventIssue:       "issues",
+	gitprovider.WebhookEventComment:     "issue_comment",
+}
+
+func newWebhook(c *WebhookClient, apiObj *github.Hook) *webhook {
+	return &webhook{
+		h: *apiObj,
+		c: c,
+	}
+}
+
+var _ gitprovider.Webhook = &webhook{}
+
+type webhook struct {
+	h github.Hook
+	c *WebhookClient
+}
+
+func (wh *webhook) Get() gitprovider.WebhookInfo {
+	return webhookFromAPI(&wh.h)
+}
+
+func (wh *webhook) Set(info gitprovider.WebhookInfo) error {
+	if err := info.ValidateInfo(); err != nil {
+		return err
+	}
+	webhookInfoToAPIObj(&info, &wh.h)
+	return nil
+}
+
+func (wh *webhook) APIObject() interface{} {
+	return &wh.h
+}
+
+// Update will apply the desired state in this object to the server.
+// In order to apply changes to this object, use the .Set({Resource}Info) error
+// function, or cast .APIObject() to a pointer to the provider-specific type
+// and set custom fields there.
+//
+// ErrNotFound is returned if the resource does not exist.
+//
+// The internal API object will be overridden with the received server data.
+func (wh *webhook) Update(ctx context.Context) error {
+	// We can use the same webhook ID that we got from the GET calls. Make sure it's non-nil.
+	// This _should never_ happen, but just check for it anyways to avoid panicing.
+	if wh.h.ID == nil {
+		return fmt.Errorf("didn't expect ID to be nil: %w", gitprovider.ErrUnexpectedEvent)
+	}
+	// PATCH /repos/{owner}/{repo}/hooks/{hook_id} or PATCH /orgs/{org}/hooks/{hook_id}
+	apiObj, err := wh.c.c.EditHook(ctx, wh.c.owner, wh.c.repository, *wh.h.ID, webhookToRequest(&wh.h))
+	if err != nil {
+		return err
+	}
+	wh.h = *apiObj
+	return nil
+}
+
+// Delete deletes the webhook.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (wh *webhook) Delete(ctx context.Context) error {
+	// We can use the same webhook ID that we got from the GET calls. Make sure it's non-nil.
+	// This _should never_ happen, but just check for it anyways to avoid panicing.
+	if wh.h.ID == nil {
+		return fmt.Errorf("didn't expect ID to be nil: %w", gitprovider.ErrUnexpectedEvent)
+	}
+	// DELETE /repos/{owner}/{repo}/hooks/{hook_id} or DELETE /orgs/{org}/hooks/{hook_id}
+	return wh.c.c.DeleteHook(ctx, wh.c.owner, wh.c.repository, *wh.h.ID)
+}
+
+// Reconcile makes sure the desired state in this object (called "req" here) becomes
+// the actual state in the backing Git provider. The webhook is looked up by its URL.
+//
+// If req doesn't exist under the hood, it is created (actionTaken == true).
+// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+// If req is already the actual state, this is a no-op (actionTaken == false).
+//
+// The internal API object will be overridden with the received server data if actionTaken == true.
+func (wh *webhook) Reconcile(ctx context.Context) (bool, error) {
+	actual, err := wh.c.get(ctx, wh.Get().URL)
+	if err != nil {
+		// Create if not found
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			// POST /repos/{owner}/{repo}/hooks or POST /orgs/{org}/hooks
+			apiObj, err := wh.c.c.CreateHook(ctx, wh.c.owner, wh.c.repository, webhookToRequest(&wh.h))
+			if err != nil {
+				return true, err
+			}
+			wh.h = *apiObj
+			return true, nil
+		}
+
+		// Unexpected path, Get should succeed or return NotFound
+		return false, err
+	}
+
+	// If the desired matches the actual state, do nothing
+	if wh.Get().Equals(actual.Get()) {
+		return false, nil
+	}
+	// If desired and actual state mis-match, update the found webhook
+	wh.h.ID = actual.h.ID
+	return true, wh.Update(ctx)
+}
+
+// validateWebhookAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateWebhookAPI(apiObj *github.Hook) error {
+	return validateAPIObject("GitHub.Hook", func(validator validation.Validator) {
+		if apiObj.ID == nil {
+			validator.Required("ID")
+		}
+		if _, ok := apiObj.Config[hookConfigURL].(string); !ok {
+			validator.Required("Config.url")
+		}
+	})
+}
+
+func webhookFromAPI(apiObj *github.Hook) gitprovider.WebhookInfo {
+	url, _ := apiObj.Config[hookConfigURL].(string)
+	info := gitprovider.WebhookInfo{
+		ID:  apiObj.GetID(),
+		URL: url,
+		// GitHub defaults to form-encoded payloads
+		ContentType:     gitprovider.WebhookContentTypeVar(gitprovider.WebhookContentTypeForm),
+		SSLVerification: gitprovider.BoolVar(fmt.Sprint(apiObj.Config[hookConfigInsecureSSL]) != "1"),
+		Active:          gitprovider.BoolVar(apiObj.GetActive()),
+	}
+	if contentType, ok := apiObj.Config[hookConfigContentType].(string); ok && len(contentType) != 0 {
+		info.ContentType = gitprovider.WebhookContentTypeVar(gitprovider.WebhookContentType(contentType))
+	}
+	// Ignore the events that can't be expressed as a WebhookEvent
+	for _, apiEvent := range apiObj.Events {
+		for event, name := range webhookEvents {
+			if apiEvent == name {
+				info.Events = append(info.Events, event)
+				break
+			}
+		}
+	}
+	return info
+}
+
+func webhookInfoToAPIObj(info *gitprovider.WebhookInfo, apiObj *github.Hook) {
+	if apiObj.Config == nil {
+		apiObj.Config = map[string]interface{}{}
+	}
+	// Required fields, we assume info is validated, and hence these are set
+	apiObj.Config[hookConfigURL] = info.URL
+	// The secret is write-only, send it if set, and remove it otherwise
+	if len(info.Secret) != 0 {
+		apiObj.Config[hookConfigSecret] = info.Secret
+	} else {
+		delete(apiObj.Config, hookConfigSecret)
+	}
+	// optional fields
+	if info.ContentType != nil {
+		apiObj.Config[hookConfigContentType] = string(*info.ContentType)
+	}
+	if info.SSLVerification != nil {
+		apiObj.Config[hookConfigInsecureSSL] = "0"
+		if !*info.SSLVerification {
+			apiObj.Config[hookConfigInsecureSSL] = "1"
+		}
+	}
+	if len(info.Events) != 0 {
+		apiObj.Events = make([]string, 0, len(info.Events))
+		for _, event := range info.Events {
+			apiObj.Events = append(apiObj.Events, webhookEvents[event])
+		}
+	}
+	if info.Active != nil {
+		apiObj.Active = info.Active
+	}
+}
+
+// webhookToRequest returns the request for creating or editing a webhook like apiObj, which
+// only contains the fields GitHub accepts.
+func webhookToRequest(apiObj *github.Hook) *github.Hook {
+	config := make(map[string]interface{}, len(apiObj.Config))
+	for key, value := range apiObj.Config {
+		config[key] = value
+	}
+	// GitHub masks the secret of received webhooks, don't send that back as the secret
+	if config[hookConfigSecret] == maskedHookSecret {
+		delete(config, hookConfigSecret)
+	}
+	return &github.Hook{
+		Config: config,
+		Events: apiObj.Events,
+		Active: apiObj.Active,
+	}
+}
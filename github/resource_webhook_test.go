@@ -0,0 +1,92 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package github
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/go-github/v32/github"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+func Test_webhookFromAPI(t *testing.T) {
+	tests := []struct {
+		name   string
+		apiObj *github.Hook
+		want   gitprovider.WebhookInfo
+	}{
+		{
+			name: "defaults",
+			apiObj: &github.Hook{
+				ID:     github.Int64(1),
+				Active: github.Bool(true),
+				Events: []string{"push"},
+				Config: map[string]interface{}{"url": "https://example.com", "insecure_ssl": "0", "secret": maskedHookSecret},
+			},
+			want: gitprovider.WebhookInfo{
+				ID:              1,
+				URL:             "https://example.com",
+				ContentType:     gitprovider.WebhookContentTypeVar(gitprovider.WebhookContentTypeForm),
+				Events:          []gitprovider.WebhookEvent{gitprovider.WebhookEventPush},
+				SSLVerification: gitprovider.BoolVar(true),
+				Active:          gitprovider.BoolVar(true),
+			},
+		},
+		{
+			name: "unknown events are ignored",
+			apiObj: &github.Hook{
+				ID:     github.Int64(2),
+				Active: github.Bool(false),
+				Events: []string{"issue_comment", "watch", "create"},
+				Config: map[string]interface{}{"url": "https://example.com", "content_type": "json", "insecure_ssl": "1"},
+			},
+			want: gitprovider.WebhookInfo{
+				ID:              2,
+				URL:             "https://example.com",
+				ContentType:     gitprovider.WebhookContentTypeVar(gitprovider.WebhookContentTypeJSON),
+				Events:          []gitprovider.WebhookEvent{gitprovider.WebhookEventComment, gitprovider.WebhookEventTag},
+				SSLVerification: gitprovider.BoolVar(false),
+				Active:          gitprovider.BoolVar(false),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := webhookFromAPI(tt.apiObj); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("webhookFromAPI() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_webhookToRequest(t *testing.T) {
+	apiObj := &github.Hook{ID: github.Int64(1), Config: map[string]interface{}{"url": "https://example.com", "secret": maskedHookSecret}}
+	info := webhookFromAPI(apiObj)
+	info.Events = []gitprovider.WebhookEvent{gitprovider.WebhookEventTag}
+	webhookInfoToAPIObj(&info, apiObj)
+
+	want := &github.Hook{
+		Config: map[string]interface{}{"url": "https://example.com", "content_type": "form", "insecure_ssl": "0"},
+		Events: []string{"create"},
+		Active: github.Bool(false),
+	}
+	if got := webhookToRequest(apiObj); !reflect.DeepEqual(got, want) {
+		t.Errorf("webhookToRequest() = %+v, want %+v", got, want)
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/google/go-github/v32/github"
 
@@ -30,6 +31,20 @@ import (
 const (
 	alreadyExistsMagicString = "name already exists on this account"
 	rateLimitDocURL          = "https://developer.github.com/v3/#rate-limiting"
+	// refAlreadyExistsMessage, refNotFoundMessage and refNotFastForwardMessage are the messages
+	// of the "422 Unprocessable Entity" responses of the git refs API.
+	refAlreadyExistsMessage  = "Reference already exists"
+	refNotFoundMessage       = "Reference does not exist"
+	refNotFastForwardMessage = "Update is not a fast forward"
+	// alreadyExistsCode is the code of the validation errors of "422 Unprocessable Entity"
+	// responses for existing resources, e.g. a release of a tag or a release asset name.
+	alreadyExistsCode = "already_exists"
+	// commitNotFoundMessagePrefix is the prefix of the message of the "422 Unprocessable Entity"
+	// responses of the commit status API for missing commits, followed by the sha.
+	commitNotFoundMessagePrefix = "No commit found for SHA"
+	// defaultAssetMediaType is the media type of uploaded release assets with an unknown
+	// file extension.
+	defaultAssetMediaType = "application/octet-stream"
 )
 
 // TODO: Guard better against nil pointer dereference panics in this package, also
@@ -134,7 +149,7 @@ func handleHTTPError(err error) error {
 		}
 		// Check for already exists errors
 		for _, validationErr := range ghErrorResponse.Errors {
-			if validationErr.Message == alreadyExistsMagicString {
+			if validationErr.Message == alreadyExistsMagicString || validationErr.Code == alreadyExistsCode {
 				return validation.NewMultiError(err, gitprovider.ErrAlreadyExists)
 			}
 		}
@@ -145,6 +160,35 @@ func handleHTTPError(err error) error {
 	return err
 }
 
+// handleRefError is like handleHTTPError, but also maps the "422 Unprocessable Entity" responses
+// of the git refs API for existing and missing refs, and for non-forced updates of refs that
+// moved, to ErrAlreadyExists, ErrNotFound and ErrConflict.
+func handleRefError(err error) error {
+	ghErrorResponse := &github.ErrorResponse{}
+	if errors.As(err, &ghErrorResponse) && ghErrorResponse.Response.StatusCode == http.StatusUnprocessableEntity {
+		switch ghErrorResponse.Message {
+		case refAlreadyExistsMessage:
+			return validation.NewMultiError(err, gitprovider.ErrAlreadyExists)
+		case refNotFoundMessage:
+			return validation.NewMultiError(err, gitprovider.ErrNotFound)
+		case refNotFastForwardMessage:
+			return validation.NewMultiError(err, gitprovider.ErrConflict)
+		}
+	}
+	return handleHTTPError(err)
+}
+
+// handleCommitStatusError is like handleHTTPError, but also maps the "422 Unprocessable Entity"
+// responses of the commit status API for missing commits to ErrNotFound.
+func handleCommitStatusError(err error) error {
+	ghErrorResponse := &github.ErrorResponse{}
+	if errors.As(err, &ghErrorResponse) && ghErrorResponse.Response.StatusCode == http.StatusUnprocessableEntity &&
+		strings.HasPrefix(ghErrorResponse.Message, commitNotFoundMessagePrefix) {
+		return validation.NewMultiError(err, gitprovider.ErrNotFound)
+	}
+	return handleHTTPError(err)
+}
+
 // allPages runs fn for each page, expecting a HTTP request to be made and returned during that call.
 // allPages expects that the data is saved in fn to an outer variable.
 // allPages calls fn as many times as needed to get all pages, and modifies opts for each call.
@@ -22,6 +22,8 @@ import (
 
 	"github.com/fluxcd/go-git-providers/gitprovider"
 	"github.com/fluxcd/go-git-providers/gitprovider/cache"
+	"github.com/fluxcd/go-git-providers/gitprovider/ratelimit"
+	"github.com/fluxcd/go-git-providers/gitprovider/retry"
 	gogitlab "github.com/xanzy/go-gitlab"
 	"golang.org/x/oauth2"
 )
@@ -46,8 +48,23 @@ type clientOptions struct {
 	// AuthTransport is a ChainableRoundTripperFunc adding authentication credentials to the transport chain.
 	AuthTransport gitprovider.ChainableRoundTripperFunc
 
+	// credential is the token of the client, namespacing the keys of CacheBackend.
+	credential string
+
 	// EnableConditionalRequests will be set if conditional requests should be used.
 	EnableConditionalRequests *bool
+
+	// CacheBackend stores the responses if conditional requests are enabled.
+	// Default: nil (in memory)
+	CacheBackend cache.Backend
+
+	// RetryOptions will be set if transient and rate-limited failures should be retried.
+	// Default: nil (no retries)
+	RetryOptions *retry.Options
+
+	// RateLimiter throttles requests according to the quota reported by the server.
+	// Default: a ratelimit.NewObserver() only observing the quota
+	RateLimiter *ratelimit.Limiter
 }
 
 // ApplyToGitlabClientOptions implements ClientOption, and applies the set fields of opts
@@ -64,6 +81,7 @@ func (opts *clientOptions) ApplyToGitlabClientOptions(target *clientOptions) err
 			return fmt.Errorf("option AuthTransport already configured: %w", gitprovider.ErrInvalidClientOptions)
 		}
 		target.AuthTransport = opts.AuthTransport
+		target.credential = opts.credential
 	}
 
 	if opts.EnableConditionalRequests != nil {
@@ -73,6 +91,30 @@ func (opts *clientOptions) ApplyToGitlabClientOptions(target *clientOptions) err
 		}
 		target.EnableConditionalRequests = opts.EnableConditionalRequests
 	}
+
+	if opts.CacheBackend != nil {
+		// Make sure the user didn't specify the CacheBackend twice
+		if target.CacheBackend != nil {
+			return fmt.Errorf("option CacheBackend already configured: %w", gitprovider.ErrInvalidClientOptions)
+		}
+		target.CacheBackend = opts.CacheBackend
+	}
+
+	if opts.RetryOptions != nil {
+		// Make sure the user didn't specify the RetryOptions twice
+		if target.RetryOptions != nil {
+			return fmt.Errorf("option RetryOptions already configured: %w", gitprovider.ErrInvalidClientOptions)
+		}
+		target.RetryOptions = opts.RetryOptions
+	}
+
+	if opts.RateLimiter != nil {
+		// Make sure the user didn't specify the RateLimiter twice
+		if target.RateLimiter != nil {
+			return fmt.Errorf("option RateLimiter already configured: %w", gitprovider.ErrInvalidClientOptions)
+		}
+		target.RateLimiter = opts.RateLimiter
+	}
 	return nil
 }
 
@@ -82,13 +124,23 @@ func (opts *clientOptions) getTransportChain() (chain []gitprovider.ChainableRou
 	if opts.PostChainTransportHook != nil {
 		chain = append(chain, opts.PostChainTransportHook)
 	}
+	if opts.RateLimiter != nil {
+		chain = append(chain, opts.RateLimiter.Transport)
+	}
+	if opts.RetryOptions != nil {
+		chain = append(chain, retry.NewRetryTransport(*opts.RetryOptions))
+	}
 	if opts.AuthTransport != nil {
 		chain = append(chain, opts.AuthTransport)
 	}
 	if opts.EnableConditionalRequests != nil && *opts.EnableConditionalRequests {
 		// TODO: Provide some kind of debug logging if/when the httpcache is used
 		// One can see if the request hit the cache using: resp.Header[httpcache.XFromCache]
-		chain = append(chain, cache.NewHTTPCacheTransport)
+		if opts.CacheBackend != nil {
+			chain = append(chain, cache.NewHTTPCacheTransportFunc(cache.ForCredential(opts.CacheBackend, opts.credential)))
+		} else {
+			chain = append(chain, cache.NewHTTPCacheTransport)
+		}
 	}
 	if opts.PreChainTransportHook != nil {
 		chain = append(chain, opts.PreChainTransportHook)
@@ -162,7 +214,7 @@ func WithOAuth2Token(oauth2Token string) ClientOption {
 		return optionError(fmt.Errorf("oauth2Token cannot be empty: %w", gitprovider.ErrInvalidClientOptions))
 	}
 
-	return &clientOptions{AuthTransport: oauth2Transport(oauth2Token)}
+	return &clientOptions{AuthTransport: oauth2Transport(oauth2Token), credential: oauth2Token}
 }
 
 func oauth2Transport(oauth2Token string) gitprovider.ChainableRoundTripperFunc {
@@ -184,6 +236,42 @@ func WithConditionalRequests(conditionalRequests bool) ClientOption {
 	return &clientOptions{EnableConditionalRequests: &conditionalRequests}
 }
 
+// WithConditionalRequestsBackend is like WithConditionalRequests(true), but stores the cached responses
+// in the given backend, e.g. cache.NewDiskBackend to survive restarts, cache.NewLRUBackend to bound the
+// memory usage, or cache.NewExternalBackend to share the cache between replicas. The built-in backends
+// report hit/miss statistics through cache.StatsReporter. The keys are namespaced by a hash of the token
+// (see cache.ForCredential), so clients using different tokens can share a backend without seeing each
+// other's responses.
+func WithConditionalRequestsBackend(backend cache.Backend) ClientOption {
+	// Don't allow an empty value
+	if backend == nil {
+		return optionError(fmt.Errorf("backend cannot be nil: %w", gitprovider.ErrInvalidClientOptions))
+	}
+
+	return &clientOptions{EnableConditionalRequests: gitprovider.BoolVar(true), CacheBackend: backend}
+}
+
+// WithRetry instructs the client to retry requests failing because of transient errors, like
+// 502, 503 and 504 responses or connection resets, and because of rate limiting, with exponential
+// backoff. Non-idempotent requests (POST and PATCH) are only retried when rejected by rate limiting.
+// The built-in retries of go-gitlab are disabled, so that this is the only layer retrying requests.
+// Zero fields of opts are replaced by their defaults. See retry.NewRetryTransport for more information.
+func WithRetry(opts retry.Options) ClientOption {
+	return &clientOptions{RetryOptions: &opts}
+}
+
+// WithRateLimiter instructs the client to throttle requests using the given ratelimit.Limiter, before
+// the quota reported by the server is exhausted. The limiter may be shared by all clients using the
+// same token, in order to share the quota. See ratelimit.NewLimiter for more information.
+func WithRateLimiter(limiter *ratelimit.Limiter) ClientOption {
+	// Don't allow an empty value
+	if limiter == nil {
+		return optionError(fmt.Errorf("limiter cannot be nil: %w", gitprovider.ErrInvalidClientOptions))
+	}
+
+	return &clientOptions{RateLimiter: limiter}
+}
+
 // makeOptions assembles a clientOptions struct from ClientOption mutator functions.
 func makeOptions(opts ...ClientOption) (*clientOptions, error) {
 	o := &clientOptions{}
@@ -206,23 +294,40 @@ func NewClient(token string, tokenType string, optFns ...ClientOption) (gitprovi
 		return nil, err
 	}
 
+	// Namespace the keys of a shared cache backend by the token
+	if opts.credential == "" {
+		opts.credential = token
+	}
+
+	// Observe the quota, even if requests shouldn't be throttled
+	if opts.RateLimiter == nil {
+		opts.RateLimiter = ratelimit.NewObserver()
+	}
+
 	// Create a *http.Client using the transport chain
 	httpClient, err := gitprovider.BuildClientFromTransportChain(opts.getTransportChain())
 	if err != nil {
 		return nil, err
 	}
 
+	// Let the retry transport decide which requests are retried. go-gitlab would otherwise retry
+	// all methods on top of it, including non-idempotent ones.
+	glOpts := []gogitlab.ClientOptionFunc{gogitlab.WithHTTPClient(httpClient)}
+	if opts.RetryOptions != nil {
+		glOpts = append(glOpts, gogitlab.WithoutRetries())
+	}
+
 	if tokenType == "oauth2" {
 		if opts.Domain == nil || *opts.Domain == DefaultDomain {
 			// No domain set or the default gitlab.com used
 			domain = DefaultDomain
-			gl, err = gogitlab.NewOAuthClient(token, gogitlab.WithHTTPClient(httpClient))
+			gl, err = gogitlab.NewOAuthClient(token, glOpts...)
 			if err != nil {
 				return nil, err
 			}
 		} else {
 			domain = *opts.Domain
-			gl, err = gogitlab.NewOAuthClient(token, gogitlab.WithHTTPClient(httpClient), gogitlab.WithBaseURL(domain))
+			gl, err = gogitlab.NewOAuthClient(token, append(glOpts, gogitlab.WithBaseURL(domain))...)
 			if err != nil {
 				return nil, err
 			}
@@ -231,13 +336,13 @@ func NewClient(token string, tokenType string, optFns ...ClientOption) (gitprovi
 		if opts.Domain == nil || *opts.Domain == DefaultDomain {
 			// No domain set or the default gitlab.com used
 			domain = DefaultDomain
-			gl, err = gogitlab.NewClient(token, gogitlab.WithHTTPClient(httpClient))
+			gl, err = gogitlab.NewClient(token, glOpts...)
 			if err != nil {
 				return nil, err
 			}
 		} else {
 			domain = *opts.Domain
-			gl, err = gogitlab.NewClient(token, gogitlab.WithHTTPClient(httpClient), gogitlab.WithBaseURL(domain))
+			gl, err = gogitlab.NewClient(token, append(glOpts, gogitlab.WithBaseURL(domain))...)
 			if err != nil {
 				return nil, err
 			}
@@ -250,5 +355,5 @@ func NewClient(token string, tokenType string, optFns ...ClientOption) (gitprovi
 		destructiveActions = *opts.EnableDestructiveAPICalls
 	}
 
-	return newClient(gl, domain, sshDomain, destructiveActions), nil
+	return newClient(gl, domain, sshDomain, destructiveActions, opts.RateLimiter), nil
 }
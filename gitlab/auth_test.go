@@ -18,12 +18,18 @@ package gitlab
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/fluxcd/go-git-providers/gitprovider"
 	"github.com/fluxcd/go-git-providers/gitprovider/cache"
+	"github.com/fluxcd/go-git-providers/gitprovider/ratelimit"
+	"github.com/fluxcd/go-git-providers/gitprovider/retry"
 	"github.com/fluxcd/go-git-providers/validation"
+	gogitlab "github.com/xanzy/go-gitlab"
 )
 
 func dummyRoundTripper1(http.RoundTripper) http.RoundTripper { return nil }
@@ -105,7 +111,56 @@ func Test_clientOptions_getTransportChain(t *testing.T) {
 	}
 }
 
+func Test_clientOptions_getTransportChain_retry(t *testing.T) {
+	opts := &clientOptions{
+		CommonClientOptions: gitprovider.CommonClientOptions{
+			PostChainTransportHook: dummyRoundTripper1,
+		},
+		AuthTransport: dummyRoundTripper2,
+		RetryOptions:  &retry.Options{},
+	}
+	// expect: "post chain" <-> "retry" <-> "auth"
+	gotChain := opts.getTransportChain()
+	if len(gotChain) != 3 || !roundTrippersEqual(gotChain[0], dummyRoundTripper1) || !roundTrippersEqual(gotChain[2], dummyRoundTripper2) {
+		t.Errorf("clientOptions.getTransportChain() = %v, want post chain, retry and auth", gotChain)
+	}
+}
+
+func TestNewClient_retryPOSTOnce(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// go-gitlab also probes the rate limit with a GET request, which may be retried
+		if r.Method != http.MethodPost {
+			return
+		}
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	c, err := NewClient("token", "", WithDomain(srv.URL), WithRetry(retry.Options{
+		MinBackoff: time.Millisecond,
+		MaxBackoff: time.Millisecond,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Neither the retry transport nor go-gitlab may retry the non-idempotent POST
+	_, _, err = c.Raw().(*gogitlab.Client).Tags.CreateTag("group/project", &gogitlab.CreateTagOptions{
+		TagName: gogitlab.String("v1.0.0"),
+		Ref:     gogitlab.String("main"),
+	})
+	if err == nil {
+		t.Fatal("expected an error for the 502 response")
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("server got %d POST requests, want 1", got)
+	}
+}
+
 func Test_makeOptions(t *testing.T) {
+	limiter := ratelimit.NewLimiter(ratelimit.Options{})
+	backend := cache.NewMemoryBackend()
 	tests := []struct {
 		name         string
 		opts         []ClientOption
@@ -154,7 +209,7 @@ func Test_makeOptions(t *testing.T) {
 		{
 			name: "WithOAuth2Token",
 			opts: []ClientOption{WithOAuth2Token("foo")},
-			want: &clientOptions{AuthTransport: oauth2Transport("foo")},
+			want: &clientOptions{AuthTransport: oauth2Transport("foo"), credential: "foo"},
 		},
 		{
 			name:         "WithOAuth2Token, empty",
@@ -171,6 +226,46 @@ func Test_makeOptions(t *testing.T) {
 			opts:         []ClientOption{WithConditionalRequests(true), WithConditionalRequests(false)},
 			expectedErrs: []error{gitprovider.ErrInvalidClientOptions},
 		},
+		{
+			name: "WithConditionalRequestsBackend",
+			opts: []ClientOption{WithConditionalRequestsBackend(backend)},
+			want: &clientOptions{EnableConditionalRequests: gitprovider.BoolVar(true), CacheBackend: backend},
+		},
+		{
+			name:         "WithConditionalRequestsBackend, nil",
+			opts:         []ClientOption{WithConditionalRequestsBackend(nil)},
+			expectedErrs: []error{gitprovider.ErrInvalidClientOptions},
+		},
+		{
+			name:         "WithConditionalRequestsBackend, exclusive with WithConditionalRequests",
+			opts:         []ClientOption{WithConditionalRequests(true), WithConditionalRequestsBackend(backend)},
+			expectedErrs: []error{gitprovider.ErrInvalidClientOptions},
+		},
+		{
+			name: "WithRetry",
+			opts: []ClientOption{WithRetry(retry.Options{MaxRetries: 2})},
+			want: &clientOptions{RetryOptions: &retry.Options{MaxRetries: 2}},
+		},
+		{
+			name: "WithRateLimiter",
+			opts: []ClientOption{WithRateLimiter(limiter)},
+			want: &clientOptions{RateLimiter: limiter},
+		},
+		{
+			name:         "WithRateLimiter, nil",
+			opts:         []ClientOption{WithRateLimiter(nil)},
+			expectedErrs: []error{gitprovider.ErrInvalidClientOptions},
+		},
+		{
+			name:         "WithRateLimiter, exclusive",
+			opts:         []ClientOption{WithRateLimiter(limiter), WithRateLimiter(limiter)},
+			expectedErrs: []error{gitprovider.ErrInvalidClientOptions},
+		},
+		{
+			name:         "WithRetry, exclusive",
+			opts:         []ClientOption{WithRetry(retry.Options{}), WithRetry(retry.Options{})},
+			expectedErrs: []error{gitprovider.ErrInvalidClientOptions},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
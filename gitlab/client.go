@@ -22,17 +22,19 @@ import (
 	"net/url"
 
 	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/gitprovider/ratelimit"
 	"github.com/xanzy/go-gitlab"
 )
 
 // ProviderID is the provider ID for GitLab.
 const ProviderID = gitprovider.ProviderID("gitlab")
 
-func newClient(c *gitlab.Client, domain string, sshDomain string, destructiveActions bool) *Client {
+func newClient(c *gitlab.Client, domain string, sshDomain string, destructiveActions bool, rateLimiter *ratelimit.Limiter) *Client {
 	glClient := &gitlabClientImpl{c, destructiveActions}
 	ctx := &clientContext{glClient, domain, sshDomain, destructiveActions}
 	return &Client{
 		clientContext: ctx,
+		rateLimiter:   rateLimiter,
 		orgs: &OrganizationsClient{
 			clientContext: ctx,
 		},
@@ -62,6 +64,8 @@ type Client struct {
 	orgs      *OrganizationsClient
 	orgRepos  *OrgRepositoriesClient
 	userRepos *UserRepositoriesClient
+
+	rateLimiter *ratelimit.Limiter
 }
 
 // SupportedDomain returns the domain endpoint for this client, e.g. "gitlab.com" or
@@ -114,3 +118,14 @@ func (c *Client) UserRepositories() gitprovider.UserRepositoriesClient {
 func (c *Client) HasTokenPermission(ctx context.Context, permission gitprovider.TokenPermission) (bool, error) {
 	return false, gitprovider.ErrNoProviderSupport
 }
+
+// Quota returns the current quota, as observed from the RateLimit-* headers of the previous responses.
+// GitLab has no endpoint for requesting it, and doesn't send these headers if rate limits aren't enabled.
+func (c *Client) Quota(_ context.Context) (gitprovider.Quota, error) {
+	if c.rateLimiter != nil {
+		if quota, ok := c.rateLimiter.Quota(); ok {
+			return quota, nil
+		}
+	}
+	return gitprovider.Quota{}, fmt.Errorf("no rate limit observed: %w", gitprovider.ErrNoProviderSupport)
+}
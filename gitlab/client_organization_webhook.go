@@ -0,0 +1,63 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"context"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// GroupWebhookClient implements the gitprovider.WebhookClient interface.
+var _ gitprovider.WebhookClient = &GroupWebhookClient{}
+
+// GroupWebhookClient operates on the webhooks of a specific group.
+//
+// Group webhooks are only available in the paid GitLab tiers, and aren't supported by this package.
+// Hence, all methods return ErrNoProviderSupport.
+type GroupWebhookClient struct {
+	*clientContext
+	ref gitprovider.OrganizationRef
+}
+
+// Get returns the webhook delivering to the given URL.
+//
+// This is not supported in GitLab.
+func (c *GroupWebhookClient) Get(_ context.Context, _ string) (gitprovider.Webhook, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// List lists all webhooks of the group.
+//
+// This is not supported in GitLab.
+func (c *GroupWebhookClient) List(_ context.Context) ([]gitprovider.Webhook, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// Create creates a webhook with the given specifications.
+//
+// This is not supported in GitLab.
+func (c *GroupWebhookClient) Create(_ context.Context, _ gitprovider.WebhookInfo) (gitprovider.Webhook, error) {
+	return nil, gitprovider.ErrNoProviderSupport
+}
+
+// Reconcile makes sure the given desired state (req) becomes the actual state in the backing Git provider.
+//
+// This is not supported in GitLab.
+func (c *GroupWebhookClient) Reconcile(_ context.Context, _ gitprovider.WebhookInfo) (gitprovider.Webhook, bool, error) {
+	return nil, false, gitprovider.ErrNoProviderSupport
+}
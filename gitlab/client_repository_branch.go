@@ -18,8 +18,8 @@ package gitlab
 
 import (
 	"context"
+
 	"github.com/fluxcd/go-git-providers/gitprovider"
-	"github.com/xanzy/go-gitlab"
 )
 
 // BranchClient implements the gitprovider.BranchClient interface.
@@ -31,17 +31,59 @@ type BranchClient struct {
 	ref gitprovider.RepositoryRef
 }
 
-// Create creates a branch with the given specifications.
-func (c *BranchClient) Create(ctx context.Context, branch, sha string) error {
+// Get returns the branch with the given name.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *BranchClient) Get(ctx context.Context, branch string) (gitprovider.Branch, error) {
+	// GET /projects/{project}/repository/branches/{branch}
+	apiObj, err := c.c.GetBranch(ctx, getRepoPath(c.ref), branch)
+	if err != nil {
+		return nil, err
+	}
+	return newBranch(apiObj), nil
+}
 
-	ref := &gitlab.CreateBranchOptions{
-		Ref:    &sha,
-		Branch: &branch,
+// List lists all branches of the repository.
+//
+// List returns all available branches, using multiple paginated requests if needed.
+func (c *BranchClient) List(ctx context.Context) ([]gitprovider.Branch, error) {
+	// GET /projects/{project}/repository/branches
+	apiObjs, err := c.c.ListBranches(ctx, getRepoPath(c.ref))
+	if err != nil {
+		return nil, err
 	}
 
-	if _, _, err := c.c.Client().Branches.CreateBranch(getRepoPath(c.ref), ref); err != nil {
-		return err
+	branches := make([]gitprovider.Branch, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		branches = append(branches, newBranch(apiObj))
 	}
+	return branches, nil
+}
+
+// Create creates a branch with the given specifications.
+func (c *BranchClient) Create(ctx context.Context, branch, sha string) error {
+	// POST /projects/{project}/repository/branches
+	_, err := c.c.CreateBranch(ctx, getRepoPath(c.ref), branch, sha)
+	return err
+}
+
+// CreateFrom creates a branch starting at the head of another branch, or at a tag.
+//
+// ErrNotFound is returned if from doesn't exist, and ErrAlreadyExists if branch does.
+func (c *BranchClient) CreateFrom(ctx context.Context, branch, from string) (gitprovider.Branch, error) {
+	// POST /projects/{project}/repository/branches
+	apiObj, err := c.c.CreateBranch(ctx, getRepoPath(c.ref), branch, from)
+	if err != nil {
+		return nil, err
+	}
+	return newBranch(apiObj), nil
+}
 
-	return nil
+// Delete deletes the branch with the given name.
+//
+// ErrDestructiveCallDisallowed is returned if the client wasn't set up with destructive
+// API calls enabled. ErrNotFound is returned if the resource does not exist.
+func (c *BranchClient) Delete(ctx context.Context, branch string) error {
+	// DELETE /projects/{project}/repository/branches/{branch}
+	return c.c.DeleteBranch(ctx, getRepoPath(c.ref), branch)
 }
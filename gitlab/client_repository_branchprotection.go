@@ -0,0 +1,151 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"context"
+	"errors"
+
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// BranchProtectionClient implements the gitprovider.BranchProtectionClient interface.
+var _ gitprovider.BranchProtectionClient = &BranchProtectionClient{}
+
+// BranchProtectionClient operates on the branch protection rules for a specific repository.
+// The rules are stored as a protected branch, and an approval rule for the required approvals.
+type BranchProtectionClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// Get returns the protection rules of the branch with the given name.
+//
+// ErrNotFound is returned if the branch isn't protected.
+func (c *BranchProtectionClient) Get(ctx context.Context, branch string) (gitprovider.BranchProtection, error) {
+	return c.get(ctx, branch)
+}
+
+func (c *BranchProtectionClient) get(ctx context.Context, branch string) (*branchProtection, error) {
+	// GET /projects/{project}/protected_branches/{branch}
+	apiObj, err := c.c.GetProtectedBranch(ctx, getRepoPath(c.ref), branch)
+	if err != nil {
+		return nil, err
+	}
+	rule, err := c.getApprovalRule(ctx, branch)
+	if err != nil {
+		return nil, err
+	}
+	bp := newBranchProtection(c, apiObj)
+	if rule != nil {
+		bp.rule = *rule
+	}
+	return bp, nil
+}
+
+// getApprovalRule returns the approval rule holding the required approvals of branch, or nil
+// if there is none.
+func (c *BranchProtectionClient) getApprovalRule(ctx context.Context, branch string) (*gitlab.ProjectApprovalRule, error) {
+	// GET /projects/{project}/approval_rules
+	rules, err := c.c.ListApprovalRules(ctx, getRepoPath(c.ref))
+	if errors.Is(err, gitprovider.ErrNotFound) {
+		// Approval rules aren't available in all GitLab editions, hence no approvals are required
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		if rule.Name == approvalRuleName(branch) {
+			return rule, nil
+		}
+	}
+	return nil, nil
+}
+
+// Reconcile makes sure the given desired state (req) becomes the actual state in the backing Git provider.
+//
+// If req doesn't exist under the hood, it is created (actionTaken == true).
+// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+// If req is already the actual state, this is a no-op (actionTaken == false).
+//
+// ErrNoProviderSupport is returned if req requires status checks, dismisses stale reviews, or
+// allows force-pushes or deletions.
+func (c *BranchProtectionClient) Reconcile(ctx context.Context, req gitprovider.BranchProtectionInfo) (gitprovider.BranchProtection, bool, error) {
+	// First thing, validate and default the request to ensure a valid and fully-populated object
+	// (to minimize any possible diffs between desired and actual state)
+	if err := gitprovider.ValidateAndDefaultInfo(&req); err != nil {
+		return nil, false, err
+	}
+
+	actual, err := c.get(ctx, req.Branch)
+	if err != nil {
+		// Create if not found
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			// GitLab allows protecting branches that don't exist (yet), unlike the other providers
+			// GET /projects/{project}/repository/branches/{branch}
+			if _, err := c.c.GetBranch(ctx, getRepoPath(c.ref), req.Branch); err != nil {
+				return nil, false, err
+			}
+			bp := newBranchProtection(c, &gitlab.ProtectedBranch{})
+			if err := bp.Set(req); err != nil {
+				return nil, false, err
+			}
+			return bp, true, bp.apply(ctx, nil)
+		}
+
+		// Unexpected path, Get should succeed or return NotFound
+		return nil, false, err
+	}
+
+	// If the desired matches the actual state, just return the actual state
+	if req.Equals(actual.Get()) {
+		return actual, false, nil
+	}
+
+	// Populate the desired state to the current-actual object
+	if err := actual.Set(req); err != nil {
+		return actual, false, err
+	}
+	// Apply the desired state by running Update
+	return actual, true, actual.Update(ctx)
+}
+
+// Delete removes the protection rules of the branch with the given name.
+//
+// ErrNotFound is returned if the branch isn't protected.
+func (c *BranchProtectionClient) Delete(ctx context.Context, branch string) error {
+	projectName := getRepoPath(c.ref)
+	// GET /projects/{project}/protected_branches/{branch}
+	if _, err := c.c.GetProtectedBranch(ctx, projectName, branch); err != nil {
+		return err
+	}
+	// An approval rule without protected branches applies to all branches, so delete it first
+	rule, err := c.getApprovalRule(ctx, branch)
+	if err != nil {
+		return err
+	}
+	if rule != nil {
+		// DELETE /projects/{project}/approval_rules/{rule_id}
+		if err := c.c.DeleteApprovalRule(ctx, projectName, rule.ID); err != nil {
+			return err
+		}
+	}
+	// DELETE /projects/{project}/protected_branches/{branch}
+	return c.c.UnprotectBranch(ctx, projectName, branch)
+}
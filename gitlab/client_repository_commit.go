@@ -18,6 +18,7 @@ package gitlab
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/fluxcd/go-git-providers/gitprovider"
@@ -64,35 +65,154 @@ func (c *CommitClient) listPage(ctx context.Context, branch string, perPage, pag
 }
 
 // Create creates a commit with the given specifications.
-func (c *CommitClient) Create(ctx context.Context, branch string, message string, files []gitprovider.CommitFile) (gitprovider.Commit, error) {
+//
+// Files without an Action are deleted if their Content is nil, and otherwise created or
+// updated depending on whether they exist on branch.
+//
+// New branches are created at the head of the StartBranch given in opts, which is passed as
+// the start SHA of the commit. GitLab creates the first commit of empty repositories natively.
+//
+// The Author given in opts is used as the author of the commit. As GitLab always uses the
+// authenticated user as committer, and the time of the commit as dates, ErrNoProviderSupport is
+// returned if opts has a Committer, or an Author with a Date.
+//
+// GitLab always commits on top of the current head of branch, so concurrent pushes are never
+// overwritten. ErrConflict is returned if the head of branch isn't the ExpectedParentSHA given
+// in opts; as GitLab can't check this atomically, the branch may still move right after the check.
+func (c *CommitClient) Create(ctx context.Context, branch string, message string, files []gitprovider.CommitFile, opts ...gitprovider.CommitCreateOption) (gitprovider.Commit, error) {
+	o, err := gitprovider.MakeCommitCreateOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
 
 	if len(files) == 0 {
 		return nil, fmt.Errorf("no files added")
 	}
 
-	fileAction := gitlab.FileCreate
-
-	commitActions := make([]*gitlab.CommitActionOptions, 0)
-	for _, file := range files {
-		filePath := *file.Path
-		fileContent := *file.Content
-		commitActions = append(commitActions, &gitlab.CommitActionOptions{
-			Action:   &fileAction,
-			FilePath: &filePath,
-			Content:  &fileContent,
-		})
-	}
-
-	opts := &gitlab.CreateCommitOptions{
+	createOpts := &gitlab.CreateCommitOptions{
 		Branch:        &branch,
 		CommitMessage: &message,
-		Actions:       commitActions,
+	}
+	if o.Committer != nil {
+		return nil, fmt.Errorf("cannot set the committer: %w", gitprovider.ErrNoProviderSupport)
+	}
+	if o.Author != nil {
+		if !o.Author.Date.IsZero() {
+			return nil, fmt.Errorf("cannot set the author date: %w", gitprovider.ErrNoProviderSupport)
+		}
+		createOpts.AuthorName = &o.Author.Name
+		createOpts.AuthorEmail = &o.Author.Email
 	}
 
-	commit, _, err := c.c.Client().Commits.CreateCommit(getRepoPath(c.ref), opts)
+	// New branches start at the head of the start branch, if given
+	head, exists, err := c.head(ctx, branch)
 	if err != nil {
 		return nil, err
 	}
+	if !exists && o.StartBranch != nil {
+		if head, exists, err = c.head(ctx, *o.StartBranch); err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, fmt.Errorf("branch %q: %w", *o.StartBranch, gitprovider.ErrNotFound)
+		}
+		createOpts.StartSHA = &head
+	}
+	if !exists {
+		// Only the first branch of an empty repository can be created without a start branch
+		branches, err := c.c.ListBranches(ctx, getRepoPath(c.ref))
+		if err != nil {
+			return nil, err
+		}
+		if len(branches) != 0 {
+			return nil, fmt.Errorf("branch %q: %w", branch, gitprovider.ErrNotFound)
+		}
+	}
+	if o.ConflictsWith(head) {
+		return nil, fmt.Errorf("branch %q is at %q, not %q: %w", branch, head, *o.ExpectedParentSHA, gitprovider.ErrConflict)
+	}
+
+	commitActions := make([]*gitlab.CommitActionOptions, 0, len(files))
+	for _, file := range files {
+		if err := file.ValidateInfo(); err != nil {
+			return nil, err
+		}
+
+		// The names of the actions are the same in GitLab
+		action := gitlab.FileAction(file.GetAction())
+		if len(action) == 0 {
+			// The existence of files is checked at the parent of the commit, there are none
+			// in empty repositories
+			action = gitlab.FileCreate
+			if len(head) != 0 {
+				exists, err := c.fileExists(ctx, head, *file.Path)
+				if err != nil {
+					return nil, err
+				}
+				if exists {
+					action = gitlab.FileUpdate
+				}
+			}
+		}
+		commitActions = append(commitActions, commitActionOptions(file, action)...)
+	}
+	createOpts.Actions = commitActions
+
+	// POST /projects/{project}/repository/commits
+	commit, _, err := c.c.Client().Commits.CreateCommit(getRepoPath(c.ref), createOpts, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
 
 	return newCommit(c, commit), nil
 }
+
+// head returns the SHA of the head of branch, and whether branch exists.
+func (c *CommitClient) head(ctx context.Context, branch string) (string, bool, error) {
+	// GET /projects/{project}/repository/branches/{branch}
+	apiObj, err := c.c.GetBranch(ctx, getRepoPath(c.ref), branch)
+	if errors.Is(err, gitprovider.ErrNotFound) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return apiObj.Commit.ID, true, nil
+}
+
+// fileExists returns true if the file at path exists as of ref.
+func (c *CommitClient) fileExists(ctx context.Context, ref, path string) (bool, error) {
+	// HEAD /projects/{project}/repository/files/{file_path}
+	_, _, err := c.c.Client().RepositoryFiles.GetFileMetaData(getRepoPath(c.ref), path, &gitlab.GetFileMetaDataOptions{Ref: &ref}, gitlab.WithContext(ctx))
+	err = handleHTTPError(err)
+	if errors.Is(err, gitprovider.ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// commitActionOptions returns the actions of a commit applying action to file. The mode of a
+// file is changed with a separate "chmod" action, as the other actions don't set it.
+func commitActionOptions(file gitprovider.CommitFile, action gitlab.FileAction) []*gitlab.CommitActionOptions {
+	opts := &gitlab.CommitActionOptions{
+		Action:   &action,
+		FilePath: file.Path,
+		Content:  file.Content,
+	}
+	if action == gitlab.FileMove {
+		opts.PreviousPath = file.PreviousPath
+	}
+	if file.Content != nil && file.Encoding != nil {
+		opts.Encoding = gitlab.String(string(*file.Encoding))
+	}
+	if file.Mode == nil {
+		return []*gitlab.CommitActionOptions{opts}
+	}
+
+	chmod := gitlab.FileAction("chmod")
+	return []*gitlab.CommitActionOptions{opts, {
+		Action:          &chmod,
+		FilePath:        file.Path,
+		ExecuteFilemode: gitlab.Bool(file.IsExecutable()),
+	}}
+}
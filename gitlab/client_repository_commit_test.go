@@ -0,0 +1,78 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+func Test_commitActionOptions(t *testing.T) {
+	create, move, chmod := gitlab.FileCreate, gitlab.FileMove, gitlab.FileAction("chmod")
+	tests := []struct {
+		name   string
+		file   gitprovider.CommitFile
+		action gitlab.FileAction
+		want   []*gitlab.CommitActionOptions
+	}{
+		{
+			name:   "text file",
+			file:   gitprovider.CommitFile{Path: gitprovider.StringVar("foo.txt"), Content: gitprovider.StringVar("foo")},
+			action: gitlab.FileCreate,
+			want: []*gitlab.CommitActionOptions{
+				{Action: &create, FilePath: gitlab.String("foo.txt"), Content: gitlab.String("foo")},
+			},
+		},
+		{
+			name: "executable binary file",
+			file: gitprovider.CommitFile{
+				Path:     gitprovider.StringVar("bin"),
+				Content:  gitprovider.StringVar("AAEC"),
+				Encoding: gitprovider.ContentEncodingVar(gitprovider.ContentEncodingBase64),
+				Mode:     gitprovider.FileModeVar(gitprovider.FileModeExecutable),
+			},
+			action: gitlab.FileCreate,
+			want: []*gitlab.CommitActionOptions{
+				{Action: &create, FilePath: gitlab.String("bin"), Content: gitlab.String("AAEC"), Encoding: gitlab.String("base64")},
+				{Action: &chmod, FilePath: gitlab.String("bin"), ExecuteFilemode: gitlab.Bool(true)},
+			},
+		},
+		{
+			name: "move",
+			file: gitprovider.CommitFile{
+				Action:       gitprovider.CommitFileActionVar(gitprovider.CommitFileActionMove),
+				Path:         gitprovider.StringVar("bar.txt"),
+				PreviousPath: gitprovider.StringVar("foo.txt"),
+			},
+			action: gitlab.FileMove,
+			want: []*gitlab.CommitActionOptions{
+				{Action: &move, FilePath: gitlab.String("bar.txt"), PreviousPath: gitlab.String("foo.txt")},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := commitActionOptions(tt.file, tt.action); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("commitActionOptions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// gitlabTreeTypeBlob is the type of the tree nodes of files.
+const gitlabTreeTypeBlob = "blob"
+
+// FileClient implements the gitprovider.FileClient interface.
+var _ gitprovider.FileClient = &FileClient{}
+
+// FileClient reads the files of a specific repository.
+//
+// Files are read through the repository tree and blobs APIs, as the repository files API
+// doesn't report file modes, and returns the content of large files base64-encoded in JSON.
+type FileClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// Get returns the file at path as of ref, i.e. a branch, tag or commit sha, including its content.
+//
+// ErrNotFound is returned if ref doesn't exist, or if path isn't a file as of ref.
+func (c *FileClient) Get(ctx context.Context, filePath, ref string) (gitprovider.File, error) {
+	filePath = strings.Trim(filePath, "/")
+	dirPath, _ := path.Split(filePath)
+	// GET /projects/{project}/repository/tree
+	nodes, err := c.c.ListTree(ctx, getRepoPath(c.ref), strings.TrimSuffix(dirPath, "/"), ref)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, node := range nodes {
+		if node.Path != filePath || node.Type != gitlabTreeTypeBlob {
+			continue
+		}
+		// GET /projects/{project}/repository/blobs/{sha}/raw
+		content, err := c.c.GetRawBlob(ctx, getRepoPath(c.ref), node.ID)
+		if err != nil {
+			return nil, err
+		}
+		return newFile(node, content), nil
+	}
+	return nil, fmt.Errorf("file %q: %w", filePath, gitprovider.ErrNotFound)
+}
+
+// List lists the files and directories in the directory at dirPath as of ref. GitLab doesn't
+// report the size of files when listing them.
+//
+// ErrNotFound is returned if ref doesn't exist, or if dirPath isn't a directory as of ref.
+func (c *FileClient) List(ctx context.Context, dirPath, ref string) ([]gitprovider.File, error) {
+	// GET /projects/{project}/repository/tree
+	nodes, err := c.c.ListTree(ctx, getRepoPath(c.ref), strings.Trim(dirPath, "/"), ref)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]gitprovider.File, 0, len(nodes))
+	for _, node := range nodes {
+		files = append(files, newFile(node, nil))
+	}
+	return files, nil
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"context"
+
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// IssueClient implements the gitprovider.IssueClient interface.
+var _ gitprovider.IssueClient = &IssueClient{}
+
+// IssueClient operates on the issues for a specific repository. The number of an issue is its
+// IID. Assigning an issue to multiple users requires a paid GitLab tier.
+type IssueClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// Get returns the issue with the given IID.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *IssueClient) Get(ctx context.Context, number int) (gitprovider.Issue, error) {
+	apiObj, err := c.c.GetIssue(ctx, getRepoPath(c.ref), number)
+	if err != nil {
+		return nil, err
+	}
+	return newIssue(c.clientContext, apiObj, c.ref), nil
+}
+
+// List lists the issues of the repository matching the given filters.
+//
+// List returns all available issues, using multiple paginated requests if needed.
+func (c *IssueClient) List(ctx context.Context, opts ...gitprovider.IssueListOption) ([]gitprovider.Issue, error) {
+	o, err := gitprovider.MakeIssueListOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	listOpts := &gitlab.ListProjectIssuesOptions{
+		Labels:           gitlab.Labels(o.Labels),
+		AssigneeUsername: o.Assignee,
+	}
+	if o.State != nil {
+		listOpts.State = gitlab.String(issueStateToAPI(*o.State))
+	}
+	apiObjs, err := c.c.ListIssues(ctx, getRepoPath(c.ref), listOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	issues := make([]gitprovider.Issue, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		issues = append(issues, newIssue(c.clientContext, apiObj, c.ref))
+	}
+	return issues, nil
+}
+
+// Create creates an open issue with the given specifications. req.State is ignored.
+//
+// ErrNotFound is returned if an assignee doesn't exist.
+func (c *IssueClient) Create(ctx context.Context, req gitprovider.IssueInfo) (gitprovider.Issue, error) {
+	// Default and validate the request
+	req.Default()
+	if err := req.ValidateInfo(); err != nil {
+		return nil, err
+	}
+
+	assigneeIDs, err := c.userIDs(ctx, req.Assignees)
+	if err != nil {
+		return nil, err
+	}
+	apiObj, err := c.c.CreateIssue(ctx, getRepoPath(c.ref), &gitlab.CreateIssueOptions{
+		Title:       &req.Title,
+		Description: &req.Body,
+		Labels:      gitlab.Labels(req.Labels),
+		AssigneeIDs: assigneeIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newIssue(c.clientContext, apiObj, c.ref), nil
+}
+
+// Update changes the title, body, labels and/or assignees of the issue with the given IID.
+// nil fields of opts are left unchanged.
+//
+// ErrNotFound is returned if the resource, or an assignee, does not exist.
+func (c *IssueClient) Update(ctx context.Context, number int, opts gitprovider.IssueUpdateOptions) (gitprovider.Issue, error) {
+	updateOpts := &gitlab.UpdateIssueOptions{
+		Title:       opts.Title,
+		Description: opts.Body,
+	}
+	if len(opts.Labels) != 0 {
+		updateOpts.Labels = gitlab.Labels(opts.Labels)
+	} else if opts.Labels != nil {
+		// An empty list of labels is omitted from the request, so explicitly remove the
+		// current labels instead
+		issue, err := c.c.GetIssue(ctx, getRepoPath(c.ref), number)
+		if err != nil {
+			return nil, err
+		}
+		updateOpts.RemoveLabels = issue.Labels
+	}
+	if len(opts.Assignees) != 0 {
+		assigneeIDs, err := c.userIDs(ctx, opts.Assignees)
+		if err != nil {
+			return nil, err
+		}
+		updateOpts.AssigneeIDs = assigneeIDs
+	} else if opts.Assignees != nil {
+		// The ID 0 unassigns all users, as an empty list is omitted from the request
+		updateOpts.AssigneeIDs = []int{0}
+	}
+	return c.update(ctx, number, updateOpts)
+}
+
+// Close closes the issue with the given IID.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *IssueClient) Close(ctx context.Context, number int) (gitprovider.Issue, error) {
+	return c.update(ctx, number, &gitlab.UpdateIssueOptions{StateEvent: gitlab.String("close")})
+}
+
+// Reopen reopens the closed issue with the given IID.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *IssueClient) Reopen(ctx context.Context, number int) (gitprovider.Issue, error) {
+	return c.update(ctx, number, &gitlab.UpdateIssueOptions{StateEvent: gitlab.String("reopen")})
+}
+
+// AddLabels adds the given labels to the issue with the given IID, keeping the existing ones.
+// Missing labels are created by GitLab.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *IssueClient) AddLabels(ctx context.Context, number int, labels []string) (gitprovider.Issue, error) {
+	if len(labels) == 0 {
+		return c.Get(ctx, number)
+	}
+	return c.update(ctx, number, &gitlab.UpdateIssueOptions{AddLabels: gitlab.Labels(labels)})
+}
+
+// RemoveLabels removes the given labels from the issue with the given IID. Labels the issue
+// doesn't have are ignored.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *IssueClient) RemoveLabels(ctx context.Context, number int, labels []string) (gitprovider.Issue, error) {
+	if len(labels) == 0 {
+		return c.Get(ctx, number)
+	}
+	return c.update(ctx, number, &gitlab.UpdateIssueOptions{RemoveLabels: gitlab.Labels(labels)})
+}
+
+func (c *IssueClient) update(ctx context.Context, number int, opts *gitlab.UpdateIssueOptions) (gitprovider.Issue, error) {
+	apiObj, err := c.c.UpdateIssue(ctx, getRepoPath(c.ref), number, opts)
+	if err != nil {
+		return nil, err
+	}
+	return newIssue(c.clientContext, apiObj, c.ref), nil
+}
+
+// userIDs returns the IDs of the users with the given usernames, as GitLab assigns issues by
+// user ID.
+func (c *IssueClient) userIDs(ctx context.Context, usernames []string) ([]int, error) {
+	if len(usernames) == 0 {
+		return nil, nil
+	}
+	ids := make([]int, 0, len(usernames))
+	for _, username := range usernames {
+		user, err := c.c.GetUserByUsername(ctx, username)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, user.ID)
+	}
+	return ids, nil
+}
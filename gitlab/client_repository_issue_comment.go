@@ -0,0 +1,80 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"context"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// IssueCommentClient implements the gitprovider.IssueCommentClient interface.
+var _ gitprovider.IssueCommentClient = &IssueCommentClient{}
+
+// IssueCommentClient operates on the notes of a specific issue.
+type IssueCommentClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+	iid int
+}
+
+// List all notes of the issue, oldest first. System notes, e.g. about changed labels, are left
+// out.
+//
+// List returns all available notes, using multiple paginated requests if needed.
+func (c *IssueCommentClient) List(ctx context.Context) ([]gitprovider.IssueComment, error) {
+	apiObjs, err := c.c.ListIssueNotes(ctx, getRepoPath(c.ref), c.iid)
+	if err != nil {
+		return nil, err
+	}
+
+	comments := make([]gitprovider.IssueComment, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		if apiObj.System {
+			continue
+		}
+		comments = append(comments, newIssueComment(apiObj))
+	}
+	return comments, nil
+}
+
+// Create adds a note with the given body to the issue.
+func (c *IssueCommentClient) Create(ctx context.Context, body string) (gitprovider.IssueComment, error) {
+	apiObj, err := c.c.CreateIssueNote(ctx, getRepoPath(c.ref), c.iid, body)
+	if err != nil {
+		return nil, err
+	}
+	return newIssueComment(apiObj), nil
+}
+
+// Update changes the body of the note with the given ID.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *IssueCommentClient) Update(ctx context.Context, id int64, body string) (gitprovider.IssueComment, error) {
+	apiObj, err := c.c.UpdateIssueNote(ctx, getRepoPath(c.ref), c.iid, int(id), body)
+	if err != nil {
+		return nil, err
+	}
+	return newIssueComment(apiObj), nil
+}
+
+// Delete deletes the note with the given ID.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *IssueCommentClient) Delete(ctx context.Context, id int64) error {
+	return c.c.DeleteIssueNote(ctx, getRepoPath(c.ref), c.iid, int(id))
+}
@@ -18,6 +18,7 @@ package gitlab
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/fluxcd/go-git-providers/gitprovider"
 	"github.com/xanzy/go-gitlab"
@@ -32,6 +33,51 @@ type PullRequestClient struct {
 	ref gitprovider.RepositoryRef
 }
 
+// Get returns the merge request with the given IID.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *PullRequestClient) Get(ctx context.Context, number int) (gitprovider.PullRequest, error) {
+	mr, err := c.c.GetMergeRequest(ctx, getRepoPath(c.ref), number)
+	if err != nil {
+		return nil, err
+	}
+	return newPullRequest(c.clientContext, mr, c.ref), nil
+}
+
+// List lists the merge requests of the repository matching the given filters.
+//
+// List returns all available merge requests, using multiple paginated requests if needed.
+func (c *PullRequestClient) List(ctx context.Context, opts ...gitprovider.PullRequestListOption) ([]gitprovider.PullRequest, error) {
+	o, err := gitprovider.MakePullRequestListOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	listOpts := &gitlab.ListProjectMergeRequestsOptions{
+		State:        gitlab.String("all"),
+		SourceBranch: o.HeadBranch,
+		TargetBranch: o.BaseBranch,
+	}
+	if o.State != nil {
+		listOpts.State = gitlab.String(mergeRequestStateToAPI(*o.State))
+	}
+
+	mrs, err := c.c.ListMergeRequests(ctx, getRepoPath(c.ref), listOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	prs := make([]gitprovider.PullRequest, 0, len(mrs))
+	for _, mr := range mrs {
+		pr := newPullRequest(c.clientContext, mr, c.ref)
+		// Locked merge requests are listed as open, so filter client-side too
+		if o.Matches(pr.Get()) {
+			prs = append(prs, pr)
+		}
+	}
+	return prs, nil
+}
+
 // Create creates a pull request with the given specifications.
 func (c *PullRequestClient) Create(ctx context.Context, title, branch, baseBranch, description string) (gitprovider.PullRequest, error) {
 
@@ -42,10 +88,138 @@ func (c *PullRequestClient) Create(ctx context.Context, title, branch, baseBranc
 		Description:  &description,
 	}
 
-	mr, _, err := c.c.Client().MergeRequests.CreateMergeRequest(getRepoPath(c.ref), prOpts)
+	mr, err := c.c.CreateMergeRequest(ctx, getRepoPath(c.ref), prOpts)
 	if err != nil {
 		return nil, err
 	}
 
-	return newPullRequest(c.clientContext, mr), nil
+	return newPullRequest(c.clientContext, mr, c.ref), nil
+}
+
+// Update changes the title, description, target branch and/or labels of the merge request
+// with the given IID. nil fields of opts are left unchanged.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *PullRequestClient) Update(ctx context.Context, number int, opts gitprovider.PullRequestUpdateOptions) (gitprovider.PullRequest, error) {
+	updateOpts := &gitlab.UpdateMergeRequestOptions{
+		Title:        opts.Title,
+		Description:  opts.Description,
+		TargetBranch: opts.BaseBranch,
+	}
+	if len(opts.Labels) != 0 {
+		updateOpts.Labels = gitlab.Labels(opts.Labels)
+	} else if opts.Labels != nil {
+		// An empty list of labels is omitted from the request, so explicitly remove the
+		// current labels instead
+		mr, err := c.c.GetMergeRequest(ctx, getRepoPath(c.ref), number)
+		if err != nil {
+			return nil, err
+		}
+		updateOpts.RemoveLabels = mr.Labels
+	}
+	return c.update(ctx, number, updateOpts)
+}
+
+// Merge merges the merge request with the given IID. message is the message of the merge or
+// squash commit; an empty message uses the GitLab default.
+//
+// GitLab configures rebasing (fast-forward merges) per project, so MergeMethodRebase returns
+// ErrNoProviderSupport.
+func (c *PullRequestClient) Merge(ctx context.Context, number int, method gitprovider.MergeMethod, message string) error {
+	if err := gitprovider.ValidateMergeMethod(method); err != nil {
+		return fmt.Errorf("merge method %q: %w", method, gitprovider.ErrInvalidArgument)
+	}
+	if method == gitprovider.MergeMethodRebase {
+		return fmt.Errorf("merge method %q: %w", method, gitprovider.ErrNoProviderSupport)
+	}
+
+	opts := &gitlab.AcceptMergeRequestOptions{}
+	if method == gitprovider.MergeMethodSquash {
+		opts.Squash = gitlab.Bool(true)
+		if message != "" {
+			opts.SquashCommitMessage = &message
+		}
+	} else if message != "" {
+		opts.MergeCommitMessage = &message
+	}
+
+	_, err := c.c.AcceptMergeRequest(ctx, getRepoPath(c.ref), number, opts)
+	return err
+}
+
+// Close closes the merge request with the given IID, without merging it.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *PullRequestClient) Close(ctx context.Context, number int) (gitprovider.PullRequest, error) {
+	return c.update(ctx, number, &gitlab.UpdateMergeRequestOptions{StateEvent: gitlab.String("close")})
+}
+
+// Reconcile makes sure the given desired state (req) becomes the actual state in the backing Git provider.
+// The merge request is looked up by req.HeadBranch and req.BaseBranch among the open merge
+// requests; the title, description and labels of req are reconciled.
+//
+// If req doesn't exist under the hood, it is created (actionTaken == true).
+// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+// If req is already the actual state, this is a no-op (actionTaken == false).
+func (c *PullRequestClient) Reconcile(ctx context.Context, req gitprovider.PullRequestInfo) (gitprovider.PullRequest, bool, error) {
+	// First thing, validate the request
+	if err := req.ValidateInfo(); err != nil {
+		return nil, false, err
+	}
+
+	// Look for an open merge request from the source into the target branch
+	prs, err := c.List(ctx, &gitprovider.PullRequestListOptions{
+		State:      gitprovider.PullRequestStateVar(gitprovider.PullRequestStateOpen),
+		HeadBranch: &req.HeadBranch,
+		BaseBranch: &req.BaseBranch,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Create if not found, including the labels
+	if len(prs) == 0 {
+		mr, err := c.c.CreateMergeRequest(ctx, getRepoPath(c.ref), &gitlab.CreateMergeRequestOptions{
+			Title:        &req.Title,
+			SourceBranch: &req.HeadBranch,
+			TargetBranch: &req.BaseBranch,
+			Description:  &req.Description,
+			Labels:       gitlab.Labels(req.Labels),
+		})
+		if err != nil {
+			return nil, true, err
+		}
+		return newPullRequest(c.clientContext, mr, c.ref), true, nil
+	}
+
+	// If the desired matches the actual state, just return the actual state
+	actual := prs[0]
+	if req.Equals(actual.Get()) {
+		return actual, false, nil
+	}
+
+	// Apply the desired state by running Update
+	resp, err := c.Update(ctx, actual.Get().Number, gitprovider.PullRequestUpdateOptions{
+		Title:       &req.Title,
+		Description: &req.Description,
+		// Use a non-nil slice, so that labels are removed if none are desired
+		Labels: append([]string{}, req.Labels...),
+	})
+	return resp, true, err
+}
+
+func (c *PullRequestClient) update(ctx context.Context, number int, opts *gitlab.UpdateMergeRequestOptions) (gitprovider.PullRequest, error) {
+	mr, err := c.c.UpdateMergeRequest(ctx, getRepoPath(c.ref), number, opts)
+	if err != nil {
+		return nil, err
+	}
+	return newPullRequest(c.clientContext, mr, c.ref), nil
+}
+
+// mergeRequestStateToAPI maps a PullRequestState to the merge request state filter.
+func mergeRequestStateToAPI(state gitprovider.PullRequestState) string {
+	if state == gitprovider.PullRequestStateOpen {
+		return "opened"
+	}
+	return string(state)
 }
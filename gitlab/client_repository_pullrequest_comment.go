@@ -0,0 +1,80 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"context"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// PullRequestCommentClient implements the gitprovider.PullRequestCommentClient interface.
+var _ gitprovider.PullRequestCommentClient = &PullRequestCommentClient{}
+
+// PullRequestCommentClient operates on the notes of a specific merge request.
+type PullRequestCommentClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+	iid int
+}
+
+// List all notes of the merge request, oldest first. System notes, e.g. about pushed commits,
+// are left out.
+//
+// List returns all available notes, using multiple paginated requests if needed.
+func (c *PullRequestCommentClient) List(ctx context.Context) ([]gitprovider.PullRequestComment, error) {
+	apiObjs, err := c.c.ListMergeRequestNotes(ctx, getRepoPath(c.ref), c.iid)
+	if err != nil {
+		return nil, err
+	}
+
+	comments := make([]gitprovider.PullRequestComment, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		if apiObj.System {
+			continue
+		}
+		comments = append(comments, newPullRequestComment(apiObj))
+	}
+	return comments, nil
+}
+
+// Create adds a note with the given body to the merge request.
+func (c *PullRequestCommentClient) Create(ctx context.Context, body string) (gitprovider.PullRequestComment, error) {
+	apiObj, err := c.c.CreateMergeRequestNote(ctx, getRepoPath(c.ref), c.iid, body)
+	if err != nil {
+		return nil, err
+	}
+	return newPullRequestComment(apiObj), nil
+}
+
+// Update changes the body of the note with the given ID.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *PullRequestCommentClient) Update(ctx context.Context, id int64, body string) (gitprovider.PullRequestComment, error) {
+	apiObj, err := c.c.UpdateMergeRequestNote(ctx, getRepoPath(c.ref), c.iid, int(id), body)
+	if err != nil {
+		return nil, err
+	}
+	return newPullRequestComment(apiObj), nil
+}
+
+// Delete deletes the note with the given ID.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *PullRequestCommentClient) Delete(ctx context.Context, id int64) error {
+	return c.c.DeleteMergeRequestNote(ctx, getRepoPath(c.ref), c.iid, int(id))
+}
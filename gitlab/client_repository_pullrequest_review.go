@@ -0,0 +1,82 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// PullRequestReviewClient implements the gitprovider.PullRequestReviewClient interface.
+var _ gitprovider.PullRequestReviewClient = &PullRequestReviewClient{}
+
+// PullRequestReviewClient operates on the approvals of a specific merge request. GitLab doesn't
+// have reviews, so approvals and notes are used instead.
+type PullRequestReviewClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+	iid int
+}
+
+// List lists the approvals of the merge request. Reviews only commenting are notes, which are
+// listed by PullRequest.Comments().
+func (c *PullRequestReviewClient) List(ctx context.Context) ([]gitprovider.PullRequestReview, error) {
+	apiObj, err := c.c.GetMergeRequestApprovals(ctx, getRepoPath(c.ref), c.iid)
+	if err != nil {
+		return nil, err
+	}
+
+	reviews := make([]gitprovider.PullRequestReview, 0, len(apiObj.ApprovedBy))
+	for _, approver := range apiObj.ApprovedBy {
+		reviews = append(reviews, newApprovalReview(approver, nil))
+	}
+	return reviews, nil
+}
+
+// Submit approves the merge request, or adds a note to it. A non-empty body is added as a note
+// when approving.
+//
+// GitLab can't request changes, so PullRequestReviewStateChangesRequested returns
+// ErrNoProviderSupport.
+func (c *PullRequestReviewClient) Submit(ctx context.Context, state gitprovider.PullRequestReviewState, body string) (gitprovider.PullRequestReview, error) {
+	switch state {
+	case gitprovider.PullRequestReviewStateApproved:
+		if _, err := c.c.ApproveMergeRequest(ctx, getRepoPath(c.ref), c.iid); err != nil {
+			return nil, err
+		}
+		if body == "" {
+			return newApprovalReview(nil, nil), nil
+		}
+		note, err := c.c.CreateMergeRequestNote(ctx, getRepoPath(c.ref), c.iid, body)
+		if err != nil {
+			return nil, err
+		}
+		return newApprovalReview(nil, note), nil
+	case gitprovider.PullRequestReviewStateCommented:
+		note, err := c.c.CreateMergeRequestNote(ctx, getRepoPath(c.ref), c.iid, body)
+		if err != nil {
+			return nil, err
+		}
+		return newNoteReview(note), nil
+	case gitprovider.PullRequestReviewStateChangesRequested:
+		return nil, fmt.Errorf("review state %q: %w", state, gitprovider.ErrNoProviderSupport)
+	default:
+		return nil, fmt.Errorf("review state %q: %w", state, gitprovider.ErrInvalidArgument)
+	}
+}
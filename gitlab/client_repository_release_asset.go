@@ -0,0 +1,121 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"context"
+	"errors"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/xanzy/go-gitlab"
+)
+
+// ReleaseAssetClient implements the gitprovider.ReleaseAssetClient interface.
+var _ gitprovider.ReleaseAssetClient = &ReleaseAssetClient{}
+
+// ReleaseAssetClient operates on the assets of a specific release. GitLab release assets are
+// links, so uploaded files are stored as project uploads, and linked from the release.
+type ReleaseAssetClient struct {
+	*clientContext
+	ref     gitprovider.RepositoryRef
+	tagName string
+}
+
+// List lists all assets of the release.
+//
+// List returns all available assets, using multiple paginated requests if needed.
+func (c *ReleaseAssetClient) List(ctx context.Context) ([]gitprovider.ReleaseAsset, error) {
+	// GET /projects/{project}/releases/{tag_name}/assets/links
+	apiObjs, err := c.c.ListReleaseLinks(ctx, getRepoPath(c.ref), c.tagName)
+	if err != nil {
+		return nil, err
+	}
+
+	assets := make([]gitprovider.ReleaseAsset, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		assets = append(assets, newReleaseAsset(apiObj))
+	}
+	return assets, nil
+}
+
+// Upload uploads the content to the project, and links it from the release with the given name.
+//
+// ErrAlreadyExists is returned if the release already has an asset with the given name.
+func (c *ReleaseAssetClient) Upload(ctx context.Context, name string, content []byte) (gitprovider.ReleaseAsset, error) {
+	// Check the name first, to not leave an unlinked upload behind
+	_, err := c.getByName(ctx, name)
+	if err == nil {
+		return nil, gitprovider.ErrAlreadyExists
+	} else if !errors.Is(err, gitprovider.ErrNotFound) {
+		return nil, err
+	}
+
+	// POST /projects/{project}/uploads
+	file, err := c.c.UploadFile(ctx, getRepoPath(c.ref), name, content)
+	if err != nil {
+		return nil, err
+	}
+	// The URL of the upload is relative to the web URL of the project
+	// POST /projects/{project}/releases/{tag_name}/assets/links
+	apiObj, err := c.c.CreateReleaseLink(ctx, getRepoPath(c.ref), c.tagName, name, c.ref.String()+file.URL)
+	if err != nil {
+		return nil, err
+	}
+	return newReleaseAsset(apiObj), nil
+}
+
+// Download returns the content of the asset with the given name.
+//
+// ErrNotFound is returned if the resource does not exist. Only files on the GitLab server can
+// be downloaded, ErrInvalidArgument is returned for links to other hosts.
+func (c *ReleaseAssetClient) Download(ctx context.Context, name string) ([]byte, error) {
+	apiObj, err := c.getByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	// GET {url}
+	return c.c.DownloadFile(ctx, apiObj.URL)
+}
+
+// Delete removes the link with the given name from the release. Uploaded files can't be
+// deleted through the API, and are kept.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *ReleaseAssetClient) Delete(ctx context.Context, name string) error {
+	apiObj, err := c.getByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	// DELETE /projects/{project}/releases/{tag_name}/assets/links/{link_id}
+	return c.c.DeleteReleaseLink(ctx, getRepoPath(c.ref), c.tagName, apiObj.ID)
+}
+
+// getByName returns the link of the release with the given name. Links are addressed by ID in
+// the GitLab API, but names are unique within a release.
+func (c *ReleaseAssetClient) getByName(ctx context.Context, name string) (*gitlab.ReleaseLink, error) {
+	// GET /projects/{project}/releases/{tag_name}/assets/links
+	apiObjs, err := c.c.ListReleaseLinks(ctx, getRepoPath(c.ref), c.tagName)
+	if err != nil {
+		return nil, err
+	}
+	for _, apiObj := range apiObjs {
+		if apiObj.Name == name {
+			return apiObj, nil
+		}
+	}
+	return nil, gitprovider.ErrNotFound
+}
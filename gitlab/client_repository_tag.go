@@ -0,0 +1,87 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"context"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// TagClient implements the gitprovider.TagClient interface.
+var _ gitprovider.TagClient = &TagClient{}
+
+// TagClient operates on the tags for a specific repository.
+type TagClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// Get returns the tag with the given name.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *TagClient) Get(ctx context.Context, name string) (gitprovider.Tag, error) {
+	// GET /projects/{project}/repository/tags/{tag_name}
+	apiObj, err := c.c.GetTag(ctx, getRepoPath(c.ref), name)
+	if err != nil {
+		return nil, err
+	}
+	return newTag(apiObj), nil
+}
+
+// List lists all tags of the repository.
+//
+// List returns all available tags, using multiple paginated requests if needed.
+func (c *TagClient) List(ctx context.Context) ([]gitprovider.Tag, error) {
+	// GET /projects/{project}/repository/tags
+	apiObjs, err := c.c.ListTags(ctx, getRepoPath(c.ref))
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]gitprovider.Tag, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		tags = append(tags, newTag(apiObj))
+	}
+	return tags, nil
+}
+
+// Create creates a tag with the given specifications. The tag is an annotated tag if
+// req.Message is set, and a lightweight tag otherwise.
+//
+// ErrAlreadyExists is returned if the tag already exists.
+func (c *TagClient) Create(ctx context.Context, req gitprovider.TagInfo) (gitprovider.Tag, error) {
+	if err := req.ValidateInfo(); err != nil {
+		return nil, err
+	}
+	// POST /projects/{project}/repository/tags
+	apiObj, err := c.c.CreateTag(ctx, getRepoPath(c.ref), req.Name, req.Sha, req.Message)
+	if err != nil {
+		return nil, err
+	}
+	return newTag(apiObj), nil
+}
+
+// Delete deletes the tag with the given name. The release of the tag, if any, is deleted along
+// with it.
+//
+// ErrDestructiveCallDisallowed is returned if the client wasn't set up with destructive
+// API calls enabled. ErrNotFound is returned if the resource does not exist.
+func (c *TagClient) Delete(ctx context.Context, name string) error {
+	// DELETE /projects/{project}/repository/tags/{tag_name}
+	return c.c.DeleteTag(ctx, getRepoPath(c.ref), name)
+}
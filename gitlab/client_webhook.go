@@ -0,0 +1,161 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"context"
+	"errors"
+
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// WebhookClient implements the gitprovider.WebhookClient interface.
+var _ gitprovider.WebhookClient = &WebhookClient{}
+
+// WebhookClient operates on the webhooks of a specific project.
+type WebhookClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// Get returns the webhook delivering to the given URL.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *WebhookClient) Get(ctx context.Context, url string) (gitprovider.Webhook, error) {
+	return c.get(ctx, url)
+}
+
+func (c *WebhookClient) get(ctx context.Context, url string) (*webhook, error) {
+	webhooks, err := c.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Loop through the webhooks until we find one with the right URL
+	for _, wh := range webhooks {
+		if wh.h.URL == url {
+			return wh, nil
+		}
+	}
+	return nil, gitprovider.ErrNotFound
+}
+
+// List lists all webhooks of the project.
+//
+// List returns all available webhooks, using multiple paginated requests if needed.
+func (c *WebhookClient) List(ctx context.Context) ([]gitprovider.Webhook, error) {
+	whs, err := c.list(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// Cast to the generic []gitprovider.Webhook
+	webhooks := make([]gitprovider.Webhook, 0, len(whs))
+	for _, wh := range whs {
+		webhooks = append(webhooks, wh)
+	}
+	return webhooks, nil
+}
+
+func (c *WebhookClient) list(ctx context.Context) ([]*webhook, error) {
+	// GET /projects/{project}/hooks
+	apiObjs, err := c.c.ListHooks(ctx, getRepoPath(c.ref))
+	if err != nil {
+		return nil, err
+	}
+
+	// Map the api object to our Webhook type
+	webhooks := make([]*webhook, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		// apiObj is already validated at ListHooks
+		webhooks = append(webhooks, newWebhook(c, apiObj))
+	}
+	return webhooks, nil
+}
+
+// Create creates a webhook with the given specifications.
+//
+// ErrAlreadyExists will be returned if a webhook delivering to req.URL already exists.
+// GitLab has no release events in this API version, always delivers active webhooks as JSON,
+// so ErrNoProviderSupport is returned if req contains a release event, form-encoded payloads
+// or an inactive webhook.
+func (c *WebhookClient) Create(ctx context.Context, req gitprovider.WebhookInfo) (gitprovider.Webhook, error) {
+	// First thing, validate and default the request to ensure a valid and fully-populated object
+	// (to minimize any possible diffs between desired and actual state)
+	if err := gitprovider.ValidateAndDefaultInfo(&req); err != nil {
+		return nil, err
+	}
+	if err := validateWebhookSupport(&req); err != nil {
+		return nil, err
+	}
+	// GitLab allows multiple webhooks with the same URL, but they are identified by it here
+	if _, err := c.get(ctx, req.URL); err == nil {
+		return nil, gitprovider.ErrAlreadyExists
+	} else if !errors.Is(err, gitprovider.ErrNotFound) {
+		return nil, err
+	}
+
+	apiObj := &gitlab.ProjectHook{}
+	webhookInfoToAPIObj(&req, apiObj)
+	// POST /projects/{project}/hooks
+	apiObj, err := c.c.CreateHook(ctx, getRepoPath(c.ref), webhookToAPI(apiObj, req.Secret))
+	if err != nil {
+		return nil, err
+	}
+	return newWebhook(c, apiObj), nil
+}
+
+// Reconcile makes sure the given desired state (req) becomes the actual state in the backing Git provider.
+// The webhook is looked up by req.URL.
+//
+// If req doesn't exist under the hood, it is created (actionTaken == true).
+// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+// If req is already the actual state, this is a no-op (actionTaken == false).
+//
+// ErrNoProviderSupport is returned if req contains a release event, form-encoded payloads or
+// an inactive webhook.
+func (c *WebhookClient) Reconcile(ctx context.Context, req gitprovider.WebhookInfo) (gitprovider.Webhook, bool, error) {
+	// First thing, validate and default the request to ensure a valid and fully-populated object
+	// (to minimize any possible diffs between desired and actual state)
+	if err := gitprovider.ValidateAndDefaultInfo(&req); err != nil {
+		return nil, false, err
+	}
+
+	actual, err := c.get(ctx, req.URL)
+	if err != nil {
+		// Create if not found
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			resp, err := c.Create(ctx, req)
+			return resp, true, err
+		}
+
+		// Unexpected path, Get should succeed or return NotFound
+		return nil, false, err
+	}
+
+	// If the desired matches the actual state, just return the actual state
+	if req.Equals(actual.Get()) {
+		return actual, false, nil
+	}
+
+	// Populate the desired state to the current-actual object
+	if err := actual.Set(req); err != nil {
+		return actual, false, err
+	}
+	// Apply the desired state by running Update
+	return actual, true, actual.Update(ctx)
+}
@@ -0,0 +1,56 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/gitprovider/conformance"
+)
+
+// TestConformance runs the conformance suite against gitlab.com. It is skipped unless
+// GITLAB_TOKEN is set, and uses the same group, team and user as the integration tests.
+func TestConformance(t *testing.T) {
+	token := os.Getenv("GITLAB_TOKEN")
+	if len(token) == 0 {
+		t.Skip("GITLAB_TOKEN is not set")
+	}
+	org, team, user := "fluxcd-testing", "fluxcd-testing-2", "fluxcd-gitprovider-bot"
+	if orgName := os.Getenv("GIT_PROVIDER_ORGANIZATION"); len(orgName) != 0 {
+		org = orgName
+	}
+	if teamName := os.Getenv("GITLAB_TEST_TEAM_NAME"); len(teamName) != 0 {
+		team = teamName
+	}
+	if userName := os.Getenv("GIT_PROVIDER_USER"); len(userName) != 0 {
+		user = userName
+	}
+
+	conformance.Run(t, conformance.Config{
+		NewClient: func(destructive bool) (gitprovider.Client, error) {
+			return NewClient(token, "", WithDomain(gitlabDomain), WithDestructiveAPICalls(destructive))
+		},
+		Organization: newOrgRef(org),
+		User:         newUserRef(user),
+		Team:         team,
+		Unsupported:  []conformance.Feature{conformance.FeatureTokenPermission, conformance.FeatureDraftReleases, conformance.FeatureOrganizationWebhooks},
+		Eventually:   30 * time.Second,
+	})
+}
@@ -17,8 +17,13 @@ limitations under the License.
 package gitlab
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/fluxcd/go-git-providers/gitprovider"
@@ -88,6 +93,19 @@ type gitlabClient interface {
 	// This function handles HTTP error wrapping.
 	DeleteKey(ctx context.Context, projectName string, keyID int) error
 
+	// ListHooks is a wrapper for "GET /projects/{project}/hooks".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListHooks(ctx context.Context, projectName string) ([]*gitlab.ProjectHook, error)
+	// CreateHook is a wrapper for "POST /projects/{project}/hooks".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateHook(ctx context.Context, projectName string, opts *gitlab.AddProjectHookOptions) (*gitlab.ProjectHook, error)
+	// EditHook is a wrapper for "PUT /projects/{project}/hooks/{hook_id}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	EditHook(ctx context.Context, projectName string, hookID int, opts *gitlab.EditProjectHookOptions) (*gitlab.ProjectHook, error)
+	// DeleteHook is a wrapper for "DELETE /projects/{project}/hooks/{hook_id}".
+	// This function handles HTTP error wrapping.
+	DeleteHook(ctx context.Context, projectName string, hookID int) error
+
 	// Team related methods
 
 	// ShareGroup is a wrapper for ""
@@ -102,6 +120,186 @@ type gitlabClient interface {
 	// ListCommitsPage is a wrapper for "GET /projects/{project}/repository/commits".
 	// This function handles pagination, HTTP error wrapping.
 	ListCommitsPage(ctx context.Context, projectName, branch string, perPage int, page int) ([]*gitlab.Commit, error)
+
+	// Branch methods
+
+	// GetBranch is a wrapper for "GET /projects/{project}/repository/branches/{branch}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetBranch(ctx context.Context, projectName, branch string) (*gitlab.Branch, error)
+	// ListBranches is a wrapper for "GET /projects/{project}/repository/branches".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListBranches(ctx context.Context, projectName string) ([]*gitlab.Branch, error)
+	// CreateBranch is a wrapper for "POST /projects/{project}/repository/branches".
+	// ref can be a commit SHA, a branch or a tag name, and is resolved by the server.
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateBranch(ctx context.Context, projectName, branch, ref string) (*gitlab.Branch, error)
+	// DeleteBranch is a wrapper for "DELETE /projects/{project}/repository/branches/{branch}".
+	// This function handles HTTP error wrapping.
+	// DANGEROUS COMMAND: In order to use this, you must set destructiveActions to true.
+	DeleteBranch(ctx context.Context, projectName, branch string) error
+
+	// ListTree is a wrapper for "GET /projects/{project}/repository/tree", listing the directory
+	// at dirPath as of ref.
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListTree(ctx context.Context, projectName, dirPath, ref string) ([]*gitlab.TreeNode, error)
+	// GetRawBlob is a wrapper for "GET /projects/{project}/repository/blobs/{sha}/raw".
+	// This function handles HTTP error wrapping.
+	GetRawBlob(ctx context.Context, projectName, sha string) ([]byte, error)
+
+	// Protected branch and approval rule methods
+
+	// GetProtectedBranch is a wrapper for "GET /projects/{project}/protected_branches/{branch}".
+	// This function handles HTTP error wrapping.
+	GetProtectedBranch(ctx context.Context, projectName, branch string) (*gitlab.ProtectedBranch, error)
+	// ProtectBranch is a wrapper for "POST /projects/{project}/protected_branches".
+	// This function handles HTTP error wrapping.
+	ProtectBranch(ctx context.Context, projectName string, opts *gitlab.ProtectRepositoryBranchesOptions) (*gitlab.ProtectedBranch, error)
+	// RequireCodeOwnerApprovals is a wrapper for "PATCH /projects/{project}/protected_branches/{branch}".
+	// This function handles HTTP error wrapping.
+	RequireCodeOwnerApprovals(ctx context.Context, projectName, branch string, required bool) error
+	// UnprotectBranch is a wrapper for "DELETE /projects/{project}/protected_branches/{branch}".
+	// This function handles HTTP error wrapping.
+	UnprotectBranch(ctx context.Context, projectName, branch string) error
+	// ListApprovalRules is a wrapper for "GET /projects/{project}/approval_rules".
+	// This function handles HTTP error wrapping.
+	ListApprovalRules(ctx context.Context, projectName string) ([]*gitlab.ProjectApprovalRule, error)
+	// CreateApprovalRule is a wrapper for "POST /projects/{project}/approval_rules".
+	// This function handles HTTP error wrapping.
+	CreateApprovalRule(ctx context.Context, projectName string, opts *gitlab.CreateProjectLevelRuleOptions) (*gitlab.ProjectApprovalRule, error)
+	// UpdateApprovalRule is a wrapper for "PUT /projects/{project}/approval_rules/{rule_id}".
+	// This function handles HTTP error wrapping.
+	UpdateApprovalRule(ctx context.Context, projectName string, ruleID int, opts *gitlab.UpdateProjectLevelRuleOptions) (*gitlab.ProjectApprovalRule, error)
+	// DeleteApprovalRule is a wrapper for "DELETE /projects/{project}/approval_rules/{rule_id}".
+	// This function handles HTTP error wrapping.
+	DeleteApprovalRule(ctx context.Context, projectName string, ruleID int) error
+
+	// Tag and release methods
+
+	// ListTags is a wrapper for "GET /projects/{project}/repository/tags".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListTags(ctx context.Context, projectName string) ([]*gitlab.Tag, error)
+	// GetTag is a wrapper for "GET /projects/{project}/repository/tags/{tag_name}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetTag(ctx context.Context, projectName, tag string) (*gitlab.Tag, error)
+	// CreateTag is a wrapper for "POST /projects/{project}/repository/tags".
+	// The tag is an annotated tag if message is set. ref can be a commit SHA or a branch name.
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateTag(ctx context.Context, projectName, tag, ref, message string) (*gitlab.Tag, error)
+	// DeleteTag is a wrapper for "DELETE /projects/{project}/repository/tags/{tag_name}".
+	// This function handles HTTP error wrapping.
+	// DANGEROUS COMMAND: In order to use this, you must set destructiveActions to true.
+	DeleteTag(ctx context.Context, projectName, tag string) error
+	// ListReleases is a wrapper for "GET /projects/{project}/releases".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListReleases(ctx context.Context, projectName string) ([]*gitlab.Release, error)
+	// GetRelease is a wrapper for "GET /projects/{project}/releases/{tag_name}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetRelease(ctx context.Context, projectName, tag string) (*gitlab.Release, error)
+	// CreateRelease is a wrapper for "POST /projects/{project}/releases".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateRelease(ctx context.Context, projectName string, opts *gitlab.CreateReleaseOptions) (*gitlab.Release, error)
+	// UpdateRelease is a wrapper for "PUT /projects/{project}/releases/{tag_name}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	UpdateRelease(ctx context.Context, projectName, tag string, opts *gitlab.UpdateReleaseOptions) (*gitlab.Release, error)
+	// DeleteRelease is a wrapper for "DELETE /projects/{project}/releases/{tag_name}".
+	// This function handles HTTP error wrapping.
+	DeleteRelease(ctx context.Context, projectName, tag string) error
+	// ListReleaseLinks is a wrapper for "GET /projects/{project}/releases/{tag_name}/assets/links".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListReleaseLinks(ctx context.Context, projectName, tag string) ([]*gitlab.ReleaseLink, error)
+	// CreateReleaseLink is a wrapper for "POST /projects/{project}/releases/{tag_name}/assets/links".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateReleaseLink(ctx context.Context, projectName, tag, name, linkURL string) (*gitlab.ReleaseLink, error)
+	// DeleteReleaseLink is a wrapper for "DELETE /projects/{project}/releases/{tag_name}/assets/links/{link_id}".
+	// This function handles HTTP error wrapping.
+	DeleteReleaseLink(ctx context.Context, projectName, tag string, id int) error
+	// UploadFile is a wrapper for "POST /projects/{project}/uploads", uploading content as a
+	// file with the given name.
+	// This function handles HTTP error wrapping.
+	UploadFile(ctx context.Context, projectName, name string, content []byte) (*gitlab.ProjectFile, error)
+	// DownloadFile fetches the given absolute URL on the GitLab server with the credentials of
+	// the client, e.g. to download a file uploaded with UploadFile. ErrInvalidArgument is
+	// returned for URLs on other hosts.
+	// This function handles HTTP error wrapping.
+	DownloadFile(ctx context.Context, fileURL string) ([]byte, error)
+
+	// ListCommitStatuses is a wrapper for "GET /projects/{project}/repository/commits/{sha}/statuses".
+	// Only the latest status of each name is returned, unless all is true.
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListCommitStatuses(ctx context.Context, projectName, sha string, all bool) ([]*gitlab.CommitStatus, error)
+	// SetCommitStatus is a wrapper for "POST /projects/{project}/statuses/{sha}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	SetCommitStatus(ctx context.Context, projectName, sha string, opts *gitlab.SetCommitStatusOptions) (*gitlab.CommitStatus, error)
+
+	// Merge request methods
+
+	// GetMergeRequest is a wrapper for "GET /projects/{project}/merge_requests/{merge_request_iid}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetMergeRequest(ctx context.Context, projectName string, iid int) (*gitlab.MergeRequest, error)
+	// ListMergeRequests is a wrapper for "GET /projects/{project}/merge_requests".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListMergeRequests(ctx context.Context, projectName string, opts *gitlab.ListProjectMergeRequestsOptions) ([]*gitlab.MergeRequest, error)
+	// CreateMergeRequest is a wrapper for "POST /projects/{project}/merge_requests".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateMergeRequest(ctx context.Context, projectName string, opts *gitlab.CreateMergeRequestOptions) (*gitlab.MergeRequest, error)
+	// UpdateMergeRequest is a wrapper for "PUT /projects/{project}/merge_requests/{merge_request_iid}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	UpdateMergeRequest(ctx context.Context, projectName string, iid int, opts *gitlab.UpdateMergeRequestOptions) (*gitlab.MergeRequest, error)
+	// AcceptMergeRequest is a wrapper for "PUT /projects/{project}/merge_requests/{merge_request_iid}/merge".
+	// This function handles HTTP error wrapping, and validates the server result.
+	AcceptMergeRequest(ctx context.Context, projectName string, iid int, opts *gitlab.AcceptMergeRequestOptions) (*gitlab.MergeRequest, error)
+
+	// Merge request note and approval methods
+
+	// ListMergeRequestNotes is a wrapper for "GET /projects/{project}/merge_requests/{merge_request_iid}/notes".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListMergeRequestNotes(ctx context.Context, projectName string, iid int) ([]*gitlab.Note, error)
+	// CreateMergeRequestNote is a wrapper for "POST /projects/{project}/merge_requests/{merge_request_iid}/notes".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateMergeRequestNote(ctx context.Context, projectName string, iid int, body string) (*gitlab.Note, error)
+	// UpdateMergeRequestNote is a wrapper for "PUT /projects/{project}/merge_requests/{merge_request_iid}/notes/{note_id}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	UpdateMergeRequestNote(ctx context.Context, projectName string, iid, noteID int, body string) (*gitlab.Note, error)
+	// DeleteMergeRequestNote is a wrapper for "DELETE /projects/{project}/merge_requests/{merge_request_iid}/notes/{note_id}".
+	// This function handles HTTP error wrapping.
+	DeleteMergeRequestNote(ctx context.Context, projectName string, iid, noteID int) error
+	// GetMergeRequestApprovals is a wrapper for "GET /projects/{project}/merge_requests/{merge_request_iid}/approvals".
+	// This function handles HTTP error wrapping.
+	GetMergeRequestApprovals(ctx context.Context, projectName string, iid int) (*gitlab.MergeRequestApprovals, error)
+	// ApproveMergeRequest is a wrapper for "POST /projects/{project}/merge_requests/{merge_request_iid}/approve".
+	// This function handles HTTP error wrapping.
+	ApproveMergeRequest(ctx context.Context, projectName string, iid int) (*gitlab.MergeRequestApprovals, error)
+
+	// Issue methods
+
+	// GetIssue is a wrapper for "GET /projects/{project}/issues/{issue_iid}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetIssue(ctx context.Context, projectName string, iid int) (*gitlab.Issue, error)
+	// ListIssues is a wrapper for "GET /projects/{project}/issues".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListIssues(ctx context.Context, projectName string, opts *gitlab.ListProjectIssuesOptions) ([]*gitlab.Issue, error)
+	// CreateIssue is a wrapper for "POST /projects/{project}/issues".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateIssue(ctx context.Context, projectName string, opts *gitlab.CreateIssueOptions) (*gitlab.Issue, error)
+	// UpdateIssue is a wrapper for "PUT /projects/{project}/issues/{issue_iid}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	UpdateIssue(ctx context.Context, projectName string, iid int, opts *gitlab.UpdateIssueOptions) (*gitlab.Issue, error)
+	// ListIssueNotes is a wrapper for "GET /projects/{project}/issues/{issue_iid}/notes".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListIssueNotes(ctx context.Context, projectName string, iid int) ([]*gitlab.Note, error)
+	// CreateIssueNote is a wrapper for "POST /projects/{project}/issues/{issue_iid}/notes".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateIssueNote(ctx context.Context, projectName string, iid int, body string) (*gitlab.Note, error)
+	// UpdateIssueNote is a wrapper for "PUT /projects/{project}/issues/{issue_iid}/notes/{note_id}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	UpdateIssueNote(ctx context.Context, projectName string, iid, noteID int, body string) (*gitlab.Note, error)
+	// DeleteIssueNote is a wrapper for "DELETE /projects/{project}/issues/{issue_iid}/notes/{note_id}".
+	// This function handles HTTP error wrapping.
+	DeleteIssueNote(ctx context.Context, projectName string, iid, noteID int) error
+	// GetUserByUsername is a wrapper for "GET /users?username={username}".
+	// ErrNotFound is returned if there is no user with the given username.
+	// This function handles HTTP error wrapping.
+	GetUserByUsername(ctx context.Context, username string) (*gitlab.User, error)
 }
 
 // gitlabClientImpl is a wrapper around *gitlab.Client, which implements higher-level methods,
@@ -373,6 +571,57 @@ func (c *gitlabClientImpl) DeleteKey(ctx context.Context, projectName string, ke
 	return handleHTTPError(err)
 }
 
+func (c *gitlabClientImpl) ListHooks(ctx context.Context, projectName string) ([]*gitlab.ProjectHook, error) {
+	apiObjs := []*gitlab.ProjectHook{}
+	opts := &gitlab.ListProjectHooksOptions{}
+	err := allHookPages(opts, func() (*gitlab.Response, error) {
+		// GET /projects/{project}/hooks
+		pageObjs, resp, listErr := c.c.Projects.ListProjectHooks(projectName, opts, gitlab.WithContext(ctx))
+		apiObjs = append(apiObjs, pageObjs...)
+		return resp, listErr
+	})
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+
+	for _, apiObj := range apiObjs {
+		if err := validateWebhookAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *gitlabClientImpl) CreateHook(ctx context.Context, projectName string, opts *gitlab.AddProjectHookOptions) (*gitlab.ProjectHook, error) {
+	// POST /projects/{project}/hooks
+	apiObj, _, err := c.c.Projects.AddProjectHook(projectName, opts, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validateWebhookAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *gitlabClientImpl) EditHook(ctx context.Context, projectName string, hookID int, opts *gitlab.EditProjectHookOptions) (*gitlab.ProjectHook, error) {
+	// PUT /projects/{project}/hooks/{hook_id}
+	apiObj, _, err := c.c.Projects.EditProjectHook(projectName, hookID, opts, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validateWebhookAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *gitlabClientImpl) DeleteHook(ctx context.Context, projectName string, hookID int) error {
+	// DELETE /projects/{project}/hooks/{hook_id}
+	_, err := c.c.Projects.DeleteProjectHook(projectName, hookID, gitlab.WithContext(ctx))
+	return handleHTTPError(err)
+}
+
 func (c *gitlabClientImpl) ShareProject(ctx context.Context, projectName string, groupIDObj, groupAccessObj int) error {
 	groupAccess := gitlab.AccessLevel(gitlab.AccessLevelValue(groupAccessObj))
 	groupID := &groupIDObj
@@ -403,14 +652,668 @@ func (c *gitlabClientImpl) ListCommitsPage(ctx context.Context, projectName stri
 
 	// GET /projects/{id}/repository/commits
 	pageObjs, _, listErr := c.c.Commits.ListCommits(projectName, &opts)
-	for _, c := range pageObjs {
-		apiObjs = append(apiObjs, &gitlab.Commit{
-			ID: c.ID,
-		})
-	}
+	apiObjs = append(apiObjs, pageObjs...)
 
 	if listErr != nil {
 		return nil, listErr
 	}
 	return apiObjs, nil
 }
+
+func (c *gitlabClientImpl) GetBranch(ctx context.Context, projectName, branch string) (*gitlab.Branch, error) {
+	// GET /projects/{project}/repository/branches/{branch}
+	apiObj, _, err := c.c.Branches.GetBranch(projectName, branch, gitlab.WithContext(ctx))
+	return validateBranchAPIResp(apiObj, err)
+}
+
+func (c *gitlabClientImpl) ListBranches(ctx context.Context, projectName string) ([]*gitlab.Branch, error) {
+	apiObjs := []*gitlab.Branch{}
+	opts := &gitlab.ListBranchesOptions{}
+	err := allBranchPages(opts, func() (*gitlab.Response, error) {
+		// GET /projects/{project}/repository/branches
+		pageObjs, resp, listErr := c.c.Branches.ListBranches(projectName, opts, gitlab.WithContext(ctx))
+		apiObjs = append(apiObjs, pageObjs...)
+		return resp, listErr
+	})
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+
+	for _, apiObj := range apiObjs {
+		if err := validateBranchAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *gitlabClientImpl) CreateBranch(ctx context.Context, projectName, branch, ref string) (*gitlab.Branch, error) {
+	opts := &gitlab.CreateBranchOptions{
+		Branch: &branch,
+		Ref:    &ref,
+	}
+	// POST /projects/{project}/repository/branches
+	apiObj, _, err := c.c.Branches.CreateBranch(projectName, opts, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, handleBranchError(err)
+	}
+	if err := validateBranchAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *gitlabClientImpl) DeleteBranch(ctx context.Context, projectName, branch string) error {
+	// Don't allow deleting branches if the user didn't explicitly allow dangerous API calls.
+	if !c.destructiveActions {
+		return fmt.Errorf("cannot delete branch: %w", gitprovider.ErrDestructiveCallDisallowed)
+	}
+	// DELETE /projects/{project}/repository/branches/{branch}
+	_, err := c.c.Branches.DeleteBranch(projectName, branch, gitlab.WithContext(ctx))
+	return handleHTTPError(err)
+}
+
+func (c *gitlabClientImpl) ListTree(ctx context.Context, projectName, dirPath, ref string) ([]*gitlab.TreeNode, error) {
+	apiObjs := []*gitlab.TreeNode{}
+	opts := &gitlab.ListTreeOptions{Ref: &ref}
+	if len(dirPath) != 0 {
+		opts.Path = &dirPath
+	}
+	err := allTreePages(opts, func() (*gitlab.Response, error) {
+		// GET /projects/{project}/repository/tree
+		pageObjs, resp, listErr := c.c.Repositories.ListTree(projectName, opts, gitlab.WithContext(ctx))
+		apiObjs = append(apiObjs, pageObjs...)
+		return resp, listErr
+	})
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+
+	for _, apiObj := range apiObjs {
+		if err := validateTreeNodeAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *gitlabClientImpl) GetRawBlob(ctx context.Context, projectName, sha string) ([]byte, error) {
+	// GET /projects/{project}/repository/blobs/{sha}/raw
+	content, _, err := c.c.Repositories.RawBlobContent(projectName, sha, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	return content, nil
+}
+
+func (c *gitlabClientImpl) GetProtectedBranch(ctx context.Context, projectName, branch string) (*gitlab.ProtectedBranch, error) {
+	// GET /projects/{project}/protected_branches/{branch}
+	apiObj, _, err := c.c.ProtectedBranches.GetProtectedBranch(projectName, branch, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	return apiObj, nil
+}
+
+func (c *gitlabClientImpl) ProtectBranch(ctx context.Context, projectName string, opts *gitlab.ProtectRepositoryBranchesOptions) (*gitlab.ProtectedBranch, error) {
+	// POST /projects/{project}/protected_branches
+	apiObj, _, err := c.c.ProtectedBranches.ProtectRepositoryBranches(projectName, opts, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	return apiObj, nil
+}
+
+func (c *gitlabClientImpl) RequireCodeOwnerApprovals(ctx context.Context, projectName, branch string, required bool) error {
+	opts := &gitlab.RequireCodeOwnerApprovalsOptions{CodeOwnerApprovalRequired: &required}
+	// PATCH /projects/{project}/protected_branches/{branch}
+	_, err := c.c.ProtectedBranches.RequireCodeOwnerApprovals(projectName, branch, opts, gitlab.WithContext(ctx))
+	return handleHTTPError(err)
+}
+
+func (c *gitlabClientImpl) UnprotectBranch(ctx context.Context, projectName, branch string) error {
+	// DELETE /projects/{project}/protected_branches/{branch}
+	_, err := c.c.ProtectedBranches.UnprotectRepositoryBranches(projectName, branch, gitlab.WithContext(ctx))
+	return handleHTTPError(err)
+}
+
+func (c *gitlabClientImpl) ListApprovalRules(ctx context.Context, projectName string) ([]*gitlab.ProjectApprovalRule, error) {
+	// GET /projects/{project}/approval_rules
+	apiObjs, _, err := c.c.Projects.GetProjectApprovalRules(projectName, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	return apiObjs, nil
+}
+
+func (c *gitlabClientImpl) CreateApprovalRule(ctx context.Context, projectName string, opts *gitlab.CreateProjectLevelRuleOptions) (*gitlab.ProjectApprovalRule, error) {
+	// POST /projects/{project}/approval_rules
+	apiObj, _, err := c.c.Projects.CreateProjectApprovalRule(projectName, opts, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	return apiObj, nil
+}
+
+func (c *gitlabClientImpl) UpdateApprovalRule(ctx context.Context, projectName string, ruleID int, opts *gitlab.UpdateProjectLevelRuleOptions) (*gitlab.ProjectApprovalRule, error) {
+	// PUT /projects/{project}/approval_rules/{rule_id}
+	apiObj, _, err := c.c.Projects.UpdateProjectApprovalRule(projectName, ruleID, opts, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	return apiObj, nil
+}
+
+func (c *gitlabClientImpl) DeleteApprovalRule(ctx context.Context, projectName string, ruleID int) error {
+	// DELETE /projects/{project}/approval_rules/{rule_id}
+	_, err := c.c.Projects.DeleteProjectApprovalRule(projectName, ruleID, gitlab.WithContext(ctx))
+	return handleHTTPError(err)
+}
+
+func validateBranchAPIResp(apiObj *gitlab.Branch, err error) (*gitlab.Branch, error) {
+	// If the response contained an error, return
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Make sure apiObj is valid
+	if err := validateBranchAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *gitlabClientImpl) ListTags(ctx context.Context, projectName string) ([]*gitlab.Tag, error) {
+	apiObjs := []*gitlab.Tag{}
+	opts := &gitlab.ListTagsOptions{}
+	err := allTagPages(opts, func() (*gitlab.Response, error) {
+		// GET /projects/{project}/repository/tags
+		pageObjs, resp, listErr := c.c.Tags.ListTags(projectName, opts, gitlab.WithContext(ctx))
+		apiObjs = append(apiObjs, pageObjs...)
+		return resp, listErr
+	})
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+
+	for _, apiObj := range apiObjs {
+		if err := validateTagAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *gitlabClientImpl) GetTag(ctx context.Context, projectName, tag string) (*gitlab.Tag, error) {
+	// GET /projects/{project}/repository/tags/{tag_name}
+	apiObj, _, err := c.c.Tags.GetTag(projectName, tag, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validateTagAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *gitlabClientImpl) CreateTag(ctx context.Context, projectName, tag, ref, message string) (*gitlab.Tag, error) {
+	opts := &gitlab.CreateTagOptions{
+		TagName: &tag,
+		Ref:     &ref,
+	}
+	if len(message) != 0 {
+		opts.Message = &message
+	}
+	// POST /projects/{project}/repository/tags
+	apiObj, _, err := c.c.Tags.CreateTag(projectName, opts, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, handleTagError(err)
+	}
+	if err := validateTagAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *gitlabClientImpl) DeleteTag(ctx context.Context, projectName, tag string) error {
+	// Don't allow deleting tags if the user didn't explicitly allow dangerous API calls.
+	if !c.destructiveActions {
+		return fmt.Errorf("cannot delete tag: %w", gitprovider.ErrDestructiveCallDisallowed)
+	}
+	// DELETE /projects/{project}/repository/tags/{tag_name}
+	_, err := c.c.Tags.DeleteTag(projectName, tag, gitlab.WithContext(ctx))
+	return handleHTTPError(err)
+}
+
+func (c *gitlabClientImpl) ListReleases(ctx context.Context, projectName string) ([]*gitlab.Release, error) {
+	apiObjs := []*gitlab.Release{}
+	opts := &gitlab.ListReleasesOptions{}
+	err := allReleasePages(opts, func() (*gitlab.Response, error) {
+		// GET /projects/{project}/releases
+		pageObjs, resp, listErr := c.c.Releases.ListReleases(projectName, opts, gitlab.WithContext(ctx))
+		apiObjs = append(apiObjs, pageObjs...)
+		return resp, listErr
+	})
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+
+	for _, apiObj := range apiObjs {
+		if err := validateReleaseAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *gitlabClientImpl) GetRelease(ctx context.Context, projectName, tag string) (*gitlab.Release, error) {
+	// GET /projects/{project}/releases/{tag_name}
+	apiObj, _, err := c.c.Releases.GetRelease(projectName, tag, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validateReleaseAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *gitlabClientImpl) CreateRelease(ctx context.Context, projectName string, opts *gitlab.CreateReleaseOptions) (*gitlab.Release, error) {
+	// POST /projects/{project}/releases
+	apiObj, _, err := c.c.Releases.CreateRelease(projectName, opts, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, handleTagError(err)
+	}
+	if err := validateReleaseAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *gitlabClientImpl) UpdateRelease(ctx context.Context, projectName, tag string, opts *gitlab.UpdateReleaseOptions) (*gitlab.Release, error) {
+	// PUT /projects/{project}/releases/{tag_name}
+	apiObj, _, err := c.c.Releases.UpdateRelease(projectName, tag, opts, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validateReleaseAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *gitlabClientImpl) DeleteRelease(ctx context.Context, projectName, tag string) error {
+	// DELETE /projects/{project}/releases/{tag_name}
+	_, _, err := c.c.Releases.DeleteRelease(projectName, tag, gitlab.WithContext(ctx))
+	return handleHTTPError(err)
+}
+
+func (c *gitlabClientImpl) ListReleaseLinks(ctx context.Context, projectName, tag string) ([]*gitlab.ReleaseLink, error) {
+	apiObjs := []*gitlab.ReleaseLink{}
+	opts := &gitlab.ListReleaseLinksOptions{}
+	err := allReleaseLinkPages(opts, func() (*gitlab.Response, error) {
+		// GET /projects/{project}/releases/{tag_name}/assets/links
+		pageObjs, resp, listErr := c.c.ReleaseLinks.ListReleaseLinks(projectName, tag, opts, gitlab.WithContext(ctx))
+		apiObjs = append(apiObjs, pageObjs...)
+		return resp, listErr
+	})
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+
+	for _, apiObj := range apiObjs {
+		if err := validateReleaseLinkAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *gitlabClientImpl) CreateReleaseLink(ctx context.Context, projectName, tag, name, linkURL string) (*gitlab.ReleaseLink, error) {
+	opts := &gitlab.CreateReleaseLinkOptions{
+		Name: &name,
+		URL:  &linkURL,
+	}
+	// POST /projects/{project}/releases/{tag_name}/assets/links
+	apiObj, _, err := c.c.ReleaseLinks.CreateReleaseLink(projectName, tag, opts, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validateReleaseLinkAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *gitlabClientImpl) DeleteReleaseLink(ctx context.Context, projectName, tag string, id int) error {
+	// DELETE /projects/{project}/releases/{tag_name}/assets/links/{link_id}
+	_, _, err := c.c.ReleaseLinks.DeleteReleaseLink(projectName, tag, id, gitlab.WithContext(ctx))
+	return handleHTTPError(err)
+}
+
+func (c *gitlabClientImpl) UploadFile(ctx context.Context, projectName, name string, content []byte) (*gitlab.ProjectFile, error) {
+	// go-gitlab only uploads files from disk, so build the multipart request ourselves
+	b := &bytes.Buffer{}
+	w := multipart.NewWriter(b)
+	fw, err := w.CreateFormFile("file", name)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := fw.Write(content); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	u := fmt.Sprintf("projects/%s/uploads", url.PathEscape(projectName))
+	req, err := c.c.NewRequest(http.MethodPost, u, nil, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return nil, err
+	}
+	req.Body = ioutil.NopCloser(b)
+	req.ContentLength = int64(b.Len())
+	req.Header.Set("Content-Type", w.FormDataContentType())
+
+	// POST /projects/{project}/uploads
+	apiObj := &gitlab.ProjectFile{}
+	if _, err := c.c.Do(req, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	return apiObj, nil
+}
+
+func (c *gitlabClientImpl) DownloadFile(ctx context.Context, fileURL string) ([]byte, error) {
+	u, err := url.Parse(fileURL)
+	if err != nil {
+		return nil, err
+	}
+	// Never send the credentials of the client to other hosts
+	if u.Host != c.c.BaseURL().Host {
+		return nil, fmt.Errorf("cannot download %q from outside of the GitLab server: %w", fileURL, gitprovider.ErrInvalidArgument)
+	}
+	// The request is created for the API base URL, point it to the file instead
+	req, err := c.c.NewRequest(http.MethodGet, "", nil, []gitlab.RequestOptionFunc{gitlab.WithContext(ctx)})
+	if err != nil {
+		return nil, err
+	}
+	req.URL = u
+	req.Host = u.Host
+
+	// GET {fileURL}
+	b := &bytes.Buffer{}
+	if _, err := c.c.Do(req, b); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	return b.Bytes(), nil
+}
+
+func (c *gitlabClientImpl) ListCommitStatuses(ctx context.Context, projectName, sha string, all bool) ([]*gitlab.CommitStatus, error) {
+	apiObjs := []*gitlab.CommitStatus{}
+	opts := &gitlab.GetCommitStatusesOptions{All: &all}
+	err := allCommitStatusPages(opts, func() (*gitlab.Response, error) {
+		// GET /projects/{project}/repository/commits/{sha}/statuses
+		pageObjs, resp, listErr := c.c.Commits.GetCommitStatuses(projectName, sha, opts, gitlab.WithContext(ctx))
+		apiObjs = append(apiObjs, pageObjs...)
+		return resp, listErr
+	})
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+
+	for _, apiObj := range apiObjs {
+		if err := validateCommitStatusAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *gitlabClientImpl) SetCommitStatus(ctx context.Context, projectName, sha string, opts *gitlab.SetCommitStatusOptions) (*gitlab.CommitStatus, error) {
+	// POST /projects/{project}/statuses/{sha}
+	apiObj, _, err := c.c.Commits.SetCommitStatus(projectName, sha, opts, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validateCommitStatusAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *gitlabClientImpl) GetMergeRequest(ctx context.Context, projectName string, iid int) (*gitlab.MergeRequest, error) {
+	// GET /projects/{project}/merge_requests/{merge_request_iid}
+	apiObj, _, err := c.c.MergeRequests.GetMergeRequest(projectName, iid, nil, gitlab.WithContext(ctx))
+	return validateMergeRequestAPIResp(apiObj, err)
+}
+
+func (c *gitlabClientImpl) ListMergeRequests(ctx context.Context, projectName string, opts *gitlab.ListProjectMergeRequestsOptions) ([]*gitlab.MergeRequest, error) {
+	apiObjs := []*gitlab.MergeRequest{}
+	err := allMergeRequestPages(opts, func() (*gitlab.Response, error) {
+		// GET /projects/{project}/merge_requests
+		pageObjs, resp, listErr := c.c.MergeRequests.ListProjectMergeRequests(projectName, opts, gitlab.WithContext(ctx))
+		apiObjs = append(apiObjs, pageObjs...)
+		return resp, listErr
+	})
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+
+	for _, apiObj := range apiObjs {
+		if err := validateMergeRequestAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *gitlabClientImpl) CreateMergeRequest(ctx context.Context, projectName string, opts *gitlab.CreateMergeRequestOptions) (*gitlab.MergeRequest, error) {
+	// POST /projects/{project}/merge_requests
+	apiObj, _, err := c.c.MergeRequests.CreateMergeRequest(projectName, opts, gitlab.WithContext(ctx))
+	return validateMergeRequestAPIResp(apiObj, err)
+}
+
+func (c *gitlabClientImpl) UpdateMergeRequest(ctx context.Context, projectName string, iid int, opts *gitlab.UpdateMergeRequestOptions) (*gitlab.MergeRequest, error) {
+	// PUT /projects/{project}/merge_requests/{merge_request_iid}
+	apiObj, _, err := c.c.MergeRequests.UpdateMergeRequest(projectName, iid, opts, gitlab.WithContext(ctx))
+	return validateMergeRequestAPIResp(apiObj, err)
+}
+
+func (c *gitlabClientImpl) AcceptMergeRequest(ctx context.Context, projectName string, iid int, opts *gitlab.AcceptMergeRequestOptions) (*gitlab.MergeRequest, error) {
+	// PUT /projects/{project}/merge_requests/{merge_request_iid}/merge
+	apiObj, _, err := c.c.MergeRequests.AcceptMergeRequest(projectName, iid, opts, gitlab.WithContext(ctx))
+	return validateMergeRequestAPIResp(apiObj, err)
+}
+
+func validateMergeRequestAPIResp(apiObj *gitlab.MergeRequest, err error) (*gitlab.MergeRequest, error) {
+	// If the response contained an error, return
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Make sure apiObj is valid
+	if err := validateMergeRequestAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *gitlabClientImpl) ListMergeRequestNotes(ctx context.Context, projectName string, iid int) ([]*gitlab.Note, error) {
+	// List the notes oldest first, the default is newest first
+	opts := &gitlab.ListMergeRequestNotesOptions{
+		OrderBy: gitlab.String("created_at"),
+		Sort:    gitlab.String("asc"),
+	}
+	apiObjs := []*gitlab.Note{}
+	err := allMergeRequestNotePages(opts, func() (*gitlab.Response, error) {
+		// GET /projects/{project}/merge_requests/{merge_request_iid}/notes
+		pageObjs, resp, listErr := c.c.Notes.ListMergeRequestNotes(projectName, iid, opts, gitlab.WithContext(ctx))
+		apiObjs = append(apiObjs, pageObjs...)
+		return resp, listErr
+	})
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+
+	for _, apiObj := range apiObjs {
+		if err := validateNoteAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *gitlabClientImpl) CreateMergeRequestNote(ctx context.Context, projectName string, iid int, body string) (*gitlab.Note, error) {
+	// POST /projects/{project}/merge_requests/{merge_request_iid}/notes
+	opts := &gitlab.CreateMergeRequestNoteOptions{Body: &body}
+	apiObj, _, err := c.c.Notes.CreateMergeRequestNote(projectName, iid, opts, gitlab.WithContext(ctx))
+	return validateNoteAPIResp(apiObj, err)
+}
+
+func (c *gitlabClientImpl) UpdateMergeRequestNote(ctx context.Context, projectName string, iid, noteID int, body string) (*gitlab.Note, error) {
+	// PUT /projects/{project}/merge_requests/{merge_request_iid}/notes/{note_id}
+	opts := &gitlab.UpdateMergeRequestNoteOptions{Body: &body}
+	apiObj, _, err := c.c.Notes.UpdateMergeRequestNote(projectName, iid, noteID, opts, gitlab.WithContext(ctx))
+	return validateNoteAPIResp(apiObj, err)
+}
+
+func (c *gitlabClientImpl) DeleteMergeRequestNote(ctx context.Context, projectName string, iid, noteID int) error {
+	// DELETE /projects/{project}/merge_requests/{merge_request_iid}/notes/{note_id}
+	_, err := c.c.Notes.DeleteMergeRequestNote(projectName, iid, noteID, gitlab.WithContext(ctx))
+	return handleHTTPError(err)
+}
+
+func (c *gitlabClientImpl) GetMergeRequestApprovals(ctx context.Context, projectName string, iid int) (*gitlab.MergeRequestApprovals, error) {
+	// GET /projects/{project}/merge_requests/{merge_request_iid}/approvals
+	apiObj, _, err := c.c.MergeRequestApprovals.GetConfiguration(projectName, iid, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	return apiObj, nil
+}
+
+func (c *gitlabClientImpl) ApproveMergeRequest(ctx context.Context, projectName string, iid int) (*gitlab.MergeRequestApprovals, error) {
+	// POST /projects/{project}/merge_requests/{merge_request_iid}/approve
+	apiObj, _, err := c.c.MergeRequestApprovals.ApproveMergeRequest(projectName, iid, nil, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	return apiObj, nil
+}
+
+func validateNoteAPIResp(apiObj *gitlab.Note, err error) (*gitlab.Note, error) {
+	// If the response contained an error, return
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Make sure apiObj is valid
+	if err := validateNoteAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *gitlabClientImpl) GetIssue(ctx context.Context, projectName string, iid int) (*gitlab.Issue, error) {
+	// GET /projects/{project}/issues/{issue_iid}
+	apiObj, _, err := c.c.Issues.GetIssue(projectName, iid, gitlab.WithContext(ctx))
+	return validateIssueAPIResp(apiObj, err)
+}
+
+func (c *gitlabClientImpl) ListIssues(ctx context.Context, projectName string, opts *gitlab.ListProjectIssuesOptions) ([]*gitlab.Issue, error) {
+	apiObjs := []*gitlab.Issue{}
+	err := allIssuePages(opts, func() (*gitlab.Response, error) {
+		// GET /projects/{project}/issues
+		pageObjs, resp, listErr := c.c.Issues.ListProjectIssues(projectName, opts, gitlab.WithContext(ctx))
+		apiObjs = append(apiObjs, pageObjs...)
+		return resp, listErr
+	})
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+
+	for _, apiObj := range apiObjs {
+		if err := validateIssueAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *gitlabClientImpl) CreateIssue(ctx context.Context, projectName string, opts *gitlab.CreateIssueOptions) (*gitlab.Issue, error) {
+	// POST /projects/{project}/issues
+	apiObj, _, err := c.c.Issues.CreateIssue(projectName, opts, gitlab.WithContext(ctx))
+	return validateIssueAPIResp(apiObj, err)
+}
+
+func (c *gitlabClientImpl) UpdateIssue(ctx context.Context, projectName string, iid int, opts *gitlab.UpdateIssueOptions) (*gitlab.Issue, error) {
+	// PUT /projects/{project}/issues/{issue_iid}
+	apiObj, _, err := c.c.Issues.UpdateIssue(projectName, iid, opts, gitlab.WithContext(ctx))
+	return validateIssueAPIResp(apiObj, err)
+}
+
+func (c *gitlabClientImpl) ListIssueNotes(ctx context.Context, projectName string, iid int) ([]*gitlab.Note, error) {
+	// List the notes oldest first, the default is newest first
+	opts := &gitlab.ListIssueNotesOptions{
+		OrderBy: gitlab.String("created_at"),
+		Sort:    gitlab.String("asc"),
+	}
+	apiObjs := []*gitlab.Note{}
+	err := allIssueNotePages(opts, func() (*gitlab.Response, error) {
+		// GET /projects/{project}/issues/{issue_iid}/notes
+		pageObjs, resp, listErr := c.c.Notes.ListIssueNotes(projectName, iid, opts, gitlab.WithContext(ctx))
+		apiObjs = append(apiObjs, pageObjs...)
+		return resp, listErr
+	})
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+
+	for _, apiObj := range apiObjs {
+		if err := validateNoteAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *gitlabClientImpl) CreateIssueNote(ctx context.Context, projectName string, iid int, body string) (*gitlab.Note, error) {
+	// POST /projects/{project}/issues/{issue_iid}/notes
+	opts := &gitlab.CreateIssueNoteOptions{Body: &body}
+	apiObj, _, err := c.c.Notes.CreateIssueNote(projectName, iid, opts, gitlab.WithContext(ctx))
+	return validateNoteAPIResp(apiObj, err)
+}
+
+func (c *gitlabClientImpl) UpdateIssueNote(ctx context.Context, projectName string, iid, noteID int, body string) (*gitlab.Note, error) {
+	// PUT /projects/{project}/issues/{issue_iid}/notes/{note_id}
+	opts := &gitlab.UpdateIssueNoteOptions{Body: &body}
+	apiObj, _, err := c.c.Notes.UpdateIssueNote(projectName, iid, noteID, opts, gitlab.WithContext(ctx))
+	return validateNoteAPIResp(apiObj, err)
+}
+
+func (c *gitlabClientImpl) DeleteIssueNote(ctx context.Context, projectName string, iid, noteID int) error {
+	// DELETE /projects/{project}/issues/{issue_iid}/notes/{note_id}
+	_, err := c.c.Notes.DeleteIssueNote(projectName, iid, noteID, gitlab.WithContext(ctx))
+	return handleHTTPError(err)
+}
+
+func (c *gitlabClientImpl) GetUserByUsername(ctx context.Context, username string) (*gitlab.User, error) {
+	// GET /users?username={username}
+	apiObjs, _, err := c.c.Users.ListUsers(&gitlab.ListUsersOptions{Username: &username}, gitlab.WithContext(ctx))
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if len(apiObjs) == 0 {
+		return nil, fmt.Errorf("user %q: %w", username, gitprovider.ErrNotFound)
+	}
+	return apiObjs[0], nil
+}
+
+func validateIssueAPIResp(apiObj *gitlab.Issue, err error) (*gitlab.Issue, error) {
+	// If the response contained an error, return
+	if err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Make sure apiObj is valid
+	if err := validateIssueAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
@@ -38,6 +38,7 @@ import (
 	"github.com/xanzy/go-gitlab"
 
 	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/gitprovider/recorder"
 	testutils "github.com/fluxcd/go-git-providers/gitprovider/testutils"
 )
 
@@ -49,6 +50,12 @@ const (
 	// gitlabDomain = "https://gitlab.dev.wkp.weave.works"
 	gitlabDomain = "gitlab.com"
 
+	// cassettePath is the cassette used when GIT_PROVIDER_RECORDER_MODE is "record" or "replay".
+	cassettePath = "testdata/cassettes/integration.json"
+	// recorderSeed seeds the random repository and branch names when recording or replaying,
+	// so that the requests match the cassette.
+	recorderSeed = 42
+
 	defaultDescription = "Foo description"
 	defaultBranch      = "master"
 )
@@ -56,6 +63,8 @@ const (
 var (
 	// customTransportImpl is a shared instance of a customTransport, allowing counting of cache hits.
 	customTransportImpl *customTransport
+	// testRecorder records or replays the interactions of the suite, depending on GIT_PROVIDER_RECORDER_MODE.
+	testRecorder *recorder.Recorder
 )
 
 func init() {
@@ -187,16 +196,28 @@ var _ = Describe("GitLab Provider", func() {
 	)
 
 	BeforeSuite(func() {
+		mode, err := recorder.ModeFromEnv()
+		Expect(err).ToNot(HaveOccurred())
+
 		gitlabToken := os.Getenv("GITLAB_TOKEN")
 		if len(gitlabToken) == 0 {
 			b, err := ioutil.ReadFile(ghTokenFile)
 			if token := string(b); err == nil && len(token) != 0 {
 				gitlabToken = token
+			} else if mode == recorder.ModeReplay {
+				// No credentials are needed to replay the cassette
+				gitlabToken = "replay-token"
 			} else {
 				Fail("couldn't acquire GITLAB_TOKEN env variable")
 			}
 		}
 
+		if mode != recorder.ModePassthrough {
+			rand.Seed(recorderSeed)
+		}
+		testRecorder, err = recorder.New(cassettePath, mode, recorder.WithSecrets(gitlabToken))
+		Expect(err).ToNot(HaveOccurred())
+
 		if orgName := os.Getenv("GIT_PROVIDER_ORGANIZATION"); len(orgName) != 0 {
 			testOrgName = orgName
 		}
@@ -213,13 +234,13 @@ var _ = Describe("GitLab Provider", func() {
 			testUserName = gitProviderUser
 		}
 
-		var err error
 		c, err = NewClient(
 			gitlabToken, "",
 			WithDomain(gitlabDomain),
 			WithDestructiveAPICalls(true),
 			WithConditionalRequests(true),
 			WithPreChainTransportHook(customTransportFactory),
+			WithPostChainTransportHook(testRecorder.Transport),
 		)
 		Expect(err).ToNot(HaveOccurred())
 	})
@@ -346,6 +367,17 @@ var _ = Describe("GitLab Provider", func() {
 		Expect(err).ToNot(HaveOccurred())
 	})
 
+	It("should report the rate limit quota, if observed", func() {
+		// The quota is observed from the previous responses, if the instance has rate limits enabled
+		quota, err := c.Quota(ctx)
+		if err != nil {
+			Expect(errors.Is(err, gitprovider.ErrNoProviderSupport)).To(BeTrue())
+			return
+		}
+		Expect(quota.Remaining).To(BeNumerically("<=", quota.Limit))
+		Expect(quota.Reset).ToNot(BeZero())
+	})
+
 	It("should be possible to create a group project", func() {
 		// First, check what repositories are available
 		repos, err := c.OrgRepositories().List(ctx, newOrgRef(testOrgName))
@@ -553,6 +585,45 @@ var _ = Describe("GitLab Provider", func() {
 		Expect(actionTaken).To(Equal(false))
 	})
 
+	It("should reconcile the protection rules of a branch", func() {
+		repoRef := newOrgRepoRef(testOrgName, testOrgRepoName)
+		repo, err := c.OrgRepositories().Get(ctx, repoRef)
+		Expect(err).ToNot(HaveOccurred())
+		defaultBranch := *repo.Get().DefaultBranch
+
+		// GitLab protects the default branch of new projects, only allowing maintainers to push
+		bp, err := repo.BranchProtections().Get(ctx, defaultBranch)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(*bp.Get().RestrictPushes).To(BeTrue())
+
+		req := gitprovider.BranchProtectionInfo{Branch: defaultBranch}
+		bp, actionTaken, err := repo.BranchProtections().Reconcile(ctx, req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(actionTaken).To(BeTrue())
+		Expect(*bp.Get().RestrictPushes).To(BeFalse())
+
+		// Reconciling the actual state is a no-op
+		_, actionTaken, err = repo.BranchProtections().Reconcile(ctx, req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(actionTaken).To(BeFalse())
+
+		// Status checks can't be required for protected branches
+		req.RequiredStatusChecks = []string{"ci/build"}
+		_, _, err = repo.BranchProtections().Reconcile(ctx, req)
+		Expect(errors.Is(err, gitprovider.ErrNoProviderSupport)).To(BeTrue())
+
+		Expect(bp.Delete(ctx)).ToNot(HaveOccurred())
+		_, err = repo.BranchProtections().Get(ctx, defaultBranch)
+		Expect(errors.Is(err, gitprovider.ErrNotFound)).To(BeTrue())
+
+		// Restore the protection GitLab set up for the project
+		req.RequiredStatusChecks = nil
+		req.RestrictPushes = gitprovider.BoolVar(true)
+		_, actionTaken, err = repo.BranchProtections().Reconcile(ctx, req)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(actionTaken).To(BeTrue())
+	})
+
 	It("should create, delete and reconcile deploy keys", func() {
 		testDeployKeyName := "test-deploy-key"
 		repoRef := newOrgRepoRef(testOrgName, testSharedOrgRepoName)
@@ -769,6 +840,21 @@ var _ = Describe("GitLab Provider", func() {
 		err = userRepo.Branches().Create(ctx, branchName2, "wrong-sha")
 		Expect(err).To(HaveOccurred())
 
+		branch, err := userRepo.Branches().Get(ctx, branchName)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(branch.Get().Sha).To(Equal(latestCommit.Get().Sha))
+
+		// Branch off the default branch without knowing its head
+		fromDefault, err := userRepo.Branches().CreateFrom(ctx, branchName+"-from", defaultBranch)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(fromDefault.Get().Sha).To(Equal(latestCommit.Get().Sha))
+		branches, err := userRepo.Branches().List(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(branches).To(HaveLen(3))
+		Expect(userRepo.Branches().Delete(ctx, branchName+"-from")).ToNot(HaveOccurred())
+		_, err = userRepo.Branches().Get(ctx, branchName+"-from")
+		Expect(errors.Is(err, gitprovider.ErrNotFound)).To(BeTrue())
+
 		path := "setup/config.txt"
 		content := "yaml content"
 		files := []gitprovider.CommitFile{
@@ -784,9 +870,55 @@ var _ = Describe("GitLab Provider", func() {
 		pr, err := userRepo.PullRequests().Create(ctx, "Added config file", branchName, defaultBranch, "added config file")
 		Expect(err).ToNot(HaveOccurred())
 		Expect(pr.Get().WebURL).ToNot(BeEmpty())
+		Expect(pr.Get().State).To(Equal(gitprovider.PullRequestStateOpen))
+		Expect(pr.Get().HeadBranch).To(Equal(branchName))
+
+		prs, err := userRepo.PullRequests().List(ctx, &gitprovider.PullRequestListOptions{HeadBranch: &branchName})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(prs).To(HaveLen(1))
+		Expect(prs[0].Get().Number).To(Equal(pr.Get().Number))
+
+		// Reconciling updates the open pull request instead of opening another one
+		reconciled, actionTaken, err := userRepo.PullRequests().Reconcile(ctx, gitprovider.PullRequestInfo{
+			Title:       "Added config file",
+			Description: "added config file",
+			HeadBranch:  branchName,
+			BaseBranch:  defaultBranch,
+			Labels:      []string{"automated"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(actionTaken).To(BeTrue())
+		Expect(reconciled.Get().Number).To(Equal(pr.Get().Number))
+		Expect(reconciled.Get().Labels).To(Equal([]string{"automated"}))
+
+		// Post plan output onto the pull request, and edit it
+		comment, err := reconciled.Comments().Create(ctx, "plan output")
+		Expect(err).ToNot(HaveOccurred())
+		comment, err = reconciled.Comments().Update(ctx, comment.Get().ID, "new plan output")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(comment.Get().Body).To(Equal("new plan output"))
+		comments, err := reconciled.Comments().List(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(comments).To(HaveLen(1))
+		Expect(comments[0].Get().ID).To(Equal(comment.Get().ID))
+		Expect(reconciled.Comments().Delete(ctx, comment.Get().ID)).ToNot(HaveOccurred())
+
+		review, err := reconciled.Reviews().Submit(ctx, gitprovider.PullRequestReviewStateCommented, "status summary")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(review.Get().State).To(Equal(gitprovider.PullRequestReviewStateCommented))
+
+		closed, err := userRepo.PullRequests().Close(ctx, pr.Get().Number)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(closed.Get().State).To(Equal(gitprovider.PullRequestStateClosed))
 	})
 
 	AfterSuite(func() {
+		if testRecorder != nil {
+			defer func() {
+				Expect(testRecorder.Stop()).ToNot(HaveOccurred())
+			}()
+		}
+
 		if os.Getenv("SKIP_CLEANUP") == "1" {
 			return
 		}
@@ -0,0 +1,287 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// approvalRuleName returns the name of the approval rule holding the required approvals of branch.
+func approvalRuleName(branch string) string {
+	return fmt.Sprintf("Protected branch %s", branch)
+}
+
+func newBranchProtection(c *BranchProtectionClient, apiObj *gitlab.ProtectedBranch) *branchProtection {
+	return &branchProtection{
+		pb: *apiObj,
+		c:  c,
+	}
+}
+
+var _ gitprovider.BranchProtection = &branchProtection{}
+
+type branchProtection struct {
+	pb gitlab.ProtectedBranch
+	// rule is the approval rule holding the required approvals; its ID is 0 if it doesn't exist
+	rule gitlab.ProjectApprovalRule
+	c    *BranchProtectionClient
+}
+
+func (bp *branchProtection) Get() gitprovider.BranchProtectionInfo {
+	return branchProtectionFromAPI(&bp.pb, &bp.rule)
+}
+
+func (bp *branchProtection) Set(info gitprovider.BranchProtectionInfo) error {
+	if err := info.ValidateInfo(); err != nil {
+		return err
+	}
+	if err := validateBranchProtectionSupport(info); err != nil {
+		return err
+	}
+	branchProtectionInfoToAPIObj(&info, &bp.pb, &bp.rule)
+	return nil
+}
+
+func (bp *branchProtection) APIObject() interface{} {
+	return &bp.pb
+}
+
+func (bp *branchProtection) Repository() gitprovider.RepositoryRef {
+	return bp.c.ref
+}
+
+// Update will apply the desired state in this object to the server.
+// In order to apply changes to this object, use the .Set({Resource}Info) error
+// function, or cast .APIObject() to a pointer to the provider-specific type
+// and set custom fields there.
+//
+// ErrNotFound is returned if the resource does not exist.
+//
+// The internal API object will be overridden with the received server data.
+func (bp *branchProtection) Update(ctx context.Context) error {
+	// GET /projects/{project}/protected_branches/{branch}
+	actual, err := bp.c.c.GetProtectedBranch(ctx, getRepoPath(bp.c.ref), bp.pb.Name)
+	if err != nil {
+		return err
+	}
+	return bp.apply(ctx, actual)
+}
+
+// apply applies the desired state in this object to the server, given the actual protected
+// branch, or nil if the branch isn't protected.
+func (bp *branchProtection) apply(ctx context.Context, actual *gitlab.ProtectedBranch) error {
+	projectName := getRepoPath(bp.c.ref)
+	var err error
+	switch {
+	case actual == nil:
+		// POST /projects/{project}/protected_branches
+		actual, err = bp.c.c.ProtectBranch(ctx, projectName, protectBranchOptions(&bp.pb, nil))
+	case pushesRestricted(actual) != pushesRestricted(&bp.pb):
+		// The access levels can only be changed by protecting the branch again. In the meantime,
+		// the approval rule applies to all branches, until it is updated below.
+		// DELETE /projects/{project}/protected_branches/{branch}
+		if err := bp.c.c.UnprotectBranch(ctx, projectName, bp.pb.Name); err != nil {
+			return err
+		}
+		// POST /projects/{project}/protected_branches
+		actual, err = bp.c.c.ProtectBranch(ctx, projectName, protectBranchOptions(&bp.pb, actual))
+	case actual.CodeOwnerApprovalRequired != bp.pb.CodeOwnerApprovalRequired:
+		// PATCH /projects/{project}/protected_branches/{branch}
+		err = bp.c.c.RequireCodeOwnerApprovals(ctx, projectName, bp.pb.Name, bp.pb.CodeOwnerApprovalRequired)
+		actual.CodeOwnerApprovalRequired = bp.pb.CodeOwnerApprovalRequired
+	}
+	if err != nil {
+		return err
+	}
+
+	rule, err := bp.applyApprovalRule(ctx, actual.ID)
+	if err != nil {
+		return err
+	}
+	bp.pb = *actual
+	bp.rule = rule
+	return nil
+}
+
+// applyApprovalRule creates, updates or deletes the approval rule of the branch, which is protected
+// with the given ID, to require the desired amount of approvals.
+func (bp *branchProtection) applyApprovalRule(ctx context.Context, protectedBranchID int) (gitlab.ProjectApprovalRule, error) {
+	projectName := getRepoPath(bp.c.ref)
+	actual, err := bp.c.getApprovalRule(ctx, bp.pb.Name)
+	if err != nil {
+		return gitlab.ProjectApprovalRule{}, err
+	}
+
+	desired := bp.rule.ApprovalsRequired
+	var rule *gitlab.ProjectApprovalRule
+	switch {
+	case actual == nil && desired == 0:
+		return gitlab.ProjectApprovalRule{}, nil
+	case actual == nil:
+		// POST /projects/{project}/approval_rules
+		rule, err = bp.c.c.CreateApprovalRule(ctx, projectName, &gitlab.CreateProjectLevelRuleOptions{
+			Name:               gitlab.String(approvalRuleName(bp.pb.Name)),
+			ApprovalsRequired:  &desired,
+			ProtectedBranchIDs: []int{protectedBranchID},
+		})
+	case desired == 0:
+		// DELETE /projects/{project}/approval_rules/{rule_id}
+		return gitlab.ProjectApprovalRule{}, bp.c.c.DeleteApprovalRule(ctx, projectName, actual.ID)
+	case actual.ApprovalsRequired != desired || !approvalRuleProtects(actual, protectedBranchID):
+		// PUT /projects/{project}/approval_rules/{rule_id}
+		rule, err = bp.c.c.UpdateApprovalRule(ctx, projectName, actual.ID, &gitlab.UpdateProjectLevelRuleOptions{
+			Name:               gitlab.String(approvalRuleName(bp.pb.Name)),
+			ApprovalsRequired:  &desired,
+			ProtectedBranchIDs: []int{protectedBranchID},
+		})
+	default:
+		rule = actual
+	}
+	if err != nil {
+		return gitlab.ProjectApprovalRule{}, err
+	}
+	return *rule, nil
+}
+
+// Delete removes the protection rules of the branch.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (bp *branchProtection) Delete(ctx context.Context) error {
+	return bp.c.Delete(ctx, bp.pb.Name)
+}
+
+// Reconcile makes sure the desired state in this object (called "req" here) becomes
+// the actual state in the backing Git provider.
+//
+// If req doesn't exist under the hood, it is created (actionTaken == true).
+// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+// If req is already the actual state, this is a no-op (actionTaken == false).
+//
+// The internal API object will be overridden with the received server data if actionTaken == true.
+func (bp *branchProtection) Reconcile(ctx context.Context) (bool, error) {
+	actual, err := bp.c.get(ctx, bp.pb.Name)
+	if err != nil {
+		// Create if not found
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			return true, bp.apply(ctx, nil)
+		}
+
+		// Unexpected path, Get should succeed or return NotFound
+		return false, err
+	}
+
+	// If the desired matches the actual state, do nothing
+	if bp.Get().Equals(actual.Get()) {
+		return false, nil
+	}
+	// If desired and actual state mis-match, update
+	return true, bp.apply(ctx, &actual.pb)
+}
+
+// validateBranchProtectionSupport returns ErrNoProviderSupport if info sets rules that can't be
+// expressed for a single protected branch in GitLab.
+func validateBranchProtectionSupport(info gitprovider.BranchProtectionInfo) error {
+	switch {
+	case len(info.RequiredStatusChecks) != 0:
+		return fmt.Errorf("cannot require status checks: %w", gitprovider.ErrNoProviderSupport)
+	case info.DismissStaleReviews != nil && *info.DismissStaleReviews:
+		return fmt.Errorf("cannot dismiss stale reviews of a single branch: %w", gitprovider.ErrNoProviderSupport)
+	case info.AllowForcePushes != nil && *info.AllowForcePushes:
+		return fmt.Errorf("cannot allow force-pushes to a protected branch: %w", gitprovider.ErrNoProviderSupport)
+	case info.AllowDeletions != nil && *info.AllowDeletions:
+		return fmt.Errorf("cannot allow deleting a protected branch: %w", gitprovider.ErrNoProviderSupport)
+	}
+	return nil
+}
+
+// pushesRestricted returns true if developers aren't allowed to push to the protected branch.
+func pushesRestricted(apiObj *gitlab.ProtectedBranch) bool {
+	for _, level := range apiObj.PushAccessLevels {
+		if level.AccessLevel != gitlab.NoPermissions && level.AccessLevel <= gitlab.DeveloperPermissions {
+			return false
+		}
+	}
+	return true
+}
+
+// approvalRuleProtects returns true if rule applies to the protected branch with the given ID.
+func approvalRuleProtects(rule *gitlab.ProjectApprovalRule, protectedBranchID int) bool {
+	for _, pb := range rule.ProtectedBranches {
+		if pb.ID == protectedBranchID {
+			return true
+		}
+	}
+	return false
+}
+
+// protectBranchOptions returns the options for protecting the branch as desired. Who is allowed
+// to merge is kept from actual, if non-nil.
+func protectBranchOptions(desired, actual *gitlab.ProtectedBranch) *gitlab.ProtectRepositoryBranchesOptions {
+	pushAccessLevel := gitlab.DeveloperPermissions
+	if pushesRestricted(desired) {
+		pushAccessLevel = gitlab.MaintainerPermissions
+	}
+	opts := &gitlab.ProtectRepositoryBranchesOptions{
+		Name:            gitlab.String(desired.Name),
+		PushAccessLevel: gitlab.AccessLevel(pushAccessLevel),
+	}
+	if actual != nil && len(actual.MergeAccessLevels) != 0 {
+		opts.MergeAccessLevel = gitlab.AccessLevel(actual.MergeAccessLevels[0].AccessLevel)
+	}
+	// Code owners are only available in some GitLab editions, only send the field if needed
+	if desired.CodeOwnerApprovalRequired {
+		opts.CodeOwnerApprovalRequired = gitlab.Bool(true)
+	}
+	return opts
+}
+
+func branchProtectionFromAPI(apiObj *gitlab.ProtectedBranch, rule *gitlab.ProjectApprovalRule) gitprovider.BranchProtectionInfo {
+	return gitprovider.BranchProtectionInfo{
+		Branch:                  apiObj.Name,
+		RequiredApprovals:       gitprovider.IntVar(rule.ApprovalsRequired),
+		DismissStaleReviews:     gitprovider.BoolVar(false),
+		RequireCodeOwnerReviews: gitprovider.BoolVar(apiObj.CodeOwnerApprovalRequired),
+		RestrictPushes:          gitprovider.BoolVar(pushesRestricted(apiObj)),
+		AllowForcePushes:        gitprovider.BoolVar(false),
+		AllowDeletions:          gitprovider.BoolVar(false),
+	}
+}
+
+func branchProtectionInfoToAPIObj(info *gitprovider.BranchProtectionInfo, apiObj *gitlab.ProtectedBranch, rule *gitlab.ProjectApprovalRule) {
+	// Required fields, we assume info is validated, and hence these are set
+	apiObj.Name = info.Branch
+	// optional fields
+	if info.RequiredApprovals != nil {
+		rule.ApprovalsRequired = *info.RequiredApprovals
+	}
+	if info.RequireCodeOwnerReviews != nil {
+		apiObj.CodeOwnerApprovalRequired = *info.RequireCodeOwnerReviews
+	}
+	if info.RestrictPushes != nil {
+		level := gitlab.DeveloperPermissions
+		if *info.RestrictPushes {
+			level = gitlab.MaintainerPermissions
+		}
+		apiObj.PushAccessLevels = []*gitlab.BranchAccessDescription{{AccessLevel: level}}
+	}
+}
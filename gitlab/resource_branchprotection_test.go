@@ -0,0 +1,144 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+func Test_branchProtectionInfoToAPIObj(t *testing.T) {
+	tests := []struct {
+		name     string
+		info     gitprovider.BranchProtectionInfo
+		actual   *gitlab.ProtectedBranch
+		wantOpts *gitlab.ProtectRepositoryBranchesOptions
+		wantErr  error
+	}{
+		{
+			name: "defaults",
+			info: gitprovider.BranchProtectionInfo{Branch: "main"},
+			wantOpts: &gitlab.ProtectRepositoryBranchesOptions{
+				Name:            gitlab.String("main"),
+				PushAccessLevel: gitlab.AccessLevel(gitlab.DeveloperPermissions),
+			},
+		},
+		{
+			name: "restricted pushes, approvals and code owners",
+			info: gitprovider.BranchProtectionInfo{
+				Branch:                  "main",
+				RequiredApprovals:       gitprovider.IntVar(2),
+				RequireCodeOwnerReviews: gitprovider.BoolVar(true),
+				RestrictPushes:          gitprovider.BoolVar(true),
+			},
+			wantOpts: &gitlab.ProtectRepositoryBranchesOptions{
+				Name:                      gitlab.String("main"),
+				PushAccessLevel:           gitlab.AccessLevel(gitlab.MaintainerPermissions),
+				CodeOwnerApprovalRequired: gitlab.Bool(true),
+			},
+		},
+		{
+			name: "keeps who can merge when protecting again",
+			info: gitprovider.BranchProtectionInfo{Branch: "main", RestrictPushes: gitprovider.BoolVar(true)},
+			actual: &gitlab.ProtectedBranch{
+				MergeAccessLevels: []*gitlab.BranchAccessDescription{{AccessLevel: gitlab.MaintainerPermissions}},
+			},
+			wantOpts: &gitlab.ProtectRepositoryBranchesOptions{
+				Name:             gitlab.String("main"),
+				PushAccessLevel:  gitlab.AccessLevel(gitlab.MaintainerPermissions),
+				MergeAccessLevel: gitlab.AccessLevel(gitlab.MaintainerPermissions),
+			},
+		},
+		{
+			name:    "status checks",
+			info:    gitprovider.BranchProtectionInfo{Branch: "main", RequiredStatusChecks: []string{"build"}},
+			wantErr: gitprovider.ErrNoProviderSupport,
+		},
+		{
+			name:    "dismissed stale reviews",
+			info:    gitprovider.BranchProtectionInfo{Branch: "main", DismissStaleReviews: gitprovider.BoolVar(true)},
+			wantErr: gitprovider.ErrNoProviderSupport,
+		},
+		{
+			name:    "force-pushes",
+			info:    gitprovider.BranchProtectionInfo{Branch: "main", AllowForcePushes: gitprovider.BoolVar(true)},
+			wantErr: gitprovider.ErrNoProviderSupport,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := gitprovider.ValidateAndDefaultInfo(&tt.info); err != nil {
+				t.Fatal(err)
+			}
+			bp := newBranchProtection(nil, &gitlab.ProtectedBranch{})
+			err := bp.Set(tt.info)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("branchProtection.Set() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if opts := protectBranchOptions(&bp.pb, tt.actual); !reflect.DeepEqual(opts, tt.wantOpts) {
+				t.Errorf("protectBranchOptions() = %+v, want %+v", opts, tt.wantOpts)
+			}
+			// The info must survive the round-trip through the API objects
+			if info := bp.Get(); !tt.info.Equals(info) {
+				t.Errorf("branchProtection.Get() = %+v, want %+v", info, tt.info)
+			}
+		})
+	}
+}
+
+func Test_pushesRestricted(t *testing.T) {
+	tests := []struct {
+		name   string
+		levels []*gitlab.BranchAccessDescription
+		want   bool
+	}{
+		{
+			name:   "developers",
+			levels: []*gitlab.BranchAccessDescription{{AccessLevel: gitlab.DeveloperPermissions}},
+			want:   false,
+		},
+		{
+			name:   "maintainers",
+			levels: []*gitlab.BranchAccessDescription{{AccessLevel: gitlab.MaintainerPermissions}},
+			want:   true,
+		},
+		{
+			name:   "a specific user",
+			levels: []*gitlab.BranchAccessDescription{{AccessLevel: gitlab.NoPermissions, UserID: 1}},
+			want:   true,
+		},
+		{
+			name: "no one",
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pushesRestricted(&gitlab.ProtectedBranch{PushAccessLevels: tt.levels}); got != tt.want {
+				t.Errorf("pushesRestricted() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package gitlab
 
 import (
+	"time"
+
 	"github.com/xanzy/go-gitlab"
 
 	"github.com/fluxcd/go-git-providers/gitprovider"
@@ -46,6 +48,25 @@ func (c *commitType) APIObject() interface{} {
 
 func commitFromAPI(apiObj *gitlab.Commit) gitprovider.CommitInfo {
 	return gitprovider.CommitInfo{
-		Sha: apiObj.ID,
+		Sha:        apiObj.ID,
+		Message:    apiObj.Message,
+		Author:     commitAuthorFromAPI(apiObj.AuthorName, apiObj.AuthorEmail, apiObj.AuthoredDate),
+		Committer:  commitAuthorFromAPI(apiObj.CommitterName, apiObj.CommitterEmail, apiObj.CommittedDate),
+		ParentShas: apiObj.ParentIDs,
+		WebURL:     apiObj.WebURL,
+	}
+}
+
+func commitAuthorFromAPI(name, email string, date *time.Time) *gitprovider.CommitAuthor {
+	if name == "" && email == "" {
+		return nil
+	}
+	author := &gitprovider.CommitAuthor{
+		Name:  name,
+		Email: email,
+	}
+	if date != nil {
+		author.Date = *date
 	}
+	return author
 }
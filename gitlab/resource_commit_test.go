@@ -0,0 +1,73 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+func Test_commitFromAPI(t *testing.T) {
+	authored := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	committed := authored.Add(time.Hour)
+	tests := []struct {
+		name   string
+		apiObj *gitlab.Commit
+		want   gitprovider.CommitInfo
+	}{
+		{
+			name: "all fields",
+			apiObj: &gitlab.Commit{
+				ID:             "abc",
+				Message:        "Add docs\n",
+				AuthorName:     "Jane",
+				AuthorEmail:    "jane@example.com",
+				AuthoredDate:   &authored,
+				CommitterName:  "Bot",
+				CommitterEmail: "bot@example.com",
+				CommittedDate:  &committed,
+				ParentIDs:      []string{"p1", "p2"},
+				WebURL:         "https://gitlab.com/group/project/-/commit/abc",
+			},
+			want: gitprovider.CommitInfo{
+				Sha:        "abc",
+				Message:    "Add docs\n",
+				Author:     &gitprovider.CommitAuthor{Name: "Jane", Email: "jane@example.com", Date: authored},
+				Committer:  &gitprovider.CommitAuthor{Name: "Bot", Email: "bot@example.com", Date: committed},
+				ParentShas: []string{"p1", "p2"},
+				WebURL:     "https://gitlab.com/group/project/-/commit/abc",
+			},
+		},
+		{
+			name:   "only id",
+			apiObj: &gitlab.Commit{ID: "abc"},
+			want:   gitprovider.CommitInfo{Sha: "abc"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := commitFromAPI(tt.apiObj); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("commitFromAPI() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+func newCommitStatus(apiObj *gitlab.CommitStatus) *commitStatus {
+	return &commitStatus{
+		s: *apiObj,
+	}
+}
+
+var _ gitprovider.CommitStatus = &commitStatus{}
+
+type commitStatus struct {
+	s gitlab.CommitStatus
+}
+
+func (s *commitStatus) Get() gitprovider.CommitStatusInfo {
+	return commitStatusFromAPI(&s.s)
+}
+
+func (s *commitStatus) APIObject() interface{} {
+	return &s.s
+}
+
+func newCombinedCommitStatus(sha string, apiObjs []*gitlab.CommitStatus) *combinedCommitStatus {
+	return &combinedCommitStatus{
+		sha:      sha,
+		statuses: apiObjs,
+	}
+}
+
+var _ gitprovider.CombinedCommitStatus = &combinedCommitStatus{}
+
+// combinedCommitStatus is computed from the latest statuses of each name, as GitLab doesn't
+// have an API for the combined status of a commit.
+type combinedCommitStatus struct {
+	sha      string
+	statuses []*gitlab.CommitStatus
+}
+
+func (s *combinedCommitStatus) Get() gitprovider.CombinedCommitStatusInfo {
+	statuses := make([]gitprovider.CommitStatusInfo, 0, len(s.statuses))
+	for _, apiObj := range s.statuses {
+		statuses = append(statuses, commitStatusFromAPI(apiObj))
+	}
+	return gitprovider.CombinedCommitStatusInfo{
+		Sha:      s.sha,
+		State:    gitprovider.CombineCommitStatusStates(statuses),
+		Statuses: statuses,
+	}
+}
+
+func (s *combinedCommitStatus) APIObject() interface{} {
+	return s.statuses
+}
+
+func commitStatusFromAPI(apiObj *gitlab.CommitStatus) gitprovider.CommitStatusInfo {
+	info := gitprovider.CommitStatusInfo{
+		State:       commitStatusStateFromAPI(gitlab.BuildStateValue(apiObj.Status)),
+		Context:     apiObj.Name,
+		Description: apiObj.Description,
+		TargetURL:   apiObj.TargetURL,
+		Creator:     apiObj.Author.Username,
+	}
+	if apiObj.CreatedAt != nil {
+		info.CreatedAt = *apiObj.CreatedAt
+	}
+	return info
+}
+
+// commitStatusStateFromAPI maps the build states of GitLab to commit status states. Builds
+// that haven't finished are pending, and canceled builds are errors.
+func commitStatusStateFromAPI(state gitlab.BuildStateValue) gitprovider.CommitStatusState {
+	switch state {
+	case gitlab.Success, gitlab.Skipped:
+		return gitprovider.CommitStatusStateSuccess
+	case gitlab.Failed:
+		return gitprovider.CommitStatusStateFailure
+	case gitlab.Canceled:
+		return gitprovider.CommitStatusStateError
+	default:
+		return gitprovider.CommitStatusStatePending
+	}
+}
+
+// commitStatusStateToAPI maps the commit status states to the build states of GitLab, which
+// has no separate state for errors.
+func commitStatusStateToAPI(state gitprovider.CommitStatusState) gitlab.BuildStateValue {
+	switch state {
+	case gitprovider.CommitStatusStateSuccess:
+		return gitlab.Success
+	case gitprovider.CommitStatusStateError, gitprovider.CommitStatusStateFailure:
+		return gitlab.Failed
+	default:
+		return gitlab.Pending
+	}
+}
+
+// validateCommitStatusAPI validates the apiObj received from the server, to make sure that it
+// is valid for our use.
+func validateCommitStatusAPI(apiObj *gitlab.CommitStatus) error {
+	return validateAPIObject("GitLab.CommitStatus", func(validator validation.Validator) {
+		if apiObj.Status == "" {
+			validator.Required("Status")
+		}
+	})
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"testing"
+
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+func Test_commitStatusStateFromAPI(t *testing.T) {
+	tests := []struct {
+		state gitlab.BuildStateValue
+		want  gitprovider.CommitStatusState
+	}{
+		{state: gitlab.Pending, want: gitprovider.CommitStatusStatePending},
+		{state: gitlab.Created, want: gitprovider.CommitStatusStatePending},
+		{state: gitlab.Running, want: gitprovider.CommitStatusStatePending},
+		{state: gitlab.Manual, want: gitprovider.CommitStatusStatePending},
+		{state: gitlab.Success, want: gitprovider.CommitStatusStateSuccess},
+		{state: gitlab.Skipped, want: gitprovider.CommitStatusStateSuccess},
+		{state: gitlab.Failed, want: gitprovider.CommitStatusStateFailure},
+		{state: gitlab.Canceled, want: gitprovider.CommitStatusStateError},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.state), func(t *testing.T) {
+			if got := commitStatusStateFromAPI(tt.state); got != tt.want {
+				t.Errorf("commitStatusStateFromAPI() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_commitStatusStateToAPI(t *testing.T) {
+	tests := []struct {
+		state gitprovider.CommitStatusState
+		want  gitlab.BuildStateValue
+	}{
+		{state: gitprovider.CommitStatusStatePending, want: gitlab.Pending},
+		{state: gitprovider.CommitStatusStateSuccess, want: gitlab.Success},
+		{state: gitprovider.CommitStatusStateFailure, want: gitlab.Failed},
+		{state: gitprovider.CommitStatusStateError, want: gitlab.Failed},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.state), func(t *testing.T) {
+			if got := commitStatusStateToAPI(tt.state); got != tt.want {
+				t.Errorf("commitStatusStateToAPI() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+	"github.com/xanzy/go-gitlab"
+)
+
+// newFile returns the file of a tree node, with the given raw content if it was fetched.
+func newFile(apiObj *gitlab.TreeNode, content []byte) *file {
+	return &file{
+		n:       *apiObj,
+		content: content,
+	}
+}
+
+var _ gitprovider.File = &file{}
+
+type file struct {
+	n       gitlab.TreeNode
+	content []byte
+}
+
+func (f *file) Get() gitprovider.FileInfo {
+	info := fileFromAPI(&f.n)
+	if f.content != nil {
+		info.Content = append([]byte(nil), f.content...)
+		info.Size = int64(len(f.content))
+	}
+	return info
+}
+
+func (f *file) APIObject() interface{} {
+	return &f.n
+}
+
+// fileFromAPI returns the info of a tree node. GitLab doesn't report the size of files in
+// trees, so it's only set when the content is fetched.
+func fileFromAPI(apiObj *gitlab.TreeNode) gitprovider.FileInfo {
+	return gitprovider.FileInfo{
+		Path: apiObj.Path,
+		Mode: gitprovider.FileMode(apiObj.Mode),
+		Sha:  apiObj.ID,
+	}
+}
+
+// validateTreeNodeAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateTreeNodeAPI(apiObj *gitlab.TreeNode) error {
+	return validateAPIObject("GitLab.TreeNode", func(validator validation.Validator) {
+		if apiObj.Path == "" {
+			validator.Required("Path")
+		}
+		if apiObj.Mode == "" {
+			validator.Required("Mode")
+		}
+		if apiObj.ID == "" {
+			validator.Required("ID")
+		}
+	})
+}
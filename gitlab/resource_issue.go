@@ -0,0 +1,107 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+func newIssue(ctx *clientContext, apiObj *gitlab.Issue, ref gitprovider.RepositoryRef) *issue {
+	return &issue{
+		i: *apiObj,
+		comments: &IssueCommentClient{
+			clientContext: ctx,
+			ref:           ref,
+			iid:           apiObj.IID,
+		},
+	}
+}
+
+var _ gitprovider.Issue = &issue{}
+
+type issue struct {
+	i        gitlab.Issue
+	comments *IssueCommentClient
+}
+
+func (i *issue) Get() gitprovider.IssueInfo {
+	return issueFromAPI(&i.i)
+}
+
+func (i *issue) APIObject() interface{} {
+	return &i.i
+}
+
+// Comments gives access to this specific issue's comments
+func (i *issue) Comments() gitprovider.IssueCommentClient {
+	return i.comments
+}
+
+func issueFromAPI(apiObj *gitlab.Issue) gitprovider.IssueInfo {
+	info := gitprovider.IssueInfo{
+		Number:   apiObj.IID,
+		Title:    apiObj.Title,
+		Body:     apiObj.Description,
+		State:    issueStateFromAPI(apiObj.State),
+		ClosedAt: apiObj.ClosedAt,
+		WebURL:   apiObj.WebURL,
+	}
+	if len(apiObj.Labels) != 0 {
+		info.Labels = append([]string{}, apiObj.Labels...)
+	}
+	for _, assignee := range apiObj.Assignees {
+		info.Assignees = append(info.Assignees, assignee.Username)
+	}
+	if apiObj.Author != nil {
+		info.Author = apiObj.Author.Username
+	}
+	if apiObj.CreatedAt != nil {
+		info.CreatedAt = *apiObj.CreatedAt
+	}
+	if apiObj.UpdatedAt != nil {
+		info.UpdatedAt = *apiObj.UpdatedAt
+	}
+	return info
+}
+
+// issueStateFromAPI maps the "opened" state of GitLab to IssueStateOpen.
+func issueStateFromAPI(state string) gitprovider.IssueState {
+	if state == "closed" {
+		return gitprovider.IssueStateClosed
+	}
+	return gitprovider.IssueStateOpen
+}
+
+// issueStateToAPI maps IssueStateOpen to the "opened" state of GitLab.
+func issueStateToAPI(state gitprovider.IssueState) string {
+	if state == gitprovider.IssueStateOpen {
+		return "opened"
+	}
+	return string(state)
+}
+
+func validateIssueAPI(apiObj *gitlab.Issue) error {
+	return validateAPIObject("GitLab.Issue", func(validator validation.Validator) {
+		// Make sure the IID is populated, it's needed to update the issue
+		if apiObj.IID == 0 {
+			validator.Required("IID")
+		}
+	})
+}
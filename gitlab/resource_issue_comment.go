@@ -0,0 +1,54 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+func newIssueComment(apiObj *gitlab.Note) *issueComment {
+	return &issueComment{
+		n: *apiObj,
+	}
+}
+
+var _ gitprovider.IssueComment = &issueComment{}
+
+type issueComment struct {
+	n gitlab.Note
+}
+
+func (c *issueComment) Get() gitprovider.IssueCommentInfo {
+	info := gitprovider.IssueCommentInfo{
+		ID:     int64(c.n.ID),
+		Body:   c.n.Body,
+		Author: c.n.Author.Username,
+	}
+	if c.n.CreatedAt != nil {
+		info.CreatedAt = *c.n.CreatedAt
+	}
+	if c.n.UpdatedAt != nil {
+		info.UpdatedAt = *c.n.UpdatedAt
+	}
+	return info
+}
+
+func (c *issueComment) APIObject() interface{} {
+	return &c.n
+}
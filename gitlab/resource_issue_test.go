@@ -0,0 +1,104 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+func Test_issueFromAPI(t *testing.T) {
+	created := time.Date(2021, 3, 1, 10, 0, 0, 0, time.UTC)
+	closed := created.Add(time.Hour)
+	tests := []struct {
+		name   string
+		apiObj *gitlab.Issue
+		want   gitprovider.IssueInfo
+	}{
+		{
+			name: "open issue",
+			apiObj: &gitlab.Issue{
+				IID:         3,
+				Title:       "Broken link",
+				Description: "The link is broken.",
+				State:       "opened",
+				Labels:      gitlab.Labels{"bug"},
+				Author:      &gitlab.IssueAuthor{Username: "alice"},
+				Assignees:   []*gitlab.IssueAssignee{{Username: "bob"}},
+				CreatedAt:   &created,
+				UpdatedAt:   &created,
+				WebURL:      "https://gitlab.com/foo/bar/-/issues/3",
+			},
+			want: gitprovider.IssueInfo{
+				Number:    3,
+				Title:     "Broken link",
+				Body:      "The link is broken.",
+				State:     gitprovider.IssueStateOpen,
+				Labels:    []string{"bug"},
+				Assignees: []string{"bob"},
+				Author:    "alice",
+				CreatedAt: created,
+				UpdatedAt: created,
+				WebURL:    "https://gitlab.com/foo/bar/-/issues/3",
+			},
+		},
+		{
+			name: "closed issue",
+			apiObj: &gitlab.Issue{
+				IID:      4,
+				Title:    "Typo",
+				State:    "closed",
+				ClosedAt: &closed,
+			},
+			want: gitprovider.IssueInfo{
+				Number:   4,
+				Title:    "Typo",
+				State:    gitprovider.IssueStateClosed,
+				ClosedAt: &closed,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := issueFromAPI(tt.apiObj); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("issueFromAPI() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_issueStateToAPI(t *testing.T) {
+	tests := []struct {
+		state gitprovider.IssueState
+		want  string
+	}{
+		{state: gitprovider.IssueStateOpen, want: "opened"},
+		{state: gitprovider.IssueStateClosed, want: "closed"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.state), func(t *testing.T) {
+			if got := issueStateToAPI(tt.state); got != tt.want {
+				t.Errorf("issueStateToAPI() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -32,6 +32,10 @@ func newOrganization(ctx *clientContext, apiObj *gitlab.Group, ref gitprovider.O
 			clientContext: ctx,
 			ref:           ref,
 		},
+		webhooks: &GroupWebhookClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
 	}
 }
 
@@ -43,7 +47,8 @@ type organization struct {
 	g   gitlab.Group
 	ref gitprovider.OrganizationRef
 
-	teams *TeamsClient
+	teams    *TeamsClient
+	webhooks *GroupWebhookClient
 }
 
 func (o *organization) Get() gitprovider.OrganizationInfo {
@@ -62,6 +67,10 @@ func (o *organization) Teams() gitprovider.TeamsClient {
 	return o.teams
 }
 
+func (o *organization) Webhooks() gitprovider.WebhookClient {
+	return o.webhooks
+}
+
 func organizationFromAPI(apiObj *gitlab.Group) gitprovider.OrganizationInfo {
 	return gitprovider.OrganizationInfo{
 		Name:        &apiObj.Name,
@@ -18,13 +18,24 @@ package gitlab
 
 import (
 	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
 	"github.com/xanzy/go-gitlab"
 )
 
-func newPullRequest(ctx *clientContext, apiObj *gitlab.MergeRequest) *pullrequest {
+func newPullRequest(ctx *clientContext, apiObj *gitlab.MergeRequest, ref gitprovider.RepositoryRef) *pullrequest {
 	return &pullrequest{
 		clientContext: ctx,
 		pr:            *apiObj,
+		comments: &PullRequestCommentClient{
+			clientContext: ctx,
+			ref:           ref,
+			iid:           apiObj.IID,
+		},
+		reviews: &PullRequestReviewClient{
+			clientContext: ctx,
+			ref:           ref,
+			iid:           apiObj.IID,
+		},
 	}
 }
 
@@ -34,6 +45,9 @@ type pullrequest struct {
 	*clientContext
 
 	pr gitlab.MergeRequest
+
+	comments *PullRequestCommentClient
+	reviews  *PullRequestReviewClient
 }
 
 func (pr *pullrequest) Get() gitprovider.PullRequestInfo {
@@ -44,8 +58,79 @@ func (pr *pullrequest) APIObject() interface{} {
 	return &pr.pr
 }
 
+// Comments gives access to this specific merge request's notes
+func (pr *pullrequest) Comments() gitprovider.PullRequestCommentClient {
+	return pr.comments
+}
+
+// Reviews gives access to this specific merge request's approvals
+func (pr *pullrequest) Reviews() gitprovider.PullRequestReviewClient {
+	return pr.reviews
+}
+
 func pullrequestFromAPI(apiObj *gitlab.MergeRequest) gitprovider.PullRequestInfo {
-	return gitprovider.PullRequestInfo{
-		WebURL: apiObj.WebURL,
+	info := gitprovider.PullRequestInfo{
+		Number:      apiObj.IID,
+		Title:       apiObj.Title,
+		Description: apiObj.Description,
+		HeadBranch:  apiObj.SourceBranch,
+		BaseBranch:  apiObj.TargetBranch,
+		State:       pullRequestStateFromAPI(apiObj.State),
+		Draft:       apiObj.WorkInProgress,
+		MergedAt:    apiObj.MergedAt,
+		ClosedAt:    apiObj.ClosedAt,
+		WebURL:      apiObj.WebURL,
+	}
+	if len(apiObj.Labels) != 0 {
+		info.Labels = append([]string{}, apiObj.Labels...)
+	}
+	if apiObj.Author != nil {
+		info.Author = apiObj.Author.Username
+	}
+	if apiObj.CreatedAt != nil {
+		info.CreatedAt = *apiObj.CreatedAt
+	}
+	if apiObj.UpdatedAt != nil {
+		info.UpdatedAt = *apiObj.UpdatedAt
 	}
+	info.Mergeable = mergeableFromAPI(apiObj.MergeStatus)
+	return info
+}
+
+// pullRequestStateFromAPI maps the merge request states "opened", "closed", "locked" and
+// "merged" to a PullRequestState. Locked merge requests are open, but being merged.
+func pullRequestStateFromAPI(state string) gitprovider.PullRequestState {
+	switch state {
+	case "merged":
+		return gitprovider.PullRequestStateMerged
+	case "closed":
+		return gitprovider.PullRequestStateClosed
+	default:
+		return gitprovider.PullRequestStateOpen
+	}
+}
+
+// mergeableFromAPI returns nil if GitLab hasn't checked whether the merge request can be merged yet.
+func mergeableFromAPI(mergeStatus string) *bool {
+	var mergeable bool
+	switch mergeStatus {
+	case "can_be_merged":
+		mergeable = true
+	case "cannot_be_merged":
+		mergeable = false
+	default:
+		return nil
+	}
+	return &mergeable
+}
+
+func validateMergeRequestAPI(apiObj *gitlab.MergeRequest) error {
+	return validateAPIObject("GitLab.MergeRequest", func(validator validation.Validator) {
+		if apiObj.IID == 0 {
+			validator.Required("IID")
+		}
+		if apiObj.State == "" {
+			validator.Required("State")
+		}
+	})
 }
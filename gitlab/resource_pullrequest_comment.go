@@ -0,0 +1,67 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+	"github.com/xanzy/go-gitlab"
+)
+
+func newPullRequestComment(apiObj *gitlab.Note) *pullRequestComment {
+	return &pullRequestComment{
+		n: *apiObj,
+	}
+}
+
+var _ gitprovider.PullRequestComment = &pullRequestComment{}
+
+type pullRequestComment struct {
+	n gitlab.Note
+}
+
+func (c *pullRequestComment) Get() gitprovider.PullRequestCommentInfo {
+	return pullRequestCommentFromAPI(&c.n)
+}
+
+func (c *pullRequestComment) APIObject() interface{} {
+	return &c.n
+}
+
+func pullRequestCommentFromAPI(apiObj *gitlab.Note) gitprovider.PullRequestCommentInfo {
+	info := gitprovider.PullRequestCommentInfo{
+		ID:     int64(apiObj.ID),
+		Body:   apiObj.Body,
+		Author: apiObj.Author.Username,
+	}
+	if apiObj.CreatedAt != nil {
+		info.CreatedAt = *apiObj.CreatedAt
+	}
+	if apiObj.UpdatedAt != nil {
+		info.UpdatedAt = *apiObj.UpdatedAt
+	}
+	return info
+}
+
+func validateNoteAPI(apiObj *gitlab.Note) error {
+	return validateAPIObject("GitLab.Note", func(validator validation.Validator) {
+		// Make sure the ID is populated, it's needed to update and delete the note
+		if apiObj.ID == 0 {
+			validator.Required("ID")
+		}
+	})
+}
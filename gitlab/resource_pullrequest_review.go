@@ -0,0 +1,77 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/xanzy/go-gitlab"
+)
+
+// newApprovalReview returns an approving review for the given approver. The approver isn't
+// returned when approving, so then the author of the note added with the approval is used,
+// if any.
+func newApprovalReview(approver *gitlab.MergeRequestApproverUser, note *gitlab.Note) *pullRequestReview {
+	info := gitprovider.PullRequestReviewInfo{
+		State: gitprovider.PullRequestReviewStateApproved,
+	}
+	var apiObj interface{}
+	if approver != nil {
+		apiObj = approver
+		if approver.User != nil {
+			info.Author = approver.User.Username
+		}
+	}
+	if note != nil {
+		info.Body = note.Body
+		info.Author = note.Author.Username
+		info.SubmittedAt = note.CreatedAt
+		apiObj = note
+	}
+	return &pullRequestReview{info: info, apiObj: apiObj}
+}
+
+// newNoteReview returns a commenting review for the given note.
+func newNoteReview(note *gitlab.Note) *pullRequestReview {
+	return &pullRequestReview{
+		info: gitprovider.PullRequestReviewInfo{
+			ID:          int64(note.ID),
+			State:       gitprovider.PullRequestReviewStateCommented,
+			Body:        note.Body,
+			Author:      note.Author.Username,
+			SubmittedAt: note.CreatedAt,
+		},
+		apiObj: note,
+	}
+}
+
+var _ gitprovider.PullRequestReview = &pullRequestReview{}
+
+// pullRequestReview is an approval or a note, as GitLab doesn't have reviews.
+type pullRequestReview struct {
+	info gitprovider.PullRequestReviewInfo
+	// apiObj is a *gitlab.MergeRequestApproverUser or a *gitlab.Note, or nil for an approval
+	// without a note
+	apiObj interface{}
+}
+
+func (r *pullRequestReview) Get() gitprovider.PullRequestReviewInfo {
+	return r.info
+}
+
+func (r *pullRequestReview) APIObject() interface{} {
+	return r.apiObj
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+	"github.com/xanzy/go-gitlab"
+)
+
+func newRelease(c *ReleaseClient, apiObj *gitlab.Release) *release {
+	return &release{
+		r:   *apiObj,
+		ref: c.ref,
+		assets: &ReleaseAssetClient{
+			clientContext: c.clientContext,
+			ref:           c.ref,
+			tagName:       apiObj.TagName,
+		},
+	}
+}
+
+var _ gitprovider.Release = &release{}
+
+type release struct {
+	r      gitlab.Release
+	ref    gitprovider.RepositoryRef
+	assets *ReleaseAssetClient
+}
+
+func (r *release) Get() gitprovider.ReleaseInfo {
+	return releaseFromAPI(&r.r, r.ref)
+}
+
+func (r *release) APIObject() interface{} {
+	return &r.r
+}
+
+func (r *release) Assets() gitprovider.ReleaseAssetClient {
+	return r.assets
+}
+
+func releaseFromAPI(apiObj *gitlab.Release, ref gitprovider.RepositoryRef) gitprovider.ReleaseInfo {
+	info := gitprovider.ReleaseInfo{
+		TagName:         apiObj.TagName,
+		TargetCommitish: apiObj.Commit.ID,
+		Name:            apiObj.Name,
+		Body:            apiObj.Description,
+		// The API doesn't return the web URL of releases, derive it from the one of the project
+		WebURL: fmt.Sprintf("%s/-/releases/%s", ref.String(), url.PathEscape(apiObj.TagName)),
+	}
+	if apiObj.CreatedAt != nil {
+		info.CreatedAt = *apiObj.CreatedAt
+	}
+	return info
+}
+
+// validateReleaseAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateReleaseAPI(apiObj *gitlab.Release) error {
+	return validateAPIObject("GitLab.Release", func(validator validation.Validator) {
+		if apiObj.TagName == "" {
+			validator.Required("TagName")
+		}
+	})
+}
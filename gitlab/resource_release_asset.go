@@ -0,0 +1,69 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+	"github.com/xanzy/go-gitlab"
+)
+
+func newReleaseAsset(apiObj *gitlab.ReleaseLink) *releaseAsset {
+	return &releaseAsset{
+		l: *apiObj,
+	}
+}
+
+var _ gitprovider.ReleaseAsset = &releaseAsset{}
+
+type releaseAsset struct {
+	l gitlab.ReleaseLink
+}
+
+func (a *releaseAsset) Get() gitprovider.ReleaseAssetInfo {
+	return releaseAssetFromAPI(&a.l)
+}
+
+func (a *releaseAsset) APIObject() interface{} {
+	return &a.l
+}
+
+// releaseAssetFromAPI maps the release link to the asset. The API doesn't return the size and
+// media type of the linked file.
+func releaseAssetFromAPI(apiObj *gitlab.ReleaseLink) gitprovider.ReleaseAssetInfo {
+	return gitprovider.ReleaseAssetInfo{
+		Name:        apiObj.Name,
+		DownloadURL: apiObj.URL,
+	}
+}
+
+// validateReleaseLinkAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateReleaseLinkAPI(apiObj *gitlab.ReleaseLink) error {
+	return validateAPIObject("GitLab.ReleaseLink", func(validator validation.Validator) {
+		// Make sure the ID is populated, it's needed to delete the link
+		if apiObj.ID == 0 {
+			validator.Required("ID")
+		}
+		if apiObj.Name == "" {
+			validator.Required("Name")
+		}
+		if apiObj.URL == "" {
+			validator.Required("URL")
+		}
+	})
+}
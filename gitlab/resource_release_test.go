@@ -0,0 +1,45 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"testing"
+
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+func Test_releaseFromAPI(t *testing.T) {
+	ref := gitprovider.OrgRepositoryRef{
+		OrganizationRef: gitprovider.OrganizationRef{Domain: "gitlab.com", Organization: "group"},
+		RepositoryName:  "project",
+	}
+	apiObj := &gitlab.Release{TagName: "release/v1.0.0", Name: "First release", Description: "notes"}
+	apiObj.Commit.ID = "abc"
+
+	want := gitprovider.ReleaseInfo{
+		TagName:         "release/v1.0.0",
+		TargetCommitish: "abc",
+		Name:            "First release",
+		Body:            "notes",
+		WebURL:          "https://gitlab.com/group/project/-/releases/release%2Fv1.0.0",
+	}
+	if got := releaseFromAPI(apiObj, ref); got != want {
+		t.Errorf("releaseFromAPI() = %+v, want %+v", got, want)
+	}
+}
@@ -43,10 +43,38 @@ func newUserProject(ctx *clientContext, apiObj *gogitlab.Project, ref gitprovide
 			clientContext: ctx,
 			ref:           ref,
 		},
+		files: &FileClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		branchProtections: &BranchProtectionClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
 		pullRequests: &PullRequestClient{
 			clientContext: ctx,
 			ref:           ref,
 		},
+		tags: &TagClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		releases: &ReleaseClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		webhooks: &WebhookClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		commitStatuses: &CommitStatusClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		issues: &IssueClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
 	}
 }
 
@@ -58,10 +86,17 @@ type userProject struct {
 	p   gogitlab.Project
 	ref gitprovider.RepositoryRef
 
-	deployKeys   *DeployKeyClient
-	commits      *CommitClient
-	branches     *BranchClient
-	pullRequests *PullRequestClient
+	deployKeys        *DeployKeyClient
+	commits           *CommitClient
+	branches          *BranchClient
+	files             *FileClient
+	branchProtections *BranchProtectionClient
+	pullRequests      *PullRequestClient
+	tags              *TagClient
+	releases          *ReleaseClient
+	webhooks          *WebhookClient
+	commitStatuses    *CommitStatusClient
+	issues            *IssueClient
 }
 
 func (p *userProject) Get() gitprovider.RepositoryInfo {
@@ -96,10 +131,38 @@ func (p *userProject) Branches() gitprovider.BranchClient {
 	return p.branches
 }
 
+func (p *userProject) Files() gitprovider.FileClient {
+	return p.files
+}
+
+func (p *userProject) BranchProtections() gitprovider.BranchProtectionClient {
+	return p.branchProtections
+}
+
 func (p *userProject) PullRequests() gitprovider.PullRequestClient {
 	return p.pullRequests
 }
 
+func (p *userProject) Tags() gitprovider.TagClient {
+	return p.tags
+}
+
+func (p *userProject) Releases() gitprovider.ReleaseClient {
+	return p.releases
+}
+
+func (p *userProject) Webhooks() gitprovider.WebhookClient {
+	return p.webhooks
+}
+
+func (p *userProject) CommitStatuses() gitprovider.CommitStatusClient {
+	return p.commitStatuses
+}
+
+func (p *userProject) Issues() gitprovider.IssueClient {
+	return p.issues
+}
+
 // The internal API object will be overridden with the received server data.
 func (p *userProject) Update(ctx context.Context) error {
 	// PATCH /repos/{owner}/{repo}
@@ -0,0 +1,64 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+	"github.com/xanzy/go-gitlab"
+)
+
+func newTag(apiObj *gitlab.Tag) *tag {
+	return &tag{
+		t: *apiObj,
+	}
+}
+
+var _ gitprovider.Tag = &tag{}
+
+type tag struct {
+	t gitlab.Tag
+}
+
+func (t *tag) Get() gitprovider.TagInfo {
+	return tagFromAPI(&t.t)
+}
+
+func (t *tag) APIObject() interface{} {
+	return &t.t
+}
+
+func tagFromAPI(apiObj *gitlab.Tag) gitprovider.TagInfo {
+	return gitprovider.TagInfo{
+		Name:    apiObj.Name,
+		Sha:     apiObj.Commit.ID,
+		Message: apiObj.Message,
+	}
+}
+
+// validateTagAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateTagAPI(apiObj *gitlab.Tag) error {
+	return validateAPIObject("GitLab.Tag", func(validator validation.Validator) {
+		if apiObj.Name == "" {
+			validator.Required("Name")
+		}
+		if apiObj.Commit == nil || apiObj.Commit.ID == "" {
+			validator.Required("Commit.ID")
+		}
+	})
+}
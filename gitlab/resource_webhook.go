@@ -0,0 +1,232 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+func newWebhook(c *WebhookClient, apiObj *gitlab.ProjectHook) *webhook {
+	return &webhook{
+		h: *apiObj,
+		c: c,
+	}
+}
+
+var _ gitprovider.Webhook = &webhook{}
+
+type webhook struct {
+	h gitlab.ProjectHook
+	// secret is the token of the webhook, which is write-only, and hence not part of the API object
+	secret string
+	c      *WebhookClient
+}
+
+func (wh *webhook) Get() gitprovider.WebhookInfo {
+	return webhookFromAPI(&wh.h)
+}
+
+// Set sets high-level desired state for this webhook.
+//
+// GitLab has no release events in this API version, always delivers active webhooks as JSON,
+// so ErrNoProviderSupport is returned if info contains a release event, form-encoded payloads
+// or an inactive webhook.
+func (wh *webhook) Set(info gitprovider.WebhookInfo) error {
+	if err := info.ValidateInfo(); err != nil {
+		return err
+	}
+	if err := validateWebhookSupport(&info); err != nil {
+		return err
+	}
+	wh.secret = info.Secret
+	webhookInfoToAPIObj(&info, &wh.h)
+	return nil
+}
+
+func (wh *webhook) APIObject() interface{} {
+	return &wh.h
+}
+
+// Update will apply the desired state in this object to the server.
+// In order to apply changes to this object, use the .Set({Resource}Info) error
+// function, or cast .APIObject() to a pointer to the provider-specific type
+// and set custom fields there.
+//
+// ErrNotFound is returned if the resource does not exist.
+//
+// The internal API object will be overridden with the received server data.
+func (wh *webhook) Update(ctx context.Context) error {
+	opts := gitlab.EditProjectHookOptions(*webhookToAPI(&wh.h, wh.secret))
+	// PUT /projects/{project}/hooks/{hook_id}
+	apiObj, err := wh.c.c.EditHook(ctx, getRepoPath(wh.c.ref), wh.h.ID, &opts)
+	if err != nil {
+		return err
+	}
+	wh.h = *apiObj
+	return nil
+}
+
+// Delete deletes the webhook.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (wh *webhook) Delete(ctx context.Context) error {
+	// DELETE /projects/{project}/hooks/{hook_id}
+	return wh.c.c.DeleteHook(ctx, getRepoPath(wh.c.ref), wh.h.ID)
+}
+
+// Reconcile makes sure the desired state in this object (called "req" here) becomes
+// the actual state in the backing Git provider. The webhook is looked up by its URL.
+//
+// If req doesn't exist under the hood, it is created (actionTaken == true).
+// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+// If req is already the actual state, this is a no-op (actionTaken == false).
+//
+// The internal API object will be overridden with the received server data if actionTaken == true.
+func (wh *webhook) Reconcile(ctx context.Context) (bool, error) {
+	actual, err := wh.c.get(ctx, wh.h.URL)
+	if err != nil {
+		// Create if not found
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			// POST /projects/{project}/hooks
+			apiObj, err := wh.c.c.CreateHook(ctx, getRepoPath(wh.c.ref), webhookToAPI(&wh.h, wh.secret))
+			if err != nil {
+				return true, err
+			}
+			wh.h = *apiObj
+			return true, nil
+		}
+
+		// Unexpected path, Get should succeed or return NotFound
+		return false, err
+	}
+
+	// If the desired matches the actual state, do nothing
+	if wh.Get().Equals(actual.Get()) {
+		return false, nil
+	}
+	// If desired and actual state mis-match, update the found webhook
+	wh.h.ID = actual.h.ID
+	return true, wh.Update(ctx)
+}
+
+// validateWebhookAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateWebhookAPI(apiObj *gitlab.ProjectHook) error {
+	return validateAPIObject("GitLab.ProjectHook", func(validator validation.Validator) {
+		if apiObj.ID == 0 {
+			validator.Required("ID")
+		}
+		if apiObj.URL == "" {
+			validator.Required("URL")
+		}
+	})
+}
+
+// validateWebhookSupport returns ErrNoProviderSupport if info can't be expressed as a GitLab
+// project hook.
+func validateWebhookSupport(info *gitprovider.WebhookInfo) error {
+	for _, event := range info.Events {
+		if event == gitprovider.WebhookEventRelease {
+			return fmt.Errorf("cannot deliver release events: %w", gitprovider.ErrNoProviderSupport)
+		}
+	}
+	if info.ContentType != nil && *info.ContentType != gitprovider.WebhookContentTypeJSON {
+		return fmt.Errorf("cannot deliver %q payloads: %w", *info.ContentType, gitprovider.ErrNoProviderSupport)
+	}
+	if info.Active != nil && !*info.Active {
+		return fmt.Errorf("cannot deactivate webhooks: %w", gitprovider.ErrNoProviderSupport)
+	}
+	return nil
+}
+
+func webhookFromAPI(apiObj *gitlab.ProjectHook) gitprovider.WebhookInfo {
+	info := gitprovider.WebhookInfo{
+		ID:              int64(apiObj.ID),
+		URL:             apiObj.URL,
+		ContentType:     gitprovider.WebhookContentTypeVar(gitprovider.WebhookContentTypeJSON),
+		SSLVerification: gitprovider.BoolVar(apiObj.EnableSSLVerification),
+		Active:          gitprovider.BoolVar(true),
+	}
+	// Ignore the events that can't be expressed as a WebhookEvent
+	for _, e := range []struct {
+		event   gitprovider.WebhookEvent
+		enabled bool
+	}{
+		{gitprovider.WebhookEventPush, apiObj.PushEvents},
+		{gitprovider.WebhookEventTag, apiObj.TagPushEvents},
+		{gitprovider.WebhookEventPullRequest, apiObj.MergeRequestsEvents},
+		{gitprovider.WebhookEventIssue, apiObj.IssuesEvents},
+		{gitprovider.WebhookEventComment, apiObj.NoteEvents},
+	} {
+		if e.enabled {
+			info.Events = append(info.Events, e.event)
+		}
+	}
+	return info
+}
+
+func webhookInfoToAPIObj(info *gitprovider.WebhookInfo, apiObj *gitlab.ProjectHook) {
+	// Required fields, we assume info is validated, and hence these are set
+	apiObj.URL = info.URL
+	// optional fields
+	if len(info.Events) != 0 {
+		events := make(map[gitprovider.WebhookEvent]bool, len(info.Events))
+		for _, event := range info.Events {
+			events[event] = true
+		}
+		apiObj.PushEvents = events[gitprovider.WebhookEventPush]
+		apiObj.TagPushEvents = events[gitprovider.WebhookEventTag]
+		apiObj.MergeRequestsEvents = events[gitprovider.WebhookEventPullRequest]
+		apiObj.IssuesEvents = events[gitprovider.WebhookEventIssue]
+		apiObj.NoteEvents = events[gitprovider.WebhookEventComment]
+	}
+	if info.SSLVerification != nil {
+		apiObj.EnableSSLVerification = *info.SSLVerification
+	}
+}
+
+// webhookToAPI returns the options for creating apiObj, with the given secret if set. The
+// options are complete, so that they can also be used for replacing a webhook.
+func webhookToAPI(apiObj *gitlab.ProjectHook, secret string) *gitlab.AddProjectHookOptions {
+	opts := &gitlab.AddProjectHookOptions{
+		URL:                      &apiObj.URL,
+		ConfidentialNoteEvents:   &apiObj.ConfidentialNoteEvents,
+		PushEvents:               &apiObj.PushEvents,
+		PushEventsBranchFilter:   &apiObj.PushEventsBranchFilter,
+		IssuesEvents:             &apiObj.IssuesEvents,
+		ConfidentialIssuesEvents: &apiObj.ConfidentialIssuesEvents,
+		MergeRequestsEvents:      &apiObj.MergeRequestsEvents,
+		TagPushEvents:            &apiObj.TagPushEvents,
+		NoteEvents:               &apiObj.NoteEvents,
+		JobEvents:                &apiObj.JobEvents,
+		PipelineEvents:           &apiObj.PipelineEvents,
+		WikiPageEvents:           &apiObj.WikiPageEvents,
+		DeploymentEvents:         &apiObj.DeploymentEvents,
+		EnableSSLVerification:    &apiObj.EnableSSLVerification,
+	}
+	if len(secret) != 0 {
+		opts.Token = &secret
+	}
+	return opts
+}
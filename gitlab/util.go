@@ -15,6 +15,15 @@ const (
 	alreadyExistsMagicString = "name: [has already been taken]"
 	alreadySharedWithGroup   = "already shared with this group"
 	masterBranchName         = "master"
+
+	// branchAlreadyExistsMessage and invalidRefMessage are part of the messages of the
+	// "400 Bad Request" responses when creating a branch.
+	branchAlreadyExistsMessage = "Branch already exists"
+	invalidRefMessage          = "Invalid reference name"
+
+	// alreadyExistsMessage is part of the messages of the "400 Bad Request" and "409 Conflict"
+	// responses when creating an existing tag or release.
+	alreadyExistsMessage = "already exists"
 )
 
 func getRepoPath(ref gitprovider.RepositoryRef) string {
@@ -116,6 +125,149 @@ func allDeployKeyPages(opts *gitlab.ListProjectDeployKeysOptions, fn func() (*gi
 	}
 }
 
+func allHookPages(opts *gitlab.ListProjectHooksOptions, fn func() (*gitlab.Response, error)) error {
+	for {
+		resp, err := fn()
+		if err != nil {
+			return err
+		}
+		if resp.NextPage == 0 {
+			return nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+func allMergeRequestPages(opts *gitlab.ListProjectMergeRequestsOptions, fn func() (*gitlab.Response, error)) error {
+	for {
+		resp, err := fn()
+		if err != nil {
+			return err
+		}
+		if resp.NextPage == 0 {
+			return nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+func allBranchPages(opts *gitlab.ListBranchesOptions, fn func() (*gitlab.Response, error)) error {
+	for {
+		resp, err := fn()
+		if err != nil {
+			return err
+		}
+		if resp.NextPage == 0 {
+			return nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+func allMergeRequestNotePages(opts *gitlab.ListMergeRequestNotesOptions, fn func() (*gitlab.Response, error)) error {
+	for {
+		resp, err := fn()
+		if err != nil {
+			return err
+		}
+		if resp.NextPage == 0 {
+			return nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+func allIssuePages(opts *gitlab.ListProjectIssuesOptions, fn func() (*gitlab.Response, error)) error {
+	for {
+		resp, err := fn()
+		if err != nil {
+			return err
+		}
+		if resp.NextPage == 0 {
+			return nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+func allIssueNotePages(opts *gitlab.ListIssueNotesOptions, fn func() (*gitlab.Response, error)) error {
+	for {
+		resp, err := fn()
+		if err != nil {
+			return err
+		}
+		if resp.NextPage == 0 {
+			return nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+func allTreePages(opts *gitlab.ListTreeOptions, fn func() (*gitlab.Response, error)) error {
+	for {
+		resp, err := fn()
+		if err != nil {
+			return err
+		}
+		if resp.NextPage == 0 {
+			return nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+func allTagPages(opts *gitlab.ListTagsOptions, fn func() (*gitlab.Response, error)) error {
+	for {
+		resp, err := fn()
+		if err != nil {
+			return err
+		}
+		if resp.NextPage == 0 {
+			return nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+func allReleasePages(opts *gitlab.ListReleasesOptions, fn func() (*gitlab.Response, error)) error {
+	for {
+		resp, err := fn()
+		if err != nil {
+			return err
+		}
+		if resp.NextPage == 0 {
+			return nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+func allCommitStatusPages(opts *gitlab.GetCommitStatusesOptions, fn func() (*gitlab.Response, error)) error {
+	for {
+		resp, err := fn()
+		if err != nil {
+			return err
+		}
+		if resp.NextPage == 0 {
+			return nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+func allReleaseLinkPages(opts *gitlab.ListReleaseLinksOptions, fn func() (*gitlab.Response, error)) error {
+	for {
+		resp, err := fn()
+		if err != nil {
+			return err
+		}
+		if resp.NextPage == 0 {
+			return nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
 // validateUserRepositoryRef makes sure the UserRepositoryRef is valid for GitHub's usage.
 func validateUserRepositoryRef(ref gitprovider.UserRepositoryRef, expectedDomain string) error {
 	// Make sure the RepositoryRef fields are valid
@@ -240,3 +392,32 @@ func handleHTTPError(err error) error {
 	// Do nothing, just pipe through the unknown err
 	return err
 }
+
+// handleBranchError is like handleHTTPError, but also maps the "400 Bad Request" responses
+// of the branches API to ErrAlreadyExists and ErrNotFound. The messages are matched as
+// substrings, as go-gitlab wraps them, e.g. as "{message: Branch already exists}".
+func handleBranchError(err error) error {
+	glErrorResponse := &gitlab.ErrorResponse{}
+	if errors.As(err, &glErrorResponse) && glErrorResponse.Response.StatusCode == http.StatusBadRequest {
+		switch {
+		case strings.Contains(glErrorResponse.Message, branchAlreadyExistsMessage):
+			return validation.NewMultiError(err, gitprovider.ErrAlreadyExists)
+		case strings.Contains(glErrorResponse.Message, invalidRefMessage):
+			return validation.NewMultiError(err, gitprovider.ErrNotFound)
+		}
+	}
+	return handleHTTPError(err)
+}
+
+// handleTagError is like handleHTTPError, but also maps the "400 Bad Request" responses of the
+// tags API and the "409 Conflict" responses of the releases API for existing tags and releases
+// to ErrAlreadyExists.
+func handleTagError(err error) error {
+	glErrorResponse := &gitlab.ErrorResponse{}
+	if errors.As(err, &glErrorResponse) &&
+		(glErrorResponse.Response.StatusCode == http.StatusBadRequest || glErrorResponse.Response.StatusCode == http.StatusConflict) &&
+		strings.Contains(glErrorResponse.Message, alreadyExistsMessage) {
+		return validation.NewMultiError(err, gitprovider.ErrAlreadyExists)
+	}
+	return handleHTTPError(err)
+}
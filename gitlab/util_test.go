@@ -17,8 +17,10 @@ limitations under the License.
 package gitlab
 
 import (
+	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strings"
 	"testing"
 
 	"github.com/fluxcd/go-git-providers/gitprovider"
@@ -133,3 +135,77 @@ func Test_allGroupPages(t *testing.T) {
 		})
 	}
 }
+
+func Test_handleBranchError(t *testing.T) {
+	// newErr parses the response body like go-gitlab does, which wraps the messages
+	newErr := func(statusCode int, body string) error {
+		return gitlab.CheckResponse(&http.Response{
+			Request:    &http.Request{Method: "POST", URL: &url.URL{}},
+			StatusCode: statusCode,
+			Body:       ioutil.NopCloser(strings.NewReader(body)),
+		})
+	}
+	tests := []struct {
+		name         string
+		err          error
+		expectedErrs []error
+	}{
+		{
+			name:         "existing branch",
+			err:          newErr(http.StatusBadRequest, `{"message":"Branch already exists"}`),
+			expectedErrs: []error{gitprovider.ErrAlreadyExists},
+		},
+		{
+			name:         "invalid branch ref",
+			err:          newErr(http.StatusBadRequest, `{"message":"Invalid reference name: missing"}`),
+			expectedErrs: []error{gitprovider.ErrNotFound},
+		},
+		{
+			name:         "other branch error",
+			err:          newErr(http.StatusBadRequest, `{"message":"Branch name is invalid"}`),
+			expectedErrs: []error{&gitprovider.HTTPError{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validation.TestExpectErrors(t, tt.name, handleBranchError(tt.err), tt.expectedErrs...)
+		})
+	}
+}
+
+func Test_handleTagError(t *testing.T) {
+	// newErr parses the response body like go-gitlab does, which wraps the messages
+	newErr := func(statusCode int, body string) error {
+		return gitlab.CheckResponse(&http.Response{
+			Request:    &http.Request{Method: "POST", URL: &url.URL{}},
+			StatusCode: statusCode,
+			Body:       ioutil.NopCloser(strings.NewReader(body)),
+		})
+	}
+	tests := []struct {
+		name         string
+		err          error
+		expectedErrs []error
+	}{
+		{
+			name:         "existing tag",
+			err:          newErr(http.StatusBadRequest, `{"message":"Tag v1.0.0 already exists"}`),
+			expectedErrs: []error{gitprovider.ErrAlreadyExists},
+		},
+		{
+			name:         "existing release",
+			err:          newErr(http.StatusConflict, `{"message":"Release already exists"}`),
+			expectedErrs: []error{gitprovider.ErrAlreadyExists},
+		},
+		{
+			name:         "other tag error",
+			err:          newErr(http.StatusBadRequest, `{"message":"Target is invalid"}`),
+			expectedErrs: []error{&gitprovider.HTTPError{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validation.TestExpectErrors(t, tt.name, handleTagError(tt.err), tt.expectedErrs...)
+		})
+	}
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gregjones/httpcache"
+)
+
+// Backend stores the cached HTTP responses, keyed by request. It is compatible with httpcache.Cache.
+// Implementations must be safe for concurrent use.
+type Backend interface {
+	// Get returns the cached response for key, and whether it was found.
+	Get(key string) (responseBytes []byte, ok bool)
+	// Set stores the response for key.
+	Set(key string, responseBytes []byte)
+	// Delete removes the response for key, if any.
+	Delete(key string)
+}
+
+// Backend is compatible with httpcache.Cache.
+var _ httpcache.Cache = Backend(nil)
+
+// Stats are the statistics of a Backend.
+type Stats struct {
+	// Hits is the number of lookups which found a cached response. Note that stale responses are
+	// revalidated using conditional requests, which don't count against the quota of most providers.
+	Hits uint64 `json:"hits"`
+	// Misses is the number of lookups which found no cached response.
+	Misses uint64 `json:"misses"`
+	// Errors is the number of failed operations of the backend, e.g. I/O errors. Failed lookups
+	// are also counted as misses.
+	Errors uint64 `json:"errors"`
+}
+
+// StatsReporter is implemented by the backends reporting Stats, which all backends in this
+// package do.
+type StatsReporter interface {
+	// Stats returns a snapshot of the statistics of the backend.
+	Stats() Stats
+}
+
+// counters implements StatsReporter, and is embedded by the backends of this package.
+type counters struct {
+	hits, misses, errors uint64
+}
+
+// lookup counts the result of a lookup.
+func (c *counters) lookup(ok bool) {
+	if ok {
+		atomic.AddUint64(&c.hits, 1)
+	} else {
+		atomic.AddUint64(&c.misses, 1)
+	}
+}
+
+// failure counts a failed operation.
+func (c *counters) failure() {
+	atomic.AddUint64(&c.errors, 1)
+}
+
+// Stats implements StatsReporter.
+func (c *counters) Stats() Stats {
+	return Stats{
+		Hits:   atomic.LoadUint64(&c.hits),
+		Misses: atomic.LoadUint64(&c.misses),
+		Errors: atomic.LoadUint64(&c.errors),
+	}
+}
+
+// NewMemoryBackend returns an unbounded in-memory Backend. Use NewLRUBackend to bound its size.
+func NewMemoryBackend() Backend {
+	return &memoryBackend{items: map[string][]byte{}}
+}
+
+// memoryBackend is an unbounded in-memory Backend.
+type memoryBackend struct {
+	counters
+
+	mu    sync.RWMutex
+	items map[string][]byte
+}
+
+// Get implements Backend.
+func (b *memoryBackend) Get(key string) ([]byte, bool) {
+	b.mu.RLock()
+	value, ok := b.items[key]
+	b.mu.RUnlock()
+	b.lookup(ok)
+	return value, ok
+}
+
+// Set implements Backend.
+func (b *memoryBackend) Set(key string, responseBytes []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.items[key] = responseBytes
+}
+
+// Delete implements Backend.
+func (b *memoryBackend) Delete(key string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.items, key)
+}
+
+// ForCredential returns a view of backend, which namespaces the keys by a hash of credential, e.g.
+// the access token of the client. The cache transport sits before the authentication transport,
+// hence keys are otherwise only derived from the request URL, and clients using different
+// credentials would get each other's cached responses when sharing a backend.
+func ForCredential(backend Backend, credential string) Backend {
+	sum := sha256.Sum256([]byte(credential))
+	return &credentialBackend{Backend: backend, prefix: hex.EncodeToString(sum[:16]) + "/"}
+}
+
+// credentialBackend prefixes the keys of the wrapped Backend.
+type credentialBackend struct {
+	Backend
+	prefix string
+}
+
+// Get implements Backend.
+func (b *credentialBackend) Get(key string) ([]byte, bool) {
+	return b.Backend.Get(b.prefix + key)
+}
+
+// Set implements Backend.
+func (b *credentialBackend) Set(key string, responseBytes []byte) {
+	b.Backend.Set(b.prefix+key, responseBytes)
+}
+
+// Delete implements Backend.
+func (b *credentialBackend) Delete(key string) {
+	b.Backend.Delete(b.prefix + key)
+}
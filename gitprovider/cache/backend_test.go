@@ -0,0 +1,179 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+// mapStore is an in-memory ExternalStore.
+type mapStore struct {
+	mu    sync.Mutex
+	items map[string][]byte
+	err   error
+}
+
+func (s *mapStore) Get(_ context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	value, ok := s.items[key]
+	if !ok {
+		return nil, ErrCacheMiss
+	}
+	return value, nil
+}
+
+func (s *mapStore) Set(_ context.Context, key string, value []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.items[key] = value
+	return nil
+}
+
+func (s *mapStore) Delete(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	delete(s.items, key)
+	return nil
+}
+
+func TestBackends(t *testing.T) {
+	tests := []struct {
+		name       string
+		newBackend func(t *testing.T) Backend
+	}{
+		{
+			name:       "memory",
+			newBackend: func(*testing.T) Backend { return NewMemoryBackend() },
+		},
+		{
+			name:       "lru",
+			newBackend: func(*testing.T) Backend { return NewLRUBackend(1024) },
+		},
+		{
+			name: "disk",
+			newBackend: func(t *testing.T) Backend {
+				b, err := NewDiskBackend(t.TempDir())
+				if err != nil {
+					t.Fatal(err)
+				}
+				return b
+			},
+		},
+		{
+			name: "external",
+			newBackend: func(*testing.T) Backend {
+				return NewExternalBackend(&mapStore{items: map[string][]byte{}}, ExternalOptions{KeyPrefix: "gitprovider:"})
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := tt.newBackend(t)
+			key := "https://api.github.com/repos/fluxcd/go-git-providers"
+			if _, ok := b.Get(key); ok {
+				t.Error("Get() of a missing key succeeded")
+			}
+			b.Set(key, []byte("foo"))
+			b.Set(key, []byte("bar"))
+			if got, ok := b.Get(key); !ok || !bytes.Equal(got, []byte("bar")) {
+				t.Errorf("Get() = %q, %v, want %q", got, ok, "bar")
+			}
+			b.Delete(key)
+			b.Delete(key)
+			if _, ok := b.Get(key); ok {
+				t.Error("Get() of a deleted key succeeded")
+			}
+
+			want := Stats{Hits: 1, Misses: 2}
+			if got := b.(StatsReporter).Stats(); got != want {
+				t.Errorf("Stats() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestLRUBackend_eviction(t *testing.T) {
+	// Every entry takes 2 bytes
+	b := NewLRUBackend(6)
+	b.Set("a", []byte("1"))
+	b.Set("b", []byte("2"))
+	b.Set("c", []byte("3"))
+	// Use "a", so that "b" is the least recently used
+	b.Get("a")
+	b.Set("d", []byte("4"))
+	for key, want := range map[string]bool{"a": true, "b": false, "c": true, "d": true} {
+		if _, ok := b.Get(key); ok != want {
+			t.Errorf("Get(%q) found = %v, want %v", key, ok, want)
+		}
+	}
+
+	// Too large to be cached at all
+	b.Set("e", []byte("123456"))
+	if _, ok := b.Get("e"); ok {
+		t.Error("Get() of an entry larger than the cache succeeded")
+	}
+	if size := b.(*lruBackend).size; size != 6 {
+		t.Errorf("size = %d, want 6", size)
+	}
+}
+
+func TestDiskBackend_persistence(t *testing.T) {
+	dir := t.TempDir()
+	b1, err := NewDiskBackend(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b1.Set("key", []byte("value"))
+
+	// Another process or replica sharing the directory
+	b2, err := NewDiskBackend(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := b2.Get("key"); !ok || string(got) != "value" {
+		t.Errorf("Get() = %q, %v, want %q", got, ok, "value")
+	}
+}
+
+func TestExternalBackend_errors(t *testing.T) {
+	store := &mapStore{items: map[string][]byte{}, err: errors.New("connection refused")}
+	b := NewExternalBackend(store, ExternalOptions{})
+	b.Set("key", []byte("value"))
+	if _, ok := b.Get("key"); ok {
+		t.Error("Get() succeeded despite the store failing")
+	}
+	b.Delete("key")
+
+	want := Stats{Misses: 1, Errors: 3}
+	if got := b.(StatsReporter).Stats(); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+}
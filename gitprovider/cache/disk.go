@@ -0,0 +1,88 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// NewDiskBackend returns a Backend storing the responses as files in dir, which is created if
+// needed. The cache hence survives restarts, and can be shared by the processes having access
+// to dir. The size of the directory is not bounded.
+func NewDiskBackend(dir string) (Backend, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("creating cache directory: %w", err)
+	}
+	return &diskBackend{dir: dir}, nil
+}
+
+// diskBackend is a Backend storing the responses in a directory.
+type diskBackend struct {
+	counters
+
+	dir string
+}
+
+// path returns the path of the file for key. Keys are URLs, so they are hashed to get valid,
+// fixed-length file names.
+func (b *diskBackend) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(b.dir, hex.EncodeToString(sum[:]))
+}
+
+// Get implements Backend.
+func (b *diskBackend) Get(key string) ([]byte, bool) {
+	value, err := ioutil.ReadFile(b.path(key))
+	if err != nil && !os.IsNotExist(err) {
+		b.failure()
+	}
+	b.lookup(err == nil)
+	return value, err == nil
+}
+
+// Set implements Backend. The file is written atomically, so that concurrent readers never see
+// a partial response.
+func (b *diskBackend) Set(key string, responseBytes []byte) {
+	f, err := ioutil.TempFile(b.dir, ".tmp-")
+	if err != nil {
+		b.failure()
+		return
+	}
+	_, err = f.Write(responseBytes)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), b.path(key))
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		b.failure()
+	}
+}
+
+// Delete implements Backend.
+func (b *diskBackend) Delete(key string) {
+	if err := os.Remove(b.path(key)); err != nil && !os.IsNotExist(err) {
+		b.failure()
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// DefaultExternalTimeout is the default value of ExternalOptions.Timeout.
+const DefaultExternalTimeout = 5 * time.Second
+
+// ErrCacheMiss must be returned by ExternalStore.Get if the key doesn't exist.
+var ErrCacheMiss = errors.New("cache miss")
+
+// ExternalStore is the interface to implement for storing the cache in an external key-value
+// store, like Redis or Memcached, e.g. for sharing it between replicas.
+type ExternalStore interface {
+	// Get returns the value for key, or ErrCacheMiss if it doesn't exist.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Set stores the value for key.
+	Set(ctx context.Context, key string, value []byte) error
+	// Delete removes the value for key. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// ExternalOptions configures the Backend returned by NewExternalBackend.
+type ExternalOptions struct {
+	// KeyPrefix is prepended to all keys, e.g. to share the store with other applications.
+	// Default: ""
+	KeyPrefix string
+
+	// Timeout bounds every operation on the store.
+	// Default: DefaultExternalTimeout
+	Timeout time.Duration
+}
+
+// NewExternalBackend returns a Backend using an ExternalStore. As a cache must not break the
+// requests, errors of the store are treated as misses, and counted in Stats.Errors.
+func NewExternalBackend(store ExternalStore, opts ExternalOptions) Backend {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultExternalTimeout
+	}
+	return &externalBackend{store: store, opts: opts}
+}
+
+// externalBackend is a Backend using an ExternalStore.
+type externalBackend struct {
+	counters
+
+	store ExternalStore
+	opts  ExternalOptions
+}
+
+// Get implements Backend.
+func (b *externalBackend) Get(key string) ([]byte, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), b.opts.Timeout)
+	defer cancel()
+
+	value, err := b.store.Get(ctx, b.opts.KeyPrefix+key)
+	if err != nil && !errors.Is(err, ErrCacheMiss) {
+		b.failure()
+	}
+	b.lookup(err == nil)
+	return value, err == nil
+}
+
+// Set implements Backend.
+func (b *externalBackend) Set(key string, responseBytes []byte) {
+	ctx, cancel := context.WithTimeout(context.Background(), b.opts.Timeout)
+	defer cancel()
+
+	if err := b.store.Set(ctx, b.opts.KeyPrefix+key, responseBytes); err != nil {
+		b.failure()
+	}
+}
+
+// Delete implements Backend.
+func (b *externalBackend) Delete(key string) {
+	ctx, cancel := context.WithTimeout(context.Background(), b.opts.Timeout)
+	defer cancel()
+
+	if err := b.store.Delete(ctx, b.opts.KeyPrefix+key); err != nil {
+		b.failure()
+	}
+}
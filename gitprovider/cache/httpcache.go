@@ -17,18 +17,32 @@ limitations under the License.
 package cache
 
 import (
+	"io"
 	"net/http"
 
 	"github.com/gregjones/httpcache"
 )
 
-// TODO: Implement an unit test for this package.
-
 // NewHTTPCacheTransport is a gitprovider.ChainableRoundTripperFunc which adds
 // HTTP Conditional Requests caching for the backend, if the server supports it.
+// The responses are cached in memory, use NewHTTPCacheTransportFunc for other backends.
 func NewHTTPCacheTransport(in http.RoundTripper) http.RoundTripper {
+	return newCacheRoundtripper(in, NewMemoryBackend())
+}
+
+// NewHTTPCacheTransportFunc returns a gitprovider.ChainableRoundTripperFunc which adds
+// HTTP Conditional Requests caching for the backend, if the server supports it, storing
+// the responses in the given Backend.
+func NewHTTPCacheTransportFunc(backend Backend) func(in http.RoundTripper) http.RoundTripper {
+	return func(in http.RoundTripper) http.RoundTripper {
+		return newCacheRoundtripper(in, backend)
+	}
+}
+
+// newCacheRoundtripper returns a cacheRoundtripper using in and backend.
+func newCacheRoundtripper(in http.RoundTripper, backend Backend) http.RoundTripper {
 	// Create a new httpcache high-level Transport
-	t := httpcache.NewMemoryCacheTransport()
+	t := httpcache.NewTransport(backend)
 	// Configure the httpcache Transport to use in as its underlying Transport.
 	// If in is nil, http.DefaultTransport will be used.
 	t.Transport = in
@@ -70,6 +84,25 @@ func (r *cacheRoundtripper) RoundTrip(req *http.Request) (*http.Response, error)
 	// Don't cache anything but "200 OK" requests
 	if resp == nil || resp.StatusCode != http.StatusOK {
 		r.Transport.Cache.Delete(cacheKey)
+		// httpcache stores the response once its body has been read, so invalidate it again then
+		if resp != nil && resp.Body != nil {
+			resp.Body = &invalidatingReadCloser{ReadCloser: resp.Body, invalidate: func() {
+				r.Transport.Cache.Delete(cacheKey)
+			}}
+		}
 	}
 	return resp, err
 }
+
+// invalidatingReadCloser calls invalidate when closed.
+type invalidatingReadCloser struct {
+	io.ReadCloser
+	invalidate func()
+}
+
+// Close implements io.Closer.
+func (r *invalidatingReadCloser) Close() error {
+	err := r.ReadCloser.Close()
+	r.invalidate()
+	return err
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gregjones/httpcache"
+)
+
+func TestHTTPCacheTransport(t *testing.T) {
+	var mu sync.Mutex
+	calls, status := 0, http.StatusOK
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		if r.Method == http.MethodGet && r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("body"))
+	}))
+	defer srv.Close()
+
+	backend := NewLRUBackend(1 << 20)
+	client := &http.Client{Transport: NewHTTPCacheTransportFunc(backend)(nil)}
+	get := func(method string) (fromCache bool) {
+		t.Helper()
+		req, _ := http.NewRequest(method, srv.URL+"/repos/foo", strings.NewReader(""))
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := ioutil.ReadAll(resp.Body)
+		if resp.StatusCode == http.StatusOK && string(body) != "body" {
+			t.Errorf("body = %q, want %q", body, "body")
+		}
+		_, fromCache = resp.Header[httpcache.XFromCache]
+		return fromCache
+	}
+
+	if get(http.MethodGet) {
+		t.Error("first GET was served from the cache")
+	}
+	// Revalidated using If-None-Match
+	if !get(http.MethodGet) {
+		t.Error("second GET wasn't served from the cache")
+	}
+	// Modifying the resource invalidates the cache
+	get(http.MethodPatch)
+	if get(http.MethodGet) {
+		t.Error("GET after PATCH was served from the cache")
+	}
+
+	// Errors aren't cached
+	mu.Lock()
+	status = http.StatusNotFound
+	mu.Unlock()
+	get(http.MethodDelete)
+	get(http.MethodGet)
+	if _, ok := backend.Get(srv.URL + "/repos/foo"); ok {
+		t.Error("a 404 response was cached")
+	}
+
+	if got := backend.(StatsReporter).Stats(); got.Hits < 1 || got.Misses < 2 {
+		t.Errorf("Stats() = %+v, want at least 1 hit and 2 misses", got)
+	}
+	if calls != 6 {
+		t.Errorf("server got %d calls, want 6", calls)
+	}
+}
+
+func TestHTTPCacheTransport_sharedBackend(t *testing.T) {
+	// The server only returns the private response to the token it belongs to
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "private, max-age=60")
+		_, _ = w.Write([]byte("private to " + r.Header.Get("Authorization")))
+	}))
+	defer srv.Close()
+
+	backend := NewLRUBackend(1 << 20)
+	newClient := func(token string) *http.Client {
+		// Like in the provider clients, the cache transport sits before the authentication
+		auth := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			req = req.Clone(req.Context())
+			req.Header.Set("Authorization", token)
+			return http.DefaultTransport.RoundTrip(req)
+		})
+		return &http.Client{Transport: NewHTTPCacheTransportFunc(ForCredential(backend, token))(auth)}
+	}
+	get := func(client *http.Client) string {
+		t.Helper()
+		resp, err := client.Get(srv.URL + "/user/repos")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, _ := ioutil.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	alice, bob := newClient("alice"), newClient("bob")
+	for i := 0; i < 2; i++ {
+		if got := get(alice); got != "private to alice" {
+			t.Errorf("GET with the token of alice = %q", got)
+		}
+		if got := get(bob); got != "private to bob" {
+			t.Errorf("GET with the token of bob = %q", got)
+		}
+	}
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
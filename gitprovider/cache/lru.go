@@ -0,0 +1,108 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+// NewLRUBackend returns an in-memory Backend holding at most maxBytes bytes of responses (keys
+// included), evicting the least recently used responses when full. Responses larger than
+// maxBytes aren't cached.
+func NewLRUBackend(maxBytes int64) Backend {
+	return &lruBackend{
+		maxBytes: maxBytes,
+		items:    map[string]*list.Element{},
+		order:    list.New(),
+	}
+}
+
+// lruBackend is a size-bounded in-memory Backend.
+type lruBackend struct {
+	counters
+
+	maxBytes int64
+
+	mu    sync.Mutex
+	size  int64
+	items map[string]*list.Element
+	// order lists the entries from the most to the least recently used
+	order *list.List
+}
+
+// lruEntry is an element of lruBackend.order.
+type lruEntry struct {
+	key   string
+	value []byte
+}
+
+// size returns the number of bytes accounted for the entry.
+func (e *lruEntry) size() int64 {
+	return int64(len(e.key) + len(e.value))
+}
+
+// Get implements Backend.
+func (b *lruBackend) Get(key string) ([]byte, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	elem, ok := b.items[key]
+	b.lookup(ok)
+	if !ok {
+		return nil, false
+	}
+	b.order.MoveToFront(elem)
+	return elem.Value.(*lruEntry).value, true
+}
+
+// Set implements Backend.
+func (b *lruBackend) Set(key string, responseBytes []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.remove(key)
+	entry := &lruEntry{key: key, value: responseBytes}
+	if entry.size() > b.maxBytes {
+		return
+	}
+	b.items[key] = b.order.PushFront(entry)
+	b.size += entry.size()
+
+	for b.size > b.maxBytes {
+		b.remove(b.order.Back().Value.(*lruEntry).key)
+	}
+}
+
+// Delete implements Backend.
+func (b *lruBackend) Delete(key string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.remove(key)
+}
+
+// remove removes the entry for key, if any. b.mu must be held.
+func (b *lruBackend) remove(key string) {
+	elem, ok := b.items[key]
+	if !ok {
+		return
+	}
+	b.order.Remove(elem)
+	delete(b.items, key)
+	b.size -= elem.Value.(*lruEntry).size()
+}
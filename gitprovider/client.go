@@ -37,6 +37,14 @@ type Client interface {
 	// permission. Permissions should be coarse-grained and applicable to *all* providers.
 	HasTokenPermission(ctx context.Context, permission TokenPermission) (bool, error)
 
+	// Quota returns the current API request quota of the client, e.g. for scheduling work.
+	// Depending on the provider, the quota is requested from the server, or observed from the
+	// rate limit headers of the previous responses.
+	//
+	// ErrNoProviderSupport is returned if the provider doesn't report rate limits, or if they
+	// haven't been observed yet.
+	Quota(ctx context.Context) (Quota, error)
+
 	// Raw returns the Go client used under the hood to access the Git provider.
 	Raw() interface{}
 }
@@ -207,6 +215,36 @@ type DeployKeyClient interface {
 	Reconcile(ctx context.Context, req DeployKeyInfo) (resp DeployKey, actionTaken bool, err error)
 }
 
+// WebhookClient operates on the webhooks of a specific repository or organization.
+// This client can be accessed through UserRepository.Webhooks() and Organization.Webhooks().
+type WebhookClient interface {
+	// Get returns the webhook delivering to the given URL. If there are multiple such
+	// webhooks, the first one is returned.
+	//
+	// ErrNotFound is returned if the resource does not exist.
+	Get(ctx context.Context, url string) (Webhook, error)
+
+	// List lists all webhooks of the repository or organization.
+	//
+	// List returns all available webhooks, using multiple paginated requests if needed.
+	List(ctx context.Context) ([]Webhook, error)
+
+	// Create creates a webhook with the given specifications.
+	//
+	// ErrAlreadyExists will be returned if a webhook delivering to req.URL already exists.
+	// ErrNoProviderSupport is returned if the provider doesn't support a field of req, e.g.
+	// an event or the content type.
+	Create(ctx context.Context, req WebhookInfo) (Webhook, error)
+
+	// Reconcile makes sure the given desired state (req) becomes the actual state in the backing Git provider.
+	// The webhook is looked up by req.URL.
+	//
+	// If req doesn't exist under the hood, it is created (actionTaken == true).
+	// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+	// If req is already the actual state, this is a no-op (actionTaken == false).
+	Reconcile(ctx context.Context, req WebhookInfo) (resp Webhook, actionTaken bool, err error)
+}
+
 // CommitClient operates on the commits list for a specific repository.
 // This client can be accessed through Repository.Commits().
 type CommitClient interface {
@@ -214,19 +252,356 @@ type CommitClient interface {
 	// ListPage lists repository commits of the given page and page size.
 	ListPage(ctx context.Context, branch string, perPage int, page int) ([]Commit, error)
 	// Create creates a commit with the given specifications.
-	Create(ctx context.Context, branch string, message string, files []CommitFile) (Commit, error)
+	//
+	// Every file is created, updated, deleted or moved as specified by its Action. Files without
+	// an Action are deleted if their Content is nil, and created or updated otherwise.
+	// ErrNoProviderSupport is returned if the provider can't express an action, encoding or mode,
+	// or can't set the Author or Committer given in opts.
+	//
+	// If branch doesn't exist, it is created from the head of the StartBranch given in opts. In an
+	// empty repository, the commit is the first commit of branch. Otherwise, ErrNotFound is returned
+	// if branch, or StartBranch when used, doesn't exist.
+	//
+	// The commit is never forced onto branch. ErrConflict is returned if the head of branch isn't
+	// the ExpectedParentSHA given in opts, or if it moved while the commit was being created.
+	Create(ctx context.Context, branch string, message string, files []CommitFile, opts ...CommitCreateOption) (Commit, error)
+}
+
+// CommitStatusClient operates on the statuses of the commits of a specific repository, e.g.
+// for reporting CI results.
+// This client can be accessed through Repository.CommitStatuses().
+type CommitStatusClient interface {
+	// Get returns the combined status of the commit with the given sha, i.e. the latest
+	// status of each context.
+	//
+	// Get uses multiple paginated requests if needed.
+	Get(ctx context.Context, sha string) (CombinedCommitStatus, error)
+
+	// List lists all statuses of the commit with the given sha, newest first, including
+	// the statuses replaced by newer ones with the same context.
+	//
+	// List returns all available statuses, using multiple paginated requests if needed.
+	List(ctx context.Context, sha string) ([]CommitStatus, error)
+
+	// Create creates a status for the commit with the given sha, which replaces the status
+	// with the same req.Context in the combined status of the commit.
+	//
+	// ErrNotFound is returned if the commit doesn't exist.
+	Create(ctx context.Context, sha string, req CommitStatusInfo) (CommitStatus, error)
+}
+
+// FileClient reads the files of a specific repository.
+// This client can be accessed through Repository.Files().
+type FileClient interface {
+	// Get returns the file at path as of ref, i.e. a branch, tag or commit sha, including its
+	// content. Large files are supported up to the limits of the provider.
+	//
+	// ErrNotFound is returned if ref doesn't exist, or if path isn't a file as of ref.
+	Get(ctx context.Context, path, ref string) (File, error)
+
+	// List lists the files and directories in the directory at dirPath as of ref. An empty
+	// dirPath lists the root directory of the repository. The content of files isn't returned.
+	//
+	// List returns all available entries, using multiple paginated requests if needed.
+	// ErrNotFound is returned if ref doesn't exist, or if dirPath isn't a directory as of ref.
+	List(ctx context.Context, dirPath, ref string) ([]File, error)
 }
 
 // BranchClient operates on the branches for a specific repository.
 // This client can be accessed through Repository.Branches().
 type BranchClient interface {
+	// Get returns the branch with the given name.
+	//
+	// ErrNotFound is returned if the resource does not exist.
+	Get(ctx context.Context, branch string) (Branch, error)
+
+	// List lists all branches of the repository.
+	//
+	// List returns all available branches, using multiple paginated requests if needed.
+	List(ctx context.Context) ([]Branch, error)
+
 	// Create creates a branch with the given specifications.
 	Create(ctx context.Context, branch, sha string) error
+
+	// CreateFrom creates a branch starting at the head of another branch, or at a tag. The
+	// source ref is resolved by the provider, so its SHA doesn't need to be known.
+	//
+	// ErrNotFound is returned if from doesn't exist, and ErrAlreadyExists if branch does.
+	CreateFrom(ctx context.Context, branch, from string) (Branch, error)
+
+	// Delete deletes the branch with the given name.
+	//
+	// ErrDestructiveCallDisallowed is returned if the client wasn't set up with destructive
+	// API calls enabled. ErrNotFound is returned if the resource does not exist.
+	Delete(ctx context.Context, branch string) error
+}
+
+// BranchProtectionClient operates on the branch protection rules for a specific repository.
+// This client can be accessed through Repository.BranchProtections().
+type BranchProtectionClient interface {
+	// Get returns the protection rules of the branch with the given name.
+	//
+	// ErrNotFound is returned if the branch isn't protected.
+	Get(ctx context.Context, branch string) (BranchProtection, error)
+
+	// Reconcile makes sure the given desired state (req) becomes the actual state in the backing Git provider.
+	// The branch is protected by req.Branch; it must exist in the repository.
+	//
+	// If req doesn't exist under the hood, it is created (actionTaken == true).
+	// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+	// If req is already the actual state, this is a no-op (actionTaken == false).
+	//
+	// ErrNoProviderSupport is returned if req sets a rule the provider can't express.
+	Reconcile(ctx context.Context, req BranchProtectionInfo) (resp BranchProtection, actionTaken bool, err error)
+
+	// Delete removes the protection rules of the branch with the given name. The branch itself
+	// is kept.
+	//
+	// ErrNotFound is returned if the branch isn't protected.
+	Delete(ctx context.Context, branch string) error
+}
+
+// TagClient operates on the tags for a specific repository.
+// This client can be accessed through Repository.Tags().
+type TagClient interface {
+	// Get returns the tag with the given name.
+	//
+	// ErrNotFound is returned if the resource does not exist.
+	Get(ctx context.Context, name string) (Tag, error)
+
+	// List lists all tags of the repository.
+	//
+	// List returns all available tags, using multiple paginated requests if needed.
+	List(ctx context.Context) ([]Tag, error)
+
+	// Create creates a tag with the given specifications, pointing to the commit req.Sha. The
+	// tag is an annotated tag if req.Message is set, and a lightweight tag otherwise.
+	//
+	// ErrAlreadyExists is returned if the tag already exists.
+	Create(ctx context.Context, req TagInfo) (Tag, error)
+
+	// Delete deletes the tag with the given name. The release of the tag, if any, may be
+	// deleted along with it, depending on the provider.
+	//
+	// ErrDestructiveCallDisallowed is returned if the client wasn't set up with destructive
+	// API calls enabled. ErrNotFound is returned if the resource does not exist.
+	Delete(ctx context.Context, name string) error
+}
+
+// ReleaseClient operates on the releases for a specific repository.
+// This client can be accessed through Repository.Releases().
+type ReleaseClient interface {
+	// Get returns the release of the tag with the given name, including drafts.
+	//
+	// ErrNotFound is returned if the resource does not exist.
+	Get(ctx context.Context, tagName string) (Release, error)
+
+	// List lists all releases of the repository, including drafts.
+	//
+	// List returns all available releases, using multiple paginated requests if needed.
+	List(ctx context.Context) ([]Release, error)
+
+	// Create creates a release with the given specifications. The tag req.TagName is created
+	// from req.TargetCommitish if it doesn't exist.
+	//
+	// ErrAlreadyExists is returned if the tag already has a release.
+	// ErrNoProviderSupport is returned if req is a draft or a prerelease, and the provider
+	// doesn't support them.
+	Create(ctx context.Context, req ReleaseInfo) (Release, error)
+
+	// Update changes the name, body, and draft and prerelease state of the release of the tag
+	// req.TagName to the ones of req. The tag of a release can't be changed.
+	//
+	// ErrNotFound is returned if the resource does not exist.
+	// ErrNoProviderSupport is returned if req is a draft or a prerelease, and the provider
+	// doesn't support them.
+	Update(ctx context.Context, req ReleaseInfo) (Release, error)
+
+	// Delete deletes the release of the tag with the given name, including its assets. The tag
+	// itself is kept.
+	//
+	// ErrNotFound is returned if the resource does not exist.
+	Delete(ctx context.Context, tagName string) error
+
+	// Reconcile makes sure the given desired state (req) becomes the actual state in the backing Git provider.
+	// The release is looked up by req.TagName.
+	//
+	// If req doesn't exist under the hood, it is created (actionTaken == true).
+	// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+	// If req is already the actual state, this is a no-op (actionTaken == false).
+	Reconcile(ctx context.Context, req ReleaseInfo) (resp Release, actionTaken bool, err error)
+}
+
+// ReleaseAssetClient operates on the files attached to a specific release.
+// This client can be accessed through Release.Assets().
+type ReleaseAssetClient interface {
+	// List lists all assets of the release.
+	//
+	// List returns all available assets, using multiple paginated requests if needed.
+	List(ctx context.Context) ([]ReleaseAsset, error)
+
+	// Upload attaches a file with the given name and content to the release.
+	//
+	// ErrAlreadyExists is returned if the release already has an asset with the given name.
+	Upload(ctx context.Context, name string, content []byte) (ReleaseAsset, error)
+
+	// Download returns the content of the asset with the given name.
+	//
+	// ErrNotFound is returned if the resource does not exist.
+	Download(ctx context.Context, name string) ([]byte, error)
+
+	// Delete removes the asset with the given name from the release.
+	//
+	// ErrNotFound is returned if the resource does not exist.
+	Delete(ctx context.Context, name string) error
 }
 
 // PullRequestClient operates on the pull requests for a specific repository.
 // This client can be accessed through Repository.PullRequests().
 type PullRequestClient interface {
+	// Get returns the pull request with the given number.
+	//
+	// ErrNotFound is returned if the resource does not exist.
+	Get(ctx context.Context, number int) (PullRequest, error)
+
+	// List lists the pull requests of the repository matching the given filters.
+	//
+	// List returns all available pull requests, using multiple paginated requests if needed.
+	List(ctx context.Context, opts ...PullRequestListOption) ([]PullRequest, error)
+
 	// Create creates a pull request with the given specifications.
 	Create(ctx context.Context, title, branch, baseBranch, description string) (PullRequest, error)
+
+	// Update changes the title, description, base branch and/or labels of the pull request
+	// with the given number. nil fields of opts are left unchanged.
+	//
+	// ErrNotFound is returned if the resource does not exist.
+	// ErrNoProviderSupport is returned if labels are given, but the provider doesn't support them.
+	Update(ctx context.Context, number int, opts PullRequestUpdateOptions) (PullRequest, error)
+
+	// Merge merges the pull request with the given number, using the given method. message is
+	// the message of the merge or squash commit; an empty message uses the provider default.
+	//
+	// ErrNoProviderSupport is returned if the provider doesn't support the merge method.
+	Merge(ctx context.Context, number int, method MergeMethod, message string) error
+
+	// Close closes the pull request with the given number, without merging it.
+	//
+	// ErrNotFound is returned if the resource does not exist.
+	Close(ctx context.Context, number int) (PullRequest, error)
+
+	// Reconcile makes sure the given desired state (req) becomes the actual state in the backing Git provider.
+	// The pull request is looked up by req.HeadBranch and req.BaseBranch among the open pull
+	// requests; the title, description and labels of req are reconciled.
+	//
+	// If req doesn't exist under the hood, it is created (actionTaken == true).
+	// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+	// If req is already the actual state, this is a no-op (actionTaken == false).
+	Reconcile(ctx context.Context, req PullRequestInfo) (resp PullRequest, actionTaken bool, err error)
+}
+
+// PullRequestCommentClient operates on the issue-style comments of a specific pull request.
+// This client can be accessed through PullRequest.Comments().
+type PullRequestCommentClient interface {
+	// List all comments of the pull request, oldest first.
+	//
+	// List returns all available comments, using multiple paginated requests if needed.
+	List(ctx context.Context) ([]PullRequestComment, error)
+
+	// Create adds a comment with the given body to the pull request.
+	Create(ctx context.Context, body string) (PullRequestComment, error)
+
+	// Update changes the body of the comment with the given ID.
+	//
+	// ErrNotFound is returned if the resource does not exist.
+	Update(ctx context.Context, id int64, body string) (PullRequestComment, error)
+
+	// Delete deletes the comment with the given ID.
+	//
+	// ErrNotFound is returned if the resource does not exist.
+	Delete(ctx context.Context, id int64) error
+}
+
+// IssueClient operates on the issues for a specific repository.
+// This client can be accessed through Repository.Issues().
+type IssueClient interface {
+	// Get returns the issue with the given number.
+	//
+	// ErrNotFound is returned if the resource does not exist, also if the number is the one of
+	// a pull request.
+	Get(ctx context.Context, number int) (Issue, error)
+
+	// List lists the issues of the repository matching the given filters. Pull requests are
+	// never listed.
+	//
+	// List returns all available issues, using multiple paginated requests if needed.
+	List(ctx context.Context, opts ...IssueListOption) ([]Issue, error)
+
+	// Create creates an open issue with the given specifications. req.State is ignored.
+	Create(ctx context.Context, req IssueInfo) (Issue, error)
+
+	// Update changes the title, body, labels and/or assignees of the issue with the given
+	// number. nil fields of opts are left unchanged.
+	//
+	// ErrNotFound is returned if the resource does not exist.
+	Update(ctx context.Context, number int, opts IssueUpdateOptions) (Issue, error)
+
+	// Close closes the issue with the given number.
+	//
+	// ErrNotFound is returned if the resource does not exist.
+	Close(ctx context.Context, number int) (Issue, error)
+
+	// Reopen reopens the closed issue with the given number.
+	//
+	// ErrNotFound is returned if the resource does not exist.
+	Reopen(ctx context.Context, number int) (Issue, error)
+
+	// AddLabels adds the given labels to the issue with the given number, keeping the
+	// existing ones.
+	//
+	// ErrNotFound is returned if the resource does not exist.
+	AddLabels(ctx context.Context, number int, labels []string) (Issue, error)
+
+	// RemoveLabels removes the given labels from the issue with the given number. Labels the
+	// issue doesn't have are ignored.
+	//
+	// ErrNotFound is returned if the resource does not exist.
+	RemoveLabels(ctx context.Context, number int, labels []string) (Issue, error)
+}
+
+// IssueCommentClient operates on the comments of a specific issue.
+// This client can be accessed through Issue.Comments().
+type IssueCommentClient interface {
+	// List all comments of the issue, oldest first.
+	//
+	// List returns all available comments, using multiple paginated requests if needed.
+	List(ctx context.Context) ([]IssueComment, error)
+
+	// Create adds a comment with the given body to the issue.
+	Create(ctx context.Context, body string) (IssueComment, error)
+
+	// Update changes the body of the comment with the given ID.
+	//
+	// ErrNotFound is returned if the resource does not exist.
+	Update(ctx context.Context, id int64, body string) (IssueComment, error)
+
+	// Delete deletes the comment with the given ID.
+	//
+	// ErrNotFound is returned if the resource does not exist.
+	Delete(ctx context.Context, id int64) error
+}
+
+// PullRequestReviewClient operates on the reviews of a specific pull request.
+// This client can be accessed through PullRequest.Reviews().
+type PullRequestReviewClient interface {
+	// List all submitted reviews of the pull request, oldest first.
+	//
+	// List returns all available reviews, using multiple paginated requests if needed.
+	List(ctx context.Context) ([]PullRequestReview, error)
+
+	// Submit submits a review with the given verdict and body, as the authenticated user.
+	// body may be empty when approving.
+	//
+	// ErrNoProviderSupport is returned if the provider can't express the verdict.
+	Submit(ctx context.Context, state PullRequestReviewState, body string) (PullRequestReview, error)
 }
@@ -0,0 +1,87 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conformance
+
+import (
+	"testing"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+func (s *suite) testClient(t *testing.T) {
+	if s.c.SupportedDomain() != s.cfg.Organization.Domain {
+		t.Errorf("SupportedDomain() = %q, want the domain of the organization %q", s.c.SupportedDomain(), s.cfg.Organization.Domain)
+	}
+	if len(s.c.ProviderID()) == 0 {
+		t.Error("ProviderID() must not be empty")
+	}
+	if s.c.Raw() == nil {
+		t.Error("Raw() must not be nil")
+	}
+
+	ok, err := s.c.HasTokenPermission(s.ctx, gitprovider.TokenPermissionRWRepository)
+	if s.expectUnsupported(t, FeatureTokenPermission, "HasTokenPermission", err) && !ok {
+		t.Error("HasTokenPermission() = false, the token must be able to create repositories")
+	}
+
+	// Quota may legitimately be unknown, e.g. before the first request
+	quota, err := s.c.Quota(s.ctx)
+	if err != nil {
+		expectError(t, "Quota", err, gitprovider.ErrNoProviderSupport)
+	} else if quota.Remaining < 0 || quota.Remaining > quota.Limit {
+		t.Errorf("Quota() = %+v, want 0 <= Remaining <= Limit", quota)
+	}
+}
+
+func (s *suite) testOrganizations(t *testing.T) {
+	ref := s.cfg.Organization
+	org, err := s.c.Organizations().Get(s.ctx, ref)
+	if err != nil {
+		t.Fatalf("Organizations().Get(): %v", err)
+	}
+	if org.Organization().String() != ref.String() {
+		t.Errorf("Organization() = %s, want %s", org.Organization(), ref)
+	}
+	if org.APIObject() == nil {
+		t.Error("APIObject() must not be nil")
+	}
+
+	missing := ref
+	missing.Organization = s.repoName() + "-missing"
+	missing.SubOrganizations = nil
+	_, err = s.c.Organizations().Get(s.ctx, missing)
+	expectError(t, "Organizations().Get() of a missing organization", err, gitprovider.ErrNotFound)
+
+	otherDomain := ref
+	otherDomain.Domain = "other." + ref.Domain
+	_, err = s.c.Organizations().Get(s.ctx, otherDomain)
+	expectError(t, "Organizations().Get() of another domain", err, gitprovider.ErrDomainUnsupported)
+
+	orgs, err := s.c.Organizations().List(s.ctx)
+	if s.expectUnsupported(t, FeatureOrganizationList, "Organizations().List()", err) && len(ref.SubOrganizations) == 0 {
+		found := false
+		for _, o := range orgs {
+			found = found || o.Organization().String() == ref.String()
+		}
+		if !found {
+			t.Errorf("Organizations().List() doesn't contain %s", ref)
+		}
+	}
+
+	_, err = s.c.Organizations().Children(s.ctx, ref)
+	s.expectUnsupported(t, FeatureOrganizationChildren, "Organizations().Children()", err)
+}
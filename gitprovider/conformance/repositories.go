@@ -0,0 +1,248 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conformance
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+const (
+	description        = "conformance test repository"
+	updatedDescription = "updated conformance test repository"
+)
+
+func (s *suite) testOrgRepositories(t *testing.T) {
+	ref := gitprovider.OrgRepositoryRef{OrganizationRef: s.cfg.Organization, RepositoryName: s.repoName()}
+	s.deleteOrgRepo(t, ref)
+	repos := s.c.OrgRepositories()
+
+	_, err := repos.Get(s.ctx, ref)
+	expectError(t, "OrgRepositories().Get() of a missing repository", err, gitprovider.ErrNotFound)
+
+	// Unsupported fields must be rejected before creating anything
+	if !s.cfg.supports(FeatureRepositoryDescription) {
+		_, err := repos.Create(s.ctx, ref, gitprovider.RepositoryInfo{Description: gitprovider.StringVar(description)})
+		expectError(t, "OrgRepositories().Create() with a description", err, gitprovider.ErrNoProviderSupport)
+	}
+	if !s.cfg.supports(FeatureLicenseTemplate) {
+		_, err := repos.Create(s.ctx, ref, gitprovider.RepositoryInfo{}, &gitprovider.RepositoryCreateOptions{
+			AutoInit:        gitprovider.BoolVar(true),
+			LicenseTemplate: gitprovider.LicenseTemplateVar(gitprovider.LicenseTemplateApache2),
+		})
+		expectError(t, "OrgRepositories().Create() with a license", err, gitprovider.ErrNoProviderSupport)
+	}
+
+	req := s.repositoryInfo()
+	opts := &gitprovider.RepositoryCreateOptions{AutoInit: gitprovider.BoolVar(true)}
+	if s.cfg.supports(FeatureLicenseTemplate) {
+		opts.LicenseTemplate = gitprovider.LicenseTemplateVar(gitprovider.LicenseTemplateApache2)
+	}
+	repo, actionTaken, err := repos.Reconcile(s.ctx, ref, req, opts)
+	if err != nil {
+		t.Fatalf("OrgRepositories().Reconcile() of a missing repository: %v", err)
+	}
+	if !actionTaken {
+		t.Error("OrgRepositories().Reconcile() of a missing repository: expected actionTaken")
+	}
+	s.validateRepo(t, repo, ref, req)
+
+	_, err = repos.Create(s.ctx, ref, req)
+	expectError(t, "OrgRepositories().Create() of an existing repository", err, gitprovider.ErrAlreadyExists)
+
+	var got gitprovider.OrgRepository
+	if err := s.eventually(func() (err error) {
+		got, err = repos.Get(s.ctx, ref)
+		return
+	}); err != nil {
+		t.Fatalf("OrgRepositories().Get(): %v", err)
+	}
+	s.validateRepo(t, got, ref, req)
+
+	list, err := repos.List(s.ctx, s.cfg.Organization)
+	if err != nil {
+		t.Fatalf("OrgRepositories().List(): %v", err)
+	}
+	if !containsRepo(list, ref) {
+		t.Errorf("OrgRepositories().List() doesn't contain %s", ref)
+	}
+
+	// Reconciling the actual state must be a no-op, both through the client and the resource
+	got, actionTaken, err = repos.Reconcile(s.ctx, ref, req)
+	if err != nil || actionTaken {
+		t.Errorf("OrgRepositories().Reconcile() of the actual state = %v, %v, want a no-op", actionTaken, err)
+	}
+	if err := got.Set(got.Get()); err != nil {
+		t.Fatal(err)
+	}
+	if actionTaken, err := got.Reconcile(s.ctx); err != nil || actionTaken {
+		t.Errorf("Repository.Reconcile() of the actual state = %v, %v, want a no-op", actionTaken, err)
+	}
+
+	s.testRepositoryUpdate(t, got)
+
+	// Deleting requires destructive API calls to be allowed
+	guarded, err := s.readOnly.OrgRepositories().Get(s.ctx, ref)
+	if err != nil {
+		t.Fatalf("OrgRepositories().Get(): %v", err)
+	}
+	expectError(t, "Repository.Delete() without destructive API calls", guarded.Delete(s.ctx), gitprovider.ErrDestructiveCallDisallowed)
+
+	if err := got.Delete(s.ctx); err != nil {
+		t.Fatalf("Repository.Delete(): %v", err)
+	}
+	if err := s.eventually(func() error {
+		_, err := repos.Get(s.ctx, ref)
+		if err == nil {
+			return errStillExists
+		}
+		return err
+	}); !errors.Is(err, gitprovider.ErrNotFound) {
+		t.Errorf("OrgRepositories().Get() of a deleted repository: expected ErrNotFound, got %v", err)
+	}
+}
+
+func (s *suite) testUserRepositories(t *testing.T) {
+	ref := gitprovider.UserRepositoryRef{UserRef: s.cfg.User, RepositoryName: s.repoName()}
+	repos := s.c.UserRepositories()
+
+	if !s.cfg.supports(FeatureUserRepositories) {
+		_, err := repos.Get(s.ctx, ref)
+		expectError(t, "UserRepositories().Get()", err, gitprovider.ErrNoProviderSupport)
+		_, err = repos.Create(s.ctx, ref, gitprovider.RepositoryInfo{})
+		expectError(t, "UserRepositories().Create()", err, gitprovider.ErrNoProviderSupport)
+		return
+	}
+	if len(s.cfg.User.UserLogin) == 0 {
+		t.Fatal("conformance: Config.User is required unless FeatureUserRepositories is unsupported")
+	}
+	t.Cleanup(func() {
+		if repo, err := s.c.UserRepositories().Get(s.ctx, ref); err == nil {
+			if err := repo.Delete(s.ctx); err != nil {
+				t.Errorf("cleanup of repository %s: %v", ref, err)
+			}
+		}
+	})
+
+	_, err := repos.Get(s.ctx, ref)
+	expectError(t, "UserRepositories().Get() of a missing repository", err, gitprovider.ErrNotFound)
+
+	req := s.repositoryInfo()
+	repo, err := repos.Create(s.ctx, ref, req, &gitprovider.RepositoryCreateOptions{AutoInit: gitprovider.BoolVar(true)})
+	if err != nil {
+		t.Fatalf("UserRepositories().Create(): %v", err)
+	}
+	s.validateRepo(t, repo, ref, req)
+
+	_, err = repos.Create(s.ctx, ref, req)
+	expectError(t, "UserRepositories().Create() of an existing repository", err, gitprovider.ErrAlreadyExists)
+
+	var list []gitprovider.UserRepository
+	if err := s.eventually(func() (err error) {
+		list, err = repos.List(s.ctx, s.cfg.User)
+		if err == nil && !containsRepo(list, ref) {
+			err = errNotListed
+		}
+		return
+	}); err != nil {
+		t.Errorf("UserRepositories().List(): %v", err)
+	}
+
+	got, actionTaken, err := repos.Reconcile(s.ctx, ref, req)
+	if err != nil || actionTaken {
+		t.Errorf("UserRepositories().Reconcile() of the actual state = %v, %v, want a no-op", actionTaken, err)
+	}
+	if got != nil {
+		s.testRepositoryUpdate(t, got)
+	}
+}
+
+// testRepositoryUpdate checks that changes made with Set are applied by Reconcile.
+func (s *suite) testRepositoryUpdate(t *testing.T, repo gitprovider.UserRepository) {
+	// The description is the only field all providers can update without side effects
+	if !s.cfg.supports(FeatureRepositoryDescription) {
+		return
+	}
+	req := repo.Get()
+	req.Description = gitprovider.StringVar(updatedDescription)
+	if err := repo.Set(req); err != nil {
+		t.Fatal(err)
+	}
+	actionTaken, err := repo.Reconcile(s.ctx)
+	if err != nil {
+		t.Fatalf("Repository.Reconcile() of an update: %v", err)
+	}
+	if !actionTaken {
+		t.Error("Repository.Reconcile() of an update: expected actionTaken")
+	}
+	if !req.Equals(repo.Get()) {
+		t.Errorf("Repository.Get() after an update = %v, want %v", repo.Get(), req)
+	}
+}
+
+// repositoryInfo returns the RepositoryInfo used to create repositories.
+func (s *suite) repositoryInfo() gitprovider.RepositoryInfo {
+	req := gitprovider.RepositoryInfo{}
+	if s.cfg.supports(FeatureRepositoryDescription) {
+		req.Description = gitprovider.StringVar(description)
+	}
+	return req
+}
+
+// validateRepo checks that the repository has the given reference, and the (defaulted) req is
+// its actual state.
+func (s *suite) validateRepo(t *testing.T, repo gitprovider.UserRepository, ref gitprovider.RepositoryRef, req gitprovider.RepositoryInfo) {
+	t.Helper()
+	if repo.Repository().String() != ref.String() {
+		t.Errorf("Repository() = %s, want %s", repo.Repository(), ref)
+	}
+	if repo.APIObject() == nil {
+		t.Error("APIObject() must not be nil")
+	}
+	req.Default()
+	info := repo.Get()
+	if info.Visibility == nil || *info.Visibility != *req.Visibility {
+		t.Errorf("Repository.Get().Visibility = %v, want %s", info.Visibility, *req.Visibility)
+	}
+	if info.DefaultBranch == nil {
+		t.Error("Repository.Get().DefaultBranch must be set")
+	}
+	if req.Description != nil && (info.Description == nil || *info.Description != *req.Description) {
+		t.Errorf("Repository.Get().Description = %v, want %q", info.Description, *req.Description)
+	}
+}
+
+// containsRepo returns true if one of repos has the given reference.
+func containsRepo(repos interface{}, ref gitprovider.RepositoryRef) bool {
+	switch list := repos.(type) {
+	case []gitprovider.OrgRepository:
+		for _, repo := range list {
+			if repo.Repository().String() == ref.String() {
+				return true
+			}
+		}
+	case []gitprovider.UserRepository:
+		for _, repo := range list {
+			if repo.Repository().String() == ref.String() {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,1058 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package conformance
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+func (s *suite) testDeployKeys(t *testing.T) {
+	keys := s.createOrgRepo(t).DeployKeys()
+	name := s.repoName()
+
+	_, err := keys.Get(s.ctx, name)
+	expectError(t, "DeployKeys().Get() of a missing key", err, gitprovider.ErrNotFound)
+
+	// Generated keys end with a newline, which providers don't return
+	req := gitprovider.DeployKeyInfo{Name: name, Key: bytes.TrimSpace(newKey(t))}
+	if !s.cfg.supports(FeatureReadOnlyDeployKeys) {
+		// Keys are read-only by default
+		_, err := keys.Create(s.ctx, req)
+		expectError(t, "DeployKeys().Create() of a read-only key", err, gitprovider.ErrNoProviderSupport)
+		req.ReadOnly = gitprovider.BoolVar(false)
+	}
+
+	key, actionTaken, err := keys.Reconcile(s.ctx, req)
+	if err != nil {
+		t.Fatalf("DeployKeys().Reconcile() of a missing key: %v", err)
+	}
+	if !actionTaken {
+		t.Error("DeployKeys().Reconcile() of a missing key: expected actionTaken")
+	}
+	defer func() { _ = key.Delete(s.ctx) }()
+	if info := key.Get(); info.Name != name || !bytes.Equal(info.Key, req.Key) {
+		t.Errorf("DeployKey.Get() = %v, want %v", info, req)
+	}
+
+	_, err = keys.Create(s.ctx, req)
+	expectError(t, "DeployKeys().Create() of an existing key", err, gitprovider.ErrAlreadyExists)
+
+	if _, actionTaken, err := keys.Reconcile(s.ctx, req); err != nil || actionTaken {
+		t.Errorf("DeployKeys().Reconcile() of the actual state = %v, %v, want a no-op", actionTaken, err)
+	}
+
+	list, err := keys.List(s.ctx)
+	if err != nil {
+		t.Fatalf("DeployKeys().List(): %v", err)
+	}
+	found := false
+	for _, k := range list {
+		found = found || k.Get().Name == name
+	}
+	if !found {
+		t.Errorf("DeployKeys().List() doesn't contain %q", name)
+	}
+
+	// Changing the key replaces it
+	req.Key = bytes.TrimSpace(newKey(t))
+	key, actionTaken, err = keys.Reconcile(s.ctx, req)
+	if err != nil {
+		t.Fatalf("DeployKeys().Reconcile() of an update: %v", err)
+	}
+	if !actionTaken || !bytes.Equal(key.Get().Key, req.Key) {
+		t.Errorf("DeployKeys().Reconcile() of an update = %v, %s, want the key to be replaced", actionTaken, key.Get().Key)
+	}
+
+	if err := key.Delete(s.ctx); err != nil {
+		t.Fatalf("DeployKey.Delete(): %v", err)
+	}
+	_, err = keys.Get(s.ctx, name)
+	expectError(t, "DeployKeys().Get() of a deleted key", err, gitprovider.ErrNotFound)
+}
+
+func (s *suite) testTeamAccess(t *testing.T) {
+	if s.cfg.supports(FeatureTeamAccess) && len(s.cfg.Team) == 0 {
+		t.Skip("conformance: Config.Team is unset")
+	}
+	access := s.createOrgRepo(t).TeamAccess()
+
+	_, err := access.List(s.ctx)
+	if !s.expectUnsupported(t, FeatureTeamAccess, "TeamAccess().List()", err) {
+		return
+	}
+
+	_, err = access.Get(s.ctx, s.cfg.Team)
+	expectError(t, "TeamAccess().Get() of a team without access", err, gitprovider.ErrNotFound)
+
+	req := gitprovider.TeamAccessInfo{Name: s.cfg.Team}
+	ta, actionTaken, err := access.Reconcile(s.ctx, req)
+	if err != nil {
+		t.Fatalf("TeamAccess().Reconcile() of a missing team access: %v", err)
+	}
+	if !actionTaken {
+		t.Error("TeamAccess().Reconcile() of a missing team access: expected actionTaken")
+	}
+	if info := ta.Get(); info.Name != s.cfg.Team || *info.Permission != gitprovider.RepositoryPermissionPull {
+		t.Errorf("TeamAccess.Get() = %v, want pull access for %q", info, s.cfg.Team)
+	}
+
+	_, err = access.Create(s.ctx, req)
+	expectError(t, "TeamAccess().Create() of an existing team access", err, gitprovider.ErrAlreadyExists)
+
+	if _, actionTaken, err := access.Reconcile(s.ctx, req); err != nil || actionTaken {
+		t.Errorf("TeamAccess().Reconcile() of the actual state = %v, %v, want a no-op", actionTaken, err)
+	}
+
+	req.Permission = gitprovider.RepositoryPermissionVar(gitprovider.RepositoryPermissionPush)
+	if _, actionTaken, err := access.Reconcile(s.ctx, req); err != nil || !actionTaken {
+		t.Errorf("TeamAccess().Reconcile() of an update = %v, %v, want an update", actionTaken, err)
+	}
+	if err := s.eventually(func() error {
+		ta, err = access.Get(s.ctx, s.cfg.Team)
+		if err == nil && *ta.Get().Permission != gitprovider.RepositoryPermissionPush {
+			err = fmt.Errorf("permission is %s", *ta.Get().Permission)
+		}
+		return err
+	}); err != nil {
+		t.Errorf("TeamAccess().Get() after an update: %v", err)
+	}
+
+	list, err := access.List(s.ctx)
+	if err != nil || len(list) == 0 {
+		t.Errorf("TeamAccess().List() = %v, %v, want the team access", list, err)
+	}
+
+	if err := ta.Delete(s.ctx); err != nil {
+		t.Fatalf("TeamAccess.Delete(): %v", err)
+	}
+	if err := s.eventually(func() error {
+		_, err := access.Get(s.ctx, s.cfg.Team)
+		if err == nil {
+			return errStillExists
+		}
+		return err
+	}); !errors.Is(err, gitprovider.ErrNotFound) {
+		t.Errorf("TeamAccess().Get() of a deleted team access: expected ErrNotFound, got %v", err)
+	}
+}
+
+func (s *suite) testCommitsBranchesPullRequests(t *testing.T) {
+	repo := s.createOrgRepo(t)
+	defaultBranch := *repo.Get().DefaultBranch
+
+	// Auto-initialized repositories have a commit on the default branch
+	var commits []gitprovider.Commit
+	if err := s.eventually(func() (err error) {
+		commits, err = repo.Commits().ListPage(s.ctx, defaultBranch, 1, 1)
+		if err == nil && len(commits) == 0 {
+			err = errNotListed
+		}
+		return
+	}); err != nil {
+		t.Fatalf("Commits().ListPage() of the default branch: %v", err)
+	}
+	head := commits[0].Get()
+	if len(head.Sha) == 0 {
+		t.Fatal("Commit.Get().Sha must not be empty")
+	}
+
+	branch := s.repoName()
+	if err := repo.Branches().Create(s.ctx, branch, head.Sha); err != nil {
+		t.Fatalf("Branches().Create(): %v", err)
+	}
+	if err := repo.Branches().Create(s.ctx, branch, head.Sha); err == nil {
+		t.Error("Branches().Create() of an existing branch: expected an error")
+	}
+	s.testBranches(t, repo, defaultBranch, head.Sha)
+
+	_, err := repo.Commits().Create(s.ctx, branch, "no files", nil)
+	if err == nil {
+		t.Error("Commits().Create() without files: expected an error")
+	}
+
+	commit, err := repo.Commits().Create(s.ctx, branch, "add a file", []gitprovider.CommitFile{
+		{
+			Path:    gitprovider.StringVar("conformance/file.txt"),
+			Content: gitprovider.StringVar("content"),
+		},
+	})
+	if err != nil {
+		t.Fatalf("Commits().Create(): %v", err)
+	}
+	if commit.Get().Sha == head.Sha {
+		t.Error("Commits().Create() returned the parent commit")
+	}
+	if err := s.eventually(func() (err error) {
+		commits, err = repo.Commits().ListPage(s.ctx, branch, 1, 1)
+		if err == nil && (len(commits) == 0 || commits[0].Get().Sha != commit.Get().Sha) {
+			err = errNotListed
+		}
+		return
+	}); err != nil {
+		t.Errorf("Commits().ListPage() of the branch doesn't start with the new commit: %v", err)
+	}
+
+	parent := commit.Get().Sha
+	_, err = repo.Commits().Create(s.ctx, branch, "move a file", []gitprovider.CommitFile{
+		{
+			Action:       gitprovider.CommitFileActionVar(gitprovider.CommitFileActionMove),
+			Path:         gitprovider.StringVar("conformance/moved.txt"),
+			PreviousPath: gitprovider.StringVar("conformance/file.txt"),
+		},
+		{
+			Action:  gitprovider.CommitFileActionVar(gitprovider.CommitFileActionCreate),
+			Path:    gitprovider.StringVar("conformance/other.txt"),
+			Content: gitprovider.StringVar("other"),
+		},
+	}, &gitprovider.CommitCreateOptions{ExpectedParentSHA: &parent})
+	if err != nil {
+		t.Fatalf("Commits().Create() moving a file: %v", err)
+	}
+	// The branch moved since parent was read
+	_, err = repo.Commits().Create(s.ctx, branch, "stale parent", []gitprovider.CommitFile{
+		{
+			Path:    gitprovider.StringVar("conformance/stale.txt"),
+			Content: gitprovider.StringVar("stale"),
+		},
+	}, &gitprovider.CommitCreateOptions{ExpectedParentSHA: &parent})
+	if !errors.Is(err, gitprovider.ErrConflict) {
+		t.Errorf("Commits().Create() with a stale parent: expected ErrConflict, got %v", err)
+	}
+	_, err = repo.Commits().Create(s.ctx, branch, "delete a file", []gitprovider.CommitFile{
+		{
+			Action: gitprovider.CommitFileActionVar(gitprovider.CommitFileActionDelete),
+			Path:   gitprovider.StringVar("conformance/other.txt"),
+		},
+	})
+	if err != nil {
+		t.Fatalf("Commits().Create() deleting a file: %v", err)
+	}
+	s.testFiles(t, repo, branch)
+	s.testCommitStartBranch(t, repo, defaultBranch, head.Sha)
+
+	pr, err := repo.PullRequests().Create(s.ctx, "Conformance test", branch, defaultBranch, "Adds a file")
+	if err != nil {
+		t.Fatalf("PullRequests().Create(): %v", err)
+	}
+	if len(pr.Get().WebURL) == 0 {
+		t.Error("PullRequest.Get().WebURL must not be empty")
+	}
+	info := pr.Get()
+	if info.Number == 0 || info.State != gitprovider.PullRequestStateOpen || info.HeadBranch != branch || info.BaseBranch != defaultBranch {
+		t.Errorf("PullRequests().Create() = %+v, want an open pull request from %q to %q", info, branch, defaultBranch)
+	}
+
+	got, err := repo.PullRequests().Get(s.ctx, info.Number)
+	if err != nil || got.Get().Title != "Conformance test" {
+		t.Errorf("PullRequests().Get() = %v, %v", got, err)
+	}
+	_, err = repo.PullRequests().Get(s.ctx, info.Number+1000)
+	expectError(t, "PullRequests().Get() of a missing pull request", err, gitprovider.ErrNotFound)
+
+	updated, err := repo.PullRequests().Update(s.ctx, info.Number, gitprovider.PullRequestUpdateOptions{
+		Title: gitprovider.StringVar("Conformance test (updated)"),
+	})
+	if err != nil || updated.Get().Title != "Conformance test (updated)" || updated.Get().Description != "Adds a file" {
+		t.Errorf("PullRequests().Update() = %v, %v", updated, err)
+	}
+
+	if err := s.eventually(func() error {
+		prs, err := repo.PullRequests().List(s.ctx, &gitprovider.PullRequestListOptions{
+			State:      gitprovider.PullRequestStateVar(gitprovider.PullRequestStateOpen),
+			HeadBranch: gitprovider.StringVar(branch),
+		})
+		if err == nil && (len(prs) != 1 || prs[0].Get().Number != info.Number) {
+			err = errNotListed
+		}
+		return err
+	}); err != nil {
+		t.Errorf("PullRequests().List() of the open pull requests of the branch: %v", err)
+	}
+
+	// Reconciling finds the open pull request of the branch instead of opening another one
+	req := gitprovider.PullRequestInfo{
+		Title:       "Conformance test (updated)",
+		Description: "Adds a file",
+		HeadBranch:  branch,
+		BaseBranch:  defaultBranch,
+	}
+	reconciled, actionTaken, err := repo.PullRequests().Reconcile(s.ctx, req)
+	if err != nil || actionTaken || reconciled.Get().Number != info.Number {
+		t.Errorf("PullRequests().Reconcile() of the actual state = %v, %v, %v", reconciled, actionTaken, err)
+	}
+	req.Description = "Adds a file (reconciled)"
+	reconciled, actionTaken, err = repo.PullRequests().Reconcile(s.ctx, req)
+	if err != nil || !actionTaken || reconciled.Get().Number != info.Number || reconciled.Get().Description != req.Description {
+		t.Errorf("PullRequests().Reconcile() of a new description = %v, %v, %v", reconciled, actionTaken, err)
+	}
+
+	s.testPullRequestComments(t, reconciled)
+
+	// Servers check whether pull requests can be merged in the background
+	if err := s.eventually(func() error {
+		return repo.PullRequests().Merge(s.ctx, info.Number, gitprovider.MergeMethodMerge, "")
+	}); err != nil {
+		t.Fatalf("PullRequests().Merge(): %v", err)
+	}
+	if err := s.eventually(func() error {
+		merged, err := repo.PullRequests().Get(s.ctx, info.Number)
+		if err == nil && merged.Get().State != gitprovider.PullRequestStateMerged {
+			err = fmt.Errorf("pull request is %s", merged.Get().State)
+		}
+		return err
+	}); err != nil {
+		t.Errorf("PullRequests().Get() of a merged pull request: %v", err)
+	}
+}
+
+// testFiles tests reading the files committed to branch, where conformance/file.txt was moved
+// to conformance/moved.txt.
+// testCommitStartBranch creates a commit on a new branch, starting at the head of the default
+// branch, which is at headSHA.
+func (s *suite) testCommitStartBranch(t *testing.T, repo gitprovider.OrgRepository, defaultBranch, headSHA string) {
+	files := []gitprovider.CommitFile{
+		{
+			Path:    gitprovider.StringVar("conformance/started.txt"),
+			Content: gitprovider.StringVar("started"),
+		},
+	}
+	branch := s.repoName()
+	if _, err := repo.Commits().Create(s.ctx, branch, "missing branch", files); !errors.Is(err, gitprovider.ErrNotFound) {
+		t.Errorf("Commits().Create() on a missing branch: expected ErrNotFound, got %v", err)
+	}
+	commit, err := repo.Commits().Create(s.ctx, branch, "start a branch", files, &gitprovider.CommitCreateOptions{
+		StartBranch:       &defaultBranch,
+		ExpectedParentSHA: &headSHA,
+	})
+	if err != nil {
+		t.Fatalf("Commits().Create() with a start branch: %v", err)
+	}
+	var commits []gitprovider.Commit
+	if err := s.eventually(func() (err error) {
+		commits, err = repo.Commits().ListPage(s.ctx, branch, 2, 1)
+		if err == nil && (len(commits) != 2 || commits[0].Get().Sha != commit.Get().Sha) {
+			err = errNotListed
+		}
+		return
+	}); err != nil {
+		t.Fatalf("Commits().ListPage() of the started branch: %v", err)
+	}
+	if commits[1].Get().Sha != headSHA {
+		t.Errorf("Commits().Create() with a start branch has parent %q, want %q", commits[1].Get().Sha, headSHA)
+	}
+}
+
+func (s *suite) testFiles(t *testing.T, repo gitprovider.OrgRepository, branch string) {
+	file, err := repo.Files().Get(s.ctx, "conformance/moved.txt", branch)
+	if err != nil {
+		t.Fatalf("Files().Get(): %v", err)
+	}
+	info := file.Get()
+	if string(info.Content) != "content" || info.Size != int64(len("content")) || info.Mode != gitprovider.FileModeRegular || len(info.Sha) == 0 {
+		t.Errorf("Files().Get() = %+v, want a regular file with the committed content", info)
+	}
+	_, err = repo.Files().Get(s.ctx, "conformance/file.txt", branch)
+	expectError(t, "Files().Get() of a moved file", err, gitprovider.ErrNotFound)
+
+	files, err := repo.Files().List(s.ctx, "conformance", branch)
+	if err != nil || len(files) != 1 || files[0].Get().Path != "conformance/moved.txt" || files[0].Get().Sha != info.Sha {
+		t.Errorf("Files().List() = %v, %v, want only conformance/moved.txt", files, err)
+	}
+	files, err = repo.Files().List(s.ctx, "", branch)
+	if err != nil || !containsDir(files, "conformance") {
+		t.Errorf("Files().List() of the root directory = %v, %v, want the conformance directory", files, err)
+	}
+}
+
+// containsDir returns true if files contains the directory at path.
+func containsDir(files []gitprovider.File, path string) bool {
+	for _, file := range files {
+		if file.Get().Path == path && file.Get().IsDir() {
+			return true
+		}
+	}
+	return false
+}
+
+// testBranches tests getting, listing, creating and deleting branches of repo, whose default
+// branch is at the commit sha.
+func (s *suite) testBranches(t *testing.T, repo gitprovider.OrgRepository, defaultBranch, sha string) {
+	got, err := repo.Branches().Get(s.ctx, defaultBranch)
+	if err != nil || got.Get().Name != defaultBranch || got.Get().Sha != sha {
+		t.Errorf("Branches().Get() of the default branch = %v, %v, want %s at %s", got, err, defaultBranch, sha)
+	}
+	_, err = repo.Branches().Get(s.ctx, s.repoName()+"-missing")
+	expectError(t, "Branches().Get() of a missing branch", err, gitprovider.ErrNotFound)
+
+	branch := s.repoName() + "-from"
+	created, err := repo.Branches().CreateFrom(s.ctx, branch, defaultBranch)
+	if err != nil {
+		t.Fatalf("Branches().CreateFrom(): %v", err)
+	}
+	if created.Get().Name != branch || created.Get().Sha != sha {
+		t.Errorf("Branches().CreateFrom() = %+v, want %s at %s", created.Get(), branch, sha)
+	}
+	_, err = repo.Branches().CreateFrom(s.ctx, branch, defaultBranch)
+	expectError(t, "Branches().CreateFrom() of an existing branch", err, gitprovider.ErrAlreadyExists)
+	_, err = repo.Branches().CreateFrom(s.ctx, s.repoName()+"-other", s.repoName()+"-missing")
+	expectError(t, "Branches().CreateFrom() of a missing branch", err, gitprovider.ErrNotFound)
+
+	if err := s.eventually(func() error {
+		branches, err := repo.Branches().List(s.ctx)
+		if err == nil && !containsBranch(branches, branch, sha) {
+			err = errNotListed
+		}
+		return err
+	}); err != nil {
+		t.Errorf("Branches().List(): %v", err)
+	}
+
+	// Deleting requires destructive API calls to be allowed
+	guarded, err := s.readOnly.OrgRepositories().Get(s.ctx, repo.Repository().(gitprovider.OrgRepositoryRef))
+	if err != nil {
+		t.Fatalf("OrgRepositories().Get(): %v", err)
+	}
+	err = guarded.Branches().Delete(s.ctx, branch)
+	expectError(t, "Branches().Delete() without destructive API calls", err, gitprovider.ErrDestructiveCallDisallowed)
+
+	if err := repo.Branches().Delete(s.ctx, branch); err != nil {
+		t.Fatalf("Branches().Delete(): %v", err)
+	}
+	if err := s.eventually(func() error {
+		_, err := repo.Branches().Get(s.ctx, branch)
+		if err == nil {
+			return errStillExists
+		}
+		return err
+	}); !errors.Is(err, gitprovider.ErrNotFound) {
+		t.Errorf("Branches().Get() of a deleted branch: expected ErrNotFound, got %v", err)
+	}
+}
+
+func (s *suite) testBranchProtection(t *testing.T) {
+	repo := s.createOrgRepo(t)
+	protections := repo.BranchProtections()
+
+	// Use a new branch, as some providers protect the default branch of new repositories
+	branch := s.repoName() + "-protected"
+	_, err := protections.Get(s.ctx, branch)
+	if !s.cfg.supports(FeatureBranchProtection) {
+		expectError(t, "BranchProtections().Get()", err, gitprovider.ErrNoProviderSupport)
+		return
+	}
+	expectError(t, "BranchProtections().Get() of a missing branch", err, gitprovider.ErrNotFound)
+
+	req := gitprovider.BranchProtectionInfo{Branch: branch}
+	_, _, err = protections.Reconcile(s.ctx, req)
+	expectError(t, "BranchProtections().Reconcile() of a missing branch", err, gitprovider.ErrNotFound)
+
+	if _, err := repo.Branches().CreateFrom(s.ctx, branch, *repo.Get().DefaultBranch); err != nil {
+		t.Fatalf("Branches().CreateFrom(): %v", err)
+	}
+	_, err = protections.Get(s.ctx, branch)
+	expectError(t, "BranchProtections().Get() of an unprotected branch", err, gitprovider.ErrNotFound)
+
+	bp, actionTaken, err := protections.Reconcile(s.ctx, req)
+	if err != nil {
+		t.Fatalf("BranchProtections().Reconcile() of an unprotected branch: %v", err)
+	}
+	if !actionTaken || bp.Get().Branch != branch {
+		t.Errorf("BranchProtections().Reconcile() of an unprotected branch = %v, %v, want protection", bp.Get(), actionTaken)
+	}
+	if _, actionTaken, err := protections.Reconcile(s.ctx, req); err != nil || actionTaken {
+		t.Errorf("BranchProtections().Reconcile() of the actual state = %v, %v, want a no-op", actionTaken, err)
+	}
+
+	req.RestrictPushes = gitprovider.BoolVar(true)
+	if _, actionTaken, err := protections.Reconcile(s.ctx, req); err != nil || !actionTaken {
+		t.Errorf("BranchProtections().Reconcile() of an update = %v, %v, want an update", actionTaken, err)
+	}
+	if err := s.eventually(func() error {
+		bp, err = protections.Get(s.ctx, branch)
+		if err == nil && !*bp.Get().RestrictPushes {
+			err = errors.New("pushes aren't restricted")
+		}
+		return err
+	}); err != nil {
+		t.Errorf("BranchProtections().Get() after an update: %v", err)
+	}
+
+	if err := protections.Delete(s.ctx, branch); err != nil {
+		t.Fatalf("BranchProtections().Delete(): %v", err)
+	}
+	if err := s.eventually(func() error {
+		_, err := protections.Get(s.ctx, branch)
+		if err == nil {
+			return errStillExists
+		}
+		return err
+	}); !errors.Is(err, gitprovider.ErrNotFound) {
+		t.Errorf("BranchProtections().Get() of an unprotected branch: expected ErrNotFound, got %v", err)
+	}
+}
+
+// testPullRequestComments tests the comments of the open pull request pr, and commenting reviews.
+func (s *suite) testPullRequestComments(t *testing.T, pr gitprovider.PullRequest) {
+	comment, err := pr.Comments().Create(s.ctx, "Conformance test comment")
+	if err != nil {
+		t.Fatalf("Comments().Create(): %v", err)
+	}
+	id := comment.Get().ID
+	if id == 0 || comment.Get().Body != "Conformance test comment" {
+		t.Errorf("Comments().Create() = %+v", comment.Get())
+	}
+
+	updated, err := pr.Comments().Update(s.ctx, id, "Conformance test comment (updated)")
+	if err != nil || updated.Get().ID != id || updated.Get().Body != "Conformance test comment (updated)" {
+		t.Errorf("Comments().Update() = %v, %v", updated, err)
+	}
+	if err := s.eventually(func() error {
+		comments, err := pr.Comments().List(s.ctx)
+		if err == nil && !containsComment(comments, id, "Conformance test comment (updated)") {
+			err = fmt.Errorf("comment %d not found in %d comments", id, len(comments))
+		}
+		return err
+	}); err != nil {
+		t.Errorf("Comments().List(): %v", err)
+	}
+
+	if err := pr.Comments().Delete(s.ctx, id); err != nil {
+		t.Errorf("Comments().Delete(): %v", err)
+	}
+	err = pr.Comments().Delete(s.ctx, id)
+	expectError(t, "Comments().Delete() of a deleted comment", err, gitprovider.ErrNotFound)
+
+	// The author of a pull request can't approve it, but every provider supports commenting
+	review, err := pr.Reviews().Submit(s.ctx, gitprovider.PullRequestReviewStateCommented, "Conformance test review")
+	if err != nil || review.Get().State != gitprovider.PullRequestReviewStateCommented {
+		t.Errorf("Reviews().Submit() of a comment = %v, %v", review, err)
+	}
+	if _, err := pr.Reviews().List(s.ctx); err != nil {
+		t.Errorf("Reviews().List(): %v", err)
+	}
+}
+
+// containsBranch returns true if branches contain the branch with the given name and head.
+func containsBranch(branches []gitprovider.Branch, name, sha string) bool {
+	for _, branch := range branches {
+		if branch.Get().Name == name && branch.Get().Sha == sha {
+			return true
+		}
+	}
+	return false
+}
+
+// containsComment returns true if comments contain the comment with the given ID and body.
+func containsComment(comments []gitprovider.PullRequestComment, id int64, body string) bool {
+	for _, comment := range comments {
+		if comment.Get().ID == id && comment.Get().Body == body {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *suite) testTagsReleases(t *testing.T) {
+	repo := s.createOrgRepo(t)
+	defaultBranch := *repo.Get().DefaultBranch
+
+	_, err := repo.Tags().Get(s.ctx, "v0.0.0")
+	if !s.cfg.supports(FeatureTags) {
+		expectError(t, "Tags().Get()", err, gitprovider.ErrNoProviderSupport)
+	} else {
+		expectError(t, "Tags().Get() of a missing tag", err, gitprovider.ErrNotFound)
+	}
+	_, err = repo.Releases().Get(s.ctx, "v0.0.0")
+	if !s.cfg.supports(FeatureReleases) {
+		expectError(t, "Releases().Get()", err, gitprovider.ErrNoProviderSupport)
+	} else {
+		expectError(t, "Releases().Get() of a missing release", err, gitprovider.ErrNotFound)
+	}
+	if !s.cfg.supports(FeatureTags) {
+		return
+	}
+
+	branch, err := repo.Branches().Get(s.ctx, defaultBranch)
+	if err != nil {
+		t.Fatalf("Branches().Get() of the default branch: %v", err)
+	}
+	sha := branch.Get().Sha
+
+	for _, req := range []gitprovider.TagInfo{
+		{Name: "v0.1.0", Sha: sha},
+		{Name: "v0.2.0", Sha: sha, Message: "Conformance test tag"},
+	} {
+		tag, err := repo.Tags().Create(s.ctx, req)
+		if err != nil {
+			t.Fatalf("Tags().Create() of %s: %v", req.Name, err)
+		}
+		if tag.Get() != req {
+			t.Errorf("Tags().Create() = %+v, want %+v", tag.Get(), req)
+		}
+		if err := s.eventually(func() (err error) {
+			tag, err = repo.Tags().Get(s.ctx, req.Name)
+			return
+		}); err != nil || tag.Get() != req {
+			t.Errorf("Tags().Get() of %s = %v, %v, want %+v", req.Name, tag, err, req)
+		}
+	}
+	_, err = repo.Tags().Create(s.ctx, gitprovider.TagInfo{Name: "v0.1.0", Sha: sha})
+	expectError(t, "Tags().Create() of an existing tag", err, gitprovider.ErrAlreadyExists)
+	if err := s.eventually(func() error {
+		tags, err := repo.Tags().List(s.ctx)
+		if err == nil && (!containsTag(tags, "v0.1.0", sha) || !containsTag(tags, "v0.2.0", sha)) {
+			err = errNotListed
+		}
+		return err
+	}); err != nil {
+		t.Errorf("Tags().List(): %v", err)
+	}
+
+	// Deleting requires destructive API calls to be allowed
+	guarded, err := s.readOnly.OrgRepositories().Get(s.ctx, repo.Repository().(gitprovider.OrgRepositoryRef))
+	if err != nil {
+		t.Fatalf("OrgRepositories().Get(): %v", err)
+	}
+	err = guarded.Tags().Delete(s.ctx, "v0.1.0")
+	expectError(t, "Tags().Delete() without destructive API calls", err, gitprovider.ErrDestructiveCallDisallowed)
+
+	if err := repo.Tags().Delete(s.ctx, "v0.1.0"); err != nil {
+		t.Errorf("Tags().Delete(): %v", err)
+	}
+	if err := s.eventually(func() error {
+		_, err := repo.Tags().Get(s.ctx, "v0.1.0")
+		if err == nil {
+			return errStillExists
+		}
+		return err
+	}); !errors.Is(err, gitprovider.ErrNotFound) {
+		t.Errorf("Tags().Get() of a deleted tag: expected ErrNotFound, got %v", err)
+	}
+
+	if s.cfg.supports(FeatureReleases) {
+		s.testReleases(t, repo, sha)
+	}
+}
+
+// testReleases tests the releases and release assets of repo, whose default branch head is sha.
+func (s *suite) testReleases(t *testing.T, repo gitprovider.OrgRepository, sha string) {
+	// The tag of the release is created from the target
+	req := gitprovider.ReleaseInfo{TagName: "v1.0.0", TargetCommitish: sha, Body: "Conformance test release"}
+	release, actionTaken, err := repo.Releases().Reconcile(s.ctx, req)
+	if err != nil {
+		t.Fatalf("Releases().Reconcile() of a new release: %v", err)
+	}
+	if !actionTaken || release.Get().TagName != req.TagName || release.Get().Name != req.TagName || release.Get().Body != req.Body {
+		t.Errorf("Releases().Reconcile() of a new release = %+v, %v", release.Get(), actionTaken)
+	}
+	if _, actionTaken, err := repo.Releases().Reconcile(s.ctx, req); err != nil || actionTaken {
+		t.Errorf("Releases().Reconcile() of the actual state = %v, %v, want a no-op", actionTaken, err)
+	}
+	_, err = repo.Releases().Create(s.ctx, req)
+	expectError(t, "Releases().Create() of an existing release", err, gitprovider.ErrAlreadyExists)
+	if err := s.eventually(func() error {
+		tag, err := repo.Tags().Get(s.ctx, req.TagName)
+		if err == nil && tag.Get().Sha != sha {
+			err = fmt.Errorf("tag points to %s, want %s", tag.Get().Sha, sha)
+		}
+		return err
+	}); err != nil {
+		t.Errorf("Tags().Get() of the release tag: %v", err)
+	}
+
+	req.Name = "Conformance test release"
+	if _, actionTaken, err := repo.Releases().Reconcile(s.ctx, req); err != nil || !actionTaken {
+		t.Errorf("Releases().Reconcile() of an update = %v, %v, want an update", actionTaken, err)
+	}
+	req.Prerelease = true
+	_, err = repo.Releases().Update(s.ctx, req)
+	if s.expectUnsupported(t, FeatureDraftReleases, "Releases().Update() to a prerelease", err) {
+		if err := s.eventually(func() error {
+			release, err := repo.Releases().Get(s.ctx, req.TagName)
+			if err == nil && !release.Get().Prerelease {
+				err = errors.New("release isn't a prerelease")
+			}
+			return err
+		}); err != nil {
+			t.Errorf("Releases().Get() after an update: %v", err)
+		}
+	}
+	if err := s.eventually(func() error {
+		releases, err := repo.Releases().List(s.ctx)
+		if err == nil && !containsRelease(releases, req.TagName, req.Name) {
+			err = errNotListed
+		}
+		return err
+	}); err != nil {
+		t.Errorf("Releases().List(): %v", err)
+	}
+
+	release, err = repo.Releases().Get(s.ctx, req.TagName)
+	if err != nil {
+		t.Fatalf("Releases().Get(): %v", err)
+	}
+	s.testReleaseAssets(t, release)
+
+	if err := repo.Releases().Delete(s.ctx, req.TagName); err != nil {
+		t.Fatalf("Releases().Delete(): %v", err)
+	}
+	if err := s.eventually(func() error {
+		_, err := repo.Releases().Get(s.ctx, req.TagName)
+		if err == nil {
+			return errStillExists
+		}
+		return err
+	}); !errors.Is(err, gitprovider.ErrNotFound) {
+		t.Errorf("Releases().Get() of a deleted release: expected ErrNotFound, got %v", err)
+	}
+	// The tag is kept
+	if _, err := repo.Tags().Get(s.ctx, req.TagName); err != nil {
+		t.Errorf("Tags().Get() of the tag of a deleted release: %v", err)
+	}
+}
+
+// testReleaseAssets tests uploading, downloading and deleting the assets of release.
+func (s *suite) testReleaseAssets(t *testing.T, release gitprovider.Release) {
+	content := []byte("conformance test asset")
+	asset, err := release.Assets().Upload(s.ctx, "asset.txt", content)
+	if err != nil {
+		t.Fatalf("Assets().Upload(): %v", err)
+	}
+	if asset.Get().Name != "asset.txt" {
+		t.Errorf("Assets().Upload() = %+v", asset.Get())
+	}
+	_, err = release.Assets().Upload(s.ctx, "asset.txt", content)
+	expectError(t, "Assets().Upload() of an existing asset", err, gitprovider.ErrAlreadyExists)
+
+	if err := s.eventually(func() error {
+		assets, err := release.Assets().List(s.ctx)
+		if err == nil && !containsAsset(assets, "asset.txt") {
+			err = errNotListed
+		}
+		return err
+	}); err != nil {
+		t.Errorf("Assets().List(): %v", err)
+	}
+	downloaded, err := release.Assets().Download(s.ctx, "asset.txt")
+	if err != nil || !bytes.Equal(downloaded, content) {
+		t.Errorf("Assets().Download() = %q, %v, want %q", downloaded, err, content)
+	}
+	_, err = release.Assets().Download(s.ctx, "missing.txt")
+	expectError(t, "Assets().Download() of a missing asset", err, gitprovider.ErrNotFound)
+
+	if err := release.Assets().Delete(s.ctx, "asset.txt"); err != nil {
+		t.Fatalf("Assets().Delete(): %v", err)
+	}
+	err = release.Assets().Delete(s.ctx, "asset.txt")
+	expectError(t, "Assets().Delete() of a deleted asset", err, gitprovider.ErrNotFound)
+}
+
+func (s *suite) testWebhooks(t *testing.T) {
+	hooks := s.createOrgRepo(t).Webhooks()
+	_, err := hooks.List(s.ctx)
+	if s.expectUnsupported(t, FeatureWebhooks, "Webhooks().List()", err) {
+		s.testWebhookClient(t, hooks, "Webhooks()")
+	}
+
+	org, err := s.c.Organizations().Get(s.ctx, s.cfg.Organization)
+	if err != nil {
+		t.Fatalf("Organizations().Get(): %v", err)
+	}
+	hooks = org.Webhooks()
+	_, err = hooks.List(s.ctx)
+	if s.expectUnsupported(t, FeatureOrganizationWebhooks, "Organization.Webhooks().List()", err) {
+		s.testWebhookClient(t, hooks, "Organization.Webhooks()")
+	}
+}
+
+// testWebhookClient tests creating, updating and deleting a webhook with hooks, described by desc in errors.
+func (s *suite) testWebhookClient(t *testing.T, hooks gitprovider.WebhookClient, desc string) {
+	url := "https://example.com/hooks/" + s.repoName()
+	_, err := hooks.Get(s.ctx, url)
+	expectError(t, desc+".Get() of a missing webhook", err, gitprovider.ErrNotFound)
+
+	req := gitprovider.WebhookInfo{URL: url, Secret: "conformance"}
+	hook, actionTaken, err := hooks.Reconcile(s.ctx, req)
+	if err != nil {
+		t.Fatalf("%s.Reconcile() of a missing webhook: %v", desc, err)
+	}
+	if !actionTaken {
+		t.Errorf("%s.Reconcile() of a missing webhook: expected actionTaken", desc)
+	}
+	defer func() { _ = hook.Delete(s.ctx) }()
+	// The secret is write-only, and the defaults are a push hook with JSON payloads
+	info := hook.Get()
+	if info.URL != url || len(info.Secret) != 0 || len(info.Events) != 1 || info.Events[0] != gitprovider.WebhookEventPush ||
+		*info.ContentType != gitprovider.WebhookContentTypeJSON || !*info.SSLVerification || !*info.Active {
+		t.Errorf("Webhook.Get() = %+v, want a push webhook for %q", info, url)
+	}
+
+	_, err = hooks.Create(s.ctx, req)
+	expectError(t, desc+".Create() of an existing webhook", err, gitprovider.ErrAlreadyExists)
+
+	if _, actionTaken, err := hooks.Reconcile(s.ctx, req); err != nil || actionTaken {
+		t.Errorf("%s.Reconcile() of the actual state = %v, %v, want a no-op", desc, actionTaken, err)
+	}
+
+	req.Events = []gitprovider.WebhookEvent{gitprovider.WebhookEventPush, gitprovider.WebhookEventTag, gitprovider.WebhookEventPullRequest}
+	req.SSLVerification = gitprovider.BoolVar(false)
+	if _, actionTaken, err := hooks.Reconcile(s.ctx, req); err != nil || !actionTaken {
+		t.Errorf("%s.Reconcile() of an update = %v, %v, want an update", desc, actionTaken, err)
+	}
+	if err := s.eventually(func() error {
+		list, err := hooks.List(s.ctx)
+		if err == nil && !containsWebhook(list, req) {
+			err = errNotListed
+		}
+		return err
+	}); err != nil {
+		t.Errorf("%s.List() after an update: %v", desc, err)
+	}
+
+	hook, err = hooks.Get(s.ctx, url)
+	if err != nil {
+		t.Fatalf("%s.Get(): %v", desc, err)
+	}
+	if err := hook.Delete(s.ctx); err != nil {
+		t.Fatalf("Webhook.Delete(): %v", err)
+	}
+	if err := s.eventually(func() error {
+		_, err := hooks.Get(s.ctx, url)
+		if err == nil {
+			return errStillExists
+		}
+		return err
+	}); !errors.Is(err, gitprovider.ErrNotFound) {
+		t.Errorf("%s.Get() of a deleted webhook: expected ErrNotFound, got %v", desc, err)
+	}
+}
+
+// containsTag returns true if tags contain the tag with the given name and sha.
+func containsTag(tags []gitprovider.Tag, name, sha string) bool {
+	for _, tag := range tags {
+		if tag.Get().Name == name && tag.Get().Sha == sha {
+			return true
+		}
+	}
+	return false
+}
+
+// containsRelease returns true if releases contain the release of the given tag, with the given name.
+func containsRelease(releases []gitprovider.Release, tagName, name string) bool {
+	for _, release := range releases {
+		if release.Get().TagName == tagName && release.Get().Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// containsAsset returns true if assets contain the asset with the given name.
+func containsAsset(assets []gitprovider.ReleaseAsset, name string) bool {
+	for _, asset := range assets {
+		if asset.Get().Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// containsWebhook returns true if hooks contain a webhook equal to the defaulted req.
+func containsWebhook(hooks []gitprovider.Webhook, req gitprovider.WebhookInfo) bool {
+	req.Default()
+	for _, hook := range hooks {
+		if hook.Get().Equals(req) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *suite) testCommitStatuses(t *testing.T) {
+	repo := s.createOrgRepo(t)
+	branch, err := repo.Branches().Get(s.ctx, *repo.Get().DefaultBranch)
+	if err != nil {
+		t.Fatalf("Branches().Get() of the default branch: %v", err)
+	}
+	sha := branch.Get().Sha
+
+	_, err = repo.CommitStatuses().List(s.ctx, sha)
+	if !s.expectUnsupported(t, FeatureCommitStatuses, "CommitStatuses().List()", err) {
+		return
+	}
+
+	// The state of a context is changed at most once, as some providers reject repeated states
+	for _, req := range []gitprovider.CommitStatusInfo{
+		{State: gitprovider.CommitStatusStatePending, Context: "conformance/build"},
+		{State: gitprovider.CommitStatusStateSuccess, Context: "conformance/build", Description: "Build passed"},
+		{State: gitprovider.CommitStatusStateFailure, Context: "conformance/lint", TargetURL: "https://example.com/lint"},
+	} {
+		status, err := repo.CommitStatuses().Create(s.ctx, sha, req)
+		if err != nil {
+			t.Fatalf("CommitStatuses().Create() of %s: %v", req.State, err)
+		}
+		if got := status.Get(); got.State != req.State || got.Context != req.Context || got.Description != req.Description || got.TargetURL != req.TargetURL {
+			t.Errorf("CommitStatuses().Create() = %+v, want %+v", got, req)
+		}
+	}
+	_, err = repo.CommitStatuses().Create(s.ctx, strings.Repeat("0", len(sha)), gitprovider.CommitStatusInfo{State: gitprovider.CommitStatusStateSuccess})
+	expectError(t, "CommitStatuses().Create() for a missing commit", err, gitprovider.ErrNotFound)
+
+	// The combined status only contains the latest status of each context
+	var combined gitprovider.CombinedCommitStatusInfo
+	if err := s.eventually(func() error {
+		status, err := repo.CommitStatuses().Get(s.ctx, sha)
+		if err != nil {
+			return err
+		}
+		if combined = status.Get(); len(combined.Statuses) != 2 {
+			return errNotListed
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("CommitStatuses().Get(): %v", err)
+	}
+	if combined.Sha != sha || combined.State != gitprovider.CommitStatusStateFailure {
+		t.Errorf("CommitStatuses().Get() = %+v, want a failed combined status of %s", combined, sha)
+	}
+	for _, status := range combined.Statuses {
+		if status.Context == "conformance/build" && status.State != gitprovider.CommitStatusStateSuccess {
+			t.Errorf("CommitStatuses().Get() status of conformance/build = %+v, want the latest status", status)
+		}
+	}
+
+	if err := s.eventually(func() error {
+		statuses, err := repo.CommitStatuses().List(s.ctx, sha)
+		if err == nil && len(statuses) != 3 {
+			err = errNotListed
+		}
+		return err
+	}); err != nil {
+		t.Errorf("CommitStatuses().List(): %v", err)
+	}
+}
+
+func (s *suite) testIssues(t *testing.T) {
+	repo := s.createOrgRepo(t)
+
+	_, err := repo.Issues().List(s.ctx)
+	if !s.expectUnsupported(t, FeatureIssues, "Issues().List()", err) {
+		return
+	}
+
+	// Assignees are left out, as they depend on the users of the provider
+	issue, err := repo.Issues().Create(s.ctx, gitprovider.IssueInfo{
+		Title:  "Conformance issue",
+		Body:   "Created by the conformance tests.",
+		Labels: []string{"bug"},
+	})
+	if err != nil {
+		t.Fatalf("Issues().Create(): %v", err)
+	}
+	info := issue.Get()
+	if info.Number == 0 || info.State != gitprovider.IssueStateOpen || info.Title != "Conformance issue" || info.Body != "Created by the conformance tests." {
+		t.Errorf("Issues().Create() = %+v, want an open issue with the requested title and body", info)
+	}
+	number := info.Number
+	if _, err := repo.Issues().Create(s.ctx, gitprovider.IssueInfo{Title: "Other conformance issue"}); err != nil {
+		t.Fatalf("Issues().Create(): %v", err)
+	}
+
+	issue, err = repo.Issues().Update(s.ctx, number, gitprovider.IssueUpdateOptions{Title: gitprovider.StringVar("Updated conformance issue")})
+	if err != nil {
+		t.Fatalf("Issues().Update(): %v", err)
+	}
+	if got := issue.Get(); got.Title != "Updated conformance issue" || got.Body != "Created by the conformance tests." {
+		t.Errorf("Issues().Update() = %+v, want only the title changed", got)
+	}
+
+	issue, err = repo.Issues().AddLabels(s.ctx, number, []string{"documentation"})
+	if err != nil {
+		t.Fatalf("Issues().AddLabels(): %v", err)
+	}
+	// The order of labels differs between providers
+	got := append([]string(nil), issue.Get().Labels...)
+	sort.Strings(got)
+	if len(got) != 2 || got[0] != "bug" || got[1] != "documentation" {
+		t.Errorf("Issues().AddLabels() labels = %v, want [bug documentation]", got)
+	}
+	issue, err = repo.Issues().RemoveLabels(s.ctx, number, []string{"bug"})
+	if err != nil {
+		t.Fatalf("Issues().RemoveLabels(): %v", err)
+	}
+	if got := issue.Get().Labels; len(got) != 1 || got[0] != "documentation" {
+		t.Errorf("Issues().RemoveLabels() labels = %v, want [documentation]", got)
+	}
+
+	issue, err = repo.Issues().Close(s.ctx, number)
+	if err != nil {
+		t.Fatalf("Issues().Close(): %v", err)
+	}
+	if got := issue.Get(); got.State != gitprovider.IssueStateClosed || got.ClosedAt == nil {
+		t.Errorf("Issues().Close() = %+v, want a closed issue", got)
+	}
+	if err := s.eventually(func() error {
+		issues, err := repo.Issues().List(s.ctx, &gitprovider.IssueListOptions{State: gitprovider.IssueStateVar(gitprovider.IssueStateClosed)})
+		if err == nil && (len(issues) != 1 || issues[0].Get().Number != number) {
+			err = errNotListed
+		}
+		return err
+	}); err != nil {
+		t.Errorf("Issues().List() of closed issues: %v", err)
+	}
+	issue, err = repo.Issues().Reopen(s.ctx, number)
+	if err != nil {
+		t.Fatalf("Issues().Reopen(): %v", err)
+	}
+	if got := issue.Get(); got.State != gitprovider.IssueStateOpen {
+		t.Errorf("Issues().Reopen() = %+v, want an open issue", got)
+	}
+	if err := s.eventually(func() error {
+		issues, err := repo.Issues().List(s.ctx, &gitprovider.IssueListOptions{Labels: []string{"documentation"}})
+		if err == nil && (len(issues) != 1 || issues[0].Get().Number != number) {
+			err = errNotListed
+		}
+		return err
+	}); err != nil {
+		t.Errorf("Issues().List() by label: %v", err)
+	}
+
+	comment, err := issue.Comments().Create(s.ctx, "Conformance comment")
+	if err != nil {
+		t.Fatalf("Comments().Create(): %v", err)
+	}
+	if _, err := issue.Comments().Update(s.ctx, comment.Get().ID, "Updated conformance comment"); err != nil {
+		t.Errorf("Comments().Update(): %v", err)
+	}
+	comments, err := issue.Comments().List(s.ctx)
+	if err != nil {
+		t.Fatalf("Comments().List(): %v", err)
+	}
+	if len(comments) != 1 || comments[0].Get().Body != "Updated conformance comment" {
+		t.Errorf("Comments().List() = %v, want the updated comment", comments)
+	}
+	if err := issue.Comments().Delete(s.ctx, comment.Get().ID); err != nil {
+		t.Errorf("Comments().Delete(): %v", err)
+	}
+}
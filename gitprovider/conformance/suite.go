@@ -0,0 +1,274 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package conformance contains a test suite checking that a gitprovider.Client implementation
+// behaves like the gitprovider interfaces specify, e.g. in terms of returned errors and Reconcile
+// semantics. Every provider package can run it against its own client:
+//
+//	func TestConformance(t *testing.T) {
+//		conformance.Run(t, conformance.Config{
+//			NewClient: func(destructive bool) (gitprovider.Client, error) {
+//				return NewClient(WithOAuth2Token(token), WithDestructiveAPICalls(destructive))
+//			},
+//			Organization: gitprovider.OrganizationRef{Domain: "github.com", Organization: "my-org"},
+//		})
+//	}
+//
+// The suite creates (and deletes) repositories with randomized names in the given organization and
+// for the given user, hence it should be pointed to dedicated test accounts.
+package conformance
+
+import (
+	"context"
+	"crypto/elliptic"
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/gitprovider/testutils"
+)
+
+const (
+	// defaultRepositoryPrefix is the prefix of the names of created repositories, if unset.
+	defaultRepositoryPrefix = "conformance-"
+	// defaultInterval is the interval between retries for eventually consistent providers.
+	defaultInterval = time.Second
+)
+
+var (
+	// errStillExists is returned while waiting for a deleted resource to disappear.
+	errStillExists = errors.New("resource still exists")
+	// errNotListed is returned while waiting for a created resource to be listed.
+	errNotListed = errors.New("resource not listed")
+)
+
+// Feature is an enum of optional features of the gitprovider interfaces, which some providers
+// can't support. The suite expects gitprovider.ErrNoProviderSupport from unsupported features.
+type Feature string
+
+const (
+	// FeatureOrganizationList is support for OrganizationsClient.List.
+	FeatureOrganizationList = Feature("OrganizationList")
+	// FeatureOrganizationChildren is support for OrganizationsClient.Children.
+	FeatureOrganizationChildren = Feature("OrganizationChildren")
+	// FeatureUserRepositories is support for the UserRepositoriesClient.
+	FeatureUserRepositories = Feature("UserRepositories")
+	// FeatureRepositoryDescription is support for RepositoryInfo.Description.
+	FeatureRepositoryDescription = Feature("RepositoryDescription")
+	// FeatureLicenseTemplate is support for RepositoryCreateOptions.LicenseTemplate.
+	FeatureLicenseTemplate = Feature("LicenseTemplate")
+	// FeatureReadOnlyDeployKeys is support for read-only deploy keys.
+	FeatureReadOnlyDeployKeys = Feature("ReadOnlyDeployKeys")
+	// FeatureTeamAccess is support for the TeamAccessClient.
+	FeatureTeamAccess = Feature("TeamAccess")
+	// FeatureTokenPermission is support for Client.HasTokenPermission.
+	FeatureTokenPermission = Feature("TokenPermission")
+	// FeatureBranchProtection is support for the BranchProtectionClient.
+	FeatureBranchProtection = Feature("BranchProtection")
+	// FeatureTags is support for the TagClient.
+	FeatureTags = Feature("Tags")
+	// FeatureReleases is support for the ReleaseClient and ReleaseAssetClient.
+	FeatureReleases = Feature("Releases")
+	// FeatureDraftReleases is support for draft releases and prereleases.
+	FeatureDraftReleases = Feature("DraftReleases")
+	// FeatureWebhooks is support for the WebhookClient of repositories.
+	FeatureWebhooks = Feature("Webhooks")
+	// FeatureOrganizationWebhooks is support for the WebhookClient of organizations.
+	FeatureOrganizationWebhooks = Feature("OrganizationWebhooks")
+	// FeatureCommitStatuses is support for the CommitStatusClient.
+	FeatureCommitStatuses = Feature("CommitStatuses")
+	// FeatureIssues is support for the IssueClient and IssueCommentClient.
+	FeatureIssues = Feature("Issues")
+)
+
+// Config specifies the client and fixtures the suite runs against.
+type Config struct {
+	// NewClient returns a new client for the provider under test, which allows destructive
+	// API calls if destructive is true.
+	// +required
+	NewClient func(destructive bool) (gitprovider.Client, error)
+
+	// Organization is an existing organization, in which the suite may create and delete repositories.
+	// +required
+	Organization gitprovider.OrganizationRef
+
+	// User is the user owning the token, for which the suite may create and delete repositories.
+	// Required unless FeatureUserRepositories is unsupported.
+	// +optional
+	User gitprovider.UserRef
+
+	// Team is the name of an existing team in Organization, used for testing team access. The
+	// team access tests are skipped if unset.
+	// +optional
+	Team string
+
+	// Unsupported lists the features the provider doesn't support.
+	// +optional
+	Unsupported []Feature
+
+	// RepositoryPrefix is the prefix of the names of created repositories. Default: "conformance-".
+	// +optional
+	RepositoryPrefix string
+
+	// Eventually is how long to retry reads after writes, for eventually consistent providers.
+	// Default: no retries.
+	// +optional
+	Eventually time.Duration
+}
+
+// supports returns true if the feature isn't listed as unsupported.
+func (c *Config) supports(f Feature) bool {
+	for _, unsupported := range c.Unsupported {
+		if f == unsupported {
+			return false
+		}
+	}
+	return true
+}
+
+// suite holds the state shared by the tests of a run.
+type suite struct {
+	cfg Config
+	ctx context.Context
+
+	// c allows destructive API calls, while readOnly doesn't
+	c        gitprovider.Client
+	readOnly gitprovider.Client
+}
+
+// Run runs the conformance suite, as subtests of t.
+func Run(t *testing.T, cfg Config) {
+	if cfg.NewClient == nil {
+		t.Fatal("conformance: Config.NewClient is required")
+	}
+	if len(cfg.RepositoryPrefix) == 0 {
+		cfg.RepositoryPrefix = defaultRepositoryPrefix
+	}
+
+	s := &suite{cfg: cfg, ctx: context.Background()}
+	var err error
+	if s.c, err = cfg.NewClient(true); err != nil {
+		t.Fatalf("conformance: creating client: %v", err)
+	}
+	if s.readOnly, err = cfg.NewClient(false); err != nil {
+		t.Fatalf("conformance: creating client: %v", err)
+	}
+
+	t.Run("Client", s.testClient)
+	t.Run("Organizations", s.testOrganizations)
+	t.Run("OrgRepositories", s.testOrgRepositories)
+	t.Run("UserRepositories", s.testUserRepositories)
+	t.Run("DeployKeys", s.testDeployKeys)
+	t.Run("TeamAccess", s.testTeamAccess)
+	t.Run("CommitsBranchesPullRequests", s.testCommitsBranchesPullRequests)
+	t.Run("BranchProtection", s.testBranchProtection)
+	t.Run("TagsReleases", s.testTagsReleases)
+	t.Run("Webhooks", s.testWebhooks)
+	t.Run("CommitStatuses", s.testCommitStatuses)
+	t.Run("Issues", s.testIssues)
+}
+
+// repoName returns a random repository name.
+func (s *suite) repoName() string {
+	//nolint:gosec
+	return fmt.Sprintf("%s%06d", s.cfg.RepositoryPrefix, rand.Intn(1000000))
+}
+
+// eventually calls fn until it returns nil, or Config.Eventually has passed.
+func (s *suite) eventually(fn func() error) error {
+	deadline := time.Now().Add(s.cfg.Eventually)
+	for {
+		err := fn()
+		if err == nil || !time.Now().Before(deadline) {
+			return err
+		}
+		time.Sleep(defaultInterval)
+	}
+}
+
+// expectError fails the test if err doesn't wrap target.
+func expectError(t *testing.T, desc string, err, target error) {
+	t.Helper()
+	if !errors.Is(err, target) {
+		t.Errorf("%s: expected error %q, got %v", desc, target, err)
+	}
+}
+
+// expectUnsupported checks that err is gitprovider.ErrNoProviderSupport for unsupported features,
+// and nil otherwise. It returns true if the feature is supported, and there was no error.
+func (s *suite) expectUnsupported(t *testing.T, f Feature, desc string, err error) bool {
+	t.Helper()
+	if !s.cfg.supports(f) {
+		expectError(t, desc, err, gitprovider.ErrNoProviderSupport)
+		return false
+	}
+	if err != nil {
+		t.Errorf("%s: %v", desc, err)
+		return false
+	}
+	return true
+}
+
+// newKey returns a new SSH public key.
+func newKey(t *testing.T) []byte {
+	t.Helper()
+	pair, err := testutils.NewECDSAGenerator(elliptic.P256()).Generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pair.PublicKey
+}
+
+// deleteOrgRepo deletes the given repository at the end of the test.
+func (s *suite) deleteOrgRepo(t *testing.T, ref gitprovider.OrgRepositoryRef) {
+	t.Cleanup(func() {
+		repo, err := s.c.OrgRepositories().Get(s.ctx, ref)
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			return
+		}
+		if err == nil {
+			err = repo.Delete(s.ctx)
+		}
+		if err != nil {
+			t.Errorf("cleanup of repository %s: %v", ref, err)
+		}
+	})
+}
+
+// createOrgRepo creates an auto-initialized repository in the organization, which is deleted at the end of the test.
+func (s *suite) createOrgRepo(t *testing.T) gitprovider.OrgRepository {
+	t.Helper()
+	ref := gitprovider.OrgRepositoryRef{OrganizationRef: s.cfg.Organization, RepositoryName: s.repoName()}
+	s.deleteOrgRepo(t, ref)
+	if _, err := s.c.OrgRepositories().Create(s.ctx, ref, gitprovider.RepositoryInfo{}, &gitprovider.RepositoryCreateOptions{
+		AutoInit: gitprovider.BoolVar(true),
+	}); err != nil {
+		t.Fatalf("creating repository %s: %v", ref, err)
+	}
+
+	// Get it again, so that the repository is known to be readable
+	var repo gitprovider.OrgRepository
+	if err := s.eventually(func() (err error) {
+		repo, err = s.c.OrgRepositories().Get(s.ctx, ref)
+		return
+	}); err != nil {
+		t.Fatalf("getting repository %s: %v", ref, err)
+	}
+	return repo
+}
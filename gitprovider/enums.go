@@ -168,3 +168,373 @@ const (
 	// Read/Write permission for public/private repositories.
 	TokenPermissionRWRepository TokenPermission = iota + 1
 )
+
+// PullRequestState is an enum specifying the state of a pull request.
+type PullRequestState string
+
+const (
+	// PullRequestStateOpen specifies that the pull request is open.
+	PullRequestStateOpen = PullRequestState("open")
+	// PullRequestStateClosed specifies that the pull request was closed without being merged.
+	PullRequestStateClosed = PullRequestState("closed")
+	// PullRequestStateMerged specifies that the pull request was merged.
+	PullRequestStateMerged = PullRequestState("merged")
+)
+
+// knownPullRequestStateValues is a map of known PullRequestState values, used for validation.
+//nolint:gochecknoglobals
+var knownPullRequestStateValues = map[PullRequestState]struct{}{
+	PullRequestStateOpen:   {},
+	PullRequestStateClosed: {},
+	PullRequestStateMerged: {},
+}
+
+// ValidatePullRequestState validates a given PullRequestState.
+// Use as errs.Append(ValidatePullRequestState(state), state, "FieldName").
+func ValidatePullRequestState(s PullRequestState) error {
+	_, ok := knownPullRequestStateValues[s]
+	if !ok {
+		return validation.ErrFieldEnumInvalid
+	}
+	return nil
+}
+
+// PullRequestStateVar returns a pointer to a PullRequestState.
+func PullRequestStateVar(s PullRequestState) *PullRequestState {
+	return &s
+}
+
+// MergeMethod is an enum specifying how a pull request is merged.
+type MergeMethod string
+
+const (
+	// MergeMethodMerge merges the head branch into the base branch with a merge commit.
+	MergeMethodMerge = MergeMethod("merge")
+	// MergeMethodSquash squashes the commits of the head branch into a single commit on the base branch.
+	MergeMethodSquash = MergeMethod("squash")
+	// MergeMethodRebase rebases the commits of the head branch onto the base branch.
+	MergeMethodRebase = MergeMethod("rebase")
+)
+
+// knownMergeMethodValues is a map of known MergeMethod values, used for validation.
+//nolint:gochecknoglobals
+var knownMergeMethodValues = map[MergeMethod]struct{}{
+	MergeMethodMerge:  {},
+	MergeMethodSquash: {},
+	MergeMethodRebase: {},
+}
+
+// ValidateMergeMethod validates a given MergeMethod.
+// Use as errs.Append(ValidateMergeMethod(method), method, "FieldName").
+func ValidateMergeMethod(m MergeMethod) error {
+	_, ok := knownMergeMethodValues[m]
+	if !ok {
+		return validation.ErrFieldEnumInvalid
+	}
+	return nil
+}
+
+// MergeMethodVar returns a pointer to a MergeMethod.
+func MergeMethodVar(m MergeMethod) *MergeMethod {
+	return &m
+}
+
+// PullRequestReviewState is an enum specifying the verdict of a pull request review.
+type PullRequestReviewState string
+
+const (
+	// PullRequestReviewStateApproved specifies that the reviewer approved the changes.
+	PullRequestReviewStateApproved = PullRequestReviewState("approved")
+	// PullRequestReviewStateChangesRequested specifies that the reviewer requested changes.
+	PullRequestReviewStateChangesRequested = PullRequestReviewState("changes_requested")
+	// PullRequestReviewStateCommented specifies that the reviewer only commented.
+	PullRequestReviewStateCommented = PullRequestReviewState("commented")
+)
+
+// knownPullRequestReviewStateValues is a map of known PullRequestReviewState values, used for validation.
+//nolint:gochecknoglobals
+var knownPullRequestReviewStateValues = map[PullRequestReviewState]struct{}{
+	PullRequestReviewStateApproved:         {},
+	PullRequestReviewStateChangesRequested: {},
+	PullRequestReviewStateCommented:        {},
+}
+
+// ValidatePullRequestReviewState validates a given PullRequestReviewState.
+// Use as errs.Append(ValidatePullRequestReviewState(state), state, "FieldName").
+func ValidatePullRequestReviewState(s PullRequestReviewState) error {
+	_, ok := knownPullRequestReviewStateValues[s]
+	if !ok {
+		return validation.ErrFieldEnumInvalid
+	}
+	return nil
+}
+
+// PullRequestReviewStateVar returns a pointer to a PullRequestReviewState.
+func PullRequestReviewStateVar(s PullRequestReviewState) *PullRequestReviewState {
+	return &s
+}
+
+// CommitFileAction is an enum specifying what a commit does with a file.
+type CommitFileAction string
+
+const (
+	// CommitFileActionCreate specifies that the file is added to the repository.
+	CommitFileActionCreate = CommitFileAction("create")
+	// CommitFileActionUpdate specifies that the content or mode of an existing file is changed.
+	CommitFileActionUpdate = CommitFileAction("update")
+	// CommitFileActionDelete specifies that an existing file is removed from the repository.
+	CommitFileActionDelete = CommitFileAction("delete")
+	// CommitFileActionMove specifies that an existing file is moved to another path, optionally
+	// changing its content.
+	CommitFileActionMove = CommitFileAction("move")
+)
+
+// knownCommitFileActionValues is a map of known CommitFileAction values, used for validation.
+//nolint:gochecknoglobals
+var knownCommitFileActionValues = map[CommitFileAction]struct{}{
+	CommitFileActionCreate: {},
+	CommitFileActionUpdate: {},
+	CommitFileActionDelete: {},
+	CommitFileActionMove:   {},
+}
+
+// ValidateCommitFileAction validates a given CommitFileAction.
+// Use as errs.Append(ValidateCommitFileAction(action), action, "FieldName").
+func ValidateCommitFileAction(a CommitFileAction) error {
+	_, ok := knownCommitFileActionValues[a]
+	if !ok {
+		return validation.ErrFieldEnumInvalid
+	}
+	return nil
+}
+
+// CommitFileActionVar returns a pointer to a CommitFileAction.
+func CommitFileActionVar(a CommitFileAction) *CommitFileAction {
+	return &a
+}
+
+// FileMode is an enum specifying the git mode of a file, i.e. of an entry in a git tree.
+type FileMode string
+
+const (
+	// FileModeRegular ("100644") specifies a regular, non-executable file.
+	FileModeRegular = FileMode("100644")
+	// FileModeExecutable ("100755") specifies an executable file.
+	FileModeExecutable = FileMode("100755")
+	// FileModeSymlink ("120000") specifies a symbolic link, whose content is the link target.
+	FileModeSymlink = FileMode("120000")
+	// FileModeDirectory ("040000") specifies a directory.
+	FileModeDirectory = FileMode("040000")
+	// FileModeSubmodule ("160000") specifies a submodule, i.e. a commit of another repository.
+	FileModeSubmodule = FileMode("160000")
+)
+
+// knownFileModeValues is a map of known FileMode values, used for validation.
+//nolint:gochecknoglobals
+var knownFileModeValues = map[FileMode]struct{}{
+	FileModeRegular:    {},
+	FileModeExecutable: {},
+	FileModeSymlink:    {},
+	FileModeDirectory:  {},
+	FileModeSubmodule:  {},
+}
+
+// ValidateFileMode validates a given FileMode.
+// Use as errs.Append(ValidateFileMode(mode), mode, "FieldName").
+func ValidateFileMode(m FileMode) error {
+	_, ok := knownFileModeValues[m]
+	if !ok {
+		return validation.ErrFieldEnumInvalid
+	}
+	return nil
+}
+
+// FileModeVar returns a pointer to a FileMode.
+func FileModeVar(m FileMode) *FileMode {
+	return &m
+}
+
+// ContentEncoding is an enum specifying how the content of a file is encoded.
+type ContentEncoding string
+
+const (
+	// ContentEncodingText specifies that the content is the (UTF-8) text of the file.
+	ContentEncodingText = ContentEncoding("text")
+	// ContentEncodingBase64 specifies that the content is base64-encoded, e.g. for binary files.
+	ContentEncodingBase64 = ContentEncoding("base64")
+)
+
+// knownContentEncodingValues is a map of known ContentEncoding values, used for validation.
+//nolint:gochecknoglobals
+var knownContentEncodingValues = map[ContentEncoding]struct{}{
+	ContentEncodingText:   {},
+	ContentEncodingBase64: {},
+}
+
+// ValidateContentEncoding validates a given ContentEncoding.
+// Use as errs.Append(ValidateContentEncoding(encoding), encoding, "FieldName").
+func ValidateContentEncoding(e ContentEncoding) error {
+	_, ok := knownContentEncodingValues[e]
+	if !ok {
+		return validation.ErrFieldEnumInvalid
+	}
+	return nil
+}
+
+// ContentEncodingVar returns a pointer to a ContentEncoding.
+func ContentEncodingVar(e ContentEncoding) *ContentEncoding {
+	return &e
+}
+
+// WebhookEvent is an enum specifying a kind of event a webhook is triggered by.
+type WebhookEvent string
+
+const (
+	// WebhookEventPush specifies pushes of commits to branches.
+	// This is called "push" in GitHub and "push_events" in GitLab.
+	WebhookEventPush = WebhookEvent("push")
+	// WebhookEventTag specifies the creation of tags.
+	// This is called "create" in GitHub and "tag_push_events" in GitLab.
+	WebhookEventTag = WebhookEvent("tag")
+	// WebhookEventPullRequest specifies changes of pull requests, e.g. opening or merging them.
+	// This is called "pull_request" in GitHub and "merge_requests_events" in GitLab.
+	WebhookEventPullRequest = WebhookEvent("pull_request")
+	// WebhookEventRelease specifies changes of releases.
+	// This is called "release" in GitHub and "releases_events" in GitLab.
+	WebhookEventRelease = WebhookEvent("release")
+	// WebhookEventIssue specifies changes of issues.
+	// This is called "issues" in GitHub and "issues_events" in GitLab.
+	WebhookEventIssue = WebhookEvent("issue")
+	// WebhookEventComment specifies comments on issues and pull requests.
+	// This is called "issue_comment" in GitHub and "note_events" in GitLab.
+	WebhookEventComment = WebhookEvent("comment")
+)
+
+// knownWebhookEventValues is a map of known WebhookEvent values, used for validation.
+//nolint:gochecknoglobals
+var knownWebhookEventValues = map[WebhookEvent]struct{}{
+	WebhookEventPush:        {},
+	WebhookEventTag:         {},
+	WebhookEventPullRequest: {},
+	WebhookEventRelease:     {},
+	WebhookEventIssue:       {},
+	WebhookEventComment:     {},
+}
+
+// ValidateWebhookEvent validates a given WebhookEvent.
+// Use as errs.Append(ValidateWebhookEvent(event), event, "FieldName").
+func ValidateWebhookEvent(e WebhookEvent) error {
+	_, ok := knownWebhookEventValues[e]
+	if !ok {
+		return validation.ErrFieldEnumInvalid
+	}
+	return nil
+}
+
+// WebhookEventVar returns a pointer to a WebhookEvent.
+func WebhookEventVar(e WebhookEvent) *WebhookEvent {
+	return &e
+}
+
+// WebhookContentType is an enum specifying how the payloads of a webhook are encoded.
+type WebhookContentType string
+
+const (
+	// WebhookContentTypeJSON specifies that the payload is the body of the request, as JSON.
+	WebhookContentTypeJSON = WebhookContentType("json")
+	// WebhookContentTypeForm specifies that the payload is the "payload" parameter of a
+	// form-encoded request body.
+	WebhookContentTypeForm = WebhookContentType("form")
+)
+
+// knownWebhookContentTypeValues is a map of known WebhookContentType values, used for validation.
+//nolint:gochecknoglobals
+var knownWebhookContentTypeValues = map[WebhookContentType]struct{}{
+	WebhookContentTypeJSON: {},
+	WebhookContentTypeForm: {},
+}
+
+// ValidateWebhookContentType validates a given WebhookContentType.
+// Use as errs.Append(ValidateWebhookContentType(contentType), contentType, "FieldName").
+func ValidateWebhookContentType(t WebhookContentType) error {
+	_, ok := knownWebhookContentTypeValues[t]
+	if !ok {
+		return validation.ErrFieldEnumInvalid
+	}
+	return nil
+}
+
+// WebhookContentTypeVar returns a pointer to a WebhookContentType.
+func WebhookContentTypeVar(t WebhookContentType) *WebhookContentType {
+	return &t
+}
+
+// CommitStatusState is an enum specifying the state of a commit status.
+type CommitStatusState string
+
+const (
+	// CommitStatusStatePending specifies that the check is queued or running.
+	CommitStatusStatePending = CommitStatusState("pending")
+	// CommitStatusStateSuccess specifies that the check succeeded.
+	CommitStatusStateSuccess = CommitStatusState("success")
+	// CommitStatusStateFailure specifies that the check failed.
+	CommitStatusStateFailure = CommitStatusState("failure")
+	// CommitStatusStateError specifies that the check couldn't be completed.
+	CommitStatusStateError = CommitStatusState("error")
+)
+
+// knownCommitStatusStateValues is a map of known CommitStatusState values, used for validation.
+//nolint:gochecknoglobals
+var knownCommitStatusStateValues = map[CommitStatusState]struct{}{
+	CommitStatusStatePending: {},
+	CommitStatusStateSuccess: {},
+	CommitStatusStateFailure: {},
+	CommitStatusStateError:   {},
+}
+
+// ValidateCommitStatusState validates a given CommitStatusState.
+// Use as errs.Append(ValidateCommitStatusState(state), state, "FieldName").
+func ValidateCommitStatusState(s CommitStatusState) error {
+	_, ok := knownCommitStatusStateValues[s]
+	if !ok {
+		return validation.ErrFieldEnumInvalid
+	}
+	return nil
+}
+
+// CommitStatusStateVar returns a pointer to a CommitStatusState.
+func CommitStatusStateVar(s CommitStatusState) *CommitStatusState {
+	return &s
+}
+
+// IssueState is an enum specifying the state of an issue.
+type IssueState string
+
+const (
+	// IssueStateOpen specifies that the issue is open.
+	IssueStateOpen = IssueState("open")
+	// IssueStateClosed specifies that the issue is closed.
+	IssueStateClosed = IssueState("closed")
+)
+
+// knownIssueStateValues is a map of known IssueState values, used for validation.
+//nolint:gochecknoglobals
+var knownIssueStateValues = map[IssueState]struct{}{
+	IssueStateOpen:   {},
+	IssueStateClosed: {},
+}
+
+// ValidateIssueState validates a given IssueState.
+// Use as errs.Append(ValidateIssueState(state), state, "FieldName").
+func ValidateIssueState(s IssueState) error {
+	_, ok := knownIssueStateValues[s]
+	if !ok {
+		return validation.ErrFieldEnumInvalid
+	}
+	return nil
+}
+
+// IssueStateVar returns a pointer to an IssueState.
+func IssueStateVar(s IssueState) *IssueState {
+	return &s
+}
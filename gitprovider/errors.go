@@ -42,6 +42,9 @@ var (
 	ErrAlreadyExists = errors.New("resource already exists, cannot create object. Use Reconcile() to create it idempotently")
 	// ErrNotFound is returned by .Get() and .Update() calls if the given resource doesn't exist.
 	ErrNotFound = errors.New("the requested resource was not found")
+	// ErrConflict is returned if the resource changed since it was read, e.g. if a branch moved
+	// while committing to it. The request can be retried with the current state.
+	ErrConflict = errors.New("the resource has changed since it was read, retry with its current state")
 	// ErrInvalidServerData is returned when the server returned invalid data, e.g. missing required fields in the response.
 	ErrInvalidServerData = errors.New("got invalid data from server, don't know how to handle")
 
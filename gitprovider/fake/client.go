@@ -0,0 +1,191 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// ProviderID is the provider ID for the fake provider.
+const ProviderID = gitprovider.ProviderID("fake")
+
+// NewClient creates a new gitprovider.Client instance backed by in-memory state. The state is empty
+// at first; organizations and teams are added using AddOrganization and AddTeam, while repositories
+// and everything in them are created through the gitprovider interfaces.
+//
+// The returned *Client is also available through Raw(), for tests only having a gitprovider.Client.
+func NewClient(optFns ...ClientOption) (*Client, error) {
+	// Complete the options struct
+	opts, err := makeOptions(optFns...)
+	if err != nil {
+		return nil, err
+	}
+
+	domain := DefaultDomain
+	if opts.Domain != nil {
+		domain = *opts.Domain
+	}
+	// By default, turn destructive actions off. But allow overrides.
+	destructiveActions := false
+	if opts.EnableDestructiveAPICalls != nil {
+		destructiveActions = *opts.EnableDestructiveAPICalls
+	}
+
+	s := newStore()
+	if opts.State != nil {
+		s = opts.State
+	}
+
+	ctx := &clientContext{s, domain, destructiveActions}
+	c := &Client{
+		clientContext: ctx,
+		orgs: &OrganizationsClient{
+			clientContext: ctx,
+		},
+		orgRepos: &OrgRepositoriesClient{
+			clientContext: ctx,
+		},
+		userRepos: &UserRepositoriesClient{
+			clientContext: ctx,
+		},
+	}
+	if opts.TokenPermissions != nil {
+		c.permissions = map[gitprovider.TokenPermission]bool{}
+		for _, permission := range opts.TokenPermissions {
+			c.permissions[permission] = true
+		}
+	}
+	return c, nil
+}
+
+type clientContext struct {
+	s                  *store
+	domain             string
+	destructiveActions bool
+}
+
+// Client implements the gitprovider.Client interface.
+var _ gitprovider.Client = &Client{}
+
+// Client is an in-memory fake of a Git provider.
+type Client struct {
+	*clientContext
+
+	orgs      *OrganizationsClient
+	orgRepos  *OrgRepositoriesClient
+	userRepos *UserRepositoriesClient
+
+	// permissions of the token, or nil if it has all permissions
+	permissions map[gitprovider.TokenPermission]bool
+
+	// quotaMu guards quota
+	quotaMu sync.Mutex
+	// quota reported by Quota, or nil if rate limits aren't simulated
+	quota *gitprovider.Quota
+}
+
+// SupportedDomain returns the domain endpoint for this client, as set by WithDomain.
+// This field is set at client creation time, and can't be changed.
+func (c *Client) SupportedDomain() string {
+	return c.domain
+}
+
+// ProviderID returns the provider ID "fake".
+// This field is set at client creation time, and can't be changed.
+func (c *Client) ProviderID() gitprovider.ProviderID {
+	return ProviderID
+}
+
+// Raw returns the *Client itself, as there is no underlying Go client.
+func (c *Client) Raw() interface{} {
+	return c
+}
+
+// Organizations returns the OrganizationsClient handling sets of organizations.
+func (c *Client) Organizations() gitprovider.OrganizationsClient {
+	return c.orgs
+}
+
+// OrgRepositories returns the OrgRepositoriesClient handling sets of repositories in an organization.
+func (c *Client) OrgRepositories() gitprovider.OrgRepositoriesClient {
+	return c.orgRepos
+}
+
+// UserRepositories returns the UserRepositoriesClient handling sets of repositories for a user.
+func (c *Client) UserRepositories() gitprovider.UserRepositoriesClient {
+	return c.userRepos
+}
+
+// HasTokenPermission returns true if the given token has the given permissions. All permissions
+// are granted, unless the client was created using WithTokenPermissions.
+func (c *Client) HasTokenPermission(_ context.Context, permission gitprovider.TokenPermission) (bool, error) {
+	if c.permissions == nil {
+		return true, nil
+	}
+	return c.permissions[permission], nil
+}
+
+// Quota returns the quota set using SetQuota. It is not decremented by requests.
+//
+// ErrNoProviderSupport is returned if SetQuota wasn't called.
+func (c *Client) Quota(_ context.Context) (gitprovider.Quota, error) {
+	c.quotaMu.Lock()
+	defer c.quotaMu.Unlock()
+
+	if c.quota == nil {
+		return gitprovider.Quota{}, gitprovider.ErrNoProviderSupport
+	}
+	return *c.quota, nil
+}
+
+// SetQuota sets the quota reported by Quota, e.g. for testing how work is scheduled.
+func (c *Client) SetQuota(quota gitprovider.Quota) {
+	c.quotaMu.Lock()
+	defer c.quotaMu.Unlock()
+
+	c.quota = &quota
+}
+
+// AddOrganization adds an organization to the in-memory state. Sub-organizations can only be added
+// after their parent organization.
+//
+// ErrAlreadyExists is returned if the organization already exists.
+func (c *Client) AddOrganization(ref gitprovider.OrganizationRef, info gitprovider.OrganizationInfo) error {
+	// Make sure the OrganizationRef is valid
+	if err := validateOrganizationRef(ref, c.domain); err != nil {
+		return err
+	}
+	return c.s.addOrg(ref, organizationToAPI(&info))
+}
+
+// AddTeam adds a team to the given organization in the in-memory state.
+//
+// ErrNotFound is returned if the organization doesn't exist, and ErrAlreadyExists if the team does.
+func (c *Client) AddTeam(ref gitprovider.OrganizationRef, info gitprovider.TeamInfo) error {
+	// Make sure the OrganizationRef is valid
+	if err := validateOrganizationRef(ref, c.domain); err != nil {
+		return err
+	}
+	if len(info.Name) == 0 {
+		return fmt.Errorf("team name must not be empty: %w", gitprovider.ErrInvalidArgument)
+	}
+	return c.s.addTeam(ref, &Team{Name: info.Name, Members: info.Members})
+}
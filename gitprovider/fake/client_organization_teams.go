@@ -0,0 +1,61 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// TeamsClient implements the gitprovider.TeamsClient interface.
+var _ gitprovider.TeamsClient = &TeamsClient{}
+
+// TeamsClient handles teams organization-wide.
+type TeamsClient struct {
+	*clientContext
+	ref gitprovider.OrganizationRef
+}
+
+// Get a team within the specific organization.
+//
+// name may include slashes, but must not be an empty string.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *TeamsClient) Get(_ context.Context, name string) (gitprovider.Team, error) {
+	apiObj, err := c.s.getTeam(c.ref, name)
+	if err != nil {
+		return nil, err
+	}
+	return newTeam(c, apiObj), nil
+}
+
+// List all teams within the specific organization.
+//
+// List returns all available teams.
+func (c *TeamsClient) List(_ context.Context) ([]gitprovider.Team, error) {
+	apiObjs, err := c.s.listTeams(c.ref)
+	if err != nil {
+		return nil, err
+	}
+
+	teams := make([]gitprovider.Team, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		teams = append(teams, newTeam(c, apiObj))
+	}
+	return teams, nil
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// OrganizationsClient implements the gitprovider.OrganizationsClient interface.
+var _ gitprovider.OrganizationsClient = &OrganizationsClient{}
+
+// OrganizationsClient operates on organizations the user has access to.
+type OrganizationsClient struct {
+	*clientContext
+}
+
+// Get a specific organization the user has access to.
+// This might also refer to a sub-organization.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *OrganizationsClient) Get(_ context.Context, ref gitprovider.OrganizationRef) (gitprovider.Organization, error) {
+	// Make sure the OrganizationRef is valid
+	if err := validateOrganizationRef(ref, c.domain); err != nil {
+		return nil, err
+	}
+
+	apiObj, err := c.s.getOrg(ref)
+	if err != nil {
+		return nil, err
+	}
+	return newOrganization(c.clientContext, apiObj, ref), nil
+}
+
+// List all top-level organizations the specific user has access to.
+//
+// List returns all available organizations.
+func (c *OrganizationsClient) List(_ context.Context) ([]gitprovider.Organization, error) {
+	return c.list(func(ref gitprovider.OrganizationRef) bool {
+		return len(ref.SubOrganizations) == 0
+	}), nil
+}
+
+// Children returns the immediate child-organizations for the specific OrganizationRef o.
+// The OrganizationRef may point to any existing sub-organization.
+//
+// ErrNotFound is returned if the given organization does not exist.
+func (c *OrganizationsClient) Children(_ context.Context, ref gitprovider.OrganizationRef) ([]gitprovider.Organization, error) {
+	// Make sure the OrganizationRef is valid
+	if err := validateOrganizationRef(ref, c.domain); err != nil {
+		return nil, err
+	}
+	// Make sure the parent exists
+	if _, err := c.s.getOrg(ref); err != nil {
+		return nil, err
+	}
+
+	return c.list(func(child gitprovider.OrganizationRef) bool {
+		return len(child.SubOrganizations) != 0 && parentRef(child).String() == ref.String()
+	}), nil
+}
+
+func (c *OrganizationsClient) list(filter func(ref gitprovider.OrganizationRef) bool) []gitprovider.Organization {
+	refs, apiObjs := c.s.listOrgs(filter)
+	orgs := make([]gitprovider.Organization, 0, len(apiObjs))
+	for i, apiObj := range apiObjs {
+		orgs = append(orgs, newOrganization(c.clientContext, apiObj, refs[i]))
+	}
+	return orgs
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// BranchClient implements the gitprovider.BranchClient interface.
+var _ gitprovider.BranchClient = &BranchClient{}
+
+// BranchClient operates on the branches for a specific repository.
+type BranchClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// Get returns the branch with the given name.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *BranchClient) Get(_ context.Context, branch string) (gitprovider.Branch, error) {
+	apiObj, err := c.s.getBranch(c.ref, branch)
+	if err != nil {
+		return nil, err
+	}
+	return newBranch(apiObj), nil
+}
+
+// List lists all branches of the repository, sorted by name.
+func (c *BranchClient) List(_ context.Context) ([]gitprovider.Branch, error) {
+	apiObjs, err := c.s.listBranches(c.ref)
+	if err != nil {
+		return nil, err
+	}
+
+	branches := make([]gitprovider.Branch, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		branches = append(branches, newBranch(apiObj))
+	}
+	return branches, nil
+}
+
+// Create creates a branch pointing to the commit with the given sha.
+//
+// ErrNotFound is returned if the commit does not exist.
+// ErrAlreadyExists will be returned if the branch already exists.
+func (c *BranchClient) Create(_ context.Context, branch, sha string) error {
+	return c.s.createBranch(c.ref, branch, sha)
+}
+
+// CreateFrom creates a branch starting at the head of another branch.
+//
+// ErrNotFound is returned if from doesn't exist, and ErrAlreadyExists if branch does.
+func (c *BranchClient) CreateFrom(_ context.Context, branch, from string) (gitprovider.Branch, error) {
+	apiObj, err := c.s.createBranchFrom(c.ref, branch, from)
+	if err != nil {
+		return nil, err
+	}
+	return newBranch(apiObj), nil
+}
+
+// Delete deletes the branch with the given name.
+//
+// ErrDestructiveCallDisallowed is returned if the client wasn't set up with destructive
+// API calls enabled. ErrNotFound is returned if the resource does not exist.
+func (c *BranchClient) Delete(_ context.Context, branch string) error {
+	if err := c.allowDestructiveCall(); err != nil {
+		return err
+	}
+	return c.s.deleteBranch(c.ref, branch)
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// CommitClient implements the gitprovider.CommitClient interface.
+var _ gitprovider.CommitClient = &CommitClient{}
+
+// CommitClient operates on the commits for a specific repository.
+type CommitClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// ListPage lists repository commits of the given page and page size, newest first.
+//
+// ErrNotFound is returned if the branch does not exist.
+func (c *CommitClient) ListPage(_ context.Context, branch string, perPage, page int) ([]gitprovider.Commit, error) {
+	apiObjs, err := c.s.listCommits(c.ref, branch, perPage, page)
+	if err != nil {
+		return nil, err
+	}
+
+	commits := make([]gitprovider.Commit, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		commits = append(commits, newCommit(apiObj))
+	}
+	return commits, nil
+}
+
+// Create creates a commit with the given specifications on top of branch. Files without an
+// Action are deleted if their Content is nil, and created or updated otherwise. If the
+// repository is empty, a root commit is created on branch.
+//
+// ErrNotFound is returned if the branch, or a file to update, delete or move, does not exist.
+// ErrAlreadyExists is returned if a file to create, or the destination of a move, exists.
+// ErrConflict is returned if the head of branch isn't the ExpectedParentSHA given in opts.
+func (c *CommitClient) Create(_ context.Context, branch string, message string, files []gitprovider.CommitFile, opts ...gitprovider.CommitCreateOption) (gitprovider.Commit, error) {
+	o, err := gitprovider.MakeCommitCreateOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no files added: %w", gitprovider.ErrInvalidArgument)
+	}
+	for _, file := range files {
+		if err := file.ValidateInfo(); err != nil {
+			return nil, err
+		}
+	}
+
+	apiObj, err := c.s.createCommit(c.ref, branch, message, files, o)
+	if err != nil {
+		return nil, err
+	}
+	return newCommit(apiObj), nil
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// CommitStatusClient implements the gitprovider.CommitStatusClient interface.
+var _ gitprovider.CommitStatusClient = &CommitStatusClient{}
+
+// CommitStatusClient operates on the statuses of the commits of a specific repository.
+type CommitStatusClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// Get returns the combined status of the commit with the given sha, i.e. the latest status of
+// each context, with the state computed by gitprovider.CombineCommitStatusStates.
+//
+// ErrNotFound is returned if the commit does not exist.
+func (c *CommitStatusClient) Get(_ context.Context, sha string) (gitprovider.CombinedCommitStatus, error) {
+	apiObjs, err := c.s.listCommitStatuses(c.ref, sha)
+	if err != nil {
+		return nil, err
+	}
+
+	// The statuses are sorted newest first, so the first status of each context is the latest
+	latest := make([]CommitStatus, 0, len(apiObjs))
+	seen := map[string]bool{}
+	for _, apiObj := range apiObjs {
+		if seen[apiObj.Context] {
+			continue
+		}
+		seen[apiObj.Context] = true
+		latest = append(latest, *apiObj)
+	}
+	return newCombinedCommitStatus(sha, latest), nil
+}
+
+// List lists all statuses of the commit with the given sha, newest first, including the
+// statuses replaced by newer ones with the same context.
+//
+// ErrNotFound is returned if the commit does not exist.
+func (c *CommitStatusClient) List(_ context.Context, sha string) ([]gitprovider.CommitStatus, error) {
+	apiObjs, err := c.s.listCommitStatuses(c.ref, sha)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]gitprovider.CommitStatus, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		statuses = append(statuses, newCommitStatus(apiObj))
+	}
+	return statuses, nil
+}
+
+// Create creates a status for the commit with the given sha, which replaces the status with
+// the same req.Context in the combined status of the commit.
+//
+// ErrNotFound is returned if the commit does not exist.
+func (c *CommitStatusClient) Create(_ context.Context, sha string, req gitprovider.CommitStatusInfo) (gitprovider.CommitStatus, error) {
+	// Default and validate the request
+	req.Default()
+	if err := req.ValidateInfo(); err != nil {
+		return nil, err
+	}
+
+	apiObj, err := c.s.createCommitStatus(c.ref, &CommitStatus{
+		SHA:         sha,
+		State:       req.State,
+		Context:     req.Context,
+		Description: req.Description,
+		TargetURL:   req.TargetURL,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newCommitStatus(apiObj), nil
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// FileClient implements the gitprovider.FileClient interface.
+var _ gitprovider.FileClient = &FileClient{}
+
+// FileClient reads the files of a specific repository.
+type FileClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// Get returns the file at path as of ref, i.e. a branch or commit sha, including its content.
+//
+// ErrNotFound is returned if ref doesn't exist, or if path isn't a file as of ref.
+func (c *FileClient) Get(_ context.Context, path, ref string) (gitprovider.File, error) {
+	apiObj, err := c.s.getFile(c.ref, path, ref)
+	if err != nil {
+		return nil, err
+	}
+	return newFile(apiObj), nil
+}
+
+// List lists the files and directories in the directory at dirPath as of ref, sorted by path.
+//
+// ErrNotFound is returned if ref doesn't exist, or if dirPath isn't a directory as of ref.
+func (c *FileClient) List(_ context.Context, dirPath, ref string) ([]gitprovider.File, error) {
+	apiObjs, err := c.s.listFiles(c.ref, dirPath, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]gitprovider.File, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		files = append(files, newFile(apiObj))
+	}
+	return files, nil
+}
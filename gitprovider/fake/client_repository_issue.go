@@ -0,0 +1,170 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+	"time"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// IssueClient implements the gitprovider.IssueClient interface.
+var _ gitprovider.IssueClient = &IssueClient{}
+
+// IssueClient operates on the issues for a specific repository. Issues are numbered separately
+// from pull requests.
+type IssueClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// Get returns the issue with the given number.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *IssueClient) Get(_ context.Context, number int) (gitprovider.Issue, error) {
+	apiObj, err := c.s.getIssue(c.ref, number)
+	if err != nil {
+		return nil, err
+	}
+	return newIssue(c.clientContext, apiObj, c.ref), nil
+}
+
+// List lists the issues of the repository matching the given filters, sorted by number.
+func (c *IssueClient) List(_ context.Context, opts ...gitprovider.IssueListOption) ([]gitprovider.Issue, error) {
+	o, err := gitprovider.MakeIssueListOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	apiObjs, err := c.s.listIssues(c.ref)
+	if err != nil {
+		return nil, err
+	}
+
+	issues := make([]gitprovider.Issue, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		issue := newIssue(c.clientContext, apiObj, c.ref)
+		if o.Matches(issue.Get()) {
+			issues = append(issues, issue)
+		}
+	}
+	return issues, nil
+}
+
+// Create creates an open issue with the given specifications. req.State is ignored.
+func (c *IssueClient) Create(_ context.Context, req gitprovider.IssueInfo) (gitprovider.Issue, error) {
+	// Default and validate the request
+	req.Default()
+	if err := req.ValidateInfo(); err != nil {
+		return nil, err
+	}
+
+	apiObj, err := c.s.createIssue(c.ref, &Issue{
+		Title:     req.Title,
+		Body:      req.Body,
+		Labels:    req.Labels,
+		Assignees: req.Assignees,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newIssue(c.clientContext, apiObj, c.ref), nil
+}
+
+// Update changes the title, body, labels and/or assignees of the issue with the given number.
+// nil fields of opts are left unchanged.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *IssueClient) Update(_ context.Context, number int, opts gitprovider.IssueUpdateOptions) (gitprovider.Issue, error) {
+	return c.update(number, func(issue *Issue) {
+		if opts.Title != nil {
+			issue.Title = *opts.Title
+		}
+		if opts.Body != nil {
+			issue.Body = *opts.Body
+		}
+		if opts.Labels != nil {
+			issue.Labels = append([]string(nil), opts.Labels...)
+		}
+		if opts.Assignees != nil {
+			issue.Assignees = append([]string(nil), opts.Assignees...)
+		}
+	})
+}
+
+// Close closes the issue with the given number. Closing a closed issue is a no-op.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *IssueClient) Close(_ context.Context, number int) (gitprovider.Issue, error) {
+	return c.update(number, func(issue *Issue) {
+		if issue.State == gitprovider.IssueStateOpen {
+			issue.State = gitprovider.IssueStateClosed
+			issue.ClosedAt = time.Now().UTC()
+		}
+	})
+}
+
+// Reopen reopens the issue with the given number. Reopening an open issue is a no-op.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *IssueClient) Reopen(_ context.Context, number int) (gitprovider.Issue, error) {
+	return c.update(number, func(issue *Issue) {
+		issue.State = gitprovider.IssueStateOpen
+		issue.ClosedAt = time.Time{}
+	})
+}
+
+// AddLabels adds the given labels to the issue with the given number, keeping the existing ones.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *IssueClient) AddLabels(_ context.Context, number int, labels []string) (gitprovider.Issue, error) {
+	return c.update(number, func(issue *Issue) {
+		for _, label := range labels {
+			if !containsString(issue.Labels, label) {
+				issue.Labels = append(issue.Labels, label)
+			}
+		}
+	})
+}
+
+// RemoveLabels removes the given labels from the issue with the given number. Labels the issue
+// doesn't have are ignored.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *IssueClient) RemoveLabels(_ context.Context, number int, labels []string) (gitprovider.Issue, error) {
+	return c.update(number, func(issue *Issue) {
+		kept := issue.Labels[:0]
+		for _, label := range issue.Labels {
+			if !containsString(labels, label) {
+				kept = append(kept, label)
+			}
+		}
+		issue.Labels = kept
+	})
+}
+
+func (c *IssueClient) update(number int, fn func(issue *Issue)) (gitprovider.Issue, error) {
+	apiObj, err := c.s.updateIssue(c.ref, number, func(issue *Issue) error {
+		fn(issue)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newIssue(c.clientContext, apiObj, c.ref), nil
+}
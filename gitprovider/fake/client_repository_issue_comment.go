@@ -0,0 +1,74 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// IssueCommentClient implements the gitprovider.IssueCommentClient interface.
+var _ gitprovider.IssueCommentClient = &IssueCommentClient{}
+
+// IssueCommentClient operates on the comments of a specific issue.
+type IssueCommentClient struct {
+	*clientContext
+	ref    gitprovider.RepositoryRef
+	number int
+}
+
+// List all comments of the issue, oldest first.
+func (c *IssueCommentClient) List(_ context.Context) ([]gitprovider.IssueComment, error) {
+	apiObjs, err := c.s.listIssueComments(c.ref, c.number)
+	if err != nil {
+		return nil, err
+	}
+
+	comments := make([]gitprovider.IssueComment, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		comments = append(comments, newIssueComment(apiObj))
+	}
+	return comments, nil
+}
+
+// Create adds a comment with the given body to the issue.
+func (c *IssueCommentClient) Create(_ context.Context, body string) (gitprovider.IssueComment, error) {
+	apiObj, err := c.s.createIssueComment(c.ref, c.number, body)
+	if err != nil {
+		return nil, err
+	}
+	return newIssueComment(apiObj), nil
+}
+
+// Update changes the body of the comment with the given ID.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *IssueCommentClient) Update(_ context.Context, id int64, body string) (gitprovider.IssueComment, error) {
+	apiObj, err := c.s.updateIssueComment(c.ref, c.number, id, body)
+	if err != nil {
+		return nil, err
+	}
+	return newIssueComment(apiObj), nil
+}
+
+// Delete deletes the comment with the given ID.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *IssueCommentClient) Delete(_ context.Context, id int64) error {
+	return c.s.deleteIssueComment(c.ref, c.number, id)
+}
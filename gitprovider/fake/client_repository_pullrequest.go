@@ -0,0 +1,207 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// PullRequestClient implements the gitprovider.PullRequestClient interface.
+var _ gitprovider.PullRequestClient = &PullRequestClient{}
+
+// PullRequestClient operates on the pull requests for a specific repository.
+type PullRequestClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// Get returns the pull request with the given number.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *PullRequestClient) Get(_ context.Context, number int) (gitprovider.PullRequest, error) {
+	apiObj, err := c.s.getPullRequest(c.ref, number)
+	if err != nil {
+		return nil, err
+	}
+	return newPullRequest(c.clientContext, apiObj, c.ref), nil
+}
+
+// List lists the pull requests of the repository matching the given filters, sorted by number.
+func (c *PullRequestClient) List(_ context.Context, opts ...gitprovider.PullRequestListOption) ([]gitprovider.PullRequest, error) {
+	o, err := gitprovider.MakePullRequestListOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+	apiObjs, err := c.s.listPullRequests(c.ref)
+	if err != nil {
+		return nil, err
+	}
+	prs := make([]gitprovider.PullRequest, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		pr := newPullRequest(c.clientContext, apiObj, c.ref)
+		if o.Matches(pr.Get()) {
+			prs = append(prs, pr)
+		}
+	}
+	return prs, nil
+}
+
+// Create opens a pull request merging branch into baseBranch.
+//
+// ErrNotFound is returned if either branch does not exist.
+func (c *PullRequestClient) Create(_ context.Context, title, branch, baseBranch, description string) (gitprovider.PullRequest, error) {
+	apiObj, err := c.s.createPullRequest(c.ref, &PullRequest{
+		Title:       title,
+		Description: description,
+		Head:        branch,
+		Base:        baseBranch,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newPullRequest(c.clientContext, apiObj, c.ref), nil
+}
+
+// Update changes the title, description, base branch and/or labels of the pull request with
+// the given number. nil fields of opts are left unchanged.
+//
+// ErrNotFound is returned if the pull request or the new base branch does not exist.
+func (c *PullRequestClient) Update(_ context.Context, number int, opts gitprovider.PullRequestUpdateOptions) (gitprovider.PullRequest, error) {
+	apiObj, err := c.s.updatePullRequest(c.ref, number, func(r *repoState, pr *PullRequest) error {
+		if opts.BaseBranch != nil {
+			if _, ok := r.branches[*opts.BaseBranch]; !ok {
+				return fmt.Errorf("branch %q: %w", *opts.BaseBranch, gitprovider.ErrNotFound)
+			}
+			pr.Base = *opts.BaseBranch
+		}
+		if opts.Title != nil {
+			pr.Title = *opts.Title
+		}
+		if opts.Description != nil {
+			pr.Description = *opts.Description
+		}
+		if opts.Labels != nil {
+			pr.Labels = append([]string(nil), opts.Labels...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newPullRequest(c.clientContext, apiObj, c.ref), nil
+}
+
+// Merge merges the pull request with the given number, committing the changes of its head branch
+// onto its base branch. All merge methods result in a single commit.
+//
+// ErrInvalidArgument is returned if the pull request isn't open.
+func (c *PullRequestClient) Merge(_ context.Context, number int, method gitprovider.MergeMethod, message string) error {
+	if err := gitprovider.ValidateMergeMethod(method); err != nil {
+		return fmt.Errorf("merge method %q: %w", method, gitprovider.ErrInvalidArgument)
+	}
+	_, err := c.s.updatePullRequest(c.ref, number, func(r *repoState, pr *PullRequest) error {
+		if pr.State != gitprovider.PullRequestStateOpen {
+			return fmt.Errorf("pull request %d is %s: %w", number, pr.State, gitprovider.ErrInvalidArgument)
+		}
+		if message == "" {
+			message = fmt.Sprintf("Merge pull request #%d from %s", number, pr.Head)
+		}
+		if _, err := r.merge(pr.Head, pr.Base, message); err != nil {
+			return err
+		}
+		pr.State = gitprovider.PullRequestStateMerged
+		pr.MergedAt = time.Now().UTC()
+		pr.ClosedAt = pr.MergedAt
+		return nil
+	})
+	return err
+}
+
+// Close closes the pull request with the given number, without merging it.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *PullRequestClient) Close(_ context.Context, number int) (gitprovider.PullRequest, error) {
+	apiObj, err := c.s.updatePullRequest(c.ref, number, func(_ *repoState, pr *PullRequest) error {
+		if pr.State == gitprovider.PullRequestStateOpen {
+			pr.State = gitprovider.PullRequestStateClosed
+			pr.ClosedAt = time.Now().UTC()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newPullRequest(c.clientContext, apiObj, c.ref), nil
+}
+
+// Reconcile makes sure the given desired state (req) becomes the actual state in the backing Git provider.
+// The pull request is looked up by req.HeadBranch and req.BaseBranch among the open pull
+// requests; the title, description and labels of req are reconciled.
+//
+// If req doesn't exist under the hood, it is created (actionTaken == true).
+// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+// If req is already the actual state, this is a no-op (actionTaken == false).
+func (c *PullRequestClient) Reconcile(ctx context.Context, req gitprovider.PullRequestInfo) (gitprovider.PullRequest, bool, error) {
+	// First thing, validate the request
+	if err := req.ValidateInfo(); err != nil {
+		return nil, false, err
+	}
+
+	// Look for an open pull request from the head into the base branch
+	prs, err := c.List(ctx, &gitprovider.PullRequestListOptions{
+		State:      gitprovider.PullRequestStateVar(gitprovider.PullRequestStateOpen),
+		HeadBranch: &req.HeadBranch,
+		BaseBranch: &req.BaseBranch,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Create if not found, including the labels
+	if len(prs) == 0 {
+		apiObj, err := c.s.createPullRequest(c.ref, &PullRequest{
+			Title:       req.Title,
+			Description: req.Description,
+			Head:        req.HeadBranch,
+			Base:        req.BaseBranch,
+			Labels:      req.Labels,
+		})
+		if err != nil {
+			return nil, true, err
+		}
+		return newPullRequest(c.clientContext, apiObj, c.ref), true, nil
+	}
+
+	// If the desired matches the actual state, just return the actual state
+	actual := prs[0]
+	if req.Equals(actual.Get()) {
+		return actual, false, nil
+	}
+
+	// Apply the desired state by running Update
+	resp, err := c.Update(ctx, actual.Get().Number, gitprovider.PullRequestUpdateOptions{
+		Title:       &req.Title,
+		Description: &req.Description,
+		// Use a non-nil slice, so that labels are removed if none are desired
+		Labels: append([]string{}, req.Labels...),
+	})
+	return resp, true, err
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// PullRequestCommentClient implements the gitprovider.PullRequestCommentClient interface.
+var _ gitprovider.PullRequestCommentClient = &PullRequestCommentClient{}
+
+// PullRequestCommentClient operates on the comments of a specific pull request.
+type PullRequestCommentClient struct {
+	*clientContext
+	ref    gitprovider.RepositoryRef
+	number int
+}
+
+// List all comments of the pull request, oldest first.
+//
+// ErrNotFound is returned if the pull request does not exist.
+func (c *PullRequestCommentClient) List(_ context.Context) ([]gitprovider.PullRequestComment, error) {
+	apiObjs, err := c.s.listPullRequestComments(c.ref, c.number)
+	if err != nil {
+		return nil, err
+	}
+	comments := make([]gitprovider.PullRequestComment, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		comments = append(comments, newPullRequestComment(apiObj))
+	}
+	return comments, nil
+}
+
+// Create adds a comment with the given body to the pull request. The fake doesn't model users,
+// so the author of the comment is empty.
+//
+// ErrNotFound is returned if the pull request does not exist.
+func (c *PullRequestCommentClient) Create(_ context.Context, body string) (gitprovider.PullRequestComment, error) {
+	apiObj, err := c.s.createPullRequestComment(c.ref, c.number, body)
+	if err != nil {
+		return nil, err
+	}
+	return newPullRequestComment(apiObj), nil
+}
+
+// Update changes the body of the comment with the given ID.
+//
+// ErrNotFound is returned if the comment does not exist on this pull request.
+func (c *PullRequestCommentClient) Update(_ context.Context, id int64, body string) (gitprovider.PullRequestComment, error) {
+	apiObj, err := c.s.updatePullRequestComment(c.ref, c.number, id, body)
+	if err != nil {
+		return nil, err
+	}
+	return newPullRequestComment(apiObj), nil
+}
+
+// Delete deletes the comment with the given ID.
+//
+// ErrNotFound is returned if the comment does not exist on this pull request.
+func (c *PullRequestCommentClient) Delete(_ context.Context, id int64) error {
+	return c.s.deletePullRequestComment(c.ref, c.number, id)
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// PullRequestReviewClient implements the gitprovider.PullRequestReviewClient interface.
+var _ gitprovider.PullRequestReviewClient = &PullRequestReviewClient{}
+
+// PullRequestReviewClient operates on the reviews of a specific pull request.
+type PullRequestReviewClient struct {
+	*clientContext
+	ref    gitprovider.RepositoryRef
+	number int
+}
+
+// List all submitted reviews of the pull request, oldest first.
+//
+// ErrNotFound is returned if the pull request does not exist.
+func (c *PullRequestReviewClient) List(_ context.Context) ([]gitprovider.PullRequestReview, error) {
+	apiObjs, err := c.s.listPullRequestReviews(c.ref, c.number)
+	if err != nil {
+		return nil, err
+	}
+	reviews := make([]gitprovider.PullRequestReview, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		reviews = append(reviews, newPullRequestReview(apiObj))
+	}
+	return reviews, nil
+}
+
+// Submit submits a review with the given verdict and body. The fake doesn't model users, so the
+// author of the review is empty.
+//
+// ErrInvalidArgument is returned if the verdict is unknown, or if the body is empty when not
+// approving. ErrNotFound is returned if the pull request does not exist.
+func (c *PullRequestReviewClient) Submit(_ context.Context, state gitprovider.PullRequestReviewState, body string) (gitprovider.PullRequestReview, error) {
+	if err := gitprovider.ValidatePullRequestReviewState(state); err != nil {
+		return nil, fmt.Errorf("review state %q: %w", state, gitprovider.ErrInvalidArgument)
+	}
+	// Like on GitHub, only approvals may come without a body
+	if body == "" && state != gitprovider.PullRequestReviewStateApproved {
+		return nil, fmt.Errorf("%s review without a body: %w", state, gitprovider.ErrInvalidArgument)
+	}
+	apiObj, err := c.s.createPullRequestReview(c.ref, c.number, state, body)
+	if err != nil {
+		return nil, err
+	}
+	return newPullRequestReview(apiObj), nil
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+	"errors"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// ReleaseClient implements the gitprovider.ReleaseClient interface.
+var _ gitprovider.ReleaseClient = &ReleaseClient{}
+
+// ReleaseClient operates on the releases for a specific repository.
+type ReleaseClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// Get returns the release of the tag with the given name, including drafts.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *ReleaseClient) Get(_ context.Context, tagName string) (gitprovider.Release, error) {
+	apiObj, err := c.s.getRelease(c.ref, tagName)
+	if err != nil {
+		return nil, err
+	}
+	return newRelease(c, apiObj), nil
+}
+
+// List lists all releases of the repository, including drafts, in the order they were created.
+func (c *ReleaseClient) List(_ context.Context) ([]gitprovider.Release, error) {
+	apiObjs, err := c.s.listReleases(c.ref)
+	if err != nil {
+		return nil, err
+	}
+
+	releases := make([]gitprovider.Release, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		releases = append(releases, newRelease(c, apiObj))
+	}
+	return releases, nil
+}
+
+// Create creates a release with the given specifications. The tag req.TagName is created
+// from req.TargetCommitish, or the default branch if that's empty, if it doesn't exist.
+//
+// ErrNotFound is returned if the tag and req.TargetCommitish don't exist.
+// ErrAlreadyExists is returned if the tag already has a release.
+func (c *ReleaseClient) Create(_ context.Context, req gitprovider.ReleaseInfo) (gitprovider.Release, error) {
+	if err := gitprovider.ValidateAndDefaultInfo(&req); err != nil {
+		return nil, err
+	}
+	apiObj, err := c.s.createRelease(c.ref, releaseToAPI(&req))
+	if err != nil {
+		return nil, err
+	}
+	return newRelease(c, apiObj), nil
+}
+
+// Update changes the name, body, and draft and prerelease state of the release of the tag
+// req.TagName to the ones of req.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *ReleaseClient) Update(_ context.Context, req gitprovider.ReleaseInfo) (gitprovider.Release, error) {
+	if err := gitprovider.ValidateAndDefaultInfo(&req); err != nil {
+		return nil, err
+	}
+	apiObj, err := c.s.updateRelease(c.ref, releaseToAPI(&req))
+	if err != nil {
+		return nil, err
+	}
+	return newRelease(c, apiObj), nil
+}
+
+// Delete deletes the release of the tag with the given name, including its assets. The tag
+// itself is kept.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *ReleaseClient) Delete(_ context.Context, tagName string) error {
+	return c.s.deleteRelease(c.ref, tagName)
+}
+
+// Reconcile makes sure the given desired state (req) becomes the actual state in the backing Git provider.
+// The release is looked up by req.TagName.
+//
+// If req doesn't exist under the hood, it is created (actionTaken == true).
+// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+// If req is already the actual state, this is a no-op (actionTaken == false).
+func (c *ReleaseClient) Reconcile(ctx context.Context, req gitprovider.ReleaseInfo) (gitprovider.Release, bool, error) {
+	// First thing, validate and default the request to ensure a valid and fully-populated object
+	// (to minimize any possible diffs between desired and actual state)
+	if err := gitprovider.ValidateAndDefaultInfo(&req); err != nil {
+		return nil, false, err
+	}
+
+	// Get the release of the desired tag
+	actual, err := c.Get(ctx, req.TagName)
+	if err != nil {
+		// Create if not found
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			resp, err := c.Create(ctx, req)
+			return resp, true, err
+		}
+
+		// Unexpected path, Get should succeed or return NotFound
+		return nil, false, err
+	}
+
+	// If the desired matches the actual state, just return the actual state
+	if req.Equals(actual.Get()) {
+		return actual, false, nil
+	}
+
+	// Apply the desired state by running Update
+	resp, err := c.Update(ctx, req)
+	return resp, true, err
+}
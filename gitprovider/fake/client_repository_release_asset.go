@@ -0,0 +1,92 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+	"mime"
+	"path"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// defaultAssetContentType is the media type of release assets with an unknown file extension.
+const defaultAssetContentType = "application/octet-stream"
+
+// ReleaseAssetClient implements the gitprovider.ReleaseAssetClient interface.
+var _ gitprovider.ReleaseAssetClient = &ReleaseAssetClient{}
+
+// ReleaseAssetClient operates on the assets of a specific release.
+type ReleaseAssetClient struct {
+	*clientContext
+	ref     gitprovider.RepositoryRef
+	tagName string
+}
+
+// List lists all assets of the release, in the order they were uploaded.
+//
+// ErrNotFound is returned if the release does not exist.
+func (c *ReleaseAssetClient) List(_ context.Context) ([]gitprovider.ReleaseAsset, error) {
+	apiObjs, err := c.s.listReleaseAssets(c.ref, c.tagName)
+	if err != nil {
+		return nil, err
+	}
+
+	assets := make([]gitprovider.ReleaseAsset, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		assets = append(assets, newReleaseAsset(apiObj))
+	}
+	return assets, nil
+}
+
+// Upload attaches a file with the given name and content to the release. Like on GitHub, the
+// media type of the asset is derived from the extension of name.
+//
+// ErrAlreadyExists is returned if the release already has an asset with the given name.
+func (c *ReleaseAssetClient) Upload(_ context.Context, name string, content []byte) (gitprovider.ReleaseAsset, error) {
+	contentType := mime.TypeByExtension(path.Ext(name))
+	if len(contentType) == 0 {
+		contentType = defaultAssetContentType
+	}
+	apiObj, err := c.s.createReleaseAsset(c.ref, c.tagName, &ReleaseAsset{
+		Name:        name,
+		ContentType: contentType,
+		Content:     content,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return newReleaseAsset(apiObj), nil
+}
+
+// Download returns the content of the asset with the given name.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *ReleaseAssetClient) Download(_ context.Context, name string) ([]byte, error) {
+	apiObj, err := c.s.getReleaseAsset(c.ref, c.tagName, name)
+	if err != nil {
+		return nil, err
+	}
+	return apiObj.Content, nil
+}
+
+// Delete removes the asset with the given name from the release.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *ReleaseAssetClient) Delete(_ context.Context, name string) error {
+	return c.s.deleteReleaseAsset(c.ref, c.tagName, name)
+}
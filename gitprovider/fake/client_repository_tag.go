@@ -0,0 +1,85 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// TagClient implements the gitprovider.TagClient interface.
+var _ gitprovider.TagClient = &TagClient{}
+
+// TagClient operates on the tags for a specific repository.
+type TagClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// Get returns the tag with the given name.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *TagClient) Get(_ context.Context, name string) (gitprovider.Tag, error) {
+	apiObj, err := c.s.getTag(c.ref, name)
+	if err != nil {
+		return nil, err
+	}
+	return newTag(apiObj), nil
+}
+
+// List lists all tags of the repository, sorted by name.
+func (c *TagClient) List(_ context.Context) ([]gitprovider.Tag, error) {
+	apiObjs, err := c.s.listTags(c.ref)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]gitprovider.Tag, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		tags = append(tags, newTag(apiObj))
+	}
+	return tags, nil
+}
+
+// Create creates a tag pointing to the commit req.Sha. The tag is an annotated tag if
+// req.Message is set, and a lightweight tag otherwise.
+//
+// ErrNotFound is returned if the commit does not exist.
+// ErrAlreadyExists is returned if the tag already exists.
+func (c *TagClient) Create(_ context.Context, req gitprovider.TagInfo) (gitprovider.Tag, error) {
+	if err := req.ValidateInfo(); err != nil {
+		return nil, err
+	}
+	apiObj, err := c.s.createTag(c.ref, &Tag{Name: req.Name, SHA: req.Sha, Message: req.Message})
+	if err != nil {
+		return nil, err
+	}
+	return newTag(apiObj), nil
+}
+
+// Delete deletes the tag with the given name. Like on GitHub, the release of the tag, if any,
+// is kept as a draft.
+//
+// ErrDestructiveCallDisallowed is returned if the client wasn't set up with destructive
+// API calls enabled. ErrNotFound is returned if the resource does not exist.
+func (c *TagClient) Delete(_ context.Context, name string) error {
+	if err := c.allowDestructiveCall(); err != nil {
+		return err
+	}
+	return c.s.deleteTag(c.ref, name)
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// TeamAccessClient implements the gitprovider.TeamAccessClient interface.
+var _ gitprovider.TeamAccessClient = &TeamAccessClient{}
+
+// TeamAccessClient operates on the teams list for a specific repository.
+type TeamAccessClient struct {
+	*clientContext
+	ref gitprovider.OrgRepositoryRef
+}
+
+// Get a team's permission level of this given repository.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *TeamAccessClient) Get(_ context.Context, name string) (gitprovider.TeamAccess, error) {
+	apiObjs, err := c.s.listTeamAccess(c.ref)
+	if err != nil {
+		return nil, err
+	}
+	for _, apiObj := range apiObjs {
+		if apiObj.Name == name {
+			return newTeamAccess(c, apiObj), nil
+		}
+	}
+	return nil, fmt.Errorf("team access %q: %w", name, gitprovider.ErrNotFound)
+}
+
+// List lists the team access control list for this repository.
+func (c *TeamAccessClient) List(_ context.Context) ([]gitprovider.TeamAccess, error) {
+	apiObjs, err := c.s.listTeamAccess(c.ref)
+	if err != nil {
+		return nil, err
+	}
+
+	teamAccess := make([]gitprovider.TeamAccess, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		teamAccess = append(teamAccess, newTeamAccess(c, apiObj))
+	}
+	return teamAccess, nil
+}
+
+// Create adds a given team to the repo's team access control list.
+//
+// ErrNotFound is returned if the team doesn't exist in the organization owning the repository.
+// ErrAlreadyExists will be returned if the resource already exists.
+func (c *TeamAccessClient) Create(_ context.Context, req gitprovider.TeamAccessInfo) (gitprovider.TeamAccess, error) {
+	// First thing, validate and default the request to ensure a valid and fully-populated object
+	// (to minimize any possible diffs between desired and actual state)
+	if err := gitprovider.ValidateAndDefaultInfo(&req); err != nil {
+		return nil, err
+	}
+
+	apiObj, err := c.s.setTeamAccess(c.ref, teamAccessToAPI(&req), true)
+	if err != nil {
+		return nil, err
+	}
+	return newTeamAccess(c, apiObj), nil
+}
+
+// Reconcile makes sure the given desired state (req) becomes the actual state in the backing Git provider.
+//
+// If req doesn't exist under the hood, it is created (actionTaken == true).
+// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+// If req is already the actual state, this is a no-op (actionTaken == false).
+func (c *TeamAccessClient) Reconcile(ctx context.Context,
+	req gitprovider.TeamAccessInfo,
+) (gitprovider.TeamAccess, bool, error) {
+	// First thing, validate and default the request to ensure a valid and fully-populated object
+	// (to minimize any possible diffs between desired and actual state)
+	if err := gitprovider.ValidateAndDefaultInfo(&req); err != nil {
+		return nil, false, err
+	}
+
+	actual, err := c.Get(ctx, req.Name)
+	if err != nil {
+		// Create if not found
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			resp, err := c.Create(ctx, req)
+			return resp, true, err
+		}
+
+		// Unexpected path, Get should succeed or return NotFound
+		return nil, false, err
+	}
+
+	// If the desired matches the actual state, just return the actual state
+	if req.Equals(actual.Get()) {
+		return actual, false, nil
+	}
+
+	// Populate the desired state to the current-actual object
+	if err := actual.Set(req); err != nil {
+		return actual, false, err
+	}
+	return actual, true, actual.Update(ctx)
+}
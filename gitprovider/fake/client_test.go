@@ -0,0 +1,1436 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+func newTestClient(t *testing.T, opts ...ClientOption) (*Client, gitprovider.OrganizationRef) {
+	t.Helper()
+	c, err := NewClient(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	orgRef := gitprovider.OrganizationRef{Domain: c.SupportedDomain(), Organization: "org"}
+	if err := c.AddOrganization(orgRef, gitprovider.OrganizationInfo{Name: gitprovider.StringVar("Org")}); err != nil {
+		t.Fatal(err)
+	}
+	return c, orgRef
+}
+
+func TestNewClient(t *testing.T) {
+	c, err := NewClient(WithDomain("github.com"), WithTokenPermissions())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.SupportedDomain() != "github.com" || c.ProviderID() != ProviderID || c.Raw() != c {
+		t.Errorf("unexpected client %v", c)
+	}
+	if ok, err := c.HasTokenPermission(context.Background(), gitprovider.TokenPermissionRWRepository); ok || err != nil {
+		t.Errorf("HasTokenPermission() = %v, %v, want false", ok, err)
+	}
+
+	c, err = NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := c.HasTokenPermission(context.Background(), gitprovider.TokenPermissionRWRepository); !ok || err != nil {
+		t.Errorf("HasTokenPermission() = %v, %v, want true", ok, err)
+	}
+
+	if _, err := c.Quota(context.Background()); !errors.Is(err, gitprovider.ErrNoProviderSupport) {
+		t.Errorf("Quota() error = %v, want %v", err, gitprovider.ErrNoProviderSupport)
+	}
+	quota := gitprovider.Quota{Limit: 5000, Remaining: 10, Reset: time.Unix(1600000000, 0)}
+	c.SetQuota(quota)
+	if got, err := c.Quota(context.Background()); got != quota || err != nil {
+		t.Errorf("Quota() = %+v, %v, want %+v", got, err, quota)
+	}
+
+	_, err = NewClient(WithDomain("a"), WithDomain("b"))
+	validation.TestExpectErrors(t, "NewClient", err, gitprovider.ErrInvalidClientOptions)
+	_, err = NewClient(WithDomain(""))
+	validation.TestExpectErrors(t, "NewClient", err, gitprovider.ErrInvalidClientOptions)
+}
+
+func TestOrganizations(t *testing.T) {
+	c, orgRef := newTestClient(t)
+	ctx := context.Background()
+
+	subRef := orgRef
+	subRef.SubOrganizations = []string{"sub"}
+	if err := c.AddOrganization(subRef, gitprovider.OrganizationInfo{}); err != nil {
+		t.Fatal(err)
+	}
+	nestedRef := orgRef
+	nestedRef.SubOrganizations = []string{"missing", "nested"}
+	err := c.AddOrganization(nestedRef, gitprovider.OrganizationInfo{})
+	validation.TestExpectErrors(t, "AddOrganization", err, gitprovider.ErrNotFound)
+	err = c.AddOrganization(orgRef, gitprovider.OrganizationInfo{})
+	validation.TestExpectErrors(t, "AddOrganization", err, gitprovider.ErrAlreadyExists)
+	err = c.AddOrganization(gitprovider.OrganizationRef{Domain: "other.com", Organization: "org"}, gitprovider.OrganizationInfo{})
+	validation.TestExpectErrors(t, "AddOrganization", err, gitprovider.ErrDomainUnsupported)
+
+	org, err := c.Organizations().Get(ctx, orgRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info := org.Get(); *info.Name != "Org" || info.Description != nil {
+		t.Errorf("Organization.Get() = %v", info)
+	}
+	_, err = c.Organizations().Get(ctx, gitprovider.OrganizationRef{Domain: c.domain, Organization: "missing"})
+	validation.TestExpectErrors(t, "Organizations().Get", err, gitprovider.ErrNotFound)
+
+	orgs, err := c.Organizations().List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orgs) != 1 || orgs[0].Organization().String() != orgRef.String() {
+		t.Errorf("Organizations().List() = %v, want [%s]", orgs, orgRef)
+	}
+	children, err := c.Organizations().Children(ctx, orgRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(children) != 1 || children[0].Organization().String() != subRef.String() {
+		t.Errorf("Organizations().Children() = %v, want [%s]", children, subRef)
+	}
+
+	if err := c.AddTeam(orgRef, gitprovider.TeamInfo{Name: "team", Members: []string{"alice"}}); err != nil {
+		t.Fatal(err)
+	}
+	err = c.AddTeam(orgRef, gitprovider.TeamInfo{Name: "team"})
+	validation.TestExpectErrors(t, "AddTeam", err, gitprovider.ErrAlreadyExists)
+	team, err := org.Teams().Get(ctx, "team")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info := team.Get(); info.Name != "team" || len(info.Members) != 1 {
+		t.Errorf("Team.Get() = %v", info)
+	}
+	_, err = org.Teams().Get(ctx, "missing")
+	validation.TestExpectErrors(t, "Teams().Get", err, gitprovider.ErrNotFound)
+	teams, err := org.Teams().List(ctx)
+	if err != nil || len(teams) != 1 {
+		t.Errorf("Teams().List() = %v, %v", teams, err)
+	}
+}
+
+func TestOrgRepositories(t *testing.T) {
+	c, orgRef := newTestClient(t)
+	ctx := context.Background()
+	ref := gitprovider.OrgRepositoryRef{OrganizationRef: orgRef, RepositoryName: "repo"}
+
+	_, err := c.OrgRepositories().Get(ctx, ref)
+	validation.TestExpectErrors(t, "OrgRepositories().Get", err, gitprovider.ErrNotFound)
+	_, err = c.OrgRepositories().Create(ctx, gitprovider.OrgRepositoryRef{
+		OrganizationRef: gitprovider.OrganizationRef{Domain: c.domain, Organization: "missing"},
+		RepositoryName:  "repo",
+	}, gitprovider.RepositoryInfo{})
+	validation.TestExpectErrors(t, "OrgRepositories().Create", err, gitprovider.ErrNotFound)
+
+	req := gitprovider.RepositoryInfo{Description: gitprovider.StringVar("desc")}
+	repo, actionTaken, err := c.OrgRepositories().Reconcile(ctx, ref, req, &gitprovider.RepositoryCreateOptions{
+		AutoInit: gitprovider.BoolVar(true),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !actionTaken {
+		t.Error("expected Reconcile to create the repository")
+	}
+	if info := repo.Get(); *info.DefaultBranch != "master" || *info.Visibility != gitprovider.RepositoryVisibilityPrivate {
+		t.Errorf("expected the repository to be defaulted, got %v", info)
+	}
+
+	_, err = c.OrgRepositories().Create(ctx, ref, req)
+	validation.TestExpectErrors(t, "OrgRepositories().Create", err, gitprovider.ErrAlreadyExists)
+
+	_, actionTaken, err = c.OrgRepositories().Reconcile(ctx, ref, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actionTaken {
+		t.Error("expected Reconcile to be a no-op")
+	}
+
+	// Changes are applied through Reconcile, or Set and Update
+	req.Visibility = gitprovider.RepositoryVisibilityVar(gitprovider.RepositoryVisibilityPublic)
+	_, actionTaken, err = c.OrgRepositories().Reconcile(ctx, ref, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !actionTaken {
+		t.Error("expected Reconcile to update the repository")
+	}
+	if err := repo.Set(gitprovider.RepositoryInfo{DefaultBranch: gitprovider.StringVar("missing")}); err != nil {
+		t.Fatal(err)
+	}
+	err = repo.Update(ctx)
+	validation.TestExpectErrors(t, "Repository.Update", err, gitprovider.ErrNotFound)
+
+	actual, err := c.OrgRepositories().Get(ctx, ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info := actual.Get(); *info.Visibility != gitprovider.RepositoryVisibilityPublic || *info.Description != "desc" {
+		t.Errorf("unexpected repository info %v", info)
+	}
+	// Modifying the API object doesn't change the state
+	actual.APIObject().(*Repository).Visibility = gitprovider.RepositoryVisibilityInternal
+	if actionTaken, err := actual.Reconcile(ctx); err != nil || !actionTaken {
+		t.Errorf("Repository.Reconcile() = %v, %v, want an update", actionTaken, err)
+	}
+
+	repos, err := c.OrgRepositories().List(ctx, orgRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repos) != 1 || repos[0].Repository().String() != ref.String() {
+		t.Errorf("OrgRepositories().List() = %v", repos)
+	}
+
+	err = repo.Delete(ctx)
+	validation.TestExpectErrors(t, "Repository.Delete", err, gitprovider.ErrDestructiveCallDisallowed)
+}
+
+func TestUserRepositories(t *testing.T) {
+	c, err := NewClient(WithDestructiveAPICalls(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	userRef := gitprovider.UserRef{Domain: c.domain, UserLogin: "user"}
+	ref := gitprovider.UserRepositoryRef{UserRef: userRef, RepositoryName: "repo"}
+
+	repo, err := c.UserRepositories().Create(ctx, ref, gitprovider.RepositoryInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	repos, err := c.UserRepositories().List(ctx, userRef)
+	if err != nil || len(repos) != 1 {
+		t.Errorf("UserRepositories().List() = %v, %v", repos, err)
+	}
+
+	if err := repo.Delete(ctx); err != nil {
+		t.Fatal(err)
+	}
+	err = repo.Delete(ctx)
+	validation.TestExpectErrors(t, "Repository.Delete", err, gitprovider.ErrNotFound)
+
+	// Reconciling the resource recreates it
+	if actionTaken, err := repo.Reconcile(ctx); err != nil || !actionTaken {
+		t.Errorf("Repository.Reconcile() = %v, %v, want the repository to be created", actionTaken, err)
+	}
+	if _, err := c.UserRepositories().Get(ctx, ref); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDeployKeysAndTeamAccess(t *testing.T) {
+	c, orgRef := newTestClient(t)
+	ctx := context.Background()
+	if err := c.AddTeam(orgRef, gitprovider.TeamInfo{Name: "team"}); err != nil {
+		t.Fatal(err)
+	}
+	repo, err := c.OrgRepositories().Create(ctx, gitprovider.OrgRepositoryRef{OrganizationRef: orgRef, RepositoryName: "repo"}, gitprovider.RepositoryInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyReq := gitprovider.DeployKeyInfo{Name: "key", Key: []byte("ssh-ed25519 AAAA")}
+	_, actionTaken, err := repo.DeployKeys().Reconcile(ctx, keyReq)
+	if err != nil || !actionTaken {
+		t.Fatalf("DeployKeys().Reconcile() = %v, %v, want the key to be created", actionTaken, err)
+	}
+	_, err = repo.DeployKeys().Create(ctx, keyReq)
+	validation.TestExpectErrors(t, "DeployKeys().Create", err, gitprovider.ErrAlreadyExists)
+	key, actionTaken, err := repo.DeployKeys().Reconcile(ctx, keyReq)
+	if err != nil || actionTaken {
+		t.Fatalf("DeployKeys().Reconcile() = %v, %v, want a no-op", actionTaken, err)
+	}
+	if !*key.Get().ReadOnly {
+		t.Error("expected the key to be read-only by default")
+	}
+	keyReq.ReadOnly = gitprovider.BoolVar(false)
+	if _, actionTaken, err := repo.DeployKeys().Reconcile(ctx, keyReq); err != nil || !actionTaken {
+		t.Fatalf("DeployKeys().Reconcile() = %v, %v, want an update", actionTaken, err)
+	}
+	keys, err := repo.DeployKeys().List(ctx)
+	if err != nil || len(keys) != 1 || *keys[0].Get().ReadOnly {
+		t.Fatalf("DeployKeys().List() = %v, %v", keys, err)
+	}
+	if err := keys[0].Delete(ctx); err != nil {
+		t.Fatal(err)
+	}
+	_, err = repo.DeployKeys().Get(ctx, "key")
+	validation.TestExpectErrors(t, "DeployKeys().Get", err, gitprovider.ErrNotFound)
+
+	orgRepo := repo.(gitprovider.OrgRepository)
+	_, err = orgRepo.TeamAccess().Create(ctx, gitprovider.TeamAccessInfo{Name: "missing"})
+	validation.TestExpectErrors(t, "TeamAccess().Create", err, gitprovider.ErrNotFound)
+	accessReq := gitprovider.TeamAccessInfo{Name: "team"}
+	if _, actionTaken, err := orgRepo.TeamAccess().Reconcile(ctx, accessReq); err != nil || !actionTaken {
+		t.Fatalf("TeamAccess().Reconcile() = %v, %v, want the access to be created", actionTaken, err)
+	}
+	if _, actionTaken, err := orgRepo.TeamAccess().Reconcile(ctx, accessReq); err != nil || actionTaken {
+		t.Fatalf("TeamAccess().Reconcile() = %v, %v, want a no-op", actionTaken, err)
+	}
+	accessReq.Permission = gitprovider.RepositoryPermissionVar(gitprovider.RepositoryPermissionAdmin)
+	access, actionTaken, err := orgRepo.TeamAccess().Reconcile(ctx, accessReq)
+	if err != nil || !actionTaken || *access.Get().Permission != gitprovider.RepositoryPermissionAdmin {
+		t.Fatalf("TeamAccess().Reconcile() = %v, %v, want an update", actionTaken, err)
+	}
+	if err := access.Delete(ctx); err != nil {
+		t.Fatal(err)
+	}
+	list, err := orgRepo.TeamAccess().List(ctx)
+	if err != nil || len(list) != 0 {
+		t.Errorf("TeamAccess().List() = %v, %v, want none", list, err)
+	}
+}
+
+func TestCommitsBranchesPullRequests(t *testing.T) {
+	c, orgRef := newTestClient(t)
+	ctx := context.Background()
+	repo, err := c.OrgRepositories().Create(ctx, gitprovider.OrgRepositoryRef{OrganizationRef: orgRef, RepositoryName: "repo"},
+		gitprovider.RepositoryInfo{DefaultBranch: gitprovider.StringVar("main")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = repo.Commits().Create(ctx, "main", "empty", nil)
+	validation.TestExpectErrors(t, "Commits().Create", err, gitprovider.ErrInvalidArgument)
+
+	// The first commit in an empty repository creates the branch
+	first, err := repo.Commits().Create(ctx, "main", "first", []gitprovider.CommitFile{
+		{Path: gitprovider.StringVar("a.txt"), Content: gitprovider.StringVar("a")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = repo.Commits().Create(ctx, "other", "on a missing branch", []gitprovider.CommitFile{
+		{Path: gitprovider.StringVar("a.txt"), Content: gitprovider.StringVar("b")},
+	})
+	validation.TestExpectErrors(t, "Commits().Create", err, gitprovider.ErrNotFound)
+
+	second, err := repo.Commits().Create(ctx, "main", "second", []gitprovider.CommitFile{
+		{Path: gitprovider.StringVar("a.txt")},
+		{Path: gitprovider.StringVar("b.txt"), Content: gitprovider.StringVar("b")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	apiObj := second.APIObject().(*Commit)
+	if apiObj.ParentSHA != first.Get().Sha || len(apiObj.Files) != 1 || apiObj.Files["b.txt"] != "b" {
+		t.Errorf("unexpected commit %+v", apiObj)
+	}
+
+	commits, err := repo.Commits().ListPage(ctx, "main", 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 1 || commits[0].Get().Sha != first.Get().Sha {
+		t.Errorf("Commits().ListPage() = %v, want [%s]", commits, first.Get().Sha)
+	}
+	_, err = repo.Commits().ListPage(ctx, "missing", 1, 1)
+	validation.TestExpectErrors(t, "Commits().ListPage", err, gitprovider.ErrNotFound)
+
+	if err := repo.Branches().Create(ctx, "feature", first.Get().Sha); err != nil {
+		t.Fatal(err)
+	}
+	err = repo.Branches().Create(ctx, "feature", first.Get().Sha)
+	validation.TestExpectErrors(t, "Branches().Create", err, gitprovider.ErrAlreadyExists)
+	err = repo.Branches().Create(ctx, "other", "0000")
+	validation.TestExpectErrors(t, "Branches().Create", err, gitprovider.ErrNotFound)
+
+	pr, err := repo.PullRequests().Create(ctx, "title", "feature", "main", "description")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := pr.Get().WebURL, "https://fake.example.com/org/repo/pull/1"; got != want {
+		t.Errorf("PullRequest.Get().WebURL = %q, want %q", got, want)
+	}
+	_, err = repo.PullRequests().Create(ctx, "title", "missing", "main", "")
+	if !errors.Is(err, gitprovider.ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestCommitFileActions(t *testing.T) {
+	c, orgRef := newTestClient(t)
+	ctx := context.Background()
+	repoRef := gitprovider.OrgRepositoryRef{OrganizationRef: orgRef, RepositoryName: "repo"}
+	repo, err := c.OrgRepositories().Create(ctx, repoRef, gitprovider.RepositoryInfo{DefaultBranch: gitprovider.StringVar("main")},
+		&gitprovider.RepositoryCreateOptions{AutoInit: gitprovider.BoolVar(true)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		file         gitprovider.CommitFile
+		expectedErrs []error
+	}{
+		{
+			name:         "create an existing file",
+			file:         gitprovider.CommitFile{Action: gitprovider.CommitFileActionVar(gitprovider.CommitFileActionCreate), Path: gitprovider.StringVar("README.md"), Content: gitprovider.StringVar("")},
+			expectedErrs: []error{gitprovider.ErrAlreadyExists},
+		},
+		{
+			name:         "update a missing file",
+			file:         gitprovider.CommitFile{Action: gitprovider.CommitFileActionVar(gitprovider.CommitFileActionUpdate), Path: gitprovider.StringVar("missing"), Content: gitprovider.StringVar("")},
+			expectedErrs: []error{gitprovider.ErrNotFound},
+		},
+		{
+			name:         "move a missing file",
+			file:         gitprovider.CommitFile{Action: gitprovider.CommitFileActionVar(gitprovider.CommitFileActionMove), Path: gitprovider.StringVar("to"), PreviousPath: gitprovider.StringVar("missing")},
+			expectedErrs: []error{gitprovider.ErrNotFound},
+		},
+		{
+			name:         "move without a previous path",
+			file:         gitprovider.CommitFile{Action: gitprovider.CommitFileActionVar(gitprovider.CommitFileActionMove), Path: gitprovider.StringVar("to")},
+			expectedErrs: []error{validation.ErrFieldRequired},
+		},
+		{
+			name:         "invalid base64 content",
+			file:         gitprovider.CommitFile{Path: gitprovider.StringVar("bin"), Content: gitprovider.StringVar("!"), Encoding: gitprovider.ContentEncodingVar(gitprovider.ContentEncodingBase64)},
+			expectedErrs: []error{validation.ErrFieldInvalid},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := repo.Commits().Create(ctx, "main", tt.name, []gitprovider.CommitFile{tt.file})
+			validation.TestExpectErrors(t, "Commits().Create", err, tt.expectedErrs...)
+		})
+	}
+
+	// A single commit can create, update, move and delete files
+	commit, err := repo.Commits().Create(ctx, "main", "change files", []gitprovider.CommitFile{
+		{
+			Path:     gitprovider.StringVar("bin/tool"),
+			Content:  gitprovider.StringVar("AAEC"),
+			Encoding: gitprovider.ContentEncodingVar(gitprovider.ContentEncodingBase64),
+			Mode:     gitprovider.FileModeVar(gitprovider.FileModeExecutable),
+		},
+		{
+			Action:       gitprovider.CommitFileActionVar(gitprovider.CommitFileActionMove),
+			Path:         gitprovider.StringVar("docs/README.md"),
+			PreviousPath: gitprovider.StringVar("README.md"),
+		},
+		{
+			Path:    gitprovider.StringVar("docs/README.md"),
+			Content: gitprovider.StringVar("# moved"),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	apiObj := commit.APIObject().(*Commit)
+	if len(apiObj.Files) != 2 || apiObj.Files["bin/tool"] != "\x00\x01\x02" || apiObj.Files["docs/README.md"] != "# moved" {
+		t.Errorf("unexpected files %v", apiObj.Files)
+	}
+	if apiObj.Modes["bin/tool"] != gitprovider.FileModeExecutable {
+		t.Errorf("unexpected modes %v", apiObj.Modes)
+	}
+
+	// Changing only the mode changes the tree
+	chmod, err := repo.Commits().Create(ctx, "main", "chmod", []gitprovider.CommitFile{
+		{
+			Action:   gitprovider.CommitFileActionVar(gitprovider.CommitFileActionUpdate),
+			Path:     gitprovider.StringVar("bin/tool"),
+			Content:  gitprovider.StringVar("AAEC"),
+			Encoding: gitprovider.ContentEncodingVar(gitprovider.ContentEncodingBase64),
+			Mode:     gitprovider.FileModeVar(gitprovider.FileModeRegular),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if apiObj := chmod.APIObject().(*Commit); len(apiObj.Modes) != 0 || len(apiObj.Files) != 2 || apiObj.TreeSHA == commit.Get().TreeSha {
+		t.Errorf("unexpected commit %+v", apiObj)
+	}
+}
+
+func TestCommitExpectedParent(t *testing.T) {
+	c, orgRef := newTestClient(t)
+	ctx := context.Background()
+	repoRef := gitprovider.OrgRepositoryRef{OrganizationRef: orgRef, RepositoryName: "repo"}
+	repo, err := c.OrgRepositories().Create(ctx, repoRef, gitprovider.RepositoryInfo{DefaultBranch: gitprovider.StringVar("main")},
+		&gitprovider.RepositoryCreateOptions{AutoInit: gitprovider.BoolVar(true)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	commits, err := repo.Commits().ListPage(ctx, "main", 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	head := commits[0].Get().Sha
+	files := []gitprovider.CommitFile{{Path: gitprovider.StringVar("foo.txt"), Content: gitprovider.StringVar("foo")}}
+
+	commit, err := repo.Commits().Create(ctx, "main", "expected", files, &gitprovider.CommitCreateOptions{ExpectedParentSHA: &head})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parent := commit.APIObject().(*Commit).ParentSHA; parent != head {
+		t.Errorf("Commits().Create() has parent %q, want %q", parent, head)
+	}
+	// The branch moved since head was read
+	_, err = repo.Commits().Create(ctx, "main", "stale", files, &gitprovider.CommitCreateOptions{ExpectedParentSHA: &head})
+	validation.TestExpectErrors(t, "Commits().Create() on a moved branch", err, gitprovider.ErrConflict)
+	_, err = repo.Commits().Create(ctx, "main", "empty", files, &gitprovider.CommitCreateOptions{ExpectedParentSHA: gitprovider.StringVar("")})
+	validation.TestExpectErrors(t, "Commits().Create() with an empty expected parent", err, validation.ErrFieldInvalid)
+}
+
+func TestCommitStartBranch(t *testing.T) {
+	c, orgRef := newTestClient(t)
+	ctx := context.Background()
+	repoRef := gitprovider.OrgRepositoryRef{OrganizationRef: orgRef, RepositoryName: "repo"}
+	repo, err := c.OrgRepositories().Create(ctx, repoRef, gitprovider.RepositoryInfo{DefaultBranch: gitprovider.StringVar("main")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := []gitprovider.CommitFile{{Path: gitprovider.StringVar("foo.txt"), Content: gitprovider.StringVar("foo")}}
+
+	// The first commit of an empty repository has no parent
+	first, err := repo.Commits().Create(ctx, "main", "first", files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parent := first.APIObject().(*Commit).ParentSHA; len(parent) != 0 {
+		t.Errorf("first commit has parent %q", parent)
+	}
+
+	_, err = repo.Commits().Create(ctx, "feature", "no start", files)
+	validation.TestExpectErrors(t, "Commits().Create() on a missing branch", err, gitprovider.ErrNotFound)
+	_, err = repo.Commits().Create(ctx, "feature", "missing start", files, &gitprovider.CommitCreateOptions{StartBranch: gitprovider.StringVar("missing")})
+	validation.TestExpectErrors(t, "Commits().Create() from a missing branch", err, gitprovider.ErrNotFound)
+
+	// The expected parent of a new branch is the head of the start branch
+	start := &gitprovider.CommitCreateOptions{StartBranch: gitprovider.StringVar("main"), ExpectedParentSHA: gitprovider.StringVar(first.Get().Sha)}
+	commit, err := repo.Commits().Create(ctx, "feature", "start", files, start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parent := commit.APIObject().(*Commit).ParentSHA; parent != first.Get().Sha {
+		t.Errorf("Commits().Create() has parent %q, want %q", parent, first.Get().Sha)
+	}
+	commits, err := repo.Commits().ListPage(ctx, "feature", 1, 1)
+	if err != nil || commits[0].Get().Sha != commit.Get().Sha {
+		t.Errorf("Commits().ListPage() = %v, %v, want the new commit", commits, err)
+	}
+	// The start branch is ignored once the branch exists, so its head isn't the parent anymore
+	_, err = repo.Commits().Create(ctx, "feature", "existing", files, start)
+	validation.TestExpectErrors(t, "Commits().Create() on an existing branch", err, gitprovider.ErrConflict)
+}
+
+func TestCommitAuthor(t *testing.T) {
+	c, orgRef := newTestClient(t)
+	ctx := context.Background()
+	repoRef := gitprovider.OrgRepositoryRef{OrganizationRef: orgRef, RepositoryName: "repo"}
+	repo, err := c.OrgRepositories().Create(ctx, repoRef, gitprovider.RepositoryInfo{DefaultBranch: gitprovider.StringVar("main")},
+		&gitprovider.RepositoryCreateOptions{AutoInit: gitprovider.BoolVar(true)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := []gitprovider.CommitFile{{Path: gitprovider.StringVar("foo.txt"), Content: gitprovider.StringVar("foo")}}
+
+	_, err = repo.Commits().Create(ctx, "main", "no email", files, &gitprovider.CommitCreateOptions{
+		Author: &gitprovider.CommitAuthor{Name: "Jane"},
+	})
+	validation.TestExpectErrors(t, "Commits().Create() with an author without email", err, validation.ErrFieldRequired)
+
+	// The author keeps its date, while the committer is dated at the time of the commit
+	author := &gitprovider.CommitAuthor{Name: "Jane", Email: "jane@example.com", Date: time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)}
+	before := time.Now().UTC()
+	_, err = repo.Commits().Create(ctx, "main", "authored", files, &gitprovider.CommitCreateOptions{
+		Author:    author,
+		Committer: &gitprovider.CommitAuthor{Name: "Bot", Email: "bot@example.com"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	commits, err := repo.Commits().ListPage(ctx, "main", 2, 1)
+	if err != nil || len(commits) != 2 {
+		t.Fatalf("Commits().ListPage() = %v, %v, want 2 commits", commits, err)
+	}
+	got := commits[0].Get()
+	if got.Message != "authored" || !reflect.DeepEqual(got.Author, author) || !reflect.DeepEqual(got.ParentShas, []string{commits[1].Get().Sha}) {
+		t.Errorf("Commits().ListPage()[0] = %+v, want message, author and parent", got)
+	}
+	if got.Committer == nil || got.Committer.Name != "Bot" || got.Committer.Date.Before(before) {
+		t.Errorf("Commits().ListPage()[0].Committer = %+v, want Bot dated now", got.Committer)
+	}
+	if want := repoRef.String() + "/commit/" + got.Sha; got.WebURL != want {
+		t.Errorf("Commits().ListPage()[0].WebURL = %q, want %q", got.WebURL, want)
+	}
+	// Commits created without identities have none
+	if got := commits[1].Get(); got.Author != nil || got.Committer != nil || len(got.ParentShas) != 0 {
+		t.Errorf("Commits().ListPage()[1] = %+v, want no identities nor parents", got)
+	}
+}
+
+func TestFiles(t *testing.T) {
+	c, orgRef := newTestClient(t)
+	ctx := context.Background()
+	repoRef := gitprovider.OrgRepositoryRef{OrganizationRef: orgRef, RepositoryName: "repo"}
+	repo, err := c.OrgRepositories().Create(ctx, repoRef, gitprovider.RepositoryInfo{DefaultBranch: gitprovider.StringVar("main")},
+		&gitprovider.RepositoryCreateOptions{AutoInit: gitprovider.BoolVar(true)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	initial, err := repo.Commits().ListPage(ctx, "main", 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.Commits().Create(ctx, "main", "add files", []gitprovider.CommitFile{
+		{
+			Path:    gitprovider.StringVar("bin/tool"),
+			Content: gitprovider.StringVar("#!/bin/sh"),
+			Mode:    gitprovider.FileModeVar(gitprovider.FileModeExecutable),
+		},
+		{
+			Path:    gitprovider.StringVar("bin/lib/util.sh"),
+			Content: gitprovider.StringVar("util"),
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := repo.Files().Get(ctx, "bin/tool", "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := file.Get()
+	if info.Path != "bin/tool" || info.Mode != gitprovider.FileModeExecutable || string(info.Content) != "#!/bin/sh" || info.Size != 9 || len(info.Sha) == 0 {
+		t.Errorf("Files().Get() = %+v", info)
+	}
+	// Files can be read as of older commits
+	_, err = repo.Files().Get(ctx, "bin/tool", initial[0].Get().Sha)
+	validation.TestExpectErrors(t, "Files().Get() of an older commit", err, gitprovider.ErrNotFound)
+	_, err = repo.Files().Get(ctx, "bin", "main")
+	validation.TestExpectErrors(t, "Files().Get() of a directory", err, gitprovider.ErrNotFound)
+	_, err = repo.Files().Get(ctx, "bin/tool", "missing")
+	validation.TestExpectErrors(t, "Files().Get() of a missing ref", err, gitprovider.ErrNotFound)
+
+	files, err := repo.Files().List(ctx, "bin", "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 2 || files[0].Get().Path != "bin/lib" || !files[0].Get().IsDir() ||
+		files[1].Get().Path != "bin/tool" || files[1].Get().Sha != info.Sha || files[1].Get().Content != nil {
+		t.Errorf("Files().List() = %+v", files)
+	}
+	files, err = repo.Files().List(ctx, "", "main")
+	if err != nil || len(files) != 2 || files[0].Get().Path != "README.md" || files[1].Get().Path != "bin" {
+		t.Errorf("Files().List() of the root directory = %v, %v", files, err)
+	}
+	_, err = repo.Files().List(ctx, "bin/tool", "main")
+	validation.TestExpectErrors(t, "Files().List() of a file", err, gitprovider.ErrNotFound)
+}
+
+func TestBranches(t *testing.T) {
+	c, orgRef := newTestClient(t)
+	ctx := context.Background()
+	repoRef := gitprovider.OrgRepositoryRef{OrganizationRef: orgRef, RepositoryName: "repo"}
+	repo, err := c.OrgRepositories().Create(ctx, repoRef, gitprovider.RepositoryInfo{DefaultBranch: gitprovider.StringVar("main")},
+		&gitprovider.RepositoryCreateOptions{AutoInit: gitprovider.BoolVar(true)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	main, err := repo.Branches().Get(ctx, "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if main.Get().Name != "main" || len(main.Get().Sha) == 0 {
+		t.Errorf("unexpected branch %+v", main.Get())
+	}
+	_, err = repo.Branches().Get(ctx, "missing")
+	validation.TestExpectErrors(t, "Branches().Get", err, gitprovider.ErrNotFound)
+
+	feature, err := repo.Branches().CreateFrom(ctx, "feature", "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (gitprovider.BranchInfo{Name: "feature", Sha: main.Get().Sha}); feature.Get() != want {
+		t.Errorf("Branches().CreateFrom() = %+v, want %+v", feature.Get(), want)
+	}
+	_, err = repo.Branches().CreateFrom(ctx, "feature", "main")
+	validation.TestExpectErrors(t, "Branches().CreateFrom", err, gitprovider.ErrAlreadyExists)
+	_, err = repo.Branches().CreateFrom(ctx, "other", "missing")
+	validation.TestExpectErrors(t, "Branches().CreateFrom", err, gitprovider.ErrNotFound)
+
+	branches, err := repo.Branches().List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(branches) != 2 || branches[0].Get().Name != "feature" || branches[1].Get().Name != "main" {
+		t.Errorf("Branches().List() = %v, want [feature main]", branches)
+	}
+
+	err = repo.Branches().Delete(ctx, "feature")
+	validation.TestExpectErrors(t, "Branches().Delete", err, gitprovider.ErrDestructiveCallDisallowed)
+
+	// Delete through a client sharing the state, with destructive actions allowed
+	dc, err := NewClient(WithSharedState(c), WithDestructiveAPICalls(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	drepo, err := dc.OrgRepositories().Get(ctx, repoRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := drepo.Branches().Delete(ctx, "feature"); err != nil {
+		t.Fatal(err)
+	}
+	err = drepo.Branches().Delete(ctx, "feature")
+	validation.TestExpectErrors(t, "Branches().Delete", err, gitprovider.ErrNotFound)
+	_, err = repo.Branches().Get(ctx, "feature")
+	validation.TestExpectErrors(t, "Branches().Get", err, gitprovider.ErrNotFound)
+}
+
+func TestBranchProtections(t *testing.T) {
+	c, orgRef := newTestClient(t)
+	ctx := context.Background()
+	repo, err := c.OrgRepositories().Create(ctx, gitprovider.OrgRepositoryRef{OrganizationRef: orgRef, RepositoryName: "repo"},
+		gitprovider.RepositoryInfo{DefaultBranch: gitprovider.StringVar("main")},
+		&gitprovider.RepositoryCreateOptions{AutoInit: gitprovider.BoolVar(true)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	protections := repo.BranchProtections()
+
+	_, err = protections.Get(ctx, "main")
+	validation.TestExpectErrors(t, "BranchProtections().Get", err, gitprovider.ErrNotFound)
+	_, _, err = protections.Reconcile(ctx, gitprovider.BranchProtectionInfo{Branch: "missing"})
+	validation.TestExpectErrors(t, "BranchProtections().Reconcile", err, gitprovider.ErrNotFound)
+
+	req := gitprovider.BranchProtectionInfo{
+		Branch:               "main",
+		RequiredApprovals:    gitprovider.IntVar(1),
+		RequiredStatusChecks: []string{"build", "test"},
+	}
+	bp, actionTaken, err := protections.Reconcile(ctx, req)
+	if err != nil || !actionTaken {
+		t.Fatalf("BranchProtections().Reconcile() = %v, %v, want a create", actionTaken, err)
+	}
+	if info := bp.Get(); *info.RequiredApprovals != 1 || *info.RestrictPushes {
+		t.Errorf("BranchProtection.Get() = %+v, want the defaulted request", info)
+	}
+	if main, err := repo.Branches().Get(ctx, "main"); err != nil || !main.Get().Protected {
+		t.Errorf("Branches().Get() of a protected branch = %v, %v, want it to be protected", main, err)
+	}
+
+	// The order of the status checks doesn't matter
+	req.RequiredStatusChecks = []string{"test", "build"}
+	if _, actionTaken, err := protections.Reconcile(ctx, req); err != nil || actionTaken {
+		t.Errorf("BranchProtections().Reconcile() of the actual state = %v, %v, want a no-op", actionTaken, err)
+	}
+
+	req.RestrictPushes = gitprovider.BoolVar(true)
+	if _, actionTaken, err := protections.Reconcile(ctx, req); err != nil || !actionTaken {
+		t.Errorf("BranchProtections().Reconcile() of an update = %v, %v, want an update", actionTaken, err)
+	}
+	bp, err = protections.Get(ctx, "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !*bp.Get().RestrictPushes {
+		t.Errorf("BranchProtection.Get() = %+v, want pushes to be restricted", bp.Get())
+	}
+
+	if err := bp.Delete(ctx); err != nil {
+		t.Fatal(err)
+	}
+	err = protections.Delete(ctx, "main")
+	validation.TestExpectErrors(t, "BranchProtections().Delete", err, gitprovider.ErrNotFound)
+	if main, err := repo.Branches().Get(ctx, "main"); err != nil || main.Get().Protected {
+		t.Errorf("Branches().Get() of an unprotected branch = %v, %v, want it to be unprotected", main, err)
+	}
+}
+
+func TestPullRequests(t *testing.T) {
+	c, orgRef := newTestClient(t)
+	ctx := context.Background()
+	repo, err := c.OrgRepositories().Create(ctx, gitprovider.OrgRepositoryRef{OrganizationRef: orgRef, RepositoryName: "repo"},
+		gitprovider.RepositoryInfo{DefaultBranch: gitprovider.StringVar("main")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := repo.Commits().Create(ctx, "main", "first", []gitprovider.CommitFile{
+		{Path: gitprovider.StringVar("a.txt"), Content: gitprovider.StringVar("a")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, branch := range []string{"feature", "other"} {
+		if err := repo.Branches().Create(ctx, branch, first.Get().Sha); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Diverge main and feature
+	if _, err := repo.Commits().Create(ctx, "main", "on main", []gitprovider.CommitFile{
+		{Path: gitprovider.StringVar("a.txt")},
+		{Path: gitprovider.StringVar("b.txt"), Content: gitprovider.StringVar("b")},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.Commits().Create(ctx, "feature", "on feature", []gitprovider.CommitFile{
+		{Path: gitprovider.StringVar("c.txt"), Content: gitprovider.StringVar("c")},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	pr, err := repo.PullRequests().Create(ctx, "title", "feature", "main", "description")
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := pr.Get()
+	if info.Number != 1 || info.State != gitprovider.PullRequestStateOpen || info.HeadBranch != "feature" ||
+		info.BaseBranch != "main" || info.Mergeable == nil || !*info.Mergeable || info.CreatedAt.IsZero() {
+		t.Errorf("unexpected pull request %+v", info)
+	}
+	other, err := repo.PullRequests().Create(ctx, "other", "other", "main", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := repo.PullRequests().Get(ctx, 1)
+	if err != nil || got.Get().Title != "title" {
+		t.Errorf("PullRequests().Get() = %v, %v", got, err)
+	}
+	_, err = repo.PullRequests().Get(ctx, 3)
+	validation.TestExpectErrors(t, "PullRequests().Get", err, gitprovider.ErrNotFound)
+
+	updated, err := repo.PullRequests().Update(ctx, 1, gitprovider.PullRequestUpdateOptions{Title: gitprovider.StringVar("new title")})
+	if err != nil || updated.Get().Title != "new title" || updated.Get().Description != "description" {
+		t.Errorf("PullRequests().Update() = %+v, %v", updated.Get(), err)
+	}
+	_, err = repo.PullRequests().Update(ctx, 1, gitprovider.PullRequestUpdateOptions{BaseBranch: gitprovider.StringVar("missing")})
+	validation.TestExpectErrors(t, "PullRequests().Update", err, gitprovider.ErrNotFound)
+
+	err = repo.PullRequests().Merge(ctx, 1, gitprovider.MergeMethod("octopus"), "")
+	validation.TestExpectErrors(t, "PullRequests().Merge", err, gitprovider.ErrInvalidArgument)
+	if err := repo.PullRequests().Merge(ctx, 1, gitprovider.MergeMethodSquash, ""); err != nil {
+		t.Fatal(err)
+	}
+	err = repo.PullRequests().Merge(ctx, 1, gitprovider.MergeMethodMerge, "")
+	validation.TestExpectErrors(t, "PullRequests().Merge", err, gitprovider.ErrInvalidArgument)
+
+	// The merge keeps the changes of both branches
+	commits, err := repo.Commits().ListPage(ctx, "main", 1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := commits[0].APIObject().(*Commit).Files
+	if len(files) != 2 || files["b.txt"] != "b" || files["c.txt"] != "c" {
+		t.Errorf("unexpected files after merge: %v", files)
+	}
+
+	closed, err := repo.PullRequests().Close(ctx, other.Get().Number)
+	if err != nil || closed.Get().State != gitprovider.PullRequestStateClosed || closed.Get().ClosedAt == nil {
+		t.Errorf("PullRequests().Close() = %+v, %v", closed.Get(), err)
+	}
+
+	for _, tt := range []struct {
+		opts []gitprovider.PullRequestListOption
+		want []int
+	}{
+		{nil, []int{1, 2}},
+		{[]gitprovider.PullRequestListOption{&gitprovider.PullRequestListOptions{State: gitprovider.PullRequestStateVar(gitprovider.PullRequestStateMerged)}}, []int{1}},
+		{[]gitprovider.PullRequestListOption{&gitprovider.PullRequestListOptions{State: gitprovider.PullRequestStateVar(gitprovider.PullRequestStateOpen)}}, []int{}},
+		{[]gitprovider.PullRequestListOption{&gitprovider.PullRequestListOptions{HeadBranch: gitprovider.StringVar("other")}}, []int{2}},
+	} {
+		prs, err := repo.PullRequests().List(ctx, tt.opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		numbers := []int{}
+		for _, pr := range prs {
+			numbers = append(numbers, pr.Get().Number)
+		}
+		if !reflect.DeepEqual(numbers, tt.want) {
+			t.Errorf("PullRequests().List(%v) = %v, want %v", tt.opts, numbers, tt.want)
+		}
+	}
+}
+
+func TestPullRequestsReconcile(t *testing.T) {
+	c, orgRef := newTestClient(t)
+	ctx := context.Background()
+	repo, err := c.OrgRepositories().Create(ctx, gitprovider.OrgRepositoryRef{OrganizationRef: orgRef, RepositoryName: "repo"},
+		gitprovider.RepositoryInfo{DefaultBranch: gitprovider.StringVar("main")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := repo.Commits().Create(ctx, "main", "first", []gitprovider.CommitFile{
+		{Path: gitprovider.StringVar("a.txt"), Content: gitprovider.StringVar("a")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Branches().Create(ctx, "feature", first.Get().Sha); err != nil {
+		t.Fatal(err)
+	}
+
+	_, _, err = repo.PullRequests().Reconcile(ctx, gitprovider.PullRequestInfo{Title: "title"})
+	validation.TestExpectErrors(t, "PullRequests().Reconcile", err, validation.ErrFieldRequired)
+
+	req := gitprovider.PullRequestInfo{
+		Title:      "title",
+		HeadBranch: "feature",
+		BaseBranch: "main",
+		Labels:     []string{"bot"},
+	}
+	for _, tt := range []struct {
+		name       string
+		mutate     func(req *gitprovider.PullRequestInfo)
+		wantAction bool
+		wantNumber int
+	}{
+		{"create", func(*gitprovider.PullRequestInfo) {}, true, 1},
+		{"no-op", func(*gitprovider.PullRequestInfo) {}, false, 1},
+		{"title drift", func(req *gitprovider.PullRequestInfo) { req.Title = "new title" }, true, 1},
+		{"labels drift", func(req *gitprovider.PullRequestInfo) { req.Labels = nil }, true, 1},
+		{"labels in sync", func(*gitprovider.PullRequestInfo) {}, false, 1},
+	} {
+		tt.mutate(&req)
+		pr, actionTaken, err := repo.PullRequests().Reconcile(ctx, req)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if actionTaken != tt.wantAction || pr.Get().Number != tt.wantNumber || !req.Equals(pr.Get()) {
+			t.Errorf("%s: PullRequests().Reconcile() = %+v, %v", tt.name, pr.Get(), actionTaken)
+		}
+	}
+
+	// A closed pull request isn't reused
+	if _, err := repo.PullRequests().Close(ctx, 1); err != nil {
+		t.Fatal(err)
+	}
+	pr, actionTaken, err := repo.PullRequests().Reconcile(ctx, req)
+	if err != nil || !actionTaken || pr.Get().Number != 2 {
+		t.Errorf("PullRequests().Reconcile() after Close = %+v, %v, %v", pr.Get(), actionTaken, err)
+	}
+}
+
+func TestPullRequestCommentsAndReviews(t *testing.T) {
+	c, orgRef := newTestClient(t)
+	ctx := context.Background()
+	repo, err := c.OrgRepositories().Create(ctx, gitprovider.OrgRepositoryRef{OrganizationRef: orgRef, RepositoryName: "repo"},
+		gitprovider.RepositoryInfo{DefaultBranch: gitprovider.StringVar("main")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := repo.Commits().Create(ctx, "main", "first", []gitprovider.CommitFile{
+		{Path: gitprovider.StringVar("a.txt"), Content: gitprovider.StringVar("a")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Branches().Create(ctx, "feature", first.Get().Sha); err != nil {
+		t.Fatal(err)
+	}
+	pr, err := repo.PullRequests().Create(ctx, "title", "feature", "main", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, body := range []string{"first", "second"} {
+		if _, err := pr.Comments().Create(ctx, body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	comment, err := pr.Comments().Update(ctx, 1, "first (edited)")
+	if err != nil || comment.Get().Body != "first (edited)" || comment.Get().UpdatedAt.Before(comment.Get().CreatedAt) {
+		t.Errorf("Comments().Update() = %+v, %v", comment, err)
+	}
+	_, err = pr.Comments().Update(ctx, 3, "missing")
+	validation.TestExpectErrors(t, "Comments().Update", err, gitprovider.ErrNotFound)
+	if err := pr.Comments().Delete(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+	err = pr.Comments().Delete(ctx, 2)
+	validation.TestExpectErrors(t, "Comments().Delete", err, gitprovider.ErrNotFound)
+	comments, err := pr.Comments().List(ctx)
+	if err != nil || len(comments) != 1 || comments[0].Get().ID != 1 || comments[0].Get().Body != "first (edited)" {
+		t.Errorf("Comments().List() = %v, %v", comments, err)
+	}
+
+	// Comments are per pull request
+	if err := repo.Branches().Create(ctx, "other", first.Get().Sha); err != nil {
+		t.Fatal(err)
+	}
+	other, err := repo.PullRequests().Create(ctx, "other", "other", "main", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if comments, err := other.Comments().List(ctx); err != nil || len(comments) != 0 {
+		t.Errorf("Comments().List() of another pull request = %v, %v", comments, err)
+	}
+	err = other.Comments().Delete(ctx, 1)
+	validation.TestExpectErrors(t, "Comments().Delete of another pull request", err, gitprovider.ErrNotFound)
+
+	_, err = pr.Reviews().Submit(ctx, gitprovider.PullRequestReviewStateChangesRequested, "")
+	validation.TestExpectErrors(t, "Reviews().Submit without a body", err, gitprovider.ErrInvalidArgument)
+	_, err = pr.Reviews().Submit(ctx, gitprovider.PullRequestReviewState("dismissed"), "body")
+	validation.TestExpectErrors(t, "Reviews().Submit of an unknown state", err, gitprovider.ErrInvalidArgument)
+	if _, err := pr.Reviews().Submit(ctx, gitprovider.PullRequestReviewStateChangesRequested, "needs work"); err != nil {
+		t.Fatal(err)
+	}
+	review, err := pr.Reviews().Submit(ctx, gitprovider.PullRequestReviewStateApproved, "")
+	if err != nil || review.Get().State != gitprovider.PullRequestReviewStateApproved || review.Get().SubmittedAt == nil {
+		t.Errorf("Reviews().Submit() = %+v, %v", review, err)
+	}
+	reviews, err := pr.Reviews().List(ctx)
+	if err != nil || len(reviews) != 2 || reviews[0].Get().State != gitprovider.PullRequestReviewStateChangesRequested || reviews[1].Get().ID != review.Get().ID {
+		t.Errorf("Reviews().List() = %v, %v", reviews, err)
+	}
+}
+
+func TestTags(t *testing.T) {
+	c, orgRef := newTestClient(t)
+	ctx := context.Background()
+	repoRef := gitprovider.OrgRepositoryRef{OrganizationRef: orgRef, RepositoryName: "repo"}
+	repo, err := c.OrgRepositories().Create(ctx, repoRef, gitprovider.RepositoryInfo{DefaultBranch: gitprovider.StringVar("main")},
+		&gitprovider.RepositoryCreateOptions{AutoInit: gitprovider.BoolVar(true)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	main, err := repo.Branches().Get(ctx, "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sha := main.Get().Sha
+
+	annotated := gitprovider.TagInfo{Name: "v1.0.0", Sha: sha, Message: "First release"}
+	tag, err := repo.Tags().Create(ctx, annotated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag.Get() != annotated {
+		t.Errorf("Tags().Create() = %+v, want %+v", tag.Get(), annotated)
+	}
+	if _, err := repo.Tags().Create(ctx, gitprovider.TagInfo{Name: "latest", Sha: sha}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = repo.Tags().Create(ctx, gitprovider.TagInfo{Name: "latest", Sha: sha})
+	validation.TestExpectErrors(t, "Tags().Create", err, gitprovider.ErrAlreadyExists)
+	_, err = repo.Tags().Create(ctx, gitprovider.TagInfo{Name: "other", Sha: "missing"})
+	validation.TestExpectErrors(t, "Tags().Create", err, gitprovider.ErrNotFound)
+	_, err = repo.Tags().Create(ctx, gitprovider.TagInfo{Name: "other"})
+	validation.TestExpectErrors(t, "Tags().Create", err, validation.ErrFieldRequired)
+
+	got, err := repo.Tags().Get(ctx, "v1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Get() != annotated {
+		t.Errorf("Tags().Get() = %+v, want %+v", got.Get(), annotated)
+	}
+	tags, err := repo.Tags().List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tags) != 2 || tags[0].Get().Name != "latest" || tags[1].Get().Name != "v1.0.0" {
+		t.Errorf("Tags().List() = %v, want [latest v1.0.0]", tags)
+	}
+
+	// Files can be read at tags
+	if _, err := repo.Files().Get(ctx, "README.md", "v1.0.0"); err != nil {
+		t.Errorf("Files().Get() at tag error = %v", err)
+	}
+
+	err = repo.Tags().Delete(ctx, "latest")
+	validation.TestExpectErrors(t, "Tags().Delete", err, gitprovider.ErrDestructiveCallDisallowed)
+	dc, err := NewClient(WithSharedState(c), WithDestructiveAPICalls(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	drepo, err := dc.OrgRepositories().Get(ctx, repoRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := drepo.Tags().Delete(ctx, "latest"); err != nil {
+		t.Fatal(err)
+	}
+	err = drepo.Tags().Delete(ctx, "latest")
+	validation.TestExpectErrors(t, "Tags().Delete", err, gitprovider.ErrNotFound)
+	_, err = repo.Tags().Get(ctx, "latest")
+	validation.TestExpectErrors(t, "Tags().Get", err, gitprovider.ErrNotFound)
+}
+
+func TestReleases(t *testing.T) {
+	c, orgRef := newTestClient(t)
+	ctx := context.Background()
+	repoRef := gitprovider.OrgRepositoryRef{OrganizationRef: orgRef, RepositoryName: "repo"}
+	repo, err := c.OrgRepositories().Create(ctx, repoRef, gitprovider.RepositoryInfo{DefaultBranch: gitprovider.StringVar("main")},
+		&gitprovider.RepositoryCreateOptions{AutoInit: gitprovider.BoolVar(true)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	main, err := repo.Branches().Get(ctx, "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The tag is created from the default branch
+	rel, err := repo.Releases().Create(ctx, gitprovider.ReleaseInfo{TagName: "v1.0.0", Body: "Notes", Draft: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := rel.Get()
+	if info.Name != "v1.0.0" || info.Body != "Notes" || !info.Draft || info.TargetCommitish != "main" || info.CreatedAt.IsZero() {
+		t.Errorf("unexpected release %+v", info)
+	}
+	if want := repoRef.String() + "/releases/tag/v1.0.0"; info.WebURL != want {
+		t.Errorf("Releases().Create().WebURL = %q, want %q", info.WebURL, want)
+	}
+	tag, err := repo.Tags().Get(ctx, "v1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag.Get().Sha != main.Get().Sha {
+		t.Errorf("Tags().Get().Sha = %q, want %q", tag.Get().Sha, main.Get().Sha)
+	}
+	_, err = repo.Releases().Create(ctx, gitprovider.ReleaseInfo{TagName: "v1.0.0"})
+	validation.TestExpectErrors(t, "Releases().Create", err, gitprovider.ErrAlreadyExists)
+	_, err = repo.Releases().Create(ctx, gitprovider.ReleaseInfo{TagName: "v2.0.0", TargetCommitish: "missing"})
+	validation.TestExpectErrors(t, "Releases().Create", err, gitprovider.ErrNotFound)
+
+	// Reconcile publishes the draft, and is a no-op afterwards
+	desired := gitprovider.ReleaseInfo{TagName: "v1.0.0", Name: "First", Body: "Notes"}
+	rel, actionTaken, err := repo.Releases().Reconcile(ctx, desired)
+	if err != nil || !actionTaken {
+		t.Fatalf("Releases().Reconcile() = %v, %v, want update", actionTaken, err)
+	}
+	if !desired.Equals(rel.Get()) {
+		t.Errorf("Releases().Reconcile() = %+v, want %+v", rel.Get(), desired)
+	}
+	if _, actionTaken, err := repo.Releases().Reconcile(ctx, desired); err != nil || actionTaken {
+		t.Errorf("Releases().Reconcile() = %v, %v, want no-op", actionTaken, err)
+	}
+	if _, actionTaken, err := repo.Releases().Reconcile(ctx, gitprovider.ReleaseInfo{TagName: "v1.1.0", Prerelease: true}); err != nil || !actionTaken {
+		t.Errorf("Releases().Reconcile() = %v, %v, want create", actionTaken, err)
+	}
+	releases, err := repo.Releases().List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(releases) != 2 || releases[0].Get().TagName != "v1.0.0" || !releases[1].Get().Prerelease {
+		t.Errorf("Releases().List() = %v, want [v1.0.0 v1.1.0]", releases)
+	}
+
+	// Assets
+	assets := rel.Assets()
+	asset, err := assets.Upload(ctx, "app.json", []byte("{}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := gitprovider.ReleaseAssetInfo{
+		Name:        "app.json",
+		Size:        2,
+		ContentType: "application/json",
+		DownloadURL: repoRef.String() + "/releases/download/v1.0.0/app.json",
+	}
+	if asset.Get() != want {
+		t.Errorf("Assets().Upload() = %+v, want %+v", asset.Get(), want)
+	}
+	_, err = assets.Upload(ctx, "app.json", []byte("{}"))
+	validation.TestExpectErrors(t, "Assets().Upload", err, gitprovider.ErrAlreadyExists)
+	if _, err := assets.Upload(ctx, "app", []byte("binary")); err != nil {
+		t.Fatal(err)
+	}
+	list, err := assets.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 2 || list[0].Get() != want || list[1].Get().ContentType != "application/octet-stream" {
+		t.Errorf("Assets().List() = %v", list)
+	}
+	content, err := assets.Download(ctx, "app")
+	if err != nil || string(content) != "binary" {
+		t.Errorf("Assets().Download() = %q, %v, want %q", content, err, "binary")
+	}
+	if err := assets.Delete(ctx, "app"); err != nil {
+		t.Fatal(err)
+	}
+	_, err = assets.Download(ctx, "app")
+	validation.TestExpectErrors(t, "Assets().Download", err, gitprovider.ErrNotFound)
+
+	// Deleting the release keeps the tag
+	if err := repo.Releases().Delete(ctx, "v1.0.0"); err != nil {
+		t.Fatal(err)
+	}
+	_, err = repo.Releases().Get(ctx, "v1.0.0")
+	validation.TestExpectErrors(t, "Releases().Get", err, gitprovider.ErrNotFound)
+	_, err = assets.List(ctx)
+	validation.TestExpectErrors(t, "Assets().List", err, gitprovider.ErrNotFound)
+	if _, err := repo.Tags().Get(ctx, "v1.0.0"); err != nil {
+		t.Errorf("Tags().Get() error = %v", err)
+	}
+	_, err = repo.Releases().Update(ctx, gitprovider.ReleaseInfo{TagName: "v1.0.0"})
+	validation.TestExpectErrors(t, "Releases().Update", err, gitprovider.ErrNotFound)
+}
+
+func TestWebhooks(t *testing.T) {
+	c, orgRef := newTestClient(t)
+	ctx := context.Background()
+	repo, err := c.OrgRepositories().Create(ctx, gitprovider.OrgRepositoryRef{OrganizationRef: orgRef, RepositoryName: "repo"}, gitprovider.RepositoryInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := gitprovider.WebhookInfo{URL: "https://example.com/hook", Secret: "secret"}
+	hook, actionTaken, err := repo.Webhooks().Reconcile(ctx, req)
+	if err != nil || !actionTaken {
+		t.Fatalf("Webhooks().Reconcile() = %v, %v, want the webhook to be created", actionTaken, err)
+	}
+	// The secret is write-only
+	info := hook.Get()
+	if info.ID == 0 || len(info.Secret) != 0 || *info.ContentType != gitprovider.WebhookContentTypeJSON || !*info.Active {
+		t.Errorf("unexpected webhook %+v", info)
+	}
+	_, err = repo.Webhooks().Create(ctx, req)
+	validation.TestExpectErrors(t, "Webhooks().Create", err, gitprovider.ErrAlreadyExists)
+	if _, actionTaken, err := repo.Webhooks().Reconcile(ctx, req); err != nil || actionTaken {
+		t.Fatalf("Webhooks().Reconcile() = %v, %v, want a no-op", actionTaken, err)
+	}
+
+	// Updating without a secret keeps the stored one
+	req.Secret = ""
+	req.Events = []gitprovider.WebhookEvent{gitprovider.WebhookEventRelease, gitprovider.WebhookEventPush}
+	req.Active = gitprovider.BoolVar(false)
+	if _, actionTaken, err := repo.Webhooks().Reconcile(ctx, req); err != nil || !actionTaken {
+		t.Fatalf("Webhooks().Reconcile() = %v, %v, want an update", actionTaken, err)
+	}
+	hooks, err := repo.Webhooks().List(ctx)
+	if err != nil || len(hooks) != 1 || *hooks[0].Get().Active || len(hooks[0].Get().Events) != 2 {
+		t.Fatalf("Webhooks().List() = %v, %v", hooks, err)
+	}
+	if secret := hooks[0].APIObject().(*Webhook).Secret; secret != "secret" {
+		t.Errorf("webhook secret = %q, want %q", secret, "secret")
+	}
+	if err := hooks[0].Delete(ctx); err != nil {
+		t.Fatal(err)
+	}
+	_, err = repo.Webhooks().Get(ctx, req.URL)
+	validation.TestExpectErrors(t, "Webhooks().Get", err, gitprovider.ErrNotFound)
+	err = hooks[0].Delete(ctx)
+	validation.TestExpectErrors(t, "Webhook.Delete", err, gitprovider.ErrNotFound)
+
+	// Organization webhooks are separate from the repository ones
+	org, err := c.Organizations().Get(ctx, orgRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := org.Webhooks().Create(ctx, gitprovider.WebhookInfo{URL: req.URL}); err != nil {
+		t.Fatal(err)
+	}
+	if hooks, err := repo.Webhooks().List(ctx); err != nil || len(hooks) != 0 {
+		t.Errorf("Webhooks().List() = %v, %v, want none", hooks, err)
+	}
+	if _, err := org.Webhooks().Get(ctx, req.URL); err != nil {
+		t.Errorf("Organization.Webhooks().Get() error = %v", err)
+	}
+}
+
+func TestCommitStatuses(t *testing.T) {
+	c, orgRef := newTestClient(t)
+	ctx := context.Background()
+	repoRef := gitprovider.OrgRepositoryRef{OrganizationRef: orgRef, RepositoryName: "repo"}
+	repo, err := c.OrgRepositories().Create(ctx, repoRef, gitprovider.RepositoryInfo{DefaultBranch: gitprovider.StringVar("main")},
+		&gitprovider.RepositoryCreateOptions{AutoInit: gitprovider.BoolVar(true)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	main, err := repo.Branches().Get(ctx, "main")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sha := main.Get().Sha
+
+	// Commits without statuses are pending
+	combined, err := repo.CommitStatuses().Get(ctx, sha)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := combined.Get(); got.Sha != sha || got.State != gitprovider.CommitStatusStatePending || len(got.Statuses) != 0 {
+		t.Errorf("CommitStatuses().Get() = %+v, want pending without statuses", got)
+	}
+
+	status, err := repo.CommitStatuses().Create(ctx, sha, gitprovider.CommitStatusInfo{State: gitprovider.CommitStatusStatePending})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := status.Get(); got.Context != "default" || got.CreatedAt.IsZero() {
+		t.Errorf("CommitStatuses().Create() = %+v, want defaulted context and creation time", got)
+	}
+	for _, req := range []gitprovider.CommitStatusInfo{
+		{State: gitprovider.CommitStatusStateSuccess, Context: "ci/lint"},
+		{State: gitprovider.CommitStatusStateSuccess, Description: "Build passed", TargetURL: "https://ci.example.com/1"},
+	} {
+		if _, err := repo.CommitStatuses().Create(ctx, sha, req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err = repo.CommitStatuses().Create(ctx, "missing", gitprovider.CommitStatusInfo{State: gitprovider.CommitStatusStateSuccess})
+	validation.TestExpectErrors(t, "CommitStatuses().Create", err, gitprovider.ErrNotFound)
+	_, err = repo.CommitStatuses().Create(ctx, sha, gitprovider.CommitStatusInfo{State: "unknown"})
+	validation.TestExpectErrors(t, "CommitStatuses().Create", err, validation.ErrFieldEnumInvalid)
+
+	// The latest status of each context is combined
+	combined, err = repo.CommitStatuses().Get(ctx, sha)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := combined.Get()
+	if got.State != gitprovider.CommitStatusStateSuccess || len(got.Statuses) != 2 {
+		t.Fatalf("CommitStatuses().Get() = %+v, want two successful statuses", got)
+	}
+	if got.Statuses[0].Context != "default" || got.Statuses[0].Description != "Build passed" || got.Statuses[1].Context != "ci/lint" {
+		t.Errorf("CommitStatuses().Get() statuses = %+v, want latest default and ci/lint statuses", got.Statuses)
+	}
+
+	statuses, err := repo.CommitStatuses().List(ctx, sha)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(statuses) != 3 || statuses[0].Get().Description != "Build passed" || statuses[2].Get().State != gitprovider.CommitStatusStatePending {
+		t.Errorf("CommitStatuses().List() = %v, want all three statuses newest first", statuses)
+	}
+	_, err = repo.CommitStatuses().List(ctx, "missing")
+	validation.TestExpectErrors(t, "CommitStatuses().List", err, gitprovider.ErrNotFound)
+}
+
+func TestIssues(t *testing.T) {
+	c, orgRef := newTestClient(t)
+	ctx := context.Background()
+	repoRef := gitprovider.OrgRepositoryRef{OrganizationRef: orgRef, RepositoryName: "repo"}
+	repo, err := c.OrgRepositories().Create(ctx, repoRef, gitprovider.RepositoryInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issue, err := repo.Issues().Create(ctx, gitprovider.IssueInfo{
+		Title:     "Broken link",
+		Body:      "The link in the README is broken.",
+		Labels:    []string{"bug"},
+		Assignees: []string{"alice"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := issue.Get(); got.Number != 1 || got.State != gitprovider.IssueStateOpen || got.CreatedAt.IsZero() || got.ClosedAt != nil {
+		t.Errorf("Issues().Create() = %+v, want open issue 1", got)
+	}
+	if _, err := repo.Issues().Create(ctx, gitprovider.IssueInfo{Title: "Typo", Labels: []string{"docs"}}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = repo.Issues().Create(ctx, gitprovider.IssueInfo{})
+	validation.TestExpectErrors(t, "Issues().Create", err, validation.ErrFieldRequired)
+	_, err = repo.Issues().Get(ctx, 3)
+	validation.TestExpectErrors(t, "Issues().Get", err, gitprovider.ErrNotFound)
+
+	// Update only changes the given fields, and empty slices clear
+	issue, err = repo.Issues().Update(ctx, 1, gitprovider.IssueUpdateOptions{
+		Title:     gitprovider.StringVar("Broken links"),
+		Assignees: []string{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := issue.Get(); got.Title != "Broken links" || got.Body != "The link in the README is broken." || len(got.Assignees) != 0 || !reflect.DeepEqual(got.Labels, []string{"bug"}) {
+		t.Errorf("Issues().Update() = %+v, want new title and no assignees", got)
+	}
+
+	issue, err = repo.Issues().AddLabels(ctx, 1, []string{"bug", "help wanted"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := issue.Get().Labels; !reflect.DeepEqual(got, []string{"bug", "help wanted"}) {
+		t.Errorf("Issues().AddLabels() labels = %v, want [bug help wanted]", got)
+	}
+	issue, err = repo.Issues().RemoveLabels(ctx, 1, []string{"bug", "missing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := issue.Get().Labels; !reflect.DeepEqual(got, []string{"help wanted"}) {
+		t.Errorf("Issues().RemoveLabels() labels = %v, want [help wanted]", got)
+	}
+
+	issue, err = repo.Issues().Close(ctx, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := issue.Get(); got.State != gitprovider.IssueStateClosed || got.ClosedAt == nil {
+		t.Errorf("Issues().Close() = %+v, want closed issue", got)
+	}
+
+	open, err := repo.Issues().List(ctx, &gitprovider.IssueListOptions{State: gitprovider.IssueStateVar(gitprovider.IssueStateOpen)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(open) != 1 || open[0].Get().Number != 1 {
+		t.Errorf("Issues().List() = %v, want only issue 1", open)
+	}
+	docs, err := repo.Issues().List(ctx, &gitprovider.IssueListOptions{Labels: []string{"docs"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(docs) != 1 || docs[0].Get().Number != 2 {
+		t.Errorf("Issues().List() = %v, want only issue 2", docs)
+	}
+
+	issue, err = repo.Issues().Reopen(ctx, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := issue.Get(); got.State != gitprovider.IssueStateOpen || got.ClosedAt != nil {
+		t.Errorf("Issues().Reopen() = %+v, want open issue", got)
+	}
+
+	// Comments
+	comment, err := issue.Comments().Create(ctx, "Fixed in the docs.")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := issue.Comments().Update(ctx, comment.Get().ID, "Fixed in the README."); err != nil {
+		t.Fatal(err)
+	}
+	comments, err := issue.Comments().List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(comments) != 1 || comments[0].Get().Body != "Fixed in the README." {
+		t.Errorf("Comments().List() = %v, want the updated comment", comments)
+	}
+	if err := issue.Comments().Delete(ctx, comment.Get().ID); err != nil {
+		t.Fatal(err)
+	}
+	err = issue.Comments().Delete(ctx, comment.Get().ID)
+	validation.TestExpectErrors(t, "Comments().Delete", err, gitprovider.ErrNotFound)
+}
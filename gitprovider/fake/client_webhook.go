@@ -0,0 +1,139 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// WebhookClient implements the gitprovider.WebhookClient interface.
+var _ gitprovider.WebhookClient = &WebhookClient{}
+
+// WebhookClient operates on the webhooks of a specific repository or organization.
+type WebhookClient struct {
+	*clientContext
+	// ref is the gitprovider.RepositoryRef or gitprovider.OrganizationRef owning the webhooks.
+	ref gitprovider.IdentityRef
+}
+
+// Get returns the webhook delivering to the given URL.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *WebhookClient) Get(_ context.Context, url string) (gitprovider.Webhook, error) {
+	return c.get(url)
+}
+
+func (c *WebhookClient) get(url string) (*webhook, error) {
+	webhooks, err := c.list()
+	if err != nil {
+		return nil, err
+	}
+	// Loop through the webhooks until we find one with the right URL
+	for _, wh := range webhooks {
+		if wh.h.URL == url {
+			return wh, nil
+		}
+	}
+	return nil, fmt.Errorf("webhook %q: %w", url, gitprovider.ErrNotFound)
+}
+
+// List lists all webhooks of the repository or organization.
+func (c *WebhookClient) List(_ context.Context) ([]gitprovider.Webhook, error) {
+	whs, err := c.list()
+	if err != nil {
+		return nil, err
+	}
+	// Cast to the generic []gitprovider.Webhook
+	webhooks := make([]gitprovider.Webhook, 0, len(whs))
+	for _, wh := range whs {
+		webhooks = append(webhooks, wh)
+	}
+	return webhooks, nil
+}
+
+func (c *WebhookClient) list() ([]*webhook, error) {
+	apiObjs, err := c.s.listHooks(c.ref)
+	if err != nil {
+		return nil, err
+	}
+
+	// Map the api object to our Webhook type
+	webhooks := make([]*webhook, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		webhooks = append(webhooks, newWebhook(c, apiObj))
+	}
+	return webhooks, nil
+}
+
+// Create creates a webhook with the given specifications.
+//
+// ErrAlreadyExists will be returned if a webhook delivering to req.URL already exists.
+func (c *WebhookClient) Create(_ context.Context, req gitprovider.WebhookInfo) (gitprovider.Webhook, error) {
+	// First thing, validate and default the request to ensure a valid and fully-populated object
+	// (to minimize any possible diffs between desired and actual state)
+	if err := gitprovider.ValidateAndDefaultInfo(&req); err != nil {
+		return nil, err
+	}
+
+	apiObj, err := c.s.createHook(c.ref, webhookToAPI(&req))
+	if err != nil {
+		return nil, err
+	}
+	return newWebhook(c, apiObj), nil
+}
+
+// Reconcile makes sure the given desired state (req) becomes the actual state in the backing Git provider.
+// The webhook is looked up by req.URL.
+//
+// If req doesn't exist under the hood, it is created (actionTaken == true).
+// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+// If req is already the actual state, this is a no-op (actionTaken == false).
+func (c *WebhookClient) Reconcile(ctx context.Context, req gitprovider.WebhookInfo) (gitprovider.Webhook, bool, error) {
+	// First thing, validate and default the request to ensure a valid and fully-populated object
+	// (to minimize any possible diffs between desired and actual state)
+	if err := gitprovider.ValidateAndDefaultInfo(&req); err != nil {
+		return nil, false, err
+	}
+
+	actual, err := c.get(req.URL)
+	if err != nil {
+		// Create if not found
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			resp, err := c.Create(ctx, req)
+			return resp, true, err
+		}
+
+		// Unexpected path, Get should succeed or return NotFound
+		return nil, false, err
+	}
+
+	// If the desired matches the actual state, just return the actual state
+	if req.Equals(actual.Get()) {
+		return actual, false, nil
+	}
+
+	// Populate the desired state to the current-actual object
+	if err := actual.Set(req); err != nil {
+		return actual, false, err
+	}
+	// Apply the desired state by running Update
+	return actual, true, actual.Update(ctx)
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"testing"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/gitprovider/conformance"
+)
+
+func TestConformance(t *testing.T) {
+	orgRef := gitprovider.OrganizationRef{Domain: DefaultDomain, Organization: "org"}
+	c, err := NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.AddOrganization(orgRef, gitprovider.OrganizationInfo{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.AddTeam(orgRef, gitprovider.TeamInfo{Name: "team"}); err != nil {
+		t.Fatal(err)
+	}
+
+	conformance.Run(t, conformance.Config{
+		NewClient: func(destructive bool) (gitprovider.Client, error) {
+			return NewClient(WithSharedState(c), WithDestructiveAPICalls(destructive))
+		},
+		Organization: orgRef,
+		User:         gitprovider.UserRef{Domain: DefaultDomain, UserLogin: "user"},
+		Team:         "team",
+	})
+}
@@ -0,0 +1,28 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fake implements the gitprovider interfaces using in-memory state, for hermetic unit tests
+// of code built on top of gitprovider.Client.
+//
+// The fake behaves like a generic Git provider: it supports organizations with arbitrarily nested
+// sub-organizations, user and organization repositories, deploy keys, team access, commits,
+// branches and pull requests. It returns the same errors as the real providers, e.g.
+// gitprovider.ErrNotFound, gitprovider.ErrAlreadyExists and gitprovider.ErrDestructiveCallDisallowed,
+// and Reconcile reports actionTaken the same way.
+//
+// Organizations and teams can't be created through the gitprovider interfaces, hence they are seeded
+// using Client.AddOrganization and Client.AddTeam. Users exist implicitly.
+package fake
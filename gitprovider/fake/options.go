@@ -0,0 +1,128 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"fmt"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// DefaultDomain specifies the default domain used by the fake provider.
+const DefaultDomain = "fake.example.com"
+
+// ClientOption is the interface to implement for passing options to NewClient.
+// The clientOptions struct is private to force usage of the With... functions.
+type ClientOption interface {
+	// ApplyToFakeClientOptions applies set fields of this object into target.
+	ApplyToFakeClientOptions(target *clientOptions) error
+}
+
+// clientOptions is the struct that tracks data about what options have been set.
+type clientOptions struct {
+	// Domain specifies the domain of the fake provider.
+	// Default: DefaultDomain
+	Domain *string
+
+	// EnableDestructiveAPICalls is a flag specifying whether destructive API calls (like
+	// deleting a repository) are allowed in the Client. Default: false
+	EnableDestructiveAPICalls *bool
+
+	// TokenPermissions is the set of permissions reported by HasTokenPermission.
+	// Default: all permissions
+	TokenPermissions []gitprovider.TokenPermission
+
+	// State is the in-memory state to use, shared with another client.
+	// Default: a new, empty state
+	State *store
+}
+
+// ApplyToFakeClientOptions implements ClientOption, and applies the set fields of opts
+// into target. If both opts and target has the same specific field set, ErrInvalidClientOptions is returned.
+func (opts *clientOptions) ApplyToFakeClientOptions(target *clientOptions) error {
+	if opts.Domain != nil {
+		// Make sure the user didn't specify the Domain twice
+		if target.Domain != nil {
+			return fmt.Errorf("option Domain already configured: %w", gitprovider.ErrInvalidClientOptions)
+		}
+		// Don't allow an empty string
+		if len(*opts.Domain) == 0 {
+			return fmt.Errorf("option Domain cannot be an empty string: %w", gitprovider.ErrInvalidClientOptions)
+		}
+		target.Domain = opts.Domain
+	}
+
+	if opts.EnableDestructiveAPICalls != nil {
+		// Make sure the user didn't specify the EnableDestructiveAPICalls twice
+		if target.EnableDestructiveAPICalls != nil {
+			return fmt.Errorf("option EnableDestructiveAPICalls already configured: %w", gitprovider.ErrInvalidClientOptions)
+		}
+		target.EnableDestructiveAPICalls = opts.EnableDestructiveAPICalls
+	}
+
+	if opts.TokenPermissions != nil {
+		// Make sure the user didn't specify the TokenPermissions twice
+		if target.TokenPermissions != nil {
+			return fmt.Errorf("option TokenPermissions already configured: %w", gitprovider.ErrInvalidClientOptions)
+		}
+		target.TokenPermissions = opts.TokenPermissions
+	}
+
+	if opts.State != nil {
+		// Make sure the user didn't specify the State twice
+		if target.State != nil {
+			return fmt.Errorf("option State already configured: %w", gitprovider.ErrInvalidClientOptions)
+		}
+		target.State = opts.State
+	}
+	return nil
+}
+
+// WithDomain sets the domain of the fake provider, e.g. "github.com", which all references given
+// to the client must use. domain must not be an empty string. Default: DefaultDomain.
+func WithDomain(domain string) ClientOption {
+	return &clientOptions{Domain: &domain}
+}
+
+// WithDestructiveAPICalls tells the client whether it's allowed to do dangerous and possibly destructive
+// actions, like e.g. deleting a repository.
+func WithDestructiveAPICalls(destructiveActions bool) ClientOption {
+	return &clientOptions{EnableDestructiveAPICalls: &destructiveActions}
+}
+
+// WithTokenPermissions restricts the permissions HasTokenPermission reports the token to have.
+// By default, the token has all permissions.
+func WithTokenPermissions(permissions ...gitprovider.TokenPermission) ClientOption {
+	return &clientOptions{TokenPermissions: append([]gitprovider.TokenPermission{}, permissions...)}
+}
+
+// WithSharedState makes the client use the in-memory state of another client, like two clients
+// talking to the same server, e.g. with different WithDestructiveAPICalls settings.
+func WithSharedState(other *Client) ClientOption {
+	return &clientOptions{State: other.s}
+}
+
+// makeOptions assembles a clientOptions struct from ClientOption mutator functions.
+func makeOptions(opts ...ClientOption) (*clientOptions, error) {
+	o := &clientOptions{}
+	for _, opt := range opts {
+		if err := opt.ApplyToFakeClientOptions(o); err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+	"errors"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+func newBranchProtection(c *BranchProtectionClient, apiObj *BranchProtection) *branchProtection {
+	return &branchProtection{
+		bp: *apiObj,
+		c:  c,
+	}
+}
+
+var _ gitprovider.BranchProtection = &branchProtection{}
+
+type branchProtection struct {
+	bp BranchProtection
+	c  *BranchProtectionClient
+}
+
+func (bp *branchProtection) Get() gitprovider.BranchProtectionInfo {
+	return branchProtectionFromAPI(&bp.bp)
+}
+
+func (bp *branchProtection) Set(info gitprovider.BranchProtectionInfo) error {
+	if err := info.ValidateInfo(); err != nil {
+		return err
+	}
+	branchProtectionInfoToAPIObj(&info, &bp.bp)
+	return nil
+}
+
+func (bp *branchProtection) APIObject() interface{} {
+	return &bp.bp
+}
+
+func (bp *branchProtection) Repository() gitprovider.RepositoryRef {
+	return bp.c.ref
+}
+
+// Update will apply the desired state in this object to the server.
+// In order to apply changes to this object, use the .Set({Resource}Info) error
+// function, or cast .APIObject() to a pointer to the provider-specific type
+// and set custom fields there.
+//
+// ErrNotFound is returned if the resource does not exist.
+//
+// The internal API object will be overridden with the received server data.
+func (bp *branchProtection) Update(_ context.Context) error {
+	// Make sure the branch is still protected, Reconcile is used for creating
+	if _, err := bp.c.s.getBranchProtection(bp.c.ref, bp.bp.Branch); err != nil {
+		return err
+	}
+	apiObj, err := bp.c.s.setBranchProtection(bp.c.ref, &bp.bp)
+	if err != nil {
+		return err
+	}
+	bp.bp = *apiObj
+	return nil
+}
+
+// Delete removes the protection rules of the branch.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (bp *branchProtection) Delete(_ context.Context) error {
+	return bp.c.s.deleteBranchProtection(bp.c.ref, bp.bp.Branch)
+}
+
+// Reconcile makes sure the desired state in this object (called "req" here) becomes
+// the actual state in the backing Git provider.
+//
+// If req doesn't exist under the hood, it is created (actionTaken == true).
+// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+// If req is already the actual state, this is a no-op (actionTaken == false).
+//
+// The internal API object will be overridden with the received server data if actionTaken == true.
+func (bp *branchProtection) Reconcile(ctx context.Context) (bool, error) {
+	actual, err := bp.c.get(bp.bp.Branch)
+	if err != nil {
+		// Create if not found
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			apiObj, err := bp.c.s.setBranchProtection(bp.c.ref, &bp.bp)
+			if err != nil {
+				return false, err
+			}
+			bp.bp = *apiObj
+			return true, nil
+		}
+
+		// Unexpected path, Get should succeed or return NotFound
+		return false, err
+	}
+
+	// If the desired matches the actual state, do nothing
+	if bp.Get().Equals(actual.Get()) {
+		return false, nil
+	}
+	// If desired and actual state mis-match, update
+	return true, bp.Update(ctx)
+}
+
+func branchProtectionFromAPI(apiObj *BranchProtection) gitprovider.BranchProtectionInfo {
+	return gitprovider.BranchProtectionInfo{
+		Branch:                  apiObj.Branch,
+		RequiredApprovals:       gitprovider.IntVar(apiObj.RequiredApprovals),
+		RequiredStatusChecks:    append([]string(nil), apiObj.RequiredStatusChecks...),
+		DismissStaleReviews:     gitprovider.BoolVar(apiObj.DismissStaleReviews),
+		RequireCodeOwnerReviews: gitprovider.BoolVar(apiObj.RequireCodeOwnerReviews),
+		RestrictPushes:          gitprovider.BoolVar(apiObj.RestrictPushes),
+		AllowForcePushes:        gitprovider.BoolVar(apiObj.AllowForcePushes),
+		AllowDeletions:          gitprovider.BoolVar(apiObj.AllowDeletions),
+	}
+}
+
+func branchProtectionToAPI(info *gitprovider.BranchProtectionInfo) *BranchProtection {
+	bp := &BranchProtection{}
+	branchProtectionInfoToAPIObj(info, bp)
+	return bp
+}
+
+func branchProtectionInfoToAPIObj(info *gitprovider.BranchProtectionInfo, apiObj *BranchProtection) {
+	// Required fields, we assume info is validated, and hence these are set
+	apiObj.Branch = info.Branch
+	apiObj.RequiredStatusChecks = append([]string(nil), info.RequiredStatusChecks...)
+	// optional fields
+	if info.RequiredApprovals != nil {
+		apiObj.RequiredApprovals = *info.RequiredApprovals
+	}
+	if info.DismissStaleReviews != nil {
+		apiObj.DismissStaleReviews = *info.DismissStaleReviews
+	}
+	if info.RequireCodeOwnerReviews != nil {
+		apiObj.RequireCodeOwnerReviews = *info.RequireCodeOwnerReviews
+	}
+	if info.RestrictPushes != nil {
+		apiObj.RestrictPushes = *info.RestrictPushes
+	}
+	if info.AllowForcePushes != nil {
+		apiObj.AllowForcePushes = *info.AllowForcePushes
+	}
+	if info.AllowDeletions != nil {
+		apiObj.AllowDeletions = *info.AllowDeletions
+	}
+}
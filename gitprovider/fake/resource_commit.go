@@ -0,0 +1,57 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+func newCommit(apiObj *Commit) *commitType {
+	return &commitType{
+		c: *apiObj,
+	}
+}
+
+var _ gitprovider.Commit = &commitType{}
+
+type commitType struct {
+	c Commit
+}
+
+func (c *commitType) Get() gitprovider.CommitInfo {
+	return commitFromAPI(&c.c)
+}
+
+func (c *commitType) APIObject() interface{} {
+	return &c.c
+}
+
+func commitFromAPI(apiObj *Commit) gitprovider.CommitInfo {
+	info := gitprovider.CommitInfo{
+		Sha:        apiObj.SHA,
+		TreeSha:    apiObj.TreeSHA,
+		Message:    apiObj.Message,
+		Author:     apiObj.Author,
+		Committer:  apiObj.Committer,
+		ParentShas: []string{},
+		WebURL:     apiObj.WebURL,
+	}
+	if len(apiObj.ParentSHA) != 0 {
+		info.ParentShas = append(info.ParentShas, apiObj.ParentSHA)
+	}
+	return info
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+func newCommitStatus(apiObj *CommitStatus) *commitStatus {
+	return &commitStatus{
+		s: *apiObj,
+	}
+}
+
+var _ gitprovider.CommitStatus = &commitStatus{}
+
+type commitStatus struct {
+	s CommitStatus
+}
+
+func (s *commitStatus) Get() gitprovider.CommitStatusInfo {
+	return commitStatusFromAPI(&s.s)
+}
+
+func (s *commitStatus) APIObject() interface{} {
+	return &s.s
+}
+
+func newCombinedCommitStatus(sha string, apiObjs []CommitStatus) *combinedCommitStatus {
+	return &combinedCommitStatus{
+		sha:      sha,
+		statuses: apiObjs,
+	}
+}
+
+var _ gitprovider.CombinedCommitStatus = &combinedCommitStatus{}
+
+type combinedCommitStatus struct {
+	sha      string
+	statuses []CommitStatus
+}
+
+func (s *combinedCommitStatus) Get() gitprovider.CombinedCommitStatusInfo {
+	statuses := make([]gitprovider.CommitStatusInfo, 0, len(s.statuses))
+	for i := range s.statuses {
+		statuses = append(statuses, commitStatusFromAPI(&s.statuses[i]))
+	}
+	return gitprovider.CombinedCommitStatusInfo{
+		Sha:      s.sha,
+		State:    gitprovider.CombineCommitStatusStates(statuses),
+		Statuses: statuses,
+	}
+}
+
+func (s *combinedCommitStatus) APIObject() interface{} {
+	return s.statuses
+}
+
+func commitStatusFromAPI(apiObj *CommitStatus) gitprovider.CommitStatusInfo {
+	return gitprovider.CommitStatusInfo{
+		State:       apiObj.State,
+		Context:     apiObj.Context,
+		Description: apiObj.Description,
+		TargetURL:   apiObj.TargetURL,
+		CreatedAt:   apiObj.CreatedAt,
+	}
+}
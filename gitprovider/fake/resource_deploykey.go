@@ -0,0 +1,145 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+func newDeployKey(c *DeployKeyClient, key *DeployKey) *deployKey {
+	return &deployKey{
+		k: *key,
+		c: c,
+	}
+}
+
+var _ gitprovider.DeployKey = &deployKey{}
+
+type deployKey struct {
+	k DeployKey
+	c *DeployKeyClient
+}
+
+func (dk *deployKey) Get() gitprovider.DeployKeyInfo {
+	return deployKeyFromAPI(&dk.k)
+}
+
+func (dk *deployKey) Set(info gitprovider.DeployKeyInfo) error {
+	if err := info.ValidateInfo(); err != nil {
+		return err
+	}
+	deployKeyInfoToAPIObj(&info, &dk.k)
+	return nil
+}
+
+func (dk *deployKey) APIObject() interface{} {
+	return &dk.k
+}
+
+func (dk *deployKey) Repository() gitprovider.RepositoryRef {
+	return dk.c.ref
+}
+
+// Update will apply the desired state in this object to the server.
+// Only set fields will be respected (i.e. PATCH behaviour).
+// In order to apply changes to this object, use the .Set({Resource}Info) error
+// function, or cast .APIObject() to a pointer to the provider-specific type
+// and set custom fields there.
+//
+// ErrNotFound is returned if the resource does not exist.
+//
+// The internal API object will be overridden with the received server data.
+func (dk *deployKey) Update(ctx context.Context) error {
+	// Delete the old key and recreate
+	if err := dk.Delete(ctx); err != nil {
+		return err
+	}
+	return dk.createIntoSelf()
+}
+
+// Delete deletes a deploy key from the repository.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (dk *deployKey) Delete(_ context.Context) error {
+	return dk.c.s.deleteKey(dk.c.ref, dk.k.ID)
+}
+
+// Reconcile makes sure the desired state in this object (called "req" here) becomes
+// the actual state in the backing Git provider.
+//
+// If req doesn't exist under the hood, it is created (actionTaken == true).
+// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+// If req is already the actual state, this is a no-op (actionTaken == false).
+//
+// The internal API object will be overridden with the received server data if actionTaken == true.
+func (dk *deployKey) Reconcile(ctx context.Context) (bool, error) {
+	actual, err := dk.c.get(dk.k.Title)
+	if err != nil {
+		// Create if not found
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			return true, dk.createIntoSelf()
+		}
+
+		// Unexpected path, Get should succeed or return NotFound
+		return false, err
+	}
+
+	// If the desired matches the actual state, do nothing
+	if reflect.DeepEqual(deployKeyFromAPI(&dk.k), deployKeyFromAPI(&actual.k)) {
+		return false, nil
+	}
+	// If desired and actual state mis-match, update
+	dk.k.ID = actual.k.ID
+	return true, dk.Update(ctx)
+}
+
+func (dk *deployKey) createIntoSelf() error {
+	apiObj, err := dk.c.s.createKey(dk.c.ref, &dk.k)
+	if err != nil {
+		return err
+	}
+	dk.k = *apiObj
+	return nil
+}
+
+func deployKeyFromAPI(apiObj *DeployKey) gitprovider.DeployKeyInfo {
+	return gitprovider.DeployKeyInfo{
+		Name:     apiObj.Title,
+		Key:      append([]byte{}, apiObj.Key...),
+		ReadOnly: gitprovider.BoolVar(apiObj.ReadOnly),
+	}
+}
+
+func deployKeyToAPI(info *gitprovider.DeployKeyInfo) *DeployKey {
+	k := &DeployKey{}
+	deployKeyInfoToAPIObj(info, k)
+	return k
+}
+
+func deployKeyInfoToAPIObj(info *gitprovider.DeployKeyInfo, apiObj *DeployKey) {
+	// Required fields, we assume info is validated, and hence these are set
+	apiObj.Title = info.Name
+	apiObj.Key = append([]byte{}, info.Key...)
+	// optional fields
+	if info.ReadOnly != nil {
+		apiObj.ReadOnly = *info.ReadOnly
+	}
+}
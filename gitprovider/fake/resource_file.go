@@ -0,0 +1,51 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+func newFile(apiObj *File) *file {
+	return &file{
+		f: *apiObj,
+	}
+}
+
+var _ gitprovider.File = &file{}
+
+type file struct {
+	f File
+}
+
+func (f *file) Get() gitprovider.FileInfo {
+	return fileFromAPI(&f.f)
+}
+
+func (f *file) APIObject() interface{} {
+	return &f.f
+}
+
+func fileFromAPI(apiObj *File) gitprovider.FileInfo {
+	return gitprovider.FileInfo{
+		Path:    apiObj.Path,
+		Mode:    apiObj.Mode,
+		Sha:     apiObj.SHA,
+		Size:    apiObj.Size,
+		Content: append([]byte(nil), apiObj.Content...),
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+func newIssue(ctx *clientContext, apiObj *Issue, ref gitprovider.RepositoryRef) *issue {
+	return &issue{
+		i: *apiObj,
+		comments: &IssueCommentClient{
+			clientContext: ctx,
+			ref:           ref,
+			number:        apiObj.Number,
+		},
+	}
+}
+
+var _ gitprovider.Issue = &issue{}
+
+type issue struct {
+	i Issue
+
+	comments *IssueCommentClient
+}
+
+func (i *issue) Get() gitprovider.IssueInfo {
+	return issueFromAPI(&i.i)
+}
+
+func (i *issue) APIObject() interface{} {
+	return &i.i
+}
+
+// Comments gives access to this specific issue's comments
+func (i *issue) Comments() gitprovider.IssueCommentClient {
+	return i.comments
+}
+
+func issueFromAPI(apiObj *Issue) gitprovider.IssueInfo {
+	info := gitprovider.IssueInfo{
+		Number:    apiObj.Number,
+		Title:     apiObj.Title,
+		Body:      apiObj.Body,
+		State:     apiObj.State,
+		Labels:    apiObj.Labels,
+		Assignees: apiObj.Assignees,
+		CreatedAt: apiObj.CreatedAt,
+		UpdatedAt: apiObj.UpdatedAt,
+		WebURL:    apiObj.WebURL,
+	}
+	if !apiObj.ClosedAt.IsZero() {
+		closedAt := apiObj.ClosedAt
+		info.ClosedAt = &closedAt
+	}
+	return info
+}
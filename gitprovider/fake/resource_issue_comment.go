@@ -0,0 +1,46 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+func newIssueComment(apiObj *IssueComment) *issueComment {
+	return &issueComment{
+		c: *apiObj,
+	}
+}
+
+var _ gitprovider.IssueComment = &issueComment{}
+
+type issueComment struct {
+	c IssueComment
+}
+
+func (c *issueComment) Get() gitprovider.IssueCommentInfo {
+	return gitprovider.IssueCommentInfo{
+		ID:        c.c.ID,
+		Body:      c.c.Body,
+		CreatedAt: c.c.CreatedAt,
+		UpdatedAt: c.c.UpdatedAt,
+	}
+}
+
+func (c *issueComment) APIObject() interface{} {
+	return &c.c
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+func newOrganization(ctx *clientContext, apiObj *Organization, ref gitprovider.OrganizationRef) *organization {
+	return &organization{
+		clientContext: ctx,
+		o:             *apiObj,
+		ref:           ref,
+		teams: &TeamsClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+		webhooks: &WebhookClient{
+			clientContext: ctx,
+			ref:           ref,
+		},
+	}
+}
+
+var _ gitprovider.Organization = &organization{}
+
+type organization struct {
+	*clientContext
+
+	o   Organization
+	ref gitprovider.OrganizationRef
+
+	teams    *TeamsClient
+	webhooks *WebhookClient
+}
+
+func (o *organization) Get() gitprovider.OrganizationInfo {
+	return organizationFromAPI(&o.o)
+}
+
+func (o *organization) APIObject() interface{} {
+	return &o.o
+}
+
+func (o *organization) Organization() gitprovider.OrganizationRef {
+	return o.ref
+}
+
+func (o *organization) Teams() gitprovider.TeamsClient {
+	return o.teams
+}
+
+func (o *organization) Webhooks() gitprovider.WebhookClient {
+	return o.webhooks
+}
+
+func organizationFromAPI(apiObj *Organization) gitprovider.OrganizationInfo {
+	info := gitprovider.OrganizationInfo{
+		Name: gitprovider.StringVar(apiObj.Name),
+	}
+	if len(apiObj.Description) != 0 {
+		info.Description = gitprovider.StringVar(apiObj.Description)
+	}
+	return info
+}
+
+func organizationToAPI(info *gitprovider.OrganizationInfo) Organization {
+	apiObj := Organization{}
+	if info.Name != nil {
+		apiObj.Name = *info.Name
+	}
+	if info.Description != nil {
+		apiObj.Description = *info.Description
+	}
+	return apiObj
+}
+
+func newTeam(c *TeamsClient, apiObj *Team) *team {
+	return &team{
+		t:   *apiObj,
+		ref: c.ref,
+	}
+}
+
+var _ gitprovider.Team = &team{}
+
+type team struct {
+	t   Team
+	ref gitprovider.OrganizationRef
+}
+
+func (t *team) Get() gitprovider.TeamInfo {
+	return gitprovider.TeamInfo{
+		Name:    t.t.Name,
+		Members: append([]string{}, t.t.Members...),
+	}
+}
+
+func (t *team) APIObject() interface{} {
+	return &t.t
+}
+
+func (t *team) Organization() gitprovider.OrganizationRef {
+	return t.ref
+}
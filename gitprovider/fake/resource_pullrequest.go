@@ -0,0 +1,92 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+func newPullRequest(ctx *clientContext, apiObj *PullRequest, ref gitprovider.RepositoryRef) *pullrequest {
+	return &pullrequest{
+		pr: *apiObj,
+		comments: &PullRequestCommentClient{
+			clientContext: ctx,
+			ref:           ref,
+			number:        apiObj.Number,
+		},
+		reviews: &PullRequestReviewClient{
+			clientContext: ctx,
+			ref:           ref,
+			number:        apiObj.Number,
+		},
+	}
+}
+
+var _ gitprovider.PullRequest = &pullrequest{}
+
+type pullrequest struct {
+	pr PullRequest
+
+	comments *PullRequestCommentClient
+	reviews  *PullRequestReviewClient
+}
+
+func (pr *pullrequest) Get() gitprovider.PullRequestInfo {
+	return pullRequestFromAPI(&pr.pr)
+}
+
+func (pr *pullrequest) APIObject() interface{} {
+	return &pr.pr
+}
+
+// Comments gives access to this specific pull request's comments
+func (pr *pullrequest) Comments() gitprovider.PullRequestCommentClient {
+	return pr.comments
+}
+
+// Reviews gives access to this specific pull request's reviews
+func (pr *pullrequest) Reviews() gitprovider.PullRequestReviewClient {
+	return pr.reviews
+}
+
+func pullRequestFromAPI(apiObj *PullRequest) gitprovider.PullRequestInfo {
+	info := gitprovider.PullRequestInfo{
+		Number:      apiObj.Number,
+		Title:       apiObj.Title,
+		Description: apiObj.Description,
+		HeadBranch:  apiObj.Head,
+		BaseBranch:  apiObj.Base,
+		State:       apiObj.State,
+		Labels:      apiObj.Labels,
+		CreatedAt:   apiObj.CreatedAt,
+		UpdatedAt:   apiObj.UpdatedAt,
+		WebURL:      apiObj.WebURL,
+	}
+	// The fake doesn't model conflicts, so all open pull requests can be merged
+	if apiObj.State == gitprovider.PullRequestStateOpen {
+		info.Mergeable = gitprovider.BoolVar(true)
+	}
+	if !apiObj.MergedAt.IsZero() {
+		mergedAt := apiObj.MergedAt
+		info.MergedAt = &mergedAt
+	}
+	if !apiObj.ClosedAt.IsZero() {
+		closedAt := apiObj.ClosedAt
+		info.ClosedAt = &closedAt
+	}
+	return info
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+func newPullRequestReview(apiObj *PullRequestReview) *pullRequestReview {
+	return &pullRequestReview{
+		r: *apiObj,
+	}
+}
+
+var _ gitprovider.PullRequestReview = &pullRequestReview{}
+
+type pullRequestReview struct {
+	r PullRequestReview
+}
+
+func (r *pullRequestReview) Get() gitprovider.PullRequestReviewInfo {
+	submittedAt := r.r.SubmittedAt
+	return gitprovider.PullRequestReviewInfo{
+		ID:          r.r.ID,
+		State:       r.r.State,
+		Body:        r.r.Body,
+		SubmittedAt: &submittedAt,
+	}
+}
+
+func (r *pullRequestReview) APIObject() interface{} {
+	return &r.r
+}
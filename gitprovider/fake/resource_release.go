@@ -0,0 +1,75 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+func newRelease(c *ReleaseClient, apiObj *Release) *release {
+	return &release{
+		r: *apiObj,
+		assets: &ReleaseAssetClient{
+			clientContext: c.clientContext,
+			ref:           c.ref,
+			tagName:       apiObj.TagName,
+		},
+	}
+}
+
+var _ gitprovider.Release = &release{}
+
+type release struct {
+	r      Release
+	assets *ReleaseAssetClient
+}
+
+func (r *release) Get() gitprovider.ReleaseInfo {
+	return releaseFromAPI(&r.r)
+}
+
+func (r *release) APIObject() interface{} {
+	return &r.r
+}
+
+func (r *release) Assets() gitprovider.ReleaseAssetClient {
+	return r.assets
+}
+
+func releaseFromAPI(apiObj *Release) gitprovider.ReleaseInfo {
+	return gitprovider.ReleaseInfo{
+		TagName:         apiObj.TagName,
+		TargetCommitish: apiObj.TargetCommitish,
+		Name:            apiObj.Name,
+		Body:            apiObj.Body,
+		Draft:           apiObj.Draft,
+		Prerelease:      apiObj.Prerelease,
+		CreatedAt:       apiObj.CreatedAt,
+		WebURL:          apiObj.WebURL,
+	}
+}
+
+func releaseToAPI(req *gitprovider.ReleaseInfo) *Release {
+	return &Release{
+		TagName:         req.TagName,
+		TargetCommitish: req.TargetCommitish,
+		Name:            req.Name,
+		Body:            req.Body,
+		Draft:           req.Draft,
+		Prerelease:      req.Prerelease,
+	}
+}
@@ -0,0 +1,46 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+func newReleaseAsset(apiObj *ReleaseAsset) *releaseAsset {
+	return &releaseAsset{
+		a: *apiObj,
+	}
+}
+
+var _ gitprovider.ReleaseAsset = &releaseAsset{}
+
+type releaseAsset struct {
+	a ReleaseAsset
+}
+
+func (a *releaseAsset) Get() gitprovider.ReleaseAssetInfo {
+	return gitprovider.ReleaseAssetInfo{
+		Name:        a.a.Name,
+		Size:        a.a.Size,
+		ContentType: a.a.ContentType,
+		DownloadURL: a.a.DownloadURL,
+	}
+}
+
+func (a *releaseAsset) APIObject() interface{} {
+	return &a.a
+}
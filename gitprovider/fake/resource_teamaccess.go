@@ -0,0 +1,139 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+	"errors"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+func newTeamAccess(c *TeamAccessClient, apiObj *TeamAccess) *teamAccess {
+	return &teamAccess{
+		ta: *apiObj,
+		c:  c,
+	}
+}
+
+var _ gitprovider.TeamAccess = &teamAccess{}
+
+type teamAccess struct {
+	ta TeamAccess
+	c  *TeamAccessClient
+}
+
+func (ta *teamAccess) Get() gitprovider.TeamAccessInfo {
+	return teamAccessFromAPI(&ta.ta)
+}
+
+func (ta *teamAccess) Set(info gitprovider.TeamAccessInfo) error {
+	if err := info.ValidateInfo(); err != nil {
+		return err
+	}
+	teamAccessInfoToAPIObj(&info, &ta.ta)
+	return nil
+}
+
+func (ta *teamAccess) APIObject() interface{} {
+	return &ta.ta
+}
+
+func (ta *teamAccess) Repository() gitprovider.RepositoryRef {
+	return ta.c.ref
+}
+
+// Delete removes the given team from the repository's team access control list.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (ta *teamAccess) Delete(_ context.Context) error {
+	return ta.c.s.deleteTeamAccess(ta.c.ref, ta.ta.Name)
+}
+
+// Update will apply the desired state in this object to the server.
+// Only set fields will be respected (i.e. PATCH behaviour).
+// In order to apply changes to this object, use the .Set({Resource}Info) error
+// function, or cast .APIObject() to a pointer to the provider-specific type
+// and set custom fields there.
+//
+// ErrNotFound is returned if the resource does not exist.
+//
+// The internal API object will be overridden with the received server data.
+func (ta *teamAccess) Update(_ context.Context) error {
+	apiObj, err := ta.c.s.setTeamAccess(ta.c.ref, &ta.ta, false)
+	if err != nil {
+		return err
+	}
+	ta.ta = *apiObj
+	return nil
+}
+
+// Reconcile makes sure the desired state in this object (called "req" here) becomes
+// the actual state in the backing Git provider.
+//
+// If req doesn't exist under the hood, it is created (actionTaken == true).
+// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+// If req is already the actual state, this is a no-op (actionTaken == false).
+//
+// The internal API object will be overridden with the received server data if actionTaken == true.
+func (ta *teamAccess) Reconcile(ctx context.Context) (bool, error) {
+	req := ta.Get()
+	actual, err := ta.c.Get(ctx, req.Name)
+	if err != nil {
+		// Create if not found
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			apiObj, err := ta.c.s.setTeamAccess(ta.c.ref, &ta.ta, true)
+			if err != nil {
+				return true, err
+			}
+			ta.ta = *apiObj
+			return true, nil
+		}
+
+		// Unexpected path, Get should succeed or return NotFound
+		return false, err
+	}
+
+	// If the desired matches the actual state, just return the actual state
+	if req.Equals(actual.Get()) {
+		return false, nil
+	}
+
+	return true, ta.Update(ctx)
+}
+
+func teamAccessFromAPI(apiObj *TeamAccess) gitprovider.TeamAccessInfo {
+	return gitprovider.TeamAccessInfo{
+		Name:       apiObj.Name,
+		Permission: gitprovider.RepositoryPermissionVar(apiObj.Permission),
+	}
+}
+
+func teamAccessToAPI(info *gitprovider.TeamAccessInfo) *TeamAccess {
+	apiObj := &TeamAccess{}
+	teamAccessInfoToAPIObj(info, apiObj)
+	return apiObj
+}
+
+func teamAccessInfoToAPIObj(info *gitprovider.TeamAccessInfo, apiObj *TeamAccess) {
+	// Required fields, we assume info is validated, and hence these are set
+	apiObj.Name = info.Name
+	// optional fields
+	if info.Permission != nil {
+		apiObj.Permission = *info.Permission
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+	"errors"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+func newWebhook(c *WebhookClient, apiObj *Webhook) *webhook {
+	return &webhook{
+		h: *apiObj,
+		c: c,
+	}
+}
+
+var _ gitprovider.Webhook = &webhook{}
+
+type webhook struct {
+	h Webhook
+	c *WebhookClient
+}
+
+func (wh *webhook) Get() gitprovider.WebhookInfo {
+	return webhookFromAPI(&wh.h)
+}
+
+func (wh *webhook) Set(info gitprovider.WebhookInfo) error {
+	if err := info.ValidateInfo(); err != nil {
+		return err
+	}
+	webhookInfoToAPIObj(&info, &wh.h)
+	return nil
+}
+
+func (wh *webhook) APIObject() interface{} {
+	return &wh.h
+}
+
+// Update will apply the desired state in this object to the server.
+// In order to apply changes to this object, use the .Set({Resource}Info) error
+// function, or cast .APIObject() to a pointer to the provider-specific type
+// and set custom fields there.
+//
+// ErrNotFound is returned if the resource does not exist.
+//
+// The internal API object will be overridden with the received server data.
+func (wh *webhook) Update(_ context.Context) error {
+	apiObj, err := wh.c.s.updateHook(wh.c.ref, &wh.h)
+	if err != nil {
+		return err
+	}
+	wh.h = *apiObj
+	return nil
+}
+
+// Delete deletes the webhook.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (wh *webhook) Delete(_ context.Context) error {
+	return wh.c.s.deleteHook(wh.c.ref, wh.h.ID)
+}
+
+// Reconcile makes sure the desired state in this object (called "req" here) becomes
+// the actual state in the backing Git provider. The webhook is looked up by its URL.
+//
+// If req doesn't exist under the hood, it is created (actionTaken == true).
+// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+// If req is already the actual state, this is a no-op (actionTaken == false).
+//
+// The internal API object will be overridden with the received server data if actionTaken == true.
+func (wh *webhook) Reconcile(ctx context.Context) (bool, error) {
+	actual, err := wh.c.get(wh.h.URL)
+	if err != nil {
+		// Create if not found
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			apiObj, err := wh.c.s.createHook(wh.c.ref, &wh.h)
+			if err != nil {
+				return true, err
+			}
+			wh.h = *apiObj
+			return true, nil
+		}
+
+		// Unexpected path, Get should succeed or return NotFound
+		return false, err
+	}
+
+	// If the desired matches the actual state, do nothing
+	if wh.Get().Equals(actual.Get()) {
+		return false, nil
+	}
+	// If desired and actual state mis-match, update the found webhook
+	wh.h.ID = actual.h.ID
+	return true, wh.Update(ctx)
+}
+
+func webhookFromAPI(apiObj *Webhook) gitprovider.WebhookInfo {
+	// The secret is write-only, like in the real providers
+	return gitprovider.WebhookInfo{
+		ID:              apiObj.ID,
+		URL:             apiObj.URL,
+		ContentType:     gitprovider.WebhookContentTypeVar(apiObj.ContentType),
+		Events:          append([]gitprovider.WebhookEvent(nil), apiObj.Events...),
+		SSLVerification: gitprovider.BoolVar(apiObj.SSLVerification),
+		Active:          gitprovider.BoolVar(apiObj.Active),
+	}
+}
+
+func webhookToAPI(info *gitprovider.WebhookInfo) *Webhook {
+	wh := &Webhook{}
+	webhookInfoToAPIObj(info, wh)
+	return wh
+}
+
+func webhookInfoToAPIObj(info *gitprovider.WebhookInfo, apiObj *Webhook) {
+	// Required fields, we assume info is validated, and hence these are set
+	apiObj.URL = info.URL
+	apiObj.Secret = info.Secret
+	// optional fields
+	if info.ContentType != nil {
+		apiObj.ContentType = *info.ContentType
+	}
+	if len(info.Events) != 0 {
+		apiObj.Events = append([]gitprovider.WebhookEvent(nil), info.Events...)
+	}
+	if info.SSLVerification != nil {
+		apiObj.SSLVerification = *info.SSLVerification
+	}
+	if info.Active != nil {
+		apiObj.Active = *info.Active
+	}
+}
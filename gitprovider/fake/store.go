@@ -0,0 +1,1584 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+const (
+	// defaultPerPage is the page size used when listing commits with a non-positive page size.
+	defaultPerPage = 30
+	// readmeFile is the path of the README created for auto-initialized repositories.
+	readmeFile = "README.md"
+	// licenseFile is the path of the license created for auto-initialized repositories.
+	licenseFile = "LICENSE"
+	// initialCommitMessage is the message of the commit auto-initializing a repository.
+	initialCommitMessage = "Initial commit"
+)
+
+// store holds the in-memory state of the fake provider. All methods are safe for concurrent use,
+// and copy data in and out of the state.
+type store struct {
+	mu sync.Mutex
+
+	// orgs by OrganizationRef.String()
+	orgs map[string]*orgState
+	// repos by RepositoryRef.String()
+	repos map[string]*repoState
+}
+
+type orgState struct {
+	ref   gitprovider.OrganizationRef
+	org   Organization
+	teams map[string]*Team
+	hooks webhooks
+}
+
+// webhooks holds the webhooks of a repository or organization.
+type webhooks struct {
+	list   []*Webhook
+	nextID int64
+}
+
+type repoState struct {
+	ref  gitprovider.RepositoryRef
+	repo *Repository
+
+	keys      []*DeployKey
+	nextKeyID int64
+	hooks     webhooks
+	teams     map[string]*TeamAccess
+	commits   map[string]*Commit
+	branches  map[string]string
+	protected map[string]*BranchProtection
+	prs       []*PullRequest
+
+	comments      []*PullRequestComment
+	nextCommentID int64
+	reviews       []*PullRequestReview
+	nextReviewID  int64
+
+	tags     map[string]*Tag
+	releases []*Release
+	// assets by the tag name of their release
+	assets map[string][]*ReleaseAsset
+
+	// statuses by commit sha, oldest first
+	statuses     map[string][]*CommitStatus
+	nextStatusID int64
+
+	issues             []*Issue
+	issueComments      []*IssueComment
+	nextIssueCommentID int64
+}
+
+func newStore() *store {
+	return &store{
+		orgs:  map[string]*orgState{},
+		repos: map[string]*repoState{},
+	}
+}
+
+//
+// Organizations and teams
+//
+
+func (s *store) addOrg(ref gitprovider.OrganizationRef, org Organization) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.orgs[ref.String()]; ok {
+		return fmt.Errorf("organization %q: %w", ref.String(), gitprovider.ErrAlreadyExists)
+	}
+	// Sub-organizations can only be added below existing organizations
+	if len(ref.SubOrganizations) != 0 {
+		if _, err := s.org(parentRef(ref)); err != nil {
+			return err
+		}
+	}
+	s.orgs[ref.String()] = &orgState{ref: ref, org: org, teams: map[string]*Team{}}
+	return nil
+}
+
+func (s *store) addTeam(ref gitprovider.OrganizationRef, team *Team) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, err := s.org(ref)
+	if err != nil {
+		return err
+	}
+	if _, ok := o.teams[team.Name]; ok {
+		return fmt.Errorf("team %q: %w", team.Name, gitprovider.ErrAlreadyExists)
+	}
+	o.teams[team.Name] = copyTeam(team)
+	return nil
+}
+
+// org returns the state of the given organization. s.mu must be held.
+func (s *store) org(ref gitprovider.OrganizationRef) (*orgState, error) {
+	o, ok := s.orgs[ref.String()]
+	if !ok {
+		return nil, fmt.Errorf("organization %q: %w", ref.String(), gitprovider.ErrNotFound)
+	}
+	return o, nil
+}
+
+func (s *store) getOrg(ref gitprovider.OrganizationRef) (*Organization, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, err := s.org(ref)
+	if err != nil {
+		return nil, err
+	}
+	org := o.org
+	return &org, nil
+}
+
+// listOrgs returns the organizations for which filter returns true, sorted by reference.
+func (s *store) listOrgs(filter func(ref gitprovider.OrganizationRef) bool) ([]gitprovider.OrganizationRef, []*Organization) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keys := make([]string, 0, len(s.orgs))
+	for key, o := range s.orgs {
+		if filter(o.ref) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	refs := make([]gitprovider.OrganizationRef, 0, len(keys))
+	orgs := make([]*Organization, 0, len(keys))
+	for _, key := range keys {
+		org := s.orgs[key].org
+		refs = append(refs, s.orgs[key].ref)
+		orgs = append(orgs, &org)
+	}
+	return refs, orgs
+}
+
+func (s *store) getTeam(ref gitprovider.OrganizationRef, name string) (*Team, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, err := s.org(ref)
+	if err != nil {
+		return nil, err
+	}
+	team, ok := o.teams[name]
+	if !ok {
+		return nil, fmt.Errorf("team %q: %w", name, gitprovider.ErrNotFound)
+	}
+	return copyTeam(team), nil
+}
+
+func (s *store) listTeams(ref gitprovider.OrganizationRef) ([]*Team, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	o, err := s.org(ref)
+	if err != nil {
+		return nil, err
+	}
+	teams := make([]*Team, 0, len(o.teams))
+	for _, team := range o.teams {
+		teams = append(teams, copyTeam(team))
+	}
+	sort.Slice(teams, func(i, j int) bool { return teams[i].Name < teams[j].Name })
+	return teams, nil
+}
+
+//
+// Repositories
+//
+
+// repository returns the state of the given repository. s.mu must be held.
+func (s *store) repository(ref gitprovider.RepositoryRef) (*repoState, error) {
+	r, ok := s.repos[ref.String()]
+	if !ok {
+		return nil, fmt.Errorf("repository %q: %w", ref.String(), gitprovider.ErrNotFound)
+	}
+	return r, nil
+}
+
+// withRepo runs fn with s.mu held, for the state of the given repository.
+func (s *store) withRepo(ref gitprovider.RepositoryRef, fn func(r *repoState) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, err := s.repository(ref)
+	if err != nil {
+		return err
+	}
+	return fn(r)
+}
+
+func (s *store) getRepo(ref gitprovider.RepositoryRef) (*Repository, error) {
+	var repo *Repository
+	return repo, s.withRepo(ref, func(r *repoState) error {
+		repo = copyRepository(r.repo)
+		return nil
+	})
+}
+
+// listRepos returns the repositories owned by the given identity, sorted by name.
+func (s *store) listRepos(owner gitprovider.IdentityRef) ([]gitprovider.RepositoryRef, []*Repository) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	states := make([]*repoState, 0)
+	for _, r := range s.repos {
+		if r.ref.GetType() == owner.GetType() && r.ref.GetIdentity() == owner.GetIdentity() {
+			states = append(states, r)
+		}
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].repo.Name < states[j].repo.Name })
+
+	refs := make([]gitprovider.RepositoryRef, 0, len(states))
+	repos := make([]*Repository, 0, len(states))
+	for _, r := range states {
+		refs = append(refs, r.ref)
+		repos = append(repos, copyRepository(r.repo))
+	}
+	return refs, repos
+}
+
+func (s *store) createRepo(ref gitprovider.RepositoryRef, repo *Repository, opts gitprovider.RepositoryCreateOptions) (*Repository, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Organization repositories can only be created in existing organizations
+	if orgRef, ok := ref.(gitprovider.OrgRepositoryRef); ok {
+		if _, err := s.org(orgRef.OrganizationRef); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := s.repos[ref.String()]; ok {
+		return nil, fmt.Errorf("repository %q: %w", ref.String(), gitprovider.ErrAlreadyExists)
+	}
+
+	r := &repoState{
+		ref:       ref,
+		repo:      copyRepository(repo),
+		teams:     map[string]*TeamAccess{},
+		commits:   map[string]*Commit{},
+		branches:  map[string]string{},
+		protected: map[string]*BranchProtection{},
+		tags:      map[string]*Tag{},
+		assets:    map[string][]*ReleaseAsset{},
+		statuses:  map[string][]*CommitStatus{},
+	}
+	if opts.AutoInit != nil && *opts.AutoInit {
+		files := map[string]*string{
+			readmeFile: gitprovider.StringVar(fmt.Sprintf("# %s\n", repo.Name)),
+		}
+		if opts.LicenseTemplate != nil {
+			files[licenseFile] = gitprovider.StringVar(fmt.Sprintf("%s license\n", *opts.LicenseTemplate))
+		}
+		if _, err := r.commit(r.repo.DefaultBranch, initialCommitMessage, files); err != nil {
+			return nil, err
+		}
+	}
+	s.repos[ref.String()] = r
+	return copyRepository(r.repo), nil
+}
+
+func (s *store) updateRepo(ref gitprovider.RepositoryRef, repo *Repository) (*Repository, error) {
+	var updated *Repository
+	return updated, s.withRepo(ref, func(r *repoState) error {
+		// Like on the real providers, the default branch must exist in non-empty repositories
+		if _, ok := r.branches[repo.DefaultBranch]; !ok && len(r.branches) != 0 {
+			return fmt.Errorf("branch %q: %w", repo.DefaultBranch, gitprovider.ErrNotFound)
+		}
+		// The name is part of the reference, and can't be changed
+		r.repo = copyRepository(repo)
+		r.repo.Name = ref.GetRepository()
+		updated = copyRepository(r.repo)
+		return nil
+	})
+}
+
+func (s *store) deleteRepo(ref gitprovider.RepositoryRef) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.repository(ref); err != nil {
+		return err
+	}
+	delete(s.repos, ref.String())
+	return nil
+}
+
+//
+// Deploy keys
+//
+
+func (s *store) listKeys(ref gitprovider.RepositoryRef) ([]*DeployKey, error) {
+	var keys []*DeployKey
+	return keys, s.withRepo(ref, func(r *repoState) error {
+		keys = make([]*DeployKey, 0, len(r.keys))
+		for _, k := range r.keys {
+			keys = append(keys, copyDeployKey(k))
+		}
+		return nil
+	})
+}
+
+func (s *store) createKey(ref gitprovider.RepositoryRef, key *DeployKey) (*DeployKey, error) {
+	var created *DeployKey
+	return created, s.withRepo(ref, func(r *repoState) error {
+		// Both the title and the key must be unique within the repository
+		for _, k := range r.keys {
+			if k.Title == key.Title || string(k.Key) == string(key.Key) {
+				return fmt.Errorf("deploy key %q: %w", key.Title, gitprovider.ErrAlreadyExists)
+			}
+		}
+		r.nextKeyID++
+		created = copyDeployKey(key)
+		created.ID = r.nextKeyID
+		r.keys = append(r.keys, created)
+		created = copyDeployKey(created)
+		return nil
+	})
+}
+
+func (s *store) deleteKey(ref gitprovider.RepositoryRef, id int64) error {
+	return s.withRepo(ref, func(r *repoState) error {
+		for i, k := range r.keys {
+			if k.ID == id {
+				r.keys = append(r.keys[:i], r.keys[i+1:]...)
+				return nil
+			}
+		}
+		return fmt.Errorf("deploy key %d: %w", id, gitprovider.ErrNotFound)
+	})
+}
+
+//
+// Webhooks
+//
+
+// withHooks runs fn with the webhooks of the repository or organization at ref.
+func (s *store) withHooks(ref gitprovider.IdentityRef, fn func(hooks *webhooks) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch ref := ref.(type) {
+	case gitprovider.RepositoryRef:
+		r, err := s.repository(ref)
+		if err != nil {
+			return err
+		}
+		return fn(&r.hooks)
+	case gitprovider.OrganizationRef:
+		o, err := s.org(ref)
+		if err != nil {
+			return err
+		}
+		return fn(&o.hooks)
+	}
+	return fmt.Errorf("unexpected reference %T: %w", ref, gitprovider.ErrInvalidArgument)
+}
+
+func (s *store) listHooks(ref gitprovider.IdentityRef) ([]*Webhook, error) {
+	var list []*Webhook
+	return list, s.withHooks(ref, func(hooks *webhooks) error {
+		list = make([]*Webhook, 0, len(hooks.list))
+		for _, wh := range hooks.list {
+			list = append(list, copyWebhook(wh))
+		}
+		return nil
+	})
+}
+
+func (s *store) createHook(ref gitprovider.IdentityRef, hook *Webhook) (*Webhook, error) {
+	var created *Webhook
+	return created, s.withHooks(ref, func(hooks *webhooks) error {
+		// Webhooks are identified by their URL
+		for _, wh := range hooks.list {
+			if wh.URL == hook.URL {
+				return fmt.Errorf("webhook %q: %w", hook.URL, gitprovider.ErrAlreadyExists)
+			}
+		}
+		hooks.nextID++
+		created = copyWebhook(hook)
+		created.ID = hooks.nextID
+		hooks.list = append(hooks.list, created)
+		created = copyWebhook(created)
+		return nil
+	})
+}
+
+func (s *store) updateHook(ref gitprovider.IdentityRef, hook *Webhook) (*Webhook, error) {
+	var updated *Webhook
+	return updated, s.withHooks(ref, func(hooks *webhooks) error {
+		for i, wh := range hooks.list {
+			if wh.ID != hook.ID {
+				continue
+			}
+			// The secret is only changed if it's set, as it isn't returned
+			secret := wh.Secret
+			hooks.list[i] = copyWebhook(hook)
+			if len(hook.Secret) == 0 {
+				hooks.list[i].Secret = secret
+			}
+			updated = copyWebhook(hooks.list[i])
+			return nil
+		}
+		return fmt.Errorf("webhook %d: %w", hook.ID, gitprovider.ErrNotFound)
+	})
+}
+
+func (s *store) deleteHook(ref gitprovider.IdentityRef, id int64) error {
+	return s.withHooks(ref, func(hooks *webhooks) error {
+		for i, wh := range hooks.list {
+			if wh.ID == id {
+				hooks.list = append(hooks.list[:i], hooks.list[i+1:]...)
+				return nil
+			}
+		}
+		return fmt.Errorf("webhook %d: %w", id, gitprovider.ErrNotFound)
+	})
+}
+
+//
+// Team access
+//
+
+func (s *store) listTeamAccess(ref gitprovider.OrgRepositoryRef) ([]*TeamAccess, error) {
+	var list []*TeamAccess
+	return list, s.withRepo(ref, func(r *repoState) error {
+		list = make([]*TeamAccess, 0, len(r.teams))
+		for _, ta := range r.teams {
+			access := *ta
+			list = append(list, &access)
+		}
+		sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+		return nil
+	})
+}
+
+// setTeamAccess adds or updates the access of a team. If create is true, ErrAlreadyExists is
+// returned if the team already has access, otherwise ErrNotFound is returned if it hasn't.
+func (s *store) setTeamAccess(ref gitprovider.OrgRepositoryRef, access *TeamAccess, create bool) (*TeamAccess, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r, err := s.repository(ref)
+	if err != nil {
+		return nil, err
+	}
+	// The team must exist in the organization owning the repository
+	o, err := s.org(ref.OrganizationRef)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := o.teams[access.Name]; !ok {
+		return nil, fmt.Errorf("team %q: %w", access.Name, gitprovider.ErrNotFound)
+	}
+
+	_, exists := r.teams[access.Name]
+	if create && exists {
+		return nil, fmt.Errorf("team access %q: %w", access.Name, gitprovider.ErrAlreadyExists)
+	}
+	if !create && !exists {
+		return nil, fmt.Errorf("team access %q: %w", access.Name, gitprovider.ErrNotFound)
+	}
+	stored := *access
+	r.teams[access.Name] = &stored
+	return &stored, nil
+}
+
+func (s *store) deleteTeamAccess(ref gitprovider.OrgRepositoryRef, name string) error {
+	return s.withRepo(ref, func(r *repoState) error {
+		if _, ok := r.teams[name]; !ok {
+			return fmt.Errorf("team access %q: %w", name, gitprovider.ErrNotFound)
+		}
+		delete(r.teams, name)
+		return nil
+	})
+}
+
+//
+// Commits, branches and pull requests
+//
+
+func (s *store) listCommits(ref gitprovider.RepositoryRef, branch string, perPage, page int) ([]*Commit, error) {
+	var commits []*Commit
+	return commits, s.withRepo(ref, func(r *repoState) error {
+		sha, ok := r.branches[branch]
+		if !ok {
+			return fmt.Errorf("branch %q: %w", branch, gitprovider.ErrNotFound)
+		}
+		if perPage <= 0 {
+			perPage = defaultPerPage
+		}
+		// Pages are 1-indexed, the zero page is the first one
+		if page < 1 {
+			page = 1
+		}
+		skip := (page - 1) * perPage
+
+		// Walk the history of the branch, newest first
+		commits = make([]*Commit, 0, perPage)
+		for i := 0; len(sha) != 0 && len(commits) < perPage; i++ {
+			c := r.commits[sha]
+			if i >= skip {
+				commits = append(commits, copyCommit(c))
+			}
+			sha = c.ParentSHA
+		}
+		return nil
+	})
+}
+
+// createCommit commits files on top of branch, whose head must be the expected parent of opts.
+func (s *store) createCommit(ref gitprovider.RepositoryRef, branch, message string, files []gitprovider.CommitFile, opts gitprovider.CommitCreateOptions) (*Commit, error) {
+	var commit *Commit
+	return commit, s.withRepo(ref, func(r *repoState) (err error) {
+		// New branches start at the head of the start branch, if given
+		parentSHA, ok := r.branches[branch]
+		if !ok && opts.StartBranch != nil {
+			if parentSHA, ok = r.branches[*opts.StartBranch]; !ok {
+				return fmt.Errorf("branch %q: %w", *opts.StartBranch, gitprovider.ErrNotFound)
+			}
+		}
+		if !ok && len(r.branches) != 0 {
+			return fmt.Errorf("branch %q: %w", branch, gitprovider.ErrNotFound)
+		}
+		// Empty repositories have no head to expect
+		if opts.ConflictsWith(parentSHA) {
+			return fmt.Errorf("branch %q is at %q, not %q: %w", branch, parentSHA, *opts.ExpectedParentSHA, gitprovider.ErrConflict)
+		}
+		commit, err = r.commitTree(branch, parentSHA, message, opts.Author, opts.Committer, func(tree *Commit) error {
+			return applyCommitFiles(tree, files)
+		})
+		return
+	})
+}
+
+// commit adds a commit with the given changes on top of branch. A nil content means the file is
+// deleted. If the repository is empty, a root commit is created on the branch.
+func (r *repoState) commit(branch, message string, changes map[string]*string) (*Commit, error) {
+	parentSHA, ok := r.branches[branch]
+	if !ok && len(r.branches) != 0 {
+		return nil, fmt.Errorf("branch %q: %w", branch, gitprovider.ErrNotFound)
+	}
+	return r.commitTree(branch, parentSHA, message, nil, nil, func(tree *Commit) error {
+		for path, content := range changes {
+			if content == nil {
+				if _, exists := tree.Files[path]; !exists {
+					return fmt.Errorf("file %q: %w", path, gitprovider.ErrNotFound)
+				}
+				delete(tree.Files, path)
+				delete(tree.Modes, path)
+				continue
+			}
+			tree.Files[path] = *content
+		}
+		return nil
+	})
+}
+
+// commitTree adds a commit with the given parent to branch, whose tree is the tree of the parent
+// commit after applying change to the Files and Modes of tree. An empty parentSHA creates a root
+// commit. The author and committer, if given, default to the current time as their date.
+func (r *repoState) commitTree(branch, parentSHA, message string, author, committer *gitprovider.CommitAuthor, change func(tree *Commit) error) (*Commit, error) {
+	tree := &Commit{Files: map[string]string{}, Modes: map[string]gitprovider.FileMode{}}
+	if len(parentSHA) != 0 {
+		tree = copyCommit(r.commits[parentSHA])
+	}
+	if err := change(tree); err != nil {
+		return nil, err
+	}
+
+	commit := &Commit{
+		TreeSHA:   hashTree(tree.Files, tree.Modes),
+		Message:   message,
+		ParentSHA: parentSHA,
+		Author:    commitIdentity(author),
+		Committer: commitIdentity(committer),
+		Files:     tree.Files,
+		Modes:     tree.Modes,
+	}
+	// Include the number of commits, so that identical commits get unique hashes
+	commit.SHA = hashString(fmt.Sprintf("tree %s\nparent %s\n%d\n\n%s", commit.TreeSHA, parentSHA, len(r.commits), message))
+	commit.WebURL = fmt.Sprintf("%s/commit/%s", r.ref.String(), commit.SHA)
+	r.commits[commit.SHA] = commit
+	r.branches[branch] = commit.SHA
+	return copyCommit(commit), nil
+}
+
+// commitIdentity returns a copy of the given author or committer, dated now if it has no date.
+func commitIdentity(identity *gitprovider.CommitAuthor) *gitprovider.CommitAuthor {
+	if identity == nil {
+		return nil
+	}
+	out := *identity
+	if out.Date.IsZero() {
+		out.Date = time.Now().UTC()
+	}
+	return &out
+}
+
+// applyCommitFiles applies the actions of the given (validated) files to the Files and Modes of tree.
+func applyCommitFiles(tree *Commit, files []gitprovider.CommitFile) error {
+	for _, file := range files {
+		path := *file.Path
+		_, exists := tree.Files[path]
+		switch file.GetAction() {
+		case gitprovider.CommitFileActionCreate:
+			if exists {
+				return fmt.Errorf("file %q: %w", path, gitprovider.ErrAlreadyExists)
+			}
+		case gitprovider.CommitFileActionUpdate:
+			if !exists {
+				return fmt.Errorf("file %q: %w", path, gitprovider.ErrNotFound)
+			}
+		case gitprovider.CommitFileActionDelete:
+			if !exists {
+				return fmt.Errorf("file %q: %w", path, gitprovider.ErrNotFound)
+			}
+			delete(tree.Files, path)
+			delete(tree.Modes, path)
+			continue
+		case gitprovider.CommitFileActionMove:
+			previousPath := *file.PreviousPath
+			if _, ok := tree.Files[previousPath]; !ok {
+				return fmt.Errorf("file %q: %w", previousPath, gitprovider.ErrNotFound)
+			}
+			if exists && path != previousPath {
+				return fmt.Errorf("file %q: %w", path, gitprovider.ErrAlreadyExists)
+			}
+			content, mode := tree.Files[previousPath], tree.Modes[previousPath]
+			delete(tree.Files, previousPath)
+			delete(tree.Modes, previousPath)
+			tree.Files[path] = content
+			if len(mode) != 0 {
+				tree.Modes[path] = mode
+			}
+		}
+
+		// The content was validated before
+		content, _ := file.GetContent()
+		if content != nil {
+			tree.Files[path] = string(content)
+		}
+		switch {
+		case file.Mode == nil:
+		case *file.Mode == gitprovider.FileModeRegular:
+			delete(tree.Modes, path)
+		default:
+			tree.Modes[path] = *file.Mode
+		}
+	}
+	return nil
+}
+
+// resolve returns the commit a branch name, tag name or commit sha refers to.
+func (r *repoState) resolve(ref string) (*Commit, error) {
+	if sha, ok := r.branches[ref]; ok {
+		return r.commits[sha], nil
+	}
+	if t, ok := r.tags[ref]; ok {
+		return r.commits[t.SHA], nil
+	}
+	if c, ok := r.commits[ref]; ok {
+		return c, nil
+	}
+	return nil, fmt.Errorf("ref %q: %w", ref, gitprovider.ErrNotFound)
+}
+
+// getFile returns the file at path in the tree of the commit gitRef refers to.
+func (s *store) getFile(ref gitprovider.RepositoryRef, path, gitRef string) (*File, error) {
+	var file *File
+	return file, s.withRepo(ref, func(r *repoState) error {
+		c, err := r.resolve(gitRef)
+		if err != nil {
+			return err
+		}
+		content, ok := c.Files[path]
+		if !ok {
+			return fmt.Errorf("file %q: %w", path, gitprovider.ErrNotFound)
+		}
+		file = fileEntry(c, path)
+		file.Content = []byte(content)
+		return nil
+	})
+}
+
+// listFiles returns the files and directories in the directory at dirPath in the tree of the
+// commit gitRef refers to, sorted by path.
+func (s *store) listFiles(ref gitprovider.RepositoryRef, dirPath, gitRef string) ([]*File, error) {
+	var files []*File
+	return files, s.withRepo(ref, func(r *repoState) error {
+		c, err := r.resolve(gitRef)
+		if err != nil {
+			return err
+		}
+		prefix := strings.Trim(dirPath, "/")
+		if len(prefix) != 0 {
+			prefix += "/"
+		}
+
+		// Directories only exist implicitly, as the common prefixes of the paths of files
+		dirs := map[string]bool{}
+		for path := range c.Files {
+			if !strings.HasPrefix(path, prefix) {
+				continue
+			}
+			rest := strings.TrimPrefix(path, prefix)
+			if i := strings.Index(rest, "/"); i >= 0 {
+				dir := prefix + rest[:i]
+				if !dirs[dir] {
+					dirs[dir] = true
+					files = append(files, dirEntry(c, dir))
+				}
+				continue
+			}
+			files = append(files, fileEntry(c, path))
+		}
+		if len(files) == 0 && len(prefix) != 0 {
+			return fmt.Errorf("directory %q: %w", dirPath, gitprovider.ErrNotFound)
+		}
+		sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+		return nil
+	})
+}
+
+// fileEntry returns the existing file at path in the tree of c, without its content.
+func fileEntry(c *Commit, path string) *File {
+	mode, ok := c.Modes[path]
+	if !ok {
+		mode = gitprovider.FileModeRegular
+	}
+	return &File{
+		Path: path,
+		Mode: mode,
+		SHA:  hashString(c.Files[path]),
+		Size: int64(len(c.Files[path])),
+	}
+}
+
+// dirEntry returns the directory at dir in the tree of c, whose hash is the hash of its subtree.
+func dirEntry(c *Commit, dir string) *File {
+	files := map[string]string{}
+	modes := map[string]gitprovider.FileMode{}
+	for path, content := range c.Files {
+		if rest := strings.TrimPrefix(path, dir+"/"); rest != path {
+			files[rest] = content
+			if mode, ok := c.Modes[path]; ok {
+				modes[rest] = mode
+			}
+		}
+	}
+	return &File{
+		Path: dir,
+		Mode: gitprovider.FileModeDirectory,
+		SHA:  hashTree(files, modes),
+	}
+}
+
+func (s *store) createBranch(ref gitprovider.RepositoryRef, branch, sha string) error {
+	return s.withRepo(ref, func(r *repoState) error {
+		if _, ok := r.branches[branch]; ok {
+			return fmt.Errorf("branch %q: %w", branch, gitprovider.ErrAlreadyExists)
+		}
+		if _, ok := r.commits[sha]; !ok {
+			return fmt.Errorf("commit %q: %w", sha, gitprovider.ErrNotFound)
+		}
+		r.branches[branch] = sha
+		return nil
+	})
+}
+
+func (s *store) getBranch(ref gitprovider.RepositoryRef, branch string) (*Branch, error) {
+	var b *Branch
+	return b, s.withRepo(ref, func(r *repoState) error {
+		sha, ok := r.branches[branch]
+		if !ok {
+			return fmt.Errorf("branch %q: %w", branch, gitprovider.ErrNotFound)
+		}
+		b = &Branch{Name: branch, SHA: sha, Protected: r.protected[branch] != nil}
+		return nil
+	})
+}
+
+// listBranches returns the branches of the repository, sorted by name.
+func (s *store) listBranches(ref gitprovider.RepositoryRef) ([]*Branch, error) {
+	var branches []*Branch
+	return branches, s.withRepo(ref, func(r *repoState) error {
+		branches = make([]*Branch, 0, len(r.branches))
+		for name, sha := range r.branches {
+			branches = append(branches, &Branch{Name: name, SHA: sha, Protected: r.protected[name] != nil})
+		}
+		sort.Slice(branches, func(i, j int) bool { return branches[i].Name < branches[j].Name })
+		return nil
+	})
+}
+
+// createBranchFrom creates branch at the head of the branch from.
+func (s *store) createBranchFrom(ref gitprovider.RepositoryRef, branch, from string) (*Branch, error) {
+	var b *Branch
+	return b, s.withRepo(ref, func(r *repoState) error {
+		sha, ok := r.branches[from]
+		if !ok {
+			return fmt.Errorf("branch %q: %w", from, gitprovider.ErrNotFound)
+		}
+		if _, ok := r.branches[branch]; ok {
+			return fmt.Errorf("branch %q: %w", branch, gitprovider.ErrAlreadyExists)
+		}
+		r.branches[branch] = sha
+		b = &Branch{Name: branch, SHA: sha}
+		return nil
+	})
+}
+
+func (s *store) deleteBranch(ref gitprovider.RepositoryRef, branch string) error {
+	return s.withRepo(ref, func(r *repoState) error {
+		if _, ok := r.branches[branch]; !ok {
+			return fmt.Errorf("branch %q: %w", branch, gitprovider.ErrNotFound)
+		}
+		// Like on GitHub, the protection rules are deleted with the branch
+		delete(r.branches, branch)
+		delete(r.protected, branch)
+		return nil
+	})
+}
+
+func (s *store) getBranchProtection(ref gitprovider.RepositoryRef, branch string) (*BranchProtection, error) {
+	var bp *BranchProtection
+	return bp, s.withRepo(ref, func(r *repoState) error {
+		stored, ok := r.protected[branch]
+		if !ok {
+			return fmt.Errorf("branch protection %q: %w", branch, gitprovider.ErrNotFound)
+		}
+		bp = copyBranchProtection(stored)
+		return nil
+	})
+}
+
+// setBranchProtection creates or replaces the protection rules of an existing branch.
+func (s *store) setBranchProtection(ref gitprovider.RepositoryRef, bp *BranchProtection) (*BranchProtection, error) {
+	var updated *BranchProtection
+	return updated, s.withRepo(ref, func(r *repoState) error {
+		if _, ok := r.branches[bp.Branch]; !ok {
+			return fmt.Errorf("branch %q: %w", bp.Branch, gitprovider.ErrNotFound)
+		}
+		r.protected[bp.Branch] = copyBranchProtection(bp)
+		updated = copyBranchProtection(bp)
+		return nil
+	})
+}
+
+func (s *store) deleteBranchProtection(ref gitprovider.RepositoryRef, branch string) error {
+	return s.withRepo(ref, func(r *repoState) error {
+		if _, ok := r.protected[branch]; !ok {
+			return fmt.Errorf("branch protection %q: %w", branch, gitprovider.ErrNotFound)
+		}
+		delete(r.protected, branch)
+		return nil
+	})
+}
+
+func (s *store) createPullRequest(ref gitprovider.RepositoryRef, pr *PullRequest) (*PullRequest, error) {
+	var created *PullRequest
+	return created, s.withRepo(ref, func(r *repoState) error {
+		for _, branch := range []string{pr.Head, pr.Base} {
+			if _, ok := r.branches[branch]; !ok {
+				return fmt.Errorf("branch %q: %w", branch, gitprovider.ErrNotFound)
+			}
+		}
+		stored := copyPullRequest(pr)
+		stored.Number = len(r.prs) + 1
+		stored.State = gitprovider.PullRequestStateOpen
+		stored.CreatedAt = time.Now().UTC()
+		stored.UpdatedAt = stored.CreatedAt
+		stored.WebURL = fmt.Sprintf("%s/pull/%d", ref.String(), stored.Number)
+		r.prs = append(r.prs, stored)
+		created = copyPullRequest(stored)
+		return nil
+	})
+}
+
+func (s *store) getPullRequest(ref gitprovider.RepositoryRef, number int) (*PullRequest, error) {
+	var pr *PullRequest
+	return pr, s.withRepo(ref, func(r *repoState) error {
+		stored, err := r.pullRequest(number)
+		if err != nil {
+			return err
+		}
+		pr = copyPullRequest(stored)
+		return nil
+	})
+}
+
+// listPullRequests returns the pull requests of the repository, sorted by number.
+func (s *store) listPullRequests(ref gitprovider.RepositoryRef) ([]*PullRequest, error) {
+	var prs []*PullRequest
+	return prs, s.withRepo(ref, func(r *repoState) error {
+		prs = make([]*PullRequest, 0, len(r.prs))
+		for _, stored := range r.prs {
+			prs = append(prs, copyPullRequest(stored))
+		}
+		return nil
+	})
+}
+
+// updatePullRequest applies fn to the stored pull request with the given number. fn returning
+// an error leaves the pull request unchanged.
+func (s *store) updatePullRequest(ref gitprovider.RepositoryRef, number int, fn func(r *repoState, pr *PullRequest) error) (*PullRequest, error) {
+	var updated *PullRequest
+	return updated, s.withRepo(ref, func(r *repoState) error {
+		stored, err := r.pullRequest(number)
+		if err != nil {
+			return err
+		}
+		pr := copyPullRequest(stored)
+		if err := fn(r, pr); err != nil {
+			return err
+		}
+		pr.UpdatedAt = time.Now().UTC()
+		*stored = *pr
+		updated = copyPullRequest(pr)
+		return nil
+	})
+}
+
+// pullRequest returns the stored pull request with the given number.
+func (r *repoState) pullRequest(number int) (*PullRequest, error) {
+	if number < 1 || number > len(r.prs) {
+		return nil, fmt.Errorf("pull request %d: %w", number, gitprovider.ErrNotFound)
+	}
+	return r.prs[number-1], nil
+}
+
+// listPullRequestComments returns the comments of the pull request, oldest first.
+func (s *store) listPullRequestComments(ref gitprovider.RepositoryRef, number int) ([]*PullRequestComment, error) {
+	var comments []*PullRequestComment
+	return comments, s.withRepo(ref, func(r *repoState) error {
+		if _, err := r.pullRequest(number); err != nil {
+			return err
+		}
+		comments = []*PullRequestComment{}
+		for _, c := range r.comments {
+			if c.PullRequest == number {
+				comment := *c
+				comments = append(comments, &comment)
+			}
+		}
+		return nil
+	})
+}
+
+func (s *store) createPullRequestComment(ref gitprovider.RepositoryRef, number int, body string) (*PullRequestComment, error) {
+	var created *PullRequestComment
+	return created, s.withRepo(ref, func(r *repoState) error {
+		if _, err := r.pullRequest(number); err != nil {
+			return err
+		}
+		r.nextCommentID++
+		now := time.Now().UTC()
+		stored := &PullRequestComment{
+			ID:          r.nextCommentID,
+			PullRequest: number,
+			Body:        body,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		r.comments = append(r.comments, stored)
+		comment := *stored
+		created = &comment
+		return nil
+	})
+}
+
+// updatePullRequestComment changes the body of the comment with the given ID, which must belong
+// to the pull request.
+func (s *store) updatePullRequestComment(ref gitprovider.RepositoryRef, number int, id int64, body string) (*PullRequestComment, error) {
+	var updated *PullRequestComment
+	return updated, s.withRepo(ref, func(r *repoState) error {
+		for _, c := range r.comments {
+			if c.ID == id && c.PullRequest == number {
+				c.Body = body
+				c.UpdatedAt = time.Now().UTC()
+				comment := *c
+				updated = &comment
+				return nil
+			}
+		}
+		return fmt.Errorf("comment %d: %w", id, gitprovider.ErrNotFound)
+	})
+}
+
+func (s *store) deletePullRequestComment(ref gitprovider.RepositoryRef, number int, id int64) error {
+	return s.withRepo(ref, func(r *repoState) error {
+		for i, c := range r.comments {
+			if c.ID == id && c.PullRequest == number {
+				r.comments = append(r.comments[:i], r.comments[i+1:]...)
+				return nil
+			}
+		}
+		return fmt.Errorf("comment %d: %w", id, gitprovider.ErrNotFound)
+	})
+}
+
+// listPullRequestReviews returns the reviews of the pull request, oldest first.
+func (s *store) listPullRequestReviews(ref gitprovider.RepositoryRef, number int) ([]*PullRequestReview, error) {
+	var reviews []*PullRequestReview
+	return reviews, s.withRepo(ref, func(r *repoState) error {
+		if _, err := r.pullRequest(number); err != nil {
+			return err
+		}
+		reviews = []*PullRequestReview{}
+		for _, rv := range r.reviews {
+			if rv.PullRequest == number {
+				review := *rv
+				reviews = append(reviews, &review)
+			}
+		}
+		return nil
+	})
+}
+
+func (s *store) createPullRequestReview(ref gitprovider.RepositoryRef, number int, state gitprovider.PullRequestReviewState, body string) (*PullRequestReview, error) {
+	var created *PullRequestReview
+	return created, s.withRepo(ref, func(r *repoState) error {
+		if _, err := r.pullRequest(number); err != nil {
+			return err
+		}
+		r.nextReviewID++
+		stored := &PullRequestReview{
+			ID:          r.nextReviewID,
+			PullRequest: number,
+			State:       state,
+			Body:        body,
+			SubmittedAt: time.Now().UTC(),
+		}
+		r.reviews = append(r.reviews, stored)
+		review := *stored
+		created = &review
+		return nil
+	})
+}
+
+// merge commits the changes made on head since it diverged from base onto base. Changes made on
+// both branches are resolved in favor of head, as the fake doesn't model conflicts.
+func (r *repoState) merge(head, base, message string) (*Commit, error) {
+	headSHA, ok := r.branches[head]
+	if !ok {
+		return nil, fmt.Errorf("branch %q: %w", head, gitprovider.ErrNotFound)
+	}
+	baseSHA, ok := r.branches[base]
+	if !ok {
+		return nil, fmt.Errorf("branch %q: %w", base, gitprovider.ErrNotFound)
+	}
+
+	// Find the merge base, i.e. the first commit of base which is also in the history of head
+	ancestors := map[string]bool{}
+	for sha := headSHA; len(sha) != 0; sha = r.commits[sha].ParentSHA {
+		ancestors[sha] = true
+	}
+	mergeBase := baseSHA
+	for len(mergeBase) != 0 && !ancestors[mergeBase] {
+		mergeBase = r.commits[mergeBase].ParentSHA
+	}
+	mergeBaseFiles := map[string]string{}
+	if len(mergeBase) != 0 {
+		mergeBaseFiles = r.commits[mergeBase].Files
+	}
+
+	headFiles := r.commits[headSHA].Files
+	changes := map[string]*string{}
+	for path, content := range headFiles {
+		if old, ok := mergeBaseFiles[path]; !ok || old != content {
+			content := content
+			changes[path] = &content
+		}
+	}
+	baseFiles := r.commits[baseSHA].Files
+	for path := range mergeBaseFiles {
+		if _, ok := headFiles[path]; !ok {
+			// Only delete files which still exist on base
+			if _, ok := baseFiles[path]; ok {
+				changes[path] = nil
+			}
+		}
+	}
+	return r.commit(base, message, changes)
+}
+
+//
+// Tags and releases
+//
+
+func (s *store) getTag(ref gitprovider.RepositoryRef, name string) (*Tag, error) {
+	var tag *Tag
+	return tag, s.withRepo(ref, func(r *repoState) error {
+		t, ok := r.tags[name]
+		if !ok {
+			return fmt.Errorf("tag %q: %w", name, gitprovider.ErrNotFound)
+		}
+		stored := *t
+		tag = &stored
+		return nil
+	})
+}
+
+// listTags returns the tags of the repository, sorted by name.
+func (s *store) listTags(ref gitprovider.RepositoryRef) ([]*Tag, error) {
+	var tags []*Tag
+	return tags, s.withRepo(ref, func(r *repoState) error {
+		tags = make([]*Tag, 0, len(r.tags))
+		for _, t := range r.tags {
+			tag := *t
+			tags = append(tags, &tag)
+		}
+		sort.Slice(tags, func(i, j int) bool { return tags[i].Name < tags[j].Name })
+		return nil
+	})
+}
+
+func (s *store) createTag(ref gitprovider.RepositoryRef, tag *Tag) (*Tag, error) {
+	var created *Tag
+	return created, s.withRepo(ref, func(r *repoState) error {
+		if _, ok := r.commits[tag.SHA]; !ok {
+			return fmt.Errorf("commit %q: %w", tag.SHA, gitprovider.ErrNotFound)
+		}
+		if err := r.createTag(tag); err != nil {
+			return err
+		}
+		stored := *tag
+		created = &stored
+		return nil
+	})
+}
+
+// createTag stores a copy of tag, which must point to an existing commit.
+func (r *repoState) createTag(tag *Tag) error {
+	if _, ok := r.tags[tag.Name]; ok {
+		return fmt.Errorf("tag %q: %w", tag.Name, gitprovider.ErrAlreadyExists)
+	}
+	stored := *tag
+	r.tags[tag.Name] = &stored
+	return nil
+}
+
+func (s *store) deleteTag(ref gitprovider.RepositoryRef, name string) error {
+	return s.withRepo(ref, func(r *repoState) error {
+		if _, ok := r.tags[name]; !ok {
+			return fmt.Errorf("tag %q: %w", name, gitprovider.ErrNotFound)
+		}
+		delete(r.tags, name)
+		// Like on GitHub, the release of the tag is kept as a draft
+		if rel, err := r.release(name); err == nil {
+			rel.Draft = true
+		}
+		return nil
+	})
+}
+
+func (s *store) getRelease(ref gitprovider.RepositoryRef, tagName string) (*Release, error) {
+	var rel *Release
+	return rel, s.withRepo(ref, func(r *repoState) error {
+		stored, err := r.release(tagName)
+		if err != nil {
+			return err
+		}
+		release := *stored
+		rel = &release
+		return nil
+	})
+}
+
+// listReleases returns the releases of the repository, in the order they were created.
+func (s *store) listReleases(ref gitprovider.RepositoryRef) ([]*Release, error) {
+	var releases []*Release
+	return releases, s.withRepo(ref, func(r *repoState) error {
+		releases = make([]*Release, 0, len(r.releases))
+		for _, stored := range r.releases {
+			release := *stored
+			releases = append(releases, &release)
+		}
+		return nil
+	})
+}
+
+// createRelease stores a copy of rel. The tag of the release is created from rel.TargetCommitish,
+// or the default branch if that's empty, if it doesn't exist.
+func (s *store) createRelease(ref gitprovider.RepositoryRef, rel *Release) (*Release, error) {
+	var created *Release
+	return created, s.withRepo(ref, func(r *repoState) error {
+		if _, err := r.release(rel.TagName); err == nil {
+			return fmt.Errorf("release %q: %w", rel.TagName, gitprovider.ErrAlreadyExists)
+		}
+		stored := *rel
+		if len(stored.TargetCommitish) == 0 {
+			stored.TargetCommitish = r.repo.DefaultBranch
+		}
+		if _, ok := r.tags[stored.TagName]; !ok {
+			c, err := r.resolve(stored.TargetCommitish)
+			if err != nil {
+				return err
+			}
+			if err := r.createTag(&Tag{Name: stored.TagName, SHA: c.SHA}); err != nil {
+				return err
+			}
+		}
+		stored.CreatedAt = time.Now().UTC()
+		stored.WebURL = fmt.Sprintf("%s/releases/tag/%s", ref.String(), stored.TagName)
+		r.releases = append(r.releases, &stored)
+		release := stored
+		created = &release
+		return nil
+	})
+}
+
+// updateRelease changes the name, body, and draft and prerelease state of the release of the
+// tag rel.TagName to the ones of rel.
+func (s *store) updateRelease(ref gitprovider.RepositoryRef, rel *Release) (*Release, error) {
+	var updated *Release
+	return updated, s.withRepo(ref, func(r *repoState) error {
+		stored, err := r.release(rel.TagName)
+		if err != nil {
+			return err
+		}
+		stored.Name = rel.Name
+		stored.Body = rel.Body
+		stored.Draft = rel.Draft
+		stored.Prerelease = rel.Prerelease
+		release := *stored
+		updated = &release
+		return nil
+	})
+}
+
+func (s *store) deleteRelease(ref gitprovider.RepositoryRef, tagName string) error {
+	return s.withRepo(ref, func(r *repoState) error {
+		for i, rel := range r.releases {
+			if rel.TagName == tagName {
+				r.releases = append(r.releases[:i], r.releases[i+1:]...)
+				delete(r.assets, tagName)
+				return nil
+			}
+		}
+		return fmt.Errorf("release %q: %w", tagName, gitprovider.ErrNotFound)
+	})
+}
+
+// release returns the stored release of the tag with the given name.
+func (r *repoState) release(tagName string) (*Release, error) {
+	for _, rel := range r.releases {
+		if rel.TagName == tagName {
+			return rel, nil
+		}
+	}
+	return nil, fmt.Errorf("release %q: %w", tagName, gitprovider.ErrNotFound)
+}
+
+// listReleaseAssets returns the assets of the release, in the order they were uploaded, without
+// their content.
+func (s *store) listReleaseAssets(ref gitprovider.RepositoryRef, tagName string) ([]*ReleaseAsset, error) {
+	var assets []*ReleaseAsset
+	return assets, s.withRepo(ref, func(r *repoState) error {
+		if _, err := r.release(tagName); err != nil {
+			return err
+		}
+		assets = make([]*ReleaseAsset, 0, len(r.assets[tagName]))
+		for _, a := range r.assets[tagName] {
+			asset := *a
+			asset.Content = nil
+			assets = append(assets, &asset)
+		}
+		return nil
+	})
+}
+
+func (s *store) getReleaseAsset(ref gitprovider.RepositoryRef, tagName, name string) (*ReleaseAsset, error) {
+	var asset *ReleaseAsset
+	return asset, s.withRepo(ref, func(r *repoState) error {
+		if _, err := r.release(tagName); err != nil {
+			return err
+		}
+		for _, a := range r.assets[tagName] {
+			if a.Name == name {
+				asset = copyReleaseAsset(a)
+				return nil
+			}
+		}
+		return fmt.Errorf("release asset %q: %w", name, gitprovider.ErrNotFound)
+	})
+}
+
+func (s *store) createReleaseAsset(ref gitprovider.RepositoryRef, tagName string, asset *ReleaseAsset) (*ReleaseAsset, error) {
+	var created *ReleaseAsset
+	return created, s.withRepo(ref, func(r *repoState) error {
+		if _, err := r.release(tagName); err != nil {
+			return err
+		}
+		for _, a := range r.assets[tagName] {
+			if a.Name == asset.Name {
+				return fmt.Errorf("release asset %q: %w", asset.Name, gitprovider.ErrAlreadyExists)
+			}
+		}
+		stored := copyReleaseAsset(asset)
+		stored.Size = int64(len(stored.Content))
+		stored.DownloadURL = fmt.Sprintf("%s/releases/download/%s/%s", ref.String(), tagName, asset.Name)
+		r.assets[tagName] = append(r.assets[tagName], stored)
+		created = copyReleaseAsset(stored)
+		created.Content = nil
+		return nil
+	})
+}
+
+func (s *store) deleteReleaseAsset(ref gitprovider.RepositoryRef, tagName, name string) error {
+	return s.withRepo(ref, func(r *repoState) error {
+		if _, err := r.release(tagName); err != nil {
+			return err
+		}
+		for i, a := range r.assets[tagName] {
+			if a.Name == name {
+				r.assets[tagName] = append(r.assets[tagName][:i], r.assets[tagName][i+1:]...)
+				return nil
+			}
+		}
+		return fmt.Errorf("release asset %q: %w", name, gitprovider.ErrNotFound)
+	})
+}
+
+//
+// Commit statuses
+//
+
+// listCommitStatuses returns the statuses of the commit, newest first.
+func (s *store) listCommitStatuses(ref gitprovider.RepositoryRef, sha string) ([]*CommitStatus, error) {
+	var statuses []*CommitStatus
+	return statuses, s.withRepo(ref, func(r *repoState) error {
+		if _, ok := r.commits[sha]; !ok {
+			return fmt.Errorf("commit %q: %w", sha, gitprovider.ErrNotFound)
+		}
+		stored := r.statuses[sha]
+		statuses = make([]*CommitStatus, 0, len(stored))
+		for i := len(stored) - 1; i >= 0; i-- {
+			status := *stored[i]
+			statuses = append(statuses, &status)
+		}
+		return nil
+	})
+}
+
+func (s *store) createCommitStatus(ref gitprovider.RepositoryRef, status *CommitStatus) (*CommitStatus, error) {
+	var created *CommitStatus
+	return created, s.withRepo(ref, func(r *repoState) error {
+		if _, ok := r.commits[status.SHA]; !ok {
+			return fmt.Errorf("commit %q: %w", status.SHA, gitprovider.ErrNotFound)
+		}
+		r.nextStatusID++
+		stored := *status
+		stored.ID = r.nextStatusID
+		stored.CreatedAt = time.Now().UTC()
+		r.statuses[status.SHA] = append(r.statuses[status.SHA], &stored)
+		out := stored
+		created = &out
+		return nil
+	})
+}
+
+//
+// Issues
+//
+
+func (s *store) createIssue(ref gitprovider.RepositoryRef, issue *Issue) (*Issue, error) {
+	var created *Issue
+	return created, s.withRepo(ref, func(r *repoState) error {
+		stored := copyIssue(issue)
+		stored.Number = len(r.issues) + 1
+		stored.State = gitprovider.IssueStateOpen
+		stored.CreatedAt = time.Now().UTC()
+		stored.UpdatedAt = stored.CreatedAt
+		stored.ClosedAt = time.Time{}
+		stored.WebURL = fmt.Sprintf("%s/issues/%d", ref.String(), stored.Number)
+		r.issues = append(r.issues, stored)
+		created = copyIssue(stored)
+		return nil
+	})
+}
+
+func (s *store) getIssue(ref gitprovider.RepositoryRef, number int) (*Issue, error) {
+	var issue *Issue
+	return issue, s.withRepo(ref, func(r *repoState) error {
+		stored, err := r.issue(number)
+		if err != nil {
+			return err
+		}
+		issue = copyIssue(stored)
+		return nil
+	})
+}
+
+// listIssues returns the issues of the repository, sorted by number.
+func (s *store) listIssues(ref gitprovider.RepositoryRef) ([]*Issue, error) {
+	var issues []*Issue
+	return issues, s.withRepo(ref, func(r *repoState) error {
+		issues = make([]*Issue, 0, len(r.issues))
+		for _, stored := range r.issues {
+			issues = append(issues, copyIssue(stored))
+		}
+		return nil
+	})
+}
+
+// updateIssue applies fn to the stored issue with the given number. fn returning an error leaves
+// the issue unchanged.
+func (s *store) updateIssue(ref gitprovider.RepositoryRef, number int, fn func(issue *Issue) error) (*Issue, error) {
+	var updated *Issue
+	return updated, s.withRepo(ref, func(r *repoState) error {
+		stored, err := r.issue(number)
+		if err != nil {
+			return err
+		}
+		issue := copyIssue(stored)
+		if err := fn(issue); err != nil {
+			return err
+		}
+		issue.UpdatedAt = time.Now().UTC()
+		*stored = *issue
+		updated = copyIssue(issue)
+		return nil
+	})
+}
+
+// issue returns the stored issue with the given number.
+func (r *repoState) issue(number int) (*Issue, error) {
+	if number < 1 || number > len(r.issues) {
+		return nil, fmt.Errorf("issue %d: %w", number, gitprovider.ErrNotFound)
+	}
+	return r.issues[number-1], nil
+}
+
+// listIssueComments returns the comments of the issue, oldest first.
+func (s *store) listIssueComments(ref gitprovider.RepositoryRef, number int) ([]*IssueComment, error) {
+	var comments []*IssueComment
+	return comments, s.withRepo(ref, func(r *repoState) error {
+		if _, err := r.issue(number); err != nil {
+			return err
+		}
+		comments = []*IssueComment{}
+		for _, c := range r.issueComments {
+			if c.Issue == number {
+				comment := *c
+				comments = append(comments, &comment)
+			}
+		}
+		return nil
+	})
+}
+
+func (s *store) createIssueComment(ref gitprovider.RepositoryRef, number int, body string) (*IssueComment, error) {
+	var created *IssueComment
+	return created, s.withRepo(ref, func(r *repoState) error {
+		if _, err := r.issue(number); err != nil {
+			return err
+		}
+		r.nextIssueCommentID++
+		now := time.Now().UTC()
+		stored := &IssueComment{
+			ID:        r.nextIssueCommentID,
+			Issue:     number,
+			Body:      body,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		r.issueComments = append(r.issueComments, stored)
+		comment := *stored
+		created = &comment
+		return nil
+	})
+}
+
+// updateIssueComment changes the body of the comment with the given ID, which must belong to the
+// issue.
+func (s *store) updateIssueComment(ref gitprovider.RepositoryRef, number int, id int64, body string) (*IssueComment, error) {
+	var updated *IssueComment
+	return updated, s.withRepo(ref, func(r *repoState) error {
+		for _, c := range r.issueComments {
+			if c.ID == id && c.Issue == number {
+				c.Body = body
+				c.UpdatedAt = time.Now().UTC()
+				comment := *c
+				updated = &comment
+				return nil
+			}
+		}
+		return fmt.Errorf("comment %d: %w", id, gitprovider.ErrNotFound)
+	})
+}
+
+func (s *store) deleteIssueComment(ref gitprovider.RepositoryRef, number int, id int64) error {
+	return s.withRepo(ref, func(r *repoState) error {
+		for i, c := range r.issueComments {
+			if c.ID == id && c.Issue == number {
+				r.issueComments = append(r.issueComments[:i], r.issueComments[i+1:]...)
+				return nil
+			}
+		}
+		return fmt.Errorf("comment %d: %w", id, gitprovider.ErrNotFound)
+	})
+}
+
+// hashTree returns a fake, but deterministic, tree hash for the given files.
+func hashTree(files map[string]string, modes map[string]gitprovider.FileMode) string {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var b strings.Builder
+	for _, path := range paths {
+		mode, ok := modes[path]
+		if !ok {
+			mode = gitprovider.FileModeRegular
+		}
+		fmt.Fprintf(&b, "%s %s %s\n", mode, hashString(files[path]), path)
+	}
+	return hashString(b.String())
+}
+
+// hashString returns a hex-encoded hash of s, of the same length as a Git SHA-1 hash.
+func hashString(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])[:40]
+}
+
+// parentRef returns the reference to the parent of the given sub-organization.
+func parentRef(ref gitprovider.OrganizationRef) gitprovider.OrganizationRef {
+	parent := ref
+	parent.SubOrganizations = ref.SubOrganizations[:len(ref.SubOrganizations)-1]
+	return parent
+}
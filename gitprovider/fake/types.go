@@ -0,0 +1,387 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fake
+
+import (
+	"time"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// The types in this file are the "API objects" of the fake provider, returned by the APIObject()
+// methods of its resources. They are copied in and out of the in-memory state, so modifying them
+// doesn't change the state until e.g. Update() is called.
+
+// Organization is the in-memory representation of an organization.
+type Organization struct {
+	// Name is the human-friendly name of the organization.
+	Name string
+	// Description is the description of the organization.
+	Description string
+}
+
+// Team is the in-memory representation of a team in an organization.
+type Team struct {
+	// Name is the name of the team.
+	Name string
+	// Members are the logins of the members of the team.
+	Members []string
+}
+
+// Repository is the in-memory representation of a repository.
+type Repository struct {
+	// Name is the name of the repository.
+	Name string
+	// Description is the optional description of the repository.
+	Description *string
+	// DefaultBranch is the name of the default branch.
+	DefaultBranch string
+	// Visibility is the visibility of the repository.
+	Visibility gitprovider.RepositoryVisibility
+}
+
+// DeployKey is the in-memory representation of a deploy key.
+type DeployKey struct {
+	// ID is the unique ID of the deploy key within the repository.
+	ID int64
+	// Title is the name of the deploy key.
+	Title string
+	// Key is the public key.
+	Key []byte
+	// ReadOnly specifies whether the key only has pull access.
+	ReadOnly bool
+}
+
+// Webhook is the in-memory representation of a webhook of a repository or organization.
+type Webhook struct {
+	// ID is the unique ID of the webhook within the repository or organization.
+	ID int64
+	// URL is the URL the events are delivered to.
+	URL string
+	// ContentType specifies how the payloads are encoded.
+	ContentType gitprovider.WebhookContentType
+	// Secret is the secret of the webhook, which isn't returned by Webhook.Get().
+	Secret string
+	// Events are the kinds of events triggering the webhook.
+	Events []gitprovider.WebhookEvent
+	// SSLVerification specifies whether the TLS certificate of URL is verified.
+	SSLVerification bool
+	// Active specifies whether events are delivered.
+	Active bool
+}
+
+// TeamAccess is the in-memory representation of a team's access to a repository.
+type TeamAccess struct {
+	// Name is the name of the team.
+	Name string
+	// Permission is the permission level of the team.
+	Permission gitprovider.RepositoryPermission
+}
+
+// Commit is the in-memory representation of a commit.
+type Commit struct {
+	// SHA is the (fake) hash of the commit.
+	SHA string
+	// TreeSHA is the (fake) hash of the tree of the commit.
+	TreeSHA string
+	// Message is the commit message.
+	Message string
+	// ParentSHA is the hash of the parent commit, or empty for a root commit.
+	ParentSHA string
+	// Author is the author of the commit, or nil if it wasn't given when committing.
+	Author *gitprovider.CommitAuthor
+	// Committer is the committer of the commit, or nil if it wasn't given when committing.
+	Committer *gitprovider.CommitAuthor
+	// WebURL is the URL of the commit in the (fake) web UI.
+	WebURL string
+	// Files holds the full content of the tree of the commit, by path.
+	Files map[string]string
+	// Modes holds the mode of the files which aren't regular files, by path.
+	Modes map[string]gitprovider.FileMode
+}
+
+// File is the in-memory representation of a file or directory in the tree of a commit.
+type File struct {
+	// Path is the path of the file, relative to the root of the repository.
+	Path string
+	// Mode is the git mode of the file.
+	Mode gitprovider.FileMode
+	// SHA is the (fake) hash of the content of a file, or of the tree of a directory.
+	SHA string
+	// Size is the size of the content of a file in bytes.
+	Size int64
+	// Content is the content of a file, which is only set when getting a single file.
+	Content []byte
+}
+
+// Branch is the in-memory representation of a branch.
+type Branch struct {
+	// Name is the name of the branch.
+	Name string
+	// SHA is the hash of the commit at the head of the branch.
+	SHA string
+	// Protected is true if the branch has protection rules.
+	Protected bool
+}
+
+// BranchProtection is the in-memory representation of the protection rules of a branch.
+type BranchProtection struct {
+	// Branch is the name of the protected branch.
+	Branch string
+	// RequiredApprovals is the number of approvals a pull request needs to be merged.
+	RequiredApprovals int
+	// RequiredStatusChecks are the contexts of the statuses that must succeed.
+	RequiredStatusChecks []string
+	// DismissStaleReviews specifies whether approvals are dismissed by new commits.
+	DismissStaleReviews bool
+	// RequireCodeOwnerReviews specifies whether code owners need to approve their files.
+	RequireCodeOwnerReviews bool
+	// RestrictPushes specifies whether only privileged users may push.
+	RestrictPushes bool
+	// AllowForcePushes specifies whether force-pushes are allowed.
+	AllowForcePushes bool
+	// AllowDeletions specifies whether the branch may be deleted.
+	AllowDeletions bool
+}
+
+// PullRequest is the in-memory representation of a pull request.
+type PullRequest struct {
+	// Number is the number of the pull request, unique within the repository.
+	Number int
+	// Title is the title of the pull request.
+	Title string
+	// Description is the description of the pull request.
+	Description string
+	// Head is the name of the branch containing the changes.
+	Head string
+	// Base is the name of the branch the changes are merged into.
+	Base string
+	// State is the state of the pull request.
+	State gitprovider.PullRequestState
+	// Labels are the names of the labels of the pull request.
+	Labels []string
+	// CreatedAt is the time the pull request was created.
+	CreatedAt time.Time
+	// UpdatedAt is the time the pull request was last changed.
+	UpdatedAt time.Time
+	// MergedAt is the time the pull request was merged, or zero.
+	MergedAt time.Time
+	// ClosedAt is the time the pull request was closed or merged, or zero.
+	ClosedAt time.Time
+	// WebURL is the URL of the pull request.
+	WebURL string
+}
+
+// PullRequestComment is the in-memory representation of a pull request comment.
+type PullRequestComment struct {
+	// ID is the identifier of the comment, unique within the repository.
+	ID int64
+	// PullRequest is the number of the pull request the comment belongs to.
+	PullRequest int
+	// Body is the text of the comment.
+	Body string
+	// CreatedAt is the time the comment was created.
+	CreatedAt time.Time
+	// UpdatedAt is the time the comment was last changed.
+	UpdatedAt time.Time
+}
+
+// PullRequestReview is the in-memory representation of a pull request review.
+type PullRequestReview struct {
+	// ID is the identifier of the review, unique within the repository.
+	ID int64
+	// PullRequest is the number of the pull request the review belongs to.
+	PullRequest int
+	// State is the verdict of the review.
+	State gitprovider.PullRequestReviewState
+	// Body is the text of the review.
+	Body string
+	// SubmittedAt is the time the review was submitted.
+	SubmittedAt time.Time
+}
+
+// Tag is the in-memory representation of a tag.
+type Tag struct {
+	// Name is the name of the tag.
+	Name string
+	// SHA is the hash of the commit the tag points to.
+	SHA string
+	// Message is the message of an annotated tag, or empty for a lightweight tag.
+	Message string
+}
+
+// Release is the in-memory representation of a release.
+type Release struct {
+	// TagName is the name of the tag of the release, unique within the repository.
+	TagName string
+	// TargetCommitish is the branch or commit the tag was created from.
+	TargetCommitish string
+	// Name is the title of the release.
+	Name string
+	// Body is the description of the release.
+	Body string
+	// Draft specifies whether the release is unpublished.
+	Draft bool
+	// Prerelease specifies whether the release is marked as not ready for production.
+	Prerelease bool
+	// CreatedAt is the time the release was created.
+	CreatedAt time.Time
+	// WebURL is the URL of the release.
+	WebURL string
+}
+
+// ReleaseAsset is the in-memory representation of a file attached to a release.
+type ReleaseAsset struct {
+	// Name is the file name of the asset, unique within the release.
+	Name string
+	// ContentType is the media type of the asset, derived from the extension of its name.
+	ContentType string
+	// Size is the size of the content of the asset in bytes.
+	Size int64
+	// Content is the content of the asset, which is only set when downloading the asset.
+	Content []byte
+	// DownloadURL is the URL of the asset.
+	DownloadURL string
+}
+
+// CommitStatus is the in-memory representation of a status of a commit.
+type CommitStatus struct {
+	// ID is the identifier of the status, unique within the repository.
+	ID int64
+	// SHA is the hash of the commit the status belongs to.
+	SHA string
+	// State is the state of the check.
+	State gitprovider.CommitStatusState
+	// Context identifies the check among the statuses of the commit.
+	Context string
+	// Description is a short description of the status.
+	Description string
+	// TargetURL is the URL with details about the check.
+	TargetURL string
+	// CreatedAt is the time the status was created.
+	CreatedAt time.Time
+}
+
+// Issue is the in-memory representation of an issue.
+type Issue struct {
+	// Number is the number of the issue, unique within the repository.
+	Number int
+	// Title is the title of the issue.
+	Title string
+	// Body is the description of the issue.
+	Body string
+	// State is the state of the issue.
+	State gitprovider.IssueState
+	// Labels are the names of the labels of the issue.
+	Labels []string
+	// Assignees are the logins of the users the issue is assigned to.
+	Assignees []string
+	// CreatedAt is the time the issue was created.
+	CreatedAt time.Time
+	// UpdatedAt is the time the issue was last changed.
+	UpdatedAt time.Time
+	// ClosedAt is the time the issue was last closed, or zero if it's open.
+	ClosedAt time.Time
+	// WebURL is the URL of the issue.
+	WebURL string
+}
+
+// IssueComment is the in-memory representation of an issue comment.
+type IssueComment struct {
+	// ID is the identifier of the comment, unique within the repository.
+	ID int64
+	// Issue is the number of the issue the comment belongs to.
+	Issue int
+	// Body is the text of the comment.
+	Body string
+	// CreatedAt is the time the comment was created.
+	CreatedAt time.Time
+	// UpdatedAt is the time the comment was last changed.
+	UpdatedAt time.Time
+}
+
+func copyTeam(t *Team) *Team {
+	out := *t
+	out.Members = append([]string(nil), t.Members...)
+	return &out
+}
+
+func copyRepository(r *Repository) *Repository {
+	out := *r
+	if r.Description != nil {
+		out.Description = gitprovider.StringVar(*r.Description)
+	}
+	return &out
+}
+
+func copyDeployKey(k *DeployKey) *DeployKey {
+	out := *k
+	out.Key = append([]byte(nil), k.Key...)
+	return &out
+}
+
+func copyWebhook(wh *Webhook) *Webhook {
+	out := *wh
+	out.Events = append([]gitprovider.WebhookEvent(nil), wh.Events...)
+	return &out
+}
+
+func copyBranchProtection(bp *BranchProtection) *BranchProtection {
+	out := *bp
+	out.RequiredStatusChecks = append([]string(nil), bp.RequiredStatusChecks...)
+	return &out
+}
+
+func copyPullRequest(pr *PullRequest) *PullRequest {
+	out := *pr
+	out.Labels = append([]string(nil), pr.Labels...)
+	return &out
+}
+
+func copyIssue(i *Issue) *Issue {
+	out := *i
+	out.Labels = append([]string(nil), i.Labels...)
+	out.Assignees = append([]string(nil), i.Assignees...)
+	return &out
+}
+
+func copyCommit(c *Commit) *Commit {
+	out := *c
+	if c.Author != nil {
+		author := *c.Author
+		out.Author = &author
+	}
+	if c.Committer != nil {
+		committer := *c.Committer
+		out.Committer = &committer
+	}
+	out.Files = make(map[string]string, len(c.Files))
+	for path, content := range c.Files {
+		out.Files[path] = content
+	}
+	out.Modes = make(map[string]gitprovider.FileMode, len(c.Modes))
+	for path, mode := range c.Modes {
+		out.Modes[path] = mode
+	}
+	return &out
+}
+
+func copyReleaseAsset(a *ReleaseAsset) *ReleaseAsset {
+	out := *a
+	out.Content = append([]byte(nil), a.Content...)
+	return &out
+}
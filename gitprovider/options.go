@@ -77,3 +77,278 @@ func (opts *RepositoryCreateOptions) ValidateOptions() error {
 	}
 	return errs.Error()
 }
+
+// MakePullRequestListOptions returns a PullRequestListOptions based off the mutator functions
+// given to PullRequestClient.List(). validation.ErrFieldEnumInvalid is returned if the state
+// doesn't match known values.
+func MakePullRequestListOptions(opts ...PullRequestListOption) (PullRequestListOptions, error) {
+	o := &PullRequestListOptions{}
+	for _, opt := range opts {
+		opt.ApplyToPullRequestListOptions(o)
+	}
+	return *o, o.ValidateOptions()
+}
+
+// PullRequestListOption is an interface for applying options to when listing pull requests.
+type PullRequestListOption interface {
+	// ApplyToPullRequestListOptions should apply relevant options to the target.
+	ApplyToPullRequestListOptions(target *PullRequestListOptions)
+}
+
+// PullRequestListOptions specifies optional filters when listing pull requests.
+type PullRequestListOptions struct {
+	// State only lists the pull requests in the given state.
+	// Default: nil (which means "all states")
+	State *PullRequestState
+
+	// HeadBranch only lists the pull requests with the given head branch.
+	// Default: nil (which means "any branch")
+	HeadBranch *string
+
+	// BaseBranch only lists the pull requests with the given base branch.
+	// Default: nil (which means "any branch")
+	BaseBranch *string
+}
+
+// ApplyToPullRequestListOptions applies the options defined in the options struct to the
+// target struct that is being completed.
+func (opts *PullRequestListOptions) ApplyToPullRequestListOptions(target *PullRequestListOptions) {
+	// Go through each field in opts, and apply it to target if set
+	if opts.State != nil {
+		target.State = opts.State
+	}
+	if opts.HeadBranch != nil {
+		target.HeadBranch = opts.HeadBranch
+	}
+	if opts.BaseBranch != nil {
+		target.BaseBranch = opts.BaseBranch
+	}
+}
+
+// ValidateOptions validates that the options are valid.
+func (opts *PullRequestListOptions) ValidateOptions() error {
+	errs := validation.New("PullRequestListOptions")
+	if opts.State != nil {
+		errs.Append(ValidatePullRequestState(*opts.State), *opts.State, "State")
+	}
+	return errs.Error()
+}
+
+// Matches returns whether the pull request matches the filters, for providers filtering
+// client-side.
+func (opts *PullRequestListOptions) Matches(info PullRequestInfo) bool {
+	if opts.State != nil && *opts.State != info.State {
+		return false
+	}
+	if opts.HeadBranch != nil && *opts.HeadBranch != info.HeadBranch {
+		return false
+	}
+	if opts.BaseBranch != nil && *opts.BaseBranch != info.BaseBranch {
+		return false
+	}
+	return true
+}
+
+// MakeIssueListOptions returns an IssueListOptions based off the mutator functions given to
+// IssueClient.List(). validation.ErrFieldEnumInvalid is returned if the state doesn't match
+// known values.
+func MakeIssueListOptions(opts ...IssueListOption) (IssueListOptions, error) {
+	o := &IssueListOptions{}
+	for _, opt := range opts {
+		opt.ApplyToIssueListOptions(o)
+	}
+	return *o, o.ValidateOptions()
+}
+
+// IssueListOption is an interface for applying options to when listing issues.
+type IssueListOption interface {
+	// ApplyToIssueListOptions should apply relevant options to the target.
+	ApplyToIssueListOptions(target *IssueListOptions)
+}
+
+// IssueListOptions specifies optional filters when listing issues.
+type IssueListOptions struct {
+	// State only lists the issues in the given state.
+	// Default: nil (which means "all states")
+	State *IssueState
+
+	// Labels only lists the issues that have all of the given labels.
+	// Default: nil (which means "any labels")
+	Labels []string
+
+	// Assignee only lists the issues assigned to the user with the given login.
+	// Default: nil (which means "any assignee, or none")
+	Assignee *string
+}
+
+// ApplyToIssueListOptions applies the options defined in the options struct to the target
+// struct that is being completed.
+func (opts *IssueListOptions) ApplyToIssueListOptions(target *IssueListOptions) {
+	// Go through each field in opts, and apply it to target if set
+	if opts.State != nil {
+		target.State = opts.State
+	}
+	if opts.Labels != nil {
+		target.Labels = opts.Labels
+	}
+	if opts.Assignee != nil {
+		target.Assignee = opts.Assignee
+	}
+}
+
+// ValidateOptions validates that the options are valid.
+func (opts *IssueListOptions) ValidateOptions() error {
+	errs := validation.New("IssueListOptions")
+	if opts.State != nil {
+		errs.Append(ValidateIssueState(*opts.State), *opts.State, "State")
+	}
+	return errs.Error()
+}
+
+// Matches returns whether the issue matches the filters, for providers filtering client-side.
+func (opts *IssueListOptions) Matches(info IssueInfo) bool {
+	if opts.State != nil && *opts.State != info.State {
+		return false
+	}
+	for _, label := range opts.Labels {
+		if !containsString(info.Labels, label) {
+			return false
+		}
+	}
+	if opts.Assignee != nil && !containsString(info.Assignees, *opts.Assignee) {
+		return false
+	}
+	return true
+}
+
+// containsString returns whether list contains str.
+func containsString(list []string, str string) bool {
+	for _, item := range list {
+		if item == str {
+			return true
+		}
+	}
+	return false
+}
+
+// MakeCommitCreateOptions returns a CommitCreateOptions based off the mutator functions
+// given to CommitClient.Create(). validation.ErrFieldInvalid is returned if the expected
+// parent SHA or the start branch is empty, and validation.ErrFieldRequired if the name or
+// email of the author or committer is missing.
+func MakeCommitCreateOptions(opts ...CommitCreateOption) (CommitCreateOptions, error) {
+	o := &CommitCreateOptions{}
+	for _, opt := range opts {
+		opt.ApplyToCommitCreateOptions(o)
+	}
+	return *o, o.ValidateOptions()
+}
+
+// CommitCreateOption is an interface for applying options to when creating commits.
+type CommitCreateOption interface {
+	// ApplyToCommitCreateOptions should apply relevant options to the target.
+	ApplyToCommitCreateOptions(target *CommitCreateOptions)
+}
+
+// CommitCreateOptions specifies optional options when creating a commit.
+type CommitCreateOptions struct {
+	// ExpectedParentSHA is the SHA of the commit the head of the branch is expected to be at.
+	// If the branch is at another commit, e.g. because of a concurrent push, ErrConflict is
+	// returned instead of creating the commit.
+	// Default: nil (which means "the current head of the branch")
+	ExpectedParentSHA *string
+
+	// StartBranch is the branch to create the branch from, if it doesn't exist. The new branch
+	// starts at the head of StartBranch, which is then the expected parent of the commit.
+	// It is ignored if the branch exists.
+	// Default: nil (which means the branch must exist, unless the repository is empty)
+	StartBranch *string
+
+	// Author is the author of the commit. Name and Email are required.
+	// Default: nil (which means the authenticated user, at the time of the commit)
+	Author *CommitAuthor
+
+	// Committer is the committer of the commit. Name and Email are required.
+	// Default: nil (which means the authenticated user, at the time of the commit)
+	Committer *CommitAuthor
+}
+
+// ApplyToCommitCreateOptions applies the options defined in the options struct to the
+// target struct that is being completed.
+func (opts *CommitCreateOptions) ApplyToCommitCreateOptions(target *CommitCreateOptions) {
+	// Go through each field in opts, and apply it to target if set
+	if opts.ExpectedParentSHA != nil {
+		target.ExpectedParentSHA = opts.ExpectedParentSHA
+	}
+	if opts.StartBranch != nil {
+		target.StartBranch = opts.StartBranch
+	}
+	if opts.Author != nil {
+		target.Author = opts.Author
+	}
+	if opts.Committer != nil {
+		target.Committer = opts.Committer
+	}
+}
+
+// ValidateOptions validates that the options are valid.
+func (opts *CommitCreateOptions) ValidateOptions() error {
+	errs := validation.New("CommitCreateOptions")
+	if opts.ExpectedParentSHA != nil && len(*opts.ExpectedParentSHA) == 0 {
+		errs.Invalid(*opts.ExpectedParentSHA, "ExpectedParentSHA")
+	}
+	if opts.StartBranch != nil && len(*opts.StartBranch) == 0 {
+		errs.Invalid(*opts.StartBranch, "StartBranch")
+	}
+	if opts.Author != nil {
+		validateCommitAuthor(errs, opts.Author, "Author")
+	}
+	if opts.Committer != nil {
+		validateCommitAuthor(errs, opts.Committer, "Committer")
+	}
+	return errs.Error()
+}
+
+// validateCommitAuthor registers the missing fields of the identity at fieldPath with errs.
+func validateCommitAuthor(errs validation.Validator, identity *CommitAuthor, fieldPath string) {
+	if len(identity.Name) == 0 {
+		errs.Required(fieldPath + ".Name")
+	}
+	if len(identity.Email) == 0 {
+		errs.Required(fieldPath + ".Email")
+	}
+}
+
+// ConflictsWith returns true if ExpectedParentSHA is set, and the given head of the branch
+// isn't that commit.
+func (opts *CommitCreateOptions) ConflictsWith(head string) bool {
+	return opts.ExpectedParentSHA != nil && *opts.ExpectedParentSHA != head
+}
+
+// PullRequestUpdateOptions specifies the fields to change when updating a pull request.
+// nil fields are left unchanged.
+type PullRequestUpdateOptions struct {
+	// Title is the new title of the pull request.
+	Title *string
+	// Description is the new description of the pull request.
+	Description *string
+	// BaseBranch is the new base branch of the pull request.
+	BaseBranch *string
+	// Labels replaces the labels of the pull request, if non-nil. An empty, non-nil slice
+	// removes all labels.
+	Labels []string
+}
+
+// IssueUpdateOptions specifies the fields to change when updating an issue.
+// nil fields are left unchanged.
+type IssueUpdateOptions struct {
+	// Title is the new title of the issue.
+	Title *string
+	// Body is the new description of the issue.
+	Body *string
+	// Labels replaces the labels of the issue, if non-nil. An empty, non-nil slice removes
+	// all labels.
+	Labels []string
+	// Assignees replaces the logins of the assignees of the issue, if non-nil. An empty,
+	// non-nil slice removes all assignees.
+	Assignees []string
+}
@@ -93,3 +93,212 @@ func TestMakeRepositoryCreateOptions(t *testing.T) {
 		})
 	}
 }
+
+func TestMakePullRequestListOptions(t *testing.T) {
+	unknownState := PullRequestState("foo")
+	tests := []struct {
+		name        string
+		opts        []PullRequestListOption
+		want        PullRequestListOptions
+		expectedErr error
+	}{
+		{
+			name: "default nil pointers",
+			want: PullRequestListOptions{},
+		},
+		{
+			name: "latter overrides former",
+			opts: []PullRequestListOption{
+				&PullRequestListOptions{State: PullRequestStateVar(PullRequestStateOpen), HeadBranch: StringVar("feature")},
+				&PullRequestListOptions{State: PullRequestStateVar(PullRequestStateMerged)},
+			},
+			want: PullRequestListOptions{State: PullRequestStateVar(PullRequestStateMerged), HeadBranch: StringVar("feature")},
+		},
+		{
+			name:        "invalid state",
+			opts:        []PullRequestListOption{&PullRequestListOptions{State: &unknownState}},
+			want:        PullRequestListOptions{State: &unknownState},
+			expectedErr: validation.ErrFieldEnumInvalid,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MakePullRequestListOptions(tt.opts...)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("MakePullRequestListOptions() error = %v, wanted %v", err, tt.expectedErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MakePullRequestListOptions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMakeIssueListOptions(t *testing.T) {
+	unknownState := IssueState("merged")
+	tests := []struct {
+		name        string
+		opts        []IssueListOption
+		want        IssueListOptions
+		expectedErr error
+	}{
+		{
+			name: "default nil pointers",
+			want: IssueListOptions{},
+		},
+		{
+			name: "latter overrides former",
+			opts: []IssueListOption{
+				&IssueListOptions{State: IssueStateVar(IssueStateOpen), Labels: []string{"drift"}},
+				&IssueListOptions{State: IssueStateVar(IssueStateClosed), Assignee: StringVar("octocat")},
+			},
+			want: IssueListOptions{State: IssueStateVar(IssueStateClosed), Labels: []string{"drift"}, Assignee: StringVar("octocat")},
+		},
+		{
+			name:        "invalid state",
+			opts:        []IssueListOption{&IssueListOptions{State: &unknownState}},
+			want:        IssueListOptions{State: &unknownState},
+			expectedErr: validation.ErrFieldEnumInvalid,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MakeIssueListOptions(tt.opts...)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("MakeIssueListOptions() error = %v, wanted %v", err, tt.expectedErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MakeIssueListOptions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIssueListOptions_Matches(t *testing.T) {
+	info := IssueInfo{
+		Title:     "Drift detected",
+		State:     IssueStateOpen,
+		Labels:    []string{"drift", "prod"},
+		Assignees: []string{"octocat"},
+	}
+	tests := []struct {
+		name string
+		opts IssueListOptions
+		want bool
+	}{
+		{name: "no filters", want: true},
+		{name: "matching filters", opts: IssueListOptions{State: IssueStateVar(IssueStateOpen), Labels: []string{"prod", "drift"}, Assignee: StringVar("octocat")}, want: true},
+		{name: "other state", opts: IssueListOptions{State: IssueStateVar(IssueStateClosed)}, want: false},
+		{name: "missing label", opts: IssueListOptions{Labels: []string{"drift", "policy"}}, want: false},
+		{name: "other assignee", opts: IssueListOptions{Assignee: StringVar("monalisa")}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.Matches(info); got != tt.want {
+				t.Errorf("IssueListOptions.Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMakeCommitCreateOptions(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        []CommitCreateOption
+		want        CommitCreateOptions
+		expectedErr error
+	}{
+		{
+			name: "default nil pointers",
+			want: CommitCreateOptions{},
+		},
+		{
+			name: "latter overrides former",
+			opts: []CommitCreateOption{
+				&CommitCreateOptions{ExpectedParentSHA: StringVar("a")},
+				&CommitCreateOptions{ExpectedParentSHA: StringVar("b")},
+			},
+			want: CommitCreateOptions{ExpectedParentSHA: StringVar("b")},
+		},
+		{
+			name: "fields are merged",
+			opts: []CommitCreateOption{
+				&CommitCreateOptions{ExpectedParentSHA: StringVar("a")},
+				&CommitCreateOptions{StartBranch: StringVar("main")},
+			},
+			want: CommitCreateOptions{ExpectedParentSHA: StringVar("a"), StartBranch: StringVar("main")},
+		},
+		{
+			name:        "empty expected parent",
+			opts:        []CommitCreateOption{&CommitCreateOptions{ExpectedParentSHA: StringVar("")}},
+			want:        CommitCreateOptions{ExpectedParentSHA: StringVar("")},
+			expectedErr: validation.ErrFieldInvalid,
+		},
+		{
+			name: "author and committer",
+			opts: []CommitCreateOption{&CommitCreateOptions{
+				Author:    &CommitAuthor{Name: "Jane", Email: "jane@example.com"},
+				Committer: &CommitAuthor{Name: "Bot", Email: "bot@example.com"},
+			}},
+			want: CommitCreateOptions{
+				Author:    &CommitAuthor{Name: "Jane", Email: "jane@example.com"},
+				Committer: &CommitAuthor{Name: "Bot", Email: "bot@example.com"},
+			},
+		},
+		{
+			name:        "author without email",
+			opts:        []CommitCreateOption{&CommitCreateOptions{Author: &CommitAuthor{Name: "Jane"}}},
+			want:        CommitCreateOptions{Author: &CommitAuthor{Name: "Jane"}},
+			expectedErr: validation.ErrFieldRequired,
+		},
+		{
+			name:        "empty start branch",
+			opts:        []CommitCreateOption{&CommitCreateOptions{StartBranch: StringVar("")}},
+			want:        CommitCreateOptions{StartBranch: StringVar("")},
+			expectedErr: validation.ErrFieldInvalid,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MakeCommitCreateOptions(tt.opts...)
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("MakeCommitCreateOptions() error = %v, wanted %v", err, tt.expectedErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MakeCommitCreateOptions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommitCreateOptions_ConflictsWith(t *testing.T) {
+	if (&CommitCreateOptions{}).ConflictsWith("a") {
+		t.Error("ConflictsWith() without an expected parent: expected no conflict")
+	}
+	opts := &CommitCreateOptions{ExpectedParentSHA: StringVar("a")}
+	if opts.ConflictsWith("a") || !opts.ConflictsWith("b") {
+		t.Errorf("ConflictsWith() = %v, %v, want a conflict with another head only", opts.ConflictsWith("a"), opts.ConflictsWith("b"))
+	}
+}
+
+func TestPullRequestListOptions_Matches(t *testing.T) {
+	info := PullRequestInfo{State: PullRequestStateOpen, HeadBranch: "feature", BaseBranch: "main"}
+	tests := []struct {
+		name string
+		opts PullRequestListOptions
+		want bool
+	}{
+		{"no filters", PullRequestListOptions{}, true},
+		{"matching filters", PullRequestListOptions{State: PullRequestStateVar(PullRequestStateOpen), HeadBranch: StringVar("feature"), BaseBranch: StringVar("main")}, true},
+		{"other state", PullRequestListOptions{State: PullRequestStateVar(PullRequestStateMerged)}, false},
+		{"other head branch", PullRequestListOptions{HeadBranch: StringVar("other")}, false},
+		{"other base branch", PullRequestListOptions{BaseBranch: StringVar("other")}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.Matches(info); got != tt.want {
+				t.Errorf("Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gitprovider
+
+import "time"
+
+// Quota describes the API request quota of a client, as reported by the Git provider.
+type Quota struct {
+	// The number of requests the client is limited to in the current window.
+	Limit int `json:"limit"`
+	// The number of requests the client can still make in the current window.
+	Remaining int `json:"remaining"`
+	// The timestamp at which point the current window ends, and the quota is reset.
+	Reset time.Time `json:"reset"`
+}
@@ -0,0 +1,244 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ratelimit implements a client-side rate limiter for Git provider APIs. The Limiter
+// observes the rate limit headers of GitHub and GitLab responses, and throttles requests using a
+// token bucket, spreading the remaining quota until its reset, before the limit is hit.
+//
+// A Limiter is safe for concurrent use, and can be shared by all clients using the same token.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+const (
+	// DefaultBurst is the default value of Options.Burst.
+	DefaultBurst = 10
+
+	// resetDeltaThreshold separates reset headers holding a number of seconds, as per the IETF
+	// RateLimit header fields draft, from those holding a UNIX timestamp, like GitHub and GitLab.
+	resetDeltaThreshold = 1000000000
+)
+
+// ErrQuotaExhausted is returned when the quota won't allow a request before the context deadline.
+var ErrQuotaExhausted = errors.New("rate limit quota exhausted")
+
+// Options configures a Limiter. Zero values are replaced by their defaults.
+type Options struct {
+	// Burst is the maximum number of requests sent at once, if the quota allows it.
+	// Default: DefaultBurst
+	Burst int
+
+	// Reserve is the number of requests of the quota left for other users of the token, e.g. to
+	// keep interactive use possible. Requests wait for the reset once only Reserve requests remain.
+	// Default: 0
+	Reserve int
+}
+
+// Limiter throttles requests according to the quota reported by the provider.
+type Limiter struct {
+	opts     Options
+	throttle bool
+	now      func() time.Time
+
+	mu    sync.Mutex
+	known bool
+	// quota is the last observed quota, with Remaining decremented for every request sent since
+	quota  gitprovider.Quota
+	tokens float64
+	last   time.Time
+}
+
+// NewLimiter creates a Limiter throttling requests.
+func NewLimiter(opts Options) *Limiter {
+	if opts.Burst <= 0 {
+		opts.Burst = DefaultBurst
+	}
+	if opts.Reserve < 0 {
+		opts.Reserve = 0
+	}
+	return &Limiter{opts: opts, throttle: true, now: time.Now}
+}
+
+// NewObserver creates a Limiter only observing the quota, without throttling requests.
+func NewObserver() *Limiter {
+	l := NewLimiter(Options{})
+	l.throttle = false
+	return l
+}
+
+// bypassKey is the context key marking requests which aren't throttled.
+type bypassKey struct{}
+
+// WithoutThrottling returns a copy of ctx, marking requests sent with it to bypass the wait for the
+// Limiter, e.g. to request the quota while it's exhausted. Their responses are still observed.
+func WithoutThrottling(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassKey{}, true)
+}
+
+// Quota returns the current quota, and whether it has been observed yet.
+func (l *Limiter) Quota() (gitprovider.Quota, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.quota, l.known
+}
+
+// Transport is a gitprovider.ChainableRoundTripperFunc, returning a RoundTripper which waits for
+// the Limiter before sending requests, and observes the rate limit headers of the responses.
+func (l *Limiter) Transport(in http.RoundTripper) http.RoundTripper {
+	if in == nil {
+		in = http.DefaultTransport
+	}
+	return &limiterRoundtripper{l: l, transport: in}
+}
+
+// Wait blocks until the quota allows sending a request. ErrQuotaExhausted is returned if this
+// isn't possible before the deadline of ctx.
+func (l *Limiter) Wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		wait := l.take(l.now())
+		l.mu.Unlock()
+		if wait == 0 {
+			return nil
+		}
+
+		if deadline, ok := ctx.Deadline(); ok && l.now().Add(wait).After(deadline) {
+			return fmt.Errorf("request would wait %s: %w", wait, ErrQuotaExhausted)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// take takes a token from the bucket, or returns how long to wait before trying again.
+// The bucket is refilled so that the requests remaining above the reserve are spread evenly
+// until the reset. l.mu must be held.
+func (l *Limiter) take(now time.Time) time.Duration {
+	if !l.known || !now.Before(l.quota.Reset) {
+		// Nothing is known about the current window
+		return 0
+	}
+	if !l.throttle {
+		l.quota.Remaining--
+		return 0
+	}
+
+	untilReset := l.quota.Reset.Sub(now)
+	available := l.quota.Remaining - l.opts.Reserve
+	if available <= 0 {
+		return untilReset
+	}
+	rate := float64(available) / untilReset.Seconds()
+
+	l.tokens += now.Sub(l.last).Seconds() * rate
+	if burst := float64(l.opts.Burst); l.tokens > burst {
+		l.tokens = burst
+	}
+	l.last = now
+
+	if l.tokens >= 1 {
+		l.tokens--
+		l.quota.Remaining--
+		return 0
+	}
+	wait := time.Duration((1 - l.tokens) / rate * float64(time.Second))
+	if wait <= 0 {
+		wait = time.Millisecond
+	}
+	return wait
+}
+
+// observe updates the quota from the rate limit headers of a response, i.e. X-RateLimit-* for
+// GitHub, and RateLimit-* for GitLab. Responses for other GitHub resources than the core API,
+// e.g. search, are ignored, as they have their own quota.
+func (l *Limiter) observe(header http.Header, now time.Time) {
+	if resource := header.Get("X-RateLimit-Resource"); resource != "" && resource != "core" {
+		return
+	}
+
+	for _, prefix := range []string{"X-RateLimit-", "RateLimit-"} {
+		remaining, err := strconv.Atoi(header.Get(prefix + "Remaining"))
+		if err != nil {
+			continue
+		}
+		reset, err := strconv.ParseInt(header.Get(prefix+"Reset"), 10, 64)
+		if err != nil {
+			continue
+		}
+		// The limit is informational only, so tolerate its absence
+		limit, _ := strconv.Atoi(header.Get(prefix + "Limit"))
+
+		quota := gitprovider.Quota{Limit: limit, Remaining: remaining, Reset: time.Unix(reset, 0)}
+		if reset < resetDeltaThreshold {
+			quota.Reset = now.Add(time.Duration(reset) * time.Second)
+		}
+
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if !l.known {
+			l.known = true
+			l.tokens = float64(l.opts.Burst)
+			l.last = now
+		}
+		// Responses to concurrent requests may arrive out of order, so within the same window, the
+		// remaining quota only decreases
+		if quota.Reset.Equal(l.quota.Reset) && quota.Remaining > l.quota.Remaining {
+			quota.Remaining = l.quota.Remaining
+		}
+		l.quota = quota
+		return
+	}
+}
+
+// limiterRoundtripper throttles requests using a Limiter.
+type limiterRoundtripper struct {
+	l         *Limiter
+	transport http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (rt *limiterRoundtripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if bypass, _ := req.Context().Value(bypassKey{}).(bool); !bypass {
+		if err := rt.l.Wait(req.Context()); err != nil {
+			// As per the http.RoundTripper contract, the body must be closed even on errors
+			if req.Body != nil {
+				_ = req.Body.Close()
+			}
+			return nil, err
+		}
+	}
+	resp, err := rt.transport.RoundTrip(req)
+	if resp != nil {
+		rt.l.observe(resp.Header, rt.l.now())
+	}
+	return resp, err
+}
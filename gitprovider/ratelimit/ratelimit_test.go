@@ -0,0 +1,218 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+var epoch = time.Unix(1600000000, 0)
+
+func TestLimiter_observe(t *testing.T) {
+	tests := []struct {
+		name      string
+		header    http.Header
+		want      gitprovider.Quota
+		wantKnown bool
+	}{
+		{
+			name:   "none",
+			header: http.Header{},
+		},
+		{
+			name:      "github",
+			header:    http.Header{"X-Ratelimit-Limit": {"5000"}, "X-Ratelimit-Remaining": {"4999"}, "X-Ratelimit-Reset": {"1600003600"}, "X-Ratelimit-Resource": {"core"}},
+			want:      gitprovider.Quota{Limit: 5000, Remaining: 4999, Reset: epoch.Add(time.Hour)},
+			wantKnown: true,
+		},
+		{
+			name:   "github search",
+			header: http.Header{"X-Ratelimit-Limit": {"30"}, "X-Ratelimit-Remaining": {"29"}, "X-Ratelimit-Reset": {"1600000060"}, "X-Ratelimit-Resource": {"search"}},
+		},
+		{
+			name:      "gitlab",
+			header:    http.Header{"Ratelimit-Limit": {"2000"}, "Ratelimit-Remaining": {"1990"}, "Ratelimit-Reset": {"1600000060"}, "Ratelimit-Observed": {"10"}},
+			want:      gitprovider.Quota{Limit: 2000, Remaining: 1990, Reset: epoch.Add(time.Minute)},
+			wantKnown: true,
+		},
+		{
+			name:      "reset delta",
+			header:    http.Header{"Ratelimit-Limit": {"100"}, "Ratelimit-Remaining": {"50"}, "Ratelimit-Reset": {"30"}},
+			want:      gitprovider.Quota{Limit: 100, Remaining: 50, Reset: epoch.Add(30 * time.Second)},
+			wantKnown: true,
+		},
+		{
+			name:   "missing reset",
+			header: http.Header{"X-Ratelimit-Limit": {"5000"}, "X-Ratelimit-Remaining": {"4999"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := NewLimiter(Options{})
+			l.observe(tt.header, epoch)
+			got, known := l.Quota()
+			if known != tt.wantKnown || got != tt.want {
+				t.Errorf("Quota() = %+v, %v, want %+v, %v", got, known, tt.want, tt.wantKnown)
+			}
+		})
+	}
+}
+
+func TestLimiter_take(t *testing.T) {
+	l := NewLimiter(Options{Burst: 2, Reserve: 10})
+	now := epoch
+	if wait := l.take(now); wait != 0 {
+		t.Errorf("take() without quota = %v, want 0", wait)
+	}
+
+	// 20 requests above the reserve in 10s, i.e. 2 requests per second
+	l.observe(http.Header{"X-Ratelimit-Remaining": {"30"}, "X-Ratelimit-Reset": {strconv.FormatInt(now.Add(10*time.Second).Unix(), 10)}}, now)
+	for i := 0; i < 2; i++ {
+		if wait := l.take(now); wait != 0 {
+			t.Errorf("take() within burst = %v, want 0", wait)
+		}
+	}
+	if wait := l.take(now); wait <= 0 || wait > 600*time.Millisecond {
+		t.Errorf("take() after burst = %v, want about 500ms", wait)
+	}
+	now = now.Add(time.Second)
+	if wait := l.take(now); wait != 0 {
+		t.Errorf("take() after refill = %v, want 0", wait)
+	}
+	if got, _ := l.Quota(); got.Remaining != 27 {
+		t.Errorf("Quota().Remaining = %d, want 27", got.Remaining)
+	}
+
+	// Only the reserve is left
+	l.observe(http.Header{"X-Ratelimit-Remaining": {"10"}, "X-Ratelimit-Reset": {strconv.FormatInt(now.Add(5*time.Second).Unix(), 10)}}, now)
+	if wait := l.take(now); wait != 5*time.Second {
+		t.Errorf("take() with exhausted quota = %v, want 5s", wait)
+	}
+
+	// The quota was reset
+	now = now.Add(5 * time.Second)
+	if wait := l.take(now); wait != 0 {
+		t.Errorf("take() after reset = %v, want 0", wait)
+	}
+}
+
+func TestObserver(t *testing.T) {
+	l := NewObserver()
+	l.observe(http.Header{"X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)}}, time.Now())
+	if err := l.Wait(context.Background()); err != nil {
+		t.Errorf("Wait() = %v, want nil", err)
+	}
+}
+
+func TestLimiter_Wait(t *testing.T) {
+	l := NewLimiter(Options{})
+	l.observe(http.Header{"X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)}}, time.Now())
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if err := l.Wait(ctx); !errors.Is(err, ErrQuotaExhausted) {
+		t.Errorf("Wait() = %v, want %v", err, ErrQuotaExhausted)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	if err := l.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Wait() = %v, want %v", err, context.Canceled)
+	}
+}
+
+func TestLimiter_Transport(t *testing.T) {
+	var mu sync.Mutex
+	remaining := 1000
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		remaining--
+		w.Header().Set("X-RateLimit-Limit", "1000")
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+	}))
+	defer srv.Close()
+
+	// Share the limiter between goroutines
+	l := NewLimiter(Options{Burst: 100})
+	client := &http.Client{Transport: l.Transport(nil)}
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 5; j++ {
+				resp, err := client.Get(srv.URL)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				resp.Body.Close()
+			}
+		}()
+	}
+	wg.Wait()
+
+	got, known := l.Quota()
+	if !known || got.Limit != 1000 || got.Remaining > 950 {
+		t.Errorf("Quota() = %+v, %v, want limit 1000 and at most 950 remaining", got, known)
+	}
+}
+
+func TestWithoutThrottling(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+	}))
+	defer srv.Close()
+
+	l := NewLimiter(Options{})
+	l.observe(http.Header{"X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)}}, time.Now())
+	client := &http.Client{Transport: l.Transport(nil)}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	for _, tt := range []struct {
+		ctx     context.Context
+		wantErr error
+	}{
+		{ctx: ctx, wantErr: ErrQuotaExhausted},
+		{ctx: WithoutThrottling(ctx), wantErr: nil},
+	} {
+		req, err := http.NewRequestWithContext(tt.ctx, http.MethodGet, srv.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := client.Do(req)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("Do() = %v, want %v", err, tt.wantErr)
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+	}
+}
@@ -0,0 +1,386 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package recorder implements a transport recording HTTP interactions with a Git provider API to
+// a "cassette" file, and replaying them later without network access or credentials. This allows
+// running integration tests deterministically, e.g. in CI.
+//
+// The Recorder.Transport method is a gitprovider.ChainableRoundTripperFunc, to be registered
+// using the WithPostChainTransportHook option of the provider client:
+//
+//	rec, err := recorder.New("testdata/cassettes/integration.json", recorder.ModeReplay, recorder.WithSecrets(token))
+//	c, err := github.NewClient(github.WithOAuth2Token(token), github.WithPostChainTransportHook(rec.Transport))
+//	...
+//	err = rec.Stop()
+//
+// Credentials are scrubbed from recorded interactions: the values of authentication headers are
+// removed, and the secrets given with WithSecrets are replaced anywhere in URLs, headers and bodies.
+// Bodies which aren't valid UTF-8, e.g. release assets, are stored base64-encoded.
+package recorder
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+const (
+	// ModeVariable is the environment variable read by ModeFromEnv.
+	ModeVariable = "GIT_PROVIDER_RECORDER_MODE"
+
+	// redacted replaces scrubbed values in recorded interactions.
+	redacted = "REDACTED"
+
+	// encodingBase64 is the BodyEncoding of bodies which aren't valid UTF-8, e.g. gzip data or
+	// release assets, as JSON strings can't hold arbitrary bytes.
+	encodingBase64 = "base64"
+)
+
+var (
+	// ErrInteractionNotFound is returned in replay mode for requests which aren't in the cassette.
+	ErrInteractionNotFound = errors.New("no recorded interaction matches the request")
+	// ErrInvalidMode is returned for unknown modes.
+	ErrInvalidMode = errors.New("invalid recorder mode")
+)
+
+// Mode is an enum specifying whether a Recorder records, replays or passes through interactions.
+type Mode string
+
+const (
+	// ModePassthrough sends requests to the server without recording them.
+	ModePassthrough = Mode("passthrough")
+	// ModeRecord sends requests to the server, and records the interactions to the cassette at Stop.
+	ModeRecord = Mode("record")
+	// ModeReplay replays the interactions from the cassette, without sending requests to the server.
+	ModeReplay = Mode("replay")
+)
+
+// ModeFromEnv returns the mode given by the GIT_PROVIDER_RECORDER_MODE environment variable,
+// or ModePassthrough if it is unset.
+func ModeFromEnv() (Mode, error) {
+	switch mode := Mode(os.Getenv(ModeVariable)); mode {
+	case "":
+		return ModePassthrough, nil
+	case ModePassthrough, ModeRecord, ModeReplay:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("%s=%q: %w", ModeVariable, mode, ErrInvalidMode)
+	}
+}
+
+// Option is a functional option for New.
+type Option func(r *Recorder)
+
+// WithSecrets registers secrets, e.g. access tokens, which are replaced in recorded interactions.
+// Empty secrets are ignored.
+func WithSecrets(secrets ...string) Option {
+	return func(r *Recorder) {
+		for _, secret := range secrets {
+			if len(secret) != 0 {
+				r.secrets = append(r.secrets, secret)
+			}
+		}
+	}
+}
+
+// WithScrubbedHeaders registers additional headers, of which the values aren't recorded.
+// Authorization, Cookie, Set-Cookie and Private-Token are always scrubbed.
+func WithScrubbedHeaders(headers ...string) Option {
+	return func(r *Recorder) {
+		for _, header := range headers {
+			r.headers = append(r.headers, http.CanonicalHeaderKey(header))
+		}
+	}
+}
+
+// Cassette is the content of a cassette file.
+type Cassette struct {
+	// Interactions are the recorded interactions, in the order they happened.
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded request, and the response to it.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded HTTP request.
+type Request struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	Body    string      `json:"body,omitempty"`
+	// BodyEncoding is "base64" if Body is base64-encoded, and empty if Body is the plain text.
+	BodyEncoding string `json:"bodyEncoding,omitempty"`
+}
+
+// Response is a recorded HTTP response.
+type Response struct {
+	StatusCode int         `json:"statusCode"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	// BodyEncoding is "base64" if Body is base64-encoded, and empty if Body is the plain text.
+	BodyEncoding string `json:"bodyEncoding,omitempty"`
+}
+
+// Recorder records or replays HTTP interactions, depending on its Mode.
+type Recorder struct {
+	mode    Mode
+	path    string
+	secrets []string
+	headers []string
+
+	mu       sync.Mutex
+	cassette Cassette
+	// replayed marks the interactions of the cassette which were already replayed
+	replayed []bool
+}
+
+// New creates a Recorder for the cassette file at path. In replay mode, the cassette is loaded
+// immediately, and must exist.
+func New(path string, mode Mode, opts ...Option) (*Recorder, error) {
+	switch mode {
+	case ModePassthrough, ModeRecord, ModeReplay:
+	default:
+		return nil, fmt.Errorf("%q: %w", mode, ErrInvalidMode)
+	}
+
+	r := &Recorder{
+		mode:    mode,
+		path:    path,
+		headers: []string{"Authorization", "Cookie", "Set-Cookie", "Private-Token"},
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	if mode == ModeReplay {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading cassette: %w", err)
+		}
+		if err := json.Unmarshal(data, &r.cassette); err != nil {
+			return nil, fmt.Errorf("decoding cassette %s: %w", path, err)
+		}
+		r.replayed = make([]bool, len(r.cassette.Interactions))
+	}
+	return r, nil
+}
+
+// Mode returns the mode of the recorder.
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Transport is a gitprovider.ChainableRoundTripperFunc, returning a RoundTripper which records or
+// replays the interactions going through it.
+func (r *Recorder) Transport(in http.RoundTripper) http.RoundTripper {
+	if in == nil {
+		in = http.DefaultTransport
+	}
+	if r.mode == ModePassthrough {
+		return in
+	}
+	return &recorderRoundTripper{r: r, transport: in}
+}
+
+// Stop writes the recorded interactions to the cassette in record mode. It is a no-op in the
+// other modes.
+func (r *Recorder) Stop() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, err := json.MarshalIndent(&r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(r.path, append(data, '\n'), 0o600)
+}
+
+// scrub replaces the secrets in s.
+func (r *Recorder) scrub(s string) string {
+	for _, secret := range r.secrets {
+		s = strings.ReplaceAll(s, secret, redacted)
+	}
+	return s
+}
+
+// scrubHeaders returns a copy of h, without credentials.
+func (r *Recorder) scrubHeaders(h http.Header) http.Header {
+	out := make(http.Header, len(h))
+	for key, values := range h {
+		scrubbed := make([]string, 0, len(values))
+		for _, value := range values {
+			scrubbed = append(scrubbed, r.scrub(value))
+		}
+		out[key] = scrubbed
+	}
+	for _, key := range r.headers {
+		if _, ok := out[key]; ok {
+			out[key] = []string{redacted}
+		}
+	}
+	return out
+}
+
+// encodeBody returns the scrubbed body, and its encoding. Bodies which aren't valid UTF-8 are
+// base64-encoded.
+func (r *Recorder) encodeBody(body []byte) (string, string) {
+	scrubbed := r.scrub(string(body))
+	if utf8.ValidString(scrubbed) {
+		return scrubbed, ""
+	}
+	return base64.StdEncoding.EncodeToString([]byte(scrubbed)), encodingBase64
+}
+
+// decodeBody returns the content of a recorded body with the given encoding.
+func decodeBody(body, encoding string) ([]byte, error) {
+	switch encoding {
+	case "":
+		return []byte(body), nil
+	case encodingBase64:
+		return base64.StdEncoding.DecodeString(body)
+	default:
+		return nil, fmt.Errorf("unknown body encoding %q", encoding)
+	}
+}
+
+// record appends an interaction to the cassette.
+func (r *Recorder) record(req *http.Request, reqBody []byte, resp *http.Response, respBody []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	interaction := &Interaction{
+		Request: Request{
+			Method:  req.Method,
+			URL:     r.scrub(req.URL.String()),
+			Headers: r.scrubHeaders(req.Header),
+		},
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Headers:    r.scrubHeaders(resp.Header),
+		},
+	}
+	interaction.Request.Body, interaction.Request.BodyEncoding = r.encodeBody(reqBody)
+	interaction.Response.Body, interaction.Response.BodyEncoding = r.encodeBody(respBody)
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+}
+
+// replay returns the first interaction which wasn't replayed yet, matching the method, URL and
+// body of the request.
+func (r *Recorder) replay(req *http.Request, reqBody []byte) (*Interaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	url := r.scrub(req.URL.String())
+	body, encoding := r.encodeBody(reqBody)
+	for i, interaction := range r.cassette.Interactions {
+		if r.replayed[i] {
+			continue
+		}
+		if interaction.Request.Method == req.Method && interaction.Request.URL == url &&
+			interaction.Request.Body == body && interaction.Request.BodyEncoding == encoding {
+			r.replayed[i] = true
+			return interaction, nil
+		}
+	}
+	return nil, fmt.Errorf("%s %s: %w", req.Method, url, ErrInteractionNotFound)
+}
+
+// recorderRoundTripper records or replays the interactions of a Recorder.
+type recorderRoundTripper struct {
+	r         *Recorder
+	transport http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (rt *recorderRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		if reqBody, err = ioutil.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+	}
+
+	if rt.r.mode == ModeReplay {
+		interaction, err := rt.r.replay(req, reqBody)
+		if err != nil {
+			return nil, err
+		}
+		return newResponse(req, &interaction.Response)
+	}
+
+	// As per the http.RoundTripper contract, the request must not be modified
+	sent := req.Clone(req.Context())
+	if req.Body != nil {
+		sent.Body = ioutil.NopCloser(bytes.NewReader(reqBody))
+	}
+	resp, err := rt.transport.RoundTrip(sent)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := ioutil.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	rt.r.record(req, reqBody, resp, respBody)
+	return resp, nil
+}
+
+// newResponse returns the *http.Response for a recorded response to req.
+func newResponse(req *http.Request, recorded *Response) (*http.Response, error) {
+	body, err := decodeBody(recorded.Body, recorded.BodyEncoding)
+	if err != nil {
+		return nil, err
+	}
+	header := recorded.Headers
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header.Clone(),
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,243 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recorder
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testToken = "s3cr3t-t0k3n"
+
+func newTestServer(t *testing.T) (*httptest.Server, *int) {
+	t.Helper()
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Header().Set("Set-Cookie", "session=abc")
+		w.Header().Set("X-Echo-Token", r.URL.Query().Get("token"))
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(r.Method + " " + r.URL.Path + " " + string(body)))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &calls
+}
+
+func doRequest(t *testing.T, client *http.Client, method, url, body string) (*http.Response, string, error) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	req.Header.Set("Private-Token", testToken)
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp, string(respBody), nil
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	srv, calls := newTestServer(t)
+	path := filepath.Join(t.TempDir(), "cassettes", "test.json")
+
+	rec, err := New(path, ModeRecord, WithSecrets(testToken, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: rec.Transport(nil)}
+	for _, body := range []string{"first", "second"} {
+		resp, got, err := doRequest(t, client, http.MethodPost, srv.URL+"/repos?token="+testToken, body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := "POST /repos " + body; got != want || resp.StatusCode != http.StatusCreated {
+			t.Errorf("recorded response = %d %q, want 201 %q", resp.StatusCode, got, want)
+		}
+	}
+	if err := rec.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), testToken) {
+		t.Errorf("cassette contains the token:\n%s", data)
+	}
+	if strings.Contains(string(data), "session=abc") {
+		t.Errorf("cassette contains the cookie:\n%s", data)
+	}
+
+	rec, err = New(path, ModeReplay, WithSecrets(testToken))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client = &http.Client{Transport: rec.Transport(nil)}
+	// Interactions are matched by body, not only in order
+	for _, body := range []string{"second", "first"} {
+		resp, got, err := doRequest(t, client, http.MethodPost, srv.URL+"/repos?token="+testToken, body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := "POST /repos " + body; got != want || resp.StatusCode != http.StatusCreated {
+			t.Errorf("replayed response = %d %q, want 201 %q", resp.StatusCode, got, want)
+		}
+		if got := resp.Header.Get("X-Echo-Token"); got != redacted {
+			t.Errorf("replayed header X-Echo-Token = %q, want %q", got, redacted)
+		}
+	}
+	if *calls != 2 {
+		t.Errorf("server got %d calls, want 2", *calls)
+	}
+
+	// Every interaction is only replayed once
+	_, _, err = doRequest(t, client, http.MethodPost, srv.URL+"/repos?token="+testToken, "first")
+	if !errors.Is(err, ErrInteractionNotFound) {
+		t.Errorf("replaying an unknown interaction: got %v, want %v", err, ErrInteractionNotFound)
+	}
+}
+
+func TestRecordAndReplay_binary(t *testing.T) {
+	// Neither body is valid UTF-8, e.g. like a release asset or gzip data
+	reqBody := []byte{0x1f, 0x8b, 0x08, 0x00, 0xff, 0xfe}
+	respBody := []byte{0x00, 0xc3, 0x28, 0xa0, 0xa1, 0xff}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if body, _ := ioutil.ReadAll(r.Body); !bytes.Equal(body, reqBody) {
+			t.Errorf("server got body %v, want %v", body, reqBody)
+		}
+		_, _ = w.Write(respBody)
+	}))
+	t.Cleanup(srv.Close)
+	path := filepath.Join(t.TempDir(), "test.json")
+
+	rec, err := New(path, ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: rec.Transport(nil)}
+	if _, _, err := doRequest(t, client, http.MethodPost, srv.URL+"/assets", string(reqBody)); err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"bodyEncoding": "base64"`) {
+		t.Errorf("expected base64-encoded bodies in the cassette:\n%s", data)
+	}
+
+	rec, err = New(path, ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client = &http.Client{Transport: rec.Transport(nil)}
+	_, got, err := doRequest(t, client, http.MethodPost, srv.URL+"/assets", string(reqBody))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal([]byte(got), respBody) {
+		t.Errorf("replayed body = %v, want %v", []byte(got), respBody)
+	}
+}
+
+func TestPassthrough(t *testing.T) {
+	srv, calls := newTestServer(t)
+	path := filepath.Join(t.TempDir(), "test.json")
+
+	rec, err := New(path, ModePassthrough)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rt := rec.Transport(nil); rt != http.DefaultTransport {
+		t.Errorf("Transport(nil) = %v, want http.DefaultTransport", rt)
+	}
+	client := &http.Client{Transport: rec.Transport(nil)}
+	if _, _, err := doRequest(t, client, http.MethodGet, srv.URL, ""); err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if *calls != 1 {
+		t.Errorf("server got %d calls, want 1", *calls)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected no cassette to be written, got %v", err)
+	}
+}
+
+func TestNew_invalid(t *testing.T) {
+	if _, err := New("test.json", Mode("foo")); !errors.Is(err, ErrInvalidMode) {
+		t.Errorf("New() with invalid mode: got %v, want %v", err, ErrInvalidMode)
+	}
+	if _, err := New(filepath.Join(t.TempDir(), "missing.json"), ModeReplay); !os.IsNotExist(errors.Unwrap(err)) {
+		t.Errorf("New() with missing cassette: got %v, want not exist", err)
+	}
+}
+
+func TestModeFromEnv(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Mode
+		wantErr error
+	}{
+		{value: "", want: ModePassthrough},
+		{value: "record", want: ModeRecord},
+		{value: "replay", want: ModeReplay},
+		{value: "passthrough", want: ModePassthrough},
+		{value: "foo", wantErr: ErrInvalidMode},
+	}
+	prev, ok := os.LookupEnv(ModeVariable)
+	t.Cleanup(func() {
+		if ok {
+			os.Setenv(ModeVariable, prev)
+		} else {
+			os.Unsetenv(ModeVariable)
+		}
+	})
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			os.Setenv(ModeVariable, tt.value)
+			got, err := ModeFromEnv()
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ModeFromEnv() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ModeFromEnv() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
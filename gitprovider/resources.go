@@ -30,6 +30,10 @@ type Organization interface {
 
 	// Teams gives access to the TeamsClient for this specific organization
 	Teams() TeamsClient
+
+	// Webhooks gives access to the webhooks of this specific organization, which are triggered
+	// by the events of all its repositories.
+	Webhooks() WebhookClient
 }
 
 // Team represents a team in an organization in a Git provider.
@@ -74,8 +78,29 @@ type UserRepository interface {
 	// Branches gives access to this specific repository branches
 	Branches() BranchClient
 
+	// Files gives access to the content of this specific repository
+	Files() FileClient
+
+	// BranchProtections gives access to the protection rules of this specific repository's branches
+	BranchProtections() BranchProtectionClient
+
 	// PullRequests gives access to this specific repository pull requests
 	PullRequests() PullRequestClient
+
+	// Tags gives access to this specific repository tags
+	Tags() TagClient
+
+	// Releases gives access to this specific repository releases
+	Releases() ReleaseClient
+
+	// Webhooks gives access to this specific repository webhooks
+	Webhooks() WebhookClient
+
+	// CommitStatuses gives access to this specific repository commit statuses
+	CommitStatuses() CommitStatusClient
+
+	// Issues gives access to this specific repository issues
+	Issues() IssueClient
 }
 
 // OrgRepository describes a repository owned by an organization.
@@ -108,6 +133,25 @@ type DeployKey interface {
 	Set(DeployKeyInfo) error
 }
 
+// Webhook represents a webhook delivering events of a repository or organization to an URL.
+type Webhook interface {
+	// Webhook implements the Object interface,
+	// allowing access to the underlying object returned from the API.
+	Object
+	// The webhook can be updated.
+	Updatable
+	// The webhook can be reconciled.
+	Reconcilable
+	// The webhook can be deleted.
+	Deletable
+
+	// Get returns high-level information about this webhook.
+	Get() WebhookInfo
+	// Set sets high-level desired state for this webhook. In order to apply these changes in
+	// the Git provider, run .Update() or .Reconcile().
+	Set(WebhookInfo) error
+}
+
 // TeamAccess describes a binding between a repository and a team.
 type TeamAccess interface {
 	// TeamAccess implements the Object interface,
@@ -129,6 +173,27 @@ type TeamAccess interface {
 	Set(TeamAccessInfo) error
 }
 
+// BranchProtection describes the protection rules of a branch.
+type BranchProtection interface {
+	// BranchProtection implements the Object interface,
+	// allowing access to the underlying object returned from the API.
+	Object
+	// The branch protection can be updated.
+	Updatable
+	// The branch protection can be reconciled.
+	Reconcilable
+	// The branch protection can be deleted.
+	Deletable
+	// RepositoryBound returns repository reference details.
+	RepositoryBound
+
+	// Get returns high-level information about the protection rules of this branch.
+	Get() BranchProtectionInfo
+	// Set sets high-level desired state for the protection rules of this branch. In order to
+	// apply these changes in the Git provider, run .Update() or .Reconcile().
+	Set(BranchProtectionInfo) error
+}
+
 // Commit represents a git commit.
 type Commit interface {
 	// Object implements the Object interface,
@@ -139,6 +204,81 @@ type Commit interface {
 	Get() CommitInfo
 }
 
+// File represents a file or directory in a repository at a given ref.
+type File interface {
+	// Object implements the Object interface,
+	// allowing access to the underlying object returned from the API.
+	Object
+
+	// Get returns high-level information about this file.
+	Get() FileInfo
+}
+
+// Branch represents a git branch.
+type Branch interface {
+	// Object implements the Object interface,
+	// allowing access to the underlying object returned from the API.
+	Object
+
+	// Get returns high-level information about this branch.
+	Get() BranchInfo
+}
+
+// Tag represents a git tag.
+type Tag interface {
+	// Object implements the Object interface,
+	// allowing access to the underlying object returned from the API.
+	Object
+
+	// Get returns high-level information about this tag.
+	Get() TagInfo
+}
+
+// CommitStatus represents a status of a commit, e.g. the result of a CI build.
+type CommitStatus interface {
+	// Object implements the Object interface,
+	// allowing access to the underlying object returned from the API.
+	Object
+
+	// Get returns high-level information about this commit status.
+	Get() CommitStatusInfo
+}
+
+// CombinedCommitStatus represents the combined status of a commit, i.e. the latest status of
+// each context.
+type CombinedCommitStatus interface {
+	// Object implements the Object interface,
+	// allowing access to the underlying object returned from the API.
+	Object
+
+	// Get returns high-level information about this combined status.
+	Get() CombinedCommitStatusInfo
+}
+
+// Release represents a release of a repository, identified by its tag.
+type Release interface {
+	// Object implements the Object interface,
+	// allowing access to the underlying object returned from the API.
+	Object
+
+	// Get returns high-level information about this release.
+	Get() ReleaseInfo
+
+	// Assets gives access to the files attached to this specific release
+	Assets() ReleaseAssetClient
+}
+
+// ReleaseAsset represents a file attached to a release.
+type ReleaseAsset interface {
+	// Object implements the Object interface,
+	// allowing access to the underlying object returned from the API.
+	Object
+
+	// Get returns high-level information about this asset.
+	Get() ReleaseAssetInfo
+}
+
+// PullRequest represents a pull request (a merge request in GitLab).
 type PullRequest interface {
 	// Object implements the Object interface,
 	// allowing access to the underlying object returned from the API.
@@ -146,4 +286,54 @@ type PullRequest interface {
 
 	// Get returns high-level information about this pull request.
 	Get() PullRequestInfo
+
+	// Comments gives access to this specific pull request's comments
+	Comments() PullRequestCommentClient
+
+	// Reviews gives access to this specific pull request's reviews
+	Reviews() PullRequestReviewClient
+}
+
+// PullRequestComment represents an issue-style comment on a pull request, i.e. one that isn't
+// attached to a line of the diff.
+type PullRequestComment interface {
+	// Object implements the Object interface,
+	// allowing access to the underlying object returned from the API.
+	Object
+
+	// Get returns high-level information about this comment.
+	Get() PullRequestCommentInfo
+}
+
+// Issue represents an issue of a repository.
+type Issue interface {
+	// Object implements the Object interface,
+	// allowing access to the underlying object returned from the API.
+	Object
+
+	// Get returns high-level information about this issue.
+	Get() IssueInfo
+
+	// Comments gives access to this specific issue's comments
+	Comments() IssueCommentClient
+}
+
+// IssueComment represents a comment on an issue.
+type IssueComment interface {
+	// Object implements the Object interface,
+	// allowing access to the underlying object returned from the API.
+	Object
+
+	// Get returns high-level information about this comment.
+	Get() IssueCommentInfo
+}
+
+// PullRequestReview represents a review of a pull request.
+type PullRequestReview interface {
+	// Object implements the Object interface,
+	// allowing access to the underlying object returned from the API.
+	Object
+
+	// Get returns high-level information about this review.
+	Get() PullRequestReviewInfo
 }
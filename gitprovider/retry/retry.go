@@ -0,0 +1,336 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package retry implements a transport retrying requests to a Git provider API on transient
+// failures, like 502, 503 and 504 responses or connection resets, and on rate limiting.
+package retry
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+const (
+	// DefaultMaxRetries is the default value of Options.MaxRetries.
+	DefaultMaxRetries = 4
+	// DefaultMinBackoff is the default value of Options.MinBackoff.
+	DefaultMinBackoff = 500 * time.Millisecond
+	// DefaultMaxBackoff is the default value of Options.MaxBackoff.
+	DefaultMaxBackoff = 30 * time.Second
+	// DefaultBudget is the default value of Options.Budget.
+	DefaultBudget = 2 * time.Minute
+
+	// IdempotencyKeyHeader marks a request as safe to retry, regardless of its method.
+	IdempotencyKeyHeader = "Idempotency-Key"
+)
+
+// Options configures the retry transport. Zero values are replaced by their defaults.
+type Options struct {
+	// MaxRetries is the maximum number of times a request is retried.
+	// Set it to a negative value to disable retries.
+	// Default: DefaultMaxRetries
+	MaxRetries int
+
+	// MinBackoff is the wait before the first retry, doubled for every following retry.
+	// Default: DefaultMinBackoff
+	MinBackoff time.Duration
+
+	// MaxBackoff caps the exponential backoff. It doesn't apply to waits requested by the server
+	// through the Retry-After and X-RateLimit-Reset headers.
+	// Default: DefaultMaxBackoff
+	MaxBackoff time.Duration
+
+	// Budget is the maximum time spent on a request, including all retries. A request is not
+	// retried if the wait would exceed the budget, or the deadline of the request context.
+	// Default: DefaultBudget
+	Budget time.Duration
+}
+
+// withDefaults returns a copy of opts, with the defaults applied.
+func (opts Options) withDefaults() Options {
+	if opts.MaxRetries == 0 {
+		opts.MaxRetries = DefaultMaxRetries
+	}
+	if opts.MinBackoff <= 0 {
+		opts.MinBackoff = DefaultMinBackoff
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = DefaultMaxBackoff
+	}
+	if opts.Budget <= 0 {
+		opts.Budget = DefaultBudget
+	}
+	return opts
+}
+
+// NewRetryTransport returns a gitprovider.ChainableRoundTripperFunc retrying requests with
+// exponential backoff and jitter. The following failures are retried:
+//
+// - 502, 503 and 504 responses, and connection resets, for idempotent requests only.
+// - 429 responses, and 403 responses caused by (GitHub secondary) rate limits, for all requests.
+// The wait requested by the server through the Retry-After or X-RateLimit-Reset headers is honored.
+//
+// Requests are idempotent if their method is idempotent as per RFC 7231 (i.e. not POST or PATCH),
+// or if they have an Idempotency-Key header. When retries are exhausted, the last response or
+// error is returned.
+func NewRetryTransport(opts Options) gitprovider.ChainableRoundTripperFunc {
+	opts = opts.withDefaults()
+	return func(in http.RoundTripper) http.RoundTripper {
+		if in == nil {
+			in = http.DefaultTransport
+		}
+		return &retryRoundtripper{
+			opts:      opts,
+			transport: in,
+			now:       time.Now,
+			rand:      rand.New(rand.NewSource(time.Now().UnixNano())),
+		}
+	}
+}
+
+// retryRoundtripper retries requests as described in NewRetryTransport.
+type retryRoundtripper struct {
+	opts      Options
+	transport http.RoundTripper
+	now       func() time.Time
+
+	// randMu guards rand, as *rand.Rand is not safe for concurrent use
+	randMu sync.Mutex
+	rand   *rand.Rand
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *retryRoundtripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if r.opts.MaxRetries < 0 {
+		return r.transport.RoundTrip(req)
+	}
+
+	getBody, err := bodyGetter(req)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := req.Context()
+	deadline := r.now().Add(r.opts.Budget)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	idempotent := isIdempotent(req)
+
+	for attempt := 0; ; attempt++ {
+		// As per the http.RoundTripper contract, the request must not be modified
+		sent := req
+		if attempt > 0 && getBody != nil {
+			sent = req.Clone(ctx)
+			if sent.Body, err = getBody(); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := r.transport.RoundTrip(sent)
+		if attempt >= r.opts.MaxRetries {
+			return resp, err
+		}
+
+		wait, retry := r.shouldRetry(resp, err, idempotent, attempt)
+		if !retry || r.now().Add(wait).After(deadline) {
+			return resp, err
+		}
+		if resp != nil {
+			drainBody(resp)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// shouldRetry returns whether a request should be retried after the given response or error,
+// and how long to wait before doing so.
+func (r *retryRoundtripper) shouldRetry(resp *http.Response, err error, idempotent bool, attempt int) (time.Duration, bool) {
+	if err != nil {
+		return r.backoff(attempt), idempotent && isTransientError(err)
+	}
+
+	if isRateLimited(resp) {
+		if wait, ok := serverWait(resp, r.now()); ok {
+			return wait, true
+		}
+		return r.backoff(attempt), true
+	}
+
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		if !idempotent {
+			return 0, false
+		}
+		if wait, ok := serverWait(resp, r.now()); ok {
+			return wait, true
+		}
+		return r.backoff(attempt), true
+	}
+	return 0, false
+}
+
+// backoff returns the exponential backoff for the given attempt, with "equal jitter", i.e. a
+// random duration between half and all of it.
+func (r *retryRoundtripper) backoff(attempt int) time.Duration {
+	d := r.opts.MaxBackoff
+	if attempt < 32 {
+		if exp := r.opts.MinBackoff << uint(attempt); exp > 0 && exp < d {
+			d = exp
+		}
+	}
+
+	r.randMu.Lock()
+	defer r.randMu.Unlock()
+	return d/2 + time.Duration(r.rand.Int63n(int64(d/2)+1))
+}
+
+// bodyGetter returns a function returning a new copy of the request body, or nil if the request
+// has no body.
+func bodyGetter(req *http.Request) (func() (io.ReadCloser, error), error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody != nil {
+		return req.GetBody, nil
+	}
+
+	// Buffer the body, and make the first attempt read from the buffer too
+	body, err := ioutil.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(body)), nil
+	}, nil
+}
+
+// isIdempotent returns whether the request may be sent more than once.
+func isIdempotent(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	_, ok := req.Header[IdempotencyKeyHeader]
+	return ok
+}
+
+// isTransientError returns whether a transport error is worth retrying.
+func isTransientError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF)
+}
+
+// isRateLimited returns whether the server rejected the request because of rate limiting.
+// GitHub signals its primary rate limit with a 403 response and X-RateLimit-Remaining set to 0,
+// and its secondary rate limits with a 403 response having either a Retry-After header, or a
+// message mentioning the limit.
+func isRateLimited(resp *http.Response) bool {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusForbidden:
+		if resp.Header.Get("Retry-After") != "" || resp.Header.Get("X-RateLimit-Remaining") == "0" {
+			return true
+		}
+		message := strings.ToLower(string(peekBody(resp)))
+		return strings.Contains(message, "secondary rate limit") || strings.Contains(message, "abuse detection")
+	}
+	return false
+}
+
+// serverWait returns the wait requested by the server through the Retry-After header, or the
+// X-RateLimit-Reset (GitHub) or RateLimit-Reset (GitLab) headers once the quota is exhausted.
+func serverWait(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second, true
+		}
+		if date, err := http.ParseTime(retryAfter); err == nil {
+			return nonNegative(date.Sub(now)), true
+		}
+	}
+
+	for _, prefix := range []string{"X-RateLimit-", "RateLimit-"} {
+		if resp.Header.Get(prefix+"Remaining") != "0" {
+			continue
+		}
+		if reset, err := strconv.ParseInt(resp.Header.Get(prefix+"Reset"), 10, 64); err == nil {
+			return nonNegative(time.Unix(reset, 0).Sub(now)), true
+		}
+	}
+	return 0, false
+}
+
+// nonNegative returns d, or 0 if it's negative.
+func nonNegative(d time.Duration) time.Duration {
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// peekBody reads at most 1 KiB of the response body, without consuming it.
+func peekBody(resp *http.Response) []byte {
+	if resp.Body == nil {
+		return nil
+	}
+	// On errors, the bytes read so far are still returned to the caller
+	head, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 1024))
+	resp.Body = &readCloser{Reader: io.MultiReader(bytes.NewReader(head), resp.Body), Closer: resp.Body}
+	return head
+}
+
+// drainBody discards and closes the response body, allowing to reuse the connection.
+func drainBody(resp *http.Response) {
+	if resp.Body == nil {
+		return
+	}
+	_, _ = io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 64*1024))
+	_ = resp.Body.Close()
+}
+
+// readCloser combines a Reader and a Closer.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,330 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+var testOptions = Options{MinBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+
+// newTestServer returns a server answering with the given responses in order, and the last one
+// once exhausted, and records the bodies of the received requests.
+func newTestServer(t *testing.T, responses ...func(w http.ResponseWriter)) (*httptest.Server, *[]string) {
+	t.Helper()
+	var mu sync.Mutex
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		body, _ := ioutil.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		i := len(bodies) - 1
+		if i >= len(responses) {
+			i = len(responses) - 1
+		}
+		responses[i](w)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &bodies
+}
+
+func status(code int, headers ...string) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		for i := 0; i+1 < len(headers); i += 2 {
+			w.Header().Set(headers[i], headers[i+1])
+		}
+		w.WriteHeader(code)
+		_, _ = fmt.Fprintf(w, `{"message": %q}`, http.StatusText(code))
+	}
+}
+
+func message(code int, msg string) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		w.WriteHeader(code)
+		_, _ = fmt.Fprintf(w, `{"message": %q}`, msg)
+	}
+}
+
+func do(t *testing.T, ctx context.Context, opts Options, method, url string, header http.Header) (*http.Response, error) {
+	t.Helper()
+	req, err := http.NewRequestWithContext(ctx, method, url, strings.NewReader("payload"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	client := &http.Client{Transport: NewRetryTransport(opts)(nil)}
+	resp, err := client.Do(req)
+	if resp != nil {
+		_, _ = ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+	}
+	return resp, err
+}
+
+func TestRetryTransport(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		header     http.Header
+		opts       *Options
+		responses  []func(w http.ResponseWriter)
+		wantStatus int
+		wantCalls  int
+	}{
+		{
+			name:       "success",
+			method:     http.MethodGet,
+			responses:  []func(w http.ResponseWriter){status(http.StatusOK)},
+			wantStatus: http.StatusOK,
+			wantCalls:  1,
+		},
+		{
+			name:       "transient failures",
+			method:     http.MethodGet,
+			responses:  []func(w http.ResponseWriter){status(http.StatusBadGateway), status(http.StatusServiceUnavailable), status(http.StatusGatewayTimeout), status(http.StatusOK)},
+			wantStatus: http.StatusOK,
+			wantCalls:  4,
+		},
+		{
+			name:       "retries exhausted",
+			method:     http.MethodDelete,
+			responses:  []func(w http.ResponseWriter){status(http.StatusServiceUnavailable)},
+			wantStatus: http.StatusServiceUnavailable,
+			wantCalls:  DefaultMaxRetries + 1,
+		},
+		{
+			name:       "retries disabled",
+			method:     http.MethodGet,
+			opts:       &Options{MaxRetries: -1},
+			responses:  []func(w http.ResponseWriter){status(http.StatusServiceUnavailable), status(http.StatusOK)},
+			wantStatus: http.StatusServiceUnavailable,
+			wantCalls:  1,
+		},
+		{
+			name:       "no retry on other errors",
+			method:     http.MethodGet,
+			responses:  []func(w http.ResponseWriter){status(http.StatusInternalServerError), status(http.StatusOK)},
+			wantStatus: http.StatusInternalServerError,
+			wantCalls:  1,
+		},
+		{
+			name:       "non-idempotent request not retried on transient failures",
+			method:     http.MethodPost,
+			responses:  []func(w http.ResponseWriter){status(http.StatusBadGateway), status(http.StatusCreated)},
+			wantStatus: http.StatusBadGateway,
+			wantCalls:  1,
+		},
+		{
+			name:       "idempotency key",
+			method:     http.MethodPost,
+			header:     http.Header{IdempotencyKeyHeader: []string{"foo"}},
+			responses:  []func(w http.ResponseWriter){status(http.StatusBadGateway), status(http.StatusCreated)},
+			wantStatus: http.StatusCreated,
+			wantCalls:  2,
+		},
+		{
+			name:       "too many requests",
+			method:     http.MethodPost,
+			responses:  []func(w http.ResponseWriter){status(http.StatusTooManyRequests, "Retry-After", "0"), status(http.StatusCreated)},
+			wantStatus: http.StatusCreated,
+			wantCalls:  2,
+		},
+		{
+			name:       "secondary rate limit",
+			method:     http.MethodPatch,
+			responses:  []func(w http.ResponseWriter){message(http.StatusForbidden, "You have exceeded a secondary rate limit."), status(http.StatusOK)},
+			wantStatus: http.StatusOK,
+			wantCalls:  2,
+		},
+		{
+			name:       "primary rate limit",
+			method:     http.MethodGet,
+			responses:  []func(w http.ResponseWriter){status(http.StatusForbidden, "X-RateLimit-Remaining", "0", "X-RateLimit-Reset", "0"), status(http.StatusOK)},
+			wantStatus: http.StatusOK,
+			wantCalls:  2,
+		},
+		{
+			name:       "forbidden",
+			method:     http.MethodGet,
+			responses:  []func(w http.ResponseWriter){message(http.StatusForbidden, "Must have admin rights to Repository."), status(http.StatusOK)},
+			wantStatus: http.StatusForbidden,
+			wantCalls:  1,
+		},
+		{
+			name:       "wait exceeds budget",
+			method:     http.MethodGet,
+			responses:  []func(w http.ResponseWriter){status(http.StatusServiceUnavailable, "Retry-After", "3600"), status(http.StatusOK)},
+			wantStatus: http.StatusServiceUnavailable,
+			wantCalls:  1,
+		},
+		{
+			name:       "rate limit reset exceeds budget",
+			method:     http.MethodGet,
+			responses:  []func(w http.ResponseWriter){status(http.StatusTooManyRequests, "RateLimit-Remaining", "0", "RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)), status(http.StatusOK)},
+			wantStatus: http.StatusTooManyRequests,
+			wantCalls:  1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, bodies := newTestServer(t, tt.responses...)
+			opts := testOptions
+			if tt.opts != nil {
+				opts = *tt.opts
+			}
+			resp, err := do(t, context.Background(), opts, tt.method, srv.URL, tt.header)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if len(*bodies) != tt.wantCalls {
+				t.Errorf("server got %d calls, want %d", len(*bodies), tt.wantCalls)
+			}
+			for i, body := range *bodies {
+				if body != "payload" {
+					t.Errorf("body of call %d = %q, want %q", i, body, "payload")
+				}
+			}
+		})
+	}
+}
+
+func TestRetryTransport_contextDeadline(t *testing.T) {
+	srv, bodies := newTestServer(t, status(http.StatusServiceUnavailable, "Retry-After", "1"), status(http.StatusOK))
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	resp, err := do(t, ctx, testOptions, http.MethodGet, srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable || len(*bodies) != 1 {
+		t.Errorf("got status %d after %d calls, want 503 after 1 call", resp.StatusCode, len(*bodies))
+	}
+}
+
+func TestRetryTransport_contextCanceled(t *testing.T) {
+	srv, _ := newTestServer(t, status(http.StatusServiceUnavailable))
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	_, err := do(t, ctx, Options{MinBackoff: time.Second}, http.MethodGet, srv.URL, nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+}
+
+// errorTransport fails with err for the given number of calls, before succeeding.
+type errorTransport struct {
+	err      error
+	failures int
+	calls    int
+}
+
+func (t *errorTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.calls++
+	if t.calls <= t.failures {
+		return nil, t.err
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody, Request: req}, nil
+}
+
+func TestRetryTransport_connectionErrors(t *testing.T) {
+	reset := &url.Error{Op: "Get", URL: "https://example.com", Err: syscall.ECONNRESET}
+	tests := []struct {
+		name      string
+		method    string
+		err       error
+		wantErr   bool
+		wantCalls int
+	}{
+		{name: "connection reset", method: http.MethodGet, err: reset, wantCalls: 3},
+		{name: "non-idempotent", method: http.MethodPost, err: reset, wantErr: true, wantCalls: 1},
+		{name: "other error", method: http.MethodGet, err: errors.New("tls: bad certificate"), wantErr: true, wantCalls: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport := &errorTransport{err: tt.err, failures: 2}
+			req, _ := http.NewRequest(tt.method, "https://example.com", nil)
+			_, err := NewRetryTransport(testOptions)(transport).RoundTrip(req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RoundTrip() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if transport.calls != tt.wantCalls {
+				t.Errorf("got %d calls, want %d", transport.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestServerWait(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	tests := []struct {
+		name     string
+		header   http.Header
+		want     time.Duration
+		wantWait bool
+	}{
+		{name: "none", header: http.Header{}},
+		{name: "retry after seconds", header: http.Header{"Retry-After": {"120"}}, want: 2 * time.Minute, wantWait: true},
+		{name: "retry after date", header: http.Header{"Retry-After": {now.Add(time.Minute).UTC().Format(http.TimeFormat)}}, want: time.Minute, wantWait: true},
+		{name: "github reset", header: http.Header{"X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {"1600000030"}}, want: 30 * time.Second, wantWait: true},
+		{name: "gitlab reset", header: http.Header{"Ratelimit-Remaining": {"0"}, "Ratelimit-Reset": {"1600000010"}}, want: 10 * time.Second, wantWait: true},
+		{name: "reset in the past", header: http.Header{"X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {"1500000000"}}, want: 0, wantWait: true},
+		{name: "quota left", header: http.Header{"X-Ratelimit-Remaining": {"10"}, "X-Ratelimit-Reset": {"1600000030"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := serverWait(&http.Response{Header: tt.header}, now)
+			if got != tt.want || ok != tt.wantWait {
+				t.Errorf("serverWait() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantWait)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	r := NewRetryTransport(Options{MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second})(nil).(*retryRoundtripper)
+	for attempt, max := range []time.Duration{100, 200, 400, 800, 1000, 1000} {
+		max *= time.Millisecond
+		for i := 0; i < 20; i++ {
+			if got := r.backoff(attempt); got < max/2 || got > max {
+				t.Errorf("backoff(%d) = %v, want between %v and %v", attempt, got, max/2, max)
+			}
+		}
+	}
+	if got := r.backoff(100); got > time.Second {
+		t.Errorf("backoff(100) = %v, want at most 1s", got)
+	}
+}
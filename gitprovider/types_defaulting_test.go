@@ -117,6 +117,101 @@ func TestDefaulting(t *testing.T) {
 				Permission: RepositoryPermissionVar(RepositoryPermissionPush),
 			},
 		},
+		{
+			name:       "BranchProtection: empty",
+			structName: "BranchProtection",
+			object:     &BranchProtectionInfo{},
+			expected: &BranchProtectionInfo{
+				RequiredApprovals:       IntVar(0),
+				DismissStaleReviews:     BoolVar(false),
+				RequireCodeOwnerReviews: BoolVar(false),
+				RestrictPushes:          BoolVar(false),
+				AllowForcePushes:        BoolVar(false),
+				AllowDeletions:          BoolVar(false),
+			},
+		},
+		{
+			name:       "BranchProtection: don't set if non-nil (non-default)",
+			structName: "BranchProtection",
+			object: &BranchProtectionInfo{
+				RequiredApprovals: IntVar(2),
+				RestrictPushes:    BoolVar(true),
+			},
+			expected: &BranchProtectionInfo{
+				RequiredApprovals:       IntVar(2),
+				DismissStaleReviews:     BoolVar(false),
+				RequireCodeOwnerReviews: BoolVar(false),
+				RestrictPushes:          BoolVar(true),
+				AllowForcePushes:        BoolVar(false),
+				AllowDeletions:          BoolVar(false),
+			},
+		},
+		{
+			name:       "Release: name defaults to the tag",
+			structName: "Release",
+			object:     &ReleaseInfo{TagName: "v1.0.0"},
+			expected:   &ReleaseInfo{TagName: "v1.0.0", Name: "v1.0.0"},
+		},
+		{
+			name:       "Release: don't set if non-empty",
+			structName: "Release",
+			object:     &ReleaseInfo{TagName: "v1.0.0", Name: "First release"},
+			expected:   &ReleaseInfo{TagName: "v1.0.0", Name: "First release"},
+		},
+		{
+			name:       "Webhook: empty",
+			structName: "Webhook",
+			object:     &WebhookInfo{URL: "https://example.com/hook"},
+			expected: &WebhookInfo{
+				URL:             "https://example.com/hook",
+				ContentType:     WebhookContentTypeVar(WebhookContentTypeJSON),
+				Events:          []WebhookEvent{WebhookEventPush},
+				SSLVerification: BoolVar(true),
+				Active:          BoolVar(true),
+			},
+		},
+		{
+			name:       "Webhook: don't set if non-nil",
+			structName: "Webhook",
+			object: &WebhookInfo{
+				URL:             "https://example.com/hook",
+				ContentType:     WebhookContentTypeVar(WebhookContentTypeForm),
+				Events:          []WebhookEvent{WebhookEventTag},
+				SSLVerification: BoolVar(false),
+				Active:          BoolVar(false),
+			},
+			expected: &WebhookInfo{
+				URL:             "https://example.com/hook",
+				ContentType:     WebhookContentTypeVar(WebhookContentTypeForm),
+				Events:          []WebhookEvent{WebhookEventTag},
+				SSLVerification: BoolVar(false),
+				Active:          BoolVar(false),
+			},
+		},
+		{
+			name:       "CommitStatus: empty",
+			structName: "CommitStatus",
+			object:     &CommitStatusInfo{State: CommitStatusStatePending},
+			expected:   &CommitStatusInfo{State: CommitStatusStatePending, Context: "default"},
+		},
+		{
+			name:       "CommitStatus: don't set if non-empty",
+			structName: "CommitStatus",
+			object:     &CommitStatusInfo{State: CommitStatusStateSuccess, Context: "ci/build"},
+			expected:   &CommitStatusInfo{State: CommitStatusStateSuccess, Context: "ci/build"},
+		},
+		{
+			name:       "Issue: empty",
+			structName: "Issue",
+			object:     &IssueInfo{Title: "foo"},
+			expected:   &IssueInfo{Title: "foo", State: IssueStateOpen},
+		},
+		{
+			name:       "Issue: don't set if non-empty",
+			structName: "Issue",
+			object:     &IssueInfo{Title: "foo", State: IssueStateClosed},
+			expected:   &IssueInfo{Title: "foo", State: IssueStateClosed},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -17,7 +17,9 @@ limitations under the License.
 package gitprovider
 
 import (
+	"encoding/base64"
 	"reflect"
+	"time"
 
 	"github.com/fluxcd/go-git-providers/validation"
 )
@@ -33,6 +35,16 @@ const (
 	defaultBranchName = "master"
 	// by default, deploy keys are read-only.
 	defaultDeployKeyReadOnly = true
+	// by default, protected branches don't require approving reviews.
+	defaultBranchProtectionRequiredApprovals = 0
+	// by default, webhooks are delivered as JSON.
+	defaultWebhookContentType = WebhookContentTypeJSON
+	// by default, webhooks are triggered by pushes.
+	defaultWebhookEvent = WebhookEventPush
+	// the default context of commit statuses, like the one of GitHub and GitLab.
+	defaultCommitStatusContext = "default"
+	// by default, issues are open.
+	defaultIssueState = IssueStateOpen
 )
 
 // RepositoryInfo implements InfoRequest and DefaultedInfoRequest (with a pointer receiver).
@@ -178,7 +190,7 @@ func (dk DeployKeyInfo) Equals(actual InfoRequest) bool {
 	return reflect.DeepEqual(dk, actual)
 }
 
-// CommitInfo contains high-level information about a deploy key.
+// CommitInfo contains high-level information about a commit.
 type CommitInfo struct {
 	// Sha is the git sha for this commit.
 	// +required
@@ -187,21 +199,908 @@ type CommitInfo struct {
 	// TreeSha is the tree sha this commit belongs to.
 	// +required
 	TreeSha string `json:"tree_sha"`
+
+	// Message is the full commit message.
+	// +optional
+	Message string `json:"message"`
+
+	// Author is who made the changes of the commit, and when.
+	// +optional
+	Author *CommitAuthor `json:"author"`
+
+	// Committer is who created the commit, and when.
+	// +optional
+	Committer *CommitAuthor `json:"committer"`
+
+	// ParentShas are the git shas of the parent commits, which is empty for a root commit.
+	// +optional
+	ParentShas []string `json:"parent_shas"`
+
+	// WebURL is the URL of the commit in the git provider web interface.
+	// +optional
+	WebURL string `json:"web_url"`
 }
 
-// CommitFile contains high-level information about a file added to a commit.
+// CommitAuthor identifies the author or committer of a commit.
+type CommitAuthor struct {
+	// Name is the name of the person.
+	// +required
+	Name string `json:"name"`
+
+	// Email is the email address of the person.
+	// +required
+	Email string `json:"email"`
+
+	// Date is when the commit was authored or committed. When creating a commit, the zero
+	// value means "now".
+	// +optional
+	Date time.Time `json:"date"`
+}
+
+// CommitFile contains high-level information about a file changed by a commit.
 type CommitFile struct {
+	// Action specifies what the commit does with the file. If unset, the file is deleted if
+	// Content is nil, and otherwise created or updated depending on whether it exists.
+	// +optional
+	Action *CommitFileAction `json:"action,omitempty"`
+
 	// Path is path where this file is located.
 	// +required
 	Path *string `json:"path"`
 
-	// Content is the content of the file.
-	// +required
+	// PreviousPath is the path the file is moved from.
+	// Required if Action is CommitFileActionMove.
+	// +optional
+	PreviousPath *string `json:"previousPath,omitempty"`
+
+	// Content is the content of the file, encoded as specified by Encoding.
+	// Required unless the file is deleted, or moved without changing its content.
+	// +optional
 	Content *string `json:"content"`
+
+	// Encoding specifies how Content is encoded, use ContentEncodingBase64 for binary files.
+	// Default: ContentEncodingText.
+	// +optional
+	Encoding *ContentEncoding `json:"encoding,omitempty"`
+
+	// Mode is the mode of the file after the commit. If unset, new files are regular files,
+	// and existing files keep their mode.
+	// +optional
+	Mode *FileMode `json:"mode,omitempty"`
 }
 
+// GetAction returns the action of the commit for the file. If Action is unset, this is
+// CommitFileActionDelete for a nil Content, and an empty action otherwise, which means that
+// the file is created or updated depending on whether it exists.
+func (f CommitFile) GetAction() CommitFileAction {
+	if f.Action != nil {
+		return *f.Action
+	}
+	if f.Content == nil {
+		return CommitFileActionDelete
+	}
+	return ""
+}
+
+// GetContent returns the decoded content of the file, or nil if Content is unset.
+func (f CommitFile) GetContent() ([]byte, error) {
+	if f.Content == nil {
+		return nil, nil
+	}
+	if f.Encoding != nil && *f.Encoding == ContentEncodingBase64 {
+		return base64.StdEncoding.DecodeString(*f.Content)
+	}
+	return []byte(*f.Content), nil
+}
+
+// IsExecutable returns true if Mode specifies an executable file.
+func (f CommitFile) IsExecutable() bool {
+	return f.Mode != nil && *f.Mode == FileModeExecutable
+}
+
+// ValidateInfo validates the file before it is committed.
+func (f CommitFile) ValidateInfo() error {
+	validator := validation.New("CommitFile")
+	// Make sure we've set the path of the file
+	if f.Path == nil || len(*f.Path) == 0 {
+		validator.Required("Path")
+	}
+	if f.Action != nil {
+		validator.Append(ValidateCommitFileAction(*f.Action), *f.Action, "Action")
+	}
+	if f.Encoding != nil {
+		validator.Append(ValidateContentEncoding(*f.Encoding), *f.Encoding, "Encoding")
+	}
+	if f.Mode != nil {
+		// Only the content of regular and executable files can be committed
+		if err := ValidateFileMode(*f.Mode); err != nil {
+			validator.Append(err, *f.Mode, "Mode")
+		} else if *f.Mode != FileModeRegular && *f.Mode != FileModeExecutable {
+			validator.Invalid(*f.Mode, "Mode")
+		}
+	}
+	if _, err := f.GetContent(); err != nil {
+		validator.Invalid(*f.Content, "Content")
+	}
+
+	switch f.GetAction() {
+	case CommitFileActionCreate, CommitFileActionUpdate:
+		if f.Content == nil {
+			validator.Required("Content")
+		}
+	case CommitFileActionMove:
+		if f.PreviousPath == nil || len(*f.PreviousPath) == 0 {
+			validator.Required("PreviousPath")
+		} else if f.Path != nil && *f.PreviousPath == *f.Path {
+			// Moving a file to its own path would delete and create it at once
+			validator.Invalid(*f.PreviousPath, "PreviousPath")
+		}
+	case CommitFileActionDelete:
+		// There's nothing left of a deleted file to set
+		if f.Content != nil {
+			validator.Invalid(*f.Content, "Content")
+		}
+		if f.Mode != nil {
+			validator.Invalid(*f.Mode, "Mode")
+		}
+	}
+	return validator.Error()
+}
+
+// FileInfo contains high-level information about a file or directory in a repository.
+type FileInfo struct {
+	// Path is the path of the file, relative to the root of the repository.
+	// +required
+	Path string `json:"path"`
+
+	// Mode is the git mode of the file, which tells files, directories, symbolic links and
+	// submodules apart.
+	// +required
+	Mode FileMode `json:"mode"`
+
+	// Sha is the git sha of the blob of a file, or of the tree of a directory.
+	// +required
+	Sha string `json:"sha"`
+
+	// Size is the size of the content of the file in bytes, and zero for directories and
+	// submodules. Some providers only report it when getting a single file.
+	// +optional
+	Size int64 `json:"size"`
+
+	// Content is the content of the file, which is only returned when getting a single file.
+	// +optional
+	Content []byte `json:"content,omitempty"`
+}
+
+// IsDir returns true if the file is a directory.
+func (f FileInfo) IsDir() bool {
+	return f.Mode == FileModeDirectory
+}
+
+// BranchInfo contains high-level information about a branch.
+type BranchInfo struct {
+	// Name is the name of the branch, e.g. "main".
+	// +required
+	Name string `json:"name"`
+
+	// Sha is the git sha of the head commit of the branch.
+	// +required
+	Sha string `json:"sha"`
+
+	// Protected is true if the branch is protected, e.g. against force-pushes.
+	// +optional
+	Protected bool `json:"protected"`
+}
+
+// BranchProtectionInfo implements InfoRequest and DefaultedInfoRequest (with a pointer receiver).
+var _ InfoRequest = BranchProtectionInfo{}
+var _ DefaultedInfoRequest = &BranchProtectionInfo{}
+
+// BranchProtectionInfo contains high-level information about the protection rules of a branch.
+// Not all providers can express every rule; ErrNoProviderSupport is returned when a rule that
+// the provider can't express is set to a non-default value.
+type BranchProtectionInfo struct {
+	// Branch is the name of the protected branch.
+	// +required
+	Branch string `json:"branch"`
+
+	// RequiredApprovals is the number of approving reviews a pull request needs before it can
+	// be merged into the branch.
+	// Default: 0.
+	// +optional
+	RequiredApprovals *int `json:"requiredApprovals,omitempty"`
+
+	// RequiredStatusChecks are the contexts of the commit statuses that must succeed before a
+	// pull request can be merged into the branch. The order is not significant.
+	// +optional
+	RequiredStatusChecks []string `json:"requiredStatusChecks,omitempty"`
+
+	// DismissStaleReviews specifies whether approvals are dismissed when new commits are pushed
+	// to the pull request.
+	// Default: false.
+	// +optional
+	DismissStaleReviews *bool `json:"dismissStaleReviews,omitempty"`
+
+	// RequireCodeOwnerReviews specifies whether pull requests changing files with a designated
+	// code owner need an approval of that code owner.
+	// Default: false.
+	// +optional
+	RequireCodeOwnerReviews *bool `json:"requireCodeOwnerReviews,omitempty"`
+
+	// RestrictPushes specifies whether pushing to the branch is restricted to privileged users
+	// (e.g. administrators in GitHub, or maintainers in GitLab), instead of everyone with write
+	// access to the repository.
+	// Default: false.
+	// +optional
+	RestrictPushes *bool `json:"restrictPushes,omitempty"`
+
+	// AllowForcePushes specifies whether force-pushing to the branch is allowed.
+	// Default: false.
+	// +optional
+	AllowForcePushes *bool `json:"allowForcePushes,omitempty"`
+
+	// AllowDeletions specifies whether the branch can be deleted.
+	// Default: false.
+	// +optional
+	AllowDeletions *bool `json:"allowDeletions,omitempty"`
+}
+
+// Default defaults the BranchProtection fields.
+func (bp *BranchProtectionInfo) Default() {
+	if bp.RequiredApprovals == nil {
+		bp.RequiredApprovals = IntVar(defaultBranchProtectionRequiredApprovals)
+	}
+	if bp.DismissStaleReviews == nil {
+		bp.DismissStaleReviews = BoolVar(false)
+	}
+	if bp.RequireCodeOwnerReviews == nil {
+		bp.RequireCodeOwnerReviews = BoolVar(false)
+	}
+	if bp.RestrictPushes == nil {
+		bp.RestrictPushes = BoolVar(false)
+	}
+	if bp.AllowForcePushes == nil {
+		bp.AllowForcePushes = BoolVar(false)
+	}
+	if bp.AllowDeletions == nil {
+		bp.AllowDeletions = BoolVar(false)
+	}
+}
+
+// ValidateInfo validates the object at {Object}.Set() and POST-time.
+func (bp BranchProtectionInfo) ValidateInfo() error {
+	validator := validation.New("BranchProtection")
+	// Make sure we've set the name of the branch
+	if len(bp.Branch) == 0 {
+		validator.Required("Branch")
+	}
+	// A negative amount of approvals doesn't make sense
+	if bp.RequiredApprovals != nil && *bp.RequiredApprovals < 0 {
+		validator.Invalid(*bp.RequiredApprovals, "RequiredApprovals")
+	}
+	return validator.Error()
+}
+
+// Equals can be used to check if this *Info request (the desired state) matches the actual
+// passed in as the argument. The required status checks are compared in any order.
+func (bp BranchProtectionInfo) Equals(actual InfoRequest) bool {
+	a, ok := actual.(BranchProtectionInfo)
+	if !ok {
+		return false
+	}
+	if !unorderedEqual(bp.RequiredStatusChecks, a.RequiredStatusChecks) {
+		return false
+	}
+	// The status checks are equal, compare the rest of the fields
+	bp.RequiredStatusChecks, a.RequiredStatusChecks = nil, nil
+	return reflect.DeepEqual(bp, a)
+}
+
+// PullRequestInfo implements InfoRequest.
+var _ InfoRequest = PullRequestInfo{}
+
+// PullRequestInfo contains high-level information about a pull request.
 type PullRequestInfo struct {
+	// Number is the number identifying the pull request in the repository.
+	// For GitLab, this is the IID of the merge request.
+	// +required
+	Number int `json:"number"`
+
+	// Title is the title of the pull request.
+	// +required
+	Title string `json:"title"`
+
+	// Description is the description of the pull request.
+	// +optional
+	Description string `json:"description"`
+
+	// HeadBranch is the branch with the changes to merge.
+	// +required
+	HeadBranch string `json:"head_branch"`
+
+	// BaseBranch is the branch the changes are merged into.
+	// +required
+	BaseBranch string `json:"base_branch"`
+
+	// State is the state of the pull request.
+	// +required
+	State PullRequestState `json:"state"`
+
+	// Labels are the names of the labels of the pull request.
+	// +optional
+	Labels []string `json:"labels"`
+
+	// Author is the login of the user who opened the pull request.
+	// +optional
+	Author string `json:"author"`
+
+	// Draft is true if the pull request is a draft, i.e. not ready for review.
+	// +optional
+	Draft bool `json:"draft"`
+
+	// Mergeable is whether the pull request can be merged without conflicts.
+	// nil means that the provider didn't compute it yet.
+	// +optional
+	Mergeable *bool `json:"mergeable"`
+
+	// CreatedAt is the time the pull request was opened.
+	// +optional
+	CreatedAt time.Time `json:"created_at"`
+
+	// UpdatedAt is the time the pull request was last updated.
+	// +optional
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// MergedAt is the time the pull request was merged, or nil if it isn't merged.
+	// +optional
+	MergedAt *time.Time `json:"merged_at"`
+
+	// ClosedAt is the time the pull request was closed or merged, or nil if it is open.
+	// +optional
+	ClosedAt *time.Time `json:"closed_at"`
+
 	// WebURL is the URL of the pull request in the git provider web interface.
 	// +required
 	WebURL string `json:"web_url"`
 }
+
+// ValidateInfo validates the object at PullRequestClient.Reconcile()-time.
+func (pr PullRequestInfo) ValidateInfo() error {
+	validator := validation.New("PullRequest")
+	// Title, HeadBranch and BaseBranch are needed to create the pull request
+	if len(pr.Title) == 0 {
+		validator.Required("Title")
+	}
+	if len(pr.HeadBranch) == 0 {
+		validator.Required("HeadBranch")
+	}
+	if len(pr.BaseBranch) == 0 {
+		validator.Required("BaseBranch")
+	}
+	return validator.Error()
+}
+
+// Equals can be used to check if this *Info request (the desired state) matches the actual
+// passed in as the argument. Only the fields set by PullRequestClient.Reconcile are compared,
+// i.e. the title, description, head and base branch, and the labels (in any order).
+func (pr PullRequestInfo) Equals(actual InfoRequest) bool {
+	a, ok := actual.(PullRequestInfo)
+	if !ok {
+		return false
+	}
+	return pr.Title == a.Title &&
+		pr.Description == a.Description &&
+		pr.HeadBranch == a.HeadBranch &&
+		pr.BaseBranch == a.BaseBranch &&
+		unorderedEqual(pr.Labels, a.Labels)
+}
+
+// unorderedEqual returns whether a and b contain the same strings, in any order.
+func unorderedEqual(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	counts := make(map[string]int, len(a))
+	for _, str := range a {
+		counts[str]++
+	}
+	for _, str := range b {
+		if counts[str] == 0 {
+			return false
+		}
+		counts[str]--
+	}
+	return true
+}
+
+// PullRequestCommentInfo contains high-level information about a pull request comment.
+type PullRequestCommentInfo struct {
+	// ID is the identifier of the comment, used for updating and deleting it.
+	// +required
+	ID int64 `json:"id"`
+
+	// Body is the text of the comment, usually in Markdown.
+	// +required
+	Body string `json:"body"`
+
+	// Author is the login of the user who wrote the comment.
+	// +optional
+	Author string `json:"author"`
+
+	// CreatedAt is the time the comment was created.
+	// +optional
+	CreatedAt time.Time `json:"created_at"`
+
+	// UpdatedAt is the time the comment was last updated.
+	// +optional
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// PullRequestReviewInfo contains high-level information about a pull request review.
+type PullRequestReviewInfo struct {
+	// ID is the identifier of the review, or 0 if the provider doesn't identify reviews, e.g.
+	// for GitLab approvals.
+	// +optional
+	ID int64 `json:"id"`
+
+	// State is the verdict of the review.
+	// +required
+	State PullRequestReviewState `json:"state"`
+
+	// Body is the text of the review.
+	// +optional
+	Body string `json:"body"`
+
+	// Author is the login of the user who submitted the review.
+	// +optional
+	Author string `json:"author"`
+
+	// SubmittedAt is the time the review was submitted, or nil if the provider doesn't
+	// report it.
+	// +optional
+	SubmittedAt *time.Time `json:"submitted_at"`
+}
+
+// TagInfo implements InfoRequest.
+var _ InfoRequest = TagInfo{}
+
+// TagInfo contains high-level information about a git tag.
+type TagInfo struct {
+	// Name is the name of the tag, e.g. "v1.0.0".
+	// +required
+	Name string `json:"name"`
+
+	// Sha is the git sha of the commit the tag points to. For annotated tags, this is the
+	// commit the tag object points to, not the sha of the tag object itself.
+	// +required
+	Sha string `json:"sha"`
+
+	// Message is the message of an annotated tag, and empty for a lightweight tag. Tags are
+	// created as annotated tags if Message is set. Some providers only return it when getting a
+	// single tag.
+	// +optional
+	Message string `json:"message"`
+}
+
+// ValidateInfo validates the object at {Object}.Set() and POST-time.
+func (t TagInfo) ValidateInfo() error {
+	validator := validation.New("Tag")
+	// Name and Sha are needed to create the tag
+	if len(t.Name) == 0 {
+		validator.Required("Name")
+	}
+	if len(t.Sha) == 0 {
+		validator.Required("Sha")
+	}
+	return validator.Error()
+}
+
+// Equals can be used to check if this *Info request (the desired state) matches the actual
+// passed in as the argument.
+func (t TagInfo) Equals(actual InfoRequest) bool {
+	return reflect.DeepEqual(t, actual)
+}
+
+// ReleaseInfo implements InfoRequest and DefaultedInfoRequest (with a pointer receiver).
+var _ InfoRequest = ReleaseInfo{}
+var _ DefaultedInfoRequest = &ReleaseInfo{}
+
+// ReleaseInfo contains high-level information about a release, i.e. a tag with a title,
+// release notes and assets.
+type ReleaseInfo struct {
+	// TagName is the name of the tag of the release, which identifies the release in the
+	// repository.
+	// +required
+	TagName string `json:"tag_name"`
+
+	// TargetCommitish is the branch or commit sha the tag is created from, if it doesn't exist
+	// when the release is created. It's ignored otherwise.
+	// Default: the default branch of the repository.
+	// +optional
+	TargetCommitish string `json:"target_commitish"`
+
+	// Name is the title of the release.
+	// Default: TagName.
+	// +optional
+	Name string `json:"name"`
+
+	// Body is the description of the release, i.e. the release notes, usually in Markdown.
+	// +optional
+	Body string `json:"body"`
+
+	// Draft specifies whether the release is an unpublished draft.
+	// +optional
+	Draft bool `json:"draft"`
+
+	// Prerelease specifies whether the release is marked as not ready for production.
+	// +optional
+	Prerelease bool `json:"prerelease"`
+
+	// CreatedAt is the time the release was created.
+	// +optional
+	CreatedAt time.Time `json:"created_at"`
+
+	// WebURL is the URL of the release in the git provider web interface.
+	// +optional
+	WebURL string `json:"web_url"`
+}
+
+// Default defaults the Release fields.
+func (r *ReleaseInfo) Default() {
+	if len(r.Name) == 0 {
+		r.Name = r.TagName
+	}
+}
+
+// ValidateInfo validates the object at {Object}.Set() and POST-time.
+func (r ReleaseInfo) ValidateInfo() error {
+	validator := validation.New("Release")
+	// Make sure we've set the tag of the release
+	if len(r.TagName) == 0 {
+		validator.Required("TagName")
+	}
+	return validator.Error()
+}
+
+// Equals can be used to check if this *Info request (the desired state) matches the actual
+// passed in as the argument. Only the fields set by ReleaseClient.Reconcile are compared,
+// i.e. the tag name, name, body, and whether the release is a draft or a prerelease.
+func (r ReleaseInfo) Equals(actual InfoRequest) bool {
+	a, ok := actual.(ReleaseInfo)
+	if !ok {
+		return false
+	}
+	return r.TagName == a.TagName &&
+		r.Name == a.Name &&
+		r.Body == a.Body &&
+		r.Draft == a.Draft &&
+		r.Prerelease == a.Prerelease
+}
+
+// ReleaseAssetInfo contains high-level information about a file attached to a release.
+type ReleaseAssetInfo struct {
+	// Name is the file name of the asset, which identifies it in the release.
+	// +required
+	Name string `json:"name"`
+
+	// Size is the size of the asset in bytes, or zero if the provider doesn't report it.
+	// +optional
+	Size int64 `json:"size"`
+
+	// ContentType is the media type of the asset, if reported by the provider.
+	// +optional
+	ContentType string `json:"content_type"`
+
+	// DownloadURL is the URL the asset can be downloaded from in a web browser.
+	// +optional
+	DownloadURL string `json:"download_url"`
+}
+
+// WebhookInfo implements InfoRequest and DefaultedInfoRequest (with a pointer receiver).
+var _ InfoRequest = WebhookInfo{}
+var _ DefaultedInfoRequest = &WebhookInfo{}
+
+// WebhookInfo contains high-level information about a webhook of a repository or organization.
+type WebhookInfo struct {
+	// ID is the identifier of the webhook, set by the provider.
+	// +optional
+	ID int64 `json:"id,omitempty"`
+
+	// URL is the URL the events are delivered to, which identifies the webhook.
+	// +required
+	URL string `json:"url"`
+
+	// ContentType specifies how the payloads are encoded.
+	// Default: "json".
+	// +optional
+	ContentType *WebhookContentType `json:"contentType,omitempty"`
+
+	// Secret is used by the receiver to verify the payloads, e.g. as the key of a signature in
+	// GitHub, or as a token in GitLab. The providers never return it, hence it's sent whenever
+	// the webhook is created or updated, but changes of only the secret aren't detected.
+	// +optional
+	Secret string `json:"secret,omitempty"`
+
+	// Events are the kinds of events triggering the webhook. The order is not significant.
+	// Events of the provider which can't be expressed as a WebhookEvent are ignored.
+	// Default: ["push"].
+	// +optional
+	Events []WebhookEvent `json:"events,omitempty"`
+
+	// SSLVerification specifies whether the TLS certificate of URL is verified.
+	// Default: true.
+	// +optional
+	SSLVerification *bool `json:"sslVerification,omitempty"`
+
+	// Active specifies whether events are delivered.
+	// Default: true.
+	// +optional
+	Active *bool `json:"active,omitempty"`
+}
+
+// Default defaults the Webhook fields.
+func (wh *WebhookInfo) Default() {
+	if wh.ContentType == nil {
+		wh.ContentType = WebhookContentTypeVar(defaultWebhookContentType)
+	}
+	if len(wh.Events) == 0 {
+		wh.Events = []WebhookEvent{defaultWebhookEvent}
+	}
+	if wh.SSLVerification == nil {
+		wh.SSLVerification = BoolVar(true)
+	}
+	if wh.Active == nil {
+		wh.Active = BoolVar(true)
+	}
+}
+
+// ValidateInfo validates the object at {Object}.Set() and POST-time.
+func (wh WebhookInfo) ValidateInfo() error {
+	validator := validation.New("Webhook")
+	// Make sure we've set the URL of the webhook
+	if len(wh.URL) == 0 {
+		validator.Required("URL")
+	}
+	if wh.ContentType != nil {
+		validator.Append(ValidateWebhookContentType(*wh.ContentType), *wh.ContentType, "ContentType")
+	}
+	for _, event := range wh.Events {
+		validator.Append(ValidateWebhookEvent(event), event, "Events")
+	}
+	return validator.Error()
+}
+
+// Equals can be used to check if this *Info request (the desired state) matches the actual
+// passed in as the argument. The ID and the secret aren't compared, and the events are
+// compared in any order.
+func (wh WebhookInfo) Equals(actual InfoRequest) bool {
+	a, ok := actual.(WebhookInfo)
+	if !ok {
+		return false
+	}
+	if !unorderedEqual(webhookEventStrings(wh.Events), webhookEventStrings(a.Events)) {
+		return false
+	}
+	// The events are equal, compare the rest of the fields
+	wh.ID, a.ID = 0, 0
+	wh.Secret, a.Secret = "", ""
+	wh.Events, a.Events = nil, nil
+	return reflect.DeepEqual(wh, a)
+}
+
+// webhookEventStrings converts events to strings, e.g. for comparing them with unorderedEqual.
+func webhookEventStrings(events []WebhookEvent) []string {
+	strs := make([]string, 0, len(events))
+	for _, event := range events {
+		strs = append(strs, string(event))
+	}
+	return strs
+}
+
+// CommitStatusInfo implements InfoRequest and DefaultedInfoRequest (with a pointer receiver).
+var _ InfoRequest = CommitStatusInfo{}
+var _ DefaultedInfoRequest = &CommitStatusInfo{}
+
+// CommitStatusInfo contains high-level information about a status of a commit, e.g. the
+// result of a CI build.
+type CommitStatusInfo struct {
+	// State is the state of the check.
+	// +required
+	State CommitStatusState `json:"state"`
+
+	// Context identifies the check among the statuses of the commit, e.g. "ci/build". A newer
+	// status with the same context replaces the older one in the combined status of the commit.
+	// Default: "default".
+	// +optional
+	Context string `json:"context"`
+
+	// Description is a short description of the status.
+	// +optional
+	Description string `json:"description"`
+
+	// TargetURL is the URL with details about the check, e.g. the build log.
+	// +optional
+	TargetURL string `json:"target_url"`
+
+	// Creator is the login of the user who created the status.
+	// +optional
+	Creator string `json:"creator"`
+
+	// CreatedAt is the time the status was created.
+	// +optional
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Default defaults the CommitStatus fields.
+func (cs *CommitStatusInfo) Default() {
+	if len(cs.Context) == 0 {
+		cs.Context = defaultCommitStatusContext
+	}
+}
+
+// ValidateInfo validates the object at {Object}.Set() and POST-time.
+func (cs CommitStatusInfo) ValidateInfo() error {
+	validator := validation.New("CommitStatus")
+	// The state is needed to create the status
+	if len(cs.State) == 0 {
+		validator.Required("State")
+	} else {
+		validator.Append(ValidateCommitStatusState(cs.State), cs.State, "State")
+	}
+	return validator.Error()
+}
+
+// Equals can be used to check if this *Info request (the desired state) matches the actual
+// passed in as the argument.
+func (cs CommitStatusInfo) Equals(actual InfoRequest) bool {
+	return reflect.DeepEqual(cs, actual)
+}
+
+// CombinedCommitStatusInfo contains the combined status of a commit, i.e. the latest status
+// of each context.
+type CombinedCommitStatusInfo struct {
+	// Sha is the git sha of the commit.
+	// +required
+	Sha string `json:"sha"`
+
+	// State is the combined state, as returned by CombineCommitStatusStates.
+	// +required
+	State CommitStatusState `json:"state"`
+
+	// Statuses are the latest statuses of each context.
+	// +optional
+	Statuses []CommitStatusInfo `json:"statuses"`
+}
+
+// CombineCommitStatusStates returns the combined state of the given latest statuses of each
+// context, like GitHub does: "failure" if any status is "error" or "failure", otherwise
+// "pending" if there are no statuses or any status is "pending", and "success" otherwise.
+func CombineCommitStatusStates(statuses []CommitStatusInfo) CommitStatusState {
+	if len(statuses) == 0 {
+		return CommitStatusStatePending
+	}
+	state := CommitStatusStateSuccess
+	for _, status := range statuses {
+		switch status.State {
+		case CommitStatusStateError, CommitStatusStateFailure:
+			return CommitStatusStateFailure
+		case CommitStatusStatePending:
+			state = CommitStatusStatePending
+		}
+	}
+	return state
+}
+
+// IssueInfo implements InfoRequest and DefaultedInfoRequest (with a pointer receiver).
+var _ InfoRequest = IssueInfo{}
+var _ DefaultedInfoRequest = &IssueInfo{}
+
+// IssueInfo contains high-level information about an issue.
+type IssueInfo struct {
+	// Number is the number identifying the issue in the repository. It is set by the server.
+	// For GitLab, this is the IID of the issue.
+	// +optional
+	Number int `json:"number"`
+
+	// Title is the title of the issue.
+	// +required
+	Title string `json:"title"`
+
+	// Body is the description of the issue, usually in Markdown.
+	// +optional
+	Body string `json:"body"`
+
+	// State is the state of the issue. Issues are always created open, use IssueClient.Close
+	// and IssueClient.Reopen to change the state.
+	// Default: "open".
+	// +optional
+	State IssueState `json:"state"`
+
+	// Labels are the names of the labels of the issue. Missing labels are created by the
+	// provider.
+	// +optional
+	Labels []string `json:"labels"`
+
+	// Assignees are the logins of the users the issue is assigned to.
+	// +optional
+	Assignees []string `json:"assignees"`
+
+	// Author is the login of the user who opened the issue.
+	// +optional
+	Author string `json:"author"`
+
+	// CreatedAt is the time the issue was opened.
+	// +optional
+	CreatedAt time.Time `json:"created_at"`
+
+	// UpdatedAt is the time the issue was last updated.
+	// +optional
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// ClosedAt is the time the issue was closed, or nil if it is open.
+	// +optional
+	ClosedAt *time.Time `json:"closed_at"`
+
+	// WebURL is the URL of the issue in the git provider web interface.
+	// +optional
+	WebURL string `json:"web_url"`
+}
+
+// Default defaults the Issue fields.
+func (i *IssueInfo) Default() {
+	if len(i.State) == 0 {
+		i.State = defaultIssueState
+	}
+}
+
+// ValidateInfo validates the object at {Object}.Set() and POST-time.
+func (i IssueInfo) ValidateInfo() error {
+	validator := validation.New("Issue")
+	// The title is needed to create the issue
+	if len(i.Title) == 0 {
+		validator.Required("Title")
+	}
+	// The state is optional, but if set, it must be known
+	if len(i.State) != 0 {
+		validator.Append(ValidateIssueState(i.State), i.State, "State")
+	}
+	return validator.Error()
+}
+
+// Equals can be used to check if this *Info request (the desired state) matches the actual
+// passed in as the argument. Only the fields that can be changed are compared, i.e. the title,
+// body and state, and the labels and assignees (in any order).
+func (i IssueInfo) Equals(actual InfoRequest) bool {
+	a, ok := actual.(IssueInfo)
+	if !ok {
+		return false
+	}
+	return i.Title == a.Title &&
+		i.Body == a.Body &&
+		i.State == a.State &&
+		unorderedEqual(i.Labels, a.Labels) &&
+		unorderedEqual(i.Assignees, a.Assignees)
+}
+
+// IssueCommentInfo contains high-level information about an issue comment.
+type IssueCommentInfo struct {
+	// ID is the identifier of the comment, used for updating and deleting it.
+	// +required
+	ID int64 `json:"id"`
+
+	// Body is the text of the comment, usually in Markdown.
+	// +required
+	Body string `json:"body"`
+
+	// Author is the login of the user who wrote the comment.
+	// +optional
+	Author string `json:"author"`
+
+	// CreatedAt is the time the comment was created.
+	// +optional
+	CreatedAt time.Time `json:"created_at"`
+
+	// UpdatedAt is the time the comment was last updated.
+	// +optional
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -19,6 +19,7 @@ package gitprovider
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/fluxcd/go-git-providers/validation"
 )
@@ -154,3 +155,615 @@ func TestTeamAccess_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestPullRequest_Validate(t *testing.T) {
+	tests := []struct {
+		name         string
+		pr           PullRequestInfo
+		expectedErrs []error
+	}{
+		{
+			name: "valid reconcile, required fields set",
+			pr: PullRequestInfo{
+				Title:      "foo-title",
+				HeadBranch: "feature",
+				BaseBranch: "main",
+			},
+		},
+		{
+			name: "invalid reconcile, missing title",
+			pr: PullRequestInfo{
+				HeadBranch: "feature",
+				BaseBranch: "main",
+			},
+			expectedErrs: []error{validation.ErrFieldRequired},
+		},
+		{
+			name: "invalid reconcile, missing branches",
+			pr: PullRequestInfo{
+				Title: "foo-title",
+			},
+			expectedErrs: []error{validation.ErrFieldRequired},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertValidation(t, "PullRequest", tt.pr.ValidateInfo, tt.expectedErrs)
+		})
+	}
+}
+
+func TestPullRequestInfo_Equals(t *testing.T) {
+	desired := PullRequestInfo{
+		Title:       "foo-title",
+		Description: "foo-description",
+		HeadBranch:  "feature",
+		BaseBranch:  "main",
+		Labels:      []string{"a", "b"},
+	}
+	tests := []struct {
+		name   string
+		actual InfoRequest
+		want   bool
+	}{
+		{
+			name: "equal, ignoring status fields and label order",
+			actual: PullRequestInfo{
+				Number:      1,
+				Title:       "foo-title",
+				Description: "foo-description",
+				HeadBranch:  "feature",
+				BaseBranch:  "main",
+				State:       PullRequestStateOpen,
+				Labels:      []string{"b", "a"},
+			},
+			want: true,
+		},
+		{
+			name: "different title",
+			actual: PullRequestInfo{
+				Title:       "bar-title",
+				Description: "foo-description",
+				HeadBranch:  "feature",
+				BaseBranch:  "main",
+				Labels:      []string{"a", "b"},
+			},
+		},
+		{
+			name: "different labels",
+			actual: PullRequestInfo{
+				Title:       "foo-title",
+				Description: "foo-description",
+				HeadBranch:  "feature",
+				BaseBranch:  "main",
+				Labels:      []string{"a", "a"},
+			},
+		},
+		{
+			name:   "different type",
+			actual: DeployKeyInfo{Name: "foo-title"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := desired.Equals(tt.actual); got != tt.want {
+				t.Errorf("PullRequestInfo.Equals() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommitFile_Validate(t *testing.T) {
+	tests := []struct {
+		name         string
+		file         CommitFile
+		expectedErrs []error
+	}{
+		{
+			name: "valid, create or update",
+			file: CommitFile{Path: StringVar("foo.txt"), Content: StringVar("foo")},
+		},
+		{
+			name: "valid, delete",
+			file: CommitFile{Path: StringVar("foo.txt")},
+		},
+		{
+			name: "valid, move without content",
+			file: CommitFile{Action: CommitFileActionVar(CommitFileActionMove), Path: StringVar("bar.txt"), PreviousPath: StringVar("foo.txt")},
+		},
+		{
+			name: "valid, executable binary file",
+			file: CommitFile{Path: StringVar("foo"), Content: StringVar("AAEC"), Encoding: ContentEncodingVar(ContentEncodingBase64), Mode: FileModeVar(FileModeExecutable)},
+		},
+		{
+			name:         "invalid, missing path",
+			file:         CommitFile{Content: StringVar("foo")},
+			expectedErrs: []error{validation.ErrFieldRequired},
+		},
+		{
+			name:         "invalid, update without content",
+			file:         CommitFile{Action: CommitFileActionVar(CommitFileActionUpdate), Path: StringVar("foo.txt")},
+			expectedErrs: []error{validation.ErrFieldRequired},
+		},
+		{
+			name:         "invalid, move without previous path",
+			file:         CommitFile{Action: CommitFileActionVar(CommitFileActionMove), Path: StringVar("bar.txt")},
+			expectedErrs: []error{validation.ErrFieldRequired},
+		},
+		{
+			name:         "invalid, move to the previous path",
+			file:         CommitFile{Action: CommitFileActionVar(CommitFileActionMove), Path: StringVar("foo.txt"), PreviousPath: StringVar("foo.txt")},
+			expectedErrs: []error{validation.ErrFieldInvalid},
+		},
+		{
+			name:         "invalid, delete with content",
+			file:         CommitFile{Action: CommitFileActionVar(CommitFileActionDelete), Path: StringVar("foo.txt"), Content: StringVar("foo")},
+			expectedErrs: []error{validation.ErrFieldInvalid},
+		},
+		{
+			name:         "invalid, unknown action",
+			file:         CommitFile{Action: CommitFileActionVar("copy"), Path: StringVar("foo.txt"), Content: StringVar("foo")},
+			expectedErrs: []error{validation.ErrFieldEnumInvalid},
+		},
+		{
+			name:         "invalid, unknown mode",
+			file:         CommitFile{Path: StringVar("foo.txt"), Content: StringVar("foo"), Mode: FileModeVar("100600")},
+			expectedErrs: []error{validation.ErrFieldEnumInvalid},
+		},
+		{
+			name:         "invalid, symbolic link",
+			file:         CommitFile{Path: StringVar("foo.txt"), Content: StringVar("foo"), Mode: FileModeVar(FileModeSymlink)},
+			expectedErrs: []error{validation.ErrFieldInvalid},
+		},
+		{
+			name:         "invalid, malformed base64 content",
+			file:         CommitFile{Path: StringVar("foo"), Content: StringVar("!"), Encoding: ContentEncodingVar(ContentEncodingBase64)},
+			expectedErrs: []error{validation.ErrFieldInvalid},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertValidation(t, "CommitFile", tt.file.ValidateInfo, tt.expectedErrs)
+		})
+	}
+}
+
+func TestBranchProtection_Validate(t *testing.T) {
+	tests := []struct {
+		name         string
+		bp           BranchProtectionInfo
+		expectedErrs []error
+	}{
+		{
+			name: "valid, only branch",
+			bp:   BranchProtectionInfo{Branch: "main"},
+		},
+		{
+			name: "valid, with approvals",
+			bp:   BranchProtectionInfo{Branch: "main", RequiredApprovals: IntVar(1)},
+		},
+		{
+			name:         "invalid, missing branch",
+			bp:           BranchProtectionInfo{},
+			expectedErrs: []error{validation.ErrFieldRequired},
+		},
+		{
+			name:         "invalid, negative approvals",
+			bp:           BranchProtectionInfo{Branch: "main", RequiredApprovals: IntVar(-1)},
+			expectedErrs: []error{validation.ErrFieldInvalid},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertValidation(t, "BranchProtection", tt.bp.ValidateInfo, tt.expectedErrs)
+		})
+	}
+}
+
+func TestBranchProtectionInfo_Equals(t *testing.T) {
+	desired := BranchProtectionInfo{
+		Branch:               "main",
+		RequiredApprovals:    IntVar(1),
+		RequiredStatusChecks: []string{"build", "test"},
+	}
+	tests := []struct {
+		name   string
+		actual InfoRequest
+		want   bool
+	}{
+		{
+			name: "equal",
+			actual: BranchProtectionInfo{
+				Branch:               "main",
+				RequiredApprovals:    IntVar(1),
+				RequiredStatusChecks: []string{"build", "test"},
+			},
+			want: true,
+		},
+		{
+			name: "status checks in another order",
+			actual: BranchProtectionInfo{
+				Branch:               "main",
+				RequiredApprovals:    IntVar(1),
+				RequiredStatusChecks: []string{"test", "build"},
+			},
+			want: true,
+		},
+		{
+			name: "different status checks",
+			actual: BranchProtectionInfo{
+				Branch:               "main",
+				RequiredApprovals:    IntVar(1),
+				RequiredStatusChecks: []string{"build"},
+			},
+		},
+		{
+			name: "different approvals",
+			actual: BranchProtectionInfo{
+				Branch:               "main",
+				RequiredApprovals:    IntVar(2),
+				RequiredStatusChecks: []string{"build", "test"},
+			},
+		},
+		{
+			name:   "different type",
+			actual: DeployKeyInfo{Name: "main"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := desired.Equals(tt.actual); got != tt.want {
+				t.Errorf("BranchProtectionInfo.Equals() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTag_Validate(t *testing.T) {
+	tests := []struct {
+		name         string
+		tag          TagInfo
+		expectedErrs []error
+	}{
+		{
+			name: "valid, lightweight",
+			tag:  TagInfo{Name: "v1.0.0", Sha: "abc"},
+		},
+		{
+			name: "valid, annotated",
+			tag:  TagInfo{Name: "v1.0.0", Sha: "abc", Message: "First release"},
+		},
+		{
+			name:         "invalid, missing name and sha",
+			tag:          TagInfo{},
+			expectedErrs: []error{validation.ErrFieldRequired},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertValidation(t, "Tag", tt.tag.ValidateInfo, tt.expectedErrs)
+		})
+	}
+}
+
+func TestRelease_Validate(t *testing.T) {
+	tests := []struct {
+		name         string
+		release      ReleaseInfo
+		expectedErrs []error
+	}{
+		{
+			name:    "valid, only tag",
+			release: ReleaseInfo{TagName: "v1.0.0"},
+		},
+		{
+			name:         "invalid, missing tag",
+			release:      ReleaseInfo{Name: "First release"},
+			expectedErrs: []error{validation.ErrFieldRequired},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertValidation(t, "Release", tt.release.ValidateInfo, tt.expectedErrs)
+		})
+	}
+}
+
+func TestReleaseInfo_Equals(t *testing.T) {
+	desired := ReleaseInfo{
+		TagName:         "v1.0.0",
+		TargetCommitish: "main",
+		Name:            "First release",
+		Body:            "Notes",
+	}
+	tests := []struct {
+		name   string
+		actual InfoRequest
+		want   bool
+	}{
+		{
+			name: "equal, ignoring the target and read-only fields",
+			actual: ReleaseInfo{
+				TagName:   "v1.0.0",
+				Name:      "First release",
+				Body:      "Notes",
+				CreatedAt: time.Now(),
+				WebURL:    "https://example.com/releases/v1.0.0",
+			},
+			want: true,
+		},
+		{
+			name:   "different body",
+			actual: ReleaseInfo{TagName: "v1.0.0", Name: "First release"},
+		},
+		{
+			name:   "draft",
+			actual: ReleaseInfo{TagName: "v1.0.0", Name: "First release", Body: "Notes", Draft: true},
+		},
+		{
+			name:   "different type",
+			actual: TagInfo{Name: "v1.0.0"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := desired.Equals(tt.actual); got != tt.want {
+				t.Errorf("ReleaseInfo.Equals() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebhook_Validate(t *testing.T) {
+	tests := []struct {
+		name         string
+		webhook      WebhookInfo
+		expectedErrs []error
+	}{
+		{
+			name:    "valid, only URL",
+			webhook: WebhookInfo{URL: "https://example.com/hook"},
+		},
+		{
+			name: "valid, all fields",
+			webhook: WebhookInfo{
+				URL:         "https://example.com/hook",
+				ContentType: WebhookContentTypeVar(WebhookContentTypeForm),
+				Secret:      "secret",
+				Events:      []WebhookEvent{WebhookEventPush, WebhookEventPullRequest},
+			},
+		},
+		{
+			name:         "invalid, missing URL",
+			webhook:      WebhookInfo{Events: []WebhookEvent{WebhookEventPush}},
+			expectedErrs: []error{validation.ErrFieldRequired},
+		},
+		{
+			name:         "invalid, unknown event",
+			webhook:      WebhookInfo{URL: "https://example.com/hook", Events: []WebhookEvent{"deployment"}},
+			expectedErrs: []error{validation.ErrFieldEnumInvalid},
+		},
+		{
+			name:         "invalid, unknown content type",
+			webhook:      WebhookInfo{URL: "https://example.com/hook", ContentType: WebhookContentTypeVar("xml")},
+			expectedErrs: []error{validation.ErrFieldEnumInvalid},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertValidation(t, "Webhook", tt.webhook.ValidateInfo, tt.expectedErrs)
+		})
+	}
+}
+
+func TestWebhookInfo_Equals(t *testing.T) {
+	desired := WebhookInfo{
+		URL:    "https://example.com/hook",
+		Secret: "secret",
+		Events: []WebhookEvent{WebhookEventPush, WebhookEventTag},
+		Active: BoolVar(true),
+	}
+	tests := []struct {
+		name   string
+		actual InfoRequest
+		want   bool
+	}{
+		{
+			name: "equal, except for the ID and the unknown secret",
+			actual: WebhookInfo{
+				ID:     1,
+				URL:    "https://example.com/hook",
+				Events: []WebhookEvent{WebhookEventPush, WebhookEventTag},
+				Active: BoolVar(true),
+			},
+			want: true,
+		},
+		{
+			name: "events in another order",
+			actual: WebhookInfo{
+				URL:    "https://example.com/hook",
+				Events: []WebhookEvent{WebhookEventTag, WebhookEventPush},
+				Active: BoolVar(true),
+			},
+			want: true,
+		},
+		{
+			name: "different events",
+			actual: WebhookInfo{
+				URL:    "https://example.com/hook",
+				Events: []WebhookEvent{WebhookEventPush},
+				Active: BoolVar(true),
+			},
+		},
+		{
+			name: "inactive",
+			actual: WebhookInfo{
+				URL:    "https://example.com/hook",
+				Events: []WebhookEvent{WebhookEventPush, WebhookEventTag},
+				Active: BoolVar(false),
+			},
+		},
+		{
+			name:   "different type",
+			actual: DeployKeyInfo{Name: "https://example.com/hook"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := desired.Equals(tt.actual); got != tt.want {
+				t.Errorf("WebhookInfo.Equals() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommitStatus_Validate(t *testing.T) {
+	tests := []struct {
+		name         string
+		status       CommitStatusInfo
+		expectedErrs []error
+	}{
+		{
+			name:   "valid, only state",
+			status: CommitStatusInfo{State: CommitStatusStateError},
+		},
+		{
+			name: "valid, all fields",
+			status: CommitStatusInfo{
+				State:       CommitStatusStateSuccess,
+				Context:     "ci/build",
+				Description: "The build succeeded",
+				TargetURL:   "https://ci.example.com/builds/1",
+			},
+		},
+		{
+			name:         "invalid, missing state",
+			status:       CommitStatusInfo{Context: "ci/build"},
+			expectedErrs: []error{validation.ErrFieldRequired},
+		},
+		{
+			name:         "invalid, unknown state",
+			status:       CommitStatusInfo{State: "running"},
+			expectedErrs: []error{validation.ErrFieldEnumInvalid},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertValidation(t, "CommitStatus", tt.status.ValidateInfo, tt.expectedErrs)
+		})
+	}
+}
+
+func TestIssue_Validate(t *testing.T) {
+	tests := []struct {
+		name         string
+		issue        IssueInfo
+		expectedErrs []error
+	}{
+		{
+			name:  "valid, only title",
+			issue: IssueInfo{Title: "Drift detected"},
+		},
+		{
+			name: "valid, all fields",
+			issue: IssueInfo{
+				Title:     "Drift detected",
+				Body:      "The cluster doesn't match the repository",
+				State:     IssueStateOpen,
+				Labels:    []string{"drift"},
+				Assignees: []string{"octocat"},
+			},
+		},
+		{
+			name:         "invalid, missing title",
+			issue:        IssueInfo{Body: "foo"},
+			expectedErrs: []error{validation.ErrFieldRequired},
+		},
+		{
+			name:         "invalid, unknown state",
+			issue:        IssueInfo{Title: "foo", State: "merged"},
+			expectedErrs: []error{validation.ErrFieldEnumInvalid},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertValidation(t, "Issue", tt.issue.ValidateInfo, tt.expectedErrs)
+		})
+	}
+}
+
+func TestIssueInfo_Equals(t *testing.T) {
+	desired := IssueInfo{
+		Title:     "foo-title",
+		Body:      "foo-body",
+		State:     IssueStateOpen,
+		Labels:    []string{"a", "b"},
+		Assignees: []string{"alice", "bob"},
+	}
+	tests := []struct {
+		name   string
+		actual InfoRequest
+		want   bool
+	}{
+		{
+			name: "equal, ignoring status fields and order",
+			actual: IssueInfo{
+				Number:    1,
+				Title:     "foo-title",
+				Body:      "foo-body",
+				State:     IssueStateOpen,
+				Labels:    []string{"b", "a"},
+				Assignees: []string{"bob", "alice"},
+				Author:    "carol",
+			},
+			want: true,
+		},
+		{
+			name:   "different state",
+			actual: IssueInfo{Title: "foo-title", Body: "foo-body", State: IssueStateClosed, Labels: []string{"a", "b"}, Assignees: []string{"alice", "bob"}},
+			want:   false,
+		},
+		{
+			name:   "different assignees",
+			actual: IssueInfo{Title: "foo-title", Body: "foo-body", State: IssueStateOpen, Labels: []string{"a", "b"}, Assignees: []string{"alice"}},
+			want:   false,
+		},
+		{
+			name:   "different type",
+			actual: PullRequestInfo{Title: "foo-title"},
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := desired.Equals(tt.actual); got != tt.want {
+				t.Errorf("IssueInfo.Equals() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCombineCommitStatusStates(t *testing.T) {
+	tests := []struct {
+		name   string
+		states []CommitStatusState
+		want   CommitStatusState
+	}{
+		{name: "no statuses", want: CommitStatusStatePending},
+		{name: "all succeeded", states: []CommitStatusState{CommitStatusStateSuccess, CommitStatusStateSuccess}, want: CommitStatusStateSuccess},
+		{name: "one pending", states: []CommitStatusState{CommitStatusStateSuccess, CommitStatusStatePending}, want: CommitStatusStatePending},
+		{name: "one failed", states: []CommitStatusState{CommitStatusStatePending, CommitStatusStateFailure}, want: CommitStatusStateFailure},
+		{name: "errors are failures", states: []CommitStatusState{CommitStatusStateError, CommitStatusStateSuccess}, want: CommitStatusStateFailure},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statuses := make([]CommitStatusInfo, 0, len(tt.states))
+			for _, state := range tt.states {
+				statuses = append(statuses, CommitStatusInfo{State: state})
+			}
+			if got := CombineCommitStatusStates(statuses); got != tt.want {
+				t.Errorf("CombineCommitStatusStates() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -26,6 +26,11 @@ func BoolVar(b bool) *bool {
 	return &b
 }
 
+// IntVar returns a pointer to the given int.
+func IntVar(i int) *int {
+	return &i
+}
+
 // StringVar returns a pointer to the given string.
 func StringVar(s string) *string {
 	return &s
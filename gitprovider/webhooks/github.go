@@ -0,0 +1,275 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/go-github/v32/github"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+const (
+	// providerGitHub equals github.ProviderID of this repository's github package.
+	providerGitHub = gitprovider.ProviderID("github")
+
+	githubEventHeader     = "X-GitHub-Event"
+	githubDeliveryHeader  = "X-GitHub-Delivery"
+	githubSignatureHeader = "X-Hub-Signature-256"
+	// githubSignaturePrefix is the prefix of the hex-encoded HMAC in githubSignatureHeader.
+	githubSignaturePrefix = "sha256="
+
+	githubOwnerTypeOrganization = "Organization"
+	githubRefTypeTag            = "tag"
+)
+
+// githubPingEvent adds the fields of ping payloads missing in github.PingEvent.
+type githubPingEvent struct {
+	github.PingEvent
+	Repo   *github.Repository `json:"repository,omitempty"`
+	Sender *github.User       `json:"sender,omitempty"`
+}
+
+// parseGitHub verifies and decodes the GitHub delivery r with the given body.
+func (p *Parser) parseGitHub(r *http.Request, body []byte) (*Event, error) {
+	if len(p.secret) != 0 {
+		// Only accept the SHA-256 signature, not the legacy SHA-1 one
+		signature := r.Header.Get(githubSignatureHeader)
+		if !strings.HasPrefix(signature, githubSignaturePrefix) || github.ValidateSignature(signature, body, p.secret) != nil {
+			return nil, ErrInvalidSignature
+		}
+	}
+
+	// Webhooks with the form content type send the JSON payload as the "payload" form value
+	payload := body
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+		}
+		payload = []byte(form.Get("payload"))
+	}
+
+	event := &Event{
+		Provider:   providerGitHub,
+		DeliveryID: r.Header.Get(githubDeliveryHeader),
+	}
+	var err error
+	switch eventType := r.Header.Get(githubEventHeader); eventType {
+	case "push":
+		err = parseGitHubPush(event, payload)
+	case "create", "delete":
+		err = parseGitHubTag(event, payload, eventType == "delete")
+	case "pull_request":
+		err = parseGitHubPullRequest(event, payload)
+	case "ping":
+		err = parseGitHubPing(event, payload)
+	default:
+		err = fmt.Errorf("%w: GitHub %q event", ErrUnsupportedEvent, eventType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+func parseGitHubPush(event *Event, payload []byte) error {
+	apiObj := &github.PushEvent{}
+	if err := decode(payload, apiObj); err != nil {
+		return err
+	}
+	repo, err := githubRepositoryRef(apiObj.GetRepo().GetHTMLURL(), apiObj.GetRepo().GetOwner())
+	if err != nil {
+		return err
+	}
+	event.Repository = repo
+	event.Sender = apiObj.GetSender().GetLogin()
+
+	switch ref := apiObj.GetRef(); {
+	case strings.HasPrefix(ref, branchRefPrefix):
+		event.Type = EventTypePush
+		event.Push = &PushEvent{
+			Branch:  strings.TrimPrefix(ref, branchRefPrefix),
+			Before:  refSha(apiObj.GetBefore()),
+			After:   refSha(apiObj.GetAfter()),
+			Commits: make([]gitprovider.CommitInfo, 0, len(apiObj.Commits)),
+		}
+		for _, commit := range apiObj.Commits {
+			event.Push.Commits = append(event.Push.Commits, githubCommitFromAPI(commit))
+		}
+	case strings.HasPrefix(ref, tagRefPrefix):
+		event.Type = EventTypeTag
+		event.Tag = &TagEvent{
+			Name:    strings.TrimPrefix(ref, tagRefPrefix),
+			Deleted: apiObj.GetDeleted(),
+		}
+		// After is the sha of the tag object for annotated tags, the head commit is the tagged one
+		if !event.Tag.Deleted {
+			event.Tag.Sha = apiObj.GetHeadCommit().GetID()
+		}
+	default:
+		return fmt.Errorf("%w: GitHub push to %q", ErrUnsupportedEvent, ref)
+	}
+	return nil
+}
+
+// parseGitHubTag decodes the "create" and "delete" events of tags.
+func parseGitHubTag(event *Event, payload []byte, deleted bool) error {
+	// The payloads of both events have the same fields
+	apiObj := &github.CreateEvent{}
+	if err := decode(payload, apiObj); err != nil {
+		return err
+	}
+	if apiObj.GetRefType() != githubRefTypeTag {
+		return fmt.Errorf("%w: GitHub event for a %s", ErrUnsupportedEvent, apiObj.GetRefType())
+	}
+	repo, err := githubRepositoryRef(apiObj.GetRepo().GetHTMLURL(), apiObj.GetRepo().GetOwner())
+	if err != nil {
+		return err
+	}
+	event.Type = EventTypeTag
+	event.Repository = repo
+	event.Sender = apiObj.GetSender().GetLogin()
+	event.Tag = &TagEvent{
+		Name:    apiObj.GetRef(),
+		Deleted: deleted,
+	}
+	return nil
+}
+
+func parseGitHubPullRequest(event *Event, payload []byte) error {
+	apiObj := &github.PullRequestEvent{}
+	if err := decode(payload, apiObj); err != nil {
+		return err
+	}
+	repo, err := githubRepositoryRef(apiObj.GetRepo().GetHTMLURL(), apiObj.GetRepo().GetOwner())
+	if err != nil {
+		return err
+	}
+	pr := apiObj.GetPullRequest()
+	event.Type = EventTypePullRequest
+	event.Repository = repo
+	event.Sender = apiObj.GetSender().GetLogin()
+	event.PullRequest = &PullRequestEvent{
+		Action:      githubPullRequestAction(apiObj.GetAction(), pr.GetMerged()),
+		PullRequest: githubPullRequestFromAPI(pr),
+		HeadSha:     pr.GetHead().GetSHA(),
+	}
+	return nil
+}
+
+func parseGitHubPing(event *Event, payload []byte) error {
+	apiObj := &githubPingEvent{}
+	if err := decode(payload, apiObj); err != nil {
+		return err
+	}
+	// Organization webhooks aren't pinged for a repository
+	if apiObj.Repo != nil {
+		repo, err := githubRepositoryRef(apiObj.Repo.GetHTMLURL(), apiObj.Repo.GetOwner())
+		if err != nil {
+			return err
+		}
+		event.Repository = repo
+	}
+	event.Type = EventTypePing
+	event.Sender = apiObj.Sender.GetLogin()
+	event.Ping = &PingEvent{HookID: apiObj.GetHookID()}
+	return nil
+}
+
+// githubRepositoryRef returns the reference of the repository with the given web URL and owner.
+func githubRepositoryRef(webURL string, owner *github.User) (gitprovider.RepositoryRef, error) {
+	return repositoryRef(webURL, owner.GetType() != githubOwnerTypeOrganization)
+}
+
+// githubCommitFromAPI converts a pushed commit. The dates of its author and committer aren't
+// sent, but the timestamp is the date of the commit.
+func githubCommitFromAPI(apiObj *github.HeadCommit) gitprovider.CommitInfo {
+	info := gitprovider.CommitInfo{
+		Sha:     apiObj.GetID(),
+		TreeSha: apiObj.GetTreeID(),
+		Message: apiObj.GetMessage(),
+		WebURL:  apiObj.GetURL(),
+	}
+	if apiObj.Author != nil {
+		info.Author = &gitprovider.CommitAuthor{
+			Name:  apiObj.Author.GetName(),
+			Email: apiObj.Author.GetEmail(),
+			Date:  apiObj.GetTimestamp().Time,
+		}
+	}
+	if apiObj.Committer != nil {
+		info.Committer = &gitprovider.CommitAuthor{
+			Name:  apiObj.Committer.GetName(),
+			Email: apiObj.Committer.GetEmail(),
+			Date:  apiObj.GetTimestamp().Time,
+		}
+	}
+	return info
+}
+
+func githubPullRequestFromAPI(apiObj *github.PullRequest) gitprovider.PullRequestInfo {
+	info := gitprovider.PullRequestInfo{
+		Number:      apiObj.GetNumber(),
+		Title:       apiObj.GetTitle(),
+		Description: apiObj.GetBody(),
+		HeadBranch:  apiObj.GetHead().GetRef(),
+		BaseBranch:  apiObj.GetBase().GetRef(),
+		State:       gitprovider.PullRequestStateOpen,
+		Author:      apiObj.GetUser().GetLogin(),
+		Draft:       apiObj.GetDraft(),
+		Mergeable:   apiObj.Mergeable,
+		CreatedAt:   apiObj.GetCreatedAt(),
+		UpdatedAt:   apiObj.GetUpdatedAt(),
+		MergedAt:    apiObj.MergedAt,
+		ClosedAt:    apiObj.ClosedAt,
+		WebURL:      apiObj.GetHTMLURL(),
+	}
+	// GitHub reports merged pull requests as closed
+	if apiObj.GetState() != "open" {
+		info.State = gitprovider.PullRequestStateClosed
+		if apiObj.GetMerged() {
+			info.State = gitprovider.PullRequestStateMerged
+		}
+	}
+	for _, label := range apiObj.Labels {
+		info.Labels = append(info.Labels, label.GetName())
+	}
+	return info
+}
+
+// githubPullRequestAction maps the "opened", "reopened" and "closed" actions, and considers all
+// others (e.g. "synchronize", "edited" and "labeled") as updates.
+func githubPullRequestAction(action string, merged bool) PullRequestAction {
+	switch action {
+	case "opened":
+		return PullRequestActionOpened
+	case "reopened":
+		return PullRequestActionReopened
+	case "closed":
+		if merged {
+			return PullRequestActionMerged
+		}
+		return PullRequestActionClosed
+	default:
+		return PullRequestActionUpdated
+	}
+}
@@ -0,0 +1,242 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/xanzy/go-gitlab"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+const (
+	// providerGitLab equals gitlab.ProviderID of this repository's gitlab package.
+	providerGitLab = gitprovider.ProviderID("gitlab")
+
+	gitlabEventHeader    = "X-Gitlab-Event"
+	gitlabTokenHeader    = "X-Gitlab-Token"
+	gitlabDeliveryHeader = "X-Gitlab-Event-UUID"
+)
+
+// gitlabTimeFormats are the formats of times in merge request payloads, which changed between
+// GitLab versions.
+//
+//nolint:gochecknoglobals
+var gitlabTimeFormats = []string{time.RFC3339, "2006-01-02 15:04:05 MST", "2006-01-02 15:04:05 -0700"}
+
+// gitlabTagEvent adds the fields of tag push payloads missing in gitlab.TagEvent.
+type gitlabTagEvent struct {
+	gitlab.TagEvent
+	UserUsername string `json:"user_username"`
+}
+
+// parseGitLab verifies and decodes the GitLab delivery r with the given body.
+func (p *Parser) parseGitLab(r *http.Request, body []byte) (*Event, error) {
+	// GitLab sends the secret itself, compare it in constant time
+	if len(p.secret) != 0 && subtle.ConstantTimeCompare([]byte(r.Header.Get(gitlabTokenHeader)), p.secret) != 1 {
+		return nil, ErrInvalidSignature
+	}
+
+	event := &Event{
+		Provider:   providerGitLab,
+		DeliveryID: r.Header.Get(gitlabDeliveryHeader),
+	}
+	var err error
+	switch eventType := gitlab.HookEventType(r); eventType {
+	case gitlab.EventTypePush:
+		err = parseGitLabPush(event, body)
+	case gitlab.EventTypeTagPush:
+		err = parseGitLabTag(event, body)
+	case gitlab.EventTypeMergeRequest:
+		err = parseGitLabMergeRequest(event, body)
+	default:
+		err = fmt.Errorf("%w: GitLab %q event", ErrUnsupportedEvent, eventType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+func parseGitLabPush(event *Event, payload []byte) error {
+	apiObj := &gitlab.PushEvent{}
+	if err := decode(payload, apiObj); err != nil {
+		return err
+	}
+	if !strings.HasPrefix(apiObj.Ref, branchRefPrefix) {
+		return fmt.Errorf("%w: GitLab push to %q", ErrUnsupportedEvent, apiObj.Ref)
+	}
+	repo, err := gitlabRepositoryRef(apiObj.Project.WebURL, apiObj.Project.PathWithNamespace, apiObj.UserUsername)
+	if err != nil {
+		return err
+	}
+	event.Type = EventTypePush
+	event.Repository = repo
+	event.Sender = apiObj.UserUsername
+	event.Push = &PushEvent{
+		Branch:  strings.TrimPrefix(apiObj.Ref, branchRefPrefix),
+		Before:  refSha(apiObj.Before),
+		After:   refSha(apiObj.After),
+		Commits: make([]gitprovider.CommitInfo, 0, len(apiObj.Commits)),
+	}
+	for _, commit := range apiObj.Commits {
+		info := gitprovider.CommitInfo{
+			Sha:     commit.ID,
+			Message: commit.Message,
+			Author: &gitprovider.CommitAuthor{
+				Name:  commit.Author.Name,
+				Email: commit.Author.Email,
+			},
+			WebURL: commit.URL,
+		}
+		if commit.Timestamp != nil {
+			info.Author.Date = *commit.Timestamp
+		}
+		event.Push.Commits = append(event.Push.Commits, info)
+	}
+	return nil
+}
+
+func parseGitLabTag(event *Event, payload []byte) error {
+	apiObj := &gitlabTagEvent{}
+	if err := decode(payload, apiObj); err != nil {
+		return err
+	}
+	repo, err := gitlabRepositoryRef(apiObj.Project.WebURL, apiObj.Project.PathWithNamespace, apiObj.UserUsername)
+	if err != nil {
+		return err
+	}
+	event.Type = EventTypeTag
+	event.Repository = repo
+	event.Sender = apiObj.UserUsername
+	// The checkout sha is the tagged commit, and After the tag object for annotated tags
+	event.Tag = &TagEvent{
+		Name:    strings.TrimPrefix(apiObj.Ref, tagRefPrefix),
+		Sha:     apiObj.CheckoutSHA,
+		Deleted: refSha(apiObj.After) == "",
+	}
+	return nil
+}
+
+func parseGitLabMergeRequest(event *Event, payload []byte) error {
+	apiObj := &gitlab.MergeEvent{}
+	if err := decode(payload, apiObj); err != nil {
+		return err
+	}
+	var username string
+	if apiObj.User != nil {
+		username = apiObj.User.Username
+	}
+	repo, err := gitlabRepositoryRef(apiObj.Project.WebURL, apiObj.Project.PathWithNamespace, username)
+	if err != nil {
+		return err
+	}
+	attrs := apiObj.ObjectAttributes
+	info := gitprovider.PullRequestInfo{
+		Number:      attrs.IID,
+		Title:       attrs.Title,
+		Description: attrs.Description,
+		HeadBranch:  attrs.SourceBranch,
+		BaseBranch:  attrs.TargetBranch,
+		State:       gitlabPullRequestState(attrs.State),
+		Draft:       attrs.WorkInProgress,
+		Mergeable:   gitlabMergeable(attrs.MergeStatus),
+		CreatedAt:   gitlabTime(attrs.CreatedAt),
+		UpdatedAt:   gitlabTime(attrs.UpdatedAt),
+		WebURL:      attrs.URL,
+	}
+	for _, label := range apiObj.Labels {
+		info.Labels = append(info.Labels, label.Name)
+	}
+	event.Type = EventTypePullRequest
+	event.Repository = repo
+	event.Sender = username
+	event.PullRequest = &PullRequestEvent{
+		Action:      gitlabPullRequestAction(attrs.Action),
+		PullRequest: info,
+		HeadSha:     attrs.LastCommit.ID,
+	}
+	return nil
+}
+
+// gitlabRepositoryRef returns the reference of the project with the given web URL and path. As
+// group and user namespaces share the same paths, the project is a user repository if it's in
+// the namespace of username.
+func gitlabRepositoryRef(webURL, pathWithNamespace, username string) (gitprovider.RepositoryRef, error) {
+	user := len(username) != 0 && strings.EqualFold(path.Dir(pathWithNamespace), username)
+	return repositoryRef(webURL, user)
+}
+
+// gitlabPullRequestState maps the merge request states "opened", "closed", "locked" and
+// "merged" to a PullRequestState. Locked merge requests are open, but being merged.
+func gitlabPullRequestState(state string) gitprovider.PullRequestState {
+	switch state {
+	case "merged":
+		return gitprovider.PullRequestStateMerged
+	case "closed":
+		return gitprovider.PullRequestStateClosed
+	default:
+		return gitprovider.PullRequestStateOpen
+	}
+}
+
+// gitlabMergeable returns nil if GitLab hasn't checked whether the merge request can be merged yet.
+func gitlabMergeable(mergeStatus string) *bool {
+	var mergeable bool
+	switch mergeStatus {
+	case "can_be_merged":
+		mergeable = true
+	case "cannot_be_merged":
+		mergeable = false
+	default:
+		return nil
+	}
+	return &mergeable
+}
+
+// gitlabTime parses a time of a merge request payload, and returns the zero time if it's invalid.
+func gitlabTime(value string) time.Time {
+	for _, format := range gitlabTimeFormats {
+		if t, err := time.Parse(format, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// gitlabPullRequestAction maps the "open", "reopen", "close" and "merge" actions, and considers
+// all others (e.g. "update" and "approved") as updates.
+func gitlabPullRequestAction(action string) PullRequestAction {
+	switch action {
+	case "open":
+		return PullRequestActionOpened
+	case "reopen":
+		return PullRequestActionReopened
+	case "close":
+		return PullRequestActionClosed
+	case "merge":
+		return PullRequestActionMerged
+	default:
+		return PullRequestActionUpdated
+	}
+}
@@ -0,0 +1,285 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package webhooks parses webhook deliveries of Git providers into provider-neutral events.
+//
+// The Parser detects the provider and event type of a delivery from its headers, verifies the
+// GitHub X-Hub-Signature-256 HMAC or GitLab X-Gitlab-Token secret, and decodes push, tag, pull
+// (merge) request and ping events. The repositories of events are gitprovider.OrgRepositoryRef
+// or gitprovider.UserRepositoryRef objects, and pushed commits gitprovider.CommitInfo objects.
+//
+// Parser.Handler returns an http.Handler for receiving deliveries:
+//
+//	parser := webhooks.NewParser([]byte(secret))
+//	http.Handle("/hook", parser.Handler(func(ctx context.Context, event *webhooks.Event) error {
+//		if event.Type == webhooks.EventTypePush {
+//			fmt.Printf("%s pushed to %s in %s\n", event.Sender, event.Push.Branch, event.Repository)
+//		}
+//		return nil
+//	}))
+//
+// The secret is the gitprovider.WebhookInfo.Secret the webhook was created with.
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+const (
+	// maxPayloadSize is the maximum size of a delivery, GitHub caps payloads at 25 MB.
+	maxPayloadSize = 25 << 20
+
+	// nullSha is sent instead of a sha for refs which don't exist before or after a push.
+	nullSha = "0000000000000000000000000000000000000000"
+
+	branchRefPrefix = "refs/heads/"
+	tagRefPrefix    = "refs/tags/"
+)
+
+var (
+	// ErrUnknownProvider is returned if the delivery isn't from a supported Git provider.
+	ErrUnknownProvider = errors.New("unknown webhook provider")
+	// ErrInvalidSignature is returned if the delivery isn't signed with the webhook secret.
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	// ErrUnsupportedEvent is returned for events which aren't decoded into an Event.
+	ErrUnsupportedEvent = errors.New("unsupported webhook event")
+	// ErrInvalidPayload is returned if the payload of the delivery can't be decoded.
+	ErrInvalidPayload = errors.New("invalid webhook payload")
+)
+
+// EventType is an enum specifying the type of a webhook event.
+type EventType string
+
+const (
+	// EventTypePush is a push to a branch.
+	EventTypePush = EventType("push")
+	// EventTypeTag is the creation or deletion of a tag.
+	EventTypeTag = EventType("tag")
+	// EventTypePullRequest is an action on a pull request, or GitLab merge request.
+	EventTypePullRequest = EventType("pull_request")
+	// EventTypePing is sent by GitHub when a webhook is created. GitLab sends push events
+	// for testing webhooks instead.
+	EventTypePing = EventType("ping")
+)
+
+// PullRequestAction is an enum specifying what happened to a pull request.
+type PullRequestAction string
+
+const (
+	// PullRequestActionOpened specifies that the pull request was opened.
+	PullRequestActionOpened = PullRequestAction("opened")
+	// PullRequestActionReopened specifies that the closed pull request was reopened.
+	PullRequestActionReopened = PullRequestAction("reopened")
+	// PullRequestActionClosed specifies that the pull request was closed without being merged.
+	PullRequestActionClosed = PullRequestAction("closed")
+	// PullRequestActionMerged specifies that the pull request was merged.
+	PullRequestActionMerged = PullRequestAction("merged")
+	// PullRequestActionUpdated specifies any other change, e.g. pushed commits, or changes of the
+	// title, labels or assignees.
+	PullRequestActionUpdated = PullRequestAction("updated")
+)
+
+// Event is a decoded webhook delivery. Depending on the Type, one of Push, Tag, PullRequest
+// and Ping is set.
+type Event struct {
+	// Provider is the ID of the provider which sent the event, e.g. "github" or "gitlab".
+	Provider gitprovider.ProviderID
+
+	// Type is the type of the event.
+	Type EventType
+
+	// DeliveryID is the unique ID of the delivery, if the provider sends one.
+	DeliveryID string
+
+	// Repository is the gitprovider.OrgRepositoryRef or gitprovider.UserRepositoryRef of the
+	// repository the event happened in. It's nil for ping events of organization webhooks.
+	//
+	// GitLab payloads don't tell whether the namespace of a project is a group or a user. As
+	// namespace paths are unique, projects in the namespace of the user triggering the event are
+	// in a gitprovider.UserRepositoryRef, and others in a gitprovider.OrgRepositoryRef.
+	Repository gitprovider.RepositoryRef
+
+	// Sender is the login of the user who triggered the event.
+	Sender string
+
+	// Push is set for EventTypePush events.
+	Push *PushEvent
+	// Tag is set for EventTypeTag events.
+	Tag *TagEvent
+	// PullRequest is set for EventTypePullRequest events.
+	PullRequest *PullRequestEvent
+	// Ping is set for EventTypePing events.
+	Ping *PingEvent
+}
+
+// PushEvent describes a push to a branch.
+type PushEvent struct {
+	// Branch is the name of the branch.
+	Branch string
+
+	// Before is the sha the branch pointed to before the push, or empty if it was created.
+	Before string
+
+	// After is the sha the branch points to after the push, or empty if it was deleted.
+	After string
+
+	// Commits are the pushed commits. Providers truncate the list for large pushes, and only
+	// send the Sha, Message, Author and WebURL of commits, and the TreeSha and Committer for GitHub.
+	Commits []gitprovider.CommitInfo
+}
+
+// TagEvent describes the creation or deletion of a tag.
+type TagEvent struct {
+	// Name is the name of the tag.
+	Name string
+
+	// Sha is the sha of the tagged commit. It's empty for deleted tags, and for tags created
+	// without pushing, which GitHub sends as "create" events.
+	Sha string
+
+	// Deleted is true if the tag was deleted.
+	Deleted bool
+}
+
+// PullRequestEvent describes an action on a pull request.
+type PullRequestEvent struct {
+	// Action is what happened to the pull request.
+	Action PullRequestAction
+
+	// PullRequest is the state of the pull request after the action. GitLab merge request
+	// payloads don't contain the author, merge and close times.
+	PullRequest gitprovider.PullRequestInfo
+
+	// HeadSha is the sha of the head commit of the pull request.
+	HeadSha string
+}
+
+// PingEvent describes the ping GitHub sends when a webhook is created.
+type PingEvent struct {
+	// HookID is the ID of the webhook.
+	HookID int64
+}
+
+// Parser verifies and decodes webhook deliveries.
+type Parser struct {
+	secret []byte
+}
+
+// NewParser returns a Parser verifying deliveries with the secret of the webhook. If secret is
+// empty, deliveries aren't verified, which is only intended for local development.
+func NewParser(secret []byte) *Parser {
+	return &Parser{secret: secret}
+}
+
+// Parse verifies and decodes the webhook delivery r, and consumes its body.
+//
+// ErrUnknownProvider is returned if r isn't a GitHub or GitLab delivery, ErrInvalidSignature if
+// the verification fails, ErrUnsupportedEvent for events other than push, tag, pull request and
+// ping events, and ErrInvalidPayload if the payload can't be decoded.
+func (p *Parser) Parse(r *http.Request) (*Event, error) {
+	var parse func(r *http.Request, body []byte) (*Event, error)
+	switch {
+	case len(r.Header.Get(githubEventHeader)) != 0:
+		parse = p.parseGitHub
+	case len(r.Header.Get(gitlabEventHeader)) != 0:
+		parse = p.parseGitLab
+	default:
+		return nil, ErrUnknownProvider
+	}
+
+	// Read one byte more than allowed, to detect too large payloads
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxPayloadSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading webhook payload: %w", err)
+	}
+	if len(body) > maxPayloadSize {
+		return nil, fmt.Errorf("%w: payload larger than %d bytes", ErrInvalidPayload, maxPayloadSize)
+	}
+	return parse(r, body)
+}
+
+// Handler returns an http.Handler which parses deliveries with p, and calls fn with the events.
+//
+// Deliveries failing verification are answered with 401 Unauthorized, and invalid ones with 400
+// Bad Request. Unsupported events are acknowledged with 202 Accepted without calling fn. If fn
+// returns an error, the response is 500 Internal Server Error, and otherwise 204 No Content.
+func (p *Parser) Handler(fn func(ctx context.Context, event *Event) error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		event, err := p.Parse(r)
+		switch {
+		case errors.Is(err, ErrUnsupportedEvent):
+			// Acknowledge the delivery, for the provider not to report it as failed
+			w.WriteHeader(http.StatusAccepted)
+			return
+		case errors.Is(err, ErrInvalidSignature):
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := fn(r.Context(), event); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// decode unmarshals the JSON payload into v.
+func decode(payload []byte, v interface{}) error {
+	if err := json.Unmarshal(payload, v); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+	return nil
+}
+
+// repositoryRef parses the web URL of a repository into a gitprovider.UserRepositoryRef if user
+// is true, and a gitprovider.OrgRepositoryRef otherwise.
+func repositoryRef(webURL string, user bool) (gitprovider.RepositoryRef, error) {
+	if user {
+		ref, err := gitprovider.ParseUserRepositoryURL(webURL)
+		if err != nil {
+			return nil, fmt.Errorf("%w: repository URL: %v", ErrInvalidPayload, err)
+		}
+		return *ref, nil
+	}
+	ref, err := gitprovider.ParseOrgRepositoryURL(webURL)
+	if err != nil {
+		return nil, fmt.Errorf("%w: repository URL: %v", ErrInvalidPayload, err)
+	}
+	return *ref, nil
+}
+
+// refSha returns sha, or an empty string for the null sha of missing refs.
+func refSha(sha string) string {
+	if sha == nullSha {
+		return ""
+	}
+	return sha
+}
@@ -0,0 +1,472 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+var testSecret = []byte("s3cr3t")
+
+// newRequest returns a delivery of the payload in testdata/fixture, with the given headers.
+func newRequest(t *testing.T, fixture string, headers map[string]string) *http.Request {
+	t.Helper()
+	payload, err := ioutil.ReadFile(filepath.Join("testdata", fixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := httptest.NewRequest(http.MethodPost, "/hook", bytes.NewReader(payload))
+	r.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		r.Header.Set(key, value)
+	}
+	return r
+}
+
+// newGitHubRequest returns a signed GitHub delivery of the payload in testdata/github/fixture.
+func newGitHubRequest(t *testing.T, event, fixture string) *http.Request {
+	t.Helper()
+	r := newRequest(t, filepath.Join("github", fixture), map[string]string{
+		githubEventHeader:    event,
+		githubDeliveryHeader: "72d3162e-cc78-11e3-81ab-4c9367dc0958",
+	})
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	r.Header.Set(githubSignatureHeader, sign(body))
+	return r
+}
+
+// newGitLabRequest returns a GitLab delivery of the payload in testdata/gitlab/fixture.
+func newGitLabRequest(t *testing.T, event, fixture string) *http.Request {
+	t.Helper()
+	return newRequest(t, filepath.Join("gitlab", fixture), map[string]string{
+		gitlabEventHeader:    event,
+		gitlabTokenHeader:    string(testSecret),
+		gitlabDeliveryHeader: "9cfd7d57-3d5f-4ab6-8a4b-d7fdd0ef1bc5",
+	})
+}
+
+func sign(body []byte) string {
+	mac := hmac.New(sha256.New, testSecret)
+	_, _ = mac.Write(body)
+	return githubSignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+func mustParseTime(t *testing.T, value string) time.Time {
+	t.Helper()
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+func TestParser_Parse(t *testing.T) {
+	githubOrgRepo := gitprovider.OrgRepositoryRef{
+		OrganizationRef: gitprovider.OrganizationRef{Domain: "github.com", Organization: "fluxcd-test", SubOrganizations: []string{}},
+		RepositoryName:  "podinfo",
+	}
+	mergedAt := mustParseTime(t, "2021-03-01T11:02:15Z")
+	tests := []struct {
+		name    string
+		r       *http.Request
+		want    *Event
+		wantErr error
+	}{
+		{
+			name: "GitHub push",
+			r:    newGitHubRequest(t, "push", "push.json"),
+			want: &Event{
+				Provider:   "github",
+				Type:       EventTypePush,
+				DeliveryID: "72d3162e-cc78-11e3-81ab-4c9367dc0958",
+				Repository: githubOrgRepo,
+				Sender:     "octocat",
+				Push: &PushEvent{
+					Branch: "main",
+					Before: "6113728f27ae82c7b1a177c8d03f9e96e0adf246",
+					After:  "0d1a26e67d8f5eaf1f6ba5c57fc3c7d91ac0fd1c",
+					Commits: []gitprovider.CommitInfo{{
+						Sha:     "0d1a26e67d8f5eaf1f6ba5c57fc3c7d91ac0fd1c",
+						TreeSha: "f9d2a07e9488b91af2641b26b9407fe22a451433",
+						Message: "Update README.md",
+						Author: &gitprovider.CommitAuthor{
+							Name:  "Monalisa Octocat",
+							Email: "octocat@github.com",
+							Date:  mustParseTime(t, "2021-03-01T12:45:26+01:00"),
+						},
+						Committer: &gitprovider.CommitAuthor{
+							Name:  "GitHub",
+							Email: "noreply@github.com",
+							Date:  mustParseTime(t, "2021-03-01T12:45:26+01:00"),
+						},
+						WebURL: "https://github.com/fluxcd-test/podinfo/commit/0d1a26e67d8f5eaf1f6ba5c57fc3c7d91ac0fd1c",
+					}},
+				},
+			},
+		},
+		{
+			name: "GitHub tag push to a user repository",
+			r:    newGitHubRequest(t, "push", "push_tag.json"),
+			want: &Event{
+				Provider:   "github",
+				Type:       EventTypeTag,
+				DeliveryID: "72d3162e-cc78-11e3-81ab-4c9367dc0958",
+				Repository: gitprovider.UserRepositoryRef{
+					UserRef:        gitprovider.UserRef{Domain: "github.com", UserLogin: "octocat"},
+					RepositoryName: "hello-world",
+				},
+				Sender: "octocat",
+				Tag:    &TagEvent{Name: "v1.0.0", Sha: "7fd1a60b01f91b314f59955a4e4d4e80d8edf11d"},
+			},
+		},
+		{
+			name: "GitHub tag deletion",
+			r:    newGitHubRequest(t, "delete", "delete_tag.json"),
+			want: &Event{
+				Provider:   "github",
+				Type:       EventTypeTag,
+				DeliveryID: "72d3162e-cc78-11e3-81ab-4c9367dc0958",
+				Repository: githubOrgRepo,
+				Sender:     "octocat",
+				Tag:        &TagEvent{Name: "v0.1.0", Deleted: true},
+			},
+		},
+		{
+			name:    "GitHub branch creation",
+			r:       newGitHubRequest(t, "create", "create_branch.json"),
+			wantErr: ErrUnsupportedEvent,
+		},
+		{
+			name: "GitHub merged pull request",
+			r:    newGitHubRequest(t, "pull_request", "pull_request.json"),
+			want: &Event{
+				Provider:   "github",
+				Type:       EventTypePullRequest,
+				DeliveryID: "72d3162e-cc78-11e3-81ab-4c9367dc0958",
+				Repository: githubOrgRepo,
+				Sender:     "octocat",
+				PullRequest: &PullRequestEvent{
+					Action: PullRequestActionMerged,
+					PullRequest: gitprovider.PullRequestInfo{
+						Number:      42,
+						Title:       "Update the README",
+						Description: "This is a pretty simple change that we need to pull into main.",
+						HeadBranch:  "readme",
+						BaseBranch:  "main",
+						State:       gitprovider.PullRequestStateMerged,
+						Labels:      []string{"documentation"},
+						Author:      "octocat",
+						CreatedAt:   mustParseTime(t, "2021-03-01T10:20:44Z"),
+						UpdatedAt:   mergedAt,
+						MergedAt:    &mergedAt,
+						ClosedAt:    &mergedAt,
+						WebURL:      "https://github.com/fluxcd-test/podinfo/pull/42",
+					},
+					HeadSha: "ec26c3e57ca3a959ca5aad62de7213c562f8c821",
+				},
+			},
+		},
+		{
+			name: "GitHub ping of an organization webhook",
+			r:    newGitHubRequest(t, "ping", "ping.json"),
+			want: &Event{
+				Provider:   "github",
+				Type:       EventTypePing,
+				DeliveryID: "72d3162e-cc78-11e3-81ab-4c9367dc0958",
+				Sender:     "octocat",
+				Ping:       &PingEvent{HookID: 287139645},
+			},
+		},
+		{
+			name:    "GitHub unsupported event",
+			r:       newGitHubRequest(t, "star", "ping.json"),
+			wantErr: ErrUnsupportedEvent,
+		},
+		{
+			name: "GitLab push to a subgroup project",
+			r:    newGitLabRequest(t, "Push Hook", "push.json"),
+			want: &Event{
+				Provider:   "gitlab",
+				Type:       EventTypePush,
+				DeliveryID: "9cfd7d57-3d5f-4ab6-8a4b-d7fdd0ef1bc5",
+				Repository: gitprovider.OrgRepositoryRef{
+					OrganizationRef: gitprovider.OrganizationRef{Domain: "gitlab.com", Organization: "fluxcd-test", SubOrganizations: []string{"apps"}},
+					RepositoryName:  "diaspora",
+				},
+				Sender: "jsmith",
+				Push: &PushEvent{
+					Branch: "main",
+					Before: "95790bf891e76fee5e1747ab589903a6a1f80f22",
+					After:  "da1560886d4f094c3e6c9ef40349f7d38b5d27d7",
+					Commits: []gitprovider.CommitInfo{
+						{
+							Sha:     "b6568db1bc1dcd7f8b4d5a946b0b91f9dacd7327",
+							Message: "Update Catalan translation to e38cb41.\n\nSee https://gitlab.com/gitlab-org/gitlab for more information",
+							Author: &gitprovider.CommitAuthor{
+								Name:  "Jordi Mallach",
+								Email: "jordi@softcatala.org",
+								Date:  mustParseTime(t, "2011-12-12T14:27:31+02:00"),
+							},
+							WebURL: "https://gitlab.com/fluxcd-test/apps/diaspora/-/commit/b6568db1bc1dcd7f8b4d5a946b0b91f9dacd7327",
+						},
+						{
+							Sha:     "da1560886d4f094c3e6c9ef40349f7d38b5d27d7",
+							Message: "fixed readme",
+							Author: &gitprovider.CommitAuthor{
+								Name:  "GitLab dev user",
+								Email: "gitlabdev@dv6700.(none)",
+								Date:  mustParseTime(t, "2012-01-03T23:36:29+02:00"),
+							},
+							WebURL: "https://gitlab.com/fluxcd-test/apps/diaspora/-/commit/da1560886d4f094c3e6c9ef40349f7d38b5d27d7",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "GitLab tag push to a user project",
+			r:    newGitLabRequest(t, "Tag Push Hook", "tag_push.json"),
+			want: &Event{
+				Provider:   "gitlab",
+				Type:       EventTypeTag,
+				DeliveryID: "9cfd7d57-3d5f-4ab6-8a4b-d7fdd0ef1bc5",
+				Repository: gitprovider.UserRepositoryRef{
+					UserRef:        gitprovider.UserRef{Domain: "gitlab.com", UserLogin: "jsmith"},
+					RepositoryName: "example",
+				},
+				Sender: "jsmith",
+				Tag:    &TagEvent{Name: "v1.0.0", Sha: "82b3d5ae55f7080f1e6022629cdb57bfae7cccc7"},
+			},
+		},
+		{
+			name: "GitLab opened merge request",
+			r:    newGitLabRequest(t, "Merge Request Hook", "merge_request.json"),
+			want: &Event{
+				Provider:   "gitlab",
+				Type:       EventTypePullRequest,
+				DeliveryID: "9cfd7d57-3d5f-4ab6-8a4b-d7fdd0ef1bc5",
+				Repository: gitprovider.OrgRepositoryRef{
+					OrganizationRef: gitprovider.OrganizationRef{Domain: "gitlab.com", Organization: "fluxcd-test", SubOrganizations: []string{}},
+					RepositoryName:  "gitlab-test",
+				},
+				Sender: "root",
+				PullRequest: &PullRequestEvent{
+					Action: PullRequestActionOpened,
+					PullRequest: gitprovider.PullRequestInfo{
+						Number:     1,
+						Title:      "MS-Viewport",
+						HeadBranch: "ms-viewport",
+						BaseBranch: "main",
+						State:      gitprovider.PullRequestStateOpen,
+						Labels:     []string{"API"},
+						CreatedAt:  mustParseTime(t, "2013-12-03T17:23:34Z"),
+						UpdatedAt:  mustParseTime(t, "2013-12-03T17:23:34Z"),
+						WebURL:     "https://gitlab.com/fluxcd-test/gitlab-test/-/merge_requests/1",
+					},
+					HeadSha: "da1560886d4f094c3e6c9ef40349f7d38b5d27d7",
+				},
+			},
+		},
+		{
+			name:    "GitLab unsupported event",
+			r:       newGitLabRequest(t, "Pipeline Hook", "push.json"),
+			wantErr: ErrUnsupportedEvent,
+		},
+		{
+			name:    "unknown provider",
+			r:       newRequest(t, "github/ping.json", nil),
+			wantErr: ErrUnknownProvider,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewParser(testSecret).Parse(tt.r)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Parser.Parse() error = %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parser.Parse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParser_Parse_verification(t *testing.T) {
+	tests := []struct {
+		name    string
+		secret  []byte
+		r       func() *http.Request
+		wantErr error
+	}{
+		{
+			name:   "GitHub",
+			secret: testSecret,
+			r:      func() *http.Request { return newGitHubRequest(t, "push", "push.json") },
+		},
+		{
+			name:   "GitHub without secret",
+			secret: nil,
+			r: func() *http.Request {
+				r := newGitHubRequest(t, "push", "push.json")
+				r.Header.Del(githubSignatureHeader)
+				return r
+			},
+		},
+		{
+			name:    "GitHub with another secret",
+			secret:  []byte("other"),
+			r:       func() *http.Request { return newGitHubRequest(t, "push", "push.json") },
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name:   "GitHub legacy signature",
+			secret: testSecret,
+			r: func() *http.Request {
+				r := newGitHubRequest(t, "push", "push.json")
+				r.Header.Del(githubSignatureHeader)
+				r.Header.Set("X-Hub-Signature", "sha1=2fd4e1c67a2d28fced849ee1bb76e7391b93eb12")
+				return r
+			},
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name:   "GitHub modified payload",
+			secret: testSecret,
+			r: func() *http.Request {
+				r := newGitHubRequest(t, "push", "push.json")
+				r.Body = ioutil.NopCloser(bytes.NewReader([]byte("{}")))
+				return r
+			},
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name:   "GitHub form payload",
+			secret: testSecret,
+			r: func() *http.Request {
+				payload, err := ioutil.ReadFile(filepath.Join("testdata", "github", "push.json"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				body := []byte(url.Values{"payload": {string(payload)}}.Encode())
+				r := httptest.NewRequest(http.MethodPost, "/hook", bytes.NewReader(body))
+				r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+				r.Header.Set(githubEventHeader, "push")
+				r.Header.Set(githubSignatureHeader, sign(body))
+				return r
+			},
+		},
+		{
+			name:   "GitLab",
+			secret: testSecret,
+			r:      func() *http.Request { return newGitLabRequest(t, "Push Hook", "push.json") },
+		},
+		{
+			name:    "GitLab with another token",
+			secret:  []byte("other"),
+			r:       func() *http.Request { return newGitLabRequest(t, "Push Hook", "push.json") },
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name:   "GitLab without token",
+			secret: testSecret,
+			r: func() *http.Request {
+				r := newGitLabRequest(t, "Push Hook", "push.json")
+				r.Header.Del(gitlabTokenHeader)
+				return r
+			},
+			wantErr: ErrInvalidSignature,
+		},
+		{
+			name:   "invalid payload",
+			secret: nil,
+			r: func() *http.Request {
+				r := newGitLabRequest(t, "Push Hook", "push.json")
+				r.Body = ioutil.NopCloser(bytes.NewReader([]byte("not json")))
+				return r
+			},
+			wantErr: ErrInvalidPayload,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event, err := NewParser(tt.secret).Parse(tt.r())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Parser.Parse() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && event.Type != EventTypePush {
+				t.Errorf("Parser.Parse() = %+v, want a push event", event)
+			}
+		})
+	}
+}
+
+func TestParser_Handler(t *testing.T) {
+	var received []*Event
+	handlerErr := errors.New("handler failed")
+	handler := NewParser(testSecret).Handler(func(_ context.Context, event *Event) error {
+		received = append(received, event)
+		if event.Type == EventTypePing {
+			return handlerErr
+		}
+		return nil
+	})
+
+	tests := []struct {
+		name       string
+		r          *http.Request
+		wantStatus int
+	}{
+		{name: "event", r: newGitHubRequest(t, "push", "push.json"), wantStatus: http.StatusNoContent},
+		{name: "handler error", r: newGitHubRequest(t, "ping", "ping.json"), wantStatus: http.StatusInternalServerError},
+		{name: "unsupported event", r: newGitLabRequest(t, "Note Hook", "push.json"), wantStatus: http.StatusAccepted},
+		{name: "invalid signature", r: newGitHubRequest(t, "push", "push.json"), wantStatus: http.StatusUnauthorized},
+		{name: "unknown provider", r: newRequest(t, "github/push.json", nil), wantStatus: http.StatusBadRequest},
+		{name: "method", r: httptest.NewRequest(http.MethodGet, "/hook", nil), wantStatus: http.StatusMethodNotAllowed},
+	}
+	tests[3].r.Header.Set(githubSignatureHeader, sign([]byte("{}")))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, tt.r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("Handler status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+		})
+	}
+	if len(received) != 2 {
+		t.Errorf("Handler called with %d events, want 2", len(received))
+	}
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stash
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// CommitClient implements the gitprovider.CommitClient interface.
+var _ gitprovider.CommitClient = &CommitClient{}
+
+// CommitClient operates on the commits for a specific repository.
+type CommitClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// ListPage lists all repository commits of the given page and page size.
+// ListPage returns all available repository commits
+// using multiple paginated requests if needed.
+func (c *CommitClient) ListPage(ctx context.Context, branch string, perPage, page int) ([]gitprovider.Commit, error) {
+	cs, err := c.listPage(ctx, branch, perPage, page)
+	if err != nil {
+		return nil, err
+	}
+	// Cast to the generic []gitprovider.Commit
+	commits := make([]gitprovider.Commit, 0, len(cs))
+	for _, commit := range cs {
+		commits = append(commits, commit)
+	}
+	return commits, nil
+}
+
+func (c *CommitClient) listPage(ctx context.Context, branch string, perPage, page int) ([]*commitType, error) {
+	// GET /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/commits
+	apiObjs, err := c.c.ListCommitsPage(ctx, projectKey(c.ref), c.ref.GetRepository(), branch, perPage, page)
+	if err != nil {
+		return nil, err
+	}
+
+	// Map the api object to our CommitType type
+	commits := make([]*commitType, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		// apiObj is already validated at ListCommitsPage
+		commits = append(commits, newCommit(c, apiObj))
+	}
+
+	return commits, nil
+}
+
+// Create creates a commit with the given specifications.
+//
+// The commit is created on top of the head of branch. New branches are created from the head of
+// the StartBranch given in opts.
+// Bitbucket Server can only change one file per commit, hence one commit is created for
+// every file, all with the same message. The last commit is returned. Files are written whether
+// they exist or not.
+//
+// ErrNoProviderSupport is returned for files which are deleted, moved or executable, as Bitbucket
+// Server can only create and update regular files through its API, and if opts has an Author or
+// a Committer, as it always commits as the authenticated user.
+//
+// ErrConflict is returned if the head of branch isn't the ExpectedParentSHA given in opts, or if
+// a file changed while the commits were being created.
+func (c *CommitClient) Create(ctx context.Context, branch string, message string, files []gitprovider.CommitFile, opts ...gitprovider.CommitCreateOption) (gitprovider.Commit, error) {
+	o, err := gitprovider.MakeCommitCreateOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no files added")
+	}
+	if o.Author != nil || o.Committer != nil {
+		return nil, fmt.Errorf("cannot set the author or committer: %w", gitprovider.ErrNoProviderSupport)
+	}
+	for _, file := range files {
+		if err := file.ValidateInfo(); err != nil {
+			return nil, err
+		}
+	}
+
+	// New branches start at the head of the start branch, if given
+	head, exists, err := c.head(ctx, branch)
+	if err != nil {
+		return nil, err
+	}
+	sourceBranch := ""
+	if !exists && o.StartBranch != nil {
+		sourceBranch = *o.StartBranch
+		if head, exists, err = c.head(ctx, sourceBranch); err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, fmt.Errorf("branch %q: %w", sourceBranch, gitprovider.ErrNotFound)
+		}
+	}
+	if !exists {
+		// Only the first branch of an empty repository can be created without a start branch
+		branches, err := c.c.ListBranches(ctx, projectKey(c.ref), c.ref.GetRepository())
+		if err != nil {
+			return nil, err
+		}
+		if len(branches) != 0 {
+			return nil, fmt.Errorf("branch %q: %w", branch, gitprovider.ErrNotFound)
+		}
+	}
+	if o.ConflictsWith(head) {
+		return nil, fmt.Errorf("branch %q is at %q, not %q: %w", branch, head, *o.ExpectedParentSHA, gitprovider.ErrConflict)
+	}
+
+	// PUT /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/browse/{path}
+	apiObj, err := c.c.CreateCommit(ctx, projectKey(c.ref), c.ref.GetRepository(), branch, sourceBranch, head, message, files)
+	if err != nil {
+		return nil, err
+	}
+	return newCommit(c, apiObj), nil
+}
+
+// head returns the SHA of the head of branch, and whether branch exists.
+func (c *CommitClient) head(ctx context.Context, branch string) (string, bool, error) {
+	// GET /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/branches
+	apiObj, err := c.c.GetBranch(ctx, projectKey(c.ref), c.ref.GetRepository(), branch)
+	if errors.Is(err, gitprovider.ErrNotFound) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return apiObj.LatestCommit, true, nil
+}
@@ -0,0 +1,251 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stash
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+const (
+	pullRequestStateOpen     = "OPEN"
+	pullRequestStateMerged   = "MERGED"
+	pullRequestStateDeclined = "DECLINED"
+)
+
+// PullRequestClient implements the gitprovider.PullRequestClient interface.
+var _ gitprovider.PullRequestClient = &PullRequestClient{}
+
+// PullRequestClient operates on the pull requests for a specific repository.
+type PullRequestClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+}
+
+// Get returns the pull request with the given ID.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *PullRequestClient) Get(ctx context.Context, number int) (gitprovider.PullRequest, error) {
+	// GET /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/pull-requests/{pullRequestId}
+	pr, err := c.c.GetPullRequest(ctx, projectKey(c.ref), c.ref.GetRepository(), number)
+	if err != nil {
+		return nil, err
+	}
+	return newPullRequest(c.clientContext, pr, c.ref), nil
+}
+
+// List lists the pull requests of the repository matching the given filters.
+//
+// List returns all available pull requests, using multiple paginated requests if needed.
+func (c *PullRequestClient) List(ctx context.Context, opts ...gitprovider.PullRequestListOption) ([]gitprovider.PullRequest, error) {
+	o, err := gitprovider.MakePullRequestListOptions(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("state", "ALL")
+	if o.State != nil {
+		query.Set("state", map[gitprovider.PullRequestState]string{
+			gitprovider.PullRequestStateOpen:   pullRequestStateOpen,
+			gitprovider.PullRequestStateMerged: pullRequestStateMerged,
+			gitprovider.PullRequestStateClosed: pullRequestStateDeclined,
+		}[*o.State])
+	}
+	// The server filters on one branch, either the source or the destination one.
+	// Any other filter is applied below.
+	if o.HeadBranch != nil {
+		query.Set("at", branchRef(*o.HeadBranch))
+		query.Set("direction", "OUTGOING")
+	} else if o.BaseBranch != nil {
+		query.Set("at", branchRef(*o.BaseBranch))
+		query.Set("direction", "INCOMING")
+	}
+
+	// GET /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/pull-requests
+	apiObjs, err := c.c.ListPullRequests(ctx, projectKey(c.ref), c.ref.GetRepository(), query)
+	if err != nil {
+		return nil, err
+	}
+
+	prs := make([]gitprovider.PullRequest, 0, len(apiObjs))
+	for _, apiObj := range apiObjs {
+		pr := newPullRequest(c.clientContext, apiObj, c.ref)
+		if o.Matches(pr.Get()) {
+			prs = append(prs, pr)
+		}
+	}
+	return prs, nil
+}
+
+// Create creates a pull request with the given specifications.
+func (c *PullRequestClient) Create(ctx context.Context, title, branch, baseBranch, description string) (gitprovider.PullRequest, error) {
+	// Both refs are in this repository
+	repo := &Repository{
+		Slug:    c.ref.GetRepository(),
+		Project: &Project{Key: projectKey(c.ref)},
+	}
+	req := &PullRequest{
+		Title:       title,
+		Description: description,
+		FromRef:     PullRequestRef{ID: branchRef(branch), Repository: repo},
+		ToRef:       PullRequestRef{ID: branchRef(baseBranch), Repository: repo},
+	}
+
+	// POST /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/pull-requests
+	pr, err := c.c.CreatePullRequest(ctx, projectKey(c.ref), c.ref.GetRepository(), req)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPullRequest(c.clientContext, pr, c.ref), nil
+}
+
+// Update changes the title, description and/or destination branch of the pull request with the
+// given ID. nil fields of opts are left unchanged.
+//
+// ErrNotFound is returned if the resource does not exist.
+// ErrNoProviderSupport is returned if labels are given.
+func (c *PullRequestClient) Update(ctx context.Context, number int, opts gitprovider.PullRequestUpdateOptions) (gitprovider.PullRequest, error) {
+	// Bitbucket Server pull requests don't have labels
+	if len(opts.Labels) != 0 {
+		return nil, fmt.Errorf("pull request labels: %w", gitprovider.ErrNoProviderSupport)
+	}
+	// Updates require the current version of the pull request
+	pr, err := c.c.GetPullRequest(ctx, projectKey(c.ref), c.ref.GetRepository(), number)
+	if err != nil {
+		return nil, err
+	}
+	req := &PullRequest{
+		Version:     pr.Version,
+		Title:       pr.Title,
+		Description: pr.Description,
+		ToRef:       PullRequestRef{ID: pr.ToRef.ID},
+	}
+	if opts.Title != nil {
+		req.Title = *opts.Title
+	}
+	if opts.Description != nil {
+		req.Description = *opts.Description
+	}
+	if opts.BaseBranch != nil {
+		req.ToRef.ID = branchRef(*opts.BaseBranch)
+	}
+
+	// PUT /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/pull-requests/{pullRequestId}
+	pr, err = c.c.UpdatePullRequest(ctx, projectKey(c.ref), c.ref.GetRepository(), number, req)
+	if err != nil {
+		return nil, err
+	}
+	return newPullRequest(c.clientContext, pr, c.ref), nil
+}
+
+// Merge merges the pull request with the given ID, using the "no-ff", "squash" or
+// "rebase-ff-only" strategy. message is the message of the merge or squash commit; an empty
+// message uses the server default.
+//
+// The strategy must be enabled for the repository, otherwise the server rejects the merge.
+func (c *PullRequestClient) Merge(ctx context.Context, number int, method gitprovider.MergeMethod, message string) error {
+	if err := gitprovider.ValidateMergeMethod(method); err != nil {
+		return fmt.Errorf("merge method %q: %w", method, gitprovider.ErrInvalidArgument)
+	}
+	req := &PullRequestMerge{
+		Message: message,
+		StrategyID: map[gitprovider.MergeMethod]string{
+			gitprovider.MergeMethodMerge:  "no-ff",
+			gitprovider.MergeMethodSquash: "squash",
+			gitprovider.MergeMethodRebase: "rebase-ff-only",
+		}[method],
+	}
+
+	// Merging requires the current version of the pull request
+	pr, err := c.c.GetPullRequest(ctx, projectKey(c.ref), c.ref.GetRepository(), number)
+	if err != nil {
+		return err
+	}
+	// POST /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/pull-requests/{pullRequestId}/merge
+	_, err = c.c.MergePullRequest(ctx, projectKey(c.ref), c.ref.GetRepository(), number, pr.Version, req)
+	return err
+}
+
+// Close declines the pull request with the given ID.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *PullRequestClient) Close(ctx context.Context, number int) (gitprovider.PullRequest, error) {
+	// Declining requires the current version of the pull request
+	pr, err := c.c.GetPullRequest(ctx, projectKey(c.ref), c.ref.GetRepository(), number)
+	if err != nil {
+		return nil, err
+	}
+	// POST /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/pull-requests/{pullRequestId}/decline
+	pr, err = c.c.DeclinePullRequest(ctx, projectKey(c.ref), c.ref.GetRepository(), number, pr.Version)
+	if err != nil {
+		return nil, err
+	}
+	return newPullRequest(c.clientContext, pr, c.ref), nil
+}
+
+// Reconcile makes sure the given desired state (req) becomes the actual state in the backing Git provider.
+// The pull request is looked up by req.HeadBranch and req.BaseBranch among the open pull
+// requests; the title and description of req are reconciled.
+//
+// If req doesn't exist under the hood, it is created (actionTaken == true).
+// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+// If req is already the actual state, this is a no-op (actionTaken == false).
+//
+// ErrNoProviderSupport is returned if req has labels.
+func (c *PullRequestClient) Reconcile(ctx context.Context, req gitprovider.PullRequestInfo) (gitprovider.PullRequest, bool, error) {
+	// First thing, validate the request
+	if err := req.ValidateInfo(); err != nil {
+		return nil, false, err
+	}
+	if len(req.Labels) != 0 {
+		return nil, false, fmt.Errorf("pull request labels: %w", gitprovider.ErrNoProviderSupport)
+	}
+
+	// Look for an open pull request from the head into the base branch
+	prs, err := c.List(ctx, &gitprovider.PullRequestListOptions{
+		State:      gitprovider.PullRequestStateVar(gitprovider.PullRequestStateOpen),
+		HeadBranch: &req.HeadBranch,
+		BaseBranch: &req.BaseBranch,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	// Create if not found
+	if len(prs) == 0 {
+		resp, err := c.Create(ctx, req.Title, req.HeadBranch, req.BaseBranch, req.Description)
+		return resp, true, err
+	}
+
+	// If the desired matches the actual state, just return the actual state
+	actual := prs[0]
+	if req.Equals(actual.Get()) {
+		return actual, false, nil
+	}
+
+	// Apply the desired state by running Update
+	resp, err := c.Update(ctx, actual.Get().Number, gitprovider.PullRequestUpdateOptions{
+		Title:       &req.Title,
+		Description: &req.Description,
+	})
+	return resp, true, err
+}
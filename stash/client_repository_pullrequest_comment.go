@@ -0,0 +1,107 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stash
+
+import (
+	"context"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+const (
+	activityActionCommented = "COMMENTED"
+	commentActionAdded      = "ADDED"
+)
+
+// PullRequestCommentClient implements the gitprovider.PullRequestCommentClient interface.
+var _ gitprovider.PullRequestCommentClient = &PullRequestCommentClient{}
+
+// PullRequestCommentClient operates on the comments of a specific pull request.
+type PullRequestCommentClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+	id  int
+}
+
+// List all comments of the pull request, oldest first. Comments on the diff, and replies, are
+// left out.
+//
+// List returns all available comments, using multiple paginated requests if needed.
+func (c *PullRequestCommentClient) List(ctx context.Context) ([]gitprovider.PullRequestComment, error) {
+	// The comments are only listed as part of the activities of the pull request, newest first
+	// GET /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/pull-requests/{pullRequestId}/activities
+	activities, err := c.c.ListPullRequestActivities(ctx, projectKey(c.ref), c.ref.GetRepository(), c.id)
+	if err != nil {
+		return nil, err
+	}
+
+	comments := []gitprovider.PullRequestComment{}
+	for i := len(activities) - 1; i >= 0; i-- {
+		activity := activities[i]
+		if activity.Action != activityActionCommented || activity.CommentAction != commentActionAdded ||
+			activity.Comment == nil || len(activity.CommentAnchor) != 0 {
+			continue
+		}
+		if err := validateCommentAPI(activity.Comment); err != nil {
+			return nil, err
+		}
+		comments = append(comments, newPullRequestComment(activity.Comment))
+	}
+	return comments, nil
+}
+
+// Create adds a comment with the given body to the pull request.
+func (c *PullRequestCommentClient) Create(ctx context.Context, body string) (gitprovider.PullRequestComment, error) {
+	// POST /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/pull-requests/{pullRequestId}/comments
+	apiObj, err := c.c.CreatePullRequestComment(ctx, projectKey(c.ref), c.ref.GetRepository(), c.id, body)
+	if err != nil {
+		return nil, err
+	}
+	return newPullRequestComment(apiObj), nil
+}
+
+// Update changes the body of the comment with the given ID.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *PullRequestCommentClient) Update(ctx context.Context, id int64, body string) (gitprovider.PullRequestComment, error) {
+	// Updates require the current version of the comment
+	// GET /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/pull-requests/{pullRequestId}/comments/{commentId}
+	comment, err := c.c.GetPullRequestComment(ctx, projectKey(c.ref), c.ref.GetRepository(), c.id, int(id))
+	if err != nil {
+		return nil, err
+	}
+	// PUT /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/pull-requests/{pullRequestId}/comments/{commentId}
+	apiObj, err := c.c.UpdatePullRequestComment(ctx, projectKey(c.ref), c.ref.GetRepository(), c.id, comment.ID, comment.Version, body)
+	if err != nil {
+		return nil, err
+	}
+	return newPullRequestComment(apiObj), nil
+}
+
+// Delete deletes the comment with the given ID.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (c *PullRequestCommentClient) Delete(ctx context.Context, id int64) error {
+	// Deletions require the current version of the comment
+	// GET /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/pull-requests/{pullRequestId}/comments/{commentId}
+	comment, err := c.c.GetPullRequestComment(ctx, projectKey(c.ref), c.ref.GetRepository(), c.id, int(id))
+	if err != nil {
+		return err
+	}
+	// DELETE /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/pull-requests/{pullRequestId}/comments/{commentId}
+	return c.c.DeletePullRequestComment(ctx, projectKey(c.ref), c.ref.GetRepository(), c.id, comment.ID, comment.Version)
+}
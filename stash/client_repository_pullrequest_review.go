@@ -0,0 +1,90 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stash
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+// PullRequestReviewClient implements the gitprovider.PullRequestReviewClient interface.
+var _ gitprovider.PullRequestReviewClient = &PullRequestReviewClient{}
+
+// PullRequestReviewClient operates on the reviews of a specific pull request. Bitbucket Server
+// doesn't have reviews, so the statuses of the reviewers, and comments, are used instead.
+type PullRequestReviewClient struct {
+	*clientContext
+	ref gitprovider.RepositoryRef
+	id  int
+}
+
+// List lists the reviewers of the pull request who approved it or marked it as needing work.
+// Reviews only commenting are comments, which are listed by PullRequest.Comments().
+func (c *PullRequestReviewClient) List(ctx context.Context) ([]gitprovider.PullRequestReview, error) {
+	// GET /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/pull-requests/{pullRequestId}
+	pr, err := c.c.GetPullRequest(ctx, projectKey(c.ref), c.ref.GetRepository(), c.id)
+	if err != nil {
+		return nil, err
+	}
+
+	reviews := make([]gitprovider.PullRequestReview, 0, len(pr.Reviewers))
+	for _, reviewer := range pr.Reviewers {
+		if reviewer.Status != participantStatusApproved && reviewer.Status != participantStatusNeedsWork {
+			continue
+		}
+		reviews = append(reviews, newParticipantReview(reviewer, nil))
+	}
+	return reviews, nil
+}
+
+// Submit approves the pull request, or adds a comment to it. A non-empty body is added as a
+// comment when approving.
+//
+// ErrNoProviderSupport is returned for PullRequestReviewStateChangesRequested, as marking a pull
+// request as needing work requires the user slug of the token.
+func (c *PullRequestReviewClient) Submit(ctx context.Context, state gitprovider.PullRequestReviewState, body string) (gitprovider.PullRequestReview, error) {
+	switch state {
+	case gitprovider.PullRequestReviewStateApproved:
+		// POST /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/pull-requests/{pullRequestId}/approve
+		participant, err := c.c.ApprovePullRequest(ctx, projectKey(c.ref), c.ref.GetRepository(), c.id)
+		if err != nil {
+			return nil, err
+		}
+		if body == "" {
+			return newParticipantReview(participant, nil), nil
+		}
+		// POST /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/pull-requests/{pullRequestId}/comments
+		comment, err := c.c.CreatePullRequestComment(ctx, projectKey(c.ref), c.ref.GetRepository(), c.id, body)
+		if err != nil {
+			return nil, err
+		}
+		return newParticipantReview(participant, comment), nil
+	case gitprovider.PullRequestReviewStateCommented:
+		// POST /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/pull-requests/{pullRequestId}/comments
+		comment, err := c.c.CreatePullRequestComment(ctx, projectKey(c.ref), c.ref.GetRepository(), c.id, body)
+		if err != nil {
+			return nil, err
+		}
+		return newCommentReview(comment), nil
+	case gitprovider.PullRequestReviewStateChangesRequested:
+		return nil, fmt.Errorf("review state %q: %w", state, gitprovider.ErrNoProviderSupport)
+	default:
+		return nil, fmt.Errorf("review state %q: %w", state, gitprovider.ErrInvalidArgument)
+	}
+}
@@ -0,0 +1,992 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stash
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+// newTestClient starts a server handling the API paths of mux, and returns a client pointing to it.
+func newTestClient(t *testing.T, mux *http.ServeMux, opts ...ClientOption) *Client {
+	t.Helper()
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	c, err := NewClient(append([]ClientOption{WithDomain(srv.URL)}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c.(*Client)
+}
+
+func writeJSON(t *testing.T, w http.ResponseWriter, status int, obj interface{}) {
+	t.Helper()
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(obj); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeError(t *testing.T, w http.ResponseWriter, status int, message, exceptionName string) {
+	t.Helper()
+	writeJSON(t, w, status, map[string]interface{}{
+		"errors": []ErrorDetail{{Message: message, ExceptionName: exceptionName}},
+	})
+}
+
+func writePage(t *testing.T, w http.ResponseWriter, values interface{}) {
+	t.Helper()
+	writeJSON(t, w, http.StatusOK, map[string]interface{}{
+		"values":     values,
+		"isLastPage": true,
+	})
+}
+
+func TestNewClient(t *testing.T) {
+	_, err := NewClient()
+	validation.TestExpectErrors(t, "NewClient", err, gitprovider.ErrInvalidClientOptions)
+
+	c, err := NewClient(WithDomain("stash.example.com/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := c.SupportedDomain(); got != "stash.example.com/" {
+		t.Errorf("SupportedDomain() = %q, want %q", got, "stash.example.com/")
+	}
+	if got := c.(*Client).c.(*stashClientImpl).domainURL; got != "https://stash.example.com" {
+		t.Errorf("domainURL = %q, want %q", got, "https://stash.example.com")
+	}
+	if got := c.ProviderID(); got != ProviderID {
+		t.Errorf("ProviderID() = %q, want %q", got, ProviderID)
+	}
+	_, err = c.HasTokenPermission(context.Background(), gitprovider.TokenPermissionRWRepository)
+	validation.TestExpectErrors(t, "HasTokenPermission", err, gitprovider.ErrNoProviderSupport)
+}
+
+func TestOrganizationsClient(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/api/1.0/projects/PRJ", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(t, w, http.StatusOK, Project{Key: "PRJ", Name: "Project", Description: "desc"})
+	})
+	mux.HandleFunc("/rest/api/1.0/projects/MISSING", func(w http.ResponseWriter, r *http.Request) {
+		writeError(t, w, http.StatusNotFound, "Project MISSING does not exist.", "com.atlassian.bitbucket.project.NoSuchProjectException")
+	})
+	mux.HandleFunc("/rest/api/1.0/projects", func(w http.ResponseWriter, r *http.Request) {
+		// Serve two pages, to test pagination
+		if r.URL.Query().Get("start") == "1" {
+			writeJSON(t, w, http.StatusOK, map[string]interface{}{
+				"values":     []Project{{Key: "OTHER"}},
+				"isLastPage": true,
+			})
+			return
+		}
+		writeJSON(t, w, http.StatusOK, map[string]interface{}{
+			"values":        []Project{{Key: "PRJ", Name: "Project"}},
+			"isLastPage":    false,
+			"nextPageStart": 1,
+		})
+	})
+	c := newTestClient(t, mux)
+	ctx := context.Background()
+
+	org, err := c.Organizations().Get(ctx, gitprovider.OrganizationRef{Domain: c.domain, Organization: "PRJ"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info := org.Get(); *info.Name != "Project" || *info.Description != "desc" {
+		t.Errorf("Organization.Get() = %+v", info)
+	}
+
+	_, err = c.Organizations().Get(ctx, gitprovider.OrganizationRef{Domain: c.domain, Organization: "MISSING"})
+	validation.TestExpectErrors(t, "Organizations().Get", err, gitprovider.ErrNotFound)
+
+	_, err = c.Organizations().Get(ctx, gitprovider.OrganizationRef{Domain: "other.com", Organization: "PRJ"})
+	validation.TestExpectErrors(t, "Organizations().Get", err, gitprovider.ErrDomainUnsupported)
+
+	_, err = c.Organizations().Get(ctx, gitprovider.OrganizationRef{Domain: c.domain, Organization: "PRJ", SubOrganizations: []string{"sub"}})
+	validation.TestExpectErrors(t, "Organizations().Get", err, gitprovider.ErrNoProviderSupport)
+
+	orgs, err := c.Organizations().List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orgs) != 2 || orgs[1].Organization().Organization != "OTHER" {
+		t.Errorf("Organizations().List() = %v, want PRJ and OTHER", orgs)
+	}
+
+	_, err = c.Organizations().Children(ctx, org.Organization())
+	validation.TestExpectErrors(t, "Organizations().Children", err, gitprovider.ErrNoProviderSupport)
+	_, err = org.Teams().List(ctx)
+	validation.TestExpectErrors(t, "Teams().List", err, gitprovider.ErrNoProviderSupport)
+}
+
+// fakeRepoServer is a minimal in-memory implementation of the repository endpoints.
+type fakeRepoServer struct {
+	t *testing.T
+	// repos by "{projectKey}/{slug}"
+	repos           map[string]*Repository
+	defaultBranches map[string]string
+	keys            []*AccessKey
+	// commits on the default branch, newest first
+	commits []Commit
+	// head commit IDs by branch name
+	branches map[string]string
+	prs      []*PullRequest
+	// activities of all pull requests, newest first
+	activities []*Activity
+}
+
+func (s *fakeRepoServer) register(mux *http.ServeMux) {
+	mux.HandleFunc("/rest/api/1.0/projects/", s.handle)
+	mux.HandleFunc("/rest/keys/1.0/projects/", s.handleKeys)
+	mux.HandleFunc("/rest/branch-utils/1.0/projects/", s.handleBranchUtils)
+}
+
+// repo returns the repository from a path of the form .../projects/{key}/repos/{slug}/...,
+// together with the remaining path segments.
+func (s *fakeRepoServer) repo(w http.ResponseWriter, path, prefix string) (*Repository, string, []string) {
+	// {key}/repos[/{slug}[/...]]
+	parts := strings.Split(strings.TrimPrefix(path, prefix), "/")
+	if len(parts) < 3 {
+		return nil, parts[0], nil
+	}
+	name := parts[0] + "/" + parts[2]
+	repo, ok := s.repos[name]
+	if !ok {
+		writeError(s.t, w, http.StatusNotFound, "Repository "+name+" does not exist.", "com.atlassian.bitbucket.repository.NoSuchRepositoryException")
+		return nil, name, nil
+	}
+	return repo, name, parts[3:]
+}
+
+func (s *fakeRepoServer) handle(w http.ResponseWriter, r *http.Request) {
+	t := s.t
+	parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/rest/api/1.0/projects/"), "/")
+	if len(parts) == 2 {
+		key := parts[0]
+		switch r.Method {
+		case http.MethodGet:
+			values := []*Repository{}
+			for name, repo := range s.repos {
+				if strings.HasPrefix(name, key+"/") {
+					values = append(values, repo)
+				}
+			}
+			writePage(t, w, values)
+		case http.MethodPost:
+			req := &Repository{}
+			if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+				t.Fatal(err)
+			}
+			req.Slug = strings.ToLower(req.Name)
+			if _, exists := s.repos[key+"/"+req.Slug]; exists {
+				writeError(t, w, http.StatusConflict, "This repository URL is already taken.", "com.atlassian.bitbucket.repository.DuplicateRepositoryNameException")
+				return
+			}
+			if req.Public == nil {
+				req.Public = gitprovider.BoolVar(false)
+			}
+			// The default branch is not part of the repository object
+			req.DefaultBranch = nil
+			req.Project = &Project{Key: key}
+			s.repos[key+"/"+req.Slug] = req
+			writeJSON(t, w, http.StatusCreated, req)
+		}
+		return
+	}
+
+	repo, name, rest := s.repo(w, r.URL.Path, "/rest/api/1.0/projects/")
+	if repo == nil {
+		return
+	}
+	if len(rest) == 0 {
+		switch r.Method {
+		case http.MethodGet:
+			writeJSON(t, w, http.StatusOK, repo)
+		case http.MethodPut:
+			req := &Repository{}
+			if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+				t.Fatal(err)
+			}
+			req.Slug = repo.Slug
+			req.DefaultBranch = nil
+			s.repos[name] = req
+			writeJSON(t, w, http.StatusOK, req)
+		case http.MethodDelete:
+			delete(s.repos, name)
+			w.WriteHeader(http.StatusAccepted)
+		}
+		return
+	}
+
+	switch rest[0] {
+	case "branches":
+		if len(rest) == 2 && rest[1] == "default" {
+			if r.Method == http.MethodPut {
+				req := &Branch{}
+				if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+					t.Fatal(err)
+				}
+				s.defaultBranches[name] = strings.TrimPrefix(req.ID, "refs/heads/")
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			branch, ok := s.defaultBranches[name]
+			if !ok {
+				writeError(t, w, http.StatusNotFound, "Repository "+name+" does not have a default branch.", "com.atlassian.bitbucket.repository.NoDefaultBranchException")
+				return
+			}
+			writeJSON(t, w, http.StatusOK, Branch{ID: "refs/heads/" + branch, DisplayID: branch})
+			return
+		}
+		s.handleBranches(w, r)
+	case "commits":
+		if len(s.commits) == 0 {
+			writeError(t, w, http.StatusNotFound, "Repository "+name+" is empty.", "com.atlassian.bitbucket.commit.NoSuchCommitException")
+			return
+		}
+		writePage(t, w, s.commits)
+	case "browse":
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatal(err)
+		}
+		if r.Header.Get("X-Atlassian-Token") != "no-check" {
+			t.Error("expected the X-Atlassian-Token header to be set")
+		}
+		// New branches are created from the source branch
+		head := r.FormValue("branch")
+		if _, ok := s.branches[head]; !ok && len(r.FormValue("sourceBranch")) != 0 {
+			head = r.FormValue("sourceBranch")
+		}
+		if r.FormValue("sourceCommitId") != s.branches[head] {
+			writeError(t, w, http.StatusConflict, "The file has been modified since it was last read.", "com.atlassian.bitbucket.content.FileContentModificationException")
+			return
+		}
+		// Commits are authored and committed by the authenticated user
+		commit := Commit{
+			ID:                 fmt.Sprintf("%040d", len(s.commits)+1),
+			Message:            fmt.Sprintf("%s\n%s=%s", r.FormValue("message"), strings.Join(rest[1:], "/"), r.FormValue("content")),
+			Author:             &User{Name: "admin", EmailAddress: "admin@example.com"},
+			AuthorTimestamp:    1614834367000,
+			Committer:          &User{Name: "admin", EmailAddress: "admin@example.com"},
+			CommitterTimestamp: 1614834367000,
+		}
+		if parent := s.branches[head]; len(parent) != 0 {
+			commit.Parents = []Commit{{ID: parent}}
+		}
+		s.commits = append([]Commit{commit}, s.commits...)
+		s.branches[r.FormValue("branch")] = commit.ID
+		writeJSON(t, w, http.StatusOK, commit)
+	case "pull-requests":
+		s.handlePullRequests(w, r, name, rest[1:])
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (s *fakeRepoServer) handlePullRequests(w http.ResponseWriter, r *http.Request, name string, rest []string) {
+	t := s.t
+	if len(rest) == 0 {
+		if r.Method == http.MethodGet {
+			// Only support the state and source branch filters used by the tests
+			query := r.URL.Query()
+			values := []*PullRequest{}
+			for _, pr := range s.prs {
+				if (query.Get("state") == "ALL" || query.Get("state") == pr.State) &&
+					(query.Get("at") == "" || query.Get("direction") == "OUTGOING" && query.Get("at") == pr.FromRef.ID) {
+					values = append(values, pr)
+				}
+			}
+			writePage(t, w, values)
+			return
+		}
+		req := &PullRequest{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			t.Fatal(err)
+		}
+		req.ID = len(s.prs) + 1
+		req.State = "OPEN"
+		req.Links = &Links{Self: []Link{{Href: fmt.Sprintf("https://stash.example.com/projects/%s/pull-requests/%d", strings.ToUpper(name), req.ID)}}}
+		s.prs = append(s.prs, req)
+		writeJSON(t, w, http.StatusCreated, req)
+		return
+	}
+
+	var pr *PullRequest
+	for _, p := range s.prs {
+		if fmt.Sprint(p.ID) == rest[0] {
+			pr = p
+		}
+	}
+	if pr == nil {
+		writeError(t, w, http.StatusNotFound, "Pull request "+rest[0]+" does not exist in "+name+".", "com.atlassian.bitbucket.pull.NoSuchPullRequestException")
+		return
+	}
+	if len(rest) > 1 {
+		switch rest[1] {
+		case "activities":
+			writePage(t, w, s.activities)
+			return
+		case "comments":
+			s.handleComments(w, r, rest[2:])
+			return
+		case "approve":
+			participant := &Participant{User: &User{Slug: "me"}, Role: "REVIEWER", Approved: true, Status: participantStatusApproved}
+			pr.Reviewers = append(pr.Reviewers, participant)
+			writeJSON(t, w, http.StatusOK, participant)
+			return
+		}
+	}
+	if r.Method == http.MethodGet {
+		writeJSON(t, w, http.StatusOK, pr)
+		return
+	}
+
+	// All changes require the current version
+	version := r.URL.Query().Get("version")
+	req := &PullRequest{}
+	if r.Method == http.MethodPut {
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			t.Fatal(err)
+		}
+		version = fmt.Sprint(req.Version)
+	}
+	if version != fmt.Sprint(pr.Version) {
+		writeError(t, w, http.StatusConflict, "You are attempting to modify a pull request based on out-of-date information.", "com.atlassian.bitbucket.pull.PullRequestOutOfDateException")
+		return
+	}
+	pr.Version++
+	switch {
+	case r.Method == http.MethodPut:
+		pr.Title, pr.Description, pr.ToRef.ID = req.Title, req.Description, req.ToRef.ID
+	case rest[1] == "merge":
+		merge := &PullRequestMerge{}
+		if err := json.NewDecoder(r.Body).Decode(merge); err != nil {
+			t.Fatal(err)
+		}
+		if merge.StrategyID != "squash" {
+			t.Errorf("unexpected merge strategy %q", merge.StrategyID)
+		}
+		pr.State = "MERGED"
+	case rest[1] == "decline":
+		pr.State = "DECLINED"
+	}
+	writeJSON(t, w, http.StatusOK, pr)
+}
+
+func (s *fakeRepoServer) handleComments(w http.ResponseWriter, r *http.Request, rest []string) {
+	t := s.t
+	if len(rest) == 0 {
+		req := &Comment{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			t.Fatal(err)
+		}
+		req.ID = len(s.activities) + 1
+		req.Author = &User{Slug: "me"}
+		s.activities = append([]*Activity{{ID: req.ID, Action: activityActionCommented, CommentAction: commentActionAdded, Comment: req}}, s.activities...)
+		writeJSON(t, w, http.StatusCreated, req)
+		return
+	}
+
+	var comment *Comment
+	index := 0
+	for i, a := range s.activities {
+		if a.Comment != nil && fmt.Sprint(a.Comment.ID) == rest[0] {
+			comment, index = a.Comment, i
+		}
+	}
+	if comment == nil {
+		writeError(t, w, http.StatusNotFound, "Comment "+rest[0]+" does not exist.", "com.atlassian.bitbucket.comment.NoSuchCommentException")
+		return
+	}
+	if r.Method == http.MethodGet {
+		writeJSON(t, w, http.StatusOK, comment)
+		return
+	}
+
+	// All changes require the current version
+	version := r.URL.Query().Get("version")
+	req := &Comment{}
+	if r.Method == http.MethodPut {
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			t.Fatal(err)
+		}
+		version = fmt.Sprint(req.Version)
+	}
+	if version != fmt.Sprint(comment.Version) {
+		writeError(t, w, http.StatusConflict, "You are attempting to modify a comment based on out-of-date information.", "com.atlassian.bitbucket.comment.CommentOutOfDateException")
+		return
+	}
+	if r.Method == http.MethodDelete {
+		s.activities = append(s.activities[:index], s.activities[index+1:]...)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	comment.Version++
+	comment.Text = req.Text
+	writeJSON(t, w, http.StatusOK, comment)
+}
+
+func (s *fakeRepoServer) handleKeys(w http.ResponseWriter, r *http.Request) {
+	t := s.t
+	repo, _, rest := s.repo(w, r.URL.Path, "/rest/keys/1.0/projects/")
+	if repo == nil {
+		return
+	}
+	switch {
+	case r.Method == http.MethodGet:
+		writePage(t, w, s.keys)
+	case r.Method == http.MethodPost:
+		req := &AccessKey{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			t.Fatal(err)
+		}
+		for _, k := range s.keys {
+			if k.Key.Text == req.Key.Text {
+				writeError(t, w, http.StatusConflict, "This SSH key is already added to this repository.", "com.atlassian.bitbucket.ssh.DuplicateAccessKeyException")
+				return
+			}
+		}
+		req.Key.ID = len(s.keys) + 1
+		s.keys = append(s.keys, req)
+		writeJSON(t, w, http.StatusCreated, req)
+	case r.Method == http.MethodDelete && len(rest) == 2:
+		for i, k := range s.keys {
+			if fmt.Sprint(k.Key.ID) == rest[1] {
+				s.keys = append(s.keys[:i], s.keys[i+1:]...)
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+		}
+		writeError(t, w, http.StatusNotFound, "Access key not found.", "")
+	}
+}
+
+func (s *fakeRepoServer) handleBranches(w http.ResponseWriter, r *http.Request) {
+	t := s.t
+	switch r.Method {
+	case http.MethodGet:
+		values := []*Branch{}
+		for name, id := range s.branches {
+			if strings.Contains(name, r.URL.Query().Get("filterText")) {
+				values = append(values, &Branch{ID: "refs/heads/" + name, DisplayID: name, LatestCommit: id})
+			}
+		}
+		writePage(t, w, values)
+	case http.MethodPost:
+		req := map[string]string{}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := s.branches[req["name"]]; ok {
+			writeError(t, w, http.StatusConflict, fmt.Sprintf("Branch '%s' already exists in this repository.", req["name"]), "com.atlassian.bitbucket.repository.DuplicateRefException")
+			return
+		}
+		// The start point can be a branch name or a commit ID
+		id, ok := s.branches[strings.TrimPrefix(req["startPoint"], "refs/heads/")]
+		for _, c := range s.commits {
+			if c.ID == req["startPoint"] {
+				id, ok = c.ID, true
+			}
+		}
+		if !ok {
+			writeError(t, w, http.StatusNotFound, "Commit '"+req["startPoint"]+"' does not exist in this repository.", "com.atlassian.bitbucket.commit.NoSuchCommitException")
+			return
+		}
+		s.branches[req["name"]] = id
+		writeJSON(t, w, http.StatusOK, Branch{ID: "refs/heads/" + req["name"], DisplayID: req["name"], LatestCommit: id})
+	}
+}
+
+func (s *fakeRepoServer) handleBranchUtils(w http.ResponseWriter, r *http.Request) {
+	t := s.t
+	if repo, _, _ := s.repo(w, r.URL.Path, "/rest/branch-utils/1.0/projects/"); repo == nil {
+		return
+	}
+	req := map[string]interface{}{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		t.Fatal(err)
+	}
+	name := strings.TrimPrefix(fmt.Sprint(req["name"]), "refs/heads/")
+	if _, ok := s.branches[name]; !ok {
+		writeError(t, w, http.StatusNotFound, "Branch '"+name+"' does not exist.", "com.atlassian.bitbucket.repository.NoSuchBranchException")
+		return
+	}
+	delete(s.branches, name)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func newFakeRepoServer(t *testing.T, repos map[string]*Repository) *fakeRepoServer {
+	return &fakeRepoServer{t: t, repos: repos, defaultBranches: map[string]string{}, branches: map[string]string{}}
+}
+
+func TestOrgRepositoriesClient(t *testing.T) {
+	mux := http.NewServeMux()
+	srv := newFakeRepoServer(t, map[string]*Repository{})
+	srv.register(mux)
+	c := newTestClient(t, mux)
+	ctx := context.Background()
+
+	ref := gitprovider.OrgRepositoryRef{
+		OrganizationRef: gitprovider.OrganizationRef{Domain: c.domain, Organization: "PRJ"},
+		RepositoryName:  "repo",
+	}
+	_, err := c.OrgRepositories().Get(ctx, ref)
+	validation.TestExpectErrors(t, "OrgRepositories().Get", err, gitprovider.ErrNotFound)
+
+	// Reconcile should create the repository
+	repo, actionTaken, err := c.OrgRepositories().Reconcile(ctx, ref, gitprovider.RepositoryInfo{
+		Description: gitprovider.StringVar("desc"),
+	}, &gitprovider.RepositoryCreateOptions{AutoInit: gitprovider.BoolVar(true)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !actionTaken {
+		t.Error("expected Reconcile to create the repository")
+	}
+	info := repo.Get()
+	if *info.Visibility != gitprovider.RepositoryVisibilityPrivate || *info.DefaultBranch != "master" || *info.Description != "desc" {
+		t.Errorf("unexpected repository info: %+v", info)
+	}
+	if len(srv.commits) != 1 || !strings.Contains(srv.commits[0].Message, "README.md=") {
+		t.Errorf("expected an initial commit with a README, got %v", srv.commits)
+	}
+	// The server makes the first pushed branch the default
+	srv.defaultBranches["PRJ/repo"] = "master"
+
+	// Creating it again is an error
+	_, err = c.OrgRepositories().Create(ctx, ref, gitprovider.RepositoryInfo{})
+	validation.TestExpectErrors(t, "OrgRepositories().Create", err, gitprovider.ErrAlreadyExists)
+
+	// Reconciling the same state again is a no-op
+	_, actionTaken, err = c.OrgRepositories().Reconcile(ctx, ref, gitprovider.RepositoryInfo{
+		Description: gitprovider.StringVar("desc"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actionTaken {
+		t.Error("expected Reconcile to be a no-op")
+	}
+
+	// Changing the visibility and default branch updates the repository
+	_, actionTaken, err = c.OrgRepositories().Reconcile(ctx, ref, gitprovider.RepositoryInfo{
+		Description:   gitprovider.StringVar("desc"),
+		DefaultBranch: gitprovider.StringVar("main"),
+		Visibility:    gitprovider.RepositoryVisibilityVar(gitprovider.RepositoryVisibilityPublic),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !actionTaken || !*srv.repos["PRJ/repo"].Public || srv.defaultBranches["PRJ/repo"] != "main" {
+		t.Error("expected Reconcile to make the repository public, with default branch main")
+	}
+
+	// Internal repositories can't be expressed
+	err = repo.Set(gitprovider.RepositoryInfo{
+		Visibility: gitprovider.RepositoryVisibilityVar(gitprovider.RepositoryVisibilityInternal),
+	})
+	validation.TestExpectErrors(t, "Repository.Set", err, gitprovider.ErrNoProviderSupport)
+
+	repos, err := c.OrgRepositories().List(ctx, ref.OrganizationRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repos) != 1 || repos[0].Repository().GetRepository() != "repo" || *repos[0].Get().DefaultBranch != "main" {
+		t.Errorf("OrgRepositories().List() = %v, want [repo]", repos)
+	}
+
+	_, err = repo.TeamAccess().List(ctx)
+	validation.TestExpectErrors(t, "TeamAccess().List", err, gitprovider.ErrNoProviderSupport)
+
+	// Deleting requires destructive actions to be enabled
+	err = repo.Delete(ctx)
+	validation.TestExpectErrors(t, "Repository.Delete", err, gitprovider.ErrDestructiveCallDisallowed)
+}
+
+func TestUserRepositoriesClient(t *testing.T) {
+	mux := http.NewServeMux()
+	srv := newFakeRepoServer(t, map[string]*Repository{})
+	srv.register(mux)
+	c := newTestClient(t, mux, WithDestructiveAPICalls(true))
+	ctx := context.Background()
+
+	ref := gitprovider.UserRepositoryRef{
+		UserRef:        gitprovider.UserRef{Domain: c.domain, UserLogin: "alice"},
+		RepositoryName: "repo",
+	}
+	repo, err := c.UserRepositories().Create(ctx, ref, gitprovider.RepositoryInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// User repositories live in the personal project of the user
+	if req := srv.repos["~alice/repo"]; req == nil || req.ScmID != "git" || *req.Public {
+		t.Errorf("unexpected create request: %+v", req)
+	}
+
+	repos, err := c.UserRepositories().List(ctx, ref.UserRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repos) != 1 {
+		t.Errorf("UserRepositories().List() = %v, want 1 repository", repos)
+	}
+
+	if err := repo.Delete(ctx); err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.UserRepositories().Get(ctx, ref)
+	validation.TestExpectErrors(t, "UserRepositories().Get", err, gitprovider.ErrNotFound)
+}
+
+func TestDeployKeyClient(t *testing.T) {
+	mux := http.NewServeMux()
+	srv := newFakeRepoServer(t, map[string]*Repository{
+		"PRJ/repo": {Slug: "repo", Public: gitprovider.BoolVar(false)},
+	})
+	srv.register(mux)
+	c := newTestClient(t, mux)
+	ctx := context.Background()
+
+	repo, err := c.OrgRepositories().Get(ctx, gitprovider.OrgRepositoryRef{
+		OrganizationRef: gitprovider.OrganizationRef{Domain: c.domain, Organization: "PRJ"},
+		RepositoryName:  "repo",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = repo.DeployKeys().Get(ctx, "flux")
+	validation.TestExpectErrors(t, "DeployKeys().Get", err, gitprovider.ErrNotFound)
+
+	req := gitprovider.DeployKeyInfo{
+		Name: "flux",
+		Key:  []byte("ssh-rsa AAAA flux@example.com"),
+	}
+	_, actionTaken, err := repo.DeployKeys().Reconcile(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !actionTaken || len(srv.keys) != 1 || srv.keys[0].Permission != accessKeyPermissionRead {
+		t.Fatalf("expected Reconcile to create a read-only key, got %v", srv.keys)
+	}
+
+	_, err = repo.DeployKeys().Create(ctx, req)
+	validation.TestExpectErrors(t, "DeployKeys().Create", err, gitprovider.ErrAlreadyExists)
+
+	// Reconciling again is a no-op
+	key, actionTaken, err := repo.DeployKeys().Reconcile(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actionTaken {
+		t.Errorf("expected Reconcile to be a no-op, got %s", key.Get().Key)
+	}
+
+	// Write access is supported, and changing it recreates the key
+	req.ReadOnly = gitprovider.BoolVar(false)
+	_, actionTaken, err = repo.DeployKeys().Reconcile(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !actionTaken || len(srv.keys) != 1 || srv.keys[0].Permission != accessKeyPermissionWrite {
+		t.Errorf("expected Reconcile to recreate the key with write access, got %v", srv.keys)
+	}
+
+	keys, err := repo.DeployKeys().List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || *keys[0].Get().ReadOnly {
+		t.Errorf("DeployKeys().List() = %v, want 1 read-write key", keys)
+	}
+	if err := keys[0].Delete(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if len(srv.keys) != 0 {
+		t.Errorf("expected the key to be deleted, got %v", srv.keys)
+	}
+}
+
+func TestCommitsBranchesPullRequests(t *testing.T) {
+	mux := http.NewServeMux()
+	srv := newFakeRepoServer(t, map[string]*Repository{
+		"PRJ/repo": {Slug: "repo", Public: gitprovider.BoolVar(false)},
+	})
+	srv.register(mux)
+	c := newTestClient(t, mux)
+	ctx := context.Background()
+
+	repo, err := c.OrgRepositories().Get(ctx, gitprovider.OrgRepositoryRef{
+		OrganizationRef: gitprovider.OrganizationRef{Domain: c.domain, Organization: "PRJ"},
+		RepositoryName:  "repo",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = repo.Commits().Create(ctx, "master", "empty", nil)
+	if err == nil {
+		t.Error("expected an error when committing no files")
+	}
+
+	// Every file is committed separately, on top of the previous one
+	commit, err := repo.Commits().Create(ctx, "master", "add files", []gitprovider.CommitFile{
+		{
+			Path:    gitprovider.StringVar("foo.txt"),
+			Content: gitprovider.StringVar("foo"),
+		},
+		{
+			Path:    gitprovider.StringVar("dir/bar.txt"),
+			Content: gitprovider.StringVar("bar"),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(srv.commits) != 2 || commit.Get().Sha != srv.commits[0].ID || srv.commits[0].Message != "add files\ndir/bar.txt=bar" {
+		t.Errorf("unexpected commit %v, server has %v", commit.Get(), srv.commits)
+	}
+
+	commits, err := repo.Commits().ListPage(ctx, "master", 2, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 2 || commits[0].Get().Sha != commit.Get().Sha {
+		t.Errorf("Commits().ListPage() = %v, want 2 commits", commits)
+	}
+	admin := &gitprovider.CommitAuthor{Name: "admin", Email: "admin@example.com", Date: time.Unix(1614834367, 0).UTC()}
+	if got := commits[0].Get(); !reflect.DeepEqual(got.Author, admin) || !reflect.DeepEqual(got.Committer, admin) || !reflect.DeepEqual(got.ParentShas, []string{commits[1].Get().Sha}) {
+		t.Errorf("Commits().ListPage()[0] = %+v, want author and committer %+v, and parent %q", got, admin, commits[1].Get().Sha)
+	}
+	_, err = repo.Commits().Create(ctx, "master", "authored", []gitprovider.CommitFile{{
+		Path:    gitprovider.StringVar("baz.txt"),
+		Content: gitprovider.StringVar("baz"),
+	}}, &gitprovider.CommitCreateOptions{Author: admin})
+	validation.TestExpectErrors(t, "Commits().Create() with an author", err, gitprovider.ErrNoProviderSupport)
+
+	// Nothing is committed if a file can't be changed
+	_, err = repo.Commits().Create(ctx, "master", "delete file", []gitprovider.CommitFile{
+		{
+			Path:    gitprovider.StringVar("baz.txt"),
+			Content: gitprovider.StringVar("baz"),
+		},
+		{
+			Path: gitprovider.StringVar("foo.txt"),
+		},
+	})
+	validation.TestExpectErrors(t, "Commits().Create", err, gitprovider.ErrNoProviderSupport)
+	if len(srv.commits) != 2 {
+		t.Errorf("unexpected commits %v", srv.commits)
+	}
+	_, err = repo.Commits().Create(ctx, "master", "stale", []gitprovider.CommitFile{{
+		Path:    gitprovider.StringVar("baz.txt"),
+		Content: gitprovider.StringVar("baz"),
+	}}, &gitprovider.CommitCreateOptions{ExpectedParentSHA: &srv.commits[1].ID})
+	validation.TestExpectErrors(t, "Commits().Create() on a moved branch", err, gitprovider.ErrConflict)
+	if len(srv.commits) != 2 {
+		t.Errorf("unexpected commits %v", srv.commits)
+	}
+
+	if err := repo.Branches().Create(ctx, "feature", commit.Get().Sha); err != nil {
+		t.Fatal(err)
+	}
+	err = repo.Branches().Create(ctx, "feature", commit.Get().Sha)
+	validation.TestExpectErrors(t, "Branches().Create", err, gitprovider.ErrAlreadyExists)
+
+	branch, err := repo.Branches().Get(ctx, "feature")
+	if err != nil || branch.Get().Sha != commit.Get().Sha {
+		t.Errorf("Branches().Get() = %v, %v", branch, err)
+	}
+	// The branch filter matches substrings, Get must only return exact matches
+	_, err = repo.Branches().Get(ctx, "feat")
+	validation.TestExpectErrors(t, "Branches().Get", err, gitprovider.ErrNotFound)
+
+	other, err := repo.Branches().CreateFrom(ctx, "other", "master")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (gitprovider.BranchInfo{Name: "other", Sha: commit.Get().Sha}); other.Get() != want {
+		t.Errorf("Branches().CreateFrom() = %+v, want %+v", other.Get(), want)
+	}
+	_, err = repo.Branches().CreateFrom(ctx, "another", "missing")
+	validation.TestExpectErrors(t, "Branches().CreateFrom", err, gitprovider.ErrNotFound)
+
+	branches, err := repo.Branches().List(ctx)
+	if err != nil || len(branches) != 3 {
+		t.Errorf("Branches().List() = %v, %v, want 3 branches", branches, err)
+	}
+	err = repo.Branches().Delete(ctx, "other")
+	validation.TestExpectErrors(t, "Branches().Delete", err, gitprovider.ErrDestructiveCallDisallowed)
+	dc := newTestClient(t, mux, WithDestructiveAPICalls(true))
+	drepo, err := dc.OrgRepositories().Get(ctx, gitprovider.OrgRepositoryRef{
+		OrganizationRef: gitprovider.OrganizationRef{Domain: dc.domain, Organization: "PRJ"},
+		RepositoryName:  "repo",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := drepo.Branches().Delete(ctx, "other"); err != nil {
+		t.Fatal(err)
+	}
+	err = drepo.Branches().Delete(ctx, "other")
+	validation.TestExpectErrors(t, "Branches().Delete", err, gitprovider.ErrNotFound)
+
+	pr, err := repo.PullRequests().Create(ctx, "title", "feature", "master", "description")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := pr.Get().WebURL; got != "https://stash.example.com/projects/PRJ/REPO/pull-requests/1" {
+		t.Errorf("PullRequest.Get().WebURL = %q", got)
+	}
+	if got := srv.prs[0]; got.FromRef.ID != "refs/heads/feature" || got.ToRef.ID != "refs/heads/master" || got.ToRef.Repository.Project.Key != "PRJ" {
+		t.Errorf("unexpected pull request: %+v", got)
+	}
+	pr, err = repo.PullRequests().Get(ctx, 1)
+	if err != nil || pr.Get().Number != 1 || pr.Get().State != gitprovider.PullRequestStateOpen || pr.Get().HeadBranch != "feature" {
+		t.Errorf("PullRequests().Get() = %+v, %v", pr, err)
+	}
+	_, err = repo.PullRequests().Get(ctx, 2)
+	validation.TestExpectErrors(t, "PullRequests().Get", err, gitprovider.ErrNotFound)
+
+	pr, err = repo.PullRequests().Update(ctx, 1, gitprovider.PullRequestUpdateOptions{Title: gitprovider.StringVar("new title")})
+	if err != nil || pr.Get().Title != "new title" || pr.Get().Description != "description" || pr.Get().BaseBranch != "master" {
+		t.Errorf("PullRequests().Update() = %+v, %v", pr, err)
+	}
+
+	req := gitprovider.PullRequestInfo{Title: "new title", Description: "description", HeadBranch: "feature", BaseBranch: "master"}
+	pr, actionTaken, err := repo.PullRequests().Reconcile(ctx, req)
+	if err != nil || actionTaken || pr.Get().Number != 1 {
+		t.Errorf("PullRequests().Reconcile() = %+v, %v, %v", pr, actionTaken, err)
+	}
+	req.Labels = []string{"bot"}
+	_, _, err = repo.PullRequests().Reconcile(ctx, req)
+	validation.TestExpectErrors(t, "PullRequests().Reconcile", err, gitprovider.ErrNoProviderSupport)
+
+	if _, err := pr.Comments().Create(ctx, "first"); err != nil {
+		t.Fatal(err)
+	}
+	comment, err := pr.Comments().Create(ctx, "plan output")
+	if err != nil || comment.Get().ID != 2 || comment.Get().Author != "me" {
+		t.Errorf("Comments().Create() = %+v, %v", comment, err)
+	}
+	srv.activities = append(srv.activities, &Activity{ID: 3, Action: activityActionCommented, CommentAction: commentActionAdded,
+		Comment: &Comment{ID: 3, Text: "on a line"}, CommentAnchor: json.RawMessage(`{"path":"foo.txt","line":1}`)})
+	// Updating twice makes sure the current version is used
+	for _, body := range []string{"new plan output", "newer plan output"} {
+		if comment, err = pr.Comments().Update(ctx, 2, body); err != nil || comment.Get().Body != body {
+			t.Errorf("Comments().Update() = %+v, %v", comment, err)
+		}
+	}
+	comments, err := pr.Comments().List(ctx)
+	if err != nil || len(comments) != 2 || comments[0].Get().Body != "first" || comments[1].Get().Body != "newer plan output" {
+		t.Errorf("Comments().List() = %v, %v", comments, err)
+	}
+	if err := pr.Comments().Delete(ctx, 2); err != nil {
+		t.Fatal(err)
+	}
+	err = pr.Comments().Delete(ctx, 2)
+	validation.TestExpectErrors(t, "Comments().Delete", err, gitprovider.ErrNotFound)
+
+	review, err := pr.Reviews().Submit(ctx, gitprovider.PullRequestReviewStateApproved, "looks good")
+	if err != nil || review.Get().State != gitprovider.PullRequestReviewStateApproved || review.Get().Body != "looks good" {
+		t.Errorf("Reviews().Submit(approved) = %+v, %v", review, err)
+	}
+	_, err = pr.Reviews().Submit(ctx, gitprovider.PullRequestReviewStateChangesRequested, "")
+	validation.TestExpectErrors(t, "Reviews().Submit", err, gitprovider.ErrNoProviderSupport)
+	reviews, err := pr.Reviews().List(ctx)
+	if err != nil || len(reviews) != 1 || reviews[0].Get().Author != "me" {
+		t.Errorf("Reviews().List() = %v, %v", reviews, err)
+	}
+
+	if err := repo.PullRequests().Merge(ctx, 1, gitprovider.MergeMethodSquash, "squashed"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repo.PullRequests().Create(ctx, "other", "other", "master", ""); err != nil {
+		t.Fatal(err)
+	}
+	pr, err = repo.PullRequests().Close(ctx, 2)
+	if err != nil || pr.Get().State != gitprovider.PullRequestStateClosed {
+		t.Errorf("PullRequests().Close() = %+v, %v", pr, err)
+	}
+
+	prs, err := repo.PullRequests().List(ctx, &gitprovider.PullRequestListOptions{State: gitprovider.PullRequestStateVar(gitprovider.PullRequestStateMerged)})
+	if err != nil || len(prs) != 1 || prs[0].Get().Number != 1 {
+		t.Errorf("PullRequests().List(merged) = %v, %v", prs, err)
+	}
+	prs, err = repo.PullRequests().List(ctx, &gitprovider.PullRequestListOptions{HeadBranch: gitprovider.StringVar("other")})
+	if err != nil || len(prs) != 1 || prs[0].Get().Number != 2 {
+		t.Errorf("PullRequests().List(head) = %v, %v", prs, err)
+	}
+
+	// New branches need a start branch, as the repository isn't empty
+	files := []gitprovider.CommitFile{{Path: gitprovider.StringVar("baz.txt"), Content: gitprovider.StringVar("baz")}}
+	_, err = repo.Commits().Create(ctx, "new", "no start", files)
+	validation.TestExpectErrors(t, "Commits().Create() on a missing branch", err, gitprovider.ErrNotFound)
+	created, err := repo.Commits().Create(ctx, "new", "start", append(files, files...), &gitprovider.CommitCreateOptions{StartBranch: gitprovider.StringVar("master")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if srv.branches["new"] != created.Get().Sha || srv.branches["master"] != commit.Get().Sha {
+		t.Errorf("unexpected branches after creating one from master: %v", srv.branches)
+	}
+}
+
+func TestClient_invalidCredentials(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/api/1.0/projects", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer wrong" {
+			t.Errorf("unexpected Authorization header %q", r.Header.Get("Authorization"))
+		}
+		writeError(t, w, http.StatusUnauthorized, "Authentication failed. Please check your credentials and try again.", "com.atlassian.bitbucket.auth.IncorrectPasswordAuthenticationException")
+	})
+	c := newTestClient(t, mux, WithAccessToken("wrong"))
+	_, err := c.Organizations().List(context.Background())
+	validation.TestExpectErrors(t, "Organizations().List", err, &gitprovider.InvalidCredentialsError{})
+	var errResp *ErrorResponse
+	if !errors.As(err, &errResp) || !strings.HasPrefix(errResp.Message(), "Authentication failed") {
+		t.Errorf("expected the error to wrap the ErrorResponse, got %v", err)
+	}
+}
+
+func TestClient_basicAuth(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rest/api/1.0/projects/PRJ", func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "user" || pass != "pass" {
+			t.Errorf("unexpected basic auth %q:%q", user, pass)
+		}
+		writeJSON(t, w, http.StatusOK, Project{Key: "PRJ"})
+	})
+	c := newTestClient(t, mux, WithBasicAuth("user", "pass"))
+	if _, err := c.Organizations().Get(context.Background(), gitprovider.OrganizationRef{Domain: c.domain, Organization: "PRJ"}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,20 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package stash implements the gitprovider interfaces for Bitbucket Server and Data Center
+// (formerly known as Stash), using the REST API 1.0. Projects are exposed as organizations,
+// and the personal project ("~username") of a user is used for user repositories.
+package stash
@@ -0,0 +1,63 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stash
+
+import (
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+func newBranch(apiObj *Branch) *branch {
+	return &branch{
+		b: *apiObj,
+	}
+}
+
+var _ gitprovider.Branch = &branch{}
+
+type branch struct {
+	b Branch
+}
+
+func (b *branch) Get() gitprovider.BranchInfo {
+	return branchFromAPI(&b.b)
+}
+
+func (b *branch) APIObject() interface{} {
+	return &b.b
+}
+
+func branchFromAPI(apiObj *Branch) gitprovider.BranchInfo {
+	// Bitbucket Server protects branches through branch permissions, which aren't part of the branch
+	return gitprovider.BranchInfo{
+		Name: apiObj.DisplayID,
+		Sha:  apiObj.LatestCommit,
+	}
+}
+
+// validateBranchAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateBranchAPI(apiObj *Branch) error {
+	return validateAPIObject("Stash.Branch", func(validator validation.Validator) {
+		if len(apiObj.DisplayID) == 0 {
+			validator.Required("DisplayID")
+		}
+		if len(apiObj.LatestCommit) == 0 {
+			validator.Required("LatestCommit")
+		}
+	})
+}
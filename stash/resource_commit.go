@@ -0,0 +1,80 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stash
+
+import (
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+func newCommit(c *CommitClient, commit *Commit) *commitType {
+	return &commitType{
+		k: *commit,
+		c: c,
+	}
+}
+
+var _ gitprovider.Commit = &commitType{}
+
+type commitType struct {
+	k Commit
+	c *CommitClient
+}
+
+func (c *commitType) Get() gitprovider.CommitInfo {
+	return commitFromAPI(&c.k)
+}
+
+func (c *commitType) APIObject() interface{} {
+	return &c.k
+}
+
+func commitFromAPI(apiObj *Commit) gitprovider.CommitInfo {
+	// The Bitbucket Server API doesn't expose the tree nor the web URL of a commit
+	info := gitprovider.CommitInfo{
+		Sha:        apiObj.ID,
+		Message:    apiObj.Message,
+		Author:     commitAuthorFromAPI(apiObj.Author, apiObj.AuthorTimestamp),
+		Committer:  commitAuthorFromAPI(apiObj.Committer, apiObj.CommitterTimestamp),
+		ParentShas: make([]string, 0, len(apiObj.Parents)),
+	}
+	for _, parent := range apiObj.Parents {
+		info.ParentShas = append(info.ParentShas, parent.ID)
+	}
+	return info
+}
+
+func commitAuthorFromAPI(apiObj *User, timestamp int64) *gitprovider.CommitAuthor {
+	if apiObj == nil {
+		return nil
+	}
+	return &gitprovider.CommitAuthor{
+		Name:  apiObj.Name,
+		Email: apiObj.EmailAddress,
+		Date:  timeFromAPI(timestamp),
+	}
+}
+
+// validateCommitAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validateCommitAPI(apiObj *Commit) error {
+	return validateAPIObject("Stash.Commit", func(validator validation.Validator) {
+		if len(apiObj.ID) == 0 {
+			validator.Required("ID")
+		}
+	})
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stash
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+func newDeployKey(c *DeployKeyClient, key *AccessKey) *deployKey {
+	return &deployKey{
+		k: *key,
+		c: c,
+	}
+}
+
+var _ gitprovider.DeployKey = &deployKey{}
+
+type deployKey struct {
+	k AccessKey
+	c *DeployKeyClient
+}
+
+func (dk *deployKey) Get() gitprovider.DeployKeyInfo {
+	return deployKeyFromAPI(&dk.k)
+}
+
+func (dk *deployKey) Set(info gitprovider.DeployKeyInfo) error {
+	if err := info.ValidateInfo(); err != nil {
+		return err
+	}
+	deployKeyInfoToAPIObj(&info, &dk.k)
+	return nil
+}
+
+func (dk *deployKey) APIObject() interface{} {
+	return &dk.k
+}
+
+func (dk *deployKey) Repository() gitprovider.RepositoryRef {
+	return dk.c.ref
+}
+
+// Update will apply the desired state in this object to the server.
+// Only set fields will be respected (i.e. PATCH behaviour).
+// In order to apply changes to this object, use the .Set({Resource}Info) error
+// function, or cast .APIObject() to a pointer to the provider-specific type
+// and set custom fields there.
+//
+// ErrNotFound is returned if the resource does not exist.
+//
+// The internal API object will be overridden with the received server data.
+func (dk *deployKey) Update(ctx context.Context) error {
+	// Delete the old key and recreate
+	if err := dk.Delete(ctx); err != nil {
+		return err
+	}
+	return dk.createIntoSelf(ctx)
+}
+
+// Delete deletes a deploy key from the repository.
+//
+// ErrNotFound is returned if the resource does not exist.
+func (dk *deployKey) Delete(ctx context.Context) error {
+	// We can use the same key ID that we got from the GET calls. Make sure it's set.
+	// This _should never_ happen, but just check for it anyways to avoid deleting the wrong thing.
+	if dk.k.Key.ID == 0 {
+		return fmt.Errorf("didn't expect ID to be unset: %w", gitprovider.ErrUnexpectedEvent)
+	}
+
+	// DELETE /rest/keys/1.0/projects/{projectKey}/repos/{repositorySlug}/ssh/{keyId}
+	return dk.c.c.DeleteKey(ctx, projectKey(dk.c.ref), dk.c.ref.GetRepository(), dk.k.Key.ID)
+}
+
+// Reconcile makes sure the desired state in this object (called "req" here) becomes
+// the actual state in the backing Git provider.
+//
+// If req doesn't exist under the hood, it is created (actionTaken == true).
+// If req doesn't equal the actual state, the resource will be updated (actionTaken == true).
+// If req is already the actual state, this is a no-op (actionTaken == false).
+//
+// The internal API object will be overridden with the received server data if actionTaken == true.
+func (dk *deployKey) Reconcile(ctx context.Context) (bool, error) {
+	actual, err := dk.c.get(ctx, dk.k.Key.Label)
+	if err != nil {
+		// Create if not found
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			return true, dk.createIntoSelf(ctx)
+		}
+
+		// Unexpected path, Get should succeed or return NotFound
+		return false, err
+	}
+
+	// Use wrappers here to extract the "spec" part of the object for comparison
+	desiredSpec := newStashKeySpec(&dk.k)
+	actualSpec := newStashKeySpec(&actual.k)
+
+	// If the desired matches the actual state, do nothing
+	if desiredSpec.Equals(actualSpec) {
+		return false, nil
+	}
+	// If desired and actual state mis-match, update
+	return true, dk.Update(ctx)
+}
+
+func (dk *deployKey) createIntoSelf(ctx context.Context) error {
+	// POST /rest/keys/1.0/projects/{projectKey}/repos/{repositorySlug}/ssh
+	apiObj, err := dk.c.c.CreateKey(ctx, projectKey(dk.c.ref), dk.c.ref.GetRepository(), newStashKeySpec(&dk.k).AccessKey)
+	if err != nil {
+		return err
+	}
+	dk.k = *apiObj
+	return nil
+}
+
+func validateAccessKeyAPI(apiObj *AccessKey) error {
+	return validateAPIObject("Stash.AccessKey", func(validator validation.Validator) {
+		// Make sure ID, label, key and permission fields are populated as per
+		// https://docs.atlassian.com/bitbucket-server/rest/latest/bitbucket-ssh-rest.html
+		if apiObj.Key.ID == 0 {
+			validator.Required("Key.ID")
+		}
+		if len(apiObj.Key.Label) == 0 {
+			validator.Required("Key.Label")
+		}
+		if len(apiObj.Key.Text) == 0 {
+			validator.Required("Key.Text")
+		}
+		if len(apiObj.Permission) == 0 {
+			validator.Required("Permission")
+		}
+	})
+}
+
+func deployKeyFromAPI(apiObj *AccessKey) gitprovider.DeployKeyInfo {
+	return gitprovider.DeployKeyInfo{
+		Name:     apiObj.Key.Label,
+		Key:      []byte(apiObj.Key.Text),
+		ReadOnly: gitprovider.BoolVar(apiObj.Permission != accessKeyPermissionWrite),
+	}
+}
+
+func deployKeyToAPI(info *gitprovider.DeployKeyInfo) *AccessKey {
+	k := &AccessKey{}
+	deployKeyInfoToAPIObj(info, k)
+	return k
+}
+
+func deployKeyInfoToAPIObj(info *gitprovider.DeployKeyInfo, apiObj *AccessKey) {
+	// Required fields, we assume info is validated, and hence these are set
+	apiObj.Key.Label = info.Name
+	apiObj.Key.Text = strings.TrimSpace(string(info.Key))
+	// optional fields
+	if info.ReadOnly != nil {
+		apiObj.Permission = accessKeyPermissionRead
+		if !*info.ReadOnly {
+			apiObj.Permission = accessKeyPermissionWrite
+		}
+	}
+}
+
+// This function copies over the fields that are part of create request of a deploy
+// i.e. the desired spec of the deploy key. This allows us to separate "spec" from "status" fields.
+func newStashKeySpec(key *AccessKey) *stashKeySpec {
+	return &stashKeySpec{
+		&AccessKey{
+			Key: SSHKey{
+				Label: key.Key.Label,
+				Text:  key.Key.Text,
+			},
+			Permission: key.Permission,
+		},
+	}
+}
+
+type stashKeySpec struct {
+	*AccessKey
+}
+
+func (s *stashKeySpec) Equals(other *stashKeySpec) bool {
+	return reflect.DeepEqual(s, other)
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stash
+
+import (
+	"strings"
+	"time"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+func newPullRequest(ctx *clientContext, apiObj *PullRequest, ref gitprovider.RepositoryRef) *pullrequest {
+	return &pullrequest{
+		clientContext: ctx,
+		pr:            *apiObj,
+		comments: &PullRequestCommentClient{
+			clientContext: ctx,
+			ref:           ref,
+			id:            apiObj.ID,
+		},
+		reviews: &PullRequestReviewClient{
+			clientContext: ctx,
+			ref:           ref,
+			id:            apiObj.ID,
+		},
+	}
+}
+
+var _ gitprovider.PullRequest = &pullrequest{}
+
+type pullrequest struct {
+	*clientContext
+
+	pr PullRequest
+
+	comments *PullRequestCommentClient
+	reviews  *PullRequestReviewClient
+}
+
+func (pr *pullrequest) Get() gitprovider.PullRequestInfo {
+	return pullrequestFromAPI(&pr.pr)
+}
+
+func (pr *pullrequest) APIObject() interface{} {
+	return &pr.pr
+}
+
+func (pr *pullrequest) Comments() gitprovider.PullRequestCommentClient {
+	return pr.comments
+}
+
+func (pr *pullrequest) Reviews() gitprovider.PullRequestReviewClient {
+	return pr.reviews
+}
+
+func pullrequestFromAPI(apiObj *PullRequest) gitprovider.PullRequestInfo {
+	info := gitprovider.PullRequestInfo{
+		Number:      apiObj.ID,
+		Title:       apiObj.Title,
+		Description: apiObj.Description,
+		HeadBranch:  strings.TrimPrefix(apiObj.FromRef.ID, "refs/heads/"),
+		BaseBranch:  strings.TrimPrefix(apiObj.ToRef.ID, "refs/heads/"),
+		State:       pullRequestStateFromAPI(apiObj.State),
+		Draft:       apiObj.Draft,
+		CreatedAt:   timeFromAPI(apiObj.CreatedDate),
+		UpdatedAt:   timeFromAPI(apiObj.UpdatedDate),
+	}
+	if apiObj.Author != nil && apiObj.Author.User != nil {
+		info.Author = apiObj.Author.User.Slug
+	}
+	if apiObj.ClosedDate != 0 {
+		closedAt := timeFromAPI(apiObj.ClosedDate)
+		info.ClosedAt = &closedAt
+		if info.State == gitprovider.PullRequestStateMerged {
+			info.MergedAt = &closedAt
+		}
+	}
+	// The self link is the web interface URL of the pull request
+	if apiObj.Links != nil && len(apiObj.Links.Self) != 0 {
+		info.WebURL = apiObj.Links.Self[0].Href
+	}
+	return info
+}
+
+// pullRequestStateFromAPI maps the Bitbucket Server states OPEN, MERGED and DECLINED to a
+// PullRequestState.
+func pullRequestStateFromAPI(state string) gitprovider.PullRequestState {
+	switch state {
+	case pullRequestStateOpen:
+		return gitprovider.PullRequestStateOpen
+	case pullRequestStateMerged:
+		return gitprovider.PullRequestStateMerged
+	default:
+		return gitprovider.PullRequestStateClosed
+	}
+}
+
+// timeFromAPI converts the milliseconds since the epoch used by Bitbucket Server to a time.Time.
+func timeFromAPI(millis int64) time.Time {
+	if millis == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, millis*int64(time.Millisecond)).UTC()
+}
+
+// validatePullRequestAPI validates the apiObj received from the server, to make sure that it is
+// valid for our use.
+func validatePullRequestAPI(apiObj *PullRequest) error {
+	return validateAPIObject("Stash.PullRequest", func(validator validation.Validator) {
+		if apiObj.ID == 0 {
+			validator.Required("ID")
+		}
+		if apiObj.State == "" {
+			validator.Required("State")
+		}
+	})
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stash
+
+import (
+	"github.com/fluxcd/go-git-providers/gitprovider"
+)
+
+const (
+	participantStatusApproved  = "APPROVED"
+	participantStatusNeedsWork = "NEEDS_WORK"
+)
+
+// newParticipantReview returns the review of the given reviewer, with the text of the comment
+// added along with it, if any.
+func newParticipantReview(participant *Participant, comment *Comment) *pullRequestReview {
+	info := gitprovider.PullRequestReviewInfo{
+		State: gitprovider.PullRequestReviewStateApproved,
+	}
+	if participant.Status == participantStatusNeedsWork {
+		info.State = gitprovider.PullRequestReviewStateChangesRequested
+	}
+	if participant.User != nil {
+		info.Author = participant.User.Slug
+	}
+	if comment != nil {
+		info.Body = comment.Text
+		submittedAt := timeFromAPI(comment.CreatedDate)
+		info.SubmittedAt = &submittedAt
+	}
+	return &pullRequestReview{info: info, apiObj: participant}
+}
+
+// newCommentReview returns a commenting review for the given comment.
+func newCommentReview(comment *Comment) *pullRequestReview {
+	submittedAt := timeFromAPI(comment.CreatedDate)
+	info := gitprovider.PullRequestReviewInfo{
+		ID:          int64(comment.ID),
+		State:       gitprovider.PullRequestReviewStateCommented,
+		Body:        comment.Text,
+		SubmittedAt: &submittedAt,
+	}
+	if comment.Author != nil {
+		info.Author = comment.Author.Slug
+	}
+	return &pullRequestReview{info: info, apiObj: comment}
+}
+
+var _ gitprovider.PullRequestReview = &pullRequestReview{}
+
+// pullRequestReview is a reviewer's status or a comment, as Bitbucket Server doesn't have reviews.
+type pullRequestReview struct {
+	info gitprovider.PullRequestReviewInfo
+	// apiObj is a *Participant or a *Comment
+	apiObj interface{}
+}
+
+func (r *pullRequestReview) Get() gitprovider.PullRequestReviewInfo {
+	return r.info
+}
+
+func (r *pullRequestReview) APIObject() interface{} {
+	return r.apiObj
+}
@@ -0,0 +1,775 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stash
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+// stashClient is a wrapper around the Bitbucket Server REST API, which implements higher-level
+// methods, operating on the structs in types.go. Pagination is implemented for all List* methods,
+// all returned objects are validated, and HTTP errors are handled/wrapped using handleHTTPError.
+// This interface is also fakeable, in order to unit-test the client.
+type stashClient interface {
+	// Client returns the underlying *http.Client
+	Client() *http.Client
+
+	// GetProject is a wrapper for "GET /rest/api/1.0/projects/{projectKey}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetProject(ctx context.Context, projectKey string) (*Project, error)
+	// ListProjects is a wrapper for "GET /rest/api/1.0/projects".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListProjects(ctx context.Context) ([]*Project, error)
+
+	// GetRepo is a wrapper for "GET /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}"
+	// and "GET /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/branches/default".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetRepo(ctx context.Context, projectKey, repoSlug string) (*Repository, error)
+	// ListRepos is a wrapper for "GET /rest/api/1.0/projects/{projectKey}/repos".
+	// The default branch of every repository is requested as in GetRepo.
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListRepos(ctx context.Context, projectKey string) ([]*Repository, error)
+	// CreateRepo is a wrapper for "POST /rest/api/1.0/projects/{projectKey}/repos".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateRepo(ctx context.Context, projectKey string, req *Repository) (*Repository, error)
+	// UpdateRepo is a wrapper for "PUT /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}",
+	// and "PUT /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/branches/default" if
+	// req.DefaultBranch is set.
+	// This function handles HTTP error wrapping, and validates the server result.
+	UpdateRepo(ctx context.Context, projectKey, repoSlug string, req *Repository) (*Repository, error)
+	// DeleteRepo is a wrapper for "DELETE /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}".
+	// This function handles HTTP error wrapping.
+	// DANGEROUS COMMAND: In order to use this, you must set destructiveActions to true.
+	DeleteRepo(ctx context.Context, projectKey, repoSlug string) error
+
+	// ListKeys is a wrapper for "GET /rest/keys/1.0/projects/{projectKey}/repos/{repositorySlug}/ssh".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListKeys(ctx context.Context, projectKey, repoSlug string) ([]*AccessKey, error)
+	// CreateKey is a wrapper for "POST /rest/keys/1.0/projects/{projectKey}/repos/{repositorySlug}/ssh".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateKey(ctx context.Context, projectKey, repoSlug string, req *AccessKey) (*AccessKey, error)
+	// DeleteKey is a wrapper for "DELETE /rest/keys/1.0/projects/{projectKey}/repos/{repositorySlug}/ssh/{keyId}".
+	// This function handles HTTP error wrapping.
+	DeleteKey(ctx context.Context, projectKey, repoSlug string, id int) error
+
+	// ListCommitsPage is a wrapper for "GET /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/commits".
+	// This function handles HTTP error wrapping, and validates the server result.
+	ListCommitsPage(ctx context.Context, projectKey, repoSlug, branch string, perPage int, page int) ([]*Commit, error)
+	// CreateCommit is a wrapper for "PUT /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/browse/{path}".
+	// As the server only supports editing one file at a time, one commit is created per file, and
+	// the last commit is returned. The first commit is based on sourceCommitID, the head of branch, or
+	// of sourceBranch to create branch from. Both are empty for the first commit of a repository.
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateCommit(ctx context.Context, projectKey, repoSlug, branch, sourceBranch, sourceCommitID, message string, files []gitprovider.CommitFile) (*Commit, error)
+
+	// GetBranch is a wrapper for "GET /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/branches",
+	// filtered to the branch with the given name, as there's no endpoint for a single branch.
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	GetBranch(ctx context.Context, projectKey, repoSlug, branch string) (*Branch, error)
+	// ListBranches is a wrapper for "GET /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/branches".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListBranches(ctx context.Context, projectKey, repoSlug string) ([]*Branch, error)
+	// CreateBranch is a wrapper for "POST /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/branches".
+	// startPoint can be a commit ID, a branch or a tag, and is resolved by the server.
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreateBranch(ctx context.Context, projectKey, repoSlug, branch, startPoint string) (*Branch, error)
+	// DeleteBranch is a wrapper for "DELETE /rest/branch-utils/1.0/projects/{projectKey}/repos/{repositorySlug}/branches".
+	// This function handles HTTP error wrapping.
+	// DANGEROUS COMMAND: In order to use this, you must set destructiveActions to true.
+	DeleteBranch(ctx context.Context, projectKey, repoSlug, branch string) error
+
+	// CreatePullRequest is a wrapper for "POST /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/pull-requests".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreatePullRequest(ctx context.Context, projectKey, repoSlug string, req *PullRequest) (*PullRequest, error)
+	// GetPullRequest is a wrapper for "GET /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/pull-requests/{pullRequestId}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetPullRequest(ctx context.Context, projectKey, repoSlug string, id int) (*PullRequest, error)
+	// ListPullRequests is a wrapper for "GET /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/pull-requests".
+	// This function handles pagination, HTTP error wrapping, and validates the server result.
+	ListPullRequests(ctx context.Context, projectKey, repoSlug string, query url.Values) ([]*PullRequest, error)
+	// UpdatePullRequest is a wrapper for "PUT /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/pull-requests/{pullRequestId}".
+	// req must contain the current version of the pull request.
+	// This function handles HTTP error wrapping, and validates the server result.
+	UpdatePullRequest(ctx context.Context, projectKey, repoSlug string, id int, req *PullRequest) (*PullRequest, error)
+	// MergePullRequest is a wrapper for "POST /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/pull-requests/{pullRequestId}/merge".
+	// This function handles HTTP error wrapping, and validates the server result.
+	MergePullRequest(ctx context.Context, projectKey, repoSlug string, id, version int, req *PullRequestMerge) (*PullRequest, error)
+	// DeclinePullRequest is a wrapper for "POST /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/pull-requests/{pullRequestId}/decline".
+	// This function handles HTTP error wrapping, and validates the server result.
+	DeclinePullRequest(ctx context.Context, projectKey, repoSlug string, id, version int) (*PullRequest, error)
+	// ListPullRequestActivities is a wrapper for "GET /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/pull-requests/{pullRequestId}/activities".
+	// This function handles pagination and HTTP error wrapping.
+	ListPullRequestActivities(ctx context.Context, projectKey, repoSlug string, id int) ([]*Activity, error)
+	// GetPullRequestComment is a wrapper for "GET /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/pull-requests/{pullRequestId}/comments/{commentId}".
+	// This function handles HTTP error wrapping, and validates the server result.
+	GetPullRequestComment(ctx context.Context, projectKey, repoSlug string, id, commentID int) (*Comment, error)
+	// CreatePullRequestComment is a wrapper for "POST /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/pull-requests/{pullRequestId}/comments".
+	// This function handles HTTP error wrapping, and validates the server result.
+	CreatePullRequestComment(ctx context.Context, projectKey, repoSlug string, id int, text string) (*Comment, error)
+	// UpdatePullRequestComment is a wrapper for "PUT /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/pull-requests/{pullRequestId}/comments/{commentId}".
+	// version must be the current version of the comment.
+	// This function handles HTTP error wrapping, and validates the server result.
+	UpdatePullRequestComment(ctx context.Context, projectKey, repoSlug string, id, commentID, version int, text string) (*Comment, error)
+	// DeletePullRequestComment is a wrapper for "DELETE /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/pull-requests/{pullRequestId}/comments/{commentId}".
+	// version must be the current version of the comment.
+	// This function handles HTTP error wrapping.
+	DeletePullRequestComment(ctx context.Context, projectKey, repoSlug string, id, commentID, version int) error
+	// ApprovePullRequest is a wrapper for "POST /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/pull-requests/{pullRequestId}/approve".
+	// This function handles HTTP error wrapping.
+	ApprovePullRequest(ctx context.Context, projectKey, repoSlug string, id int) (*Participant, error)
+}
+
+// stashClientImpl is a wrapper around the Bitbucket Server REST API, which implements higher-level
+// methods. See the stashClient interface for method documentation.
+// Pagination is implemented for all List* methods, all returned
+// objects are validated, and HTTP errors are handled/wrapped using handleHTTPError.
+type stashClientImpl struct {
+	c *http.Client
+	// domainURL is the URL of the server, without a trailing slash
+	domainURL          string
+	destructiveActions bool
+}
+
+// stashClientImpl implements stashClient.
+var _ stashClient = &stashClientImpl{}
+
+const (
+	// coreAPIPath is the path of the core REST API, relative to the domain URL.
+	coreAPIPath = "rest/api/1.0"
+	// keysAPIPath is the path of the SSH access keys REST API, relative to the domain URL.
+	keysAPIPath = "rest/keys/1.0"
+	// branchUtilsAPIPath is the path of the branch utils REST API, relative to the domain URL.
+	branchUtilsAPIPath = "rest/branch-utils/1.0"
+)
+
+func (c *stashClientImpl) Client() *http.Client {
+	return c.c
+}
+
+func (c *stashClientImpl) GetProject(ctx context.Context, projectKey string) (*Project, error) {
+	apiObj := &Project{}
+	// GET /rest/api/1.0/projects/{projectKey}
+	if err := c.do(ctx, http.MethodGet, c.url(coreAPIPath, nil, "projects", projectKey), nil, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// Validate the API object
+	if err := validateProjectAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *stashClientImpl) ListProjects(ctx context.Context) ([]*Project, error) {
+	apiObjs := []*Project{}
+	// GET /rest/api/1.0/projects
+	err := c.allPages(ctx, c.url(coreAPIPath, nil, "projects"), func(values json.RawMessage) error {
+		var pageObjs []*Project
+		err := json.Unmarshal(values, &pageObjs)
+		apiObjs = append(apiObjs, pageObjs...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate the API objects
+	for _, apiObj := range apiObjs {
+		if err := validateProjectAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *stashClientImpl) GetRepo(ctx context.Context, projectKey, repoSlug string) (*Repository, error) {
+	apiObj := &Repository{}
+	// GET /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}
+	if err := c.do(ctx, http.MethodGet, c.url(coreAPIPath, nil, "projects", projectKey, "repos", repoSlug), nil, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := c.getDefaultBranch(ctx, projectKey, apiObj); err != nil {
+		return nil, err
+	}
+	// Make sure apiObj is valid
+	if err := validateRepositoryAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+// getDefaultBranch populates apiObj.DefaultBranch from the server.
+func (c *stashClientImpl) getDefaultBranch(ctx context.Context, projectKey string, apiObj *Repository) error {
+	branch := &Branch{}
+	// GET /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/branches/default
+	err := c.do(ctx, http.MethodGet, c.url(coreAPIPath, nil, "projects", projectKey, "repos", apiObj.Slug, "branches", "default"), nil, branch)
+	if err != nil {
+		err = handleHTTPError(err)
+		// Empty repositories don't have a default branch yet, leave it unset in that case
+		if errors.Is(err, gitprovider.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	if len(branch.DisplayID) != 0 {
+		apiObj.DefaultBranch = gitprovider.StringVar(branch.DisplayID)
+	}
+	return nil
+}
+
+func (c *stashClientImpl) ListRepos(ctx context.Context, projectKey string) ([]*Repository, error) {
+	apiObjs := []*Repository{}
+	// GET /rest/api/1.0/projects/{projectKey}/repos
+	err := c.allPages(ctx, c.url(coreAPIPath, nil, "projects", projectKey, "repos"), func(values json.RawMessage) error {
+		var pageObjs []*Repository
+		err := json.Unmarshal(values, &pageObjs)
+		apiObjs = append(apiObjs, pageObjs...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, apiObj := range apiObjs {
+		if err := c.getDefaultBranch(ctx, projectKey, apiObj); err != nil {
+			return nil, err
+		}
+		// Make sure apiObj is valid
+		if err := validateRepositoryAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *stashClientImpl) CreateRepo(ctx context.Context, projectKey string, req *Repository) (*Repository, error) {
+	apiObj := &Repository{}
+	// POST /rest/api/1.0/projects/{projectKey}/repos
+	if err := c.do(ctx, http.MethodPost, c.url(coreAPIPath, nil, "projects", projectKey, "repos"), req, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	// The repository is empty, the default branch given at creation time is what will be used
+	apiObj.DefaultBranch = req.DefaultBranch
+	// Make sure apiObj is valid
+	if err := validateRepositoryAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *stashClientImpl) UpdateRepo(ctx context.Context, projectKey, repoSlug string, req *Repository) (*Repository, error) {
+	apiObj := &Repository{}
+	// PUT /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}
+	if err := c.do(ctx, http.MethodPut, c.url(coreAPIPath, nil, "projects", projectKey, "repos", repoSlug), req, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if req.DefaultBranch != nil {
+		body := &Branch{ID: branchRef(*req.DefaultBranch)}
+		// PUT /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/branches/default
+		if err := c.do(ctx, http.MethodPut, c.url(coreAPIPath, nil, "projects", projectKey, "repos", apiObj.Slug, "branches", "default"), body, nil); err != nil {
+			return nil, handleHTTPError(err)
+		}
+		apiObj.DefaultBranch = req.DefaultBranch
+	} else if err := c.getDefaultBranch(ctx, projectKey, apiObj); err != nil {
+		return nil, err
+	}
+	// Make sure apiObj is valid
+	if err := validateRepositoryAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *stashClientImpl) DeleteRepo(ctx context.Context, projectKey, repoSlug string) error {
+	// Don't allow deleting repositories if the user didn't explicitly allow dangerous API calls.
+	if !c.destructiveActions {
+		return fmt.Errorf("cannot delete repository: %w", gitprovider.ErrDestructiveCallDisallowed)
+	}
+	// DELETE /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}
+	err := c.do(ctx, http.MethodDelete, c.url(coreAPIPath, nil, "projects", projectKey, "repos", repoSlug), nil, nil)
+	return handleHTTPError(err)
+}
+
+func (c *stashClientImpl) ListKeys(ctx context.Context, projectKey, repoSlug string) ([]*AccessKey, error) {
+	apiObjs := []*AccessKey{}
+	// GET /rest/keys/1.0/projects/{projectKey}/repos/{repositorySlug}/ssh
+	err := c.allPages(ctx, c.url(keysAPIPath, nil, "projects", projectKey, "repos", repoSlug, "ssh"), func(values json.RawMessage) error {
+		var pageObjs []*AccessKey
+		err := json.Unmarshal(values, &pageObjs)
+		apiObjs = append(apiObjs, pageObjs...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, apiObj := range apiObjs {
+		if err := validateAccessKeyAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *stashClientImpl) CreateKey(ctx context.Context, projectKey, repoSlug string, req *AccessKey) (*AccessKey, error) {
+	apiObj := &AccessKey{}
+	// POST /rest/keys/1.0/projects/{projectKey}/repos/{repositorySlug}/ssh
+	if err := c.do(ctx, http.MethodPost, c.url(keysAPIPath, nil, "projects", projectKey, "repos", repoSlug, "ssh"), req, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validateAccessKeyAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *stashClientImpl) DeleteKey(ctx context.Context, projectKey, repoSlug string, id int) error {
+	// DELETE /rest/keys/1.0/projects/{projectKey}/repos/{repositorySlug}/ssh/{keyId}
+	err := c.do(ctx, http.MethodDelete, c.url(keysAPIPath, nil, "projects", projectKey, "repos", repoSlug, "ssh", strconv.Itoa(id)), nil, nil)
+	return handleHTTPError(err)
+}
+
+func (c *stashClientImpl) ListCommitsPage(ctx context.Context, projectKey, repoSlug, branch string, perPage int, page int) ([]*Commit, error) {
+	query := url.Values{}
+	query.Set("until", branchRef(branch))
+	if perPage > 0 {
+		query.Set("limit", strconv.Itoa(perPage))
+		// Pages are 1-indexed, treat 0 as "the first page" like the other providers do
+		if page > 1 {
+			query.Set("start", strconv.Itoa((page-1)*perPage))
+		}
+	}
+
+	resp := &struct {
+		Values []*Commit `json:"values"`
+	}{}
+	// GET /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/commits
+	if err := c.do(ctx, http.MethodGet, c.url(coreAPIPath, query, "projects", projectKey, "repos", repoSlug, "commits"), nil, resp); err != nil {
+		return nil, handleHTTPError(err)
+	}
+
+	for _, apiObj := range resp.Values {
+		if err := validateCommitAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return resp.Values, nil
+}
+
+func (c *stashClientImpl) CreateCommit(ctx context.Context, projectKey, repoSlug, branch, sourceBranch, sourceCommitID, message string, files []gitprovider.CommitFile) (*Commit, error) {
+	// Only writing files is supported by the browse endpoint
+	for _, file := range files {
+		switch {
+		case file.GetAction() == gitprovider.CommitFileActionDelete || file.GetAction() == gitprovider.CommitFileActionMove:
+			return nil, fmt.Errorf("cannot %s file %q: %w", file.GetAction(), *file.Path, gitprovider.ErrNoProviderSupport)
+		case file.IsExecutable():
+			return nil, fmt.Errorf("cannot commit executable file %q: %w", *file.Path, gitprovider.ErrNoProviderSupport)
+		}
+	}
+
+	// Every edit is based on the previous commit. This is both required for editing existing
+	// files, and protects against concurrent changes.
+	var apiObj *Commit
+	for _, file := range files {
+		var err error
+		apiObj, err = c.editFile(ctx, projectKey, repoSlug, branch, sourceBranch, message, sourceCommitID, file)
+		if err != nil {
+			return nil, err
+		}
+		// Build the next commit on top of this one, which is on branch now
+		sourceBranch = ""
+		sourceCommitID = apiObj.ID
+	}
+	return apiObj, nil
+}
+
+func (c *stashClientImpl) editFile(ctx context.Context, projectKey, repoSlug, branch, sourceBranch, message, sourceCommitID string, file gitprovider.CommitFile) (*Commit, error) {
+	content, err := file.GetContent()
+	if err != nil {
+		return nil, err
+	}
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	fields := [][2]string{{"branch", branch}, {"message", message}, {"content", string(content)}}
+	if len(sourceCommitID) != 0 {
+		fields = append(fields, [2]string{"sourceCommitId", sourceCommitID})
+	}
+	if len(sourceBranch) != 0 {
+		fields = append(fields, [2]string{"sourceBranch", sourceBranch})
+	}
+	for _, field := range fields {
+		if err := w.WriteField(field[0], field[1]); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	// The file path is part of the URL, keep its slashes
+	segments := append([]string{"projects", projectKey, "repos", repoSlug, "browse"}, strings.Split(*file.Path, "/")...)
+	// PUT /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/browse/{path}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.url(coreAPIPath, nil, segments...), body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	apiObj := &Commit{}
+	if _, err := c.roundTrip(req, apiObj); err != nil {
+		// The server replies with "409 Conflict" if the file changed after sourceCommitID
+		stashErrorResponse := &ErrorResponse{}
+		if errors.As(err, &stashErrorResponse) && stashErrorResponse.Response.StatusCode == http.StatusConflict {
+			return nil, validation.NewMultiError(err, gitprovider.ErrConflict)
+		}
+		return nil, handleHTTPError(err)
+	}
+	if err := validateCommitAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *stashClientImpl) GetBranch(ctx context.Context, projectKey, repoSlug, branch string) (*Branch, error) {
+	query := url.Values{}
+	query.Set("filterText", branch)
+	var apiObj *Branch
+	// GET /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/branches
+	err := c.allPages(ctx, c.url(coreAPIPath, query, "projects", projectKey, "repos", repoSlug, "branches"), func(values json.RawMessage) error {
+		var pageObjs []*Branch
+		if err := json.Unmarshal(values, &pageObjs); err != nil {
+			return err
+		}
+		// The filter matches substrings, look for the exact name
+		for _, pageObj := range pageObjs {
+			if pageObj.DisplayID == branch {
+				apiObj = pageObj
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if apiObj == nil {
+		return nil, fmt.Errorf("branch %q: %w", branch, gitprovider.ErrNotFound)
+	}
+	if err := validateBranchAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *stashClientImpl) ListBranches(ctx context.Context, projectKey, repoSlug string) ([]*Branch, error) {
+	apiObjs := []*Branch{}
+	// GET /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/branches
+	err := c.allPages(ctx, c.url(coreAPIPath, nil, "projects", projectKey, "repos", repoSlug, "branches"), func(values json.RawMessage) error {
+		var pageObjs []*Branch
+		err := json.Unmarshal(values, &pageObjs)
+		apiObjs = append(apiObjs, pageObjs...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, apiObj := range apiObjs {
+		if err := validateBranchAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *stashClientImpl) CreateBranch(ctx context.Context, projectKey, repoSlug, branch, startPoint string) (*Branch, error) {
+	req := &struct {
+		Name       string `json:"name"`
+		StartPoint string `json:"startPoint"`
+	}{
+		Name:       branch,
+		StartPoint: startPoint,
+	}
+	apiObj := &Branch{}
+	// POST /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/branches
+	if err := c.do(ctx, http.MethodPost, c.url(coreAPIPath, nil, "projects", projectKey, "repos", repoSlug, "branches"), req, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validateBranchAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *stashClientImpl) DeleteBranch(ctx context.Context, projectKey, repoSlug, branch string) error {
+	// Don't allow deleting branches if the user didn't explicitly allow dangerous API calls.
+	if !c.destructiveActions {
+		return fmt.Errorf("cannot delete branch: %w", gitprovider.ErrDestructiveCallDisallowed)
+	}
+	req := &struct {
+		Name string `json:"name"`
+	}{
+		Name: branchRef(branch),
+	}
+	// DELETE /rest/branch-utils/1.0/projects/{projectKey}/repos/{repositorySlug}/branches
+	err := c.do(ctx, http.MethodDelete, c.url(branchUtilsAPIPath, nil, "projects", projectKey, "repos", repoSlug, "branches"), req, nil)
+	return handleHTTPError(err)
+}
+
+func (c *stashClientImpl) CreatePullRequest(ctx context.Context, projectKey, repoSlug string, req *PullRequest) (*PullRequest, error) {
+	apiObj := &PullRequest{}
+	// POST /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/pull-requests
+	if err := c.do(ctx, http.MethodPost, c.url(coreAPIPath, nil, "projects", projectKey, "repos", repoSlug, "pull-requests"), req, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validatePullRequestAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *stashClientImpl) GetPullRequest(ctx context.Context, projectKey, repoSlug string, id int) (*PullRequest, error) {
+	// GET /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/pull-requests/{pullRequestId}
+	return c.doPullRequest(ctx, http.MethodGet, c.url(coreAPIPath, nil, "projects", projectKey, "repos", repoSlug, "pull-requests", strconv.Itoa(id)), nil)
+}
+
+func (c *stashClientImpl) ListPullRequests(ctx context.Context, projectKey, repoSlug string, query url.Values) ([]*PullRequest, error) {
+	apiObjs := []*PullRequest{}
+	// GET /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/pull-requests
+	err := c.allPages(ctx, c.url(coreAPIPath, query, "projects", projectKey, "repos", repoSlug, "pull-requests"), func(values json.RawMessage) error {
+		var pageObjs []*PullRequest
+		err := json.Unmarshal(values, &pageObjs)
+		apiObjs = append(apiObjs, pageObjs...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, apiObj := range apiObjs {
+		if err := validatePullRequestAPI(apiObj); err != nil {
+			return nil, err
+		}
+	}
+	return apiObjs, nil
+}
+
+func (c *stashClientImpl) UpdatePullRequest(ctx context.Context, projectKey, repoSlug string, id int, req *PullRequest) (*PullRequest, error) {
+	// PUT /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/pull-requests/{pullRequestId}
+	return c.doPullRequest(ctx, http.MethodPut, c.url(coreAPIPath, nil, "projects", projectKey, "repos", repoSlug, "pull-requests", strconv.Itoa(id)), req)
+}
+
+func (c *stashClientImpl) MergePullRequest(ctx context.Context, projectKey, repoSlug string, id, version int, req *PullRequestMerge) (*PullRequest, error) {
+	query := url.Values{"version": []string{strconv.Itoa(version)}}
+	// POST /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/pull-requests/{pullRequestId}/merge
+	return c.doPullRequest(ctx, http.MethodPost, c.url(coreAPIPath, query, "projects", projectKey, "repos", repoSlug, "pull-requests", strconv.Itoa(id), "merge"), req)
+}
+
+func (c *stashClientImpl) DeclinePullRequest(ctx context.Context, projectKey, repoSlug string, id, version int) (*PullRequest, error) {
+	query := url.Values{"version": []string{strconv.Itoa(version)}}
+	// POST /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/pull-requests/{pullRequestId}/decline
+	return c.doPullRequest(ctx, http.MethodPost, c.url(coreAPIPath, query, "projects", projectKey, "repos", repoSlug, "pull-requests", strconv.Itoa(id), "decline"), struct{}{})
+}
+
+// doPullRequest sends a request returning a pull request, and validates the result.
+func (c *stashClientImpl) doPullRequest(ctx context.Context, method, urlStr string, body interface{}) (*PullRequest, error) {
+	apiObj := &PullRequest{}
+	if err := c.do(ctx, method, urlStr, body, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validatePullRequestAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+func (c *stashClientImpl) ListPullRequestActivities(ctx context.Context, projectKey, repoSlug string, id int) ([]*Activity, error) {
+	apiObjs := []*Activity{}
+	// GET /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/pull-requests/{pullRequestId}/activities
+	err := c.allPages(ctx, c.url(coreAPIPath, nil, "projects", projectKey, "repos", repoSlug, "pull-requests", strconv.Itoa(id), "activities"), func(values json.RawMessage) error {
+		var pageObjs []*Activity
+		err := json.Unmarshal(values, &pageObjs)
+		apiObjs = append(apiObjs, pageObjs...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return apiObjs, nil
+}
+
+func (c *stashClientImpl) GetPullRequestComment(ctx context.Context, projectKey, repoSlug string, id, commentID int) (*Comment, error) {
+	// GET /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/pull-requests/{pullRequestId}/comments/{commentId}
+	return c.doComment(ctx, http.MethodGet, c.url(coreAPIPath, nil, "projects", projectKey, "repos", repoSlug, "pull-requests", strconv.Itoa(id), "comments", strconv.Itoa(commentID)), nil)
+}
+
+func (c *stashClientImpl) CreatePullRequestComment(ctx context.Context, projectKey, repoSlug string, id int, text string) (*Comment, error) {
+	// POST /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/pull-requests/{pullRequestId}/comments
+	return c.doComment(ctx, http.MethodPost, c.url(coreAPIPath, nil, "projects", projectKey, "repos", repoSlug, "pull-requests", strconv.Itoa(id), "comments"), &Comment{Text: text})
+}
+
+func (c *stashClientImpl) UpdatePullRequestComment(ctx context.Context, projectKey, repoSlug string, id, commentID, version int, text string) (*Comment, error) {
+	// PUT /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/pull-requests/{pullRequestId}/comments/{commentId}
+	return c.doComment(ctx, http.MethodPut, c.url(coreAPIPath, nil, "projects", projectKey, "repos", repoSlug, "pull-requests", strconv.Itoa(id), "comments", strconv.Itoa(commentID)), &Comment{Text: text, Version: version})
+}
+
+func (c *stashClientImpl) DeletePullRequestComment(ctx context.Context, projectKey, repoSlug string, id, commentID, version int) error {
+	query := url.Values{"version": []string{strconv.Itoa(version)}}
+	// DELETE /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/pull-requests/{pullRequestId}/comments/{commentId}
+	err := c.do(ctx, http.MethodDelete, c.url(coreAPIPath, query, "projects", projectKey, "repos", repoSlug, "pull-requests", strconv.Itoa(id), "comments", strconv.Itoa(commentID)), nil, nil)
+	return handleHTTPError(err)
+}
+
+func (c *stashClientImpl) ApprovePullRequest(ctx context.Context, projectKey, repoSlug string, id int) (*Participant, error) {
+	apiObj := &Participant{}
+	// POST /rest/api/1.0/projects/{projectKey}/repos/{repositorySlug}/pull-requests/{pullRequestId}/approve
+	if err := c.do(ctx, http.MethodPost, c.url(coreAPIPath, nil, "projects", projectKey, "repos", repoSlug, "pull-requests", strconv.Itoa(id), "approve"), struct{}{}, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	return apiObj, nil
+}
+
+// doComment sends a request returning a comment, and validates the result.
+func (c *stashClientImpl) doComment(ctx context.Context, method, urlStr string, body interface{}) (*Comment, error) {
+	apiObj := &Comment{}
+	if err := c.do(ctx, method, urlStr, body, apiObj); err != nil {
+		return nil, handleHTTPError(err)
+	}
+	if err := validateCommentAPI(apiObj); err != nil {
+		return nil, err
+	}
+	return apiObj, nil
+}
+
+// branchRef returns the fully-qualified ref of the given branch name.
+func branchRef(branch string) string {
+	if strings.HasPrefix(branch, "refs/") {
+		return branch
+	}
+	return "refs/heads/" + branch
+}
+
+// url returns the absolute URL for the path built from the API path and the escaped
+// path segments, with the optional query appended.
+func (c *stashClientImpl) url(apiPath string, query url.Values, segments ...string) string {
+	escaped := make([]string, 0, len(segments)+1)
+	escaped = append(escaped, apiPath)
+	for _, s := range segments {
+		escaped = append(escaped, url.PathEscape(s))
+	}
+	u := c.domainURL + "/" + strings.Join(escaped, "/")
+	if len(query) != 0 {
+		u = u + "?" + query.Encode()
+	}
+	return u
+}
+
+// do sends a request with the given JSON-encoded body (if non-nil) and decodes the
+// response into out (if non-nil). Non-2xx responses are returned as *ErrorResponse.
+func (c *stashClientImpl) do(ctx context.Context, method, urlStr string, body, out interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, urlStr, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	_, err = c.roundTrip(req, out)
+	return err
+}
+
+// roundTrip executes req, and decodes a successful response into out, if out is non-nil.
+func (c *stashClientImpl) roundTrip(req *http.Request, out interface{}) (*http.Response, error) {
+	// The server rejects multipart uploads without this header, as a protection against XSRF
+	req.Header.Set("X-Atlassian-Token", "no-check")
+
+	resp, err := c.c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		errResp := &ErrorResponse{Response: resp}
+		// The error body is best-effort, the status code is what matters
+		if data, readErr := ioutil.ReadAll(resp.Body); readErr == nil && len(data) != 0 {
+			_ = json.Unmarshal(data, errResp)
+		}
+		return resp, errResp
+	}
+
+	if out != nil && resp.StatusCode != http.StatusNoContent {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil && err != io.EOF {
+			return resp, err
+		}
+	}
+	return resp, nil
+}
+
+// allPages requests urlStr, and keeps requesting the next page until the last page has been
+// fetched. fn is called with the raw "values" list of every page.
+// There is no need to wrap the resulting error in handleHTTPError(err), as that's already done.
+func (c *stashClientImpl) allPages(ctx context.Context, urlStr string, fn func(values json.RawMessage) error) error {
+	u, err := url.Parse(urlStr)
+	if err != nil {
+		return err
+	}
+	query := u.Query()
+	start := -1
+	for {
+		u.RawQuery = query.Encode()
+		page := &struct {
+			pagedResponse
+			Values json.RawMessage `json:"values"`
+		}{}
+		if err := c.do(ctx, http.MethodGet, u.String(), nil, page); err != nil {
+			return handleHTTPError(err)
+		}
+		if len(page.Values) != 0 {
+			if err := fn(page.Values); err != nil {
+				return err
+			}
+		}
+		// Stop at the last page, and don't loop forever if the server doesn't advance
+		if page.IsLastPage || page.NextPageStart <= start {
+			return nil
+		}
+		start = page.NextPageStart
+		query.Set("start", strconv.Itoa(start))
+	}
+}
@@ -0,0 +1,225 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stash
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// The structs in this file are the Bitbucket Server REST API 1.0 objects returned from the server,
+// and what .APIObject() returns for the resources of this package. Only fields that are used
+// by this library, or are likely to be useful for consumers, are modelled.
+// See: https://docs.atlassian.com/bitbucket-server/rest/latest/bitbucket-rest.html
+
+// Link is a hypermedia link to a resource.
+type Link struct {
+	Href string `json:"href"`
+	Name string `json:"name,omitempty"`
+}
+
+// Links contains the links that Bitbucket Server returns for most objects.
+type Links struct {
+	Self  []Link `json:"self,omitempty"`
+	Clone []Link `json:"clone,omitempty"`
+}
+
+// Project represents a Bitbucket Server project, the equivalent of an organization.
+// Personal projects have a key of the form "~username", and type "PERSONAL".
+type Project struct {
+	ID          int    `json:"id,omitempty"`
+	Key         string `json:"key"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	Public      bool   `json:"public,omitempty"`
+	Type        string `json:"type,omitempty"`
+	Links       *Links `json:"links,omitempty"`
+}
+
+// Repository represents a Bitbucket Server repository.
+type Repository struct {
+	ID          int      `json:"id,omitempty"`
+	Slug        string   `json:"slug,omitempty"`
+	Name        string   `json:"name,omitempty"`
+	Description *string  `json:"description,omitempty"`
+	ScmID       string   `json:"scmId,omitempty"`
+	State       string   `json:"state,omitempty"`
+	Forkable    *bool    `json:"forkable,omitempty"`
+	Public      *bool    `json:"public,omitempty"`
+	Project     *Project `json:"project,omitempty"`
+	Links       *Links   `json:"links,omitempty"`
+
+	// DefaultBranch is the name of the default branch of the repository. It is not part of the
+	// repository object returned by the server, but requested separately, and filled in by the client.
+	DefaultBranch *string `json:"defaultBranch,omitempty"`
+}
+
+// SSHKey is a public SSH key.
+type SSHKey struct {
+	ID    int    `json:"id,omitempty"`
+	Text  string `json:"text"`
+	Label string `json:"label,omitempty"`
+}
+
+const (
+	// accessKeyPermissionRead is the permission of a read-only access key.
+	accessKeyPermissionRead = "REPO_READ"
+	// accessKeyPermissionWrite is the permission of an access key which can push to the repository.
+	accessKeyPermissionWrite = "REPO_WRITE"
+)
+
+// AccessKey represents a Bitbucket Server repository access key, i.e. a deploy key.
+type AccessKey struct {
+	Key        SSHKey      `json:"key"`
+	Permission string      `json:"permission"`
+	Repository *Repository `json:"repository,omitempty"`
+}
+
+// User is a Bitbucket Server user.
+type User struct {
+	ID           int    `json:"id,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Slug         string `json:"slug,omitempty"`
+	DisplayName  string `json:"displayName,omitempty"`
+	EmailAddress string `json:"emailAddress,omitempty"`
+}
+
+// Commit represents a Bitbucket Server commit.
+type Commit struct {
+	ID                 string   `json:"id"`
+	DisplayID          string   `json:"displayId,omitempty"`
+	Message            string   `json:"message,omitempty"`
+	Author             *User    `json:"author,omitempty"`
+	AuthorTimestamp    int64    `json:"authorTimestamp,omitempty"`
+	Committer          *User    `json:"committer,omitempty"`
+	CommitterTimestamp int64    `json:"committerTimestamp,omitempty"`
+	Parents            []Commit `json:"parents,omitempty"`
+}
+
+// Branch represents a Bitbucket Server branch.
+type Branch struct {
+	ID           string `json:"id,omitempty"`
+	DisplayID    string `json:"displayId,omitempty"`
+	LatestCommit string `json:"latestCommit,omitempty"`
+	IsDefault    bool   `json:"isDefault,omitempty"`
+}
+
+// PullRequestRef is the source or target ref of a pull request.
+type PullRequestRef struct {
+	ID           string      `json:"id"`
+	DisplayID    string      `json:"displayId,omitempty"`
+	LatestCommit string      `json:"latestCommit,omitempty"`
+	Repository   *Repository `json:"repository,omitempty"`
+}
+
+// PullRequest represents a Bitbucket Server pull request.
+type PullRequest struct {
+	ID          int            `json:"id,omitempty"`
+	Version     int            `json:"version,omitempty"`
+	Title       string         `json:"title"`
+	Description string         `json:"description,omitempty"`
+	State       string         `json:"state,omitempty"`
+	Open        bool           `json:"open,omitempty"`
+	Closed      bool           `json:"closed,omitempty"`
+	Draft       bool           `json:"draft,omitempty"`
+	FromRef     PullRequestRef `json:"fromRef"`
+	ToRef       PullRequestRef `json:"toRef"`
+	Author      *Participant   `json:"author,omitempty"`
+	CreatedDate int64          `json:"createdDate,omitempty"`
+	UpdatedDate int64          `json:"updatedDate,omitempty"`
+	ClosedDate  int64          `json:"closedDate,omitempty"`
+	Reviewers   []*Participant `json:"reviewers,omitempty"`
+	Links       *Links         `json:"links,omitempty"`
+}
+
+// Participant is a user taking part in a pull request, e.g. its author or a reviewer.
+type Participant struct {
+	User     *User  `json:"user,omitempty"`
+	Role     string `json:"role,omitempty"`
+	Approved bool   `json:"approved,omitempty"`
+	// Status is one of APPROVED, NEEDS_WORK or UNAPPROVED
+	Status string `json:"status,omitempty"`
+}
+
+// Comment represents a Bitbucket Server pull request comment.
+type Comment struct {
+	ID          int    `json:"id,omitempty"`
+	Version     int    `json:"version,omitempty"`
+	Text        string `json:"text"`
+	Author      *User  `json:"author,omitempty"`
+	CreatedDate int64  `json:"createdDate,omitempty"`
+	UpdatedDate int64  `json:"updatedDate,omitempty"`
+}
+
+// Activity is an entry of the activity stream of a pull request, e.g. an added comment.
+type Activity struct {
+	ID            int      `json:"id"`
+	Action        string   `json:"action"`
+	CommentAction string   `json:"commentAction,omitempty"`
+	Comment       *Comment `json:"comment,omitempty"`
+	// CommentAnchor is set for comments on a line or file of the diff
+	CommentAnchor json.RawMessage `json:"commentAnchor,omitempty"`
+}
+
+// PullRequestMerge is the request body for merging a pull request.
+type PullRequestMerge struct {
+	Message    string `json:"message,omitempty"`
+	StrategyID string `json:"strategyId,omitempty"`
+}
+
+// pagedResponse is the envelope used by Bitbucket Server for all list responses. Values is decoded
+// separately by the caller, as its type depends on the endpoint.
+type pagedResponse struct {
+	IsLastPage    bool `json:"isLastPage"`
+	NextPageStart int  `json:"nextPageStart"`
+}
+
+// ErrorResponse is the error returned from the server when a request fails.
+// See: https://docs.atlassian.com/bitbucket-server/rest/latest/bitbucket-rest.html#errors-and-validation
+type ErrorResponse struct {
+	// Response is the HTTP response that caused this error.
+	Response *http.Response `json:"-"`
+	// Errors contains the individual errors returned by the server.
+	Errors []ErrorDetail `json:"errors"`
+}
+
+// ErrorDetail describes what went wrong in a failed request.
+type ErrorDetail struct {
+	Context       string `json:"context,omitempty"`
+	Message       string `json:"message"`
+	ExceptionName string `json:"exceptionName,omitempty"`
+}
+
+// Message returns the messages of all errors, joined by "; ".
+func (e *ErrorResponse) Message() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, detail := range e.Errors {
+		msgs = append(msgs, detail.Message)
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Error implements the error interface.
+func (e *ErrorResponse) Error() string {
+	msg := fmt.Sprintf("%s %s: %d", e.Response.Request.Method, e.Response.Request.URL, e.Response.StatusCode)
+	if m := e.Message(); len(m) != 0 {
+		msg = fmt.Sprintf("%s %s", msg, m)
+	}
+	return msg
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stash
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+const (
+	alreadyExistsMagicString = "already exists"
+	alreadyTakenMagicString  = "already taken"
+	duplicateExceptionPrefix = "Duplicate"
+	apiDocURL                = "https://docs.atlassian.com/bitbucket-server/rest/latest/bitbucket-rest.html"
+	personalProjectKeyPrefix = "~"
+)
+
+// validateUserRepositoryRef makes sure the UserRepositoryRef is valid for Bitbucket Server's usage.
+func validateUserRepositoryRef(ref gitprovider.UserRepositoryRef, expectedDomain string) error {
+	// Make sure the RepositoryRef fields are valid
+	if err := validation.ValidateTargets("UserRepositoryRef", ref); err != nil {
+		return err
+	}
+	// Make sure the type is valid, and domain is expected
+	return validateIdentityFields(ref, expectedDomain)
+}
+
+// validateOrgRepositoryRef makes sure the OrgRepositoryRef is valid for Bitbucket Server's usage.
+func validateOrgRepositoryRef(ref gitprovider.OrgRepositoryRef, expectedDomain string) error {
+	// Make sure the RepositoryRef fields are valid
+	if err := validation.ValidateTargets("OrgRepositoryRef", ref); err != nil {
+		return err
+	}
+	// Make sure the type is valid, and domain is expected
+	return validateIdentityFields(ref, expectedDomain)
+}
+
+// validateOrganizationRef makes sure the OrganizationRef is valid for Bitbucket Server's usage.
+func validateOrganizationRef(ref gitprovider.OrganizationRef, expectedDomain string) error {
+	// Make sure the OrganizationRef fields are valid
+	if err := validation.ValidateTargets("OrganizationRef", ref); err != nil {
+		return err
+	}
+	// Make sure the type is valid, and domain is expected
+	return validateIdentityFields(ref, expectedDomain)
+}
+
+// validateUserRef makes sure the UserRef is valid for Bitbucket Server's usage.
+func validateUserRef(ref gitprovider.UserRef, expectedDomain string) error {
+	// Make sure the UserRef fields are valid
+	if err := validation.ValidateTargets("UserRef", ref); err != nil {
+		return err
+	}
+	// Make sure the type is valid, and domain is expected
+	return validateIdentityFields(ref, expectedDomain)
+}
+
+// validateIdentityFields makes sure the type of the IdentityRef is supported, and the domain is as expected.
+func validateIdentityFields(ref gitprovider.IdentityRef, expectedDomain string) error {
+	// Make sure the expected domain is used
+	if ref.GetDomain() != expectedDomain {
+		return fmt.Errorf("domain %q not supported by this client: %w", ref.GetDomain(), gitprovider.ErrDomainUnsupported)
+	}
+	// Make sure the right type of identityref is used
+	switch ref.GetType() {
+	case gitprovider.IdentityTypeOrganization, gitprovider.IdentityTypeUser:
+		return nil
+	case gitprovider.IdentityTypeSuborganization:
+		return fmt.Errorf("bitbucket server doesn't support sub-organizations: %w", gitprovider.ErrNoProviderSupport)
+	}
+	return fmt.Errorf("invalid identity type: %v: %w", ref.GetType(), gitprovider.ErrInvalidArgument)
+}
+
+// projectKey returns the key of the project backing the given identity. Users have
+// personal projects, with keys of the form "~username".
+func projectKey(ref gitprovider.IdentityRef) string {
+	if ref.GetType() == gitprovider.IdentityTypeUser {
+		return personalProjectKeyPrefix + ref.GetIdentity()
+	}
+	return ref.GetIdentity()
+}
+
+// handleHTTPError checks the type of err, and returns typed variants of it
+// However, it _always_ keeps the original error too, and just wraps it in a MultiError
+// The consumer must use errors.Is and errors.As to check for equality and get data out of it.
+func handleHTTPError(err error) error {
+	// Short-circuit quickly if possible, allow always piping through this function
+	if err == nil {
+		return nil
+	}
+	stashErrorResponse := &ErrorResponse{}
+	if errors.As(err, &stashErrorResponse) {
+		httpErr := gitprovider.HTTPError{
+			Response:         stashErrorResponse.Response,
+			ErrorMessage:     stashErrorResponse.Error(),
+			Message:          stashErrorResponse.Message(),
+			DocumentationURL: apiDocURL,
+		}
+		switch stashErrorResponse.Response.StatusCode {
+		// Check for invalid credentials, and return a typed error in that case
+		case http.StatusForbidden, http.StatusUnauthorized:
+			return validation.NewMultiError(err,
+				&gitprovider.InvalidCredentialsError{HTTPError: httpErr},
+			)
+		// Check for 404 Not Found
+		case http.StatusNotFound:
+			return validation.NewMultiError(err, gitprovider.ErrNotFound)
+		// Rate limiting is optional, and only returns the status code and Retry-After
+		case http.StatusTooManyRequests:
+			return validation.NewMultiError(err, &gitprovider.RateLimitError{HTTPError: httpErr})
+		}
+		// Check for already exists errors
+		if isAlreadyExistsError(stashErrorResponse) {
+			return validation.NewMultiError(err, gitprovider.ErrAlreadyExists)
+		}
+		// Otherwise, return a generic *HTTPError
+		return validation.NewMultiError(err, &httpErr)
+	}
+	// Do nothing, just pipe through the unknown err
+	return err
+}
+
+// isAlreadyExistsError returns true if any of the errors in the response denotes a conflict
+// with an existing resource.
+func isAlreadyExistsError(e *ErrorResponse) bool {
+	for _, detail := range e.Errors {
+		msg := strings.ToLower(detail.Message)
+		if strings.Contains(msg, alreadyExistsMagicString) || strings.Contains(msg, alreadyTakenMagicString) {
+			return true
+		}
+		// e.g. "com.atlassian.bitbucket.repository.DuplicateRepositoryNameException"
+		exceptionName := detail.ExceptionName[strings.LastIndex(detail.ExceptionName, ".")+1:]
+		if strings.HasPrefix(exceptionName, duplicateExceptionPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateAPIObject creates a Validatior with the specified name, gives it to fn, and
+// depending on if any error was registered with it; either returns nil, or a MultiError
+// with both the validation error and ErrInvalidServerData, to mark that the server data
+// was invalid.
+func validateAPIObject(name string, fn func(validation.Validator)) error {
+	v := validation.New(name)
+	fn(v)
+	// If there was a validation error, also mark it specifically as invalid server data
+	if err := v.Error(); err != nil {
+		return validation.NewMultiError(err, gitprovider.ErrInvalidServerData)
+	}
+	return nil
+}
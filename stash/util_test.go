@@ -0,0 +1,168 @@
+/*
+Copyright 2021 The Flux CD contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stash
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/fluxcd/go-git-providers/gitprovider"
+	"github.com/fluxcd/go-git-providers/validation"
+)
+
+func Test_validateAPIObject(t *testing.T) {
+	tests := []struct {
+		name         string
+		structName   string
+		fn           func(validation.Validator)
+		expectedErrs []error
+	}{
+		{
+			name:       "no error => nil",
+			structName: "Foo",
+			fn:         func(validation.Validator) {},
+		},
+		{
+			name:       "one error => MultiError & InvalidServerData",
+			structName: "Foo",
+			fn: func(v validation.Validator) {
+				v.Required("FieldBar")
+			},
+			expectedErrs: []error{gitprovider.ErrInvalidServerData, &validation.MultiError{}, validation.ErrFieldRequired},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateAPIObject(tt.structName, tt.fn)
+			validation.TestExpectErrors(t, "validateAPIObject", err, tt.expectedErrs...)
+		})
+	}
+}
+
+func newStashError(statusCode int, message, exceptionName string) *ErrorResponse {
+	return &ErrorResponse{
+		Response: &http.Response{
+			Request: &http.Request{
+				Method: "GET",
+				URL:    &url.URL{},
+			},
+			StatusCode: statusCode,
+		},
+		Errors: []ErrorDetail{{Message: message, ExceptionName: exceptionName}},
+	}
+}
+
+func Test_handleHTTPError(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		expectedErrs []error
+	}{
+		{
+			name: "nil => nil",
+		},
+		{
+			name:         "unknown error => passthrough",
+			err:          gitprovider.ErrUnexpectedEvent,
+			expectedErrs: []error{gitprovider.ErrUnexpectedEvent},
+		},
+		{
+			name:         "401 => InvalidCredentialsError",
+			err:          newStashError(http.StatusUnauthorized, "Authentication failed. Please check your credentials and try again.", ""),
+			expectedErrs: []error{&validation.MultiError{}, &gitprovider.InvalidCredentialsError{}, &ErrorResponse{}},
+		},
+		{
+			name:         "403 => InvalidCredentialsError",
+			err:          newStashError(http.StatusForbidden, "", ""),
+			expectedErrs: []error{&validation.MultiError{}, &gitprovider.InvalidCredentialsError{}, &ErrorResponse{}},
+		},
+		{
+			name:         "404 => ErrNotFound",
+			err:          newStashError(http.StatusNotFound, "Repository foo/bar does not exist.", "com.atlassian.bitbucket.repository.NoSuchRepositoryException"),
+			expectedErrs: []error{&validation.MultiError{}, gitprovider.ErrNotFound, &ErrorResponse{}},
+		},
+		{
+			name:         "429 => RateLimitError",
+			err:          newStashError(http.StatusTooManyRequests, "", ""),
+			expectedErrs: []error{&validation.MultiError{}, &gitprovider.RateLimitError{}, &ErrorResponse{}},
+		},
+		{
+			name:         "409 with already taken message => ErrAlreadyExists",
+			err:          newStashError(http.StatusConflict, "This repository URL is already taken.", ""),
+			expectedErrs: []error{&validation.MultiError{}, gitprovider.ErrAlreadyExists, &ErrorResponse{}},
+		},
+		{
+			name:         "409 with duplicate exception => ErrAlreadyExists",
+			err:          newStashError(http.StatusConflict, "", "com.atlassian.bitbucket.ssh.DuplicateAccessKeyException"),
+			expectedErrs: []error{&validation.MultiError{}, gitprovider.ErrAlreadyExists, &ErrorResponse{}},
+		},
+		{
+			name:         "409 otherwise => HTTPError",
+			err:          newStashError(http.StatusConflict, "The file has been modified since it was last read.", ""),
+			expectedErrs: []error{&validation.MultiError{}, &gitprovider.HTTPError{}, &ErrorResponse{}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := handleHTTPError(tt.err)
+			validation.TestExpectErrors(t, "handleHTTPError", err, tt.expectedErrs...)
+			if tt.err == nil {
+				return
+			}
+			// The original error must always be kept
+			if !errors.Is(err, tt.err) {
+				t.Errorf("handleHTTPError() = %v, expected to wrap %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func Test_projectKey(t *testing.T) {
+	tests := []struct {
+		name string
+		ref  gitprovider.IdentityRef
+		want string
+	}{
+		{
+			name: "organization",
+			ref:  gitprovider.OrganizationRef{Domain: "example.com", Organization: "PRJ"},
+			want: "PRJ",
+		},
+		{
+			name: "user",
+			ref:  gitprovider.UserRef{Domain: "example.com", UserLogin: "alice"},
+			want: "~alice",
+		},
+		{
+			name: "user repository",
+			ref: gitprovider.UserRepositoryRef{
+				UserRef:        gitprovider.UserRef{Domain: "example.com", UserLogin: "alice"},
+				RepositoryName: "repo",
+			},
+			want: "~alice",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := projectKey(tt.ref); got != tt.want {
+				t.Errorf("projectKey() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}